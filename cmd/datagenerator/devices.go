@@ -26,8 +26,8 @@ func generateDevices(count int, app model.Application, datastore *storage.Storag
 		d.DevAddr = randomDevAddr()
 		d.AppSKey = randomAesKey()
 		d.NwkSKey = randomAesKey()
-		d.FCntDn = uint16(rand.Intn(4096))
-		d.FCntDn = uint16(rand.Intn(4096))
+		d.FCntDn = uint32(rand.Intn(4096))
+		d.FCntDn = uint32(rand.Intn(4096))
 		d.DeviceEUI, _ = keyGen.NewDeviceEUI()
 		d.RelaxedCounter = false
 		if err := datastore.CreateDevice(d, app.AppEUI); err != nil {
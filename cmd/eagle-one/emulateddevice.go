@@ -20,8 +20,8 @@ import (
 type EmulatedDevice struct {
 	keys              *DeviceKeys
 	sentMessageCount  int    // Number of messages currently sent
-	FrameCounterUp    uint16 // Current frame counter
-	FrameCounterDown  uint16
+	FrameCounterUp    uint32 // Current frame counter
+	FrameCounterDown  uint32
 	duplicateMessages *Randomizer
 	publisher         *server.EventRouter[protocol.DevAddr, GWMessage]
 	Config            EagleConfig
@@ -219,7 +219,7 @@ func (d *EmulatedDevice) makeNonce() uint16 {
 
 // Since the server side only implements the server side AES encryption it consistently decrypts
 // the devices use Encrypt for both encryption and decryption. Just to confuse everyone a bit more.
-func decryptPayload(devAddr protocol.DevAddr, fCntDown uint16, key protocol.AESKey, buf []byte) []byte {
+func decryptPayload(devAddr protocol.DevAddr, fCntDown uint32, key protocol.AESKey, buf []byte) []byte {
 	k := int(math.Ceil(float64(len(buf)) / 16))
 
 	var S []byte
@@ -229,7 +229,7 @@ func decryptPayload(devAddr protocol.DevAddr, fCntDown uint16, key protocol.AESK
 
 		A[5] = 1 // Always downlink
 		binary.LittleEndian.PutUint32(A[6:], devAddr.ToUint32())
-		binary.LittleEndian.PutUint32(A[10:], uint32(fCntDown))
+		binary.LittleEndian.PutUint32(A[10:], fCntDown)
 
 		A[15] = byte(i + 1)
 
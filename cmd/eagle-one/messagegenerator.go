@@ -94,7 +94,7 @@ func (m *MessageGenerator) buildPayload() []byte {
 
 // Generate creates a new message, encodes it and returns a base64-encoded string. The second
 // returned value indicates a valid message or not.
-func (m *MessageGenerator) Generate(keys *DeviceKeys, fCnt uint16) (string, protocol.MType) {
+func (m *MessageGenerator) Generate(keys *DeviceKeys, fCnt uint32) (string, protocol.MType) {
 	mt := m.randomMessageType()
 
 	if m.CorruptPayloadError.Now() {
@@ -11,6 +11,7 @@ type appCmd struct {
 	Add  addAppCmd    `kong:"cmd,help='Add application',aliases='create,new,n'"`
 	Del  deleteAppCmd `kong:"cmd,help='Remove application',aliases='rm,delete,d'"`
 	Get  getAppCmd    `kong:"cmd,help='Retrieve application',aliases='show,retrieve,g'"`
+	Up   updateAppCmd `kong:"cmd,help='Update application',aliases='update,u'"`
 }
 
 func (c *appCmd) Run(args *params) error {
@@ -42,6 +43,8 @@ func (c *listAppCmd) Run(args *params) error {
 }
 
 type addAppCmd struct {
+	Decoder string `kong:"help='Payload decoder (CEL expression)'"`
+	Encoder string `kong:"help='Payload encoder (CEL expression)'"`
 }
 
 func (*addAppCmd) Run(args *params) error {
@@ -51,7 +54,14 @@ func (*addAppCmd) Run(args *params) error {
 	}
 	defer done()
 
-	res, err := client.CreateApplication(ctx, &lospan.CreateApplicationRequest{})
+	req := &lospan.CreateApplicationRequest{}
+	if args.App.Add.Decoder != "" {
+		req.Decoder = newPtr(args.App.Add.Decoder)
+	}
+	if args.App.Add.Encoder != "" {
+		req.Encoder = newPtr(args.App.Add.Encoder)
+	}
+	res, err := client.CreateApplication(ctx, req)
 	if err != nil {
 		return err
 	}
@@ -94,6 +104,57 @@ func (*getAppCmd) Run(args *params) error {
 	if err != nil {
 		return err
 	}
+	printApp(app)
+	return nil
+}
+
+func printApp(app *lospan.Application) {
 	fmt.Printf("Application EUI %s\n", app.Eui)
+	fmt.Printf("    Tag:     %s\n", app.GetTag())
+	fmt.Printf("    Decoder: %s\n", app.GetDecoder())
+	fmt.Printf("    Encoder: %s\n", app.GetEncoder())
+}
+
+type updateAppCmd struct {
+	EUI     string `kong:"help='Application EUI to update',required"`
+	Tag     string `kong:"help='Tag for application',optional"`
+	Decoder string `kong:"help='Payload decoder (CEL expression). Use - to remove the decoder',optional"`
+	Encoder string `kong:"help='Payload encoder (CEL expression). Use - to remove the encoder',optional"`
+}
+
+// codecExpression returns the expression for the update request. "-"
+// clears the expression.
+func codecExpression(expr string) *string {
+	switch expr {
+	case "":
+		return nil
+	case "-":
+		return newPtr("")
+	default:
+		return newPtr(expr)
+	}
+}
+
+func (*updateAppCmd) Run(args *params) error {
+	client, ctx, done, err := createClient(args.Address)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	req := &lospan.Application{
+		Eui:     args.App.Up.EUI,
+		Decoder: codecExpression(args.App.Up.Decoder),
+		Encoder: codecExpression(args.App.Up.Encoder),
+	}
+	if args.App.Up.Tag != "" {
+		req.Tag = newPtr(args.App.Up.Tag)
+	}
+	app, err := client.UpdateApplication(ctx, req)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Updated application")
+	printApp(app)
 	return nil
 }
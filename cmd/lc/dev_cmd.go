@@ -7,6 +7,7 @@ import (
 	"os"
 	"strconv"
 	"text/tabwriter"
+	"time"
 
 	"github.com/lab5e/lospan/pkg/pb/lospan"
 )
@@ -17,32 +18,58 @@ type devCmd struct {
 	Get    getDevCmd    `kong:"cmd,help='Get device',aliases='show,g,i'"`
 	Del    delDevCmd    `kong:"cmd,help='Delete device',aliases='rm,delete,r,d'"`
 	List   listDevCmd   `kong:"cmd,help='List devices',aliases='ls,l'"`
+	Stats  statsDevCmd  `kong:"cmd,help='Show frame statistics for device',aliases='st'"`
 }
 
 // This is common for both the add and update parameters; reuse
 type deviceParameters struct {
 	Type              string `kong:"help='Device type',enum='none,otaa,abp,disabled',default='none'"`
+	Class             string `kong:"help='Device class',enum='none,a,b,c',default='none'"`
+	MACVersion        string `kong:"help='LoRaWAN MAC version',enum='none,1.0,1.1',default='none'"`
 	DevAddr           string `kong:"help='Device address (4 byte, for ABP devices in hexadecimal)'"`
 	AppSessionKey     string `kong:"help='Application session key (for ABP, 16 bytes hexadecimal)'"`
 	NetworkSessionKey string `kong:"help='Network session key (for ABP, 16 bytes hexadecimal)'"`
 	AppKey            string `kong:"help='Application key (for OTAA, 16 bytes hexadecimal)'"`
+	NwkKey            string `kong:"help='Network key (for LoRaWAN 1.1 OTAA, 16 bytes hexadecimal)'"`
 	FrameCountUp      int    `kong:"help='Frame counter up',default=-1"`
 	FrameCountDown    int    `kong:"help='Frame counter up',default=-1"`
 	RelaxedCounter    bool   `kong:"help='Relaxed counter',default=false"`
+	Profile           int64  `kong:"help='Device profile ID (0 removes the profile)',default=-1"`
 }
 
 func printDevice(d *lospan.Device) {
 	fmt.Printf("   EUI:              %s\n", d.GetEui())
 	fmt.Printf("   AppEUI:           %s\n", d.GetApplicationEui())
 	fmt.Printf("   State:            %s\n", d.GetState().String())
+	fmt.Printf("   Class:            %s\n", d.GetDeviceClass().String())
+	fmt.Printf("   MAC version:      %s\n", d.GetMacVersion().String())
+	if d.GetProfileId() != 0 {
+		fmt.Printf("   Profile:          %d\n", d.GetProfileId())
+	}
 	fmt.Printf("   DevAddr:          %08x\n", d.GetDevAddr())
 	fmt.Printf("   AppKey:           %s\n", hex.EncodeToString(d.AppKey))
+	if d.GetMacVersion() == lospan.MACVersion_LORAWAN_1_1 {
+		fmt.Printf("   NwkKey:           %s\n", hex.EncodeToString(d.NetworkKey))
+	}
 	fmt.Printf("   AppSKey:          %s\n", hex.EncodeToString(d.AppSessionKey))
 	fmt.Printf("   NwkSKey:          %s\n", hex.EncodeToString(d.NetworkSessionKey))
 	fmt.Printf("   Frame count up:   %d\n", d.GetFrameCountUp())
 	fmt.Printf("   Frame count down: %d\n", d.GetFrameCountDown())
 	fmt.Printf("   Relaxed counter:  %t\n", d.GetRelaxedCounter())
 	fmt.Printf("   Key warning:      %t\n", d.GetKeyWarning())
+	fmt.Printf("   Data rate:        DR%d\n", d.GetDataRate())
+	fmt.Printf("   TX power:         %d\n", d.GetTxPower())
+	fmt.Printf("   Channel mask:     %04x\n", d.GetChannelMask())
+	fmt.Printf("   RX1 DR offset:    %d\n", d.GetRx1DrOffset())
+	fmt.Printf("   RX1 delay:        %d s\n", d.GetRx1Delay())
+	fmt.Printf("   RX2 data rate:    DR%d\n", d.GetRx2DataRate())
+	fmt.Printf("   RX2 frequency:    %.3f MHz\n", d.GetRx2Frequency())
+	fmt.Printf("   Max duty cycle:   %d\n", d.GetMaxDutyCycle())
+	if d.GetDeviceClass() == lospan.DeviceClass_CLASS_B {
+		fmt.Printf("   Ping periodicity: %d\n", d.GetPingPeriodicity())
+		fmt.Printf("   Ping data rate:   DR%d\n", d.GetPingDataRate())
+		fmt.Printf("   Ping frequency:   %.3f MHz\n", d.GetPingFrequency())
+	}
 	fmt.Printf("   Nonce history:\n")
 	for i := range d.DevNonces {
 		fmt.Printf("        %d: %02x\n", i, d.DevNonces[i])
@@ -67,6 +94,20 @@ func updateDeviceWithParameters(d *lospan.Device, p deviceParameters) error {
 	if p.Type == "disabled" {
 		d.State = newPtr(lospan.DeviceState_DISABLED)
 	}
+	switch p.Class {
+	case "a":
+		d.DeviceClass = newPtr(lospan.DeviceClass_CLASS_A)
+	case "b":
+		d.DeviceClass = newPtr(lospan.DeviceClass_CLASS_B)
+	case "c":
+		d.DeviceClass = newPtr(lospan.DeviceClass_CLASS_C)
+	}
+	switch p.MACVersion {
+	case "1.0":
+		d.MacVersion = newPtr(lospan.MACVersion_LORAWAN_1_0)
+	case "1.1":
+		d.MacVersion = newPtr(lospan.MACVersion_LORAWAN_1_1)
+	}
 	if p.AppKey != "" {
 		buf, err := hex.DecodeString(p.AppKey)
 		if err != nil || len(buf) != 16 {
@@ -74,6 +115,13 @@ func updateDeviceWithParameters(d *lospan.Device, p deviceParameters) error {
 		}
 		d.AppKey = buf[:]
 	}
+	if p.NwkKey != "" {
+		buf, err := hex.DecodeString(p.NwkKey)
+		if err != nil || len(buf) != 16 {
+			return fmt.Errorf("invalid NwkKey")
+		}
+		d.NetworkKey = buf[:]
+	}
 	if p.AppSessionKey != "" {
 		buf, err := hex.DecodeString(p.AppKey)
 		if err != nil || len(buf) != 16 {
@@ -89,10 +137,13 @@ func updateDeviceWithParameters(d *lospan.Device, p deviceParameters) error {
 		d.NetworkSessionKey = buf[:]
 	}
 	if p.FrameCountDown > -1 {
-		d.FrameCountDown = newPtr(int32(p.FrameCountDown))
+		d.FrameCountDown = newPtr(uint32(p.FrameCountDown))
 	}
 	if p.FrameCountUp > -1 {
-		d.FrameCountUp = newPtr(int32(p.FrameCountUp))
+		d.FrameCountUp = newPtr(uint32(p.FrameCountUp))
+	}
+	if p.Profile > -1 {
+		d.ProfileId = newPtr(p.Profile)
 	}
 	d.RelaxedCounter = newPtr(p.RelaxedCounter)
 	return nil
@@ -242,3 +293,33 @@ func (*listDevCmd) Run(args *params) error {
 	table.Flush()
 	return nil
 }
+
+type statsDevCmd struct {
+	EUI string `kong:"help='Device EUI',required"`
+}
+
+func (*statsDevCmd) Run(args *params) error {
+	p := args.Dev.Stats
+	client, ctx, done, err := createClient(args.Address)
+	if err != nil {
+		return err
+	}
+	defer done()
+	stats, err := client.GetDeviceStats(ctx, &lospan.GetDeviceStatsRequest{
+		Eui: p.EUI,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("   EUI:              %s\n", stats.GetEui())
+	fmt.Printf("   Received:         %d\n", stats.GetReceived())
+	fmt.Printf("   Lost:             %d\n", stats.GetLost())
+	fmt.Printf("   Replayed:         %d\n", stats.GetReplayed())
+	fmt.Printf("   MIC failed:       %d\n", stats.GetMicFailed())
+	fmt.Printf("   Rejected:         %d\n", stats.GetRejected())
+	fmt.Printf("   Frame loss:       %.1f%%\n", stats.GetFrameLoss()*100)
+	if stats.GetLastUpdated() > 0 {
+		fmt.Printf("   Last updated:     %s\n", time.Unix(0, stats.GetLastUpdated()).Format(time.RFC3339))
+	}
+	return nil
+}
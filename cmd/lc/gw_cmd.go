@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
 	"text/tabwriter"
+	"time"
 
 	"github.com/lab5e/lospan/pkg/pb/lospan"
 )
@@ -15,6 +19,159 @@ func printGateway(gw *lospan.Gateway) {
 	fmt.Printf("    Latitude:        %2.2f\n", gw.GetLatitude())
 	fmt.Printf("    Longitude:       %3.2f\n", gw.GetLongitude())
 	fmt.Printf("    Altitude:        %2.2f\n", gw.GetAltitude())
+	fmt.Printf("    Online:          %t\n", gw.GetOnline())
+	fmt.Printf("    Last seen:       %s\n", lastSeen(gw))
+	fmt.Printf("    Frequency plan:  %s\n", gw.GetFrequencyPlan())
+	printChannels(gw.GetChannels())
+	printDutyCycle(gw)
+	if stats := gw.GetStats(); stats != nil {
+		fmt.Printf("    Stats updated:   %s\n", time.UnixMilli(stats.Updated).Format(time.RFC3339))
+		fmt.Printf("    Gateway time:    %s\n", stats.Time)
+		fmt.Printf("    GPS position:    %2.4f, %3.4f, %2.1f\n", stats.Latitude, stats.Longitude, stats.Altitude)
+		fmt.Printf("    RX received:     %d (%d OK, %d forwarded)\n", stats.RxReceived, stats.RxOk, stats.RxForwarded)
+		fmt.Printf("    ACK ratio:       %3.1f%%\n", stats.AckRatio)
+		fmt.Printf("    Downlinks:       %d received, %d emitted\n", stats.DownReceived, stats.TxEmitted)
+	}
+}
+
+// printDutyCycle prints the downlink airtime left in each of the sub-bands
+// and the dwell time limit for the gateway
+func printDutyCycle(gw *lospan.Gateway) {
+	for _, s := range gw.GetDutyCycle() {
+		fmt.Printf("    Duty cycle:      %.3f-%.3f MHz %.1f%% (%v used, %v left)\n",
+			s.MinFrequency, s.MaxFrequency, s.DutyCycle*100,
+			time.Duration(s.Used)*time.Millisecond, time.Duration(s.Remaining)*time.Millisecond)
+	}
+	if gw.MaxDwellTime != nil {
+		fmt.Printf("    Max dwell time:  %v\n", time.Duration(gw.GetMaxDwellTime())*time.Millisecond)
+	}
+}
+
+// printChannels prints the concentrator channels for the gateway
+func printChannels(c *lospan.ConcentratorConfig) {
+	if c == nil || len(c.Radios) == 0 {
+		fmt.Printf("    Channels:        default for frequency plan\n")
+		return
+	}
+	frequency := func(ch *lospan.ConcentratorChannel) float64 {
+		if int(ch.Radio) >= len(c.Radios) {
+			return 0
+		}
+		return float64(c.Radios[ch.Radio]) + float64(ch.IfOffset)/1e6
+	}
+	for i, f := range c.Radios {
+		fmt.Printf("    Radio %d:         %.4f MHz\n", i, f)
+	}
+	for i, ch := range c.MultiSf {
+		if ch.Enabled {
+			fmt.Printf("    Channel %d:       %.4f MHz (radio %d)\n", i, frequency(ch), ch.Radio)
+		}
+	}
+	if ch := c.GetLoraStd(); ch.GetEnabled() {
+		fmt.Printf("    LoRa std:        %.4f MHz SF%dBW%d (radio %d)\n", frequency(ch), ch.SpreadFactor, ch.Bandwidth/1000, ch.Radio)
+	}
+	if ch := c.GetFsk(); ch.GetEnabled() {
+		fmt.Printf("    FSK:             %.4f MHz %d bps (radio %d)\n", frequency(ch), ch.DataRate, ch.Radio)
+	}
+}
+
+// forwarderChannel is a radio or channel in the packet forwarder configuration
+type forwarderChannel struct {
+	Enable       bool   `json:"enable"`
+	Freq         uint32 `json:"freq"`
+	Radio        uint32 `json:"radio"`
+	IF           int32  `json:"if"`
+	Bandwidth    uint32 `json:"bandwidth"`
+	SpreadFactor uint32 `json:"spread_factor"`
+	DataRate     uint32 `json:"datarate"`
+}
+
+// readChannelConfig reads the concentrator channels from a packet forwarder
+// configuration file (global_conf.json). The file can also contain just the
+// SX1301_conf object.
+func readChannelConfig(filename string) (*lospan.ConcentratorConfig, error) {
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	conf := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(buf, &conf); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %v", filename, err)
+	}
+	if sx1301, ok := conf["SX1301_conf"]; ok {
+		conf = make(map[string]json.RawMessage)
+		if err := json.Unmarshal(sx1301, &conf); err != nil {
+			return nil, fmt.Errorf("unable to read SX1301_conf in %s: %v", filename, err)
+		}
+	}
+	channel := func(name string) (*forwarderChannel, error) {
+		raw, ok := conf[name]
+		if !ok {
+			return nil, nil
+		}
+		ch := &forwarderChannel{}
+		if err := json.Unmarshal(raw, ch); err != nil {
+			return nil, fmt.Errorf("unable to read %s in %s: %v", name, filename, err)
+		}
+		return ch, nil
+	}
+	toChannel := func(ch *forwarderChannel) *lospan.ConcentratorChannel {
+		if ch == nil {
+			return &lospan.ConcentratorChannel{}
+		}
+		return &lospan.ConcentratorChannel{
+			Enabled:      ch.Enable,
+			Radio:        ch.Radio,
+			IfOffset:     ch.IF,
+			Bandwidth:    ch.Bandwidth,
+			SpreadFactor: ch.SpreadFactor,
+			DataRate:     ch.DataRate,
+		}
+	}
+
+	ret := &lospan.ConcentratorConfig{}
+	for i := 0; ; i++ {
+		radio, err := channel(fmt.Sprintf("radio_%d", i))
+		if err != nil {
+			return nil, err
+		}
+		if radio == nil {
+			break
+		}
+		ret.Radios = append(ret.Radios, float32(float64(radio.Freq)/1e6))
+	}
+	if len(ret.Radios) == 0 {
+		return nil, fmt.Errorf("no radios in %s", filename)
+	}
+	for i := 0; i < 8; i++ {
+		ch, err := channel(fmt.Sprintf("chan_multiSF_%d", i))
+		if err != nil {
+			return nil, err
+		}
+		if ch == nil {
+			break
+		}
+		ret.MultiSf = append(ret.MultiSf, toChannel(ch))
+	}
+	loraStd, err := channel("chan_Lora_std")
+	if err != nil {
+		return nil, err
+	}
+	fsk, err := channel("chan_FSK")
+	if err != nil {
+		return nil, err
+	}
+	ret.LoraStd = toChannel(loraStd)
+	ret.Fsk = toChannel(fsk)
+	return ret, nil
+}
+
+// lastSeen formats the last seen time for the gateway
+func lastSeen(gw *lospan.Gateway) string {
+	if gw.LastSeen == nil {
+		return "never"
+	}
+	return time.UnixMilli(gw.GetLastSeen()).Format(time.RFC3339)
 }
 
 type gwCmds struct {
@@ -23,6 +180,7 @@ type gwCmds struct {
 	Update gwUpdateCmd `kong:"cmd,help='Update gateway',aliases='up'"`
 	Get    gwGetCmd    `kong:"cmd,help='Get gateway info',aliases='g'"`
 	List   gwListCmd   `kong:"cmd,help='List gateways',aliases='ls'"`
+	Stream gwStreamCmd `kong:"cmd,help='Stream gateway events',aliases='tail'"`
 }
 
 type gwAddCmd struct {
@@ -32,6 +190,8 @@ type gwAddCmd struct {
 	Longitude float32 `kong:"help='Longitude for gateway (-360...360)'"`
 	Latitude  float32 `kong:"help='Latitude for gateway (-90...90)'"`
 	StrictIP  bool    `kong:"help='Strict IP check',default=true"`
+	Plan      string  `kong:"help='Frequency plan (EU868, US915, AS923-1...)',default='EU868'"`
+	Channels  string  `kong:"help='Packet forwarder configuration file (global_conf.json) with the concentrator channels',type='existingfile'"`
 }
 
 func (*gwAddCmd) Run(args *params) error {
@@ -41,14 +201,21 @@ func (*gwAddCmd) Run(args *params) error {
 	}
 	defer done()
 
-	gw, err := client.CreateGateway(ctx, &lospan.Gateway{
-		Eui:       args.GW.Add.EUI,
-		Ip:        newPtr(args.GW.Add.IP),
-		StrictIp:  newPtr(args.GW.Add.StrictIP),
-		Altitude:  newPtr(args.GW.Add.Altitude),
-		Longitude: newPtr(args.GW.Add.Longitude),
-		Latitude:  newPtr(args.GW.Add.Latitude),
-	})
+	newGW := &lospan.Gateway{
+		Eui:           args.GW.Add.EUI,
+		Ip:            newPtr(args.GW.Add.IP),
+		StrictIp:      newPtr(args.GW.Add.StrictIP),
+		Altitude:      newPtr(args.GW.Add.Altitude),
+		Longitude:     newPtr(args.GW.Add.Longitude),
+		Latitude:      newPtr(args.GW.Add.Latitude),
+		FrequencyPlan: newPtr(args.GW.Add.Plan),
+	}
+	if args.GW.Add.Channels != "" {
+		if newGW.Channels, err = readChannelConfig(args.GW.Add.Channels); err != nil {
+			return err
+		}
+	}
+	gw, err := client.CreateGateway(ctx, newGW)
 	if err != nil {
 		return err
 	}
@@ -64,6 +231,9 @@ type gwUpdateCmd struct {
 	Longitude float32 `kong:"help='Longitude for gateway (-360...360)',default=-999"`
 	Latitude  float32 `kong:"help='Latitude for gateway (-90...90)',default=-999"`
 	StrictIP  bool    `kong:"help='Strict IP check',default=true,optional"`
+	Plan      string  `kong:"help='Frequency plan (EU868, US915, AS923-1...)',optional"`
+	Channels  string  `kong:"help='Packet forwarder configuration file (global_conf.json) with the concentrator channels',type='existingfile',optional"`
+	Default   bool    `kong:"help='Use the default concentrator channels for the frequency plan',default=false"`
 }
 
 func (*gwUpdateCmd) Run(args *params) error {
@@ -89,6 +259,17 @@ func (*gwUpdateCmd) Run(args *params) error {
 		gw.Latitude = newPtr(args.GW.Update.Latitude)
 	}
 	gw.StrictIp = newPtr(args.GW.Update.StrictIP)
+	if args.GW.Update.Plan != "" {
+		gw.FrequencyPlan = newPtr(args.GW.Update.Plan)
+	}
+	if args.GW.Update.Default {
+		gw.Channels = &lospan.ConcentratorConfig{}
+	}
+	if args.GW.Update.Channels != "" {
+		if gw.Channels, err = readChannelConfig(args.GW.Update.Channels); err != nil {
+			return err
+		}
+	}
 
 	newGW, err := client.UpdateGateway(ctx, gw)
 	if err != nil {
@@ -159,16 +340,59 @@ func (*gwListCmd) Run(args *params) error {
 	}
 
 	writer := tabwriter.NewWriter(os.Stdout, 3, 4, 2, ' ', 0)
-	writer.Write([]byte("EUI\tIP\tStrict\tLat\tLon\tAlt\n"))
+	writer.Write([]byte("EUI\tPlan\tIP\tStrict\tLat\tLon\tAlt\tOnline\tLast seen\n"))
 	for _, gw := range gws.Gateways {
-		writer.Write([]byte(fmt.Sprintf("%s\t%s\t%t\t%3.2f\t%3.2f\t%3.2f\n",
+		writer.Write([]byte(fmt.Sprintf("%s\t%s\t%s\t%t\t%3.2f\t%3.2f\t%3.2f\t%t\t%s\n",
 			gw.Eui,
+			gw.GetFrequencyPlan(),
 			gw.GetIp(),
 			gw.GetStrictIp(),
 			gw.GetLatitude(),
 			gw.GetLongitude(),
-			gw.GetAltitude())))
+			gw.GetAltitude(),
+			gw.GetOnline(),
+			lastSeen(gw))))
 	}
 	writer.Flush()
 	return nil
 }
+
+type gwStreamCmd struct {
+	EUI  string `kong:"help='Gateway EUI',required"`
+	JSON bool   `kong:"help='Show raw JSON from the gateway',default=false"`
+}
+
+func (*gwStreamCmd) Run(args *params) error {
+	client, _, done, err := createClient(args.Address)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	// The stream runs until it is interrupted so the default timeout isn't used
+	stream, err := client.StreamGateway(context.Background(), &lospan.StreamGatewayRequest{
+		Eui: args.GW.Stream.EUI,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		ts := time.UnixMilli(msg.Timestamp).Format("15:04:05.000")
+		fmt.Printf("%s %-10s %s\n", ts, msg.Type.String(), msg.GatewayEui)
+		for _, rx := range msg.Rx {
+			fmt.Printf("    rx: %.3f MHz %s ch %d rssi %d snr %.1f tmst %d: %s\n",
+				rx.Frequency, rx.DataRate, rx.Channel, rx.Rssi, rx.Snr, rx.Timestamp, hex.EncodeToString(rx.Payload))
+		}
+		if tx := msg.Tx; tx != nil {
+			fmt.Printf("    tx: %.3f MHz %s power %d tmst %d gps %d: %s\n",
+				tx.Frequency, tx.DataRate, tx.Power, tx.Timestamp, tx.GpsTime, hex.EncodeToString(tx.Payload))
+		}
+		if args.GW.Stream.JSON && msg.Json != "" {
+			fmt.Printf("    %s\n", msg.Json)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"text/tabwriter"
 
 	"github.com/lab5e/lospan/pkg/pb/lospan"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 type inboxCmd struct {
@@ -28,10 +29,22 @@ func (*inboxCmd) Run(args *params) error {
 	}
 
 	table := tabwriter.NewWriter(os.Stdout, 8, 3, 2, ' ', 0)
-	table.Write([]byte("DevAddr\tGateway\tData rate\tRSSI\tSNR\tFrequency\tPayload\n"))
+	table.Write([]byte("DevAddr\tGateway\tData rate\tRSSI\tSNR\tFrequency\tPort\tPayload\n"))
 	for _, msg := range res.Messages {
-		table.Write([]byte(fmt.Sprintf("%08x\t%s\t%s\t%d\t%3.2f\t%3.2f\t%s\n",
-			msg.DevAddr, msg.GatewayEui, msg.DataRate, msg.Rssi, msg.Snr, msg.Frequency, ellipsisString(hex.EncodeToString(msg.Payload), 40))))
+		table.Write([]byte(fmt.Sprintf("%08x\t%s\t%s\t%d\t%3.2f\t%3.2f\t%d\t%s\n",
+			msg.DevAddr, msg.GatewayEui, msg.DataRate, msg.Rssi, msg.Snr, msg.Frequency, msg.Port, ellipsisString(hex.EncodeToString(msg.Payload), 40))))
+		if msg.DecodedPayload != nil {
+			buf, _ := protojson.Marshal(msg.DecodedPayload)
+			table.Write([]byte(fmt.Sprintf("\t  decoded: %s\n", buf)))
+		}
+		if msg.CodecError != nil {
+			table.Write([]byte(fmt.Sprintf("\t  codec error: %s\n", msg.GetCodecError())))
+		}
+		// List all of the gateways that received the message below it
+		for _, r := range msg.Receptions {
+			table.Write([]byte(fmt.Sprintf("\t  %s\tch %d/rf %d\t%d\t%3.2f\ttmst %d\t\n",
+				r.GatewayEui, r.Channel, r.RfChain, r.Rssi, r.Snr, r.Timestamp)))
+		}
 	}
 	table.Flush()
 	return nil
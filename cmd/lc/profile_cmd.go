@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/lab5e/lospan/pkg/pb/lospan"
+)
+
+type profileCmds struct {
+	Add    addProfileCmd    `kong:"cmd,help='Add device profile',aliases='create,a'"`
+	Update updateProfileCmd `kong:"cmd,help='Update device profile',aliases='up,u'"`
+	Get    getProfileCmd    `kong:"cmd,help='Get device profile',aliases='show,g,i'"`
+	Del    delProfileCmd    `kong:"cmd,help='Delete device profile',aliases='rm,delete,r,d'"`
+	List   listProfileCmd   `kong:"cmd,help='List device profiles',aliases='ls,l'"`
+}
+
+// This is common for both the add and update parameters; reuse
+type profileParameters struct {
+	Name         string  `kong:"help='Profile name'"`
+	MACVersion   string  `kong:"help='LoRaWAN MAC version',enum='none,1.0,1.1',default='none'"`
+	RegParams    string  `kong:"help='Regional parameters revision'"`
+	Class        string  `kong:"help='Device class',enum='none,a,b,c',default='none'"`
+	Join         string  `kong:"help='Devices can join via OTAA',enum='none,yes,no',default='none'"`
+	RX1Delay     int     `kong:"help='RX1 delay (seconds)',default=-1"`
+	RX1DROffset  int     `kong:"help='RX1 data rate offset',default=-1"`
+	RX2DataRate  int     `kong:"help='RX2 data rate',default=-1"`
+	RX2Frequency float32 `kong:"help='RX2 frequency (MHz, 0 = band default)',default=-1"`
+	MaxEIRP      int     `kong:"help='Max EIRP (dBm, 0 = band default)',default=-1"`
+	ADR          string  `kong:"help='Adaptive data rate',enum='none,on,off',default='none'"`
+}
+
+func updateProfileWithParameters(p *lospan.DeviceProfile, params profileParameters) {
+	if params.Name != "" {
+		p.Name = newPtr(params.Name)
+	}
+	switch params.MACVersion {
+	case "1.0":
+		p.MacVersion = newPtr(lospan.MACVersion_LORAWAN_1_0)
+	case "1.1":
+		p.MacVersion = newPtr(lospan.MACVersion_LORAWAN_1_1)
+	}
+	if params.RegParams != "" {
+		p.RegParamsRevision = newPtr(params.RegParams)
+	}
+	switch params.Class {
+	case "a":
+		p.DeviceClass = newPtr(lospan.DeviceClass_CLASS_A)
+	case "b":
+		p.DeviceClass = newPtr(lospan.DeviceClass_CLASS_B)
+	case "c":
+		p.DeviceClass = newPtr(lospan.DeviceClass_CLASS_C)
+	}
+	if params.Join != "none" {
+		p.SupportsJoin = newPtr(params.Join == "yes")
+	}
+	if params.RX1Delay > -1 {
+		p.Rx1Delay = newPtr(int32(params.RX1Delay))
+	}
+	if params.RX1DROffset > -1 {
+		p.Rx1DrOffset = newPtr(int32(params.RX1DROffset))
+	}
+	if params.RX2DataRate > -1 {
+		p.Rx2DataRate = newPtr(int32(params.RX2DataRate))
+	}
+	if params.RX2Frequency > -1 {
+		p.Rx2Frequency = newPtr(params.RX2Frequency)
+	}
+	if params.MaxEIRP > -1 {
+		p.MaxEirp = newPtr(int32(params.MaxEIRP))
+	}
+	if params.ADR != "none" {
+		p.AdrEnabled = newPtr(params.ADR == "on")
+	}
+}
+
+func printProfile(p *lospan.DeviceProfile) {
+	fmt.Printf("   ID:               %d\n", p.GetId())
+	fmt.Printf("   Name:             %s\n", p.GetName())
+	fmt.Printf("   MAC version:      %s\n", p.GetMacVersion().String())
+	fmt.Printf("   Reg. parameters:  %s\n", p.GetRegParamsRevision())
+	fmt.Printf("   Class:            %s\n", p.GetDeviceClass().String())
+	fmt.Printf("   Supports join:    %t\n", p.GetSupportsJoin())
+	fmt.Printf("   RX1 delay:        %d s\n", p.GetRx1Delay())
+	fmt.Printf("   RX1 DR offset:    %d\n", p.GetRx1DrOffset())
+	fmt.Printf("   RX2 data rate:    DR%d\n", p.GetRx2DataRate())
+	fmt.Printf("   RX2 frequency:    %.3f MHz\n", p.GetRx2Frequency())
+	fmt.Printf("   Max EIRP:         %d dBm\n", p.GetMaxEirp())
+	fmt.Printf("   ADR enabled:      %t\n", p.GetAdrEnabled())
+}
+
+type addProfileCmd struct {
+	profileParameters
+}
+
+func (*addProfileCmd) Run(args *params) error {
+	if args.Profile.Add.Name == "" {
+		return fmt.Errorf("profile must have a name")
+	}
+	client, ctx, done, err := createClient(args.Address)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	p := &lospan.DeviceProfile{}
+	updateProfileWithParameters(p, args.Profile.Add.profileParameters)
+	res, err := client.CreateDeviceProfile(ctx, p)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created device profile %d\n", res.GetId())
+	printProfile(res)
+	return nil
+}
+
+type updateProfileCmd struct {
+	ID int64 `kong:"help='Profile ID',required"`
+	profileParameters
+}
+
+func (*updateProfileCmd) Run(args *params) error {
+	client, ctx, done, err := createClient(args.Address)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	p := &lospan.DeviceProfile{Id: newPtr(args.Profile.Update.ID)}
+	updateProfileWithParameters(p, args.Profile.Update.profileParameters)
+	res, err := client.UpdateDeviceProfile(ctx, p)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Updated device profile %d\n", res.GetId())
+	printProfile(res)
+	return nil
+}
+
+type getProfileCmd struct {
+	ID int64 `kong:"help='Profile ID',required"`
+}
+
+func (*getProfileCmd) Run(args *params) error {
+	client, ctx, done, err := createClient(args.Address)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	res, err := client.GetDeviceProfile(ctx, &lospan.GetDeviceProfileRequest{Id: args.Profile.Get.ID})
+	if err != nil {
+		return err
+	}
+	printProfile(res)
+	return nil
+}
+
+type delProfileCmd struct {
+	ID int64 `kong:"help='Profile ID',required"`
+}
+
+func (*delProfileCmd) Run(args *params) error {
+	client, ctx, done, err := createClient(args.Address)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	res, err := client.DeleteDeviceProfile(ctx, &lospan.DeleteDeviceProfileRequest{Id: args.Profile.Del.ID})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed device profile %d (%s)\n", res.GetId(), res.GetName())
+	return nil
+}
+
+type listProfileCmd struct {
+}
+
+func (*listProfileCmd) Run(args *params) error {
+	client, ctx, done, err := createClient(args.Address)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	res, err := client.ListDeviceProfiles(ctx, &lospan.ListDeviceProfilesRequest{})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d device profiles found\n", len(res.Profiles))
+	fmt.Println()
+	tw := tabwriter.NewWriter(os.Stdout, 4, 4, 1, ' ', 0)
+	fmt.Fprintln(tw, "ID\tName\tMAC\tClass\tJoin\tRX1 delay\tADR")
+	for _, p := range res.Profiles {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%t\t%d\t%t\n", p.GetId(), p.GetName(), p.GetMacVersion().String(),
+			p.GetDeviceClass().String(), p.GetSupportsJoin(), p.GetRx1Delay(), p.GetAdrEnabled())
+	}
+	tw.Flush()
+	return nil
+}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 
 	"github.com/lab5e/lospan/pkg/pb/lospan"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 type sendCmd struct {
 	DeviceEUI string `kong:"help='Device EUI',required"`
-	Payload   string `kong:"help='Payload, base64 encoded',required"`
+	Payload   string `kong:"help='Payload, base64 encoded',xor='payload',required"`
+	JSON      string `kong:"help='JSON object encoded by the application payload encoder',xor='payload',required"`
 	Port      uint8  `kong:"help='Port for message',default=1"`
 	Ack       bool   `kong:"help='Request message ack',default=false"`
 }
@@ -23,15 +26,18 @@ func (*sendCmd) Run(args *params) error {
 	}
 	defer done()
 
-	buf, err := base64.StdEncoding.DecodeString(p.Payload)
-	if err != nil {
-		return err
-	}
 	req := &lospan.DownstreamMessage{
-		Eui:     p.DeviceEUI,
-		Payload: buf,
-		Port:    int32(p.Port),
-		Ack:     p.Ack,
+		Eui:  p.DeviceEUI,
+		Port: int32(p.Port),
+		Ack:  p.Ack,
+	}
+	if p.JSON != "" {
+		req.Object = &structpb.Struct{}
+		if err := protojson.Unmarshal([]byte(p.JSON), req.Object); err != nil {
+			return err
+		}
+	} else if req.Payload, err = base64.StdEncoding.DecodeString(p.Payload); err != nil {
+		return err
 	}
 	_, err = client.SendMessage(ctx, req)
 	if err != nil {
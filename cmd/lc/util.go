@@ -2,7 +2,7 @@ package main
 
 import "github.com/lab5e/lospan/pkg/pb/lospan"
 
-func newPtr[T int | uint32 | int32 | bool | float32 | string | lospan.DeviceState](v T) *T {
+func newPtr[T int | uint32 | int64 | int32 | bool | float32 | string | lospan.DeviceState | lospan.DeviceClass | lospan.MACVersion](v T) *T {
 	ret := new(T)
 	*ret = v
 	return ret
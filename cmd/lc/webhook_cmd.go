@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/pb/lospan"
+)
+
+type webhookCmds struct {
+	Add  webhookAddCmd  `kong:"cmd,help='Add webhook',aliases='create,a'"`
+	Del  webhookDelCmd  `kong:"cmd,help='Remove webhook',aliases='rm,delete,d'"`
+	List webhookListCmd `kong:"cmd,help='List webhooks for application',aliases='ls'"`
+	Log  webhookLogCmd  `kong:"cmd,help='Show delivery log for webhook',aliases='deliveries'"`
+}
+
+type webhookAddCmd struct {
+	App    string            `kong:"help='Application EUI',required"`
+	URL    string            `kong:"help='Webhook URL',required"`
+	Header map[string]string `kong:"help='Custom headers for the requests'"`
+	Secret string            `kong:"help='Secret for the HMAC-SHA256 signature'"`
+}
+
+func (*webhookAddCmd) Run(args *params) error {
+	client, ctx, done, err := createClient(args.Address)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	hook := &lospan.Webhook{
+		ApplicationEui: args.Webhook.Add.App,
+		Url:            args.Webhook.Add.URL,
+		Headers:        args.Webhook.Add.Header,
+	}
+	if args.Webhook.Add.Secret != "" {
+		hook.Secret = newPtr(args.Webhook.Add.Secret)
+	}
+	res, err := client.CreateWebhook(ctx, hook)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created webhook %d for application %s\n", res.Id, res.ApplicationEui)
+	return nil
+}
+
+type webhookDelCmd struct {
+	App string `kong:"help='Application EUI',required"`
+	ID  int64  `kong:"help='Webhook ID',required"`
+}
+
+func (*webhookDelCmd) Run(args *params) error {
+	client, ctx, done, err := createClient(args.Address)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	res, err := client.DeleteWebhook(ctx, &lospan.DeleteWebhookRequest{
+		ApplicationEui: args.Webhook.Del.App,
+		Id:             args.Webhook.Del.ID,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed webhook %d (%s)\n", res.Id, res.Url)
+	return nil
+}
+
+type webhookListCmd struct {
+	App string `kong:"help='Application EUI',required"`
+}
+
+func (*webhookListCmd) Run(args *params) error {
+	client, ctx, done, err := createClient(args.Address)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	res, err := client.ListWebhooks(ctx, &lospan.ListWebhooksRequest{ApplicationEui: args.Webhook.List.App})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d webhooks found\n", len(res.Webhooks))
+	fmt.Println()
+	tw := tabwriter.NewWriter(os.Stdout, 4, 4, 1, ' ', 0)
+	fmt.Fprintln(tw, "ID\tURL\tHeaders")
+	for _, hook := range res.Webhooks {
+		fmt.Fprintf(tw, "%d\t%s\t%d\n", hook.Id, hook.Url, len(hook.Headers))
+	}
+	tw.Flush()
+	return nil
+}
+
+type webhookLogCmd struct {
+	App   string `kong:"help='Application EUI',required"`
+	ID    int64  `kong:"help='Webhook ID',required"`
+	Limit int32  `kong:"help='Number of log entries',default='20'"`
+}
+
+func (*webhookLogCmd) Run(args *params) error {
+	client, ctx, done, err := createClient(args.Address)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	res, err := client.ListWebhookDeliveries(ctx, &lospan.ListWebhookDeliveriesRequest{
+		ApplicationEui: args.Webhook.Log.App,
+		WebhookId:      args.Webhook.Log.ID,
+		Limit:          newPtr(args.Webhook.Log.Limit),
+	})
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 4, 4, 1, ' ', 0)
+	fmt.Fprintln(tw, "Time\tEvent\tAttempt\tStatus\tDelivered\tError")
+	for _, d := range res.Deliveries {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%t\t%s\n",
+			time.UnixMilli(d.Time).Format(time.RFC3339), d.EventType, d.Attempt, d.StatusCode, d.Delivered, d.Error)
+	}
+	tw.Flush()
+	return nil
+}
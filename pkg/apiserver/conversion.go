@@ -1,16 +1,28 @@
 package apiserver
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/events/gwevents"
+	"github.com/lab5e/lospan/pkg/gateway"
+	"github.com/lab5e/lospan/pkg/lg"
 	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/pb/lospan"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
 )
 
 // Convert model.Application -> lospan.Application
 
 func toAPIApplication(app model.Application) *lospan.Application {
 	return &lospan.Application{
-		Eui: app.AppEUI.String(),
-		Tag: &app.Tag,
+		Eui:     app.AppEUI.String(),
+		Tag:     &app.Tag,
+		Decoder: &app.Decoder,
+		Encoder: &app.Encoder,
 	}
 }
 
@@ -20,15 +32,108 @@ func newPtr[T int | int32 | uint32 | int64 | bool | float32 | string](v T) *T {
 	return ret
 }
 
-func toAPIGateway(gw model.Gateway) *lospan.Gateway {
-	return &lospan.Gateway{
+func toAPIGateway(gw model.Gateway, timeout time.Duration, config *server.Parameters, dutyCycle *server.DutyCycleLedger) *lospan.Gateway {
+	ret := &lospan.Gateway{
 		Eui:       gw.GatewayEUI.String(),
 		Ip:        newPtr(gw.IP.String()),
 		Altitude:  newPtr(gw.Altitude),
 		Longitude: newPtr(gw.Longitude),
 		Latitude:  newPtr(gw.Latitude),
 		StrictIp:  newPtr(gw.StrictIP),
+		Online:    newPtr(gw.Online(timeout)),
+	}
+	if gw.LastSeen > 0 {
+		ret.LastSeen = newPtr(time.Unix(0, gw.LastSeen).UnixMilli())
+	}
+	if gw.Stats.Updated > 0 {
+		ret.Stats = toAPIGatewayStats(gw.Stats)
+	}
+	ret.FrequencyPlan = newPtr(gw.Plan.String())
+	if !gw.Channels.Empty() {
+		ret.Channels = toAPIConcentratorConfig(gw.Channels)
+	}
+	plan, err := config.NewBand(gw.Plan)
+	if err != nil {
+		return ret
+	}
+	bandConfig := plan.Configuration()
+	if bandConfig.MaxDwellTime > 0 {
+		ret.MaxDwellTime = newPtr(bandConfig.MaxDwellTime.Milliseconds())
+	}
+	if dutyCycle != nil {
+		for _, s := range bandConfig.SubBands {
+			ret.DutyCycle = append(ret.DutyCycle, toAPISubBandDutyCycle(gw.GatewayEUI, s, dutyCycle))
+		}
+	}
+	return ret
+}
+
+func toAPISubBandDutyCycle(gatewayEUI protocol.EUI, s band.SubBand, dutyCycle *server.DutyCycleLedger) *lospan.SubBandDutyCycle {
+	return &lospan.SubBandDutyCycle{
+		MinFrequency: s.MinFrequency,
+		MaxFrequency: s.MaxFrequency,
+		DutyCycle:    s.DutyCycle,
+		Used:         dutyCycle.Used(gatewayEUI, s).Milliseconds(),
+		Remaining:    dutyCycle.Remaining(gatewayEUI, s).Milliseconds(),
+	}
+}
+
+func toAPIConcentratorChannel(ch model.ConcentratorChannel) *lospan.ConcentratorChannel {
+	return &lospan.ConcentratorChannel{
+		Enabled:      ch.Enabled,
+		Radio:        uint32(ch.Radio),
+		IfOffset:     ch.IF,
+		Bandwidth:    ch.Bandwidth,
+		SpreadFactor: uint32(ch.SpreadFactor),
+		DataRate:     ch.DataRate,
+	}
+}
+
+func toAPIConcentratorConfig(c model.ConcentratorConfig) *lospan.ConcentratorConfig {
+	ret := &lospan.ConcentratorConfig{
+		Radios:  c.Radios,
+		LoraStd: toAPIConcentratorChannel(c.LoRaStd),
+		Fsk:     toAPIConcentratorChannel(c.FSK),
+	}
+	for _, ch := range c.MultiSF {
+		ret.MultiSf = append(ret.MultiSf, toAPIConcentratorChannel(ch))
+	}
+	return ret
+}
+
+func toAPIGatewayStats(stats model.GatewayStats) *lospan.GatewayStats {
+	return &lospan.GatewayStats{
+		Time:         stats.Time,
+		Latitude:     stats.Latitude,
+		Longitude:    stats.Longitude,
+		Altitude:     stats.Altitude,
+		RxReceived:   stats.RxReceived,
+		RxOk:         stats.RxOK,
+		RxForwarded:  stats.RxForwarded,
+		AckRatio:     stats.AckRatio,
+		DownReceived: stats.DownReceived,
+		TxEmitted:    stats.TxEmitted,
+		Updated:      time.Unix(0, stats.Updated).UnixMilli(),
+	}
+}
+
+func toAPIReceptions(receptions []model.GatewayReception) []*lospan.GatewayReception {
+	var ret []*lospan.GatewayReception
+	for _, r := range receptions {
+		rec := &lospan.GatewayReception{
+			GatewayEui: r.GatewayEUI.String(),
+			Rssi:       r.RSSI,
+			Snr:        r.SNR,
+			Channel:    int32(r.Channel),
+			RfChain:    int32(r.RFChain),
+			Timestamp:  r.Timestamp,
+		}
+		if r.FineTimestamp != 0 {
+			rec.FineTimestamp = newPtr(r.FineTimestamp)
+		}
+		ret = append(ret, rec)
 	}
+	return ret
 }
 
 func toAPIState(s model.DeviceState) *lospan.DeviceState {
@@ -46,6 +151,25 @@ func toAPIState(s model.DeviceState) *lospan.DeviceState {
 	return &ret
 }
 
+func toAPIClass(c model.DeviceClass) *lospan.DeviceClass {
+	ret := lospan.DeviceClass_CLASS_A
+	switch c {
+	case model.ClassB:
+		ret = lospan.DeviceClass_CLASS_B
+	case model.ClassC:
+		ret = lospan.DeviceClass_CLASS_C
+	}
+	return &ret
+}
+
+func toAPIMACVersion(v model.MACVersion) *lospan.MACVersion {
+	ret := lospan.MACVersion_LORAWAN_1_0
+	if v == model.LoRaWAN11 {
+		ret = lospan.MACVersion_LORAWAN_1_1
+	}
+	return &ret
+}
+
 func toAPINonces(nonces []uint16) []int32 {
 	var ret []int32
 	for _, n := range nonces {
@@ -63,11 +187,166 @@ func toAPIDevice(d model.Device) *lospan.Device {
 		AppKey:            d.AppKey.Key[:],
 		AppSessionKey:     d.AppSKey.Key[:],
 		NetworkSessionKey: d.NwkSKey.Key[:],
-		FrameCountUp:      newPtr(int32(d.FCntUp)),
-		FrameCountDown:    newPtr(int32(d.FCntDn)),
+		FrameCountUp:      newPtr(d.FCntUp),
+		FrameCountDown:    newPtr(d.FCntDn),
 		RelaxedCounter:    newPtr(d.RelaxedCounter),
 		KeyWarning:        newPtr(d.KeyWarning),
 		Tag:               &d.Tag,
 		DevNonces:         toAPINonces(d.DevNonceHistory[:]),
+		DataRate:          newPtr(int32(d.DataRate)),
+		TxPower:           newPtr(int32(d.TXPower)),
+		ChannelMask:       newPtr(uint32(d.ChannelMask)),
+		Rx1DrOffset:       newPtr(int32(d.RX1DROffset)),
+		Rx2DataRate:       newPtr(int32(d.RX2DataRate)),
+		Rx2Frequency:      newPtr(d.RX2Frequency),
+		Rx1Delay:          newPtr(int32(d.RX1Delay)),
+		MaxDutyCycle:      newPtr(int32(d.MaxDutyCycle)),
+		DeviceClass:       toAPIClass(d.Class),
+		PingPeriodicity:   newPtr(int32(d.PingPeriodicity)),
+		PingDataRate:      newPtr(int32(d.PingDataRate)),
+		PingFrequency:     newPtr(d.PingFrequency),
+		MacVersion:        toAPIMACVersion(d.MACVersion),
+		NetworkKey:        d.NwkKey.Key[:],
+		ProfileId:         newPtr(d.ProfileID),
+	}
+}
+
+func toAPIDeviceStats(s model.DeviceStats) *lospan.DeviceStats {
+	return &lospan.DeviceStats{
+		Eui:         s.DeviceEUI.String(),
+		Received:    s.Received,
+		Lost:        s.Lost,
+		Replayed:    s.Replayed,
+		MicFailed:   s.MICFailed,
+		Rejected:    s.Rejected,
+		LastUpdated: s.LastUpdated,
+		FrameLoss:   s.FrameLoss(),
+	}
+}
+
+func toAPIGatewayEventType(t gwevents.GwEvent) lospan.GatewayEventType {
+	switch t.Type {
+	case gwevents.Rx:
+		return lospan.GatewayEventType_RX
+	case gwevents.Tx:
+		return lospan.GatewayEventType_TX
+	case gwevents.KeepAlive:
+		return lospan.GatewayEventType_KEEP_ALIVE
+	case gwevents.Inactive:
+		return lospan.GatewayEventType_INACTIVE
+	default:
+		return lospan.GatewayEventType_UNKNOWN_EVENT
+	}
+}
+
+func toAPIRxPacket(rxpk gateway.Rxpk) *lospan.GatewayRxPacket {
+	payload, err := base64.StdEncoding.DecodeString(rxpk.RFPackets)
+	if err != nil {
+		lg.Info("Unable to decode rxpk payload: %v", err)
+	}
+	return &lospan.GatewayRxPacket{
+		Time:       rxpk.Time,
+		Timestamp:  rxpk.Timestamp,
+		Frequency:  rxpk.Frequency,
+		Channel:    int32(rxpk.ConcentratorChannel),
+		RfChain:    int32(rxpk.ConcentratorRFChain),
+		Modulation: rxpk.ModulationID,
+		DataRate:   rxpk.DataRateID,
+		CodingRate: rxpk.CodingRateID,
+		Rssi:       rxpk.RSSI,
+		Snr:        rxpk.LoraSNRRatio,
+		Size:       rxpk.PayloadSize,
+		Payload:    payload,
+	}
+}
+
+func toAPITxPacket(txpk gateway.Txpk) *lospan.GatewayTxPacket {
+	payload, err := base64.StdEncoding.DecodeString(txpk.Data)
+	if err != nil {
+		lg.Info("Unable to decode txpk payload: %v", err)
+	}
+	return &lospan.GatewayTxPacket{
+		Immediate:        txpk.Immediate,
+		Timestamp:        txpk.Timestamp,
+		GpsTime:          txpk.GPSTime,
+		Frequency:        txpk.Frequency,
+		RfChain:          int32(txpk.RFChain),
+		Power:            txpk.TxPower,
+		Modulation:       txpk.Modulation,
+		DataRate:         txpk.LoRaDataRate,
+		CodingRate:       txpk.EccCoding,
+		InvertedPolarity: txpk.LoraInvPol,
+		Size:             int32(txpk.PayloadSize),
+		Payload:          payload,
+	}
+}
+
+// toAPIGatewayMessage converts a gateway event into a gateway message. The
+// JSON for Rx and Tx events is decoded into rxpk and txpk fields.
+func toAPIGatewayMessage(eui protocol.EUI, ev gwevents.GwEvent) *lospan.GatewayMessage {
+	ret := &lospan.GatewayMessage{
+		GatewayEui: eui.String(),
+		Type:       toAPIGatewayEventType(ev),
+		Timestamp:  ev.Timestamp.UnixMilli(),
+		Json:       ev.Data,
+	}
+	switch ev.Type {
+	case gwevents.Rx:
+		rxData := gateway.RXData{}
+		if err := json.Unmarshal([]byte(ev.Data), &rxData); err != nil {
+			lg.Info("Unable to decode rxpk JSON from gateway %s: %v", eui, err)
+			break
+		}
+		for _, rxpk := range rxData.Data {
+			ret.Rx = append(ret.Rx, toAPIRxPacket(rxpk))
+		}
+	case gwevents.Tx:
+		txData := gateway.TXData{}
+		if err := json.Unmarshal([]byte(ev.Data), &txData); err != nil {
+			lg.Info("Unable to decode txpk JSON for gateway %s: %v", eui, err)
+			break
+		}
+		ret.Tx = toAPITxPacket(txData.Data)
+	}
+	return ret
+}
+
+// Convert model.Webhook -> lospan.Webhook. The secret is never returned.
+
+func toAPIWebhook(w model.Webhook) *lospan.Webhook {
+	return &lospan.Webhook{
+		Id:             w.ID,
+		ApplicationEui: w.AppEUI.String(),
+		Url:            w.URL,
+		Headers:        w.Headers,
+	}
+}
+
+func toAPIWebhookDelivery(d model.WebhookDelivery) *lospan.WebhookDelivery {
+	return &lospan.WebhookDelivery{
+		WebhookId:  d.WebhookID,
+		EventType:  d.EventType,
+		Time:       time.Unix(0, d.Time).UnixMilli(),
+		Attempt:    int32(d.Attempt),
+		StatusCode: int32(d.StatusCode),
+		Error:      d.Error,
+		Delivered:  d.Delivered,
+	}
+}
+
+func toAPIDeviceProfile(p model.DeviceProfile) *lospan.DeviceProfile {
+	return &lospan.DeviceProfile{
+		Id:                newPtr(p.ID),
+		Name:              newPtr(p.Name),
+		MacVersion:        toAPIMACVersion(p.MACVersion),
+		RegParamsRevision: newPtr(p.RegParamsRevision),
+		DeviceClass:       toAPIClass(p.Class),
+		SupportsJoin:      newPtr(p.SupportsJoin),
+		Rx1Delay:          newPtr(int32(p.RX1Delay)),
+		Rx1DrOffset:       newPtr(int32(p.RX1DROffset)),
+		Rx2DataRate:       newPtr(int32(p.RX2DataRate)),
+		Rx2Frequency:      newPtr(p.RX2Frequency),
+		MaxEirp:           newPtr(int32(p.MaxEIRP)),
+		AdrEnabled:        newPtr(p.ADREnabled),
 	}
 }
@@ -1,6 +1,10 @@
 package apiserver
 
 import (
+	"time"
+
+	"github.com/lab5e/lospan/pkg/codec"
+	"github.com/lab5e/lospan/pkg/events/gwevents"
 	"github.com/lab5e/lospan/pkg/keys"
 	"github.com/lab5e/lospan/pkg/pb/lospan"
 	"github.com/lab5e/lospan/pkg/protocol"
@@ -9,16 +13,32 @@ import (
 )
 
 type apiServer struct {
-	store  *storage.Storage
-	keyGen *keys.KeyGenerator
-	router *server.EventRouter[protocol.EUI, *server.PayloadMessage]
+	store     *storage.Storage
+	keyGen    *keys.KeyGenerator
+	router    *server.EventRouter[protocol.EUI, *server.PayloadMessage]
+	gwRouter  *server.EventRouter[protocol.EUI, gwevents.GwEvent]
+	gwTimeout time.Duration
+	config    *server.Parameters
+	codecs    *codec.Cache
+	dutyCycle *server.DutyCycleLedger
 }
 
-// New creates a new API server
-func New(store *storage.Storage, keyGen *keys.KeyGenerator, router *server.EventRouter[protocol.EUI, *server.PayloadMessage]) (lospan.LospanServer, error) {
+// New creates a new API server. Gateways that haven't been seen within the
+// gateway timeout in the configuration are reported as offline. The gateways'
+// remaining downlink airtime is read from the duty cycle ledger.
+func New(store *storage.Storage, keyGen *keys.KeyGenerator, router *server.EventRouter[protocol.EUI, *server.PayloadMessage], gwRouter *server.EventRouter[protocol.EUI, gwevents.GwEvent], config *server.Parameters, dutyCycle *server.DutyCycleLedger) (lospan.LospanServer, error) {
+	gwTimeout := server.DefaultGatewayTimeout
+	if config != nil && config.GatewayTimeout > 0 {
+		gwTimeout = config.GatewayTimeout
+	}
 	return &apiServer{
-		store:  store,
-		keyGen: keyGen,
-		router: router,
+		store:     store,
+		keyGen:    keyGen,
+		router:    router,
+		gwRouter:  gwRouter,
+		gwTimeout: gwTimeout,
+		config:    config,
+		codecs:    codec.NewCache(),
+		dutyCycle: dutyCycle,
 	}, nil
 }
@@ -33,6 +33,11 @@ func (a *apiServer) CreateApplication(ctx context.Context, req *lospan.CreateApp
 			return nil, status.Error(codes.InvalidArgument, "Invalid application EUI")
 		}
 	}
+	newApp.Decoder = req.GetDecoder()
+	newApp.Encoder = req.GetEncoder()
+	if _, err := a.codecs.Get(newApp.Decoder, newApp.Encoder); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid payload codec: %v", err)
+	}
 	if newApp.AppEUI.ToInt64() == 0 {
 		newApp.AppEUI, err = a.keyGen.NewAppEUI()
 		if err != nil {
@@ -57,6 +62,33 @@ func (a *apiServer) GetApplication(ctx context.Context, req *lospan.GetApplicati
 	return toAPIApplication(app), nil
 }
 
+func (a *apiServer) UpdateApplication(ctx context.Context, req *lospan.Application) (*lospan.Application, error) {
+	eui, err := protocol.EUIFromString(req.Eui)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid EUI")
+	}
+	app, err := a.store.GetApplicationByEUI(eui)
+	if err != nil {
+		return nil, toProtoErr(err)
+	}
+	if req.Tag != nil {
+		app.Tag = *req.Tag
+	}
+	if req.Decoder != nil {
+		app.Decoder = *req.Decoder
+	}
+	if req.Encoder != nil {
+		app.Encoder = *req.Encoder
+	}
+	if _, err := a.codecs.Get(app.Decoder, app.Encoder); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid payload codec: %v", err)
+	}
+	if err := a.store.UpdateApplication(app); err != nil {
+		return nil, toProtoErr(err)
+	}
+	return toAPIApplication(app), nil
+}
+
 func (a *apiServer) DeleteApplication(ctx context.Context, req *lospan.DeleteApplicationRequest) (*lospan.Application, error) {
 	eui, err := protocol.EUIFromString(req.Eui)
 	if err != nil {
@@ -28,6 +28,48 @@ func toState(s *lospan.DeviceState) (model.DeviceState, error) {
 	return ret, nil
 }
 
+func toClass(c *lospan.DeviceClass) (model.DeviceClass, error) {
+	ret := model.ClassA
+	if c != nil {
+		switch *c {
+		case lospan.DeviceClass_CLASS_A:
+			ret = model.ClassA
+		case lospan.DeviceClass_CLASS_B:
+			ret = model.ClassB
+		case lospan.DeviceClass_CLASS_C:
+			ret = model.ClassC
+		default:
+			return ret, status.Error(codes.InvalidArgument, "Device class must be A, B or C")
+		}
+	}
+	return ret, nil
+}
+
+func toMACVersion(v *lospan.MACVersion) (model.MACVersion, error) {
+	ret := model.LoRaWAN10
+	if v != nil {
+		switch *v {
+		case lospan.MACVersion_LORAWAN_1_0:
+			ret = model.LoRaWAN10
+		case lospan.MACVersion_LORAWAN_1_1:
+			ret = model.LoRaWAN11
+		default:
+			return ret, status.Error(codes.InvalidArgument, "MAC version must be 1.0 or 1.1")
+		}
+	}
+	return ret, nil
+}
+
+// setABPSessionKeys uses the network session key for all of the LoRaWAN 1.1
+// network session keys since there's only a single session key in the API.
+func setABPSessionKeys(d *model.Device) {
+	if d.State != model.PersonalizedDevice || d.MACVersion != model.LoRaWAN11 {
+		return
+	}
+	d.SNwkSIntKey = d.NwkSKey
+	d.NwkSEncKey = d.NwkSKey
+}
+
 func (a *apiServer) CreateDevice(ctx context.Context, req *lospan.Device) (*lospan.Device, error) {
 	var eui protocol.EUI
 	var err error
@@ -57,6 +99,22 @@ func (a *apiServer) CreateDevice(ctx context.Context, req *lospan.Device) (*losp
 	if err != nil {
 		return nil, err
 	}
+	// The device class and MAC version in the request override the profile
+	if err := a.applyProfile(&d, req); err != nil {
+		return nil, err
+	}
+	if req.DeviceClass != nil {
+		d.Class, err = toClass(req.DeviceClass)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.MacVersion != nil {
+		d.MACVersion, err = toMACVersion(req.MacVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	if req.DevAddr != nil {
 		d.DevAddr = protocol.DevAddrFromUint32(req.GetDevAddr())
@@ -68,6 +126,12 @@ func (a *apiServer) CreateDevice(ctx context.Context, req *lospan.Device) (*losp
 			return nil, status.Error(codes.InvalidArgument, "Invalid App Key")
 		}
 	}
+	if req.NetworkKey != nil {
+		d.NwkKey, err = protocol.AESKeyFromString(hex.EncodeToString(req.NetworkKey))
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid Network Key")
+		}
+	}
 	if req.AppSessionKey != nil {
 		d.AppSKey, err = protocol.AESKeyFromString(hex.EncodeToString(req.AppSessionKey))
 		if err != nil {
@@ -89,17 +153,17 @@ func (a *apiServer) CreateDevice(ctx context.Context, req *lospan.Device) (*losp
 	}
 	d.FCntDn = 0
 	if req.FrameCountDown != nil {
-		d.FCntDn = uint16(req.GetFrameCountDown())
+		d.FCntDn = req.GetFrameCountDown()
 	}
 	d.FCntUp = 0
 	if req.FrameCountUp != nil {
-		d.FCntUp = uint16(req.GetFrameCountUp())
+		d.FCntUp = req.GetFrameCountUp()
 	}
 	if d.State == model.OverTheAirDevice && (!d.AppSKey.Empty() || d.DevAddr.ToUint32() != 0 || !d.NwkSKey.Empty()) {
 		return nil, status.Error(codes.InvalidArgument, "DevAddr, AppSKey and NwkSKey can only be specified for ABP devices")
 	}
-	if d.State == model.PersonalizedDevice && !d.AppKey.Empty() {
-		return nil, status.Error(codes.InvalidArgument, "AppKey can only be specified for OTAA devices")
+	if d.State == model.PersonalizedDevice && (!d.AppKey.Empty() || !d.NwkKey.Empty()) {
+		return nil, status.Error(codes.InvalidArgument, "AppKey and NwkKey can only be specified for OTAA devices")
 	}
 
 	if d.State == model.OverTheAirDevice {
@@ -109,6 +173,12 @@ func (a *apiServer) CreateDevice(ctx context.Context, req *lospan.Device) (*losp
 				return nil, toProtoErr(err)
 			}
 		}
+		if d.MACVersion == model.LoRaWAN11 && d.NwkKey.Empty() {
+			d.NwkKey, err = protocol.NewAESKey()
+			if err != nil {
+				return nil, toProtoErr(err)
+			}
+		}
 	}
 	if d.State == model.PersonalizedDevice {
 		if d.AppSKey.Empty() {
@@ -127,6 +197,7 @@ func (a *apiServer) CreateDevice(ctx context.Context, req *lospan.Device) (*losp
 			d.DevAddr = protocol.NewDevAddr()
 		}
 	}
+	setABPSessionKeys(&d)
 
 	if err := a.store.CreateDevice(d, d.AppEUI); err != nil {
 		return nil, toProtoErr(err)
@@ -164,6 +235,22 @@ func (a *apiServer) DeleteDevice(ctx context.Context, req *lospan.DeleteDeviceRe
 	return toAPIDevice(d), nil
 }
 
+func (a *apiServer) GetDeviceStats(ctx context.Context, req *lospan.GetDeviceStatsRequest) (*lospan.DeviceStats, error) {
+	eui, err := protocol.EUIFromString(req.GetEui())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid EUI")
+	}
+
+	if _, err := a.store.GetDeviceByEUI(eui); err != nil {
+		return nil, toProtoErr(err)
+	}
+	stats, err := a.store.GetDeviceStats(eui)
+	if err != nil {
+		return nil, toProtoErr(err)
+	}
+	return toAPIDeviceStats(stats), nil
+}
+
 func (a *apiServer) UpdateDevice(ctx context.Context, req *lospan.Device) (*lospan.Device, error) {
 	eui, err := protocol.EUIFromString(req.GetEui())
 	if err != nil {
@@ -180,6 +267,22 @@ func (a *apiServer) UpdateDevice(ctx context.Context, req *lospan.Device) (*losp
 			return nil, err
 		}
 	}
+	// The device class and MAC version in the request override the profile
+	if err := a.applyProfile(&d, req); err != nil {
+		return nil, err
+	}
+	if req.DeviceClass != nil {
+		d.Class, err = toClass(req.DeviceClass)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.MacVersion != nil {
+		d.MACVersion, err = toMACVersion(req.MacVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if req.DevAddr != nil {
 		d.DevAddr = protocol.DevAddrFromUint32(req.GetDevAddr())
 	}
@@ -189,6 +292,12 @@ func (a *apiServer) UpdateDevice(ctx context.Context, req *lospan.Device) (*losp
 			return nil, status.Error(codes.InvalidArgument, "Invalid App Key")
 		}
 	}
+	if len(req.NetworkKey) > 0 {
+		d.NwkKey, err = protocol.AESKeyFromString(hex.EncodeToString(req.NetworkKey))
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid Network Key")
+		}
+	}
 	if len(req.AppSessionKey) > 0 {
 		d.AppSKey, err = protocol.AESKeyFromString(hex.EncodeToString(req.AppSessionKey))
 		if err != nil {
@@ -202,10 +311,10 @@ func (a *apiServer) UpdateDevice(ctx context.Context, req *lospan.Device) (*losp
 		}
 	}
 	if req.FrameCountUp != nil {
-		d.FCntUp = uint16(req.GetFrameCountUp())
+		d.FCntUp = req.GetFrameCountUp()
 	}
 	if req.FrameCountDown != nil {
-		d.FCntDn = uint16(req.GetFrameCountDown())
+		d.FCntDn = req.GetFrameCountDown()
 	}
 	if req.KeyWarning != nil {
 		d.KeyWarning = req.GetKeyWarning()
@@ -244,6 +353,13 @@ func (a *apiServer) UpdateDevice(ctx context.Context, req *lospan.Device) (*losp
 			// no checks
 		}
 	}
+	if d.State == model.OverTheAirDevice && d.MACVersion == model.LoRaWAN11 && d.NwkKey.Empty() {
+		d.NwkKey, err = protocol.NewAESKey()
+		if err != nil {
+			return nil, status.Error(codes.Internal, "Could not create network key for device")
+		}
+	}
+	setABPSessionKeys(&d)
 	if err := a.store.UpdateDevice(d); err != nil {
 		return nil, toProtoErr(err)
 	}
@@ -2,9 +2,10 @@ package apiserver
 
 import (
 	"context"
-	"errors"
 	"net"
 
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/lg"
 	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/pb/lospan"
 	"github.com/lab5e/lospan/pkg/protocol"
@@ -12,6 +13,49 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// toConcentratorChannel converts an API concentrator channel
+func toConcentratorChannel(ch *lospan.ConcentratorChannel) model.ConcentratorChannel {
+	return model.ConcentratorChannel{
+		Enabled:      ch.GetEnabled(),
+		Radio:        uint8(ch.GetRadio()),
+		IF:           ch.GetIfOffset(),
+		Bandwidth:    ch.GetBandwidth(),
+		SpreadFactor: uint8(ch.GetSpreadFactor()),
+		DataRate:     ch.GetDataRate(),
+	}
+}
+
+// updateGatewayPlan sets the frequency plan and channel configuration for the
+// gateway if they are set in the request. An empty channel configuration
+// resets the gateway to the default channels for the plan.
+func updateGatewayPlan(gw *model.Gateway, req *lospan.Gateway) error {
+	if req.FrequencyPlan != nil {
+		plan, err := band.ParseBand(req.GetFrequencyPlan())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "Unknown frequency plan")
+		}
+		if _, err := band.NewBand(plan); err != nil {
+			return status.Error(codes.InvalidArgument, "Frequency plan is not supported")
+		}
+		gw.Plan = plan
+	}
+	if req.Channels != nil {
+		channels := model.ConcentratorConfig{
+			Radios:  req.Channels.Radios,
+			LoRaStd: toConcentratorChannel(req.Channels.LoraStd),
+			FSK:     toConcentratorChannel(req.Channels.Fsk),
+		}
+		for _, ch := range req.Channels.MultiSf {
+			channels.MultiSF = append(channels.MultiSF, toConcentratorChannel(ch))
+		}
+		if err := channels.Validate(); err != nil {
+			return status.Errorf(codes.InvalidArgument, "Invalid channel configuration: %v", err)
+		}
+		gw.Channels = channels
+	}
+	return nil
+}
+
 func (a *apiServer) CreateGateway(ctx context.Context, req *lospan.Gateway) (*lospan.Gateway, error) {
 	var err error
 
@@ -48,12 +92,15 @@ func (a *apiServer) CreateGateway(ctx context.Context, req *lospan.Gateway) (*lo
 	}
 	newGW.IP = ip
 	newGW.Altitude = req.GetAltitude()
+	if err := updateGatewayPlan(&newGW, req); err != nil {
+		return nil, err
+	}
 
 	if err := a.store.CreateGateway(newGW); err != nil {
 		return nil, toProtoErr(err)
 	}
 
-	return toAPIGateway(newGW), nil
+	return toAPIGateway(newGW, a.gwTimeout, a.config, a.dutyCycle), nil
 }
 
 func (a *apiServer) ListGateways(ctx context.Context, req *lospan.ListGatewaysRequest) (*lospan.ListGatewaysResponse, error) {
@@ -65,7 +112,7 @@ func (a *apiServer) ListGateways(ctx context.Context, req *lospan.ListGatewaysRe
 		Gateways: make([]*lospan.Gateway, 0),
 	}
 	for _, gw := range gws {
-		ret.Gateways = append(ret.Gateways, toAPIGateway(gw))
+		ret.Gateways = append(ret.Gateways, toAPIGateway(gw, a.gwTimeout, a.config, a.dutyCycle))
 	}
 	return ret, nil
 }
@@ -82,7 +129,7 @@ func (a *apiServer) GetGateway(ctx context.Context, req *lospan.GetGatewayReques
 	if err != nil {
 		return nil, toProtoErr(err)
 	}
-	return toAPIGateway(gw), nil
+	return toAPIGateway(gw, a.gwTimeout, a.config, a.dutyCycle), nil
 }
 
 func (a *apiServer) DeleteGateway(ctx context.Context, req *lospan.DeleteGatewayRequest) (*lospan.Gateway, error) {
@@ -102,7 +149,7 @@ func (a *apiServer) DeleteGateway(ctx context.Context, req *lospan.DeleteGateway
 		return nil, toProtoErr(err)
 	}
 
-	return toAPIGateway(gw), nil
+	return toAPIGateway(gw, a.gwTimeout, a.config, a.dutyCycle), nil
 }
 
 func (a *apiServer) UpdateGateway(ctx context.Context, req *lospan.Gateway) (*lospan.Gateway, error) {
@@ -138,12 +185,34 @@ func (a *apiServer) UpdateGateway(ctx context.Context, req *lospan.Gateway) (*lo
 	if req.StrictIp != nil {
 		gw.StrictIP = req.GetStrictIp()
 	}
+	if err := updateGatewayPlan(&gw, req); err != nil {
+		return nil, err
+	}
 	if err := a.store.UpdateGateway(gw); err != nil {
 		return nil, toProtoErr(err)
 	}
-	return toAPIGateway(gw), nil
+	return toAPIGateway(gw, a.gwTimeout, a.config, a.dutyCycle), nil
 }
 
 func (a *apiServer) StreamGateway(req *lospan.StreamGatewayRequest, stream lospan.Lospan_StreamGatewayServer) error {
-	return errors.New("not implemented")
+	eui, err := protocol.EUIFromString(req.Eui)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "Invalid gateway EUI")
+	}
+	if a.gwRouter == nil {
+		return status.Error(codes.Unavailable, "Gateway events are not available")
+	}
+	evChan := a.gwRouter.Subscribe(eui)
+	defer a.gwRouter.Unsubscribe(evChan)
+	for {
+		select {
+		case ev := <-evChan:
+			if err := stream.Send(toAPIGatewayMessage(eui, ev)); err != nil {
+				lg.Warning("Error sending gateway message. Closing stream: %v", err)
+				return nil
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
 }
@@ -9,8 +9,10 @@ import (
 	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/pb/lospan"
 	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func (a *apiServer) Inbox(ctx context.Context, req *lospan.InboxRequest) (*lospan.InboxResponse, error) {
@@ -25,8 +27,9 @@ func (a *apiServer) Inbox(ctx context.Context, req *lospan.InboxRequest) (*lospa
 	ret := &lospan.InboxResponse{
 		Messages: make([]*lospan.UpstreamMessage, 0),
 	}
+	app := a.deviceApplication(eui)
 	for _, msg := range list {
-		ret.Messages = append(ret.Messages, &lospan.UpstreamMessage{
+		upstream := &lospan.UpstreamMessage{
 			Eui:        msg.DeviceEUI.String(),
 			Timestamp:  msg.Timestamp,
 			Payload:    msg.Data[:],
@@ -36,11 +39,45 @@ func (a *apiServer) Inbox(ctx context.Context, req *lospan.InboxRequest) (*lospa
 			Frequency:  msg.Frequency,
 			DataRate:   msg.DataRate,
 			DevAddr:    msg.DevAddr.ToUint32(),
-		})
+			Receptions: toAPIReceptions(msg.Receptions),
+			Port:       int32(msg.FPort),
+		}
+		a.decodePayload(app, upstream)
+		ret.Messages = append(ret.Messages, upstream)
 	}
 	return ret, nil
 }
 
+// deviceApplication returns the application for a device. An empty
+// application is returned if the device or application can't be found.
+func (a *apiServer) deviceApplication(eui protocol.EUI) model.Application {
+	device, err := a.store.GetDeviceByEUI(eui)
+	if err != nil {
+		return model.NewApplication()
+	}
+	app, err := a.store.GetApplicationByEUI(device.AppEUI)
+	if err != nil {
+		lg.Warning("Unable to retrieve application for device %s: %v", eui, err)
+		return model.NewApplication()
+	}
+	return app
+}
+
+// decodePayload decodes the payload for a stored message with the
+// application's decoder. Decoder errors are reported on the message.
+func (a *apiServer) decodePayload(app model.Application, msg *lospan.UpstreamMessage) {
+	if app.Decoder == "" || len(msg.Payload) == 0 {
+		return
+	}
+	c, err := a.codecs.Get(app.Decoder, "")
+	if err == nil {
+		msg.DecodedPayload, err = c.Decode(uint8(msg.Port), msg.Payload)
+	}
+	if err != nil {
+		msg.CodecError = newPtr(err.Error())
+	}
+}
+
 func (a *apiServer) Outbox(ctx context.Context, req *lospan.OutboxRequest) (*lospan.OutboxResponse, error) {
 	eui, err := protocol.EUIFromString(req.Eui)
 	if err != nil {
@@ -83,9 +120,15 @@ func (a *apiServer) SendMessage(ctx context.Context, req *lospan.DownstreamMessa
 	if req.Port > 255 || req.Port < 0 {
 		return nil, status.Error(codes.InvalidArgument, "Port must be 0-255")
 	}
+	payload := req.Payload
+	if len(payload) == 0 && req.Object != nil {
+		if payload, err = a.encodePayload(eui, uint8(req.Port), req.Object); err != nil {
+			return nil, err
+		}
+	}
 	msg := model.DownstreamMessage{
 		DeviceEUI:   eui,
-		Data:        hex.EncodeToString(req.Payload),
+		Data:        hex.EncodeToString(payload),
 		Port:        uint8(req.Port),
 		Ack:         req.Ack,
 		CreatedTime: time.Now().UnixMilli(),
@@ -98,7 +141,7 @@ func (a *apiServer) SendMessage(ctx context.Context, req *lospan.DownstreamMessa
 
 	return &lospan.DownstreamMessage{
 		Eui:     req.Eui,
-		Payload: req.Payload[:],
+		Payload: payload,
 		Port:    int32(msg.Port),
 		Ack:     msg.Ack,
 		Created: newPtr(msg.CreatedTime),
@@ -115,16 +158,23 @@ func (a *apiServer) StreamMessages(req *lospan.StreamMessagesRequest, stream los
 	msgChan := a.router.Subscribe(eui)
 	defer a.router.Unsubscribe(msgChan)
 	for msg := range msgChan {
+		if msg.Type != server.UplinkEvent {
+			continue
+		}
 		if err := stream.Send(&lospan.UpstreamMessage{
-			Eui:        msg.Device.DeviceEUI.String(),
-			Timestamp:  time.Now().UnixMilli(),
-			Payload:    msg.Payload,
-			GatewayEui: msg.FrameContext.GatewayContext.Gateway.GatewayEUI.String(),
-			Rssi:       msg.FrameContext.GatewayContext.Radio.RSSI,
-			Snr:        msg.FrameContext.GatewayContext.Radio.SNR,
-			Frequency:  msg.FrameContext.GatewayContext.Radio.Frequency,
-			DataRate:   msg.FrameContext.GatewayContext.Radio.DataRate,
-			DevAddr:    msg.Device.DevAddr.ToUint32(),
+			Eui:            msg.Device.DeviceEUI.String(),
+			Timestamp:      time.Now().UnixMilli(),
+			Payload:        msg.Payload,
+			GatewayEui:     msg.FrameContext.GatewayContext.Gateway.GatewayEUI.String(),
+			Rssi:           msg.FrameContext.GatewayContext.Radio.RSSI,
+			Snr:            msg.FrameContext.GatewayContext.Radio.SNR,
+			Frequency:      msg.FrameContext.GatewayContext.Radio.Frequency,
+			DataRate:       msg.FrameContext.GatewayContext.Radio.DataRate,
+			DevAddr:        msg.Device.DevAddr.ToUint32(),
+			Receptions:     toAPIReceptions(msg.FrameContext.GatewayReceptions()),
+			Port:           int32(msg.FPort),
+			DecodedPayload: msg.Object,
+			CodecError:     codecError(msg.CodecError),
 		}); err != nil {
 			lg.Warning("Error sending message. Closing stream: %v", err)
 			return nil
@@ -132,3 +182,36 @@ func (a *apiServer) StreamMessages(req *lospan.StreamMessagesRequest, stream los
 	}
 	return nil
 }
+
+// encodePayload encodes the object with the encoder for the device's
+// application
+func (a *apiServer) encodePayload(eui protocol.EUI, port uint8, object *structpb.Struct) ([]byte, error) {
+	device, err := a.store.GetDeviceByEUI(eui)
+	if err != nil {
+		return nil, toProtoErr(err)
+	}
+	app, err := a.store.GetApplicationByEUI(device.AppEUI)
+	if err != nil {
+		return nil, toProtoErr(err)
+	}
+	if app.Encoder == "" {
+		return nil, status.Error(codes.FailedPrecondition, "Application has no payload encoder")
+	}
+	c, err := a.codecs.Get("", app.Encoder)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "Invalid payload encoder: %v", err)
+	}
+	payload, err := c.Encode(port, object)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Unable to encode payload: %v", err)
+	}
+	return payload, nil
+}
+
+// codecError returns nil if there's no codec error
+func codecError(err string) *string {
+	if err == "" {
+		return nil
+	}
+	return &err
+}
@@ -0,0 +1,167 @@
+package apiserver
+
+import (
+	"context"
+
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/pb/lospan"
+	"github.com/lab5e/lospan/pkg/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// updateProfile copies the fields that are set in the request to the profile
+func updateProfile(p *model.DeviceProfile, req *lospan.DeviceProfile) error {
+	if req.GetRx1Delay() < 0 || req.GetRx1Delay() > 15 || req.GetRx1DrOffset() < 0 || req.GetRx1DrOffset() > 7 ||
+		req.GetRx2DataRate() < 0 || req.GetRx2DataRate() > 15 || req.GetMaxEirp() < 0 || req.GetMaxEirp() > 255 {
+		return status.Error(codes.InvalidArgument, "Profile value out of range")
+	}
+	var err error
+	if req.Name != nil {
+		p.Name = req.GetName()
+	}
+	if req.MacVersion != nil {
+		p.MACVersion, err = toMACVersion(req.MacVersion)
+		if err != nil {
+			return err
+		}
+	}
+	if req.RegParamsRevision != nil {
+		p.RegParamsRevision = req.GetRegParamsRevision()
+	}
+	if req.DeviceClass != nil {
+		p.Class, err = toClass(req.DeviceClass)
+		if err != nil {
+			return err
+		}
+	}
+	if req.SupportsJoin != nil {
+		p.SupportsJoin = req.GetSupportsJoin()
+	}
+	if req.Rx1Delay != nil {
+		p.RX1Delay = uint8(req.GetRx1Delay())
+	}
+	if req.Rx1DrOffset != nil {
+		p.RX1DROffset = uint8(req.GetRx1DrOffset())
+	}
+	if req.Rx2DataRate != nil {
+		p.RX2DataRate = uint8(req.GetRx2DataRate())
+	}
+	if req.Rx2Frequency != nil {
+		p.RX2Frequency = req.GetRx2Frequency()
+	}
+	if req.MaxEirp != nil {
+		p.MaxEIRP = uint8(req.GetMaxEirp())
+	}
+	if req.AdrEnabled != nil {
+		p.ADREnabled = req.GetAdrEnabled()
+	}
+	if err := p.Validate(); err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid profile: %v", err)
+	}
+	return nil
+}
+
+func (a *apiServer) ListDeviceProfiles(ctx context.Context, req *lospan.ListDeviceProfilesRequest) (*lospan.ListDeviceProfilesResponse, error) {
+	profiles, err := a.store.ListDeviceProfiles()
+	if err != nil {
+		return nil, toProtoErr(err)
+	}
+	ret := &lospan.ListDeviceProfilesResponse{
+		Profiles: make([]*lospan.DeviceProfile, 0),
+	}
+	for _, p := range profiles {
+		ret.Profiles = append(ret.Profiles, toAPIDeviceProfile(p))
+	}
+	return ret, nil
+}
+
+func (a *apiServer) CreateDeviceProfile(ctx context.Context, req *lospan.DeviceProfile) (*lospan.DeviceProfile, error) {
+	p := model.NewDeviceProfile()
+	if err := updateProfile(&p, req); err != nil {
+		return nil, err
+	}
+	p, err := a.store.CreateDeviceProfile(p)
+	if err != nil {
+		return nil, toProtoErr(err)
+	}
+	return toAPIDeviceProfile(p), nil
+}
+
+func (a *apiServer) GetDeviceProfile(ctx context.Context, req *lospan.GetDeviceProfileRequest) (*lospan.DeviceProfile, error) {
+	p, err := a.store.GetDeviceProfile(req.Id)
+	if err != nil {
+		return nil, toProtoErr(err)
+	}
+	return toAPIDeviceProfile(p), nil
+}
+
+func (a *apiServer) UpdateDeviceProfile(ctx context.Context, req *lospan.DeviceProfile) (*lospan.DeviceProfile, error) {
+	if req.Id == nil {
+		return nil, status.Error(codes.InvalidArgument, "Missing profile ID")
+	}
+	p, err := a.store.GetDeviceProfile(req.GetId())
+	if err != nil {
+		return nil, toProtoErr(err)
+	}
+	if err := updateProfile(&p, req); err != nil {
+		return nil, err
+	}
+	if err := a.store.UpdateDeviceProfile(p); err != nil {
+		return nil, toProtoErr(err)
+	}
+	if err := a.updateProfileDevices(p); err != nil {
+		return nil, toProtoErr(err)
+	}
+	return toAPIDeviceProfile(p), nil
+}
+
+// updateProfileDevices applies the profile to the ABP devices using it. OTAA
+// devices get the new settings when they join.
+func (a *apiServer) updateProfileDevices(p model.DeviceProfile) error {
+	devices, err := a.store.GetDevicesByProfile(p.ID)
+	if err != nil {
+		return err
+	}
+	for _, d := range devices {
+		if d.State != model.PersonalizedDevice {
+			continue
+		}
+		p.Apply(&d)
+		if err := a.store.UpdateDevice(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *apiServer) DeleteDeviceProfile(ctx context.Context, req *lospan.DeleteDeviceProfileRequest) (*lospan.DeviceProfile, error) {
+	p, err := a.store.GetDeviceProfile(req.Id)
+	if err != nil {
+		return nil, toProtoErr(err)
+	}
+	if err := a.store.DeleteDeviceProfile(p.ID); err != nil {
+		return nil, toProtoErr(err)
+	}
+	return toAPIDeviceProfile(p), nil
+}
+
+// applyProfile applies the device profile set in the request to the device
+func (a *apiServer) applyProfile(d *model.Device, req *lospan.Device) error {
+	if req.ProfileId == nil {
+		return nil
+	}
+	if req.GetProfileId() == 0 {
+		d.ProfileID = 0
+		return nil
+	}
+	p, err := a.store.GetDeviceProfile(req.GetProfileId())
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return status.Error(codes.InvalidArgument, "Unknown device profile")
+		}
+		return toProtoErr(err)
+	}
+	p.Apply(d)
+	return nil
+}
@@ -0,0 +1,112 @@
+package apiserver
+
+import (
+	"context"
+
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/pb/lospan"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxWebhookDeliveries is the default number of delivery log entries
+// returned by ListWebhookDeliveries
+const maxWebhookDeliveries = 50
+
+func (a *apiServer) CreateWebhook(ctx context.Context, req *lospan.Webhook) (*lospan.Webhook, error) {
+	eui, err := protocol.EUIFromString(req.ApplicationEui)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid application EUI")
+	}
+	if _, err := a.store.GetApplicationByEUI(eui); err != nil {
+		return nil, toProtoErr(err)
+	}
+	hook := model.Webhook{
+		AppEUI:  eui,
+		URL:     req.Url,
+		Headers: req.Headers,
+		Secret:  req.GetSecret(),
+	}
+	if err := hook.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid webhook URL: %v", err)
+	}
+	hook, err = a.store.CreateWebhook(hook)
+	if err != nil {
+		return nil, toProtoErr(err)
+	}
+	return toAPIWebhook(hook), nil
+}
+
+func (a *apiServer) ListWebhooks(ctx context.Context, req *lospan.ListWebhooksRequest) (*lospan.ListWebhooksResponse, error) {
+	eui, err := protocol.EUIFromString(req.ApplicationEui)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid application EUI")
+	}
+	hooks, err := a.store.ListWebhooks(eui)
+	if err != nil {
+		return nil, toProtoErr(err)
+	}
+	ret := &lospan.ListWebhooksResponse{
+		Webhooks: make([]*lospan.Webhook, 0),
+	}
+	for _, hook := range hooks {
+		ret.Webhooks = append(ret.Webhooks, toAPIWebhook(hook))
+	}
+	return ret, nil
+}
+
+// findWebhook looks up a webhook in an application
+func (a *apiServer) findWebhook(appEUI string, id int64) (model.Webhook, error) {
+	eui, err := protocol.EUIFromString(appEUI)
+	if err != nil {
+		return model.Webhook{}, status.Error(codes.InvalidArgument, "Invalid application EUI")
+	}
+	hooks, err := a.store.ListWebhooks(eui)
+	if err != nil {
+		return model.Webhook{}, toProtoErr(err)
+	}
+	for _, hook := range hooks {
+		if hook.ID == id {
+			return hook, nil
+		}
+	}
+	return model.Webhook{}, toProtoErr(storage.ErrNotFound)
+}
+
+func (a *apiServer) DeleteWebhook(ctx context.Context, req *lospan.DeleteWebhookRequest) (*lospan.Webhook, error) {
+	hook, err := a.findWebhook(req.ApplicationEui, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.store.DeleteWebhook(hook.AppEUI, hook.ID); err != nil {
+		return nil, toProtoErr(err)
+	}
+	return toAPIWebhook(hook), nil
+}
+
+func (a *apiServer) ListWebhookDeliveries(ctx context.Context, req *lospan.ListWebhookDeliveriesRequest) (*lospan.ListWebhookDeliveriesResponse, error) {
+	hook, err := a.findWebhook(req.ApplicationEui, req.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+	limit := maxWebhookDeliveries
+	if req.Limit != nil {
+		if *req.Limit <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "Limit must be greater than 0")
+		}
+		limit = int(*req.Limit)
+	}
+	deliveries, err := a.store.ListWebhookDeliveries(hook.ID, limit)
+	if err != nil {
+		return nil, toProtoErr(err)
+	}
+	ret := &lospan.ListWebhookDeliveriesResponse{
+		Deliveries: make([]*lospan.WebhookDelivery, 0),
+	}
+	for _, d := range deliveries {
+		ret.Deliveries = append(ret.Deliveries, toAPIWebhookDelivery(d))
+	}
+	return ret, nil
+}
@@ -0,0 +1,220 @@
+package band
+
+import (
+	"fmt"
+	"time"
+)
+
+// AS923 represents configuration and frequency plan for the AS 923MHz ISM Band.
+// The band is split into four groups with different channel frequencies. The
+// data rates and the rest of the settings are the same for all groups. The
+// dwell time limits are off by default and turned on with WithDwellTime.
+type AS923 struct {
+	configuration       Configuration
+	DownstreamDataRates [][]uint8
+	group               int
+	dwellTime           bool
+}
+
+// as923Frequencies is the default channels for each of the AS923 groups. The
+// first channel is also used for RX2 and the second for the beacon [RP 2.8.2]
+var as923Frequencies = map[int][2]float32{
+	1: {923.2, 923.4},
+	2: {921.4, 921.6},
+	3: {916.6, 916.8},
+	4: {917.3, 917.5},
+}
+
+func newAS923(group int) AS923 {
+	freqs := as923Frequencies[group]
+	return AS923{
+		group: group,
+		configuration: Configuration{
+			ReceiveDelay1:            1,        // [RP 2.8.8]
+			ReceiveDelay2:            2,        // ReceiveDelay1 + 1 according to [RP 2.8.8]
+			JoinAccepDelay1:          5,        // [RP 2.8.8]
+			JoinAccepDelay2:          6,        // [RP 2.8.8]
+			MaxFCntGap:               16384,    // [RP 2.8.8]
+			AdrAckLimit:              64,       // [RP 2.8.8]
+			AdrAckDelay:              32,       // [RP 2.8.8]
+			DefaultTxPower:           16,       // Default max EIRP [RP 2.8.3]
+			SupportsJoinAcceptCFList: true,     // [RP 2.8.4]
+			MinFrequency:             915.0,    // [RP 2.8.1]
+			MaxFrequency:             928.0,    // [RP 2.8.1]
+			RX2Frequency:             freqs[0], // [RP 2.8.7]
+			RX2DataRate:              2,        // [RP 2.8.7]
+			MaxADRDataRate:           5,        // SF7BW125 is the fastest rate on all channels
+			MaxTxPower:               7,        // [RP 2.8.3]
+			DefaultChannelMask:       0x0003,   // The two mandatory channels
+			BeaconDataRate:           3,        // [RP 2.8.9]
+			PingSlotDataRate:         3,        // [RP 2.8.9]
+			MandatoryEndDeviceChannels: []float32{
+				freqs[0],
+				freqs[1]}, // [RP 2.8.2]
+			JoinReqChannels: []float32{
+				freqs[0],
+				freqs[1]}, // [RP 2.8.2]
+			BeaconFrequencies:   []float32{freqs[1]}, // [RP 2.8.9]
+			PingSlotFrequencies: []float32{freqs[1]}, // [RP 2.8.9]
+			BeaconRFUSize:       [2]int{2, 0},        // [RP 2.8.9]
+		},
+		// The table assumes DownlinkDwellTime = 0. Offsets 6 and 7 increase
+		// the data rate by 1 and 2 [RP 2.8.7]
+		DownstreamDataRates: [][]uint8{
+			{0, 0, 0, 0, 0, 0, 1, 2}, // DR0
+			{1, 0, 0, 0, 0, 0, 2, 3}, // DR1
+			{2, 1, 0, 0, 0, 0, 3, 4}, // DR2
+			{3, 2, 1, 0, 0, 0, 4, 5}, // DR3
+			{4, 3, 2, 1, 0, 0, 5, 5}, // DR4
+			{5, 4, 3, 2, 1, 0, 5, 5}, // DR5
+			{5, 5, 4, 3, 2, 1, 5, 5}, // DR6
+			{5, 5, 5, 4, 3, 2, 5, 5}, // DR7
+		},
+	}
+}
+
+// as923DwellTimeDataRates is the RX1 data rate table when DownlinkDwellTime
+// = 1. DR2 is the lowest downlink data rate [RP 2.8.7]
+var as923DwellTimeDataRates = [][]uint8{
+	{2, 2, 2, 2, 2, 2, 2, 2}, // DR0
+	{2, 2, 2, 2, 2, 2, 2, 3}, // DR1
+	{2, 2, 2, 2, 2, 2, 3, 4}, // DR2
+	{3, 2, 2, 2, 2, 2, 4, 5}, // DR3
+	{4, 3, 2, 2, 2, 2, 5, 5}, // DR4
+	{5, 4, 3, 2, 2, 2, 5, 5}, // DR5
+	{5, 5, 4, 3, 2, 2, 5, 5}, // DR6
+	{5, 5, 5, 4, 3, 2, 5, 5}, // DR7
+}
+
+// DwellTime returns true if the 400 ms dwell time limit applies
+func (b AS923) DwellTime() bool {
+	return b.dwellTime
+}
+
+// WithDwellTime returns the band with the 400 ms dwell time limit for both
+// uplinks and downlinks [RP 2.8.3]. The name is unchanged.
+func (b AS923) WithDwellTime() FrequencyPlan {
+	b.dwellTime = true
+	b.configuration.MaxDwellTime = 400 * time.Millisecond
+	b.DownstreamDataRates = as923DwellTimeDataRates
+	return b
+}
+
+// Name returns frequency band name.
+func (b AS923) Name() string {
+	return fmt.Sprintf("AS 923MHz ISM Band (group %d)", b.group)
+}
+
+// Group returns the AS923 group (1-4) for the band
+func (b AS923) Group() int {
+	return b.group
+}
+
+// Configuration returns parameters for the AS 923MHz ISM Band.
+func (b AS923) Configuration() *Configuration {
+	return &b.configuration
+}
+
+// TxPower returns power in dBm for the AS 923MHz ISM Band, given a TXPower
+// key. The power is relative to the default max EIRP of 16 dBm [RP 2.8.3]
+func (b AS923) TxPower(power uint8) (int8, error) {
+	if power > b.configuration.MaxTxPower {
+		return 0, fmt.Errorf("invalid power: %d", power)
+	}
+	return int8(b.configuration.DefaultTxPower) - 2*int8(power), nil
+}
+
+// Encoding returns a description of modulation, spread factor and bit rate for the AS 923MHz ISM Band, given a data rate. [RP 2.8.3]
+func (b AS923) Encoding(dataRate uint8) (Encoding, error) {
+	switch dataRate {
+	case 0:
+		return Encoding{Modulation: LoRa, SpreadFactor: 12, Bandwidth: 125, BitRate: 250}, nil
+	case 1:
+		return Encoding{Modulation: LoRa, SpreadFactor: 11, Bandwidth: 125, BitRate: 440}, nil
+	case 2:
+		return Encoding{Modulation: LoRa, SpreadFactor: 10, Bandwidth: 125, BitRate: 980}, nil
+	case 3:
+		return Encoding{Modulation: LoRa, SpreadFactor: 9, Bandwidth: 125, BitRate: 1760}, nil
+	case 4:
+		return Encoding{Modulation: LoRa, SpreadFactor: 8, Bandwidth: 125, BitRate: 3125}, nil
+	case 5:
+		return Encoding{Modulation: LoRa, SpreadFactor: 7, Bandwidth: 125, BitRate: 5470}, nil
+	case 6:
+		return Encoding{Modulation: LoRa, SpreadFactor: 7, Bandwidth: 250, BitRate: 11000}, nil
+	case 7:
+		return Encoding{Modulation: FSK, BitRate: 50000}, nil
+	default:
+		return Encoding{}, fmt.Errorf("unable to look up encoding. Invalid data rate :%d", dataRate)
+	}
+}
+
+// MaximumPayload return a maximum payload size, given a data rate. DR0 and DR1
+// can't be used when the dwell time limit applies. With the limit both sizes
+// are the max FRMPayload size (N) so the frame header fits within the dwell
+// time.
+// This implementation uses the repeater compatible definition in the LoRaWAN specification. [RP 2.8.6]
+func (b AS923) MaximumPayload(dataRate string) (MaximumPayloadSize, error) {
+	dr, err := b.GetDataRate(dataRate)
+	if err != nil {
+		return MaximumPayloadSize{}, err
+	}
+	if b.dwellTime {
+		switch dr {
+		case 2:
+			return MaximumPayloadSize{M: 11, N: 11}, nil
+		case 3:
+			return MaximumPayloadSize{M: 53, N: 53}, nil
+		case 4:
+			return MaximumPayloadSize{M: 125, N: 125}, nil
+		case 5, 6, 7:
+			return MaximumPayloadSize{M: 222, N: 222}, nil
+		default:
+			return MaximumPayloadSize{}, fmt.Errorf("data rate %s can't be used with the dwell time limit", dataRate)
+		}
+	}
+	switch dr {
+	case 0, 1, 2:
+		return MaximumPayloadSize{M: 59, N: 51}, nil
+	case 3:
+		return MaximumPayloadSize{M: 123, N: 115}, nil
+	case 4, 5, 6, 7:
+		return MaximumPayloadSize{M: 230, N: 222}, nil
+	default:
+		return MaximumPayloadSize{}, fmt.Errorf("unable to look up maximum payload. Invalid data rate :%s", dataRate)
+	}
+}
+
+// GetRX1Parameters returns datarate and frequency for downlink in receive window 1, given upstream data rate and RX1DROffset
+func (b AS923) GetRX1Parameters(channel uint8, upstreamFrequency float32, upstreamDataRate uint8, RX1DROffset uint8) (DownlinkParameters, error) {
+	datarate, err := rx1DataRate(b.DownstreamDataRates, upstreamDataRate, RX1DROffset)
+	return DownlinkParameters{DataRate: datarate, Frequency: upstreamFrequency}, err
+}
+
+// GetRX2Parameters returns datarate and frequency for downlink in receive window 2.
+func (b AS923) GetRX2Parameters() DownlinkParameters {
+	return DownlinkParameters{DataRate: b.configuration.RX2DataRate, Frequency: b.configuration.RX2Frequency}
+}
+
+// GetDataRate returns data rate, given gateway representation of configuration
+func (b AS923) GetDataRate(configuration string) (uint8, error) {
+	switch configuration {
+	case "SF12BW125":
+		return 0, nil
+	case "SF11BW125":
+		return 1, nil
+	case "SF10BW125":
+		return 2, nil
+	case "SF9BW125":
+		return 3, nil
+	case "SF8BW125":
+		return 4, nil
+	case "SF7BW125":
+		return 5, nil
+	case "SF7BW250":
+		return 6, nil
+	case "FSKBW500":
+		return 7, nil
+	default:
+		return 0, fmt.Errorf("unable to convert configuration '%s' into data rate", configuration)
+	}
+}
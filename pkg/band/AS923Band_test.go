@@ -0,0 +1,282 @@
+package band
+
+import "testing"
+
+func TestNameAS(t *testing.T) {
+	b := newAS923(1)
+	if b.Name() != "AS 923MHz ISM Band (group 1)" {
+		t.Error("Unexpected band name")
+	}
+}
+
+func TestDefaultConfigurationAS(t *testing.T) {
+	b := newAS923(1)
+
+	if b.Configuration().ReceiveDelay1 != 1 {
+		t.Errorf("Wrong default RECEIVE_DELAY1 for %s [RP 2.8.8]", b.Name())
+	}
+	if b.Configuration().ReceiveDelay2 != b.Configuration().ReceiveDelay1+1 {
+		t.Errorf("Wrong default RECEIVE_DELAY2 for %s [RP 2.8.8]", b.Name())
+	}
+	if b.Configuration().JoinAccepDelay1 != 5 {
+		t.Errorf("Wrong default JOIN_ACCEPT_DELAY1 for %s [RP 2.8.8]", b.Name())
+	}
+	if b.Configuration().JoinAccepDelay2 != 6 {
+		t.Errorf("Wrong default JOIN_ACCEPT_DELAY2 for %s [RP 2.8.8]", b.Name())
+	}
+	if b.Configuration().MaxFCntGap != 16384 {
+		t.Errorf("Wrong default MAX_FCNT_GAP for %s [RP 2.8.8]", b.Name())
+	}
+	if b.Configuration().AdrAckLimit != 64 {
+		t.Errorf("Wrong default ADR_ACK_LIMIT for %s [RP 2.8.8]", b.Name())
+	}
+	if b.Configuration().AdrAckDelay != 32 {
+		t.Errorf("Wrong default ADR_ACK_DELAY for %s [RP 2.8.8]", b.Name())
+	}
+	if b.Configuration().DefaultTxPower != 16 {
+		t.Errorf("Wrong default TXPower for %s [RP 2.8.3]", b.Name())
+	}
+	if !b.Configuration().SupportsJoinAcceptCFList {
+		t.Errorf("Wrong default value for SupportsJoinAcceptCFList for %s [RP 2.8.4]", b.Name())
+	}
+	if b.Configuration().RX2Frequency != 923.2 {
+		t.Errorf("Wrong default RX2Frequency for %s [RP 2.8.7]", b.Name())
+	}
+	if b.Configuration().RX2DataRate != 2 {
+		t.Errorf("Wrong default RX2DataRate for %s [RP 2.8.7]", b.Name())
+	}
+	expectedChannels := []float32{923.2, 923.4}
+	if len(b.Configuration().JoinReqChannels) != len(expectedChannels) {
+		t.Fatalf("Wrong number of join channels for %s [RP 2.8.2]", b.Name())
+	}
+	for i, f := range expectedChannels {
+		if b.Configuration().JoinReqChannels[i] != f {
+			t.Errorf("Wrong join channel %d (%f) for %s [RP 2.8.2]", i, b.Configuration().JoinReqChannels[i], b.Name())
+		}
+	}
+}
+
+func TestTxPowerAS(t *testing.T) {
+	b := newAS923(1)
+
+	expectedOutput := []int8{16, 14, 12, 10, 8, 6, 4, 2}
+	for i := 0; i < len(expectedOutput); i++ {
+		power, err := b.TxPower(uint8(i))
+		if err != nil {
+			t.Errorf("%s, %s [RP 2.8.3]", err, b.Name())
+		}
+		if power != expectedOutput[i] {
+			t.Errorf("Wrong TxPower configuration for %d, %s [RP 2.8.3]", power, b.Name())
+		}
+	}
+
+	if _, err := b.TxPower(8); err == nil {
+		t.Errorf("Invalid parameter should fail, %s [RP 2.8.3]", b.Name())
+	}
+}
+
+func TestEncodingAS(t *testing.T) {
+	b := newAS923(1)
+	testParams := []uint8{0, 1, 2, 3, 4, 5, 6, 7}
+	expectedModulations := []ModulationType{LoRa, LoRa, LoRa, LoRa, LoRa, LoRa, LoRa, FSK}
+	expectedSpreadFactors := []uint8{12, 11, 10, 9, 8, 7, 7, 0}
+	expectedBandwidths := []uint32{125, 125, 125, 125, 125, 125, 250, 0}
+	expectedBitrates := []uint32{250, 440, 980, 1760, 3125, 5470, 11000, 50000}
+
+	for i := 0; i < len(testParams); i++ {
+		encoding, err := b.Encoding(testParams[i])
+		if err != nil {
+			t.Errorf("%s, %s [RP 2.8.3]", err, b.Name())
+		}
+		if encoding.Modulation != expectedModulations[i] {
+			t.Errorf("Unexpected modulation (%v) for datarate (%d) %s [RP 2.8.3]", encoding.Modulation, testParams[i], b.Name())
+		}
+		if encoding.SpreadFactor != expectedSpreadFactors[i] {
+			t.Errorf("Unexpected spreadfactor (%v) for datarate (%d) %s [RP 2.8.3]", encoding.SpreadFactor, testParams[i], b.Name())
+		}
+		if encoding.Bandwidth != expectedBandwidths[i] {
+			t.Errorf("Unexpected bandwidth (%v) for datarate (%d) %s [RP 2.8.3]", encoding.Bandwidth, testParams[i], b.Name())
+		}
+		if encoding.BitRate != expectedBitrates[i] {
+			t.Errorf("Unexpected bit rate (%v) for datarate (%d) %s [RP 2.8.3]", encoding.BitRate, testParams[i], b.Name())
+		}
+	}
+
+	if _, err := b.Encoding(42); err == nil {
+		t.Errorf("Invalid parameter should fail, %s [RP 2.8.3]", b.Name())
+	}
+}
+
+func TestMaximumPayloadAS(t *testing.T) {
+	b := newAS923(1)
+	testParams := []string{"SF12BW125", "SF10BW125", "SF9BW125", "SF8BW125", "SF7BW250"}
+	expectedMs := []uint8{59, 59, 123, 230, 230}
+	expectedNs := []uint8{51, 51, 115, 222, 222}
+
+	for i := 0; i < len(testParams); i++ {
+		mp, err := b.MaximumPayload(testParams[i])
+		if err != nil {
+			t.Errorf("%s, %s [RP 2.8.6]", err, b.Name())
+		}
+		if mp.WithoutFOpts() != expectedMs[i] {
+			t.Errorf("Unexpected M (%d) for %s %s [RP 2.8.6]", mp.M, testParams[i], b.Name())
+		}
+		if mp.WithFOpts() != expectedNs[i] {
+			t.Errorf("Unexpected N (%d) for %s %s [RP 2.8.6]", mp.N, testParams[i], b.Name())
+		}
+	}
+
+	if _, err := b.MaximumPayload("SF19BW1"); err == nil {
+		t.Errorf("Invalid parameter should fail, %s [RP 2.8.6]", b.Name())
+	}
+}
+
+func TestDownlinkDataRatesAS(t *testing.T) {
+	expectedRates := [][]uint8{
+		{0, 0, 0, 0, 0, 0, 1, 2},
+		{1, 0, 0, 0, 0, 0, 2, 3},
+		{2, 1, 0, 0, 0, 0, 3, 4},
+		{3, 2, 1, 0, 0, 0, 4, 5},
+		{4, 3, 2, 1, 0, 0, 5, 5},
+		{5, 4, 3, 2, 1, 0, 5, 5},
+		{5, 5, 4, 3, 2, 1, 5, 5},
+		{5, 5, 5, 4, 3, 2, 5, 5},
+	}
+	b := newAS923(1)
+	for upstreamDataRate, rates := range expectedRates {
+		if rates == nil {
+			if _, err := b.GetRX1Parameters(0, b.Configuration().RX2Frequency, uint8(upstreamDataRate), 0); err == nil {
+				t.Errorf("Expected error for data rate %d, %s [RP 2.8.7]", upstreamDataRate, b.Name())
+			}
+			continue
+		}
+		for RX1DROffset, expected := range rates {
+			rate, err := rx1DataRate(b.DownstreamDataRates, uint8(upstreamDataRate), uint8(RX1DROffset))
+			if err != nil {
+				t.Errorf("%s, %s [RP 2.8.7]", err, b.Name())
+			}
+			if rate != expected {
+				t.Errorf("Unexpected downstream datarate (%d) for given upstream datarate/RX1DROffset (%d/%d) %s [RP 2.8.7]", rate, upstreamDataRate, RX1DROffset, b.Name())
+			}
+		}
+	}
+
+	if _, err := rx1DataRate(b.DownstreamDataRates, 99, 0); err == nil {
+		t.Errorf("Invalid parameter should fail, %s [RP 2.8.7]", b.Name())
+	}
+	if _, err := rx1DataRate(b.DownstreamDataRates, 0, 8); err == nil {
+		t.Errorf("Invalid parameter should fail, %s [RP 2.8.7]", b.Name())
+	}
+}
+
+func TestGetRX1ParametersAS(t *testing.T) {
+	b := newAS923(1)
+	testParams := []struct {
+		upstreamFrequency float32
+		upstreamDataRate  uint8
+		rx1DROffset       uint8
+		frequency         float32
+		dataRate          uint8
+	}{
+		{923.4, 5, 1, 923.4, 4},
+	}
+	for _, p := range testParams {
+		dlParams, err := b.GetRX1Parameters(0, p.upstreamFrequency, p.upstreamDataRate, p.rx1DROffset)
+		if err != nil {
+			t.Error(err)
+		}
+		if dlParams.DataRate != p.dataRate {
+			t.Errorf("Unexpected data rate for %f/DR%d: %d", p.upstreamFrequency, p.upstreamDataRate, dlParams.DataRate)
+		}
+		if dlParams.Frequency != p.frequency {
+			t.Errorf("Unexpected frequency for %f/DR%d: %f", p.upstreamFrequency, p.upstreamDataRate, dlParams.Frequency)
+		}
+	}
+
+	if _, err := b.GetRX1Parameters(0, 923.4, 30, 0); err == nil {
+		t.Errorf("Expected invalid data rate.")
+	}
+	if _, err := b.GetRX1Parameters(0, 923.4, 0, 30); err == nil {
+		t.Errorf("Expected invalid data rate offset.")
+	}
+}
+
+func TestGetDataRateAS(t *testing.T) {
+	b := newAS923(1)
+	for configuration, expected := range map[string]uint8{
+		"SF12BW125": 0,
+		"SF7BW125":  5,
+		"SF7BW250":  6,
+		"FSKBW500":  7,
+	} {
+		dr, err := b.GetDataRate(configuration)
+		if (dr != expected) || (err != nil) {
+			t.Errorf("Unexpected data rate or error in lookup of %s: %d. Error: %v", configuration, dr, err)
+		}
+	}
+
+	if _, err := b.GetDataRate("XYZZY"); err == nil {
+		t.Error("Expected lookup of XYZZY to fail")
+	}
+}
+
+func TestGroupsAS(t *testing.T) {
+	for group, freqs := range as923Frequencies {
+		b := newAS923(group)
+		if b.Group() != group {
+			t.Errorf("Unexpected group %d for %s", b.Group(), b.Name())
+		}
+		if b.Configuration().RX2Frequency != freqs[0] {
+			t.Errorf("Wrong RX2Frequency for %s [RP 2.8.7]", b.Name())
+		}
+		if len(b.Configuration().JoinReqChannels) != 2 ||
+			b.Configuration().JoinReqChannels[0] != freqs[0] ||
+			b.Configuration().JoinReqChannels[1] != freqs[1] {
+			t.Errorf("Wrong default channels for %s [RP 2.8.2]", b.Name())
+		}
+	}
+}
+
+func TestDwellTimeAS(t *testing.T) {
+	b := newAS923(2)
+	if b.DwellTime() {
+		t.Error("Dwell time should be off by default")
+	}
+	plan := b.WithDwellTime()
+	dwell, ok := plan.(DwellTimePlan)
+	if !ok || !dwell.DwellTime() {
+		t.Fatal("Expected the band to have the dwell time limit")
+	}
+	if plan.Name() != b.Name() {
+		t.Errorf("Name should be unchanged but got %s", plan.Name())
+	}
+	if b.Configuration().MaxDwellTime != 0 {
+		t.Error("The original band should not be changed")
+	}
+
+	for dataRate, expected := range map[string]uint8{"SF10BW125": 11, "SF9BW125": 53, "SF8BW125": 125, "SF7BW125": 222} {
+		mp, err := plan.MaximumPayload(dataRate)
+		if err != nil {
+			t.Errorf("%s, %s [RP 2.8.6]", err, b.Name())
+		}
+		if mp.WithoutFOpts() != expected || mp.WithFOpts() != expected {
+			t.Errorf("Unexpected M/N (%d/%d) for %s with dwell time [RP 2.8.6]", mp.M, mp.N, dataRate)
+		}
+		dr, _ := plan.GetDataRate(dataRate)
+		encoding, _ := plan.Encoding(dr)
+		if toa := encoding.TimeOnAir(int(expected) + 13); toa > plan.Configuration().MaxDwellTime {
+			t.Errorf("Max payload for %s exceeds the dwell time (%v)", dataRate, toa)
+		}
+	}
+	if _, err := plan.MaximumPayload("SF12BW125"); err == nil {
+		t.Error("DR0 should not be allowed with dwell time [RP 2.8.6]")
+	}
+
+	dl, err := plan.GetRX1Parameters(0, 923.4, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dl.DataRate != 2 {
+		t.Errorf("Expected DR2 for DR1 uplinks with dwell time but got DR%d [RP 2.8.7]", dl.DataRate)
+	}
+}
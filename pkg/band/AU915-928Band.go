@@ -0,0 +1,203 @@
+package band
+
+import "fmt"
+
+// AU915 represents configuration and frequency plan for the Australia 915-928MHz ISM Band.
+type AU915 struct {
+	configuration       Configuration
+	DownstreamDataRates [][]uint8
+	DownstreamChannels  []float32
+}
+
+func newAU915() AU915 {
+	return AU915{
+		configuration: Configuration{
+			ReceiveDelay1:            1,     // [RP 2.6.8]
+			ReceiveDelay2:            2,     // ReceiveDelay1 + 1 according to [RP 2.6.8]
+			JoinAccepDelay1:          5,     // [RP 2.6.8]
+			JoinAccepDelay2:          6,     // [RP 2.6.8]
+			MaxFCntGap:               16384, // [RP 2.6.8]
+			AdrAckLimit:              64,    // [RP 2.6.8]
+			AdrAckDelay:              32,    // [RP 2.6.8]
+			DefaultTxPower:           30,    // Default max EIRP [RP 2.6.3]
+			SupportsJoinAcceptCFList: false, // [RP 2.6.4]
+			MinFrequency:             915.0, // [RP 2.6.1]
+			MaxFrequency:             928.0, // [RP 2.6.1]
+			RX2Frequency:             923.3, // [RP 2.6.7]
+			RX2DataRate:              8,     // [RP 2.6.7]
+			MaxADRDataRate:           5,     // SF7BW125 is the fastest 125kHz rate
+			DownlinkOnlyDataRate:     8,     // DR8-13 are downlink only [RP 2.6.3]
+			MaxTxPower:               14,    // [RP 2.6.3]
+			DefaultChannelMask:       0xFF,  // First sub-band
+			BeaconDataRate:           8,     // [RP 2.6.9]
+			PingSlotDataRate:         8,     // [RP 2.6.9]
+			BeaconFrequencies: []float32{
+				923.3, 923.9, 924.5, 925.1, 925.7, 926.3, 926.9, 927.5}, // 923.3 + 0.6 * n [RP 2.6.9]
+			PingSlotFrequencies: []float32{
+				923.3, 923.9, 924.5, 925.1, 925.7, 926.3, 926.9, 927.5}, // [RP 2.6.9]
+			BeaconRFUSize: [2]int{5, 3}, // [RP 2.6.9]
+		},
+		DownstreamDataRates: [][]uint8{
+			{8, 8, 8, 8, 8, 8},      // DR0
+			{9, 8, 8, 8, 8, 8},      // DR1
+			{10, 9, 8, 8, 8, 8},     // DR2
+			{11, 10, 9, 8, 8, 8},    // DR3
+			{12, 11, 10, 9, 8, 8},   // DR4
+			{13, 12, 11, 10, 9, 8},  // DR5
+			{13, 13, 12, 11, 10, 9}, // DR6
+		},
+		DownstreamChannels: []float32{
+			923.3,
+			923.9,
+			924.5,
+			925.1,
+			925.7,
+			926.3,
+			926.9,
+			927.5,
+		},
+	}
+}
+
+// Name returns frequency band name.
+func (b AU915) Name() string {
+	return "AU 915-928MHz ISM Band"
+}
+
+// UplinkChannel returns the uplink channel for a frequency. Channel 0-63 are
+// the 125 kHz channels starting at 915.2 MHz and channel 64-71 are the 500 kHz
+// channels starting at 915.9 MHz [RP 2.6.2]
+func (b AU915) UplinkChannel(frequency float32) (int, error) {
+	return fixedUplinkChannel(frequency, 915.2, 915.9)
+}
+
+// Configuration returns parameters for the AU 915-928MHz ISM Band.
+func (b AU915) Configuration() *Configuration {
+	return &b.configuration
+}
+
+// TxPower returns power in dBm for the AU 915-928MHz ISM Band, given a TXPower
+// key. The power is relative to the default max EIRP of 30 dBm [RP 2.6.3]
+func (b AU915) TxPower(power uint8) (int8, error) {
+	if power > b.configuration.MaxTxPower {
+		return 0, fmt.Errorf("invalid power: %d", power)
+	}
+	return int8(b.configuration.DefaultTxPower) - 2*int8(power), nil
+}
+
+// Encoding returns a description of modulation, spread factor and bit rate for the AU 915-928MHz ISM Band, given a data rate. [RP 2.6.3]
+func (b AU915) Encoding(dataRate uint8) (Encoding, error) {
+	switch dataRate {
+	case 0:
+		return Encoding{Modulation: LoRa, SpreadFactor: 12, Bandwidth: 125, BitRate: 250}, nil
+	case 1:
+		return Encoding{Modulation: LoRa, SpreadFactor: 11, Bandwidth: 125, BitRate: 440}, nil
+	case 2:
+		return Encoding{Modulation: LoRa, SpreadFactor: 10, Bandwidth: 125, BitRate: 980}, nil
+	case 3:
+		return Encoding{Modulation: LoRa, SpreadFactor: 9, Bandwidth: 125, BitRate: 1760}, nil
+	case 4:
+		return Encoding{Modulation: LoRa, SpreadFactor: 8, Bandwidth: 125, BitRate: 3125}, nil
+	case 5:
+		return Encoding{Modulation: LoRa, SpreadFactor: 7, Bandwidth: 125, BitRate: 5470}, nil
+	case 6:
+		return Encoding{Modulation: LoRa, SpreadFactor: 8, Bandwidth: 500, BitRate: 12500}, nil
+	case 8:
+		return Encoding{Modulation: LoRa, SpreadFactor: 12, Bandwidth: 500, BitRate: 980}, nil
+	case 9:
+		return Encoding{Modulation: LoRa, SpreadFactor: 11, Bandwidth: 500, BitRate: 1760}, nil
+	case 10:
+		return Encoding{Modulation: LoRa, SpreadFactor: 10, Bandwidth: 500, BitRate: 3900}, nil
+	case 11:
+		return Encoding{Modulation: LoRa, SpreadFactor: 9, Bandwidth: 500, BitRate: 7000}, nil
+	case 12:
+		return Encoding{Modulation: LoRa, SpreadFactor: 8, Bandwidth: 500, BitRate: 12500}, nil
+	case 13:
+		return Encoding{Modulation: LoRa, SpreadFactor: 7, Bandwidth: 500, BitRate: 21900}, nil
+	default:
+		return Encoding{}, fmt.Errorf("unable to look up encoding. Invalid data rate: %d (Datarate 7 is RFU)", dataRate)
+	}
+}
+
+// MaximumPayload return a maximum payload size, given a data rate. The sizes
+// assume that there are no dwell time limits.
+// This implementation uses the repeater compatible definition in the LoRaWAN specification. [RP 2.6.6]
+func (b AU915) MaximumPayload(dataRate string) (MaximumPayloadSize, error) {
+	dr, err := b.GetDataRate(dataRate)
+	if err != nil {
+		return MaximumPayloadSize{}, err
+	}
+	switch dr {
+	case 0, 1, 2:
+		return MaximumPayloadSize{M: 59, N: 51}, nil
+	case 3:
+		return MaximumPayloadSize{M: 123, N: 115}, nil
+	case 4, 5, 6:
+		return MaximumPayloadSize{M: 230, N: 222}, nil
+	case 8:
+		return MaximumPayloadSize{M: 41, N: 33}, nil
+	case 9:
+		return MaximumPayloadSize{M: 117, N: 109}, nil
+	case 10, 11, 12, 13:
+		return MaximumPayloadSize{M: 230, N: 222}, nil
+	default:
+		return MaximumPayloadSize{}, fmt.Errorf("unable to look up maximum payload. Invalid data rate:%s (Datarate 7 is RFU)", dataRate)
+	}
+}
+
+// GetRX1Parameters returns datarate and frequency for downlink in receive
+// window 1, given upstream data rate and RX1DROffset. The downlink channel is
+// the upstream channel modulo 8 [RP 2.6.7]
+func (b AU915) GetRX1Parameters(channel uint8, upstreamFrequency float32, upstreamDataRate uint8, RX1DROffset uint8) (DownlinkParameters, error) {
+	datarate, err := rx1DataRate(b.DownstreamDataRates, upstreamDataRate, RX1DROffset)
+	if err != nil {
+		return DownlinkParameters{}, err
+	}
+	// 64 125kHz channels starting at 915.2MHz followed by 8 500kHz channels starting at 915.9MHz
+	upstreamChannel := channelIndex(upstreamFrequency, 915.2, 0.2)
+	if upstreamDataRate == 6 {
+		upstreamChannel = 64 + channelIndex(upstreamFrequency, 915.9, 1.6)
+	}
+	if upstreamChannel < 0 || upstreamChannel > 71 {
+		return DownlinkParameters{}, fmt.Errorf("invalid upstream frequency: %f", upstreamFrequency)
+	}
+	return DownlinkParameters{DataRate: datarate, Frequency: b.DownstreamChannels[upstreamChannel%8]}, nil
+}
+
+// GetRX2Parameters returns datarate and frequency for downlink in receive window 2.
+func (b AU915) GetRX2Parameters() DownlinkParameters {
+	return DownlinkParameters{DataRate: b.configuration.RX2DataRate, Frequency: b.configuration.RX2Frequency}
+}
+
+// GetDataRate returns data rate, given gateway representation of configuration
+// (DR6 is identical to DR12. Defaulting to DR6)
+func (b AU915) GetDataRate(configuration string) (uint8, error) {
+	switch configuration {
+	case "SF12BW125":
+		return 0, nil
+	case "SF11BW125":
+		return 1, nil
+	case "SF10BW125":
+		return 2, nil
+	case "SF9BW125":
+		return 3, nil
+	case "SF8BW125":
+		return 4, nil
+	case "SF7BW125":
+		return 5, nil
+	case "SF8BW500":
+		return 6, nil
+	case "SF12BW500":
+		return 8, nil
+	case "SF11BW500":
+		return 9, nil
+	case "SF10BW500":
+		return 10, nil
+	case "SF9BW500":
+		return 11, nil
+	case "SF7BW500":
+		return 13, nil
+	default:
+		return 0, fmt.Errorf("unknown configuration: %s", configuration)
+	}
+}
@@ -0,0 +1,171 @@
+package band
+
+import "fmt"
+
+// CN470 represents configuration and frequency plan for the China 470-510MHz Band.
+type CN470 struct {
+	configuration       Configuration
+	DownstreamDataRates [][]uint8
+}
+
+func newCN470() CN470 {
+	return CN470{
+		configuration: Configuration{
+			ReceiveDelay1:            1,     // [RP 2.7.8]
+			ReceiveDelay2:            2,     // ReceiveDelay1 + 1 according to [RP 2.7.8]
+			JoinAccepDelay1:          5,     // [RP 2.7.8]
+			JoinAccepDelay2:          6,     // [RP 2.7.8]
+			MaxFCntGap:               16384, // [RP 2.7.8]
+			AdrAckLimit:              64,    // [RP 2.7.8]
+			AdrAckDelay:              32,    // [RP 2.7.8]
+			DefaultTxPower:           14,    // [RP 2.7.3]
+			SupportsJoinAcceptCFList: false, // [RP 2.7.4]
+			MinFrequency:             470.0, // [RP 2.7.1]
+			MaxFrequency:             510.0, // [RP 2.7.1]
+			RX2Frequency:             505.3, // [RP 2.7.7]
+			RX2DataRate:              0,     // [RP 2.7.7]
+			MaxADRDataRate:           5,     // SF7BW125 is the fastest rate
+			MaxTxPower:               7,     // [RP 2.7.3]
+			DefaultChannelMask:       0xFF,  // The first eight channels
+			BeaconDataRate:           2,     // [RP 2.7.9]
+			PingSlotDataRate:         2,     // [RP 2.7.9]
+			BeaconFrequencies: []float32{
+				508.3, 508.5, 508.7, 508.9, 509.1, 509.3, 509.5, 509.7}, // 508.3 + 0.2 * n [RP 2.7.9]
+			PingSlotFrequencies: []float32{
+				508.3, 508.5, 508.7, 508.9, 509.1, 509.3, 509.5, 509.7}, // [RP 2.7.9]
+			BeaconRFUSize: [2]int{3, 1}, // [RP 2.7.9]
+		},
+		DownstreamDataRates: [][]uint8{
+			{0, 0, 0, 0, 0, 0},
+			{1, 0, 0, 0, 0, 0},
+			{2, 1, 0, 0, 0, 0},
+			{3, 2, 1, 0, 0, 0},
+			{4, 3, 2, 1, 0, 0},
+			{5, 4, 3, 2, 1, 0},
+		},
+	}
+}
+
+// Name returns frequency band name.
+func (b CN470) Name() string {
+	return "CN 470-510MHz Band"
+}
+
+// Configuration returns parameters for the CN 470-510MHz Band.
+func (b CN470) Configuration() *Configuration {
+	return &b.configuration
+}
+
+// TxPower returns power in dBm for the CN 470-510MHz Band, given a TXPower key [RP 2.7.3]
+func (b CN470) TxPower(power uint8) (int8, error) {
+	switch power {
+	case 0:
+		return 17, nil
+	case 1:
+		return 16, nil
+	case 2:
+		return 14, nil
+	case 3:
+		return 12, nil
+	case 4:
+		return 10, nil
+	case 5:
+		return 7, nil
+	case 6:
+		return 5, nil
+	case 7:
+		return 2, nil
+	default:
+		return 0, fmt.Errorf("invalid power: %d", power)
+	}
+}
+
+// Encoding returns a description of modulation, spread factor and bit rate for the CN 470-510MHz Band, given a data rate. [RP 2.7.3]
+func (b CN470) Encoding(dataRate uint8) (Encoding, error) {
+	switch dataRate {
+	case 0:
+		return Encoding{Modulation: LoRa, SpreadFactor: 12, Bandwidth: 125, BitRate: 250}, nil
+	case 1:
+		return Encoding{Modulation: LoRa, SpreadFactor: 11, Bandwidth: 125, BitRate: 440}, nil
+	case 2:
+		return Encoding{Modulation: LoRa, SpreadFactor: 10, Bandwidth: 125, BitRate: 980}, nil
+	case 3:
+		return Encoding{Modulation: LoRa, SpreadFactor: 9, Bandwidth: 125, BitRate: 1760}, nil
+	case 4:
+		return Encoding{Modulation: LoRa, SpreadFactor: 8, Bandwidth: 125, BitRate: 3125}, nil
+	case 5:
+		return Encoding{Modulation: LoRa, SpreadFactor: 7, Bandwidth: 125, BitRate: 5470}, nil
+	default:
+		return Encoding{}, fmt.Errorf("unable to look up encoding. Invalid data rate :%d", dataRate)
+	}
+}
+
+// MaximumPayload return a maximum payload size, given a data rate.
+// This implementation uses the repeater compatible definition in the LoRaWAN specification. [RP 2.7.6]
+func (b CN470) MaximumPayload(dataRate string) (MaximumPayloadSize, error) {
+	dr, err := b.GetDataRate(dataRate)
+	if err != nil {
+		return MaximumPayloadSize{}, err
+	}
+	switch dr {
+	case 0, 1, 2:
+		return MaximumPayloadSize{M: 59, N: 51}, nil
+	case 3:
+		return MaximumPayloadSize{M: 123, N: 115}, nil
+	case 4, 5:
+		return MaximumPayloadSize{M: 230, N: 222}, nil
+	default:
+		return MaximumPayloadSize{}, fmt.Errorf("unable to look up maximum payload. Invalid data rate :%s", dataRate)
+	}
+}
+
+// uplinkChannel returns the uplink channel for a frequency. There are 96
+// uplink channels starting at 470.3MHz [RP 2.7.2]
+func (b CN470) uplinkChannel(frequency float32) (int, error) {
+	ch := channelIndex(frequency, 470.3, 0.2)
+	if ch < 0 || ch > 95 {
+		return 0, fmt.Errorf("invalid upstream frequency: %f", frequency)
+	}
+	return ch, nil
+}
+
+// GetRX1Parameters returns datarate and frequency for downlink in receive
+// window 1, given upstream data rate and RX1DROffset. The downlink channel is
+// the upstream channel modulo 48 [RP 2.7.7]
+func (b CN470) GetRX1Parameters(channel uint8, upstreamFrequency float32, upstreamDataRate uint8, RX1DROffset uint8) (DownlinkParameters, error) {
+	datarate, err := rx1DataRate(b.DownstreamDataRates, upstreamDataRate, RX1DROffset)
+	if err != nil {
+		return DownlinkParameters{}, err
+	}
+	upstreamChannel, err := b.uplinkChannel(upstreamFrequency)
+	if err != nil {
+		return DownlinkParameters{}, err
+	}
+	// 48 downlink channels starting at 500.3MHz
+	return DownlinkParameters{DataRate: datarate, Frequency: channelFrequency(500.3, 0.2, upstreamChannel%48)}, nil
+}
+
+// GetRX2Parameters returns datarate and frequency for downlink in receive window 2.
+func (b CN470) GetRX2Parameters() DownlinkParameters {
+	return DownlinkParameters{DataRate: b.configuration.RX2DataRate, Frequency: b.configuration.RX2Frequency}
+}
+
+// GetDataRate returns data rate, given gateway representation of configuration
+func (b CN470) GetDataRate(configuration string) (uint8, error) {
+	switch configuration {
+	case "SF12BW125":
+		return 0, nil
+	case "SF11BW125":
+		return 1, nil
+	case "SF10BW125":
+		return 2, nil
+	case "SF9BW125":
+		return 3, nil
+	case "SF8BW125":
+		return 4, nil
+	case "SF7BW125":
+		return 5, nil
+	default:
+		return 0, fmt.Errorf("unable to convert configuration '%s' into data rate", configuration)
+	}
+}
@@ -0,0 +1,212 @@
+package band
+
+import "testing"
+
+func TestNameCN(t *testing.T) {
+	b := newCN470()
+	if b.Name() != "CN 470-510MHz Band" {
+		t.Error("Unexpected band name")
+	}
+}
+
+func TestDefaultConfigurationCN(t *testing.T) {
+	b := newCN470()
+
+	if b.Configuration().ReceiveDelay1 != 1 {
+		t.Errorf("Wrong default RECEIVE_DELAY1 for %s [RP 2.7.8]", b.Name())
+	}
+	if b.Configuration().ReceiveDelay2 != b.Configuration().ReceiveDelay1+1 {
+		t.Errorf("Wrong default RECEIVE_DELAY2 for %s [RP 2.7.8]", b.Name())
+	}
+	if b.Configuration().JoinAccepDelay1 != 5 {
+		t.Errorf("Wrong default JOIN_ACCEPT_DELAY1 for %s [RP 2.7.8]", b.Name())
+	}
+	if b.Configuration().JoinAccepDelay2 != 6 {
+		t.Errorf("Wrong default JOIN_ACCEPT_DELAY2 for %s [RP 2.7.8]", b.Name())
+	}
+	if b.Configuration().MaxFCntGap != 16384 {
+		t.Errorf("Wrong default MAX_FCNT_GAP for %s [RP 2.7.8]", b.Name())
+	}
+	if b.Configuration().AdrAckLimit != 64 {
+		t.Errorf("Wrong default ADR_ACK_LIMIT for %s [RP 2.7.8]", b.Name())
+	}
+	if b.Configuration().AdrAckDelay != 32 {
+		t.Errorf("Wrong default ADR_ACK_DELAY for %s [RP 2.7.8]", b.Name())
+	}
+	if b.Configuration().DefaultTxPower != 14 {
+		t.Errorf("Wrong default TXPower for %s [RP 2.7.3]", b.Name())
+	}
+	if b.Configuration().SupportsJoinAcceptCFList {
+		t.Errorf("Wrong default value for SupportsJoinAcceptCFList for %s [RP 2.7.4]", b.Name())
+	}
+	if b.Configuration().RX2Frequency != 505.3 {
+		t.Errorf("Wrong default RX2Frequency for %s [RP 2.7.7]", b.Name())
+	}
+	if b.Configuration().RX2DataRate != 0 {
+		t.Errorf("Wrong default RX2DataRate for %s [RP 2.7.7]", b.Name())
+	}
+}
+
+func TestTxPowerCN(t *testing.T) {
+	b := newCN470()
+
+	expectedOutput := []int8{17, 16, 14, 12, 10, 7, 5, 2}
+	for i := 0; i < len(expectedOutput); i++ {
+		power, err := b.TxPower(uint8(i))
+		if err != nil {
+			t.Errorf("%s, %s [RP 2.7.3]", err, b.Name())
+		}
+		if power != expectedOutput[i] {
+			t.Errorf("Wrong TxPower configuration for %d, %s [RP 2.7.3]", power, b.Name())
+		}
+	}
+
+	if _, err := b.TxPower(8); err == nil {
+		t.Errorf("Invalid parameter should fail, %s [RP 2.7.3]", b.Name())
+	}
+}
+
+func TestEncodingCN(t *testing.T) {
+	b := newCN470()
+	testParams := []uint8{0, 1, 2, 3, 4, 5}
+	expectedModulations := []ModulationType{LoRa, LoRa, LoRa, LoRa, LoRa, LoRa}
+	expectedSpreadFactors := []uint8{12, 11, 10, 9, 8, 7}
+	expectedBandwidths := []uint32{125, 125, 125, 125, 125, 125}
+	expectedBitrates := []uint32{250, 440, 980, 1760, 3125, 5470}
+
+	for i := 0; i < len(testParams); i++ {
+		encoding, err := b.Encoding(testParams[i])
+		if err != nil {
+			t.Errorf("%s, %s [RP 2.7.3]", err, b.Name())
+		}
+		if encoding.Modulation != expectedModulations[i] {
+			t.Errorf("Unexpected modulation (%v) for datarate (%d) %s [RP 2.7.3]", encoding.Modulation, testParams[i], b.Name())
+		}
+		if encoding.SpreadFactor != expectedSpreadFactors[i] {
+			t.Errorf("Unexpected spreadfactor (%v) for datarate (%d) %s [RP 2.7.3]", encoding.SpreadFactor, testParams[i], b.Name())
+		}
+		if encoding.Bandwidth != expectedBandwidths[i] {
+			t.Errorf("Unexpected bandwidth (%v) for datarate (%d) %s [RP 2.7.3]", encoding.Bandwidth, testParams[i], b.Name())
+		}
+		if encoding.BitRate != expectedBitrates[i] {
+			t.Errorf("Unexpected bit rate (%v) for datarate (%d) %s [RP 2.7.3]", encoding.BitRate, testParams[i], b.Name())
+		}
+	}
+
+	if _, err := b.Encoding(42); err == nil {
+		t.Errorf("Invalid parameter should fail, %s [RP 2.7.3]", b.Name())
+	}
+}
+
+func TestMaximumPayloadCN(t *testing.T) {
+	b := newCN470()
+	testParams := []string{"SF12BW125", "SF10BW125", "SF9BW125", "SF8BW125", "SF7BW125"}
+	expectedMs := []uint8{59, 59, 123, 230, 230}
+	expectedNs := []uint8{51, 51, 115, 222, 222}
+
+	for i := 0; i < len(testParams); i++ {
+		mp, err := b.MaximumPayload(testParams[i])
+		if err != nil {
+			t.Errorf("%s, %s [RP 2.7.6]", err, b.Name())
+		}
+		if mp.WithoutFOpts() != expectedMs[i] {
+			t.Errorf("Unexpected M (%d) for %s %s [RP 2.7.6]", mp.M, testParams[i], b.Name())
+		}
+		if mp.WithFOpts() != expectedNs[i] {
+			t.Errorf("Unexpected N (%d) for %s %s [RP 2.7.6]", mp.N, testParams[i], b.Name())
+		}
+	}
+
+	if _, err := b.MaximumPayload("SF19BW1"); err == nil {
+		t.Errorf("Invalid parameter should fail, %s [RP 2.7.6]", b.Name())
+	}
+}
+
+func TestDownlinkDataRatesCN(t *testing.T) {
+	expectedRates := [][]uint8{
+		{0, 0, 0, 0, 0, 0},
+		{1, 0, 0, 0, 0, 0},
+		{2, 1, 0, 0, 0, 0},
+		{3, 2, 1, 0, 0, 0},
+		{4, 3, 2, 1, 0, 0},
+		{5, 4, 3, 2, 1, 0},
+	}
+	b := newCN470()
+	for upstreamDataRate, rates := range expectedRates {
+		if rates == nil {
+			if _, err := b.GetRX1Parameters(0, b.Configuration().RX2Frequency, uint8(upstreamDataRate), 0); err == nil {
+				t.Errorf("Expected error for data rate %d, %s [RP 2.7.7]", upstreamDataRate, b.Name())
+			}
+			continue
+		}
+		for RX1DROffset, expected := range rates {
+			rate, err := rx1DataRate(b.DownstreamDataRates, uint8(upstreamDataRate), uint8(RX1DROffset))
+			if err != nil {
+				t.Errorf("%s, %s [RP 2.7.7]", err, b.Name())
+			}
+			if rate != expected {
+				t.Errorf("Unexpected downstream datarate (%d) for given upstream datarate/RX1DROffset (%d/%d) %s [RP 2.7.7]", rate, upstreamDataRate, RX1DROffset, b.Name())
+			}
+		}
+	}
+
+	if _, err := rx1DataRate(b.DownstreamDataRates, 99, 0); err == nil {
+		t.Errorf("Invalid parameter should fail, %s [RP 2.7.7]", b.Name())
+	}
+	if _, err := rx1DataRate(b.DownstreamDataRates, 0, 6); err == nil {
+		t.Errorf("Invalid parameter should fail, %s [RP 2.7.7]", b.Name())
+	}
+}
+
+func TestGetRX1ParametersCN(t *testing.T) {
+	b := newCN470()
+	testParams := []struct {
+		upstreamFrequency float32
+		upstreamDataRate  uint8
+		rx1DROffset       uint8
+		frequency         float32
+		dataRate          uint8
+	}{
+		{470.3, 5, 0, 500.3, 5},
+		{479.9, 3, 1, 500.3, 2},
+		{480.1, 4, 4, 500.5, 0},
+		{489.3, 0, 0, 509.7, 0},
+	}
+	for _, p := range testParams {
+		dlParams, err := b.GetRX1Parameters(0, p.upstreamFrequency, p.upstreamDataRate, p.rx1DROffset)
+		if err != nil {
+			t.Error(err)
+		}
+		if dlParams.DataRate != p.dataRate {
+			t.Errorf("Unexpected data rate for %f/DR%d: %d", p.upstreamFrequency, p.upstreamDataRate, dlParams.DataRate)
+		}
+		if dlParams.Frequency != p.frequency {
+			t.Errorf("Unexpected frequency for %f/DR%d: %f", p.upstreamFrequency, p.upstreamDataRate, dlParams.Frequency)
+		}
+	}
+
+	if _, err := b.GetRX1Parameters(0, 470.3, 30, 0); err == nil {
+		t.Errorf("Expected invalid data rate.")
+	}
+	if _, err := b.GetRX1Parameters(0, 470.3, 0, 30); err == nil {
+		t.Errorf("Expected invalid data rate offset.")
+	}
+}
+
+func TestGetDataRateCN(t *testing.T) {
+	b := newCN470()
+	for configuration, expected := range map[string]uint8{
+		"SF12BW125": 0,
+		"SF9BW125":  3,
+		"SF7BW125":  5,
+	} {
+		dr, err := b.GetDataRate(configuration)
+		if (dr != expected) || (err != nil) {
+			t.Errorf("Unexpected data rate or error in lookup of %s: %d. Error: %v", configuration, dr, err)
+		}
+	}
+
+	if _, err := b.GetDataRate("XYZZY"); err == nil {
+		t.Error("Expected lookup of XYZZY to fail")
+	}
+}
@@ -0,0 +1,166 @@
+package band
+
+import "fmt"
+
+// EU433 represents configuration and frequency plan for the EU 433MHz ISM Band.
+type EU433 struct {
+	configuration       Configuration
+	DownstreamDataRates [][]uint8
+}
+
+func newEU433() EU433 {
+	return EU433{
+		configuration: Configuration{
+			ReceiveDelay1:            1,       // [7.4.8]
+			ReceiveDelay2:            2,       // ReceiveDelay1 + 1 according to [7.4.8]
+			JoinAccepDelay1:          5,       // [7.4.8]
+			JoinAccepDelay2:          6,       // [7.4.8]
+			MaxFCntGap:               16384,   // [7.4.8]
+			AdrAckLimit:              64,      // [7.4.8]
+			AdrAckDelay:              32,      // [7.4.8]
+			DefaultTxPower:           10,      // [7.4.2]
+			SupportsJoinAcceptCFList: true,    // [7.4.4]
+			MinFrequency:             433.175, // [7.4.1]
+			MaxFrequency:             434.665, // [7.4.1]
+			RX2Frequency:             434.665, // [7.4.7]
+			RX2DataRate:              0,       // [7.4.7]
+			MaxADRDataRate:           5,       // SF7BW125 is the fastest rate on all channels
+			MaxTxPower:               5,       // [7.4.3]
+			DefaultChannelMask:       0x0007,  // The three mandatory channels
+			BeaconDataRate:           3,       // [7.4.9]
+			PingSlotDataRate:         3,       // [7.4.9]
+			MandatoryEndDeviceChannels: []float32{
+				433.175,
+				433.375,
+				433.575}, // [7.4.2]
+			JoinReqChannels: []float32{
+				433.175,
+				433.375,
+				433.575}, // [7.4.2]
+			BeaconFrequencies:   []float32{434.665}, // [7.4.9]
+			PingSlotFrequencies: []float32{434.665}, // [7.4.9]
+			BeaconRFUSize:       [2]int{2, 0},       // [7.4.9]
+			SubBands: []SubBand{
+				{MinFrequency: 433.05, MaxFrequency: 434.79, DutyCycle: 0.1},
+			}, // ETSI EN 300 220
+		},
+		DownstreamDataRates: [][]uint8{
+			{0, 0, 0, 0, 0, 0},
+			{1, 0, 0, 0, 0, 0},
+			{2, 1, 0, 0, 0, 0},
+			{3, 2, 1, 0, 0, 0},
+			{4, 3, 2, 1, 0, 0},
+			{5, 4, 3, 2, 1, 0},
+			{6, 5, 4, 3, 2, 1},
+			{7, 6, 5, 4, 3, 2},
+		},
+	}
+}
+
+// Name returns frequency band name.
+func (b EU433) Name() string {
+	return "EU 433MHz ISM Band"
+}
+
+// Configuration returns parameters for the EU 433MHz ISM Band.
+func (b EU433) Configuration() *Configuration {
+	return &b.configuration
+}
+
+// TxPower returns power in dBm for the EU 433MHz ISM Band, given a TXPower key [7.4.3]
+func (b EU433) TxPower(power uint8) (int8, error) {
+	switch power {
+	case 0:
+		return 10, nil
+	case 1:
+		return 7, nil
+	case 2:
+		return 4, nil
+	case 3:
+		return 1, nil
+	case 4:
+		return -2, nil
+	case 5:
+		return -5, nil
+	default:
+		return 0, fmt.Errorf("invalid power: %d", power)
+	}
+}
+
+// Encoding returns a description of modulation, spread factor and bit rate for the EU 433MHz ISM Band, given a data rate. [7.4.3]
+func (b EU433) Encoding(dataRate uint8) (Encoding, error) {
+	switch dataRate {
+	case 0:
+		return Encoding{Modulation: LoRa, SpreadFactor: 12, Bandwidth: 125, BitRate: 250}, nil
+	case 1:
+		return Encoding{Modulation: LoRa, SpreadFactor: 11, Bandwidth: 125, BitRate: 440}, nil
+	case 2:
+		return Encoding{Modulation: LoRa, SpreadFactor: 10, Bandwidth: 125, BitRate: 980}, nil
+	case 3:
+		return Encoding{Modulation: LoRa, SpreadFactor: 9, Bandwidth: 125, BitRate: 1760}, nil
+	case 4:
+		return Encoding{Modulation: LoRa, SpreadFactor: 8, Bandwidth: 125, BitRate: 3125}, nil
+	case 5:
+		return Encoding{Modulation: LoRa, SpreadFactor: 7, Bandwidth: 125, BitRate: 5470}, nil
+	case 6:
+		return Encoding{Modulation: LoRa, SpreadFactor: 7, Bandwidth: 250, BitRate: 11000}, nil
+	case 7:
+		return Encoding{Modulation: FSK, BitRate: 50000}, nil
+	default:
+		return Encoding{}, fmt.Errorf("unable to look up encoding. Invalid data rate :%d", dataRate)
+	}
+}
+
+// MaximumPayload return a maximum payload size, given a data rate.
+// This implementation uses the repeater compatible definition in the LoRaWAN specification. [7.4.6]
+func (b EU433) MaximumPayload(dataRate string) (MaximumPayloadSize, error) {
+	dr, err := b.GetDataRate(dataRate)
+	if err != nil {
+		return MaximumPayloadSize{}, err
+	}
+	switch dr {
+	case 0, 1, 2:
+		return MaximumPayloadSize{M: 59, N: 51}, nil
+	case 3:
+		return MaximumPayloadSize{M: 123, N: 115}, nil
+	case 4, 5, 6, 7:
+		return MaximumPayloadSize{M: 230, N: 222}, nil
+	default:
+		return MaximumPayloadSize{}, fmt.Errorf("unable to look up maximum payload. Invalid data rate :%s", dataRate)
+	}
+}
+
+// GetRX1Parameters returns datarate and frequency for downlink in receive window 1, given upstream data rate and RX1DROffset
+func (b EU433) GetRX1Parameters(channel uint8, upstreamFrequency float32, upstreamDataRate uint8, RX1DROffset uint8) (DownlinkParameters, error) {
+	datarate, err := rx1DataRate(b.DownstreamDataRates, upstreamDataRate, RX1DROffset)
+	return DownlinkParameters{DataRate: datarate, Frequency: upstreamFrequency}, err
+}
+
+// GetRX2Parameters returns datarate and frequency for downlink in receive window 2.
+func (b EU433) GetRX2Parameters() DownlinkParameters {
+	return DownlinkParameters{DataRate: b.configuration.RX2DataRate, Frequency: b.configuration.RX2Frequency}
+}
+
+// GetDataRate returns data rate, given gateway representation of configuration
+func (b EU433) GetDataRate(configuration string) (uint8, error) {
+	switch configuration {
+	case "SF12BW125":
+		return 0, nil
+	case "SF11BW125":
+		return 1, nil
+	case "SF10BW125":
+		return 2, nil
+	case "SF9BW125":
+		return 3, nil
+	case "SF8BW125":
+		return 4, nil
+	case "SF7BW125":
+		return 5, nil
+	case "SF7BW250":
+		return 6, nil
+	case "FSKBW500":
+		return 7, nil
+	default:
+		return 0, fmt.Errorf("unable to convert configuration '%s' into data rate", configuration)
+	}
+}
@@ -0,0 +1,221 @@
+package band
+
+import "testing"
+
+func TestNameEU433(t *testing.T) {
+	b := newEU433()
+	if b.Name() != "EU 433MHz ISM Band" {
+		t.Error("Unexpected band name")
+	}
+}
+
+func TestDefaultConfigurationEU433(t *testing.T) {
+	b := newEU433()
+
+	if b.Configuration().ReceiveDelay1 != 1 {
+		t.Errorf("Wrong default RECEIVE_DELAY1 for %s [7.4.8]", b.Name())
+	}
+	if b.Configuration().ReceiveDelay2 != b.Configuration().ReceiveDelay1+1 {
+		t.Errorf("Wrong default RECEIVE_DELAY2 for %s [7.4.8]", b.Name())
+	}
+	if b.Configuration().JoinAccepDelay1 != 5 {
+		t.Errorf("Wrong default JOIN_ACCEPT_DELAY1 for %s [7.4.8]", b.Name())
+	}
+	if b.Configuration().JoinAccepDelay2 != 6 {
+		t.Errorf("Wrong default JOIN_ACCEPT_DELAY2 for %s [7.4.8]", b.Name())
+	}
+	if b.Configuration().MaxFCntGap != 16384 {
+		t.Errorf("Wrong default MAX_FCNT_GAP for %s [7.4.8]", b.Name())
+	}
+	if b.Configuration().AdrAckLimit != 64 {
+		t.Errorf("Wrong default ADR_ACK_LIMIT for %s [7.4.8]", b.Name())
+	}
+	if b.Configuration().AdrAckDelay != 32 {
+		t.Errorf("Wrong default ADR_ACK_DELAY for %s [7.4.8]", b.Name())
+	}
+	if b.Configuration().DefaultTxPower != 10 {
+		t.Errorf("Wrong default TXPower for %s [7.4.3]", b.Name())
+	}
+	if !b.Configuration().SupportsJoinAcceptCFList {
+		t.Errorf("Wrong default value for SupportsJoinAcceptCFList for %s [7.4.4]", b.Name())
+	}
+	if b.Configuration().RX2Frequency != 434.665 {
+		t.Errorf("Wrong default RX2Frequency for %s [7.4.7]", b.Name())
+	}
+	if b.Configuration().RX2DataRate != 0 {
+		t.Errorf("Wrong default RX2DataRate for %s [7.4.7]", b.Name())
+	}
+	expectedChannels := []float32{433.175, 433.375, 433.575}
+	if len(b.Configuration().JoinReqChannels) != len(expectedChannels) {
+		t.Fatalf("Wrong number of join channels for %s [7.4.2]", b.Name())
+	}
+	for i, f := range expectedChannels {
+		if b.Configuration().JoinReqChannels[i] != f {
+			t.Errorf("Wrong join channel %d (%f) for %s [7.4.2]", i, b.Configuration().JoinReqChannels[i], b.Name())
+		}
+	}
+}
+
+func TestTxPowerEU433(t *testing.T) {
+	b := newEU433()
+
+	expectedOutput := []int8{10, 7, 4, 1, -2, -5}
+	for i := 0; i < len(expectedOutput); i++ {
+		power, err := b.TxPower(uint8(i))
+		if err != nil {
+			t.Errorf("%s, %s [7.4.3]", err, b.Name())
+		}
+		if power != expectedOutput[i] {
+			t.Errorf("Wrong TxPower configuration for %d, %s [7.4.3]", power, b.Name())
+		}
+	}
+
+	if _, err := b.TxPower(6); err == nil {
+		t.Errorf("Invalid parameter should fail, %s [7.4.3]", b.Name())
+	}
+}
+
+func TestEncodingEU433(t *testing.T) {
+	b := newEU433()
+	testParams := []uint8{0, 1, 2, 3, 4, 5, 6, 7}
+	expectedModulations := []ModulationType{LoRa, LoRa, LoRa, LoRa, LoRa, LoRa, LoRa, FSK}
+	expectedSpreadFactors := []uint8{12, 11, 10, 9, 8, 7, 7, 0}
+	expectedBandwidths := []uint32{125, 125, 125, 125, 125, 125, 250, 0}
+	expectedBitrates := []uint32{250, 440, 980, 1760, 3125, 5470, 11000, 50000}
+
+	for i := 0; i < len(testParams); i++ {
+		encoding, err := b.Encoding(testParams[i])
+		if err != nil {
+			t.Errorf("%s, %s [7.4.3]", err, b.Name())
+		}
+		if encoding.Modulation != expectedModulations[i] {
+			t.Errorf("Unexpected modulation (%v) for datarate (%d) %s [7.4.3]", encoding.Modulation, testParams[i], b.Name())
+		}
+		if encoding.SpreadFactor != expectedSpreadFactors[i] {
+			t.Errorf("Unexpected spreadfactor (%v) for datarate (%d) %s [7.4.3]", encoding.SpreadFactor, testParams[i], b.Name())
+		}
+		if encoding.Bandwidth != expectedBandwidths[i] {
+			t.Errorf("Unexpected bandwidth (%v) for datarate (%d) %s [7.4.3]", encoding.Bandwidth, testParams[i], b.Name())
+		}
+		if encoding.BitRate != expectedBitrates[i] {
+			t.Errorf("Unexpected bit rate (%v) for datarate (%d) %s [7.4.3]", encoding.BitRate, testParams[i], b.Name())
+		}
+	}
+
+	if _, err := b.Encoding(42); err == nil {
+		t.Errorf("Invalid parameter should fail, %s [7.4.3]", b.Name())
+	}
+}
+
+func TestMaximumPayloadEU433(t *testing.T) {
+	b := newEU433()
+	testParams := []string{"SF12BW125", "SF10BW125", "SF9BW125", "SF8BW125", "SF7BW250"}
+	expectedMs := []uint8{59, 59, 123, 230, 230}
+	expectedNs := []uint8{51, 51, 115, 222, 222}
+
+	for i := 0; i < len(testParams); i++ {
+		mp, err := b.MaximumPayload(testParams[i])
+		if err != nil {
+			t.Errorf("%s, %s [7.4.6]", err, b.Name())
+		}
+		if mp.WithoutFOpts() != expectedMs[i] {
+			t.Errorf("Unexpected M (%d) for %s %s [7.4.6]", mp.M, testParams[i], b.Name())
+		}
+		if mp.WithFOpts() != expectedNs[i] {
+			t.Errorf("Unexpected N (%d) for %s %s [7.4.6]", mp.N, testParams[i], b.Name())
+		}
+	}
+
+	if _, err := b.MaximumPayload("SF19BW1"); err == nil {
+		t.Errorf("Invalid parameter should fail, %s [7.4.6]", b.Name())
+	}
+}
+
+func TestDownlinkDataRatesEU433(t *testing.T) {
+	expectedRates := [][]uint8{
+		{0, 0, 0, 0, 0, 0},
+		{1, 0, 0, 0, 0, 0},
+		{2, 1, 0, 0, 0, 0},
+		{3, 2, 1, 0, 0, 0},
+		{4, 3, 2, 1, 0, 0},
+		{5, 4, 3, 2, 1, 0},
+		{6, 5, 4, 3, 2, 1},
+		{7, 6, 5, 4, 3, 2},
+	}
+	b := newEU433()
+	for upstreamDataRate, rates := range expectedRates {
+		if rates == nil {
+			if _, err := b.GetRX1Parameters(0, b.Configuration().RX2Frequency, uint8(upstreamDataRate), 0); err == nil {
+				t.Errorf("Expected error for data rate %d, %s [7.4.7]", upstreamDataRate, b.Name())
+			}
+			continue
+		}
+		for RX1DROffset, expected := range rates {
+			rate, err := rx1DataRate(b.DownstreamDataRates, uint8(upstreamDataRate), uint8(RX1DROffset))
+			if err != nil {
+				t.Errorf("%s, %s [7.4.7]", err, b.Name())
+			}
+			if rate != expected {
+				t.Errorf("Unexpected downstream datarate (%d) for given upstream datarate/RX1DROffset (%d/%d) %s [7.4.7]", rate, upstreamDataRate, RX1DROffset, b.Name())
+			}
+		}
+	}
+
+	if _, err := rx1DataRate(b.DownstreamDataRates, 99, 0); err == nil {
+		t.Errorf("Invalid parameter should fail, %s [7.4.7]", b.Name())
+	}
+	if _, err := rx1DataRate(b.DownstreamDataRates, 0, 6); err == nil {
+		t.Errorf("Invalid parameter should fail, %s [7.4.7]", b.Name())
+	}
+}
+
+func TestGetRX1ParametersEU433(t *testing.T) {
+	b := newEU433()
+	testParams := []struct {
+		upstreamFrequency float32
+		upstreamDataRate  uint8
+		rx1DROffset       uint8
+		frequency         float32
+		dataRate          uint8
+	}{
+		{433.375, 5, 1, 433.375, 4},
+	}
+	for _, p := range testParams {
+		dlParams, err := b.GetRX1Parameters(0, p.upstreamFrequency, p.upstreamDataRate, p.rx1DROffset)
+		if err != nil {
+			t.Error(err)
+		}
+		if dlParams.DataRate != p.dataRate {
+			t.Errorf("Unexpected data rate for %f/DR%d: %d", p.upstreamFrequency, p.upstreamDataRate, dlParams.DataRate)
+		}
+		if dlParams.Frequency != p.frequency {
+			t.Errorf("Unexpected frequency for %f/DR%d: %f", p.upstreamFrequency, p.upstreamDataRate, dlParams.Frequency)
+		}
+	}
+
+	if _, err := b.GetRX1Parameters(0, 433.375, 30, 0); err == nil {
+		t.Errorf("Expected invalid data rate.")
+	}
+	if _, err := b.GetRX1Parameters(0, 433.375, 0, 30); err == nil {
+		t.Errorf("Expected invalid data rate offset.")
+	}
+}
+
+func TestGetDataRateEU433(t *testing.T) {
+	b := newEU433()
+	for configuration, expected := range map[string]uint8{
+		"SF12BW125": 0,
+		"SF7BW125":  5,
+		"SF7BW250":  6,
+		"FSKBW500":  7,
+	} {
+		dr, err := b.GetDataRate(configuration)
+		if (dr != expected) || (err != nil) {
+			t.Errorf("Unexpected data rate or error in lookup of %s: %d. Error: %v", configuration, dr, err)
+		}
+	}
+
+	if _, err := b.GetDataRate("XYZZY"); err == nil {
+		t.Error("Expected lookup of XYZZY to fail")
+	}
+}
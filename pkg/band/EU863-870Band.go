@@ -35,8 +35,15 @@ func newEU868() EU868 {
 			AdrAckDelay:              32,      // [7.1.8]
 			DefaultTxPower:           14,      // [7.1.7]
 			SupportsJoinAcceptCFList: true,    // [7.1.4]
+			MinFrequency:             863.0,   // [7.1.1]
+			MaxFrequency:             870.0,   // [7.1.1]
 			RX2Frequency:             869.525, // [7.1.7]
 			RX2DataRate:              0,       // [7.1.8]
+			MaxADRDataRate:           5,       // SF7BW125 is the fastest rate on all channels
+			MaxTxPower:               5,       // [7.1.3]
+			DefaultChannelMask:       0x0007,  // The three mandatory channels
+			BeaconDataRate:           3,       // [7.1.9]
+			PingSlotDataRate:         3,       // [7.1.9]
 			MandatoryEndDeviceChannels: []float32{
 				868.1,
 				868.3,
@@ -45,6 +52,21 @@ func newEU868() EU868 {
 				868.1,
 				868.3,
 				868.5}, // [2.1.2/Regional Parameters]
+			BeaconFrequencies:   []float32{869.525}, // [7.1.9]
+			PingSlotFrequencies: []float32{869.525}, // [7.1.9]
+			BeaconRFUSize:       [2]int{2, 0},       // [7.1.9]
+			// The sub-bands without a generic SRD limit use the strictest limit (0.1%)
+			SubBands: []SubBand{
+				{MinFrequency: 863.0, MaxFrequency: 865.0, DutyCycle: 0.001},
+				{MinFrequency: 865.0, MaxFrequency: 868.0, DutyCycle: 0.01},
+				{MinFrequency: 868.0, MaxFrequency: 868.6, DutyCycle: 0.01},
+				{MinFrequency: 868.6, MaxFrequency: 868.7, DutyCycle: 0.001},
+				{MinFrequency: 868.7, MaxFrequency: 869.2, DutyCycle: 0.001},
+				{MinFrequency: 869.2, MaxFrequency: 869.4, DutyCycle: 0.001},
+				{MinFrequency: 869.4, MaxFrequency: 869.65, DutyCycle: 0.1},
+				{MinFrequency: 869.65, MaxFrequency: 869.7, DutyCycle: 0.001},
+				{MinFrequency: 869.7, MaxFrequency: 870.0, DutyCycle: 0.01},
+			}, // ETSI EN 300 220 and ERC Recommendation 70-03
 		},
 		DownstreamDataRates: [][]uint8{
 			{0, 0, 0, 0, 0, 0},
@@ -0,0 +1,149 @@
+package band
+
+import "fmt"
+
+// IN865 represents configuration and frequency plan for the India 865-867MHz ISM Band.
+type IN865 struct {
+	configuration       Configuration
+	DownstreamDataRates [][]uint8
+}
+
+func newIN865() IN865 {
+	return IN865{
+		configuration: Configuration{
+			ReceiveDelay1:            1,      // [RP 2.10.8]
+			ReceiveDelay2:            2,      // ReceiveDelay1 + 1 according to [RP 2.10.8]
+			JoinAccepDelay1:          5,      // [RP 2.10.8]
+			JoinAccepDelay2:          6,      // [RP 2.10.8]
+			MaxFCntGap:               16384,  // [RP 2.10.8]
+			AdrAckLimit:              64,     // [RP 2.10.8]
+			AdrAckDelay:              32,     // [RP 2.10.8]
+			DefaultTxPower:           30,     // Default max EIRP [RP 2.10.3]
+			SupportsJoinAcceptCFList: true,   // [RP 2.10.4]
+			MinFrequency:             865.0,  // [RP 2.10.1]
+			MaxFrequency:             867.0,  // [RP 2.10.1]
+			RX2Frequency:             866.55, // [RP 2.10.7]
+			RX2DataRate:              2,      // [RP 2.10.7]
+			MaxADRDataRate:           5,      // SF7BW125 is the fastest LoRa rate
+			MaxTxPower:               10,     // [RP 2.10.3]
+			DefaultChannelMask:       0x0007, // The three mandatory channels
+			BeaconDataRate:           4,      // [RP 2.10.9]
+			PingSlotDataRate:         4,      // [RP 2.10.9]
+			MandatoryEndDeviceChannels: []float32{
+				865.0625,
+				865.4025,
+				865.985}, // [RP 2.10.2]
+			JoinReqChannels: []float32{
+				865.0625,
+				865.4025,
+				865.985}, // [RP 2.10.2]
+			BeaconFrequencies:   []float32{866.55}, // [RP 2.10.9]
+			PingSlotFrequencies: []float32{866.55}, // [RP 2.10.9]
+			BeaconRFUSize:       [2]int{1, 2},      // [RP 2.10.9]
+		},
+		// Offsets 6 and 7 increase the data rate by 1 and 2 [RP 2.10.7]
+		DownstreamDataRates: [][]uint8{
+			{0, 0, 0, 0, 0, 0, 1, 2}, // DR0
+			{1, 0, 0, 0, 0, 0, 2, 3}, // DR1
+			{2, 1, 0, 0, 0, 0, 3, 4}, // DR2
+			{3, 2, 1, 0, 0, 0, 4, 5}, // DR3
+			{4, 3, 2, 1, 0, 0, 5, 5}, // DR4
+			{5, 4, 3, 2, 1, 0, 5, 7}, // DR5
+			{},                       // DR6 - RFU
+			{7, 5, 5, 4, 3, 2, 7, 7}, // DR7
+		},
+	}
+}
+
+// Name returns frequency band name.
+func (b IN865) Name() string {
+	return "IN 865-867MHz ISM Band"
+}
+
+// Configuration returns parameters for the IN 865-867MHz ISM Band.
+func (b IN865) Configuration() *Configuration {
+	return &b.configuration
+}
+
+// TxPower returns power in dBm for the IN 865-867MHz ISM Band, given a TXPower
+// key. The power is relative to the default max EIRP of 30 dBm [RP 2.10.3]
+func (b IN865) TxPower(power uint8) (int8, error) {
+	if power > b.configuration.MaxTxPower {
+		return 0, fmt.Errorf("invalid power: %d", power)
+	}
+	return int8(b.configuration.DefaultTxPower) - 2*int8(power), nil
+}
+
+// Encoding returns a description of modulation, spread factor and bit rate for the IN 865-867MHz ISM Band, given a data rate. [RP 2.10.3]
+func (b IN865) Encoding(dataRate uint8) (Encoding, error) {
+	switch dataRate {
+	case 0:
+		return Encoding{Modulation: LoRa, SpreadFactor: 12, Bandwidth: 125, BitRate: 250}, nil
+	case 1:
+		return Encoding{Modulation: LoRa, SpreadFactor: 11, Bandwidth: 125, BitRate: 440}, nil
+	case 2:
+		return Encoding{Modulation: LoRa, SpreadFactor: 10, Bandwidth: 125, BitRate: 980}, nil
+	case 3:
+		return Encoding{Modulation: LoRa, SpreadFactor: 9, Bandwidth: 125, BitRate: 1760}, nil
+	case 4:
+		return Encoding{Modulation: LoRa, SpreadFactor: 8, Bandwidth: 125, BitRate: 3125}, nil
+	case 5:
+		return Encoding{Modulation: LoRa, SpreadFactor: 7, Bandwidth: 125, BitRate: 5470}, nil
+	case 7:
+		return Encoding{Modulation: FSK, BitRate: 50000}, nil
+	default:
+		return Encoding{}, fmt.Errorf("unable to look up encoding. Invalid data rate :%d", dataRate)
+	}
+}
+
+// MaximumPayload return a maximum payload size, given a data rate.
+// This implementation uses the repeater compatible definition in the LoRaWAN specification. [RP 2.10.6]
+func (b IN865) MaximumPayload(dataRate string) (MaximumPayloadSize, error) {
+	dr, err := b.GetDataRate(dataRate)
+	if err != nil {
+		return MaximumPayloadSize{}, err
+	}
+	switch dr {
+	case 0, 1, 2:
+		return MaximumPayloadSize{M: 59, N: 51}, nil
+	case 3:
+		return MaximumPayloadSize{M: 123, N: 115}, nil
+	case 4, 5, 7:
+		return MaximumPayloadSize{M: 230, N: 222}, nil
+	default:
+		return MaximumPayloadSize{}, fmt.Errorf("unable to look up maximum payload. Invalid data rate :%s", dataRate)
+	}
+}
+
+// GetRX1Parameters returns datarate and frequency for downlink in receive window 1, given upstream data rate and RX1DROffset
+func (b IN865) GetRX1Parameters(channel uint8, upstreamFrequency float32, upstreamDataRate uint8, RX1DROffset uint8) (DownlinkParameters, error) {
+	datarate, err := rx1DataRate(b.DownstreamDataRates, upstreamDataRate, RX1DROffset)
+	return DownlinkParameters{DataRate: datarate, Frequency: upstreamFrequency}, err
+}
+
+// GetRX2Parameters returns datarate and frequency for downlink in receive window 2.
+func (b IN865) GetRX2Parameters() DownlinkParameters {
+	return DownlinkParameters{DataRate: b.configuration.RX2DataRate, Frequency: b.configuration.RX2Frequency}
+}
+
+// GetDataRate returns data rate, given gateway representation of configuration
+func (b IN865) GetDataRate(configuration string) (uint8, error) {
+	switch configuration {
+	case "SF12BW125":
+		return 0, nil
+	case "SF11BW125":
+		return 1, nil
+	case "SF10BW125":
+		return 2, nil
+	case "SF9BW125":
+		return 3, nil
+	case "SF8BW125":
+		return 4, nil
+	case "SF7BW125":
+		return 5, nil
+	case "FSKBW500":
+		return 7, nil
+	default:
+		return 0, fmt.Errorf("unable to convert configuration '%s' into data rate", configuration)
+	}
+}
@@ -0,0 +1,142 @@
+package band
+
+import "fmt"
+
+// KR920 represents configuration and frequency plan for the South Korea 920-923MHz ISM Band.
+type KR920 struct {
+	configuration       Configuration
+	DownstreamDataRates [][]uint8
+}
+
+func newKR920() KR920 {
+	return KR920{
+		configuration: Configuration{
+			ReceiveDelay1:            1,      // [RP 2.9.8]
+			ReceiveDelay2:            2,      // ReceiveDelay1 + 1 according to [RP 2.9.8]
+			JoinAccepDelay1:          5,      // [RP 2.9.8]
+			JoinAccepDelay2:          6,      // [RP 2.9.8]
+			MaxFCntGap:               16384,  // [RP 2.9.8]
+			AdrAckLimit:              64,     // [RP 2.9.8]
+			AdrAckDelay:              32,     // [RP 2.9.8]
+			DefaultTxPower:           14,     // Default max EIRP [RP 2.9.3]
+			SupportsJoinAcceptCFList: true,   // [RP 2.9.4]
+			MinFrequency:             920.9,  // [RP 2.9.1]
+			MaxFrequency:             923.3,  // [RP 2.9.1]
+			RX2Frequency:             921.9,  // [RP 2.9.7]
+			RX2DataRate:              0,      // [RP 2.9.7]
+			MaxADRDataRate:           5,      // SF7BW125 is the fastest rate
+			MaxTxPower:               7,      // [RP 2.9.3]
+			DefaultChannelMask:       0x0007, // The three mandatory channels
+			BeaconDataRate:           3,      // [RP 2.9.9]
+			PingSlotDataRate:         3,      // [RP 2.9.9]
+			MandatoryEndDeviceChannels: []float32{
+				922.1,
+				922.3,
+				922.5}, // [RP 2.9.2]
+			JoinReqChannels: []float32{
+				922.1,
+				922.3,
+				922.5}, // [RP 2.9.2]
+			BeaconFrequencies:   []float32{923.1}, // [RP 2.9.9]
+			PingSlotFrequencies: []float32{923.1}, // [RP 2.9.9]
+			BeaconRFUSize:       [2]int{2, 0},     // [RP 2.9.9]
+		},
+		DownstreamDataRates: [][]uint8{
+			{0, 0, 0, 0, 0, 0},
+			{1, 0, 0, 0, 0, 0},
+			{2, 1, 0, 0, 0, 0},
+			{3, 2, 1, 0, 0, 0},
+			{4, 3, 2, 1, 0, 0},
+			{5, 4, 3, 2, 1, 0},
+		},
+	}
+}
+
+// Name returns frequency band name.
+func (b KR920) Name() string {
+	return "KR 920-923MHz ISM Band"
+}
+
+// Configuration returns parameters for the KR 920-923MHz ISM Band.
+func (b KR920) Configuration() *Configuration {
+	return &b.configuration
+}
+
+// TxPower returns power in dBm for the KR 920-923MHz ISM Band, given a TXPower
+// key. The power is relative to the default max EIRP of 14 dBm [RP 2.9.3]
+func (b KR920) TxPower(power uint8) (int8, error) {
+	if power > b.configuration.MaxTxPower {
+		return 0, fmt.Errorf("invalid power: %d", power)
+	}
+	return int8(b.configuration.DefaultTxPower) - 2*int8(power), nil
+}
+
+// Encoding returns a description of modulation, spread factor and bit rate for the KR 920-923MHz ISM Band, given a data rate. [RP 2.9.3]
+func (b KR920) Encoding(dataRate uint8) (Encoding, error) {
+	switch dataRate {
+	case 0:
+		return Encoding{Modulation: LoRa, SpreadFactor: 12, Bandwidth: 125, BitRate: 250}, nil
+	case 1:
+		return Encoding{Modulation: LoRa, SpreadFactor: 11, Bandwidth: 125, BitRate: 440}, nil
+	case 2:
+		return Encoding{Modulation: LoRa, SpreadFactor: 10, Bandwidth: 125, BitRate: 980}, nil
+	case 3:
+		return Encoding{Modulation: LoRa, SpreadFactor: 9, Bandwidth: 125, BitRate: 1760}, nil
+	case 4:
+		return Encoding{Modulation: LoRa, SpreadFactor: 8, Bandwidth: 125, BitRate: 3125}, nil
+	case 5:
+		return Encoding{Modulation: LoRa, SpreadFactor: 7, Bandwidth: 125, BitRate: 5470}, nil
+	default:
+		return Encoding{}, fmt.Errorf("unable to look up encoding. Invalid data rate :%d", dataRate)
+	}
+}
+
+// MaximumPayload return a maximum payload size, given a data rate.
+// This implementation uses the repeater compatible definition in the LoRaWAN specification. [RP 2.9.6]
+func (b KR920) MaximumPayload(dataRate string) (MaximumPayloadSize, error) {
+	dr, err := b.GetDataRate(dataRate)
+	if err != nil {
+		return MaximumPayloadSize{}, err
+	}
+	switch dr {
+	case 0, 1, 2:
+		return MaximumPayloadSize{M: 59, N: 51}, nil
+	case 3:
+		return MaximumPayloadSize{M: 123, N: 115}, nil
+	case 4, 5:
+		return MaximumPayloadSize{M: 230, N: 222}, nil
+	default:
+		return MaximumPayloadSize{}, fmt.Errorf("unable to look up maximum payload. Invalid data rate :%s", dataRate)
+	}
+}
+
+// GetRX1Parameters returns datarate and frequency for downlink in receive window 1, given upstream data rate and RX1DROffset
+func (b KR920) GetRX1Parameters(channel uint8, upstreamFrequency float32, upstreamDataRate uint8, RX1DROffset uint8) (DownlinkParameters, error) {
+	datarate, err := rx1DataRate(b.DownstreamDataRates, upstreamDataRate, RX1DROffset)
+	return DownlinkParameters{DataRate: datarate, Frequency: upstreamFrequency}, err
+}
+
+// GetRX2Parameters returns datarate and frequency for downlink in receive window 2.
+func (b KR920) GetRX2Parameters() DownlinkParameters {
+	return DownlinkParameters{DataRate: b.configuration.RX2DataRate, Frequency: b.configuration.RX2Frequency}
+}
+
+// GetDataRate returns data rate, given gateway representation of configuration
+func (b KR920) GetDataRate(configuration string) (uint8, error) {
+	switch configuration {
+	case "SF12BW125":
+		return 0, nil
+	case "SF11BW125":
+		return 1, nil
+	case "SF10BW125":
+		return 2, nil
+	case "SF9BW125":
+		return 3, nil
+	case "SF8BW125":
+		return 4, nil
+	case "SF7BW125":
+		return 5, nil
+	default:
+		return 0, fmt.Errorf("unable to convert configuration '%s' into data rate", configuration)
+	}
+}
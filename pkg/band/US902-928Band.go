@@ -36,8 +36,21 @@ func newUS902() US902 {
 			AdrAckDelay:              32,    // [7.2.8]
 			DefaultTxPower:           20,    // or a) 30 dBm for 125kHz BW (max 400ms), or b) 26 dBm for 500kHz BW [7.2.1]
 			SupportsJoinAcceptCFList: false, // [7.2.4]
+			MinFrequency:             902.0, // [7.2.1]
+			MaxFrequency:             928.0, // [7.2.1]
 			RX2Frequency:             923.3, // [7.2.7]
 			RX2DataRate:              8,     // [7.2.7]
+			MaxADRDataRate:           3,     // SF7BW125 is the fastest 125kHz rate
+			DownlinkOnlyDataRate:     8,     // DR8-13 are downlink only [7.2.3]
+			MaxTxPower:               10,    // [7.2.3]
+			DefaultChannelMask:       0xFF,  // First sub-band
+			BeaconDataRate:           8,     // [7.2.9]
+			PingSlotDataRate:         8,     // [7.2.9]
+			BeaconFrequencies: []float32{
+				923.3, 923.9, 924.5, 925.1, 925.7, 926.3, 926.9, 927.5}, // 923.3 + 0.6 * n [7.2.9]
+			PingSlotFrequencies: []float32{
+				923.3, 923.9, 924.5, 925.1, 925.7, 926.3, 926.9, 927.5}, // [7.2.9]
+			BeaconRFUSize: [2]int{5, 3}, // [7.2.9]
 		},
 		DownstreamDataRates: [][]uint8{
 			{10, 9, 8, 8},    // DR0
@@ -73,6 +86,13 @@ func (b US902) Name() string {
 	return "US 902-928MHz ISM Band"
 }
 
+// UplinkChannel returns the uplink channel for a frequency. Channel 0-63 are
+// the 125 kHz channels starting at 902.3 MHz and channel 64-71 are the 500 kHz
+// channels starting at 903.0 MHz [7.2.2]
+func (b US902) UplinkChannel(frequency float32) (int, error) {
+	return fixedUplinkChannel(frequency, 902.3, 903.0)
+}
+
 // Configuration returns parameters for the US 902-928MHz ISM Band.
 func (b US902) Configuration() *Configuration {
 	return &b.configuration
@@ -156,7 +176,16 @@ func (b US902) MaximumPayload(dataRate string) (MaximumPayloadSize, error) {
 // GetRX1Parameters returns datarate and frequency for downlink in receive window 1, given upstream data rate and RX1DROffset
 func (b US902) GetRX1Parameters(channel uint8, upstreamFrequency float32, upstreamDataRate uint8, RX1DROffset uint8) (DownlinkParameters, error) {
 	datarate, err := b.downlinkDataRate(upstreamDataRate, RX1DROffset)
-	return DownlinkParameters{DataRate: datarate, Frequency: b.DownstreamChannels[channel%8]}, err
+	if err != nil {
+		return DownlinkParameters{}, err
+	}
+	// The channel is the concentrator's IF chain so the uplink channel is
+	// looked up from the frequency [7.2.7]
+	upstreamChannel, err := b.UplinkChannel(upstreamFrequency)
+	if err != nil {
+		return DownlinkParameters{}, err
+	}
+	return DownlinkParameters{DataRate: datarate, Frequency: b.DownstreamChannels[upstreamChannel%8]}, nil
 }
 
 // GetRX2Parameters returns datarate and frequency for downlink in receive window 2.
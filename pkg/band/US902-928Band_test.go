@@ -225,25 +225,43 @@ func TestDownlinkDataRatesUS(t *testing.T) {
 
 func TestGetRX1ParametersUS(t *testing.T) {
 	b := newUS902()
-	dlParams, err := b.GetRX1Parameters(63, 0 /* only channel is used in US lookup */, 3, 3)
-	if err != nil {
-		t.Error(err)
-	}
-	if dlParams.DataRate != 10 {
-		t.Errorf("Unexpected data rate : %d", dlParams.DataRate)
-	}
-	if dlParams.Frequency != 927.5 {
-		t.Errorf("Unexpected frequency: %f", dlParams.Frequency)
+	testParams := []struct {
+		channel           uint8
+		upstreamFrequency float32
+		upstreamDataRate  uint8
+		rx1DROffset       uint8
+		frequency         float32
+		dataRate          uint8
+	}{
+		{0, 914.9, 3, 3, 927.5, 10},
+		{7, 902.3, 0, 0, 923.3, 10},
+		{2, 904.7, 1, 1, 925.7, 10},
+		{8, 903.0, 4, 0, 923.3, 13},
+		{8, 914.2, 4, 0, 927.5, 13},
+	}
+	for _, p := range testParams {
+		// The channel is the concentrator IF chain and doesn't affect the result
+		dlParams, err := b.GetRX1Parameters(p.channel, p.upstreamFrequency, p.upstreamDataRate, p.rx1DROffset)
+		if err != nil {
+			t.Error(err)
+		}
+		if dlParams.DataRate != p.dataRate {
+			t.Errorf("Unexpected data rate for %f/DR%d: %d", p.upstreamFrequency, p.upstreamDataRate, dlParams.DataRate)
+		}
+		if dlParams.Frequency != p.frequency {
+			t.Errorf("Unexpected frequency for %f/DR%d: %f", p.upstreamFrequency, p.upstreamDataRate, dlParams.Frequency)
+		}
 	}
 
-	dlParams, err = b.GetRX1Parameters(0, 868.1, 30, 3)
-	if err == nil {
+	if _, err := b.GetRX1Parameters(0, 902.3, 30, 3); err == nil {
 		t.Errorf("Expected invalid data rate.")
 	}
-	dlParams, err = b.GetRX1Parameters(0, 868.1, 3, 30)
-	if err == nil {
+	if _, err := b.GetRX1Parameters(0, 902.3, 3, 30); err == nil {
 		t.Errorf("Expected invalid data rate offset.")
 	}
+	if _, err := b.GetRX1Parameters(0, 868.1, 3, 3); err == nil {
+		t.Errorf("Expected invalid upstream frequency.")
+	}
 }
 
 func TestGetDataRateUS(t *testing.T) {
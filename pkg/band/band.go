@@ -15,13 +15,12 @@ package band
 //See the License for the specific language governing permissions and
 //limitations under the License.
 //
-// BUG(hjg) No LinkAdrReq support yet.
-// BUG(hjg) No CFList support yet.
-// BUG(hjg) No NewChannelReq support yet.
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
+	"strings"
 	"time"
 )
 
@@ -61,6 +60,22 @@ const (
 	CN780Band
 	// EU433Band is the EU 433MHz ISM Band
 	EU433Band
+	// AS923Band is the AS 923MHz ISM Band, group 1 (AS923-1)
+	AS923Band
+	// AS923Group2Band is the AS 923MHz ISM Band, group 2 (AS923-2)
+	AS923Group2Band
+	// AS923Group3Band is the AS 923MHz ISM Band, group 3 (AS923-3)
+	AS923Group3Band
+	// AS923Group4Band is the AS 923MHz ISM Band, group 4 (AS923-4)
+	AS923Group4Band
+	// AU915Band is the Australia 915-928MHz ISM Band
+	AU915Band
+	// CN470Band is the China 470-510MHz Band
+	CN470Band
+	// IN865Band is the India 865-867MHz ISM Band
+	IN865Band
+	// KR920Band is the South Korea 920-923MHz ISM Band
+	KR920Band
 )
 
 // Encoding holds the data rate specific spread factor, frequency and bit rate parameters
@@ -75,6 +90,63 @@ type Encoding struct {
 	Bandwidth uint32
 }
 
+// RequiredSNR returns the minimum SNR (in dB) needed to demodulate a LoRa frame
+// with the encoding's spread factor. The values are the demodulator floors
+// listed in the SX1276 data sheet.
+func (e Encoding) RequiredSNR() (float32, error) {
+	if e.Modulation != LoRa {
+		return 0, fmt.Errorf("no SNR floor for modulation type %d", e.Modulation)
+	}
+	switch e.SpreadFactor {
+	case 7:
+		return -7.5, nil
+	case 8:
+		return -10, nil
+	case 9:
+		return -12.5, nil
+	case 10:
+		return -15, nil
+	case 11:
+		return -17.5, nil
+	case 12:
+		return -20, nil
+	default:
+		return 0, fmt.Errorf("unknown spread factor: %d", e.SpreadFactor)
+	}
+}
+
+// GatewayDataRate returns the data rate identifier used by the gateways, ie
+// "SF7BW125" for LoRa modulation or the bit rate for FSK modulation.
+func (e Encoding) GatewayDataRate() string {
+	if e.Modulation != LoRa {
+		return fmt.Sprintf("%d", e.BitRate)
+	}
+	return fmt.Sprintf("SF%dBW%d", e.SpreadFactor, e.Bandwidth)
+}
+
+// TimeOnAir returns the time needed to transmit a frame with the specified
+// PHY payload size. The calculation assumes the settings used for downlinks,
+// ie an 8 symbol preamble, explicit header, coding rate 4/5 and no payload
+// CRC. See the SX1276 data sheet for the LoRa formula.
+func (e Encoding) TimeOnAir(payloadSize int) time.Duration {
+	if e.Modulation != LoRa {
+		// Preamble (5), sync word (3), length (1) and CRC (2) bytes
+		bits := float64(8 * (payloadSize + 5 + 3 + 1 + 2))
+		return time.Duration(bits / float64(e.BitRate) * float64(time.Second))
+	}
+	const preambleSymbols = 8
+	const codingRate = 1 // 4/5
+	sf := float64(e.SpreadFactor)
+	symbolTime := math.Pow(2, sf) / float64(e.Bandwidth*1000)
+	lowDataRateOptimize := 0.0
+	if e.SpreadFactor >= 11 && e.Bandwidth == 125 {
+		lowDataRateOptimize = 1.0
+	}
+	payloadSymbols := 8 + math.Max(math.Ceil((8*float64(payloadSize)-4*sf+28)/(4*(sf-2*lowDataRateOptimize)))*(codingRate+4), 0)
+	seconds := (preambleSymbols+4.25)*symbolTime + payloadSymbols*symbolTime
+	return time.Duration(seconds * float64(time.Second))
+}
+
 // MaximumPayloadSize defines max payload size
 type MaximumPayloadSize struct {
 	// M is max payload length if FOpts is present.
@@ -125,14 +197,41 @@ type Configuration struct {
 	// SupportsJoinAcceptCFList indicates if the band support the optional list of channel freequencies for
 	// the network the end-device is joining [Band sub-chapters in 7].
 	SupportsJoinAcceptCFList bool
+	// MinFrequency is the lowest frequency (in MHz) in the band [Band sub-chapters in 7].
+	MinFrequency float32
+	// MaxFrequency is the highest frequency (in MHz) in the band [Band sub-chapters in 7].
+	MaxFrequency float32
 	// RX2Frequency is the default band frequency for the second receive window [Band sub-chapters in 7].
 	RX2Frequency float32
 	// RX2DataRate is the default data rate for the second receive window [Band sub-chapters in 7].
 	RX2DataRate uint8
+	// MaxADRDataRate is the highest data rate the network will assign through LinkADRReq.
+	MaxADRDataRate uint8
+	// DownlinkOnlyDataRate is the lowest data rate that is only used for
+	// downlinks. 0 means all data rates can be used for uplinks.
+	DownlinkOnlyDataRate uint8
+	// MaxTxPower is the highest (ie weakest) TXPower index the band defines [Band sub-chapters in 7].
+	MaxTxPower uint8
+	// DefaultChannelMask is the channel mask sent in LinkADRReq commands [5.2].
+	DefaultChannelMask uint16
+	// SubBands are the regulatory sub-bands with duty cycle limits for the
+	// gateways. Frequencies outside the sub-bands have no limit.
+	SubBands []SubBand
+	// MaxDwellTime is the longest time a single downlink can be on air. 0
+	// means no limit.
+	MaxDwellTime time.Duration
+	// BeaconDataRate is the data rate for class B beacons [Band sub-chapters in 7].
+	BeaconDataRate uint8
+	// BeaconRFUSize is the size of the two RFU fields in the beacon frame [Band sub-chapters in 7].
+	BeaconRFUSize [2]int
+	// PingSlotDataRate is the default data rate for class B ping slots [Band sub-chapters in 7].
+	PingSlotDataRate uint8
 
 	MandatoryEndDeviceChannels []float32
 	JoinReqChannels            []float32
 	DownLinkFrequencies        []float32
+	BeaconFrequencies          []float32 // The beacon hops between the frequencies each beacon period
+	PingSlotFrequencies        []float32 // The ping slots hops between the frequencies based on the DevAddr
 }
 
 // AckTimeout is the max delay limit (in seconds after the second receive window) for when then the network can send a frame with the
@@ -141,6 +240,38 @@ func (c *Configuration) AckTimeout() int {
 	return rand.Intn(3) + 1
 }
 
+// Contains returns true if the frequency is inside the band
+func (c *Configuration) Contains(frequency float32) bool {
+	return frequency >= c.MinFrequency && frequency <= c.MaxFrequency
+}
+
+// DownlinkOnly returns true if the data rate is only used for downlinks
+func (c *Configuration) DownlinkOnly(dataRate uint8) bool {
+	return c.DownlinkOnlyDataRate > 0 && dataRate >= c.DownlinkOnlyDataRate
+}
+
+// BeaconFrequency returns the beacon frequency for the beacon period starting
+// at beaconTime (GPS time). Bands without beacon frequencies use the RX2
+// frequency.
+func (c *Configuration) BeaconFrequency(beaconTime time.Duration) float32 {
+	if len(c.BeaconFrequencies) == 0 {
+		return c.RX2Frequency
+	}
+	period := int64(beaconTime / (128 * time.Second))
+	return c.BeaconFrequencies[period%int64(len(c.BeaconFrequencies))]
+}
+
+// PingSlotFrequency returns the default ping slot frequency for the device in
+// the beacon period starting at beaconTime (GPS time). Bands without ping slot
+// frequencies use the RX2 frequency.
+func (c *Configuration) PingSlotFrequency(beaconTime time.Duration, devAddr uint32) float32 {
+	if len(c.PingSlotFrequencies) == 0 {
+		return c.RX2Frequency
+	}
+	period := int64(beaconTime/(128*time.Second)) + int64(devAddr)
+	return c.PingSlotFrequencies[period%int64(len(c.PingSlotFrequencies))]
+}
+
 // FrequencyPlan is the interface for band specific parameters
 type FrequencyPlan interface {
 	// Configuration returns band frequency parameters
@@ -164,6 +295,56 @@ type FrequencyPlan interface {
 	Name() string
 }
 
+// DwellTimePlan is implemented by the bands where the network turns the 400 ms
+// dwell time limit on and off with TxParamSetupReq [5.8]. The limit changes
+// the maximum payload sizes and the RX1 data rates.
+type DwellTimePlan interface {
+	// DwellTime returns true if the dwell time limit applies
+	DwellTime() bool
+	// WithDwellTime returns the band with the dwell time limit
+	WithDwellTime() FrequencyPlan
+}
+
+// bandNames is the short name for each of the frequency band types. The names
+// match the common names used in the regional parameters.
+var bandNames = map[FrequencyBandType]string{
+	EU868Band:       "EU868",
+	US915Band:       "US915",
+	CN780Band:       "CN779",
+	EU433Band:       "EU433",
+	AS923Band:       "AS923-1",
+	AS923Group2Band: "AS923-2",
+	AS923Group3Band: "AS923-3",
+	AS923Group4Band: "AS923-4",
+	AU915Band:       "AU915",
+	CN470Band:       "CN470",
+	IN865Band:       "IN865",
+	KR920Band:       "KR920",
+}
+
+// String returns the short name of the band, ie EU868 or AS923-1
+func (b FrequencyBandType) String() string {
+	if name, ok := bandNames[b]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(b))
+}
+
+// ParseBand returns the frequency band type with the short name. The name
+// is case insensitive and AS923 is an alias for AS923-1.
+func ParseBand(name string) (FrequencyBandType, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "AS923" {
+		return AS923Band, nil
+	}
+	for b, n := range bandNames {
+		if n == name {
+			return b, nil
+		}
+	}
+	return EU868Band, fmt.Errorf("unknown band name: %s", name)
+}
+
 // NewBand creates a new band configuration
 func NewBand(band FrequencyBandType) (FrequencyPlan, error) {
 	switch band {
@@ -171,7 +352,49 @@ func NewBand(band FrequencyBandType) (FrequencyPlan, error) {
 		return newEU868(), nil
 	case US915Band:
 		return newUS902(), nil
+	case EU433Band:
+		return newEU433(), nil
+	case AS923Band:
+		return newAS923(1), nil
+	case AS923Group2Band:
+		return newAS923(2), nil
+	case AS923Group3Band:
+		return newAS923(3), nil
+	case AS923Group4Band:
+		return newAS923(4), nil
+	case AU915Band:
+		return newAU915(), nil
+	case CN470Band:
+		return newCN470(), nil
+	case IN865Band:
+		return newIN865(), nil
+	case KR920Band:
+		return newKR920(), nil
 	default:
-		return nil, fmt.Errorf("unknown band: %v. Valid arguments are: EU868, US915 (CN780 and EU433 are not implemented yet)", band)
+		return nil, fmt.Errorf("unknown band: %v. Valid arguments are: EU868, US915, EU433, AS923 (groups 1-4), AU915, CN470, IN865 and KR920 (CN780 is not implemented yet)", band)
+	}
+}
+
+// channelIndex returns the index of the channel with the specified frequency
+// in a channel plan with evenly spaced channels.
+func channelIndex(frequency float32, first float32, spacing float32) int {
+	return int(math.Round(float64((frequency - first) / spacing)))
+}
+
+// channelFrequency returns the frequency of a channel in a channel plan with
+// evenly spaced channels. The frequency is rounded to the nearest kHz.
+func channelFrequency(first float64, spacing float64, index int) float32 {
+	return float32(math.Round((first+spacing*float64(index))*1000) / 1000)
+}
+
+// rx1DataRate looks up the RX1 data rate in a band's downstream data rate
+// table. Unused upstream data rates have an empty row.
+func rx1DataRate(table [][]uint8, upstreamDataRate uint8, RX1DROffset uint8) (uint8, error) {
+	if int(upstreamDataRate) >= len(table) || len(table[upstreamDataRate]) == 0 {
+		return 0, fmt.Errorf("invalid data rate parameter: %d", upstreamDataRate)
+	}
+	if int(RX1DROffset) >= len(table[upstreamDataRate]) {
+		return 0, fmt.Errorf("invalid RX1DROffset parameter: %d. RX1DROffset has to be in the interval [0, %d]", RX1DROffset, len(table[upstreamDataRate])-1)
 	}
+	return table[upstreamDataRate][RX1DROffset], nil
 }
@@ -15,7 +15,10 @@ package band
 //See the License for the specific language governing permissions and
 //limitations under the License.
 //
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestBandFactory(t *testing.T) {
 	eu, err := NewBand(EU868Band)
@@ -32,6 +35,26 @@ func TestBandFactory(t *testing.T) {
 	if us.Name() != "US 902-928MHz ISM Band" {
 		t.Errorf("Unexpected band name : %s", us.Name())
 	}
+	for id, name := range map[FrequencyBandType]string{
+		EU433Band:       "EU 433MHz ISM Band",
+		AS923Band:       "AS 923MHz ISM Band (group 1)",
+		AS923Group2Band: "AS 923MHz ISM Band (group 2)",
+		AS923Group3Band: "AS 923MHz ISM Band (group 3)",
+		AS923Group4Band: "AS 923MHz ISM Band (group 4)",
+		AU915Band:       "AU 915-928MHz ISM Band",
+		CN470Band:       "CN 470-510MHz Band",
+		IN865Band:       "IN 865-867MHz ISM Band",
+		KR920Band:       "KR 920-923MHz ISM Band",
+	} {
+		b, err := NewBand(id)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if b.Name() != name {
+			t.Errorf("Unexpected band name : %s", b.Name())
+		}
+	}
 	_, err3 := NewBand(CN780Band)
 	if err3 == nil {
 		t.Error("Did not expect this band to be implemented.")
@@ -49,3 +72,113 @@ func TestFOptsLookup(t *testing.T) {
 	}
 
 }
+
+func TestRequiredSNR(t *testing.T) {
+	eu, _ := NewBand(EU868Band)
+	for dr, expected := range []float32{-20, -17.5, -15, -12.5, -10, -7.5, -7.5} {
+		enc, _ := eu.Encoding(uint8(dr))
+		snr, err := enc.RequiredSNR()
+		if err != nil {
+			t.Fatalf("Got error for DR%d: %v", dr, err)
+		}
+		if snr != expected {
+			t.Errorf("Expected %f dB for DR%d but got %f", expected, dr, snr)
+		}
+	}
+	fsk, _ := eu.Encoding(7)
+	if _, err := fsk.RequiredSNR(); err == nil {
+		t.Error("Expected error for FSK modulation")
+	}
+}
+
+func TestTimeOnAir(t *testing.T) {
+	eu, _ := NewBand(EU868Band)
+	sf7, _ := eu.Encoding(5)
+	if toa := sf7.TimeOnAir(13); toa != 41216*time.Microsecond {
+		t.Errorf("Expected 41.216ms for SF7 but got %v", toa)
+	}
+	sf12, _ := eu.Encoding(0)
+	if toa := sf12.TimeOnAir(13); toa != 1155072*time.Microsecond {
+		t.Errorf("Expected 1155.072ms for SF12 but got %v", toa)
+	}
+	if sf12.GatewayDataRate() != "SF12BW125" {
+		t.Errorf("Expected SF12BW125 but got %s", sf12.GatewayDataRate())
+	}
+	fsk, _ := eu.Encoding(7)
+	if fsk.GatewayDataRate() != "50000" {
+		t.Errorf("Expected 50000 but got %s", fsk.GatewayDataRate())
+	}
+	if fsk.TimeOnAir(13) != 3840*time.Microsecond {
+		t.Errorf("Expected 3.84ms for FSK but got %v", fsk.TimeOnAir(13))
+	}
+}
+
+func TestClassBFrequencies(t *testing.T) {
+	eu, _ := NewBand(EU868Band)
+	if eu.Configuration().BeaconFrequency(1280*time.Second) != 869.525 {
+		t.Error("EU868 beacon should use 869.525MHz")
+	}
+	if eu.Configuration().PingSlotFrequency(1280*time.Second, 0x01020304) != 869.525 {
+		t.Error("EU868 ping slots should use 869.525MHz")
+	}
+	us, _ := NewBand(US915Band)
+	if us.Configuration().BeaconFrequency(0) != 923.3 || us.Configuration().BeaconFrequency(128*time.Second) != 923.9 {
+		t.Error("US915 beacon should hop between channels")
+	}
+	if us.Configuration().PingSlotFrequency(0, 2) != 924.5 {
+		t.Error("US915 ping slot channel should depend on DevAddr")
+	}
+}
+
+func TestBandNames(t *testing.T) {
+	for b := range bandNames {
+		parsed, err := ParseBand(b.String())
+		if err != nil {
+			t.Errorf("Unable to parse %s: %v", b, err)
+		}
+		if parsed != b {
+			t.Errorf("%s parsed as %s", b, parsed)
+		}
+	}
+	if b, err := ParseBand("as923"); err != nil || b != AS923Band {
+		t.Errorf("Expected AS923 to be an alias for AS923-1 (%s, %v)", b, err)
+	}
+	if _, err := ParseBand("XX999"); err == nil {
+		t.Error("Expected unknown band name to fail")
+	}
+	if FrequencyBandType(200).String() != "unknown(200)" {
+		t.Errorf("Unexpected name for unknown band: %s", FrequencyBandType(200))
+	}
+}
+
+func TestBandFrequencyRange(t *testing.T) {
+	for b := range bandNames {
+		plan, err := NewBand(b)
+		if err != nil {
+			continue
+		}
+		config := plan.Configuration()
+		frequencies := append([]float32{config.RX2Frequency}, config.BeaconFrequencies...)
+		frequencies = append(frequencies, config.PingSlotFrequencies...)
+		frequencies = append(frequencies, config.MandatoryEndDeviceChannels...)
+		for _, f := range frequencies {
+			if !config.Contains(f) {
+				t.Errorf("%s should contain %.3f MHz", b, f)
+			}
+		}
+		if config.Contains(config.MinFrequency-0.1) || config.Contains(config.MaxFrequency+0.1) {
+			t.Errorf("%s should not contain frequencies outside %.3f-%.3f MHz", b, config.MinFrequency, config.MaxFrequency)
+		}
+	}
+}
+
+func TestDownlinkOnlyDataRates(t *testing.T) {
+	us915, _ := NewBand(US915Band)
+	if us915.Configuration().DownlinkOnly(4) || !us915.Configuration().DownlinkOnly(8) || !us915.Configuration().DownlinkOnly(13) {
+		t.Fatal("DR8-13 should be downlink only in US915")
+	}
+	eu868, _ := NewBand(EU868Band)
+	if eu868.Configuration().DownlinkOnly(7) {
+		t.Fatal("EU868 has no downlink only data rates")
+	}
+}
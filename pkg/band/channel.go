@@ -0,0 +1,92 @@
+package band
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Channel is an extra uplink channel for bands with dynamic channel plans,
+// ie channels that are added to the default channels through the CFList in
+// the JoinAccept message or the NewChannelReq MAC command.
+type Channel struct {
+	Frequency         float32 `json:"frequency"`                    // Uplink frequency in MHz
+	DownlinkFrequency float32 `json:"downlink_frequency,omitempty"` // RX1 frequency in MHz (via DlChannelReq). 0 means the uplink frequency
+}
+
+// RX1Frequency returns the frequency for the first receive window
+func (c Channel) RX1Frequency() float32 {
+	if c.DownlinkFrequency == 0 {
+		return c.Frequency
+	}
+	return c.DownlinkFrequency
+}
+
+// String returns the channel as <uplink> or <uplink>:<downlink>
+func (c Channel) String() string {
+	if c.DownlinkFrequency == 0 {
+		return strconv.FormatFloat(float64(c.Frequency), 'f', -1, 32)
+	}
+	return fmt.Sprintf("%s:%s", strconv.FormatFloat(float64(c.Frequency), 'f', -1, 32),
+		strconv.FormatFloat(float64(c.DownlinkFrequency), 'f', -1, 32))
+}
+
+// ParseChannel parses a channel on the format <uplink> or <uplink>:<downlink>.
+// The frequencies are in MHz.
+func ParseChannel(s string) (Channel, error) {
+	fields := strings.Split(strings.TrimSpace(s), ":")
+	if len(fields) > 2 {
+		return Channel{}, fmt.Errorf("invalid channel %q. Format is <uplink> or <uplink>:<downlink>", s)
+	}
+	var ret Channel
+	for i, v := range fields {
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 32)
+		if err != nil || f <= 0 {
+			return Channel{}, fmt.Errorf("invalid frequency %q in channel %q", v, s)
+		}
+		if i == 0 {
+			ret.Frequency = float32(f)
+			continue
+		}
+		ret.DownlinkFrequency = float32(f)
+	}
+	return ret, nil
+}
+
+// DynamicChannels returns true if the band uses a dynamic channel plan, ie
+// extra channels can be added with the CFList and NewChannelReq.
+func DynamicChannels(plan FrequencyPlan) bool {
+	if plan == nil {
+		return false
+	}
+	if _, fixed := plan.(FixedChannelPlan); fixed {
+		return false
+	}
+	return plan.Configuration().SupportsJoinAcceptCFList
+}
+
+// UplinkChannel returns the channel number for an uplink frequency, ie the
+// TxCh field in the LoRaWAN 1.1 MIC. The channel numbers are fixed in US915
+// and AU915 (0-71) and CN470 (0-95). In bands with dynamic channel plans the
+// default channels come first, followed by the extra channels set up on the
+// device.
+func UplinkChannel(plan FrequencyPlan, extra []Channel, frequency float32) (int, error) {
+	switch p := plan.(type) {
+	case FixedChannelPlan:
+		return p.UplinkChannel(frequency)
+	case CN470:
+		return p.uplinkChannel(frequency)
+	}
+	defaults := plan.Configuration().MandatoryEndDeviceChannels
+	for i, f := range defaults {
+		if roundKHz(f) == roundKHz(frequency) {
+			return i, nil
+		}
+	}
+	for i, ch := range extra {
+		if ch.Frequency != 0 && roundKHz(ch.Frequency) == roundKHz(frequency) {
+			return len(defaults) + i, nil
+		}
+	}
+	return 0, fmt.Errorf("%.4f MHz isn't an uplink channel", frequency)
+}
@@ -0,0 +1,76 @@
+package band
+
+import "testing"
+
+func TestParseChannel(t *testing.T) {
+	ch, err := ParseChannel("867.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch.Frequency != 867.1 || ch.DownlinkFrequency != 0 || ch.RX1Frequency() != 867.1 {
+		t.Fatalf("Unexpected channel: %+v", ch)
+	}
+	if ch.String() != "867.1" {
+		t.Fatalf("Unexpected string: %s", ch.String())
+	}
+	ch, err = ParseChannel(" 867.3:869.525 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch.Frequency != 867.3 || ch.DownlinkFrequency != 869.525 || ch.RX1Frequency() != 869.525 {
+		t.Fatalf("Unexpected channel: %+v", ch)
+	}
+	if ch.String() != "867.3:869.525" {
+		t.Fatalf("Unexpected string: %s", ch.String())
+	}
+	for _, s := range []string{"", "abc", "867.1:", "867.1:868.1:869.1", "-1"} {
+		if _, err := ParseChannel(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
+
+func TestDynamicChannels(t *testing.T) {
+	for _, b := range []FrequencyBandType{EU868Band, EU433Band, AS923Band, IN865Band, KR920Band} {
+		plan, _ := NewBand(b)
+		if !DynamicChannels(plan) {
+			t.Errorf("Expected dynamic channels for %s", b)
+		}
+	}
+	for _, b := range []FrequencyBandType{US915Band, AU915Band, CN470Band} {
+		plan, _ := NewBand(b)
+		if DynamicChannels(plan) {
+			t.Errorf("Did not expect dynamic channels for %s", b)
+		}
+	}
+	if DynamicChannels(nil) {
+		t.Error("Did not expect dynamic channels without a band")
+	}
+}
+
+func TestUplinkChannelNumber(t *testing.T) {
+	eu868, _ := NewBand(EU868Band)
+	us915, _ := NewBand(US915Band)
+	cn470, _ := NewBand(CN470Band)
+	extra := []Channel{{Frequency: 867.1}, {}, {Frequency: 867.5, DownlinkFrequency: 869.525}}
+	tests := []struct {
+		plan      FrequencyPlan
+		frequency float32
+		channel   int
+	}{
+		{eu868, 868.1, 0}, {eu868, 868.5, 2}, {eu868, 867.1, 3}, {eu868, 867.5, 5},
+		{us915, 902.3, 0}, {us915, 904.5, 11}, {us915, 903.0, 64}, {us915, 904.6, 65},
+		{cn470, 470.3, 0}, {cn470, 489.3, 95},
+	}
+	for _, test := range tests {
+		ch, err := UplinkChannel(test.plan, extra, test.frequency)
+		if err != nil || ch != test.channel {
+			t.Errorf("Expected channel %d for %.3f MHz in %s but got %d (%v)", test.channel, test.frequency, test.plan.Name(), ch, err)
+		}
+	}
+	for _, f := range []float32{867.3, 869.525} {
+		if _, err := UplinkChannel(eu868, extra, f); err == nil {
+			t.Errorf("Expected error for %.3f MHz", f)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package band
+
+import (
+	"fmt"
+	"math"
+)
+
+// Number of uplink channels in the bands with fixed channel plans. Channel
+// 0-63 are the 125 kHz channels and channel 64-71 are the 500 kHz channels.
+const (
+	fixedChannels125kHz = 64
+	fixedChannels       = 72
+)
+
+// ChannelMask is the set of enabled uplink channels for bands with fixed
+// channel plans, ie US915 and AU915. Bit n in ChannelMask[i] is channel 16*i+n.
+// The layout is the same as the channel mask in the CFList.
+type ChannelMask [5]uint16
+
+// Set enables a channel in the mask
+func (c *ChannelMask) Set(channel int) {
+	if channel < 0 || channel >= fixedChannels {
+		return
+	}
+	c[channel/16] |= 1 << uint(channel%16)
+}
+
+// Enabled returns true if the channel is enabled
+func (c ChannelMask) Enabled(channel int) bool {
+	if channel < 0 || channel >= fixedChannels {
+		return false
+	}
+	return c[channel/16]&(1<<uint(channel%16)) != 0
+}
+
+// Empty returns true if no channels are enabled
+func (c ChannelMask) Empty() bool {
+	return c == ChannelMask{}
+}
+
+// LinkADRMask is the channel mask and channel mask control fields for a
+// single LinkADRReq command
+type LinkADRMask struct {
+	ChMaskCntl uint8
+	ChMask     uint16
+}
+
+// LinkADRMasks returns the sequence of channel masks that must be sent in a
+// block of LinkADRReq commands to enable the channels in the mask. ChMaskCntl
+// 6 and 7 turns all of the 125 kHz channels on or off and sets the 500 kHz
+// channels. ChMaskCntl 0-3 sets 16 of the 125 kHz channels [RP 2.5.5].
+func (c ChannelMask) LinkADRMasks() []LinkADRMask {
+	all := true
+	for i := 0; i < 4; i++ {
+		if c[i] != 0xFFFF {
+			all = false
+		}
+	}
+	// Only the 500 kHz channels (64-71) are in the last mask
+	channels500kHz := c[4] & 0x00FF
+	if all {
+		return []LinkADRMask{{ChMaskCntl: 6, ChMask: channels500kHz}}
+	}
+	ret := []LinkADRMask{{ChMaskCntl: 7, ChMask: channels500kHz}}
+	for i := 0; i < 4; i++ {
+		if c[i] != 0 {
+			ret = append(ret, LinkADRMask{ChMaskCntl: uint8(i), ChMask: c[i]})
+		}
+	}
+	return ret
+}
+
+// FixedChannelPlan is implemented by the bands with a fixed set of 72 uplink
+// channels. The end-devices are told which channels to use through channel
+// masks in the CFList and LinkADRReq commands.
+type FixedChannelPlan interface {
+	// UplinkChannel returns the uplink channel (0-71) for a frequency
+	UplinkChannel(frequency float32) (int, error)
+}
+
+// NewChannelMask builds a channel mask with the uplink frequencies (in MHz)
+// for a band with a fixed channel plan. Frequencies that aren't uplink
+// channels in the band are ignored. False is returned if the band doesn't use
+// a fixed channel plan or none of the frequencies are uplink channels.
+func NewChannelMask(plan FrequencyPlan, frequencies []float32) (ChannelMask, bool) {
+	var mask ChannelMask
+	fixed, ok := plan.(FixedChannelPlan)
+	if !ok {
+		return mask, false
+	}
+	for _, f := range frequencies {
+		ch, err := fixed.UplinkChannel(f)
+		if err != nil {
+			continue
+		}
+		mask.Set(ch)
+	}
+	return mask, !mask.Empty()
+}
+
+// fixedUplinkChannel returns the uplink channel for a frequency in a band with
+// 64 125 kHz channels spaced 200 kHz apart and 8 500 kHz channels spaced
+// 1.6 MHz apart.
+func fixedUplinkChannel(frequency float32, first125kHz float64, first500kHz float64) (int, error) {
+	if ch := channelIndex(frequency, float32(first125kHz), 0.2); ch >= 0 && ch < fixedChannels125kHz &&
+		channelFrequency(first125kHz, 0.2, ch) == roundKHz(frequency) {
+		return ch, nil
+	}
+	if ch := channelIndex(frequency, float32(first500kHz), 1.6); ch >= 0 && ch < fixedChannels-fixedChannels125kHz &&
+		channelFrequency(first500kHz, 1.6, ch) == roundKHz(frequency) {
+		return fixedChannels125kHz + ch, nil
+	}
+	return 0, fmt.Errorf("%.4f MHz isn't an uplink channel", frequency)
+}
+
+// roundKHz rounds a frequency (in MHz) to the nearest kHz
+func roundKHz(frequency float32) float32 {
+	return float32(math.Round(float64(frequency)*1000) / 1000)
+}
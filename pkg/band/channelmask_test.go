@@ -0,0 +1,75 @@
+package band
+
+import "testing"
+
+func TestUplinkChannel(t *testing.T) {
+	us := newUS902()
+	au := newAU915()
+	tests := []struct {
+		plan      FixedChannelPlan
+		frequency float32
+		channel   int
+	}{
+		{us, 902.3, 0}, {us, 903.9, 8}, {us, 905.3, 15}, {us, 914.9, 63},
+		{us, 903.0, 64}, {us, 904.6, 65}, {us, 914.2, 71},
+		{au, 915.2, 0}, {au, 916.8, 8}, {au, 927.8, 63},
+		{au, 915.9, 64}, {au, 917.5, 65}, {au, 927.1, 71},
+	}
+	for _, test := range tests {
+		ch, err := test.plan.UplinkChannel(test.frequency)
+		if err != nil {
+			t.Fatalf("Got error for %.1f MHz: %v", test.frequency, err)
+		}
+		if ch != test.channel {
+			t.Errorf("Expected channel %d for %.1f MHz but got %d", test.channel, test.frequency, ch)
+		}
+	}
+	for _, f := range []float32{902.2, 902.4, 915.0, 923.3} {
+		if ch, err := us.UplinkChannel(f); err == nil {
+			t.Errorf("Expected error for %.1f MHz but got channel %d", f, ch)
+		}
+	}
+}
+
+func TestNewChannelMask(t *testing.T) {
+	us := newUS902()
+	mask, ok := NewChannelMask(us, []float32{903.9, 904.1, 904.3, 904.5, 904.7, 904.9, 905.1, 905.3, 904.6, 868.1})
+	if !ok {
+		t.Fatal("Expected a channel mask for US915")
+	}
+	if mask != (ChannelMask{0xFF00, 0, 0, 0, 0x0002}) {
+		t.Fatalf("Unexpected mask: %04x", mask)
+	}
+	if !mask.Enabled(8) || !mask.Enabled(65) || mask.Enabled(0) || mask.Enabled(72) {
+		t.Fatal("Unexpected enabled channels")
+	}
+	if _, ok := NewChannelMask(us, []float32{868.1}); ok {
+		t.Fatal("Expected no mask without uplink channels")
+	}
+	if _, ok := NewChannelMask(newEU868(), []float32{868.1}); ok {
+		t.Fatal("Expected no mask for EU868")
+	}
+}
+
+func TestLinkADRMasks(t *testing.T) {
+	mask := ChannelMask{0xFF00, 0, 0, 0, 0x0002}
+	masks := mask.LinkADRMasks()
+	if len(masks) != 2 {
+		t.Fatalf("Expected 2 masks but got %d", len(masks))
+	}
+	if masks[0] != (LinkADRMask{ChMaskCntl: 7, ChMask: 0x0002}) || masks[1] != (LinkADRMask{ChMaskCntl: 0, ChMask: 0xFF00}) {
+		t.Fatalf("Unexpected masks: %v", masks)
+	}
+
+	mask = ChannelMask{0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0x00FF}
+	masks = mask.LinkADRMasks()
+	if len(masks) != 1 || masks[0] != (LinkADRMask{ChMaskCntl: 6, ChMask: 0x00FF}) {
+		t.Fatalf("Unexpected masks: %v", masks)
+	}
+
+	mask = ChannelMask{0, 0x00FF, 0, 0x0F00, 0}
+	masks = mask.LinkADRMasks()
+	if len(masks) != 3 || masks[1].ChMaskCntl != 1 || masks[2].ChMaskCntl != 3 {
+		t.Fatalf("Unexpected masks: %v", masks)
+	}
+}
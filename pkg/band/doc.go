@@ -1,6 +1,7 @@
 /*Package band defines the frequency bands used by the LoRaWAN package.
 
-Currently only the EU868 and US915 bands are defined.
+The EU868, US915, EU433, AS923 (groups 1-4), AU915, CN470, IN865 and KR920
+bands are defined.
 */
 package band
 
@@ -0,0 +1,33 @@
+package band
+
+import "fmt"
+
+// SubBand is a regulatory sub-band with a duty cycle limit for the gateways
+// transmitting in it, ie the sub-bands in ETSI EN 300 220 for the EU bands.
+type SubBand struct {
+	MinFrequency float32 // Lowest frequency in the sub-band (in MHz)
+	MaxFrequency float32 // Highest frequency in the sub-band (in MHz), not included
+	DutyCycle    float32 // Max duty cycle, ie 0.01 for 1%. 0 means no limit
+}
+
+// Contains returns true if the frequency is inside the sub-band
+func (s SubBand) Contains(frequency float32) bool {
+	return frequency >= s.MinFrequency && frequency < s.MaxFrequency
+}
+
+// String returns the frequency range for the sub-band
+func (s SubBand) String() string {
+	return fmt.Sprintf("%.3f-%.3f MHz", s.MinFrequency, s.MaxFrequency)
+}
+
+// SubBand returns the sub-band for a frequency. Bands with duty cycle limits
+// have sub-bands for the whole band so only frequencies outside the band and
+// bands without limits get an empty sub-band without a duty cycle limit.
+func (c *Configuration) SubBand(frequency float32) SubBand {
+	for _, s := range c.SubBands {
+		if s.Contains(frequency) {
+			return s
+		}
+	}
+	return SubBand{}
+}
@@ -0,0 +1,51 @@
+package band
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubBand(t *testing.T) {
+	eu := newEU868()
+	config := eu.Configuration()
+	tests := []struct {
+		frequency float32
+		dutyCycle float32
+	}{
+		{868.1, 0.01}, {868.5, 0.01}, {867.1, 0.01}, {864.1, 0.001},
+		{869.525, 0.1}, {869.8, 0.01}, {868.8, 0.001}, {915.2, 0},
+		{868.65, 0.001}, {869.3, 0.001}, {869.675, 0.001}, {863.0, 0.001}, {869.999, 0.01},
+	}
+	for _, test := range tests {
+		s := config.SubBand(test.frequency)
+		if s.DutyCycle != test.dutyCycle {
+			t.Errorf("Expected duty cycle %f for %.3f MHz but got %f (%s)", test.dutyCycle, test.frequency, s.DutyCycle, s)
+		}
+		if test.dutyCycle != 0 && !s.Contains(test.frequency) {
+			t.Errorf("Sub-band %s should contain %.3f MHz", s, test.frequency)
+		}
+	}
+	if config.SubBand(868.1) == config.SubBand(869.525) {
+		t.Error("RX1 and RX2 frequencies should be in different sub-bands")
+	}
+	// The sub-bands cover the whole band
+	for f := float32(863.0); f < 870.0; f += 0.025 {
+		if config.SubBand(f).DutyCycle == 0 {
+			t.Errorf("No duty cycle limit for %.3f MHz", f)
+		}
+	}
+	if s := config.SubBand(869.525); s.String() != "869.400-869.650 MHz" {
+		t.Errorf("Unexpected string for sub-band: %s", s)
+	}
+
+	as := newAS923(1)
+	if as.Configuration().MaxDwellTime != 0 {
+		t.Errorf("Expected no dwell time for AS923 but got %v", as.Configuration().MaxDwellTime)
+	}
+	if dt := as.WithDwellTime().Configuration().MaxDwellTime; dt != 400*time.Millisecond {
+		t.Errorf("Expected 400ms dwell time for AS923 with dwell time but got %v", dt)
+	}
+	if s := as.Configuration().SubBand(923.2); s.DutyCycle != 0 {
+		t.Errorf("AS923 should have no duty cycle limit but got %s", s)
+	}
+}
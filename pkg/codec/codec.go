@@ -0,0 +1,146 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// costLimit is the maximum cost for evaluating an expression. This stops
+// runaway expressions.
+const costLimit = 100000
+
+// maxCacheSize is the maximum number of codecs in the cache
+const maxCacheSize = 256
+
+// Codec is a payload codec for an application. The decoder is a CEL
+// expression with the variables fPort (int) and payload (bytes) that
+// returns a map. The encoder is a CEL expression with the variables fPort
+// (int) and object (map) that returns bytes. Integers are read from the
+// payload with payload.uint8(offset), payload.int16(offset) and so on (big
+// endian, little endian with the "le" suffix) and are written with
+// packUint8(value), packInt16(value) and so on. Bytes are joined with +.
+type Codec struct {
+	decoder cel.Program
+	encoder cel.Program
+}
+
+func newEnv(opts ...cel.EnvOption) (*cel.Env, error) {
+	opts = append(opts, ext.Strings(), ext.Encoders(), ext.Math())
+	return cel.NewEnv(append(opts, payloadFunctions()...)...)
+}
+
+func compile(env *cel.Env, expr string, resultType *cel.Type) (cel.Program, error) {
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if !resultType.IsAssignableType(ast.OutputType()) && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression returns %s, not %s", ast.OutputType(), resultType)
+	}
+	return env.Program(ast, cel.CostLimit(costLimit))
+}
+
+// New compiles the decoder and encoder expressions. Either of the
+// expressions can be blank.
+func New(decoder, encoder string) (*Codec, error) {
+	ret := &Codec{}
+	if decoder != "" {
+		env, err := newEnv(cel.Variable("fPort", cel.IntType), cel.Variable("payload", cel.BytesType))
+		if err != nil {
+			return nil, err
+		}
+		if ret.decoder, err = compile(env, decoder, cel.MapType(cel.StringType, cel.DynType)); err != nil {
+			return nil, fmt.Errorf("invalid decoder: %v", err)
+		}
+	}
+	if encoder != "" {
+		env, err := newEnv(cel.Variable("fPort", cel.IntType), cel.Variable("object", cel.MapType(cel.StringType, cel.DynType)))
+		if err != nil {
+			return nil, err
+		}
+		if ret.encoder, err = compile(env, encoder, cel.BytesType); err != nil {
+			return nil, fmt.Errorf("invalid encoder: %v", err)
+		}
+	}
+	return ret, nil
+}
+
+// HasDecoder returns true if the codec can decode payloads
+func (c *Codec) HasDecoder() bool {
+	return c.decoder != nil
+}
+
+// HasEncoder returns true if the codec can encode objects
+func (c *Codec) HasEncoder() bool {
+	return c.encoder != nil
+}
+
+// Decode decodes an uplink payload into an object
+func (c *Codec) Decode(fPort uint8, payload []byte) (*structpb.Struct, error) {
+	if c.decoder == nil {
+		return nil, errors.New("no decoder")
+	}
+	val, _, err := c.decoder.Eval(map[string]interface{}{"fPort": int64(fPort), "payload": payload})
+	if err != nil {
+		return nil, err
+	}
+	obj, err := val.ConvertToNative(reflect.TypeOf(&structpb.Struct{}))
+	if err != nil {
+		return nil, fmt.Errorf("decoder must return a map: %v", err)
+	}
+	return obj.(*structpb.Struct), nil
+}
+
+// Encode encodes an object into a downlink payload
+func (c *Codec) Encode(fPort uint8, object *structpb.Struct) ([]byte, error) {
+	if c.encoder == nil {
+		return nil, errors.New("no encoder")
+	}
+	val, _, err := c.encoder.Eval(map[string]interface{}{"fPort": int64(fPort), "object": object.AsMap()})
+	if err != nil {
+		return nil, err
+	}
+	buf, err := val.ConvertToNative(reflect.TypeOf([]byte{}))
+	if err != nil {
+		return nil, fmt.Errorf("encoder must return bytes: %v", err)
+	}
+	return buf.([]byte), nil
+}
+
+// Cache holds the compiled codecs. Compiling the expressions is expensive
+// so the codecs are reused as long as the expressions are unchanged.
+type Cache struct {
+	mutex  *sync.Mutex
+	codecs map[[2]string]*Codec
+}
+
+// NewCache creates a new codec cache
+func NewCache() *Cache {
+	return &Cache{mutex: &sync.Mutex{}, codecs: make(map[[2]string]*Codec)}
+}
+
+// Get returns the codec for the expressions. The codec is compiled if it
+// isn't in the cache.
+func (c *Cache) Get(decoder, encoder string) (*Codec, error) {
+	key := [2]string{decoder, encoder}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if codec, ok := c.codecs[key]; ok {
+		return codec, nil
+	}
+	codec, err := New(decoder, encoder)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.codecs) >= maxCacheSize {
+		c.codecs = make(map[[2]string]*Codec)
+	}
+	c.codecs[key] = codec
+	return codec, nil
+}
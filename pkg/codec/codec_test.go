@@ -0,0 +1,87 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestDecode(t *testing.T) {
+	c, err := New(`fPort == 1 ? {"temperature": double(payload.int16(0)) / 10.0, "humidity": payload.uint8(2), "counter": payload.uint32le(3)} : {"raw": base64.encode(payload)}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.HasDecoder() || c.HasEncoder() {
+		t.Fatal("Codec should only have a decoder")
+	}
+	obj, err := c.Decode(1, []byte{0xFF, 0x9C, 0x32, 0x01, 0x00, 0x00, 0x00})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := obj.AsMap()
+	if m["temperature"] != -10.0 || m["humidity"] != 50.0 || m["counter"] != 1.0 {
+		t.Fatalf("Incorrect decoded object: %v", m)
+	}
+	if obj, err = c.Decode(2, []byte{1, 2}); err != nil || obj.AsMap()["raw"] != "AQI=" {
+		t.Fatalf("Incorrect decoded object for port 2: %v (%v)", obj, err)
+	}
+	// Out of range reads are errors
+	if _, err := c.Decode(1, []byte{1}); err == nil {
+		t.Fatal("Expected error for short payload")
+	}
+	if _, err := c.Encode(1, &structpb.Struct{}); err == nil {
+		t.Fatal("Expected error when there's no encoder")
+	}
+}
+
+func TestEncode(t *testing.T) {
+	c, err := New("", `packUint8(fPort) + packInt16(int(object.setpoint * 10.0)) + packUint32le(int(object.interval)) + b"\x01".slice(0, 1)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := structpb.NewStruct(map[string]interface{}{"setpoint": -1.5, "interval": 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := c.Encode(2, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{0x02, 0xFF, 0xF1, 0x2C, 0x01, 0x00, 0x00, 0x01}
+	if !bytes.Equal(buf, expected) {
+		t.Fatalf("Incorrect payload: %x (expected %x)", buf, expected)
+	}
+	// Missing fields are errors
+	if _, err := c.Encode(2, &structpb.Struct{}); err == nil {
+		t.Fatal("Expected error for missing fields")
+	}
+}
+
+func TestInvalidCodec(t *testing.T) {
+	for _, v := range [][2]string{
+		{"payload.uint8(", ""},
+		{"payload", ""},
+		{"", `{"a": 1}`},
+		{"", "unknown"},
+	} {
+		if _, err := New(v[0], v[1]); err == nil {
+			t.Fatalf("Expected error for %q/%q", v[0], v[1])
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	cache := NewCache()
+	first, err := cache.Get(`{"a": payload.uint8(0)}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := cache.Get(`{"a": payload.uint8(0)}`, "")
+	if err != nil || first != second {
+		t.Fatal("Expected cached codec")
+	}
+	if _, err := cache.Get("payload.", ""); err == nil {
+		t.Fatal("Expected error for invalid expression")
+	}
+}
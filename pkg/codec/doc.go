@@ -0,0 +1,4 @@
+// Package codec contains the payload codecs for the applications. The
+// codecs are CEL expressions that decode the uplink payloads into JSON
+// objects and encode JSON objects into downlink payloads.
+package codec
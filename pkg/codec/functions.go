@@ -0,0 +1,113 @@
+package codec
+
+import (
+	"encoding/binary"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// integerReader reads an integer from a byte slice at an offset
+type integerReader struct {
+	name   string
+	size   int
+	signed bool
+	order  binary.ByteOrder
+}
+
+var integerReaders = []integerReader{
+	{"uint8", 1, false, binary.BigEndian},
+	{"int8", 1, true, binary.BigEndian},
+	{"uint16", 2, false, binary.BigEndian},
+	{"int16", 2, true, binary.BigEndian},
+	{"uint32", 4, false, binary.BigEndian},
+	{"int32", 4, true, binary.BigEndian},
+	{"uint16le", 2, false, binary.LittleEndian},
+	{"int16le", 2, true, binary.LittleEndian},
+	{"uint32le", 4, false, binary.LittleEndian},
+	{"int32le", 4, true, binary.LittleEndian},
+}
+
+func (r integerReader) read(buf []byte) int64 {
+	switch r.size {
+	case 1:
+		if r.signed {
+			return int64(int8(buf[0]))
+		}
+		return int64(buf[0])
+	case 2:
+		if r.signed {
+			return int64(int16(r.order.Uint16(buf)))
+		}
+		return int64(r.order.Uint16(buf))
+	default:
+		if r.signed {
+			return int64(int32(r.order.Uint32(buf)))
+		}
+		return int64(r.order.Uint32(buf))
+	}
+}
+
+func (r integerReader) write(v int64) []byte {
+	buf := make([]byte, r.size)
+	switch r.size {
+	case 1:
+		buf[0] = byte(v)
+	case 2:
+		r.order.PutUint16(buf, uint16(v))
+	default:
+		r.order.PutUint32(buf, uint32(v))
+	}
+	return buf
+}
+
+// readFunction is the member function that reads an integer from the
+// payload, ie payload.uint16(2)
+func (r integerReader) readFunction() cel.EnvOption {
+	return cel.Function(r.name,
+		cel.MemberOverload("bytes_"+r.name+"_int", []*cel.Type{cel.BytesType, cel.IntType}, cel.IntType,
+			cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+				buf := []byte(lhs.(types.Bytes))
+				offset := int64(rhs.(types.Int))
+				if offset < 0 || offset+int64(r.size) > int64(len(buf)) {
+					return types.NewErr("%s: offset %d out of range for %d bytes", r.name, offset, len(buf))
+				}
+				return types.Int(r.read(buf[offset:]))
+			})))
+}
+
+// packFunction is the function that encodes an integer, ie packUint16(x).
+// Signed and unsigned values are encoded the same way.
+func (r integerReader) packFunction() cel.EnvOption {
+	name := "pack" + string(r.name[0]-'a'+'A') + r.name[1:]
+	return cel.Function(name,
+		cel.Overload(name+"_int", []*cel.Type{cel.IntType}, cel.BytesType,
+			cel.UnaryBinding(func(v ref.Val) ref.Val {
+				return types.Bytes(r.write(int64(v.(types.Int))))
+			})))
+}
+
+// sliceFunction is the member function that returns a part of the
+// payload, ie payload.slice(2, 4)
+func sliceFunction() cel.EnvOption {
+	return cel.Function("slice",
+		cel.MemberOverload("bytes_slice_int_int", []*cel.Type{cel.BytesType, cel.IntType, cel.IntType}, cel.BytesType,
+			cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+				buf := []byte(args[0].(types.Bytes))
+				start, end := int64(args[1].(types.Int)), int64(args[2].(types.Int))
+				if start < 0 || end < start || end > int64(len(buf)) {
+					return types.NewErr("slice: range %d-%d out of range for %d bytes", start, end, len(buf))
+				}
+				return types.Bytes(buf[start:end])
+			})))
+}
+
+// payloadFunctions returns the functions for reading and writing payloads
+func payloadFunctions() []cel.EnvOption {
+	ret := []cel.EnvOption{sliceFunction()}
+	for _, r := range integerReaders {
+		ret = append(ret, r.readFunction(), r.packFunction())
+	}
+	return ret
+}
@@ -7,7 +7,7 @@ import (
 
 	"github.com/lab5e/lospan/pkg/apiserver"
 	"github.com/lab5e/lospan/pkg/events/gwevents"
-	"github.com/lab5e/lospan/pkg/gateway"
+	"github.com/lab5e/lospan/pkg/integration"
 	"github.com/lab5e/lospan/pkg/keys"
 	"github.com/lab5e/lospan/pkg/lg"
 	"github.com/lab5e/lospan/pkg/pb/lospan"
@@ -21,12 +21,14 @@ import (
 // LoRaServer is the main Congress server process. It will launch several
 // endpoints and a processing pipeline.
 type LoRaServer struct {
-	config     *server.Parameters
-	context    *server.Context
-	forwarder  processor.GwForwarder
-	pipeline   *processor.Pipeline
-	terminator chan bool
-	listenAddr net.Addr // gRPC listener
+	config      *server.Parameters
+	context     *server.Context
+	forwarder   processor.GwForwarder
+	pipeline    *processor.Pipeline
+	integration *integration.MQTTIntegration   // MQTT integration. Nil if it isn't configured
+	webhooks    *integration.WebhookDispatcher // Webhooks. Nil if there's no storage
+	terminator  chan bool
+	listenAddr  net.Addr // gRPC listener
 }
 
 func (c *LoRaServer) checkConfig() error {
@@ -64,6 +66,7 @@ func NewLoRaServer(config *server.Parameters) (*LoRaServer, error) {
 
 	appRouter := server.NewEventRouter[protocol.EUI, *server.PayloadMessage](5)
 	gwEventRouter := server.NewEventRouter[protocol.EUI, gwevents.GwEvent](5)
+	dutyCycle := server.NewDutyCycleLedger()
 	c.context = &server.Context{
 		Storage:       datastore,
 		Terminator:    make(chan bool),
@@ -72,18 +75,28 @@ func NewLoRaServer(config *server.Parameters) (*LoRaServer, error) {
 		KeyGenerator:  &keyGenerator,
 		GwEventRouter: &gwEventRouter,
 		AppRouter:     &appRouter,
+		DutyCycle:     &dutyCycle,
 	}
 
-	lg.Info("Launching generic packet forwarder on port %d...", config.GatewayPort)
-	c.forwarder = gateway.NewGenericPacketForwarder(c.config.GatewayPort, datastore, c.context)
+	c.forwarder, err = processor.NewConfiguredForwarder(c.context)
+	if err != nil {
+		lg.Error("Unable to create gateway forwarders: %v", err)
+		return nil, err
+	}
 	c.pipeline = processor.NewPipeline(c.context, c.forwarder)
+	if config.AppMQTTBroker != "" {
+		c.integration = integration.NewMQTTIntegration(c.context)
+	}
+	if datastore != nil {
+		c.webhooks = integration.NewWebhookDispatcher(c.context)
+	}
 
 	listener, err := net.Listen("tcp", config.GRPCEndpoint)
 	if err != nil {
 		lg.Error("Error creating listener: %v", err)
 		return nil, err
 	}
-	lospanSvc, err := apiserver.New(c.context.Storage, c.context.KeyGenerator, &appRouter)
+	lospanSvc, err := apiserver.New(c.context.Storage, c.context.KeyGenerator, &appRouter, &gwEventRouter, config, &dutyCycle)
 	if err != nil {
 		lg.Error("Error creatig lospan service: %v", err)
 		return nil, err
@@ -107,6 +120,18 @@ func (c *LoRaServer) Start() error {
 	lg.Debug("Start Congress LoRa Server")
 	c.pipeline.Start()
 	go c.forwarder.Start()
+	if c.integration != nil {
+		if err := c.integration.Start(); err != nil {
+			lg.Error("Unable to start MQTT integration: %v", err)
+			return err
+		}
+	}
+	if c.webhooks != nil {
+		if err := c.webhooks.Start(); err != nil {
+			lg.Error("Unable to start webhooks: %v", err)
+			return err
+		}
+	}
 	return nil
 }
 
@@ -114,6 +139,12 @@ func (c *LoRaServer) Start() error {
 func (c *LoRaServer) Shutdown() error {
 	lg.Debug("Shutting down LoRa server")
 	c.forwarder.Stop()
+	if c.integration != nil {
+		c.integration.Stop()
+	}
+	if c.webhooks != nil {
+		c.webhooks.Stop()
+	}
 	c.context.Storage.Close()
 
 	return nil
@@ -1,32 +1,43 @@
 package gwevents
 
+import "time"
+
 type gwEventType string
 
+// Gateway event types
+const (
+	Inactive  gwEventType = "Inactive"  // The gateway has gone inactive
+	KeepAlive gwEventType = "KeepAlive" // Keepalive received from the gateway
+	Rx        gwEventType = "Rx"        // Data received from the gateway
+	Tx        gwEventType = "Tx"        // Data sent to the gateway
+)
+
 // GwEvent types are OOB events for the gateway. They will be sent
 // as a debugging aid for gateways. The gateway interface(s) forwards all
 // gateway events to a buffered channel which will distribute the events
 // to listeners.
 type GwEvent struct {
-	Type gwEventType `json:"event"`          // EventType holds the event type (see constants)
-	Data string      `json:"data,omitempty"` // The data sent or received from the gateway (if applicable)
+	Type      gwEventType `json:"event"`          // EventType holds the event type (see constants)
+	Data      string      `json:"data,omitempty"` // The data sent or received from the gateway (if applicable)
+	Timestamp time.Time   `json:"timestamp"`      // Time of event
 }
 
 // NewInactive creates a new inactive event
 func NewInactive() GwEvent {
-	return GwEvent{gwEventType("Inactive"), ""}
+	return GwEvent{Inactive, "", time.Now()}
 }
 
 // NewKeepAlive creates a new keepalive event
 func NewKeepAlive() GwEvent {
-	return GwEvent{gwEventType("KeepAlive"), ""}
+	return GwEvent{KeepAlive, "", time.Now()}
 }
 
 // NewRx creates a new Rx event for the gateway
 func NewRx(data string) GwEvent {
-	return GwEvent{gwEventType("Rx"), data}
+	return GwEvent{Rx, data, time.Now()}
 }
 
 // NewTx creates a new Tx event for the gateway
 func NewTx(data string) GwEvent {
-	return GwEvent{gwEventType("Tx"), data}
+	return GwEvent{Tx, data, time.Now()}
 }
@@ -8,3 +8,16 @@ func TestEventCreation(t *testing.T) {
 	NewTx("some data")
 	NewRx("some data")
 }
+
+func TestEventTypes(t *testing.T) {
+	if NewInactive().Type != Inactive || NewKeepAlive().Type != KeepAlive {
+		t.Fatal("Incorrect event type")
+	}
+	ev := NewRx("some data")
+	if ev.Type != Rx || ev.Data != "some data" || ev.Timestamp.IsZero() {
+		t.Fatalf("Incorrect Rx event: %+v", ev)
+	}
+	if NewTx("").Type != Tx {
+		t.Fatal("Incorrect event type")
+	}
+}
@@ -3,19 +3,21 @@ package frequency
 // Stub functions for frequency management.
 
 import (
+	"math"
+
+	"github.com/lab5e/lospan/pkg/band"
 	"github.com/lab5e/lospan/pkg/protocol"
 )
 
-// TODO: Replace with proper frequency management. These values are the defaults
-// for the EU868 band and won't work for other bands.
-
 // GetDLSettingsOTAA returns the DLSettings value returned during OTAA
-// join procedure. This returns the default values for now.
-func GetDLSettingsOTAA() protocol.DLSettings {
-	return protocol.DLSettings{
-		RX1DRoffset: 0,
-		RX2DataRate: 5,
+// join procedure, ie no RX1 data rate offset and the band's default RX2 data
+// rate.
+func GetDLSettingsOTAA(plan band.FrequencyPlan) protocol.DLSettings {
+	ret := protocol.DLSettings{RX1DRoffset: 0}
+	if plan != nil {
+		ret.RX2DataRate = plan.Configuration().RX2DataRate
 	}
+	return ret
 }
 
 // GetRxDelayOTAA returns the RxDelay parameter for OTAA join procedures. This
@@ -24,8 +26,24 @@ func GetRxDelayOTAA() uint8 {
 	return 1
 }
 
-// GetCFListOTAA returns the CFList type used during OTAA. It always returns
-// the default values.
-func GetCFListOTAA() protocol.CFList {
-	return protocol.CFList{}
+// GetCFListOTAA returns the CFList type used during OTAA. Bands with fixed
+// channel plans get a channel mask with the channels the gateway listens on.
+// Bands with dynamic channel plans get the frequencies of the extra channels.
+// Other bands get an empty list, ie the default channels.
+func GetCFListOTAA(plan band.FrequencyPlan, mask band.ChannelMask, channels []band.Channel) protocol.CFList {
+	if !mask.Empty() {
+		return protocol.CFList{Type: protocol.CFListChannelMask, ChMask: mask}
+	}
+	ret := protocol.CFList{Type: protocol.CFListFrequencies}
+	if !band.DynamicChannels(plan) {
+		return ret
+	}
+	for i, ch := range channels {
+		if i >= len(ret.Frequencies) {
+			break
+		}
+		// The CFList frequencies are in units of 100 Hz
+		ret.Frequencies[i] = uint32(math.Round(float64(ch.Frequency)*1e4)) * 100
+	}
+	return ret
 }
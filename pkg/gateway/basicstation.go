@@ -0,0 +1,528 @@
+package gateway
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/events/gwevents"
+	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+	"github.com/lab5e/lospan/pkg/storage"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// routerInfoPath is the endpoint the stations use to discover the
+	// traffic endpoint
+	routerInfoPath = "/router-info"
+	// trafficPath is the prefix for the traffic endpoint. The station EUI
+	// is appended to the path.
+	trafficPath = "/traffic/"
+	// pendingDownlinkExpiry is the time to wait for a dntxed message before
+	// a downlink is discarded. Class B downlinks might be scheduled up to a
+	// beacon period ahead.
+	pendingDownlinkExpiry = 5 * time.Minute
+)
+
+// station is a connected Basic Station
+type station struct {
+	eui  protocol.EUI
+	host string
+	port int
+	conn *websocket.Conn
+}
+
+// send marshals and sends a message to the station
+func (s *station) send(msg interface{}) error {
+	buf, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return websocket.Message.Send(s.conn, string(buf))
+}
+
+// pendingDownlink is a downlink that the station hasn't confirmed yet
+type pendingDownlink struct {
+	gatewayEUI protocol.EUI
+	txData     string                 // The downlink in the Semtech packet forwarder format
+	downlink   *server.PayloadMessage // The downlink event for the application payload
+	created    time.Time
+}
+
+// BasicStationForwarder is a gateway forwarder for gateways running the
+// LoRa Basic Station. The stations connect through a WebSocket to the
+// router info endpoint to discover the traffic endpoint, then connect to the
+// traffic endpoint where the uplinks and downlinks are exchanged. The
+// stations are configured with the gateway's frequency plan and channels.
+type BasicStationForwarder struct {
+	input    chan server.GatewayPacket // Input to the gateway, ie data that should be sent to the gateway
+	output   chan server.GatewayPacket // Output from the gateway; ie data received from the gateway
+	port     int                       // Server port to listen on
+	storage  *storage.Storage
+	context  *server.Context
+	monitor  gatewayMonitor
+	plans    gatewayPlans
+	mutex    *sync.Mutex // Mutex for the stations and pending maps
+	stations map[protocol.EUI]*station
+	pending  map[int64]pendingDownlink
+	nextDIID int64
+	closed   bool            // Set when the forwarder shuts down
+	handlers *sync.WaitGroup // Running traffic handlers
+}
+
+// NewBasicStationForwarder creates a new Basic Station forwarder listening
+// on a TCP port. Gateways are checked against the storage when they connect
+// unless the gateway checks are disabled in the configuration.
+func NewBasicStationForwarder(port int, storage *storage.Storage, context *server.Context) *BasicStationForwarder {
+	return &BasicStationForwarder{
+		input:    make(chan server.GatewayPacket),
+		output:   make(chan server.GatewayPacket),
+		port:     port,
+		storage:  storage,
+		context:  context,
+		monitor:  newGatewayMonitor(storage, context),
+		plans:    newGatewayPlans(storage, context.Config),
+		mutex:    &sync.Mutex{},
+		stations: make(map[protocol.EUI]*station),
+		pending:  make(map[int64]pendingDownlink),
+		handlers: &sync.WaitGroup{},
+	}
+}
+
+// Start launches the forwarder. It does not return until the forwarder
+// shuts down.
+func (b *BasicStationForwarder) Start() {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", b.port))
+	if err != nil {
+		lg.Error("Unable to listen on TCP port %d: %v", b.port, err)
+		return
+	}
+	lg.Info("Basic Station forwarder listening on port %d", b.port)
+
+	// The stations don't send an Origin header so it isn't checked
+	acceptAll := func(*websocket.Config, *http.Request) error { return nil }
+	mux := http.NewServeMux()
+	mux.Handle(routerInfoPath, websocket.Server{Handler: b.routerInfo, Handshake: acceptAll})
+	mux.Handle(trafficPath, websocket.Server{Handler: b.traffic, Handshake: acceptAll})
+	srv := &http.Server{Handler: mux}
+	go func() {
+		if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+			lg.Error("Basic Station forwarder terminated: %v", err)
+		}
+	}()
+	b.mainLoop(srv)
+}
+
+// Stop stops the forwarder and closes the channels
+func (b *BasicStationForwarder) Stop() {
+	close(b.input)
+}
+
+// Output returns the output channel for the gateway. A message will be sent
+// on this channel every time a station has sent an uplink.
+func (b *BasicStationForwarder) Output() <-chan server.GatewayPacket {
+	return b.output
+}
+
+// Input returns the input channel for the gateway. When a message is received
+// on this channel the message will be forwarded to the station.
+func (b *BasicStationForwarder) Input() chan<- server.GatewayPacket {
+	return b.input
+}
+
+func (b *BasicStationForwarder) mainLoop(srv *http.Server) {
+	livenessTicker := time.NewTicker(b.monitor.CheckInterval())
+	defer livenessTicker.Stop()
+	for {
+		select {
+		case <-livenessTicker.C:
+			b.checkStations()
+
+		case val, ok := <-b.input:
+			if !ok {
+				lg.Debug("Input channel for Basic Station forwarder closed. Terminating")
+				b.shutdown(srv)
+				return
+			}
+			b.sendDownlink(val)
+		}
+	}
+}
+
+// shutdown closes the server and the station connections. The output channel
+// is closed when all of the traffic handlers have terminated.
+func (b *BasicStationForwarder) shutdown(srv *http.Server) {
+	b.mutex.Lock()
+	b.closed = true
+	for _, s := range b.stations {
+		s.conn.Close()
+	}
+	b.mutex.Unlock()
+
+	srv.Close()
+	b.handlers.Wait()
+	close(b.output)
+}
+
+// checkStations marks the connected stations as seen, emits inactive events
+// for stations that have disconnected and discards stale downlinks.
+func (b *BasicStationForwarder) checkStations() {
+	b.mutex.Lock()
+	var connected []protocol.EUI
+	for eui := range b.stations {
+		connected = append(connected, eui)
+	}
+	for diid, p := range b.pending {
+		if time.Since(p.created) > pendingDownlinkExpiry {
+			lg.Warning("No dntxed for downlink %d to gateway %s", diid, p.gatewayEUI)
+			delete(b.pending, diid)
+		}
+	}
+	b.mutex.Unlock()
+
+	for _, eui := range connected {
+		b.monitor.Seen(eui)
+	}
+	b.monitor.CheckLiveness()
+}
+
+// checkGateway verifies that the gateway exists. Gateways with the strict IP
+// check must connect from the registered IP address.
+func (b *BasicStationForwarder) checkGateway(eui protocol.EUI, host string) error {
+	if b.context.Config == nil || b.context.Config.DisableGatewayChecks {
+		return nil
+	}
+	if b.storage == nil {
+		return fmt.Errorf("no storage for gateway lookup")
+	}
+	gw, err := b.storage.GetGateway(eui)
+	if err != nil {
+		return fmt.Errorf("unknown gateway %s", eui)
+	}
+	if gw.StrictIP && gw.IP.String() != host {
+		return fmt.Errorf("IP mismatch for gateway %s: %s (should be %s)", eui, host, gw.IP)
+	}
+	return nil
+}
+
+// remoteAddress returns the host and port for the connection
+func remoteAddress(conn *websocket.Conn) (string, int) {
+	host, portStr, err := net.SplitHostPort(conn.Request().RemoteAddr)
+	if err != nil {
+		return conn.Request().RemoteAddr, 0
+	}
+	port, _ := strconv.Atoi(portStr)
+	return host, port
+}
+
+// routerInfo handles the discovery requests from the stations. The response
+// contains the URI for the traffic endpoint.
+func (b *BasicStationForwarder) routerInfo(conn *websocket.Conn) {
+	defer conn.Close()
+
+	req := stationRouterInfoRequest{}
+	if err := websocket.JSON.Receive(conn, &req); err != nil {
+		lg.Info("Unable to read router info request from %s: %v", conn.Request().RemoteAddr, err)
+		return
+	}
+	resp := stationRouterInfoResponse{Router: string(req.Router)}
+	eui, err := req.EUI()
+	if err == nil {
+		resp.Router = eui.String()
+		host, _ := remoteAddress(conn)
+		err = b.checkGateway(eui, host)
+	}
+	if err != nil {
+		lg.Info("Rejecting router info request from %s: %v", conn.Request().RemoteAddr, err)
+		resp.Error = err.Error()
+	} else {
+		resp.Muxs = protocol.EUI{}.String()
+		resp.URI = fmt.Sprintf("ws://%s%s%s", conn.Request().Host, trafficPath, eui)
+	}
+	if err := websocket.JSON.Send(conn, resp); err != nil {
+		lg.Info("Unable to send router info response to %s: %v", conn.Request().RemoteAddr, err)
+	}
+}
+
+// addStation registers a station. Existing connections for the same station
+// are closed.
+func (b *BasicStationForwarder) addStation(s *station) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.closed {
+		return false
+	}
+	if existing, ok := b.stations[s.eui]; ok {
+		lg.Info("Gateway %s reconnected. Closing the old connection", s.eui)
+		existing.conn.Close()
+	}
+	b.stations[s.eui] = s
+	b.handlers.Add(1)
+	return true
+}
+
+// removeStation removes the station unless it has been replaced by a new
+// connection
+func (b *BasicStationForwarder) removeStation(s *station) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.stations[s.eui] == s {
+		delete(b.stations, s.eui)
+	}
+	b.handlers.Done()
+}
+
+// HasGateway returns true if the station is connected to the forwarder
+func (b *BasicStationForwarder) HasGateway(eui protocol.EUI) bool {
+	return b.getStation(eui) != nil
+}
+
+func (b *BasicStationForwarder) getStation(eui protocol.EUI) *station {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.stations[eui]
+}
+
+// traffic handles the traffic endpoint for a single station. Messages are
+// read until the connection is closed.
+func (b *BasicStationForwarder) traffic(conn *websocket.Conn) {
+	defer conn.Close()
+
+	eui, err := parseStationEUI(strings.TrimPrefix(conn.Request().URL.Path, trafficPath))
+	if err != nil {
+		lg.Info("Invalid station EUI in traffic endpoint %s: %v", conn.Request().URL.Path, err)
+		return
+	}
+	host, port := remoteAddress(conn)
+	if err := b.checkGateway(eui, host); err != nil {
+		lg.Info("Rejecting station at %s: %v", conn.Request().RemoteAddr, err)
+		return
+	}
+	s := &station{eui: eui, host: host, port: port, conn: conn}
+	if !b.addStation(s) {
+		return
+	}
+	defer b.removeStation(s)
+
+	lg.Info("Gateway %s connected from %s", eui, conn.Request().RemoteAddr)
+	for {
+		var msg string
+		if err := websocket.Message.Receive(conn, &msg); err != nil {
+			if err != io.EOF {
+				lg.Info("Unable to read from gateway %s: %v", eui, err)
+			}
+			break
+		}
+		b.monitor.Seen(eui)
+		b.handleMessage(s, msg)
+	}
+	lg.Info("Gateway %s disconnected", eui)
+}
+
+// handleMessage decodes and handles a single message from a station
+func (b *BasicStationForwarder) handleMessage(s *station, msg string) {
+	msgType := stationMessage{}
+	if err := json.Unmarshal([]byte(msg), &msgType); err != nil {
+		lg.Info("Unable to unmarshal message from gateway %s: %v (json=%s)", s.eui, err, msg)
+		return
+	}
+	switch msgType.MsgType {
+	case stationVersion:
+		version := stationVersionMessage{}
+		if err := json.Unmarshal([]byte(msg), &version); err != nil {
+			lg.Info("Unable to unmarshal version from gateway %s: %v", s.eui, err)
+			return
+		}
+		lg.Info("Gateway %s runs station %s (model: %s, protocol: %d)", s.eui, version.Station, version.Model, version.Protocol)
+		config, err := newStationRouterConfig(b.plans.Lookup(s.eui))
+		if err != nil {
+			lg.Error("Unable to create router config for gateway %s: %v", s.eui, err)
+			return
+		}
+		if err := s.send(config); err != nil {
+			lg.Warning("Unable to send router config to gateway %s: %v", s.eui, err)
+		}
+		b.monitor.Publish(s.eui, gwevents.NewKeepAlive())
+
+	case stationUplinkData, stationJoinRequest:
+		uplink := stationUplink{}
+		if err := json.Unmarshal([]byte(msg), &uplink); err != nil {
+			lg.Info("Unable to unmarshal uplink from gateway %s: %v (json=%s)", s.eui, err, msg)
+			return
+		}
+		b.handleUplink(s, uplink)
+
+	case stationTxConfirm:
+		confirmation := stationTxConfirmation{}
+		if err := json.Unmarshal([]byte(msg), &confirmation); err != nil {
+			lg.Info("Unable to unmarshal dntxed from gateway %s: %v", s.eui, err)
+			return
+		}
+		b.handleTxConfirmation(s, confirmation)
+
+	case stationTimeSync:
+		timeSync := stationTimeSyncMessage{}
+		if err := json.Unmarshal([]byte(msg), &timeSync); err != nil {
+			lg.Info("Unable to unmarshal timesync from gateway %s: %v", s.eui, err)
+			return
+		}
+		timeSync.GPSTime = int64(protocol.TimeToGPS(time.Now()) / time.Microsecond)
+		if err := s.send(timeSync); err != nil {
+			lg.Warning("Unable to send timesync to gateway %s: %v", s.eui, err)
+		}
+
+	case stationProprietary:
+		lg.Debug("Ignoring proprietary frame from gateway %s", s.eui)
+
+	default:
+		lg.Debug("Don't know how to handle message type %s from gateway %s", msgType.MsgType, s.eui)
+	}
+}
+
+// handleUplink forwards an uplink to the pipeline
+func (b *BasicStationForwarder) handleUplink(s *station, uplink stationUplink) {
+	phyPayload, err := uplink.PHYPayload()
+	if err != nil {
+		lg.Info("Unable to build PHYPayload from gateway %s: %v", s.eui, err)
+		return
+	}
+	plan, channels := b.plans.Lookup(s.eui)
+	encoding, err := plan.Encoding(uplink.DR)
+	if err != nil {
+		lg.Info("Invalid data rate from gateway %s: %v", s.eui, err)
+		return
+	}
+	frequency := fromHz(uplink.Freq)
+	channel, _ := channels.Channel(frequency)
+	gwPacket := server.GatewayPacket{
+		RawMessage: phyPayload,
+		Radio:      radioContext(plan, channels, frequency, encoding.GatewayDataRate(), channel, 0, int32(uplink.UpInfo.RSSI), uplink.UpInfo.SNR),
+		Gateway: server.GatewayContext{
+			GatewayEUI:   s.eui,
+			GatewayHost:  s.host,
+			GatewayPort:  s.port,
+			GatewayClock: uint32(uplink.UpInfo.XTime),
+			XTime:        uplink.UpInfo.XTime,
+			RCtx:         uplink.UpInfo.RCtx,
+		},
+		ReceivedAt: time.Now(),
+	}
+	rxpk := uplink.Rxpk(phyPayload, gwPacket.Radio.DataRate)
+	rxpk.ConcentratorChannel = channel
+	rxData, err := json.Marshal(RXData{Data: []Rxpk{rxpk}})
+	if err == nil {
+		b.monitor.Publish(s.eui, gwevents.NewRx(string(rxData)))
+	}
+	b.output <- gwPacket
+}
+
+// sendDownlink sends a dnmsg to the station that received the uplink.
+// Immediate downlinks are sent as class C downlinks, downlinks with a GPS
+// time as class B and the rest as class A downlinks in the RX1 or RX2 window.
+func (b *BasicStationForwarder) sendDownlink(packet server.GatewayPacket) {
+	s := b.getStation(packet.Gateway.GatewayEUI)
+	if s == nil {
+		lg.Warning("Gateway %s isn't connected. Dropping downlink", packet.Gateway.GatewayEUI)
+		return
+	}
+	dataRate, err := b.plans.DownlinkBand(packet).GetDataRate(packet.Radio.DataRate)
+	if err != nil {
+		lg.Warning("Unable to look up data rate for downlink to gateway %s: %v", s.eui, err)
+		return
+	}
+	frequency := toHz(packet.Radio.Frequency)
+
+	b.mutex.Lock()
+	b.nextDIID++
+	diid := b.nextDIID
+	b.mutex.Unlock()
+
+	msg := stationDownlinkMessage{
+		MsgType: stationDownlink,
+		// The device EUI isn't known at this point. The station only uses
+		// it for logging.
+		DevEUI:  protocol.EUI{}.String(),
+		DIID:    diid,
+		PDU:     hex.EncodeToString(packet.RawMessage),
+		RCtx:    packet.Gateway.RCtx,
+		MuxTime: muxTime(time.Now()),
+	}
+	txpk := Txpk{
+		Immediate:    packet.Immediate,
+		Frequency:    packet.Radio.Frequency,
+		Data:         base64.StdEncoding.EncodeToString(packet.RawMessage),
+		Modulation:   "LORA",
+		EccCoding:    "4/5",
+		LoraInvPol:   true,
+		PayloadSize:  len(packet.RawMessage),
+		LoRaDataRate: packet.Radio.DataRate,
+	}
+	switch {
+	case packet.Immediate:
+		msg.DC = 2
+		msg.RX2DR = &dataRate
+		msg.RX2Freq = frequency
+	case packet.GPSTime != 0:
+		msg.DC = 1
+		msg.DR = &dataRate
+		msg.Freq = frequency
+		msg.GPSTime = int64(packet.GPSTime / time.Microsecond)
+		txpk.GPSTime = uint64(packet.GPSTime / time.Millisecond)
+	case packet.Radio.Window == band.RX2:
+		// The station opens RX2 one second after RX1
+		msg.DC = 0
+		msg.XTime = packet.Gateway.XTime
+		msg.RxDelay = packet.Radio.RX1Delay
+		msg.RX2DR = &dataRate
+		msg.RX2Freq = frequency
+		txpk.Timestamp = packet.Gateway.GatewayClock + 1000000*uint32(packet.Radio.TxDelay())
+	default:
+		msg.DC = 0
+		msg.XTime = packet.Gateway.XTime
+		msg.RxDelay = packet.Radio.RX1Delay
+		msg.RX1DR = &dataRate
+		msg.RX1Freq = frequency
+		txpk.Timestamp = packet.Gateway.GatewayClock + 1000000*uint32(packet.Radio.TxDelay())
+	}
+
+	txData, err := json.Marshal(TXData{Data: txpk})
+	if err != nil {
+		lg.Warning("Unable to marshal txpk for gateway %s: %v", s.eui, err)
+	}
+	b.mutex.Lock()
+	b.pending[diid] = pendingDownlink{gatewayEUI: s.eui, txData: string(txData), downlink: packet.Downlink, created: time.Now()}
+	b.mutex.Unlock()
+
+	if err := s.send(msg); err != nil {
+		lg.Warning("Unable to send downlink to gateway %s: %v", s.eui, err)
+	}
+}
+
+// handleTxConfirmation handles the dntxed message from the station. A Tx
+// event and the downlink event for the application payload are published when
+// the downlink is confirmed.
+func (b *BasicStationForwarder) handleTxConfirmation(s *station, confirmation stationTxConfirmation) {
+	b.mutex.Lock()
+	p, ok := b.pending[confirmation.DIID]
+	delete(b.pending, confirmation.DIID)
+	b.mutex.Unlock()
+
+	if !ok {
+		lg.Info("Gateway %s confirmed unknown downlink %d", s.eui, confirmation.DIID)
+		return
+	}
+	lg.Debug("Gateway %s sent downlink %d after %v", s.eui, confirmation.DIID, time.Since(p.created))
+	b.monitor.Publish(s.eui, gwevents.NewTx(p.txData))
+	b.monitor.Transmitted(p.downlink)
+}
@@ -0,0 +1,422 @@
+package gateway
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+)
+
+// Message types used by the LoRa Basic Station LNS protocol. The protocol is
+// described at https://doc.sm.tc/station/tcproto.html
+const (
+	stationVersion      = "version"
+	stationRouterConfig = "router_config"
+	stationUplinkData   = "updf"
+	stationJoinRequest  = "jreq"
+	stationProprietary  = "propdf"
+	stationDownlink     = "dnmsg"
+	stationTxConfirm    = "dntxed"
+	stationTimeSync     = "timesync"
+)
+
+// stationRouterInfoRequest is sent by the station to the router info endpoint
+type stationRouterInfoRequest struct {
+	Router json.RawMessage `json:"router"` // ID6, EUI string or integer
+}
+
+// EUI returns the EUI of the station
+func (r *stationRouterInfoRequest) EUI() (protocol.EUI, error) {
+	var n uint64
+	if err := json.Unmarshal(r.Router, &n); err == nil {
+		return protocol.EUIFromInt64(int64(n)), nil
+	}
+	var s string
+	if err := json.Unmarshal(r.Router, &s); err != nil {
+		return protocol.EUI{}, fmt.Errorf("invalid router identifier: %s", string(r.Router))
+	}
+	return parseStationEUI(s)
+}
+
+// stationRouterInfoResponse is the response from the router info endpoint.
+// Either the URI or the error field is set.
+type stationRouterInfoResponse struct {
+	Router string `json:"router"`
+	Muxs   string `json:"muxs,omitempty"`
+	URI    string `json:"uri,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// stationMessage is used to determine the type of message from the station
+type stationMessage struct {
+	MsgType string `json:"msgtype"`
+}
+
+// stationVersionMessage is the first message sent by the station on the
+// traffic endpoint
+type stationVersionMessage struct {
+	Station  string `json:"station"`
+	Firmware string `json:"firmware"`
+	Package  string `json:"package"`
+	Model    string `json:"model"`
+	Protocol int    `json:"protocol"`
+	Features string `json:"features"`
+}
+
+// stationRouterConfigMessage is the response to the version message. It
+// contains the channel plan for the station.
+type stationRouterConfigMessage struct {
+	MsgType    string                   `json:"msgtype"`
+	Region     string                   `json:"region"`
+	HWSpec     string                   `json:"hwspec"`
+	FreqRange  [2]uint32                `json:"freq_range"`
+	DataRates  [][3]int                 `json:"DRs"`
+	SX1301Conf []map[string]interface{} `json:"sx1301_conf"`
+	MuxTime    float64                  `json:"MuxTime"`
+}
+
+// stationRadio is a radio in the concentrator configuration
+type stationRadio struct {
+	Enable bool   `json:"enable"`
+	Freq   uint32 `json:"freq"`
+}
+
+// stationChannel is an IF channel in the concentrator configuration. The
+// channel frequency is relative to the radio frequency.
+type stationChannel struct {
+	Enable       bool   `json:"enable"`
+	Radio        int    `json:"radio"`
+	IF           int32  `json:"if"`
+	Bandwidth    uint32 `json:"bandwidth,omitempty"`     // LoRa std channel only
+	SpreadFactor uint8  `json:"spread_factor,omitempty"` // LoRa std channel only
+}
+
+// stationUpInfo is the radio metadata for uplinks
+type stationUpInfo struct {
+	RCtx    int64   `json:"rctx"`    // Radio context; returned in downlinks
+	XTime   int64   `json:"xtime"`   // Concentrator time with session bits
+	GPSTime int64   `json:"gpstime"` // GPS time in microseconds. 0 if not available
+	RSSI    float32 `json:"rssi"`
+	SNR     float32 `json:"snr"`
+}
+
+// stationUplink is an uplink (updf) or join request (jreq) from the station.
+// The station splits the frame into separate fields.
+type stationUplink struct {
+	MsgType    string        `json:"msgtype"`
+	MHdr       uint8         `json:"MHdr"`
+	DevAddr    int32         `json:"DevAddr"`
+	FCtrl      uint8         `json:"FCtrl"`
+	FCnt       uint16        `json:"FCnt"`
+	FOpts      string        `json:"FOpts"`
+	FPort      int           `json:"FPort"` // -1 if there's no port
+	FRMPayload string        `json:"FRMPayload"`
+	JoinEUI    string        `json:"JoinEui"`
+	DevEUI     string        `json:"DevEui"`
+	DevNonce   uint16        `json:"DevNonce"`
+	MIC        int32         `json:"MIC"`
+	DR         uint8         `json:"DR"`
+	Freq       uint32        `json:"Freq"`
+	UpInfo     stationUpInfo `json:"upinfo"`
+}
+
+// PHYPayload rebuilds the PHYPayload as it was sent by the device
+func (u *stationUplink) PHYPayload() ([]byte, error) {
+	buf := []byte{u.MHdr}
+	switch u.MsgType {
+	case stationJoinRequest:
+		joinEUI, err := parseStationEUI(u.JoinEUI)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JoinEui: %v", err)
+		}
+		devEUI, err := parseStationEUI(u.DevEUI)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DevEui: %v", err)
+		}
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(joinEUI.ToInt64()))
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(devEUI.ToInt64()))
+		buf = binary.LittleEndian.AppendUint16(buf, u.DevNonce)
+
+	case stationUplinkData:
+		fopts, err := hex.DecodeString(u.FOpts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid FOpts: %v", err)
+		}
+		payload, err := hex.DecodeString(u.FRMPayload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid FRMPayload: %v", err)
+		}
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(u.DevAddr))
+		buf = append(buf, u.FCtrl)
+		buf = binary.LittleEndian.AppendUint16(buf, u.FCnt)
+		buf = append(buf, fopts...)
+		if u.FPort >= 0 {
+			buf = append(buf, uint8(u.FPort))
+		}
+		buf = append(buf, payload...)
+
+	default:
+		return nil, fmt.Errorf("unknown uplink message type: %s", u.MsgType)
+	}
+	return binary.LittleEndian.AppendUint32(buf, uint32(u.MIC)), nil
+}
+
+// Rxpk returns the uplink in the Semtech packet forwarder format. This is
+// used for the gateway events.
+func (u *stationUplink) Rxpk(phyPayload []byte, dataRate string) Rxpk {
+	return Rxpk{
+		Time:         time.Now().UTC().Format(time.RFC3339Nano),
+		Timestamp:    uint32(u.UpInfo.XTime),
+		Frequency:    fromHz(u.Freq),
+		ModulationID: "LORA",
+		DataRateID:   dataRate,
+		CodingRateID: "4/5",
+		RSSI:         int32(u.UpInfo.RSSI),
+		LoraSNRRatio: u.UpInfo.SNR,
+		PayloadSize:  uint32(len(phyPayload)),
+		RFPackets:    base64.StdEncoding.EncodeToString(phyPayload),
+	}
+}
+
+// stationDownlinkMessage is a downlink (dnmsg) to the station. The fields
+// depend on the device class.
+type stationDownlinkMessage struct {
+	MsgType  string  `json:"msgtype"`
+	DevEUI   string  `json:"DevEui"`
+	DC       int     `json:"dC"` // Device class; 0 = A, 1 = B, 2 = C
+	DIID     int64   `json:"diid"`
+	PDU      string  `json:"pdu"`
+	Priority int     `json:"priority"`
+	RCtx     int64   `json:"rctx"`
+	XTime    int64   `json:"xtime,omitempty"`
+	RxDelay  uint8   `json:"RxDelay,omitempty"`
+	RX1DR    *uint8  `json:"RX1DR,omitempty"`
+	RX1Freq  uint32  `json:"RX1Freq,omitempty"`
+	RX2DR    *uint8  `json:"RX2DR,omitempty"`
+	RX2Freq  uint32  `json:"RX2Freq,omitempty"`
+	DR       *uint8  `json:"DR,omitempty"`
+	Freq     uint32  `json:"Freq,omitempty"`
+	GPSTime  int64   `json:"gpstime,omitempty"`
+	MuxTime  float64 `json:"MuxTime"`
+}
+
+// stationTxConfirmation is sent by the station when a downlink is sent
+type stationTxConfirmation struct {
+	DIID    int64   `json:"diid"`
+	DevEUI  string  `json:"DevEui"`
+	RCtx    int64   `json:"rctx"`
+	XTime   int64   `json:"xtime"`
+	TxTime  float64 `json:"txtime"`
+	GPSTime int64   `json:"gpstime"`
+}
+
+// stationTimeSyncMessage is used by the station to synchronize the GPS time.
+// The server echoes the txtime field and adds the current GPS time.
+type stationTimeSyncMessage struct {
+	MsgType string          `json:"msgtype"`
+	TxTime  json.RawMessage `json:"txtime"`
+	GPSTime int64           `json:"gpstime,omitempty"`
+}
+
+// parseStationEUI parses EUIs in the formats used by the station; either ID6
+// ("1:2:3:4" or "::1"), EUI ("01-02-03-04-05-06-07-08") or hex digits.
+func parseStationEUI(s string) (protocol.EUI, error) {
+	if !strings.Contains(s, ":") {
+		return protocol.EUIFromString(s)
+	}
+	splitGroups := func(str string) []string {
+		if str == "" {
+			return nil
+		}
+		return strings.Split(str, ":")
+	}
+	parts := strings.SplitN(s, "::", 2)
+	head := splitGroups(parts[0])
+	var tail []string
+	if len(parts) == 2 {
+		tail = splitGroups(parts[1])
+	}
+	if len(head)+len(tail) > 4 || (len(parts) == 1 && len(head) != 4) {
+		return protocol.EUI{}, fmt.Errorf("invalid ID6 format: %s", s)
+	}
+	groups := append(head, make([]string, 4-len(head)-len(tail))...)
+	groups = append(groups, tail...)
+	val := uint64(0)
+	for i, g := range groups {
+		n := uint64(0)
+		if i < len(head) || i >= 4-len(tail) {
+			var err error
+			if n, err = strconv.ParseUint(g, 16, 16); err != nil {
+				return protocol.EUI{}, fmt.Errorf("invalid ID6 format: %s", s)
+			}
+		}
+		val = val<<16 | n
+	}
+	return protocol.EUIFromInt64(int64(val)), nil
+}
+
+// toHz converts a frequency in MHz to Hz. The frequency is rounded to 100Hz
+// to remove the float32 rounding errors.
+func toHz(freq float32) uint32 {
+	return uint32(math.Round(float64(freq)*1e4)) * 100
+}
+
+// fromHz converts a frequency in Hz to MHz
+func fromHz(freq uint32) float32 {
+	return float32(float64(freq) / 1e6)
+}
+
+// stationRegion returns the region name and frequency range (in Hz) for the
+// frequency plan.
+func stationRegion(plan band.FrequencyPlan) (string, [2]uint32, error) {
+	switch p := plan.(type) {
+	case band.EU868:
+		return "EU863", [2]uint32{863000000, 870000000}, nil
+	case band.US902:
+		return "US902", [2]uint32{902000000, 928000000}, nil
+	case band.EU433:
+		return "EU433", [2]uint32{433050000, 434790000}, nil
+	case band.AS923:
+		return fmt.Sprintf("AS923-%d", p.Group()), [2]uint32{915000000, 928000000}, nil
+	case band.AU915:
+		return "AU915", [2]uint32{915000000, 928000000}, nil
+	case band.CN470:
+		return "CN470", [2]uint32{470000000, 510000000}, nil
+	case band.IN865:
+		return "IN865", [2]uint32{865000000, 867000000}, nil
+	case band.KR920:
+		return "KR920", [2]uint32{920900000, 923300000}, nil
+	default:
+		return "", [2]uint32{}, fmt.Errorf("no station region for band %s", plan.Name())
+	}
+}
+
+// stationDataRates returns the data rate table for the station. Each entry
+// is the spreading factor, bandwidth and a downlink only flag. FSK data rates
+// use a spreading factor of 0 and unused data rates a spreading factor of -1.
+func stationDataRates(plan band.FrequencyPlan) [][3]int {
+	var ret [][3]int
+	for dr := uint8(0); dr < 16; dr++ {
+		encoding, err := plan.Encoding(dr)
+		switch {
+		case err != nil:
+			ret = append(ret, [3]int{-1, 0, 0})
+		case encoding.Modulation == band.FSK:
+			ret = append(ret, [3]int{0, 0, 0})
+		case plan.Configuration().DownlinkOnly(dr):
+			ret = append(ret, [3]int{int(encoding.SpreadFactor), int(encoding.Bandwidth), 1})
+		default:
+			ret = append(ret, [3]int{int(encoding.SpreadFactor), int(encoding.Bandwidth), 0})
+		}
+	}
+	return ret
+}
+
+// maxRadioSpan is the maximum distance between the lowest and highest
+// channel on a single SX1301 radio.
+const maxRadioSpan = 800000
+
+// stationSX1301Conf builds the concentrator configuration for the channels.
+// The channels are sorted and split between the two radios of the SX1301.
+// Each radio is tuned to the center of its channels.
+func stationSX1301Conf(channels []float32) (map[string]interface{}, error) {
+	if len(channels) > 8 {
+		return nil, fmt.Errorf("the concentrator supports 8 channels (got %d)", len(channels))
+	}
+	freqs := make([]uint32, len(channels))
+	for i, ch := range channels {
+		freqs[i] = toHz(ch)
+	}
+	sort.Slice(freqs, func(i, j int) bool { return freqs[i] < freqs[j] })
+
+	var radios [][]uint32
+	for _, f := range freqs {
+		if n := len(radios); n > 0 && f-radios[n-1][0] <= maxRadioSpan {
+			radios[n-1] = append(radios[n-1], f)
+			continue
+		}
+		radios = append(radios, []uint32{f})
+	}
+	if len(radios) > 2 {
+		return nil, fmt.Errorf("the channels do not fit on two radios")
+	}
+
+	ret := make(map[string]interface{})
+	ch := 0
+	for i, radio := range radios {
+		center := (radio[0] + radio[len(radio)-1]) / 2
+		ret[fmt.Sprintf("radio_%d", i)] = stationRadio{Enable: true, Freq: center}
+		for _, f := range radio {
+			ret[fmt.Sprintf("chan_multiSF_%d", ch)] = stationChannel{Enable: true, Radio: i, IF: int32(f) - int32(center)}
+			ch++
+		}
+	}
+	return ret, nil
+}
+
+// stationConcentratorConf builds the concentrator configuration from the
+// gateway's channel configuration.
+func stationConcentratorConf(channels model.ConcentratorConfig) map[string]interface{} {
+	ret := make(map[string]interface{})
+	for i, f := range channels.Radios {
+		ret[fmt.Sprintf("radio_%d", i)] = stationRadio{Enable: true, Freq: toHz(f)}
+	}
+	for i, ch := range channels.MultiSF {
+		ret[fmt.Sprintf("chan_multiSF_%d", i)] = stationChannel{Enable: ch.Enabled, Radio: int(ch.Radio), IF: ch.IF}
+	}
+	if ch := channels.LoRaStd; ch.Enabled {
+		ret["chan_Lora_std"] = stationChannel{Enable: true, Radio: int(ch.Radio), IF: ch.IF,
+			Bandwidth: ch.Bandwidth, SpreadFactor: ch.SpreadFactor}
+	}
+	if ch := channels.FSK; ch.Enabled {
+		ret["chan_FSK"] = stationChannel{Enable: true, Radio: int(ch.Radio), IF: ch.IF}
+	}
+	return ret
+}
+
+// newStationRouterConfig creates the router_config message for a station.
+// Gateways without a channel configuration get the join channels for the
+// frequency plan. Plans without join channels require a channel configuration.
+func newStationRouterConfig(plan band.FrequencyPlan, channels model.ConcentratorConfig) (stationRouterConfigMessage, error) {
+	region, freqRange, err := stationRegion(plan)
+	if err != nil {
+		return stationRouterConfigMessage{}, err
+	}
+	var conf map[string]interface{}
+	if channels.Empty() {
+		joinChannels := plan.Configuration().JoinReqChannels
+		if len(joinChannels) == 0 {
+			return stationRouterConfigMessage{}, fmt.Errorf("no channels configured for band %s", plan.Name())
+		}
+		if conf, err = stationSX1301Conf(joinChannels); err != nil {
+			return stationRouterConfigMessage{}, err
+		}
+	} else {
+		conf = stationConcentratorConf(channels)
+	}
+	return stationRouterConfigMessage{
+		MsgType:    stationRouterConfig,
+		Region:     region,
+		HWSpec:     "sx1301/1",
+		FreqRange:  freqRange,
+		DataRates:  stationDataRates(plan),
+		SX1301Conf: []map[string]interface{}{conf},
+		MuxTime:    muxTime(time.Now()),
+	}, nil
+}
+
+// muxTime returns the server time in seconds. It is included in messages to
+// the station to let it measure the round trip time.
+func muxTime(now time.Time) float64 {
+	return float64(now.UnixNano()) / 1e9
+}
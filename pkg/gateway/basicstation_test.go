@@ -0,0 +1,366 @@
+package gateway
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/events/gwevents"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+	"github.com/lab5e/lospan/pkg/utils"
+	"golang.org/x/net/websocket"
+)
+
+func TestParseStationEUI(t *testing.T) {
+	tests := []struct {
+		str string
+		eui uint64
+	}{
+		{"::0", 0},
+		{"::1", 1},
+		{"1::", 0x0001000000000000},
+		{"1::2", 0x0001000000000002},
+		{"b827:ebff:fe61:0b99", 0xb827ebfffe610b99},
+		{"01-02-03-04-05-06-07-08", 0x0102030405060708},
+		{"0102030405060708", 0x0102030405060708},
+	}
+	for _, test := range tests {
+		eui, err := parseStationEUI(test.str)
+		if err != nil {
+			t.Fatalf("Unable to parse %s: %v", test.str, err)
+		}
+		if eui != protocol.EUIFromInt64(int64(test.eui)) {
+			t.Fatalf("Incorrect EUI for %s: %s", test.str, eui)
+		}
+	}
+	for _, invalid := range []string{"1:2:3", "1:2:3:4:5", "1::2::3", "x::", "01-02"} {
+		if _, err := parseStationEUI(invalid); err == nil {
+			t.Fatalf("Expected error for %s", invalid)
+		}
+	}
+
+	req := stationRouterInfoRequest{Router: json.RawMessage("1234")}
+	if eui, err := req.EUI(); err != nil || eui != protocol.EUIFromInt64(1234) {
+		t.Fatalf("Incorrect EUI for numeric router: %s (%v)", eui, err)
+	}
+}
+
+func TestStationSX1301Conf(t *testing.T) {
+	defaultChannels := model.DefaultConcentratorConfig(band.EU868Band).Frequencies()
+	conf, err := stationSX1301Conf(defaultChannels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := conf["radio_0"].(stationRadio); r.Freq != 867500000 {
+		t.Fatalf("Incorrect frequency for radio 0: %d", r.Freq)
+	}
+	if r := conf["radio_1"].(stationRadio); r.Freq != 868300000 {
+		t.Fatalf("Incorrect frequency for radio 1: %d", r.Freq)
+	}
+	for i := 0; i < len(defaultChannels); i++ {
+		ch, ok := conf[fmt.Sprintf("chan_multiSF_%d", i)].(stationChannel)
+		if !ok || ch.IF < -400000 || ch.IF > 400000 {
+			t.Fatalf("Channel %d is out of range: %+v", i, ch)
+		}
+	}
+
+	if _, err := stationSX1301Conf([]float32{863.1, 865.1, 867.1}); err == nil {
+		t.Fatal("Channels for three radios should fail")
+	}
+	if _, err := stationSX1301Conf(append(defaultChannels, 868.8)); err == nil {
+		t.Fatal("More than 8 channels should fail")
+	}
+
+	eu868, _ := band.NewBand(band.EU868Band)
+	if _, err := newStationRouterConfig(eu868, model.ConcentratorConfig{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStationConcentratorConf(t *testing.T) {
+	us915, _ := band.NewBand(band.US915Band)
+	config, err := newStationRouterConfig(us915, model.DefaultConcentratorConfig(band.US915Band))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.DataRates[4] != [3]int{8, 500, 0} || config.DataRates[8] != [3]int{12, 500, 1} || config.DataRates[13] != [3]int{7, 500, 1} {
+		t.Fatalf("DR8-13 should be downlink only: %v", config.DataRates)
+	}
+	conf := config.SX1301Conf[0]
+	if r := conf["radio_1"].(stationRadio); r.Freq != 905000000 {
+		t.Fatalf("Incorrect frequency for radio 1: %d", r.Freq)
+	}
+	if ch := conf["chan_multiSF_7"].(stationChannel); ch.Radio != 1 || ch.IF != 300000 {
+		t.Fatalf("Incorrect channel 7: %+v", ch)
+	}
+	if ch := conf["chan_Lora_std"].(stationChannel); ch.SpreadFactor != 8 || ch.Bandwidth != 500000 {
+		t.Fatalf("Incorrect LoRa std channel: %+v", ch)
+	}
+	if _, ok := conf["chan_FSK"]; ok {
+		t.Fatal("FSK channel should not be configured")
+	}
+
+	// Plans without a default channel configuration use the join channels
+	kr920, _ := band.NewBand(band.KR920Band)
+	config, err = newStationRouterConfig(kr920, model.ConcentratorConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := config.SX1301Conf[0]["chan_multiSF_2"]; !ok {
+		t.Fatal("Expected three channels for KR920")
+	}
+
+	// CN470 has neither join channels nor a default channel configuration
+	cn470, _ := band.NewBand(band.CN470Band)
+	if _, err := newStationRouterConfig(cn470, model.ConcentratorConfig{}); err == nil {
+		t.Fatal("Expected error for CN470 without channels")
+	}
+}
+
+func TestStationRegion(t *testing.T) {
+	regions := map[band.FrequencyBandType]string{
+		band.EU868Band:       "EU863",
+		band.US915Band:       "US902",
+		band.EU433Band:       "EU433",
+		band.AS923Band:       "AS923-1",
+		band.AS923Group3Band: "AS923-3",
+		band.AU915Band:       "AU915",
+		band.CN470Band:       "CN470",
+		band.IN865Band:       "IN865",
+		band.KR920Band:       "KR920",
+	}
+	for bandType, expected := range regions {
+		plan, err := band.NewBand(bandType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		region, freqRange, err := stationRegion(plan)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if region != expected {
+			t.Errorf("Expected region %s for %s but got %s", expected, plan.Name(), region)
+		}
+		rx2 := toHz(plan.GetRX2Parameters().Frequency)
+		if rx2 < freqRange[0] || rx2 > freqRange[1] {
+			t.Errorf("RX2 frequency for %s is outside the frequency range", plan.Name())
+		}
+	}
+}
+
+// newStationUplink splits an uplink frame into the fields used by the station
+func newStationUplink(phyPayload []byte) stationUplink {
+	fOptsLen := int(phyPayload[5] & 0x0F)
+	fhdrEnd := 8 + fOptsLen
+	return stationUplink{
+		MsgType:    stationUplinkData,
+		MHdr:       phyPayload[0],
+		DevAddr:    int32(binary.LittleEndian.Uint32(phyPayload[1:])),
+		FCtrl:      phyPayload[5],
+		FCnt:       binary.LittleEndian.Uint16(phyPayload[6:]),
+		FOpts:      hex.EncodeToString(phyPayload[8:fhdrEnd]),
+		FPort:      int(phyPayload[fhdrEnd]),
+		FRMPayload: hex.EncodeToString(phyPayload[fhdrEnd+1 : len(phyPayload)-4]),
+		MIC:        int32(binary.LittleEndian.Uint32(phyPayload[len(phyPayload)-4:])),
+		DR:         3,
+		Freq:       868300000,
+		UpInfo:     stationUpInfo{RCtx: 1, XTime: 0x1000000001234567, RSSI: -80, SNR: 7.5},
+	}
+}
+
+func TestStationUplinkPHYPayload(t *testing.T) {
+	phyPayload, _ := base64.StdEncoding.DecodeString("gOZy5gGAAQALqBJvwTWKKB0=")
+	uplink := newStationUplink(phyPayload)
+	buf, err := uplink.PHYPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(buf) != hex.EncodeToString(phyPayload) {
+		t.Fatalf("Incorrect PHYPayload: %x (expected %x)", buf, phyPayload)
+	}
+
+	join := stationUplink{
+		MsgType:  stationJoinRequest,
+		MHdr:     0,
+		JoinEUI:  "01-02-03-04-05-06-07-08",
+		DevEUI:   "11-12-13-14-15-16-17-18",
+		DevNonce: 0x0201,
+		MIC:      0x04030201,
+	}
+	buf, err = join.PHYPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "00" + "0807060504030201" + "1817161514131211" + "0102" + "01020304"
+	if hex.EncodeToString(buf) != expected {
+		t.Fatalf("Incorrect join request: %x (expected %s)", buf, expected)
+	}
+	payload := protocol.NewPHYPayload(protocol.JoinRequest)
+	if err := payload.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	if payload.JoinRequestPayload.DevEUI.String() != join.DevEUI {
+		t.Fatalf("Incorrect DevEUI in join request: %s", payload.JoinRequestPayload.DevEUI)
+	}
+
+	join.DevEUI = "foo"
+	if _, err := join.PHYPayload(); err == nil {
+		t.Fatal("Expected error with invalid DevEUI")
+	}
+}
+
+// dialStation connects to the forwarder. The forwarder might not be listening
+// when the first attempt is made.
+func dialStation(t *testing.T, url string) *websocket.Conn {
+	for i := 0; i < 10; i++ {
+		conn, err := websocket.Dial(url, "", "http://localhost/")
+		if err == nil {
+			return conn
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("Unable to connect to %s", url)
+	return nil
+}
+
+func sendStation(t *testing.T, conn *websocket.Conn, msg interface{}) {
+	if err := websocket.JSON.Send(conn, msg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func receiveStation(t *testing.T, conn *websocket.Conn, msg interface{}) {
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if err := websocket.JSON.Receive(conn, msg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func routerInfo(t *testing.T, port int, router string) stationRouterInfoResponse {
+	conn := dialStation(t, fmt.Sprintf("ws://localhost:%d%s", port, routerInfoPath))
+	defer conn.Close()
+	sendStation(t, conn, map[string]string{"router": router})
+	resp := stationRouterInfoResponse{}
+	receiveStation(t, conn, &resp)
+	return resp
+}
+
+func TestBasicStationForwarder(t *testing.T) {
+	gwEUI := protocol.EUIFromInt64(0x010203040506070C)
+	if err := gwStorage.CreateGateway(model.Gateway{GatewayEUI: gwEUI, IP: net.ParseIP("127.0.0.1"), StrictIP: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	port, err := utils.FreePort()
+	if err != nil {
+		t.Fatal("Could not allocate free port: ", err)
+	}
+	router := server.NewEventRouter[protocol.EUI, gwevents.GwEvent](5)
+	context := server.Context{GwEventRouter: &router, Config: &server.Parameters{GatewayTimeout: 400 * time.Millisecond}}
+	forwarder := NewBasicStationForwarder(port, gwStorage, &context)
+	go forwarder.Start()
+
+	events := router.Subscribe(gwEUI)
+	defer router.Unsubscribe(events)
+
+	// Unknown gateways are rejected
+	if resp := routerInfo(t, port, "::1"); resp.Error == "" || resp.URI != "" {
+		t.Fatalf("Unknown gateway should be rejected: %+v", resp)
+	}
+
+	resp := routerInfo(t, port, "102:304:506:70c")
+	if resp.Error != "" || !strings.HasSuffix(resp.URI, trafficPath+gwEUI.String()) {
+		t.Fatalf("Incorrect router info response: %+v", resp)
+	}
+
+	conn := dialStation(t, resp.URI)
+	defer conn.Close()
+
+	sendStation(t, conn, map[string]interface{}{"msgtype": "version", "station": "2.0.6", "protocol": 2})
+	config := stationRouterConfigMessage{}
+	receiveStation(t, conn, &config)
+	if config.MsgType != stationRouterConfig || config.Region != "EU863" || config.DataRates[0] != [3]int{12, 125, 0} {
+		t.Fatalf("Incorrect router config: %+v", config)
+	}
+
+	phyPayload, _ := base64.StdEncoding.DecodeString("gOZy5gGAAQALqBJvwTWKKB0=")
+	sendStation(t, conn, newStationUplink(phyPayload))
+
+	var pkt server.GatewayPacket
+	select {
+	case pkt = <-forwarder.Output():
+	case <-time.After(time.Second):
+		t.Fatal("No uplink from forwarder")
+	}
+	if hex.EncodeToString(pkt.RawMessage) != hex.EncodeToString(phyPayload) {
+		t.Fatalf("Incorrect uplink payload: %x", pkt.RawMessage)
+	}
+	if pkt.Radio.DataRate != "SF9BW125" || toHz(pkt.Radio.Frequency) != 868300000 || pkt.Radio.Channel != 1 {
+		t.Fatalf("Incorrect radio context: %+v", pkt.Radio)
+	}
+	if pkt.Gateway.GatewayEUI != gwEUI || pkt.Gateway.XTime != 0x1000000001234567 || pkt.Gateway.RCtx != 1 {
+		t.Fatalf("Incorrect gateway context: %+v", pkt.Gateway)
+	}
+
+	// Send a class A downlink in RX1
+	pkt.Radio.RX1Delay = 1
+	forwarder.Input() <- pkt
+	dnmsg := stationDownlinkMessage{}
+	receiveStation(t, conn, &dnmsg)
+	if dnmsg.MsgType != stationDownlink || dnmsg.DC != 0 || dnmsg.RxDelay != 1 || dnmsg.XTime != pkt.Gateway.XTime || dnmsg.RCtx != 1 {
+		t.Fatalf("Incorrect downlink: %+v", dnmsg)
+	}
+	if dnmsg.RX1DR == nil || *dnmsg.RX1DR != 3 || dnmsg.RX1Freq != 868300000 || dnmsg.PDU != hex.EncodeToString(phyPayload) {
+		t.Fatalf("Incorrect RX1 parameters for downlink: %+v", dnmsg)
+	}
+
+	// Class C downlinks use the RX2 fields
+	pkt.Immediate = true
+	forwarder.Input() <- pkt
+	classC := stationDownlinkMessage{}
+	receiveStation(t, conn, &classC)
+	if classC.DC != 2 || classC.RX2DR == nil || *classC.RX2DR != 3 || classC.RX2Freq != 868300000 || classC.DIID == dnmsg.DIID {
+		t.Fatalf("Incorrect class C downlink: %+v", classC)
+	}
+
+	sendStation(t, conn, map[string]interface{}{"msgtype": "dntxed", "diid": dnmsg.DIID, "xtime": dnmsg.XTime})
+
+	var seen []gwevents.GwEvent
+	timeout := time.After(time.Second)
+	for len(seen) == 0 || seen[len(seen)-1].Type != gwevents.Tx {
+		select {
+		case ev := <-events:
+			seen = append(seen, ev)
+		case <-timeout:
+			t.Fatalf("No Tx event after dntxed (got %+v)", seen)
+		}
+	}
+	if len(seen) != 3 || seen[0].Type != gwevents.KeepAlive || seen[1].Type != gwevents.Rx {
+		t.Fatalf("Incorrect events: %+v", seen)
+	}
+	txData := TXData{}
+	if err := json.Unmarshal([]byte(seen[2].Data), &txData); err != nil || txData.Data.Frequency != pkt.Radio.Frequency {
+		t.Fatalf("Incorrect Tx event: %+v", seen[2])
+	}
+
+	waitForLastSeen(t, gwEUI)
+
+	forwarder.Stop()
+	select {
+	case _, ok := <-forwarder.Output():
+		if ok {
+			t.Fatal("Expected output channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Output channel not closed after Stop")
+	}
+}
@@ -0,0 +1,165 @@
+package gateway
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/events/gwevents"
+	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+	"github.com/lab5e/lospan/pkg/storage"
+)
+
+// livenessTracker keeps track of when the gateways were last seen. Gateways
+// that haven't been seen within the timeout are reported as expired once.
+type livenessTracker struct {
+	mutex    *sync.Mutex
+	lastSeen map[protocol.EUI]time.Time
+	unsaved  map[protocol.EUI]time.Time
+	timeout  time.Duration
+}
+
+func newLivenessTracker(timeout time.Duration) livenessTracker {
+	return livenessTracker{
+		mutex:    &sync.Mutex{},
+		lastSeen: make(map[protocol.EUI]time.Time),
+		unsaved:  make(map[protocol.EUI]time.Time),
+		timeout:  timeout,
+	}
+}
+
+// Seen records activity from the gateway
+func (l *livenessTracker) Seen(eui protocol.EUI, now time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.lastSeen[eui] = now
+	l.unsaved[eui] = now
+}
+
+// Unsaved returns the last seen times recorded since the previous call
+func (l *livenessTracker) Unsaved() map[protocol.EUI]time.Time {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	ret := l.unsaved
+	l.unsaved = make(map[protocol.EUI]time.Time)
+	return ret
+}
+
+// Expired returns the gateways that haven't been seen within the timeout.
+// The gateways are removed from the tracker until they are seen again.
+func (l *livenessTracker) Expired(now time.Time) []protocol.EUI {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	var ret []protocol.EUI
+	for eui, seen := range l.lastSeen {
+		if now.Sub(seen) >= l.timeout {
+			ret = append(ret, eui)
+			delete(l.lastSeen, eui)
+		}
+	}
+	return ret
+}
+
+// gatewayMonitor is shared by the forwarders. It records the last seen time
+// for the gateways, publishes gateway events and emits inactive events for
+// gateways that time out. The last seen times are written to the storage in
+// one batch by CheckLiveness rather than for every packet from the gateways.
+type gatewayMonitor struct {
+	storage  *storage.Storage
+	context  *server.Context
+	liveness livenessTracker
+}
+
+// newGatewayMonitor creates a new monitor. The timeout is read from the
+// configuration.
+func newGatewayMonitor(store *storage.Storage, context *server.Context) gatewayMonitor {
+	timeout := server.DefaultGatewayTimeout
+	if context.Config != nil && context.Config.GatewayTimeout > 0 {
+		timeout = context.Config.GatewayTimeout
+	}
+	return gatewayMonitor{
+		storage:  store,
+		context:  context,
+		liveness: newLivenessTracker(timeout),
+	}
+}
+
+// CheckInterval is the interval between each CheckLiveness call
+func (m *gatewayMonitor) CheckInterval() time.Duration {
+	return m.liveness.timeout / 4
+}
+
+// Seen records activity from the gateway
+func (m *gatewayMonitor) Seen(eui protocol.EUI) {
+	m.liveness.Seen(eui, time.Now())
+}
+
+// CheckLiveness stores the last seen times for the gateways and emits an
+// inactive event for gateways that haven't been seen within the timeout.
+func (m *gatewayMonitor) CheckLiveness() {
+	m.saveLastSeen()
+	for _, eui := range m.liveness.Expired(time.Now()) {
+		lg.Info("Gateway %s is inactive", eui)
+		m.Publish(eui, gwevents.NewInactive())
+	}
+}
+
+// saveLastSeen writes the last seen times recorded since the previous call to
+// the storage.
+func (m *gatewayMonitor) saveLastSeen() {
+	unsaved := m.liveness.Unsaved()
+	if m.storage == nil || len(unsaved) == 0 {
+		return
+	}
+	lastSeen := make(map[protocol.EUI]int64)
+	for eui, seen := range unsaved {
+		lastSeen[eui] = seen.UnixNano()
+	}
+	if err := m.storage.UpdateGatewaysLastSeen(lastSeen); err != nil {
+		lg.Warning("Unable to update last seen time for %d gateways: %v", len(lastSeen), err)
+	}
+}
+
+// UpdateStats stores the status report from the gateway
+func (m *gatewayMonitor) UpdateStats(eui protocol.EUI, stat Stat) {
+	if m.storage == nil {
+		return
+	}
+	err := m.storage.UpdateGatewayStats(eui, model.GatewayStats{
+		Time:         stat.Time,
+		Latitude:     stat.Latitude,
+		Longitude:    stat.Longitude,
+		Altitude:     stat.Altitude,
+		RxReceived:   stat.RxNb,
+		RxOK:         stat.RxOK,
+		RxForwarded:  stat.RxFw,
+		AckRatio:     stat.AckR,
+		DownReceived: stat.DwNb,
+		TxEmitted:    stat.TxNb,
+		Updated:      time.Now().UnixNano(),
+	})
+	if err != nil && err != storage.ErrNotFound {
+		lg.Warning("Unable to update stats for gateway %s: %v", eui, err)
+	}
+}
+
+// Transmitted publishes the downlink event for the application payload in a
+// packet the gateway has sent. Nil events and events without an application
+// router are ignored.
+func (m *gatewayMonitor) Transmitted(downlink *server.PayloadMessage) {
+	if downlink == nil || m.context.AppRouter == nil {
+		return
+	}
+	m.context.AppRouter.Publish(downlink.Application.AppEUI, downlink)
+}
+
+// Publish publishes a gateway event to the subscribers. Events are ignored if
+// there's no event router.
+func (m *gatewayMonitor) Publish(eui protocol.EUI, ev gwevents.GwEvent) {
+	if m.context.GwEventRouter == nil {
+		return
+	}
+	m.context.GwEventRouter.Publish(eui, ev)
+}
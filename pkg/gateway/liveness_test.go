@@ -0,0 +1,81 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+)
+
+// waitForLastSeen waits until the monitor has stored the last seen time for
+// the gateway. The times are stored at every liveness check.
+func waitForLastSeen(t *testing.T, eui protocol.EUI) model.Gateway {
+	var gw model.Gateway
+	var err error
+	for i := 0; i < 100; i++ {
+		gw, err = gwStorage.GetGateway(eui)
+		if err == nil && gw.LastSeen != 0 {
+			return gw
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Gateway should be marked as seen: %+v (%v)", gw, err)
+	return gw
+}
+
+func TestLivenessTracker(t *testing.T) {
+	tracker := newLivenessTracker(time.Minute)
+	eui1 := protocol.EUIFromInt64(1)
+	eui2 := protocol.EUIFromInt64(2)
+	now := time.Now()
+
+	tracker.Seen(eui1, now)
+	tracker.Seen(eui2, now)
+	tracker.Seen(eui2, now.Add(30*time.Second))
+	unsaved := tracker.Unsaved()
+	if len(unsaved) != 2 || !unsaved[eui2].Equal(now.Add(30*time.Second)) {
+		t.Fatalf("Expected the last seen time for both gateways: %v", unsaved)
+	}
+	if len(tracker.Unsaved()) != 0 {
+		t.Fatal("Last seen times should only be returned once")
+	}
+	if len(tracker.Expired(now.Add(59*time.Second))) != 0 {
+		t.Fatal("No gateways should expire")
+	}
+	expired := tracker.Expired(now.Add(time.Minute))
+	if len(expired) != 1 || expired[0] != eui1 {
+		t.Fatalf("Expected gateway 1 to expire: %v", expired)
+	}
+	if len(tracker.Expired(now.Add(time.Minute))) != 0 {
+		t.Fatal("Gateways should only expire once")
+	}
+	expired = tracker.Expired(now.Add(2 * time.Minute))
+	if len(expired) != 1 || expired[0] != eui2 {
+		t.Fatalf("Expected gateway 2 to expire: %v", expired)
+	}
+}
+
+func TestRXDataStatus(t *testing.T) {
+	rxData := RXData{}
+	if _, ok := rxData.Status(); ok {
+		t.Fatal("Should not have status without a stat block")
+	}
+	if err := json.Unmarshal([]byte(`{"stat":{"time":"2014-01-12 08:59:28 GMT","lati":46.24,"long":3.2523,"alti":145,"rxnb":2,"rxok":2,"rxfw":2,"ackr":100.0,"dwnb":2,"txnb":2}}`), &rxData); err != nil {
+		t.Fatal(err)
+	}
+	stat, ok := rxData.Status()
+	if !ok || stat.RxNb != 2 || stat.Altitude != 145 || stat.AckR != 100.0 {
+		t.Fatalf("Incorrect status: %+v", stat)
+	}
+
+	rxData = RXData{}
+	if err := json.Unmarshal([]byte(`{"stat":[{"rxnb":1},{"rxnb":3}]}`), &rxData); err != nil {
+		t.Fatal(err)
+	}
+	stat, ok = rxData.Status()
+	if !ok || stat.RxNb != 3 {
+		t.Fatalf("Expected last status in list: %+v", stat)
+	}
+}
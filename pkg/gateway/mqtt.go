@@ -0,0 +1,421 @@
+package gateway
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/lab5e/lospan/pkg/events/gwevents"
+	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+	"github.com/lab5e/lospan/pkg/storage"
+)
+
+const (
+	// mqttTimeout is the maximum time to wait for publish and subscribe
+	// requests to complete
+	mqttTimeout = 5 * time.Second
+	// mqttDisconnectQuiesce is the time (in ms) to wait for pending work
+	// when disconnecting from the broker
+	mqttDisconnectQuiesce = 250
+)
+
+// MQTTForwarder is a gateway forwarder for gateways that connect through a
+// MQTT broker. A gateway bridge running on (or next to) the gateway
+// publishes the uplinks, stats and acknowledgements as JSON on the
+// <prefix>/<gateway id>/event/<type> topics and subscribes to the downlinks
+// on <prefix>/<gateway id>/command/down. The gateway ID is the EUI as hex
+// digits. Gateways can't be checked by their IP address so the strict IP
+// setting is ignored.
+type MQTTForwarder struct {
+	input    chan server.GatewayPacket // Input to the gateway, ie data that should be sent to the gateway
+	output   chan server.GatewayPacket // Output from the gateway; ie data received from the gateway
+	storage  *storage.Storage
+	context  *server.Context
+	monitor  gatewayMonitor
+	plans    gatewayPlans
+	prefix   string      // Topic prefix
+	client   mqtt.Client // MQTT client. Created when the forwarder starts
+	mutex    *sync.Mutex // Mutex for the gateways and pending maps
+	gateways map[protocol.EUI]bool
+	pending  map[uint32]pendingDownlink
+	closed   bool            // Set when the forwarder shuts down
+	handlers *sync.WaitGroup // Running message handlers
+}
+
+// NewMQTTForwarder creates a new MQTT forwarder. The broker and topic prefix
+// are read from the configuration. Gateways are checked against the storage
+// unless the gateway checks are disabled in the configuration.
+func NewMQTTForwarder(storage *storage.Storage, context *server.Context) *MQTTForwarder {
+	return &MQTTForwarder{
+		input:    make(chan server.GatewayPacket),
+		output:   make(chan server.GatewayPacket),
+		storage:  storage,
+		context:  context,
+		monitor:  newGatewayMonitor(storage, context),
+		plans:    newGatewayPlans(storage, context.Config),
+		prefix:   server.DefaultMQTTTopicPrefix,
+		mutex:    &sync.Mutex{},
+		gateways: make(map[protocol.EUI]bool),
+		pending:  make(map[uint32]pendingDownlink),
+		handlers: &sync.WaitGroup{},
+	}
+}
+
+// Start connects to the broker and launches the forwarder. It does not
+// return until the forwarder shuts down. The forwarder reconnects to the
+// broker if the connection is lost.
+func (m *MQTTForwarder) Start() {
+	opts := mqtt.NewClientOptions()
+	broker := server.DefaultMQTTBroker
+	if cfg := m.context.Config; cfg != nil {
+		if cfg.MQTTBroker != "" {
+			broker = cfg.MQTTBroker
+		}
+		if cfg.MQTTTopicPrefix != "" {
+			m.prefix = strings.TrimSuffix(cfg.MQTTTopicPrefix, "/")
+		}
+		opts.SetUsername(cfg.MQTTUsername)
+		opts.SetPassword(cfg.MQTTPassword)
+	}
+	opts.AddBroker(broker)
+	opts.SetClientID(fmt.Sprintf("lospan-%08x", rand.Uint32()))
+	opts.SetAutoReconnect(true)
+	opts.SetConnectRetry(true)
+	opts.SetOnConnectHandler(m.subscribe)
+	opts.SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+		lg.Warning("Lost connection to MQTT broker: %v", err)
+	})
+
+	m.client = mqtt.NewClient(opts)
+	lg.Info("MQTT forwarder connecting to %s", broker)
+	m.client.Connect()
+	m.mainLoop()
+}
+
+// Stop stops the forwarder and closes the channels
+func (m *MQTTForwarder) Stop() {
+	close(m.input)
+}
+
+// Output returns the output channel for the gateway. A message will be sent
+// on this channel every time a gateway has sent an uplink.
+func (m *MQTTForwarder) Output() <-chan server.GatewayPacket {
+	return m.output
+}
+
+// Input returns the input channel for the gateway. When a message is received
+// on this channel the message will be published to the gateway.
+func (m *MQTTForwarder) Input() chan<- server.GatewayPacket {
+	return m.input
+}
+
+// HasGateway returns true if the gateway is connected to the broker
+func (m *MQTTForwarder) HasGateway(eui protocol.EUI) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.gateways[eui]
+}
+
+func (m *MQTTForwarder) mainLoop() {
+	livenessTicker := time.NewTicker(m.monitor.CheckInterval())
+	defer livenessTicker.Stop()
+	for {
+		select {
+		case <-livenessTicker.C:
+			m.expireDownlinks()
+			m.monitor.CheckLiveness()
+
+		case val, ok := <-m.input:
+			if !ok {
+				lg.Debug("Input channel for MQTT forwarder closed. Terminating")
+				m.shutdown()
+				return
+			}
+			m.sendDownlink(val)
+		}
+	}
+}
+
+// shutdown disconnects from the broker. The output channel is closed when
+// all of the message handlers have terminated.
+func (m *MQTTForwarder) shutdown() {
+	m.mutex.Lock()
+	m.closed = true
+	m.mutex.Unlock()
+
+	m.client.Disconnect(mqttDisconnectQuiesce)
+	m.handlers.Wait()
+	close(m.output)
+}
+
+// expireDownlinks discards downlinks that the gateways haven't acknowledged
+func (m *MQTTForwarder) expireDownlinks() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for id, p := range m.pending {
+		if time.Since(p.created) > pendingDownlinkExpiry {
+			lg.Warning("No ack for downlink %d to gateway %s", id, p.gatewayEUI)
+			delete(m.pending, id)
+		}
+	}
+}
+
+// subscribe subscribes to the gateway topics. This is called every time
+// the client connects to the broker.
+func (m *MQTTForwarder) subscribe(client mqtt.Client) {
+	lg.Info("MQTT forwarder connected to broker")
+	filters := map[string]byte{
+		m.prefix + "/+/event/+": 0,
+		m.prefix + "/+/state/+": 0,
+	}
+	token := client.SubscribeMultiple(filters, m.handleMessage)
+	if !token.WaitTimeout(mqttTimeout) {
+		lg.Error("Timed out subscribing to the gateway topics")
+		return
+	}
+	if err := token.Error(); err != nil {
+		lg.Error("Unable to subscribe to the gateway topics: %v", err)
+	}
+}
+
+// beginHandler registers a running message handler. It returns false if
+// the forwarder is shutting down.
+func (m *MQTTForwarder) beginHandler() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.closed {
+		return false
+	}
+	m.handlers.Add(1)
+	return true
+}
+
+// checkGateway verifies that the gateway exists
+func (m *MQTTForwarder) checkGateway(eui protocol.EUI) error {
+	if m.context.Config == nil || m.context.Config.DisableGatewayChecks {
+		return nil
+	}
+	if m.storage == nil {
+		return fmt.Errorf("no storage for gateway lookup")
+	}
+	if _, err := m.storage.GetGateway(eui); err != nil {
+		return fmt.Errorf("unknown gateway %s", eui)
+	}
+	return nil
+}
+
+// setConnected updates the connection state for the gateway
+func (m *MQTTForwarder) setConnected(eui protocol.EUI, connected bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if connected {
+		m.gateways[eui] = true
+		return
+	}
+	delete(m.gateways, eui)
+}
+
+// handleMessage decodes and handles a message from a gateway bridge. The
+// topic is <prefix>/<gateway id>/<event|state>/<type>
+func (m *MQTTForwarder) handleMessage(_ mqtt.Client, msg mqtt.Message) {
+	if !m.beginHandler() {
+		return
+	}
+	defer m.handlers.Done()
+
+	fields := strings.Split(strings.TrimPrefix(msg.Topic(), m.prefix+"/"), "/")
+	if len(fields) != 3 {
+		lg.Debug("Ignoring message on topic %s", msg.Topic())
+		return
+	}
+	eui, err := protocol.EUIFromString(fields[0])
+	if err != nil {
+		lg.Info("Invalid gateway ID in topic %s: %v", msg.Topic(), err)
+		return
+	}
+	if err := m.checkGateway(eui); err != nil {
+		lg.Info("Ignoring message from gateway: %v", err)
+		return
+	}
+
+	switch fields[1] + "/" + fields[2] {
+	case bridgeUplinkEvent:
+		uplink := bridgeUplinkFrame{}
+		if err := json.Unmarshal(msg.Payload(), &uplink); err != nil {
+			lg.Info("Unable to unmarshal uplink from gateway %s: %v (json=%s)", eui, err, msg.Payload())
+			return
+		}
+		m.seen(eui)
+		m.handleUplink(eui, uplink)
+
+	case bridgeStatsEvent:
+		stats := bridgeGatewayStats{}
+		if err := json.Unmarshal(msg.Payload(), &stats); err != nil {
+			lg.Info("Unable to unmarshal stats from gateway %s: %v (json=%s)", eui, err, msg.Payload())
+			return
+		}
+		m.seen(eui)
+		m.monitor.UpdateStats(eui, stats.Stat())
+		m.monitor.Publish(eui, gwevents.NewKeepAlive())
+
+	case bridgeAckEvent:
+		ack := bridgeTxAck{}
+		if err := json.Unmarshal(msg.Payload(), &ack); err != nil {
+			lg.Info("Unable to unmarshal ack from gateway %s: %v (json=%s)", eui, err, msg.Payload())
+			return
+		}
+		m.seen(eui)
+		m.handleAck(eui, ack)
+
+	case bridgeConnState:
+		state := bridgeConnectionState{}
+		if err := json.Unmarshal(msg.Payload(), &state); err != nil {
+			lg.Info("Unable to unmarshal connection state from gateway %s: %v (json=%s)", eui, err, msg.Payload())
+			return
+		}
+		lg.Info("Gateway %s is %s", eui, state.State)
+		if state.State == bridgeStateOnline {
+			m.seen(eui)
+			return
+		}
+		m.setConnected(eui, false)
+
+	default:
+		lg.Debug("Don't know how to handle %s from gateway %s", msg.Topic(), eui)
+	}
+}
+
+// seen marks the gateway as connected and records the activity
+func (m *MQTTForwarder) seen(eui protocol.EUI) {
+	m.setConnected(eui, true)
+	m.monitor.Seen(eui)
+}
+
+// handleUplink forwards an uplink to the pipeline
+func (m *MQTTForwarder) handleUplink(eui protocol.EUI, uplink bridgeUplinkFrame) {
+	rxpk := uplink.Rxpk()
+	// The bridge reports the frequency so the channel configuration is only
+	// used for the channel mask.
+	plan, channels := m.plans.Lookup(eui)
+	gwPacket := server.GatewayPacket{
+		RawMessage: uplink.PHYPayload,
+		Radio:      radioContext(plan, channels, rxpk.Frequency, rxpk.DataRateID, rxpk.ConcentratorChannel, rxpk.ConcentratorRFChain, rxpk.RSSI, rxpk.LoraSNRRatio),
+		Gateway: server.GatewayContext{
+			GatewayEUI:    eui,
+			GatewayClock:  rxpk.Timestamp,
+			BridgeContext: uplink.RxInfo.Context,
+		},
+		ReceivedAt: time.Now(),
+	}
+	rxData, err := json.Marshal(RXData{Data: []Rxpk{rxpk}})
+	if err == nil {
+		m.monitor.Publish(eui, gwevents.NewRx(string(rxData)))
+	}
+	m.output <- gwPacket
+}
+
+// sendDownlink publishes a downlink to the gateway. Immediate downlinks are
+// sent immediately, downlinks with a GPS time at the GPS time and the rest
+// in the RX1 window relative to the uplink context.
+func (m *MQTTForwarder) sendDownlink(packet server.GatewayPacket) {
+	eui := packet.Gateway.GatewayEUI
+	plan := m.plans.DownlinkBand(packet)
+	dataRate, err := plan.GetDataRate(packet.Radio.DataRate)
+	if err != nil {
+		lg.Warning("Unable to look up data rate for downlink to gateway %s: %v", eui, err)
+		return
+	}
+	encoding, err := plan.Encoding(dataRate)
+	if err != nil {
+		lg.Warning("Unable to look up encoding for downlink to gateway %s: %v", eui, err)
+		return
+	}
+
+	txInfo := bridgeDownlinkTxInfo{
+		Frequency: toHz(packet.Radio.Frequency),
+		Modulation: bridgeModulation{LoRa: &bridgeLoRaModulation{
+			Bandwidth:             encoding.Bandwidth * 1000,
+			SpreadingFactor:       encoding.SpreadFactor,
+			CodeRate:              "CR_4_5",
+			PolarizationInversion: true,
+		}},
+		Context: packet.Gateway.BridgeContext,
+	}
+	txpk := Txpk{
+		Immediate:    packet.Immediate,
+		Frequency:    packet.Radio.Frequency,
+		Data:         base64.StdEncoding.EncodeToString(packet.RawMessage),
+		Modulation:   "LORA",
+		EccCoding:    "4/5",
+		LoraInvPol:   true,
+		PayloadSize:  len(packet.RawMessage),
+		LoRaDataRate: packet.Radio.DataRate,
+	}
+	switch {
+	case packet.Immediate:
+		txInfo.Timing.Immediately = &struct{}{}
+	case packet.GPSTime != 0:
+		txInfo.Timing.GPSEpoch = &bridgeGPSEpochTiming{TimeSinceGPSEpoch: bridgeDuration(packet.GPSTime)}
+		txpk.GPSTime = uint64(packet.GPSTime / time.Millisecond)
+	default:
+		txInfo.Timing.Delay = &bridgeDelayTiming{Delay: bridgeDuration(time.Duration(packet.Radio.TxDelay()) * time.Second)}
+		txpk.Timestamp = packet.Gateway.GatewayClock + 1000000*uint32(packet.Radio.TxDelay())
+	}
+
+	downlink := bridgeDownlinkFrame{
+		DownlinkID: rand.Uint32(),
+		GatewayID:  bridgeGatewayID(eui),
+		Items:      []bridgeDownlinkItem{{PHYPayload: packet.RawMessage, TxInfo: txInfo}},
+	}
+	buf, err := json.Marshal(downlink)
+	if err != nil {
+		lg.Warning("Unable to marshal downlink for gateway %s: %v", eui, err)
+		return
+	}
+	txData, err := json.Marshal(TXData{Data: txpk})
+	if err != nil {
+		lg.Warning("Unable to marshal txpk for gateway %s: %v", eui, err)
+	}
+	m.mutex.Lock()
+	m.pending[downlink.DownlinkID] = pendingDownlink{gatewayEUI: eui, txData: string(txData), downlink: packet.Downlink, created: time.Now()}
+	m.mutex.Unlock()
+
+	topic := fmt.Sprintf("%s/%s/%s", m.prefix, downlink.GatewayID, bridgeDownCommand)
+	token := m.client.Publish(topic, 0, false, buf)
+	if !token.WaitTimeout(mqttTimeout) {
+		lg.Warning("Timed out sending downlink to gateway %s", eui)
+		return
+	}
+	if err := token.Error(); err != nil {
+		lg.Warning("Unable to send downlink to gateway %s: %v", eui, err)
+	}
+}
+
+// handleAck handles the acknowledgement for a downlink. A Tx event and the
+// downlink event for the application payload are published if the gateway has
+// sent the downlink.
+func (m *MQTTForwarder) handleAck(eui protocol.EUI, ack bridgeTxAck) {
+	m.mutex.Lock()
+	p, ok := m.pending[ack.DownlinkID]
+	delete(m.pending, ack.DownlinkID)
+	m.mutex.Unlock()
+
+	if !ok {
+		lg.Info("Gateway %s acknowledged unknown downlink %d", eui, ack.DownlinkID)
+		return
+	}
+	if !ack.OK() {
+		lg.Warning("Gateway %s rejected downlink %d: %+v", eui, ack.DownlinkID, ack.Items)
+		return
+	}
+	lg.Debug("Gateway %s sent downlink %d after %v", eui, ack.DownlinkID, time.Since(p.created))
+	m.monitor.Publish(eui, gwevents.NewTx(p.txData))
+	m.monitor.Transmitted(p.downlink)
+}
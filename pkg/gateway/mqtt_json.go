@@ -0,0 +1,252 @@
+package gateway
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/protocol"
+)
+
+// The topics used by the MQTT gateway bridge. The topics are prefixed by
+// the topic prefix and the gateway ID, ie "gateway/0102030405060708/event/up"
+const (
+	bridgeUplinkEvent  = "event/up"
+	bridgeStatsEvent   = "event/stats"
+	bridgeAckEvent     = "event/ack"
+	bridgeConnState    = "state/conn"
+	bridgeDownCommand  = "command/down"
+	bridgeStateOnline  = "ONLINE"
+	bridgeStateOffline = "OFFLINE"
+	bridgeAckOK        = "OK"
+)
+
+// bridgeLoRaModulation is the LoRa modulation parameters. The bandwidth is
+// in Hz.
+type bridgeLoRaModulation struct {
+	Bandwidth             uint32 `json:"bandwidth"`
+	SpreadingFactor       uint8  `json:"spreadingFactor"`
+	CodeRate              string `json:"codeRate"`
+	PolarizationInversion bool   `json:"polarizationInversion,omitempty"`
+}
+
+// bridgeFSKModulation is the FSK modulation parameters
+type bridgeFSKModulation struct {
+	Datarate           uint32 `json:"datarate"`
+	FrequencyDeviation uint32 `json:"frequencyDeviation,omitempty"`
+}
+
+// bridgeModulation holds either the LoRa or the FSK modulation
+type bridgeModulation struct {
+	LoRa *bridgeLoRaModulation `json:"lora,omitempty"`
+	FSK  *bridgeFSKModulation  `json:"fsk,omitempty"`
+}
+
+// DataRate returns the data rate in the format used by the gateways, ie
+// "SF7BW125"
+func (m *bridgeModulation) DataRate() string {
+	if m.FSK != nil {
+		return band.Encoding{Modulation: band.FSK, BitRate: m.FSK.Datarate}.GatewayDataRate()
+	}
+	if m.LoRa == nil {
+		return ""
+	}
+	return band.Encoding{
+		Modulation:   band.LoRa,
+		SpreadFactor: m.LoRa.SpreadingFactor,
+		Bandwidth:    m.LoRa.Bandwidth / 1000,
+	}.GatewayDataRate()
+}
+
+// bridgeUplinkTxInfo is the transmission parameters for an uplink. The
+// frequency is in Hz.
+type bridgeUplinkTxInfo struct {
+	Frequency  uint32           `json:"frequency"`
+	Modulation bridgeModulation `json:"modulation"`
+}
+
+// bridgeRxInfo is the reception parameters for an uplink. The context is
+// opaque and must be returned to the gateway in the downlinks.
+type bridgeRxInfo struct {
+	GatewayID string  `json:"gatewayId"`
+	UplinkID  uint32  `json:"uplinkId"`
+	Time      string  `json:"time,omitempty"`
+	RSSI      int32   `json:"rssi"`
+	SNR       float32 `json:"snr"`
+	Channel   uint8   `json:"channel"`
+	RFChain   uint8   `json:"rfChain"`
+	Context   []byte  `json:"context,omitempty"`
+}
+
+// bridgeUplinkFrame is an uplink from the gateway bridge
+type bridgeUplinkFrame struct {
+	PHYPayload []byte             `json:"phyPayload"`
+	TxInfo     bridgeUplinkTxInfo `json:"txInfo"`
+	RxInfo     bridgeRxInfo       `json:"rxInfo"`
+}
+
+// Timestamp returns the concentrator counter from the context. The Semtech
+// concentrators use a 32-bit counter as the context. 0 is returned if the
+// context is something else.
+func (u *bridgeUplinkFrame) Timestamp() uint32 {
+	if len(u.RxInfo.Context) != 4 {
+		return 0
+	}
+	return binary.BigEndian.Uint32(u.RxInfo.Context)
+}
+
+// Rxpk returns the uplink in the Semtech packet forwarder format. This is
+// used for the gateway events.
+func (u *bridgeUplinkFrame) Rxpk() Rxpk {
+	modulation := "LORA"
+	if u.TxInfo.Modulation.FSK != nil {
+		modulation = "FSK"
+	}
+	rxpk := Rxpk{
+		Time:                u.RxInfo.Time,
+		Timestamp:           u.Timestamp(),
+		Frequency:           fromHz(u.TxInfo.Frequency),
+		ConcentratorChannel: u.RxInfo.Channel,
+		ConcentratorRFChain: u.RxInfo.RFChain,
+		ModulationID:        modulation,
+		DataRateID:          u.TxInfo.Modulation.DataRate(),
+		RSSI:                u.RxInfo.RSSI,
+		LoraSNRRatio:        u.RxInfo.SNR,
+		PayloadSize:         uint32(len(u.PHYPayload)),
+		RFPackets:           base64.StdEncoding.EncodeToString(u.PHYPayload),
+	}
+	if u.TxInfo.Modulation.LoRa != nil {
+		rxpk.CodingRateID = bridgeCodeRate(u.TxInfo.Modulation.LoRa.CodeRate)
+	}
+	return rxpk
+}
+
+// bridgeCodeRate converts the code rate from the bridge format ("CR_4_5")
+// into the packet forwarder format ("4/5")
+func bridgeCodeRate(codeRate string) string {
+	return strings.Replace(strings.TrimPrefix(codeRate, "CR_"), "_", "/", 1)
+}
+
+// bridgeDelayTiming sends the downlink after a delay relative to the
+// uplink context
+type bridgeDelayTiming struct {
+	Delay string `json:"delay"`
+}
+
+// bridgeGPSEpochTiming sends the downlink at a GPS time
+type bridgeGPSEpochTiming struct {
+	TimeSinceGPSEpoch string `json:"timeSinceGpsEpoch"`
+}
+
+// bridgeTiming is the timing for a downlink. Only one of the fields is set.
+type bridgeTiming struct {
+	Immediately *struct{}             `json:"immediately,omitempty"`
+	Delay       *bridgeDelayTiming    `json:"delay,omitempty"`
+	GPSEpoch    *bridgeGPSEpochTiming `json:"gpsEpoch,omitempty"`
+}
+
+// bridgeDownlinkTxInfo is the transmission parameters for a downlink. The
+// power is in dBm. It is left out when it's 0 and the bridge uses the
+// gateway's default TX power.
+type bridgeDownlinkTxInfo struct {
+	Frequency  uint32           `json:"frequency"`
+	Power      int32            `json:"power,omitempty"`
+	Modulation bridgeModulation `json:"modulation"`
+	Timing     bridgeTiming     `json:"timing"`
+	Context    []byte           `json:"context,omitempty"`
+}
+
+// bridgeDownlinkItem is a single downlink. The gateway bridge tries each of
+// the items until one is accepted by the concentrator.
+type bridgeDownlinkItem struct {
+	PHYPayload []byte               `json:"phyPayload"`
+	TxInfo     bridgeDownlinkTxInfo `json:"txInfo"`
+}
+
+// bridgeDownlinkFrame is the downlink command sent to the gateway bridge
+type bridgeDownlinkFrame struct {
+	DownlinkID uint32               `json:"downlinkId"`
+	GatewayID  string               `json:"gatewayId"`
+	Items      []bridgeDownlinkItem `json:"items"`
+}
+
+// bridgeTxAckItem is the status for each of the items in the downlink
+type bridgeTxAckItem struct {
+	Status string `json:"status"`
+}
+
+// bridgeTxAck is the acknowledgement for a downlink
+type bridgeTxAck struct {
+	GatewayID  string            `json:"gatewayId"`
+	DownlinkID uint32            `json:"downlinkId"`
+	Items      []bridgeTxAckItem `json:"items"`
+}
+
+// OK returns true if one of the downlink items was sent by the gateway
+func (a *bridgeTxAck) OK() bool {
+	for _, v := range a.Items {
+		if v.Status == bridgeAckOK {
+			return true
+		}
+	}
+	return false
+}
+
+// bridgeLocation is the location of the gateway
+type bridgeLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Altitude  float64 `json:"altitude"`
+}
+
+// bridgeGatewayStats is the status report from the gateway bridge
+type bridgeGatewayStats struct {
+	GatewayID           string          `json:"gatewayId"`
+	Time                string          `json:"time"`
+	Location            *bridgeLocation `json:"location,omitempty"`
+	RxPacketsReceived   uint32          `json:"rxPacketsReceived"`
+	RxPacketsReceivedOK uint32          `json:"rxPacketsReceivedOk"`
+	TxPacketsReceived   uint32          `json:"txPacketsReceived"`
+	TxPacketsEmitted    uint32          `json:"txPacketsEmitted"`
+}
+
+// Stat returns the status report in the Semtech packet forwarder format.
+// The gateway bridge forwards all of the packets with a valid CRC.
+func (s *bridgeGatewayStats) Stat() Stat {
+	stat := Stat{
+		Time: s.Time,
+		RxNb: s.RxPacketsReceived,
+		RxOK: s.RxPacketsReceivedOK,
+		RxFw: s.RxPacketsReceivedOK,
+		DwNb: s.TxPacketsReceived,
+		TxNb: s.TxPacketsEmitted,
+	}
+	if s.Location != nil {
+		stat.Latitude = float32(s.Location.Latitude)
+		stat.Longitude = float32(s.Location.Longitude)
+		stat.Altitude = float32(s.Location.Altitude)
+	}
+	return stat
+}
+
+// bridgeConnectionState is the connection state for the gateway bridge. The
+// bridge sets OFFLINE as the last will.
+type bridgeConnectionState struct {
+	GatewayID string `json:"gatewayId"`
+	State     string `json:"state"`
+}
+
+// bridgeGatewayID returns the gateway ID used in the topics, ie the EUI as
+// lower case hex digits
+func bridgeGatewayID(eui protocol.EUI) string {
+	return strings.ToLower(strings.ReplaceAll(eui.String(), "-", ""))
+}
+
+// bridgeDuration formats the duration in the JSON format for protobuf
+// durations, ie "1.5s"
+func bridgeDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
@@ -0,0 +1,225 @@
+package gateway
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/lab5e/lospan/pkg/events/gwevents"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+	"github.com/lab5e/lospan/pkg/utils/mqtttest"
+)
+
+func TestBridgeModulation(t *testing.T) {
+	m := bridgeModulation{LoRa: &bridgeLoRaModulation{Bandwidth: 125000, SpreadingFactor: 9, CodeRate: "CR_4_5"}}
+	if m.DataRate() != "SF9BW125" {
+		t.Fatalf("Incorrect data rate: %s", m.DataRate())
+	}
+	if bridgeCodeRate("CR_4_5") != "4/5" {
+		t.Fatal("Incorrect code rate")
+	}
+	if bridgeDuration(time.Second) != "1s" || bridgeDuration(1500*time.Millisecond) != "1.5s" {
+		t.Fatal("Incorrect duration format")
+	}
+	if bridgeGatewayID(protocol.EUIFromInt64(0x0102030405060A0B)) != "0102030405060a0b" {
+		t.Fatal("Incorrect gateway ID")
+	}
+}
+
+func publishBridge(t *testing.T, client mqtt.Client, topic string, msg interface{}) {
+	buf, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := client.Publish(topic, 1, false, buf)
+	if !token.WaitTimeout(time.Second) || token.Error() != nil {
+		t.Fatalf("Unable to publish on %s: %v", topic, token.Error())
+	}
+}
+
+func TestMQTTForwarder(t *testing.T) {
+	gwEUI := protocol.EUIFromInt64(0x010203040506070D)
+	if err := gwStorage.CreateGateway(model.Gateway{GatewayEUI: gwEUI, IP: net.ParseIP("10.0.0.1"), StrictIP: true}); err != nil {
+		t.Fatal(err)
+	}
+	gwID := bridgeGatewayID(gwEUI)
+
+	broker, err := mqtttest.NewBroker("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+
+	router := server.NewEventRouter[protocol.EUI, gwevents.GwEvent](5)
+	appRouter := server.NewEventRouter[protocol.EUI, *server.PayloadMessage](5)
+	context := server.Context{GwEventRouter: &router, AppRouter: &appRouter, Config: &server.Parameters{
+		MQTTBroker:      broker.URL(),
+		MQTTTopicPrefix: "gateway",
+		GatewayTimeout:  400 * time.Millisecond,
+	}}
+	forwarder := NewMQTTForwarder(gwStorage, &context)
+	go forwarder.Start()
+
+	events := router.Subscribe(gwEUI)
+	defer router.Unsubscribe(events)
+
+	opts := mqtt.NewClientOptions().AddBroker(broker.URL()).SetClientID("bridge")
+	bridge := mqtt.NewClient(opts)
+	if token := bridge.Connect(); !token.WaitTimeout(time.Second) || token.Error() != nil {
+		t.Fatalf("Unable to connect to broker: %v", token.Error())
+	}
+	defer bridge.Disconnect(0)
+	downlinks := make(chan []byte, 5)
+	token := bridge.Subscribe(fmt.Sprintf("gateway/%s/command/down", gwID), 0, func(_ mqtt.Client, msg mqtt.Message) {
+		downlinks <- msg.Payload()
+	})
+	if !token.WaitTimeout(time.Second) || token.Error() != nil {
+		t.Fatalf("Unable to subscribe: %v", token.Error())
+	}
+	if !broker.WaitForSubscriber(fmt.Sprintf("gateway/%s/event/up", gwID), time.Second) {
+		t.Fatal("Forwarder hasn't subscribed to the gateway topics")
+	}
+
+	if forwarder.HasGateway(gwEUI) {
+		t.Fatal("Gateway shouldn't be connected")
+	}
+
+	phyPayload, _ := base64.StdEncoding.DecodeString("gOZy5gGAAQALqBJvwTWKKB0=")
+	uplink := bridgeUplinkFrame{
+		PHYPayload: phyPayload,
+		TxInfo: bridgeUplinkTxInfo{
+			Frequency:  868300000,
+			Modulation: bridgeModulation{LoRa: &bridgeLoRaModulation{Bandwidth: 125000, SpreadingFactor: 9, CodeRate: "CR_4_5"}},
+		},
+		RxInfo: bridgeRxInfo{
+			GatewayID: gwID,
+			RSSI:      -80,
+			SNR:       7.5,
+			Channel:   1,
+			Context:   binary.BigEndian.AppendUint32(nil, 0x12345678),
+		},
+	}
+	// Uplinks from unknown gateways are ignored
+	publishBridge(t, bridge, "gateway/0000000000000001/event/up", uplink)
+	publishBridge(t, bridge, fmt.Sprintf("gateway/%s/event/up", gwID), uplink)
+
+	var pkt server.GatewayPacket
+	select {
+	case pkt = <-forwarder.Output():
+	case <-time.After(time.Second):
+		t.Fatal("No uplink from forwarder")
+	}
+	if pkt.Gateway.GatewayEUI != gwEUI || pkt.Gateway.GatewayClock != 0x12345678 {
+		t.Fatalf("Incorrect gateway context: %+v", pkt.Gateway)
+	}
+	if pkt.Radio.DataRate != "SF9BW125" || toHz(pkt.Radio.Frequency) != 868300000 || pkt.Radio.Channel != 1 || pkt.Radio.RSSI != -80 {
+		t.Fatalf("Incorrect radio context: %+v", pkt.Radio)
+	}
+	if !forwarder.HasGateway(gwEUI) {
+		t.Fatal("Gateway should be connected")
+	}
+
+	// Class A downlink in RX1. The downlink event is published when the
+	// gateway acknowledges the downlink.
+	appEUI := protocol.EUIFromInt64(0x0A)
+	appEvents := appRouter.Subscribe(appEUI)
+	defer appRouter.Unsubscribe(appEvents)
+	pkt.Radio.RX1Delay = 1
+	pkt.Downlink = &server.PayloadMessage{Type: server.DownlinkEvent, Application: model.Application{AppEUI: appEUI}}
+	forwarder.Input() <- pkt
+	downlink := bridgeDownlinkFrame{}
+	select {
+	case buf := <-downlinks:
+		if err := json.Unmarshal(buf, &downlink); err != nil {
+			t.Fatal(err)
+		}
+		// The bridge sets the TX power
+		if strings.Contains(string(buf), `"power"`) {
+			t.Fatalf("Downlink should not set the TX power: %s", buf)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("No downlink from forwarder")
+	}
+	if downlink.GatewayID != gwID || len(downlink.Items) != 1 {
+		t.Fatalf("Incorrect downlink: %+v", downlink)
+	}
+	txInfo := downlink.Items[0].TxInfo
+	if txInfo.Frequency != 868300000 || txInfo.Timing.Delay == nil || txInfo.Timing.Delay.Delay != "1s" ||
+		string(txInfo.Context) != string(uplink.RxInfo.Context) || txInfo.Modulation.DataRate() != "SF9BW125" {
+		t.Fatalf("Incorrect downlink parameters: %+v", txInfo)
+	}
+
+	publishBridge(t, bridge, fmt.Sprintf("gateway/%s/event/ack", gwID), bridgeTxAck{
+		GatewayID:  gwID,
+		DownlinkID: downlink.DownlinkID,
+		Items:      []bridgeTxAckItem{{Status: bridgeAckOK}},
+	})
+
+	var seen []gwevents.GwEvent
+	timeout := time.After(time.Second)
+	for len(seen) == 0 || seen[len(seen)-1].Type != gwevents.Tx {
+		select {
+		case ev := <-events:
+			seen = append(seen, ev)
+		case <-timeout:
+			t.Fatalf("No Tx event after ack (got %+v)", seen)
+		}
+	}
+	if len(seen) != 2 || seen[0].Type != gwevents.Rx {
+		t.Fatalf("Incorrect events: %+v", seen)
+	}
+	select {
+	case ev := <-appEvents:
+		if ev != pkt.Downlink {
+			t.Fatalf("Incorrect downlink event: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("No downlink event after ack")
+	}
+
+	publishBridge(t, bridge, fmt.Sprintf("gateway/%s/event/stats", gwID), bridgeGatewayStats{
+		GatewayID:           gwID,
+		Location:            &bridgeLocation{Latitude: 63.4, Longitude: 10.4},
+		RxPacketsReceived:   10,
+		RxPacketsReceivedOK: 8,
+		TxPacketsEmitted:    1,
+	})
+	select {
+	case ev := <-events:
+		if ev.Type != gwevents.KeepAlive {
+			t.Fatalf("Expected keepalive after stats: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("No event after stats")
+	}
+	gw := waitForLastSeen(t, gwEUI)
+	if gw.Stats.RxReceived != 10 || gw.Stats.RxOK != 8 || gw.Stats.TxEmitted != 1 {
+		t.Fatalf("Gateway stats should be updated: %+v (%v)", gw, err)
+	}
+
+	publishBridge(t, bridge, fmt.Sprintf("gateway/%s/state/conn", gwID), bridgeConnectionState{GatewayID: gwID, State: bridgeStateOffline})
+	for i := 0; i < 100 && forwarder.HasGateway(gwEUI); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if forwarder.HasGateway(gwEUI) {
+		t.Fatal("Gateway should be disconnected")
+	}
+
+	forwarder.Stop()
+	select {
+	case _, ok := <-forwarder.Output():
+		if ok {
+			t.Fatal("Expected output channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Output channel not closed after Stop")
+	}
+}
@@ -0,0 +1,101 @@
+package gateway
+
+import (
+	"sync"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+	"github.com/lab5e/lospan/pkg/storage"
+)
+
+// gatewayPlans looks up the frequency plan and concentrator channels for the
+// gateways. The band instances are read only so a single instance is used for
+// each frequency plan. The band settings (ie the dwell time limit) are read
+// from the configuration.
+type gatewayPlans struct {
+	storage *storage.Storage
+	config  *server.Parameters
+	mutex   *sync.Mutex
+	bands   map[band.FrequencyBandType]band.FrequencyPlan
+}
+
+func newGatewayPlans(storage *storage.Storage, config *server.Parameters) gatewayPlans {
+	return gatewayPlans{
+		storage: storage,
+		config:  config,
+		mutex:   &sync.Mutex{},
+		bands:   make(map[band.FrequencyBandType]band.FrequencyPlan),
+	}
+}
+
+// Band returns the band instance for a frequency plan. Unknown plans use the
+// EU868 band.
+func (g *gatewayPlans) Band(plan band.FrequencyBandType) band.FrequencyPlan {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if b, ok := g.bands[plan]; ok {
+		return b
+	}
+	b, err := g.config.NewBand(plan)
+	if err != nil {
+		lg.Error("Unable to create band instance for %s: %v. Using EU868", plan, err)
+		if b, err = band.NewBand(band.EU868Band); err != nil {
+			lg.Error("Unable to create EU868 band instance: %v", err)
+			return nil
+		}
+	}
+	g.bands[plan] = b
+	return b
+}
+
+// Lookup returns the frequency plan and the concentrator channels for the
+// gateway. Gateways that aren't in the store (ie when the gateway checks are
+// disabled) use the EU868 plan with the default channels.
+func (g *gatewayPlans) Lookup(eui protocol.EUI) (band.FrequencyPlan, model.ConcentratorConfig) {
+	gw := model.NewGateway()
+	if g.storage != nil {
+		var err error
+		if gw, err = g.storage.GetGateway(eui); err != nil {
+			lg.Debug("Unable to look up gateway %s: %v. Using default frequency plan", eui, err)
+			gw = model.NewGateway()
+		}
+	}
+	return g.Band(gw.Plan), gw.ChannelConfig()
+}
+
+// uplinkFrequency returns the frequency for a concentrator channel. The
+// channel configuration for the gateway is used if the channel is configured,
+// otherwise the frequency reported by the gateway is used.
+func uplinkFrequency(channels model.ConcentratorConfig, channel uint8, reported float32) float32 {
+	freq, err := channels.Frequency(channel)
+	if err != nil {
+		if !channels.Empty() {
+			lg.Warning("Unknown channel: %d. Using reported frequency %.4f MHz", channel, reported)
+		}
+		return reported
+	}
+	return freq
+}
+
+// channelMask returns the uplink channels for gateways in bands with fixed
+// channel plans. The mask is empty for other bands.
+func channelMask(plan band.FrequencyPlan, channels model.ConcentratorConfig) band.ChannelMask {
+	if plan == nil {
+		return band.ChannelMask{}
+	}
+	mask, _ := band.NewChannelMask(plan, channels.UplinkFrequencies())
+	return mask
+}
+
+// DownlinkBand returns the frequency plan for a downlink. Downlinks use the
+// band from the uplink when it is set.
+func (g *gatewayPlans) DownlinkBand(packet server.GatewayPacket) band.FrequencyPlan {
+	if packet.Radio.Band != nil {
+		return packet.Radio.Band
+	}
+	plan, _ := g.Lookup(packet.Gateway.GatewayEUI)
+	return plan
+}
@@ -0,0 +1,89 @@
+package gateway
+
+import (
+	"net"
+	"testing"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+	"github.com/lab5e/lospan/pkg/storage"
+)
+
+func TestGatewayPlans(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+
+	euGW := model.NewGateway()
+	euGW.GatewayEUI = protocol.EUIFromInt64(0x100)
+	euGW.IP = net.ParseIP("127.0.0.1")
+	usGW := model.NewGateway()
+	usGW.GatewayEUI = protocol.EUIFromInt64(0x101)
+	usGW.IP = net.ParseIP("127.0.0.1")
+	usGW.Plan = band.US915Band
+	if err := store.CreateGateway(euGW); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateGateway(usGW); err != nil {
+		t.Fatal(err)
+	}
+
+	plans := newGatewayPlans(store, nil)
+
+	plan, channels := plans.Lookup(euGW.GatewayEUI)
+	if plan.Name() != "EU 863-870MHz ISM Band" {
+		t.Fatalf("Expected EU868 plan but got %s", plan.Name())
+	}
+	if f := uplinkFrequency(channels, 3, 0); f != 867.1 {
+		t.Fatalf("Expected 867.1 for channel 3 but got %f", f)
+	}
+
+	plan, channels = plans.Lookup(usGW.GatewayEUI)
+	if plan.Name() != "US 902-928MHz ISM Band" {
+		t.Fatalf("Expected US915 plan but got %s", plan.Name())
+	}
+	if f := uplinkFrequency(channels, 3, 0); f != 904.5 {
+		t.Fatalf("Expected 904.5 for channel 3 but got %f", f)
+	}
+	// Channels that aren't configured use the reported frequency
+	if f := uplinkFrequency(channels, model.FSKChannel, 902.3); f != 902.3 {
+		t.Fatalf("Expected reported frequency but got %f", f)
+	}
+
+	// The US915 gateway listens on the second sub-band
+	if mask := channelMask(plan, channels); mask != (band.ChannelMask{0xFF00, 0, 0, 0, 0x0002}) {
+		t.Fatalf("Unexpected channel mask for US915 gateway: %04x", mask)
+	}
+
+	// Unknown gateways use the EU868 plan
+	plan, channels = plans.Lookup(protocol.EUIFromInt64(0x102))
+	if plan.Name() != "EU 863-870MHz ISM Band" {
+		t.Fatalf("Expected EU868 plan for unknown gateway but got %s", plan.Name())
+	}
+	if !channelMask(plan, channels).Empty() {
+		t.Fatal("Expected empty channel mask for EU868 gateway")
+	}
+
+	// Downlinks use the band from the uplink
+	packet := server.GatewayPacket{Radio: server.RadioContext{Band: plans.Band(band.US915Band)}}
+	packet.Gateway.GatewayEUI = euGW.GatewayEUI
+	if plans.DownlinkBand(packet).Name() != "US 902-928MHz ISM Band" {
+		t.Fatal("Expected band from uplink")
+	}
+	packet.Radio.Band = nil
+	if plans.DownlinkBand(packet).Name() != "EU 863-870MHz ISM Band" {
+		t.Fatal("Expected gateway's band for downlink without band")
+	}
+
+	// The dwell time limit is set in the configuration
+	config := server.NewDefaultConfig()
+	config.DwellTimeBands = []string{"AS923-2"}
+	plans = newGatewayPlans(store, config)
+	if plans.Band(band.AS923Group2Band).Configuration().MaxDwellTime == 0 {
+		t.Fatal("Expected dwell time limit for AS923-2")
+	}
+	if plans.Band(band.AS923Band).Configuration().MaxDwellTime != 0 {
+		t.Fatal("Expected no dwell time limit for AS923-1")
+	}
+}
@@ -74,9 +74,12 @@ func (pkt *GwPacket) UnmarshalBinary(data []byte) error {
 		pkt.Identifier = PullAck
 
 	case 5:
+		// The TX_ACK was added in version 2 and includes the gateway EUI
 		pkt.Identifier = TxAck
-		if len(data) > 4 {
-			pkt.JSONString = string(data[4:])
+		if len(data) >= 12 {
+			val := binary.BigEndian.Uint64(data[4:12])
+			pkt.GatewayEUI = protocol.EUIFromInt64(int64(val))
+			pkt.JSONString = string(data[12:])
 		}
 
 	default:
@@ -123,8 +126,9 @@ func (pkt *GwPacket) MarshalBinary() ([]byte, error) {
 
 	case TxAck:
 		data[3] = TxAck
-		copy(data[4:], pkt.JSONString)
-		packetLen := 4 + len(pkt.JSONString)
+		copy(data[4:], pkt.GatewayEUI.Octets[:])
+		copy(data[12:], pkt.JSONString)
+		packetLen := 12 + len(pkt.JSONString)
 		return data[:packetLen], nil
 
 	default:
@@ -144,3 +144,24 @@ func TestBinaryMarshalUnmarshal(t *testing.T) {
 		t.Fatalf("Not the same packet (original: %v != copy: %v)", pkt, pk2)
 	}
 }
+
+func TestTxAckMarshalUnmarshal(t *testing.T) {
+	pkt := GwPacket{
+		ProtocolVersion: 2,
+		Token:           0x1234,
+		Identifier:      TxAck,
+		GatewayEUI:      protocol.EUIFromInt64(0x0102030405060708),
+		JSONString:      `{"txpk_ack":{"error":"NONE"}}`,
+	}
+	buf, err := pkt.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk2 := GwPacket{}
+	if err := pk2.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	if pk2.Token != pkt.Token || pk2.GatewayEUI != pkt.GatewayEUI || pk2.JSONString != pkt.JSONString {
+		t.Fatalf("TX_ACK doesn't match: %+v != %+v", pk2, pkt)
+	}
+}
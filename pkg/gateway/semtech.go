@@ -13,21 +13,12 @@ import (
 	"github.com/lab5e/lospan/pkg/band"
 	"github.com/lab5e/lospan/pkg/events/gwevents"
 	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/protocol"
 	"github.com/lab5e/lospan/pkg/server"
 	"github.com/lab5e/lospan/pkg/storage"
 )
 
-var defaultBand band.FrequencyPlan
-
-func init() {
-	var err error
-	defaultBand, err = band.NewBand(band.EU868Band)
-	if err != nil {
-		lg.Error("Unable to create EU868 band instance: %v", err)
-	}
-}
-
 // GenericPacketForwarder is the generic packet forwarder provided by
 // Semtech. It has its weak points but it is the smallest common
 // denominator for all gateways on the market.
@@ -40,10 +31,17 @@ type GenericPacketForwarder struct {
 	terminate    chan bool
 	storage      *storage.Storage
 	context      *server.Context
-	mutex        *sync.Mutex    // Mutex for pullAckPort map
-	pullAckPorts map[string]int // Map of port <-> gateway
+	mutex        *sync.Mutex                      // Mutex for pullAckPort, gateways and pending maps
+	pullAckPorts map[string]int                   // Map of port <-> gateway
+	gateways     map[string]server.GatewayContext // Gateways that have sent PULL_DATA. Used for beacons
+	pending      map[uint16]pendingDownlink       // PULL_RESP tokens waiting for a TX_ACK from the gateway
+	monitor      gatewayMonitor                   // Gateway activity and events
+	plans        gatewayPlans                     // Frequency plans and channels for the gateways
 }
 
+// beaconLead is the time before the beacon is due it is sent to the gateways
+const beaconLead = 2 * time.Second
+
 // Start launches the generic packet forwarder. It does not return until the
 // gateway shuts down.
 func (p *GenericPacketForwarder) Start() {
@@ -101,6 +99,10 @@ func NewGenericPacketForwarder(serverPort int, storage *storage.Storage, context
 		context:      context,
 		mutex:        &sync.Mutex{},
 		pullAckPorts: make(map[string]int),
+		gateways:     make(map[string]server.GatewayContext),
+		pending:      make(map[uint16]pendingDownlink),
+		monitor:      newGatewayMonitor(storage, context),
+		plans:        newGatewayPlans(storage, context.Config),
 	}
 }
 
@@ -143,6 +145,35 @@ func (p *GenericPacketForwarder) setPullAckPort(eui protocol.EUI, port int) {
 	p.pullAckPorts[eui.String()] = port
 }
 
+func (p *GenericPacketForwarder) addGateway(pullData GwPacket) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.gateways[pullData.GatewayEUI.String()] = server.GatewayContext{
+		GatewayEUI:      pullData.GatewayEUI,
+		GatewayHost:     pullData.Host,
+		GatewayPort:     pullData.Port,
+		ProtocolVersion: pullData.ProtocolVersion,
+	}
+}
+
+func (p *GenericPacketForwarder) gatewayList() []server.GatewayContext {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var ret []server.GatewayContext
+	for _, v := range p.gateways {
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+// HasGateway returns true if the gateway has sent a PULL_DATA to the forwarder
+func (p *GenericPacketForwarder) HasGateway(eui protocol.EUI) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	_, exists := p.pullAckPorts[eui.String()]
+	return exists
+}
+
 func (p *GenericPacketForwarder) getPullAckPort(eui protocol.EUI) int {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
@@ -162,7 +193,7 @@ func (p *GenericPacketForwarder) udpSender(serverConn *net.UDPConn) {
 			continue
 		}
 		if val.JSONString != "" {
-			p.context.GwEventRouter.Publish(val.GatewayEUI, gwevents.NewTx(val.JSONString))
+			p.monitor.Publish(val.GatewayEUI, gwevents.NewTx(val.JSONString))
 		}
 		targetAddr, err := net.ResolveUDPAddr("udp", fmt.Sprintf("%s:%d", val.Host, val.Port))
 		if err != nil {
@@ -184,8 +215,22 @@ func (p *GenericPacketForwarder) udpSender(serverConn *net.UDPConn) {
 // between the wire format and the external representation.
 func (p *GenericPacketForwarder) mainLoop(serverConn *net.UDPConn) {
 	defer serverConn.Close()
+	var beaconTimer <-chan time.Time
+	if p.context.Config != nil && p.context.Config.ClassBBeacons {
+		beaconTimer = time.After(timeToNextBeacon(time.Now()))
+	}
+	livenessTicker := time.NewTicker(p.monitor.CheckInterval())
+	defer livenessTicker.Stop()
 	for {
 		select {
+		case <-livenessTicker.C:
+			p.monitor.CheckLiveness()
+			p.expirePending()
+
+		case <-beaconTimer:
+			p.sendBeacons(nextBeaconTime(time.Now()) - protocol.BeaconPeriod)
+			beaconTimer = time.After(timeToNextBeacon(time.Now()))
+
 		case val, ok := <-p.input:
 			// Generate a txpk message, aka PULL_RESP
 			if !ok {
@@ -208,6 +253,8 @@ func (p *GenericPacketForwarder) mainLoop(serverConn *net.UDPConn) {
 				// Send PullAck with same version and token
 				lg.Debug("PULL_DATA received from %s, sending PULL_ACK response", val.GatewayEUI)
 				p.setPullAckPort(val.GatewayEUI, val.Port)
+				p.addGateway(val)
+				p.monitor.Seen(val.GatewayEUI)
 				p.udpOutput <- GwPacket{
 					GatewayEUI:      val.GatewayEUI,
 					Identifier:      PullAck,
@@ -216,7 +263,7 @@ func (p *GenericPacketForwarder) mainLoop(serverConn *net.UDPConn) {
 					Port:            val.Port,
 					ProtocolVersion: val.ProtocolVersion,
 				}
-				p.context.GwEventRouter.Publish(val.GatewayEUI, gwevents.NewKeepAlive())
+				p.monitor.Publish(val.GatewayEUI, gwevents.NewKeepAlive())
 
 			case PushData:
 				lg.Debug("PUSH_DATA received from %s: %s", val.GatewayEUI, val.JSONString)
@@ -231,7 +278,8 @@ func (p *GenericPacketForwarder) mainLoop(serverConn *net.UDPConn) {
 						continue
 					}
 				}
-				p.context.GwEventRouter.Publish(val.GatewayEUI, gwevents.NewRx(val.JSONString))
+				p.monitor.Seen(val.GatewayEUI)
+				p.monitor.Publish(val.GatewayEUI, gwevents.NewRx(val.JSONString))
 
 				// Send PushAck with same version and token
 				p.decodeReceivedJSON(val)
@@ -244,7 +292,7 @@ func (p *GenericPacketForwarder) mainLoop(serverConn *net.UDPConn) {
 					ProtocolVersion: val.ProtocolVersion,
 				}
 			case TxAck:
-				// Ignore (for now)
+				p.handleTxAck(val)
 			default:
 				lg.Info("Don't know how to handle input with identifier=%d from gateway", val.Identifier)
 			}
@@ -252,30 +300,55 @@ func (p *GenericPacketForwarder) mainLoop(serverConn *net.UDPConn) {
 	}
 }
 
-// This is the default setup for the Semtech packet forwarder/EU868 band config.
-func (p *GenericPacketForwarder) lookupFrequency(rfchain uint8, channel uint8) float32 {
-	switch channel {
-	case 0:
-		return 868.1
-	case 1:
-		return 868.3
-	case 2:
-		return 868.5
-	case 3:
-		return 867.1
-	case 4:
-		return 867.3
-	case 5:
-		return 867.5
-	case 6:
-		return 867.7
-	case 7:
-		return 867.9
-	}
+// nextBeaconTime returns the GPS time of the next beacon that should be sent
+// to the gateways. Beacons are sent to the gateways a short while (beaconLead)
+// before they are due.
+func nextBeaconTime(now time.Time) time.Duration {
+	return protocol.BeaconTime(protocol.TimeToGPS(now.Add(beaconLead))) + protocol.BeaconPeriod
+}
 
-	lg.Warning("Unknown channel: %d. Returning 868.1MHz", channel)
-	return 868.1
+// timeToNextBeacon returns the time until the next beacon should be sent to
+// the gateways.
+func timeToNextBeacon(now time.Time) time.Duration {
+	return protocol.GPSToTime(nextBeaconTime(now)).Add(-beaconLead).Sub(now)
+}
 
+// sendBeacons sends a class B beacon to all of the gateways that have sent a
+// PULL_DATA. The beacon is transmitted by the gateway at the beacon time (in
+// GPS time). Gateways without a GPS will drop the beacon. The beacon data rate
+// comes from the gateway's frequency plan. The beacon frequency is the
+// configured beacon frequency for the plan or the plan's default.
+func (p *GenericPacketForwarder) sendBeacons(beaconTime time.Duration) {
+	for _, gw := range p.gatewayList() {
+		gateway := model.NewGateway()
+		if p.storage != nil {
+			if g, err := p.storage.GetGateway(gw.GatewayEUI); err == nil {
+				gateway = g
+			}
+		}
+		plan := p.plans.Band(gateway.Plan)
+		config := plan.Configuration()
+		encoding, err := plan.Encoding(config.BeaconDataRate)
+		if err != nil {
+			lg.Error("Unable to look up encoding for beacons to gateway %s: %v", gw.GatewayEUI, err)
+			continue
+		}
+		beacon := protocol.Beacon{Time: beaconTime, Latitude: gateway.Latitude, Longitude: gateway.Longitude}
+		buf := beacon.Encode(config.BeaconRFUSize[0], config.BeaconRFUSize[1])
+		p.sendTxpk(gw, Txpk{
+			GPSTime:      uint64(beaconTime / time.Millisecond),
+			Frequency:    p.context.Config.BeaconFrequency(plan, beaconTime),
+			Data:         base64.StdEncoding.EncodeToString(buf),
+			Modulation:   "LORA",
+			EccCoding:    "4/5",
+			LoraInvPol:   false,
+			RfPreamble:   10,
+			NoCRC:        true,
+			NoHeader:     true,
+			PayloadSize:  len(buf),
+			LoRaDataRate: encoding.GatewayDataRate(),
+		}, nil)
+	}
 }
 
 // Unmarshal and forward JSON from gateway
@@ -287,25 +360,21 @@ func (p *GenericPacketForwarder) decodeReceivedJSON(val GwPacket) {
 		lg.Info("Unable to unmarshal JSON from %s:%d: %v (json=%s)", val.Host, val.Port, err, val.JSONString)
 		return
 	}
+	if stat, ok := rxData.Status(); ok {
+		p.monitor.UpdateStats(val.GatewayEUI, stat)
+	}
 
+	plan, channels := p.plans.Lookup(val.GatewayEUI)
 	for _, packet := range rxData.Data {
 		gwPacket := server.GatewayPacket{
-			Radio: server.RadioContext{
-				Frequency: p.lookupFrequency(packet.ConcentratorRFChain, packet.ConcentratorChannel),
-				DataRate:  packet.DataRateID,
-				Channel:   packet.ConcentratorChannel,
-				RFChain:   packet.ConcentratorRFChain,
-				Band:      defaultBand,
-				RX1Delay:  0,
-				RX2Delay:  0,
-				RSSI:      packet.RSSI,
-				SNR:       packet.LoraSNRRatio,
-			},
+			Radio: radioContext(plan, channels, uplinkFrequency(channels, packet.ConcentratorChannel, packet.Frequency),
+				packet.DataRateID, packet.ConcentratorChannel, packet.ConcentratorRFChain, packet.RSSI, packet.LoraSNRRatio),
 			Gateway: server.GatewayContext{
 				GatewayEUI:      val.GatewayEUI,
 				GatewayHost:     val.Host,
 				GatewayPort:     val.Port,
 				GatewayClock:    packet.Timestamp,
+				FineTimestamp:   packet.FineTimestamp,
 				ProtocolVersion: val.ProtocolVersion,
 			},
 			ReceivedAt: time.Now(),
@@ -318,12 +387,107 @@ func (p *GenericPacketForwarder) decodeReceivedJSON(val GwPacket) {
 	}
 }
 
+// radioContext creates the radio context for a received packet. The band is
+// the frequency plan for the gateway that received the packet and the channel
+// mask is built from the gateway's channel configuration.
+func radioContext(plan band.FrequencyPlan, channels model.ConcentratorConfig, frequency float32, dataRate string, channel, rfChain uint8, rssi int32, snr float32) server.RadioContext {
+	return server.RadioContext{
+		Frequency: frequency,
+		DataRate:  dataRate,
+		Channel:   channel,
+		RFChain:   rfChain,
+		Band:      plan,
+		Channels:  channelMask(plan, channels),
+		RX1Delay:  0,
+		RX2Delay:  0,
+		RSSI:      rssi,
+		SNR:       snr,
+	}
+}
+
+// handleTxAck handles the TX_ACK from the gateway. The downlink event for the
+// PULL_RESP with the same token is published if the gateway has sent the
+// packet.
+func (p *GenericPacketForwarder) handleTxAck(ack GwPacket) {
+	p.mutex.Lock()
+	pending, ok := p.pending[ack.Token]
+	delete(p.pending, ack.Token)
+	p.mutex.Unlock()
+
+	if !ok {
+		lg.Debug("TX_ACK for unknown token %d from gateway %s", ack.Token, ack.GatewayEUI)
+		return
+	}
+	txAck := TxAckData{}
+	if ack.JSONString != "" {
+		if err := json.Unmarshal([]byte(ack.JSONString), &txAck); err != nil {
+			lg.Warning("Unable to unmarshal TX_ACK from gateway %s: %v", pending.gatewayEUI, err)
+			return
+		}
+	}
+	if !txAck.OK() {
+		lg.Warning("Gateway %s rejected downlink: %s", pending.gatewayEUI, txAck.Ack.Error)
+		return
+	}
+	p.monitor.Transmitted(pending.downlink)
+}
+
+// expirePending removes the PULL_RESP tokens that haven't been acknowledged
+func (p *GenericPacketForwarder) expirePending() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for token, pending := range p.pending {
+		if time.Since(pending.created) > pendingDownlinkExpiry {
+			lg.Warning("No TX_ACK for downlink to gateway %s", pending.gatewayEUI)
+			delete(p.pending, token)
+		}
+	}
+}
+
+// sendTxpk sends a PULL_RESP packet to the gateway. The downlink event is
+// published when the gateway acknowledges the packet with a TX_ACK. Version 1
+// of the protocol has no TX_ACK so the event is published right away.
+func (p *GenericPacketForwarder) sendTxpk(gateway server.GatewayContext, txpk Txpk, downlink *server.PayloadMessage) {
+	outputStruct := TXData{Data: txpk}
+
+	buffer, err := json.Marshal(outputStruct)
+	if err != nil {
+		lg.Info("Unable to marshal JSON for txpk: %v", err)
+		return
+	}
+	token := uint16(rand.Int() & 0xFFFF) // This is unused in v1
+	if downlink != nil {
+		if gateway.ProtocolVersion < 2 {
+			p.monitor.Transmitted(downlink)
+		} else {
+			p.mutex.Lock()
+			p.pending[token] = pendingDownlink{gatewayEUI: gateway.GatewayEUI, downlink: downlink, created: time.Now()}
+			p.mutex.Unlock()
+		}
+	}
+	p.udpOutput <- GwPacket{
+		Identifier:      PullResp,
+		Token:           token,
+		Host:            gateway.GatewayHost,
+		Port:            p.getPullAckPort(gateway.GatewayEUI),
+		ProtocolVersion: gateway.ProtocolVersion,
+		GatewayEUI:      gateway.GatewayEUI,
+		JSONString:      string(buffer),
+	}
+}
+
 // Encode and send data as JSON to gateway
 func (p *GenericPacketForwarder) encodeAndSend(packet server.GatewayPacket) {
 	// Create a PULL_RESP packet for the gateway
 	// Timestamp is in us; use precomputed RXDelay value
-	timestamp := packet.Gateway.GatewayClock + 1000000*uint32(packet.Radio.RX1Delay)
+	timestamp := packet.Gateway.GatewayClock + 1000000*uint32(packet.Radio.TxDelay())
+	if packet.Immediate || packet.GPSTime != 0 {
+		// Class C downlinks are sent right away and class B downlinks are
+		// sent at a GPS time. The gateway ignores the timestamp in both cases.
+		timestamp = 0
+	}
 	outputPkt := Txpk{
+		Immediate:    packet.Immediate,
 		Timestamp:    timestamp,              // us clock
 		Frequency:    packet.Radio.Frequency, // packet.TransmitFrequency,
 		RFChain:      0,                      // This is the default for the packet forwarder
@@ -334,22 +498,14 @@ func (p *GenericPacketForwarder) encodeAndSend(packet server.GatewayPacket) {
 		PayloadSize:  len(packet.RawMessage),
 		LoRaDataRate: packet.Radio.DataRate,
 	}
-	outputStruct := TXData{Data: outputPkt}
+	if packet.GPSTime != 0 {
+		outputPkt.GPSTime = uint64(packet.GPSTime / time.Millisecond)
+	}
+	p.sendTxpk(packet.Gateway, outputPkt, packet.Downlink)
 
-	buffer, err := json.Marshal(outputStruct)
-	if err != nil {
-		lg.Info("Unable to marshal JSON for txpk: %v", err)
+	if packet.Immediate || packet.GPSTime != 0 {
 		return
 	}
-	p.udpOutput <- GwPacket{
-		Identifier:      PullResp,
-		Token:           uint16(rand.Int() & 0xFFFF), // This is unused in v1
-		Host:            packet.Gateway.GatewayHost,
-		Port:            p.getPullAckPort(packet.Gateway.GatewayEUI),
-		ProtocolVersion: packet.Gateway.ProtocolVersion,
-		GatewayEUI:      packet.Gateway.GatewayEUI,
-		JSONString:      string(buffer),
-	}
 	timeToProcess := time.Since(packet.ReceivedAt)
 	const assumedLatency = 0.2
 	// Assume 100ms latency between gateway and
@@ -1,5 +1,7 @@
 package gateway
 
+import "encoding/json"
+
 // Rxpk is a (JSON) struct used by the Semtech packet forwarder. It is sent from the gateway to the server.
 type Rxpk struct {
 	Time                string  `json:"time"` // Time stamp (unix-) for the gateway
@@ -14,6 +16,7 @@ type Rxpk struct {
 	LoraSNRRatio        float32 `json:"lsnr"`
 	PayloadSize         uint32  `json:"size"`
 	RFPackets           string  `json:"data"`
+	FineTimestamp       int64   `json:"ftime,omitempty"` // Fine timestamp (ns) if the gateway supports it
 }
 
 // Txpk is a (JSON) struct used by the Semtech packet forwarder. It is sent from the server to the gateway
@@ -21,6 +24,7 @@ type Txpk struct {
 	Immediate             bool    `json:"imme"`           // (one of)Send packet immediately (will ignore tmst & time)
 	Timestamp             uint32  `json:"tmst,omitempty"` // (one of)Send packet on a certain timestamp value (will ignore time)
 	Time                  string  `json:"time,omitempty"` // (one of)Send packet at a certain time (GPS synchronization required)
+	GPSTime               uint64  `json:"tmms,omitempty"` // (one of)Send packet at a certain GPS time (in ms since the GPS epoch)
 	Frequency             float32 `json:"freq"`           // (mandatory)TX central frequency in MHz (unsigned float, Hz precision)
 	RFChain               uint8   `json:"rfch"`           // (mandatory)Concentrator "RF chain" used for TX (unsigned integer)
 	TxPower               uint32  `json:"powe,omitempty"` // TX output power in dBm (unsigned integer, dBm precision)
@@ -33,16 +37,61 @@ type Txpk struct {
 	PayloadSize           int     `json:"size"`           // (mandatory)RF packet payload size in bytes (unsigned integer)
 	Data                  string  `json:"data"`           // (mandatory)Base64 encoded RF packet payload, padding optional
 	NoCRC                 bool    `json:"ncrc,omitempty"` // If true, disable the CRC of the physical layer (optional)
+	NoHeader              bool    `json:"nhdr,omitempty"` // If true, disable the header of the physical layer (optional)
+
+}
 
+// Stat is the (JSON) status report sent by the Semtech packet forwarder.
+type Stat struct {
+	Time      string  `json:"time"` // UTC system time of the gateway
+	Latitude  float32 `json:"lati"` // GPS latitude of the gateway in degrees
+	Longitude float32 `json:"long"` // GPS longitude of the gateway in degrees
+	Altitude  float32 `json:"alti"` // GPS altitude of the gateway in meters
+	RxNb      uint32  `json:"rxnb"` // Number of radio packets received
+	RxOK      uint32  `json:"rxok"` // Number of radio packets received with a valid PHY CRC
+	RxFw      uint32  `json:"rxfw"` // Number of radio packets forwarded
+	AckR      float32 `json:"ackr"` // Percentage of upstream datagrams that were acknowledged
+	DwNb      uint32  `json:"dwnb"` // Number of downlink datagrams received
+	TxNb      uint32  `json:"txnb"` // Number of packets emitted
 }
 
 // RXData contains device payload in "Data" and also (possibly) gateway status in "Stat". Both contain JSON
 type RXData struct {
-	Data []Rxpk `json:"rxpk"`
-	//	Stat statv2 `json:"stat"` // this might be an array or a single value, depending on configuration.
+	Data []Rxpk          `json:"rxpk"`
+	Stat json.RawMessage `json:"stat,omitempty"` // this might be an array or a single value, depending on configuration.
+}
+
+// Status returns the status report in the data. If there's more than one
+// report the last one is returned.
+func (r *RXData) Status() (Stat, bool) {
+	if len(r.Stat) == 0 {
+		return Stat{}, false
+	}
+	stat := Stat{}
+	if err := json.Unmarshal(r.Stat, &stat); err == nil {
+		return stat, true
+	}
+	var stats []Stat
+	if err := json.Unmarshal(r.Stat, &stats); err != nil || len(stats) == 0 {
+		return Stat{}, false
+	}
+	return stats[len(stats)-1], true
 }
 
 // TXData is the struct used when transmitting data to the gateway
 type TXData struct {
 	Data Txpk `json:"txpk"`
 }
+
+// TxAckData is the struct the gateway sends in the TX_ACK packet
+type TxAckData struct {
+	Ack struct {
+		Error string `json:"error"` // NONE if the packet is sent
+	} `json:"txpk_ack"`
+}
+
+// OK returns true if the gateway has sent the packet. An empty TX_ACK means
+// the packet is sent.
+func (t *TxAckData) OK() bool {
+	return t.Ack.Error == "" || t.Ack.Error == "NONE"
+}
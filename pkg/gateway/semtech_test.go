@@ -20,11 +20,16 @@ import (
 type serverConfig struct {
 	forwarder *GenericPacketForwarder
 	clientUDP *net.UDPConn
+	router    *server.EventRouter[protocol.EUI, gwevents.GwEvent]
 }
 
 var gwStorage = storage.NewMemoryStorage()
 
 func setupServer(t *testing.T) serverConfig {
+	return setupServerWithConfig(t, &server.Parameters{})
+}
+
+func setupServerWithConfig(t *testing.T, config *server.Parameters) serverConfig {
 	ret := serverConfig{}
 
 	port, err := utils.FreePort()
@@ -33,8 +38,9 @@ func setupServer(t *testing.T) serverConfig {
 	}
 
 	router := server.NewEventRouter[protocol.EUI, gwevents.GwEvent](5)
-	context := server.Context{GwEventRouter: &router, Config: &server.Parameters{}}
+	context := server.Context{GwEventRouter: &router, Config: config}
 	ret.forwarder = NewGenericPacketForwarder(port, gwStorage, &context)
+	ret.router = &router
 
 	go ret.forwarder.Start()
 
@@ -269,3 +275,199 @@ func TestEUIFilteringOnEUI(t *testing.T) {
 	}
 
 }
+
+// pullResp waits for the next PULL_RESP from the server and decodes the txpk
+func (s *serverConfig) pullResp(t *testing.T) Txpk {
+	buf := make([]byte, 1024)
+	s.clientUDP.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := s.clientUDP.Read(buf)
+	if err != nil {
+		t.Fatal("Did not get PULL_RESP from server: ", err)
+	}
+	pkt := GwPacket{}
+	if err := pkt.UnmarshalBinary(buf[:n]); err != nil || pkt.Identifier != PullResp {
+		t.Fatalf("Expected PULL_RESP but got %v (err=%v)", pkt.Identifier, err)
+	}
+	txData := TXData{}
+	if err := json.Unmarshal([]byte(pkt.JSONString), &txData); err != nil {
+		t.Fatal("Unable to decode txpk: ", err)
+	}
+	return txData.Data
+}
+
+// pullData sends PULL_DATA until the server responds with PULL_ACK. The
+// server might not be listening when the first packet is sent.
+func (s *serverConfig) pullData(t *testing.T, eui protocol.EUI) {
+	pullData := GwPacket{Token: 0x0102, Identifier: PullData, GatewayEUI: eui}
+	out, _ := pullData.MarshalBinary()
+	buf := make([]byte, 1024)
+	for i := 0; i < 10; i++ {
+		s.clientUDP.Write(out)
+		s.clientUDP.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		n, err := s.clientUDP.Read(buf)
+		if err != nil {
+			time.Sleep(50 * time.Millisecond)
+			continue
+		}
+		pkt := GwPacket{}
+		if pkt.UnmarshalBinary(buf[:n]) == nil && pkt.Identifier == PullAck {
+			return
+		}
+	}
+	t.Fatal("Did not get PULL_ACK from server")
+}
+
+func TestClassBTransmissions(t *testing.T) {
+	s := setupServer(t)
+	defer s.close()
+
+	gwEUI := protocol.EUIFromInt64(0x0102030405060709)
+	s.pullData(t, gwEUI)
+
+	// Class B downlinks are sent at GPS time
+	slot := 1000*protocol.BeaconPeriod + protocol.BeaconReserved + 100*protocol.PingSlotLength
+	s.forwarder.Input() <- server.GatewayPacket{
+		RawMessage: []byte{1, 2, 3},
+		Radio:      server.RadioContext{Frequency: 869.525, DataRate: "SF9BW125"},
+		Gateway:    server.GatewayContext{GatewayEUI: gwEUI, GatewayHost: "127.0.0.1", GatewayClock: 2017},
+		GPSTime:    slot,
+	}
+	txpk := s.pullResp(t)
+	if txpk.GPSTime != uint64(slot/time.Millisecond) || txpk.Timestamp != 0 || txpk.Immediate {
+		t.Fatalf("Downlink should be sent at GPS time %d: %+v", slot/time.Millisecond, txpk)
+	}
+
+	// Beacons are sent to all gateways with the beacon time
+	beaconTime := 1001 * protocol.BeaconPeriod
+	s.forwarder.sendBeacons(beaconTime)
+	txpk = s.pullResp(t)
+	if txpk.GPSTime != uint64(beaconTime/time.Millisecond) || txpk.Timestamp != 0 {
+		t.Fatalf("Beacon should be sent at GPS time %d: %+v", beaconTime/time.Millisecond, txpk)
+	}
+	if !txpk.NoCRC || !txpk.NoHeader || txpk.LoraInvPol || txpk.PayloadSize != 17 {
+		t.Fatalf("Beacon is not encoded correctly: %+v", txpk)
+	}
+	if txpk.Frequency != 869.525 || txpk.LoRaDataRate != "SF9BW125" {
+		t.Fatalf("Beacon has incorrect frequency or data rate: %+v", txpk)
+	}
+
+	// The beacon frequency can be set for the band
+	s.forwarder.context.Config = &server.Parameters{BeaconFrequencies: []string{"EU868:869.3"}}
+	s.forwarder.sendBeacons(beaconTime)
+	txpk = s.pullResp(t)
+	if txpk.Frequency != 869.3 || txpk.LoRaDataRate != "SF9BW125" {
+		t.Fatalf("Beacon should use the configured frequency: %+v", txpk)
+	}
+}
+
+func TestBeaconTimer(t *testing.T) {
+	now := protocol.GPSToTime(1000*protocol.BeaconPeriod + 10*time.Second)
+	if nextBeaconTime(now) != 1001*protocol.BeaconPeriod {
+		t.Fatalf("Incorrect beacon time: %v", nextBeaconTime(now))
+	}
+	if timeToNextBeacon(now) != protocol.BeaconPeriod-10*time.Second-beaconLead {
+		t.Fatalf("Incorrect time to next beacon: %v", timeToNextBeacon(now))
+	}
+}
+
+func TestGatewayEvents(t *testing.T) {
+	s := setupServer(t)
+	defer s.close()
+
+	gwEUI := protocol.EUIFromInt64(0x010203040506070A)
+	events := s.router.Subscribe(gwEUI)
+	defer s.router.Unsubscribe(events)
+
+	nextEvent := func() gwevents.GwEvent {
+		select {
+		case ev := <-events:
+			return ev
+		case <-time.After(time.Second):
+			t.Fatal("No gateway event")
+		}
+		return gwevents.GwEvent{}
+	}
+
+	s.pullData(t, gwEUI)
+	if ev := nextEvent(); ev.Type != gwevents.KeepAlive {
+		t.Fatalf("Expected keepalive but got %+v", ev)
+	}
+
+	s.forwarder.Input() <- server.GatewayPacket{
+		RawMessage: []byte{1, 2, 3},
+		Radio:      server.RadioContext{Frequency: 869.525, DataRate: "SF9BW125"},
+		Gateway:    server.GatewayContext{GatewayEUI: gwEUI, GatewayHost: "127.0.0.1"},
+		Immediate:  true,
+	}
+	s.pullResp(t)
+	ev := nextEvent()
+	for ev.Type == gwevents.KeepAlive {
+		ev = nextEvent()
+	}
+	txData := TXData{}
+	if ev.Type != gwevents.Tx || json.Unmarshal([]byte(ev.Data), &txData) != nil {
+		t.Fatalf("Expected Tx event with txpk but got %+v", ev)
+	}
+	if txData.Data.Frequency != 869.525 || !txData.Data.Immediate {
+		t.Fatalf("Incorrect txpk in event: %+v", txData.Data)
+	}
+}
+
+func TestGatewayLiveness(t *testing.T) {
+	s := setupServerWithConfig(t, &server.Parameters{GatewayTimeout: 200 * time.Millisecond})
+	defer s.close()
+
+	gwEUI := protocol.EUIFromInt64(0x010203040506070B)
+	if err := gwStorage.CreateGateway(model.Gateway{GatewayEUI: gwEUI, IP: net.ParseIP("127.0.0.1")}); err != nil {
+		t.Fatal(err)
+	}
+	events := s.router.Subscribe(gwEUI)
+	defer s.router.Unsubscribe(events)
+
+	s.pullData(t, gwEUI)
+
+	if gw := waitForLastSeen(t, gwEUI); !gw.Online(time.Minute) {
+		t.Fatalf("Gateway should be marked as seen: %+v", gw)
+	}
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case ev := <-events:
+			if ev.Type == gwevents.Inactive {
+				return
+			}
+		case <-timeout:
+			t.Fatal("No inactive event for gateway")
+		}
+	}
+}
+
+func TestTxAck(t *testing.T) {
+	appRouter := server.NewEventRouter[protocol.EUI, *server.PayloadMessage](5)
+	context := server.Context{AppRouter: &appRouter, Config: &server.Parameters{}}
+	forwarder := NewGenericPacketForwarder(0, gwStorage, &context)
+
+	appEUI := protocol.EUIFromInt64(0x0B)
+	events := appRouter.Subscribe(appEUI)
+	defer appRouter.Unsubscribe(events)
+
+	sent := &server.PayloadMessage{Type: server.DownlinkEvent, Application: model.Application{AppEUI: appEUI}}
+	forwarder.pending[1] = pendingDownlink{downlink: sent, created: time.Now()}
+	forwarder.pending[2] = pendingDownlink{downlink: &server.PayloadMessage{Application: model.Application{AppEUI: appEUI}}, created: time.Now()}
+
+	forwarder.handleTxAck(GwPacket{Identifier: TxAck, Token: 2, JSONString: `{"txpk_ack":{"error":"COLLISION_PACKET"}}`})
+	forwarder.handleTxAck(GwPacket{Identifier: TxAck, Token: 3})
+	forwarder.handleTxAck(GwPacket{Identifier: TxAck, Token: 1, JSONString: `{"txpk_ack":{"error":"NONE"}}`})
+	select {
+	case ev := <-events:
+		if ev != sent {
+			t.Fatalf("Expected event for the sent downlink but got %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("No downlink event after TX_ACK")
+	}
+	if len(forwarder.pending) != 0 {
+		t.Fatalf("Acknowledged downlinks should be removed: %+v", forwarder.pending)
+	}
+}
@@ -0,0 +1,3 @@
+// Package integration contains the integrations that forward the
+// application events to external systems
+package integration
@@ -0,0 +1,88 @@
+package integration
+
+import (
+	"strings"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+)
+
+// rxInfo is the reception by a single gateway
+type rxInfo struct {
+	GatewayEUI string  `json:"gatewayEui"`
+	RSSI       int32   `json:"rssi"`
+	SNR        float32 `json:"snr"`
+}
+
+// txInfo is the radio parameters for the uplink
+type txInfo struct {
+	Frequency float32 `json:"frequency"`
+	DataRate  string  `json:"dataRate"`
+}
+
+// appEvent is the JSON representation of the application events. The
+// reception fields are only set for uplinks and joins.
+type appEvent struct {
+	Type           server.AppEventType    `json:"type"`
+	ApplicationEUI string                 `json:"applicationEui"`
+	DeviceEUI      string                 `json:"deviceEui"`
+	DevAddr        string                 `json:"devAddr"`
+	Time           string                 `json:"time"`
+	FCnt           uint32                 `json:"fCnt,omitempty"`
+	FPort          uint8                  `json:"fPort,omitempty"`
+	Data           []byte                 `json:"data,omitempty"`
+	Object         map[string]interface{} `json:"object,omitempty"`     // Decoded by the application decoder
+	CodecError     string                 `json:"codecError,omitempty"` // Set if the decoder failed
+	Confirmed      bool                   `json:"confirmed,omitempty"`
+	Created        int64                  `json:"created,omitempty"` // Created time for downlinks
+	RxInfo         []rxInfo               `json:"rxInfo,omitempty"`
+	TxInfo         *txInfo                `json:"txInfo,omitempty"`
+}
+
+// newAppEvent converts the message from the application router into an event
+func newAppEvent(msg *server.PayloadMessage) appEvent {
+	received := msg.FrameContext.GatewayContext.ReceivedAt
+	if received.IsZero() {
+		received = time.Now()
+	}
+	ret := appEvent{
+		Type:           msg.Type,
+		ApplicationEUI: msg.Application.AppEUI.String(),
+		DeviceEUI:      msg.Device.DeviceEUI.String(),
+		DevAddr:        msg.Device.DevAddr.String(),
+		Time:           received.UTC().Format(time.RFC3339Nano),
+		FCnt:           msg.FCnt,
+		FPort:          msg.FPort,
+		Data:           msg.Payload,
+		CodecError:     msg.CodecError,
+	}
+	if msg.Object != nil {
+		ret.Object = msg.Object.AsMap()
+	}
+	switch msg.Type {
+	case server.UplinkEvent, server.JoinEvent:
+		for _, r := range msg.FrameContext.GatewayReceptions() {
+			ret.RxInfo = append(ret.RxInfo, rxInfo{GatewayEUI: r.GatewayEUI.String(), RSSI: r.RSSI, SNR: r.SNR})
+		}
+		radio := msg.FrameContext.GatewayContext.Radio
+		ret.TxInfo = &txInfo{Frequency: radio.Frequency, DataRate: radio.DataRate}
+	case server.DownlinkEvent:
+		ret.Confirmed = msg.Downlink.Ack
+		ret.Created = msg.Downlink.CreatedTime
+	}
+	return ret
+}
+
+// downlinkCommand is a downlink request from an integration
+type downlinkCommand struct {
+	FPort     int    `json:"fPort"`
+	Data      []byte `json:"data"`
+	Confirmed bool   `json:"confirmed"`
+}
+
+// topicEUI returns the EUI in the format used in the topics, ie lower case
+// hex digits
+func topicEUI(eui protocol.EUI) string {
+	return strings.ToLower(strings.ReplaceAll(eui.String(), "-", ""))
+}
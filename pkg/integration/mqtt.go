@@ -0,0 +1,281 @@
+package integration
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+)
+
+const (
+	// mqttTimeout is the maximum time to wait for publish and subscribe
+	// requests to complete
+	mqttTimeout = 5 * time.Second
+	// mqttDisconnectQuiesce is the time (in ms) to wait for pending work
+	// when disconnecting from the broker
+	mqttDisconnectQuiesce = 250
+	// defaultRetryInterval is the time between each attempt to connect to
+	// the broker and to publish the buffered events
+	defaultRetryInterval = 5 * time.Second
+	// downlinkCommandTopic is the topic for the downlink requests
+	downlinkCommandTopic = "command/down"
+)
+
+// mqttMessage is a message waiting to be published
+type mqttMessage struct {
+	id      uint64
+	topic   string
+	payload []byte
+}
+
+// MQTTIntegration publishes the application events to a MQTT broker. The
+// events are published as JSON on <prefix>/<app eui>/device/<device eui>/event/<type>
+// where the type is up, join, ack or txack. Downlinks are received on
+// <prefix>/<app eui>/device/<device eui>/command/down. The EUIs are lower case
+// hex digits. Events are buffered while the broker is unavailable. The oldest
+// events are dropped when the buffer is full.
+type MQTTIntegration struct {
+	context       *server.Context
+	prefix        string
+	bufferSize    int
+	retryInterval time.Duration
+	client        mqtt.Client
+	events        <-chan *server.PayloadMessage
+	mutex         *sync.Mutex // Mutex for the buffer
+	buffer        []mqttMessage
+	nextID        uint64
+	pending       chan bool // Signals that there are events to publish
+	stop          chan bool // Closed when the router subscription ends
+	done          chan bool // Closed when the integration has stopped
+}
+
+// NewMQTTIntegration creates a new MQTT integration. The broker is read from
+// the configuration.
+func NewMQTTIntegration(context *server.Context) *MQTTIntegration {
+	return &MQTTIntegration{
+		context:       context,
+		prefix:        server.DefaultAppMQTTTopicPrefix,
+		bufferSize:    server.DefaultAppMQTTBufferSize,
+		retryInterval: defaultRetryInterval,
+		mutex:         &sync.Mutex{},
+		pending:       make(chan bool, 1),
+		stop:          make(chan bool),
+		done:          make(chan bool),
+	}
+}
+
+// Start connects to the broker and starts publishing the events for all of
+// the applications. The integration reconnects to the broker if the
+// connection is lost.
+func (m *MQTTIntegration) Start() error {
+	if m.context.AppRouter == nil {
+		return errors.New("no application router for the MQTT integration")
+	}
+	cfg := m.context.Config
+	if cfg == nil || cfg.AppMQTTBroker == "" {
+		return errors.New("no MQTT broker for the application integration")
+	}
+	if cfg.AppMQTTTopicPrefix != "" {
+		m.prefix = strings.TrimSuffix(cfg.AppMQTTTopicPrefix, "/")
+	}
+	if cfg.AppMQTTBufferSize > 0 {
+		m.bufferSize = cfg.AppMQTTBufferSize
+	}
+
+	opts := mqtt.NewClientOptions()
+	opts.AddBroker(cfg.AppMQTTBroker)
+	opts.SetUsername(cfg.AppMQTTUsername)
+	opts.SetPassword(cfg.AppMQTTPassword)
+	opts.SetClientID(fmt.Sprintf("lospan-app-%08x", rand.Uint32()))
+	opts.SetAutoReconnect(true)
+	opts.SetConnectRetry(true)
+	opts.SetConnectRetryInterval(m.retryInterval)
+	opts.SetMaxReconnectInterval(m.retryInterval)
+	opts.SetOnConnectHandler(m.connected)
+	opts.SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+		lg.Warning("Lost connection to application MQTT broker: %v", err)
+	})
+	m.client = mqtt.NewClient(opts)
+	lg.Info("MQTT integration connecting to %s", cfg.AppMQTTBroker)
+	m.client.Connect()
+
+	m.events = m.context.AppRouter.SubscribeAll()
+	go m.receive()
+	go m.publishLoop()
+	return nil
+}
+
+// Stop stops the integration. Buffered events are published if the broker
+// is available.
+func (m *MQTTIntegration) Stop() {
+	if m.events == nil {
+		// Not started
+		return
+	}
+	m.context.AppRouter.Unsubscribe(m.events)
+	<-m.done
+}
+
+// connected is called every time the client connects to the broker
+func (m *MQTTIntegration) connected(client mqtt.Client) {
+	lg.Info("MQTT integration connected to broker")
+	topic := fmt.Sprintf("%s/+/device/+/%s", m.prefix, downlinkCommandTopic)
+	token := client.Subscribe(topic, 1, m.handleDownlink)
+	if !token.WaitTimeout(mqttTimeout) {
+		lg.Error("Timed out subscribing to %s", topic)
+	} else if err := token.Error(); err != nil {
+		lg.Error("Unable to subscribe to %s: %v", topic, err)
+	}
+	m.signal()
+}
+
+// signal wakes up the publisher
+func (m *MQTTIntegration) signal() {
+	select {
+	case m.pending <- true:
+	default:
+	}
+}
+
+// receive reads the events from the application router until the
+// subscription ends
+func (m *MQTTIntegration) receive() {
+	for msg := range m.events {
+		buf, err := json.Marshal(newAppEvent(msg))
+		if err != nil {
+			lg.Warning("Unable to marshal %s event for device %s: %v", msg.Type, msg.Device.DeviceEUI, err)
+			continue
+		}
+		topic := fmt.Sprintf("%s/%s/device/%s/event/%s", m.prefix,
+			topicEUI(msg.Application.AppEUI), topicEUI(msg.Device.DeviceEUI), msg.Type)
+		m.enqueue(mqttMessage{topic: topic, payload: buf})
+		m.signal()
+	}
+	close(m.stop)
+}
+
+// enqueue adds a message to the buffer. The oldest message is dropped if
+// the buffer is full.
+func (m *MQTTIntegration) enqueue(msg mqttMessage) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if len(m.buffer) >= m.bufferSize {
+		lg.Warning("MQTT integration buffer is full. Dropping message for %s", m.buffer[0].topic)
+		m.buffer = m.buffer[1:]
+	}
+	m.nextID++
+	msg.id = m.nextID
+	m.buffer = append(m.buffer, msg)
+}
+
+func (m *MQTTIntegration) publishLoop() {
+	retryTicker := time.NewTicker(m.retryInterval)
+	defer retryTicker.Stop()
+	for {
+		select {
+		case <-m.pending:
+		case <-retryTicker.C:
+		case <-m.stop:
+			m.flush()
+			m.client.Disconnect(mqttDisconnectQuiesce)
+			close(m.done)
+			return
+		}
+		m.flush()
+	}
+}
+
+// flush publishes the buffered messages. Messages are kept in the buffer
+// until the broker has acknowledged them.
+func (m *MQTTIntegration) flush() {
+	for m.client.IsConnectionOpen() {
+		m.mutex.Lock()
+		if len(m.buffer) == 0 {
+			m.mutex.Unlock()
+			return
+		}
+		msg := m.buffer[0]
+		m.mutex.Unlock()
+
+		token := m.client.Publish(msg.topic, 1, false, msg.payload)
+		if !token.WaitTimeout(mqttTimeout) {
+			lg.Warning("Timed out publishing to %s", msg.topic)
+			return
+		}
+		if err := token.Error(); err != nil {
+			lg.Warning("Unable to publish to %s: %v", msg.topic, err)
+			return
+		}
+
+		m.mutex.Lock()
+		// The message might have been dropped while it was published
+		if len(m.buffer) > 0 && m.buffer[0].id == msg.id {
+			m.buffer = m.buffer[1:]
+		}
+		m.mutex.Unlock()
+	}
+}
+
+// handleDownlink schedules a downlink request. The topic is
+// <prefix>/<app eui>/device/<device eui>/command/down
+func (m *MQTTIntegration) handleDownlink(_ mqtt.Client, msg mqtt.Message) {
+	fields := strings.Split(strings.TrimPrefix(msg.Topic(), m.prefix+"/"), "/")
+	if len(fields) != 5 {
+		lg.Info("Ignoring downlink on topic %s", msg.Topic())
+		return
+	}
+	appEUI, err := protocol.EUIFromString(fields[0])
+	if err != nil {
+		lg.Info("Invalid application EUI in topic %s: %v", msg.Topic(), err)
+		return
+	}
+	deviceEUI, err := protocol.EUIFromString(fields[2])
+	if err != nil {
+		lg.Info("Invalid device EUI in topic %s: %v", msg.Topic(), err)
+		return
+	}
+	cmd := downlinkCommand{}
+	if err := json.Unmarshal(msg.Payload(), &cmd); err != nil {
+		lg.Info("Unable to unmarshal downlink for device %s: %v (json=%s)", deviceEUI, err, msg.Payload())
+		return
+	}
+	if err := m.scheduleDownlink(appEUI, deviceEUI, cmd); err != nil {
+		lg.Info("Unable to schedule downlink for device %s: %v", deviceEUI, err)
+	}
+}
+
+// scheduleDownlink stores the downlink. It will be sent the next time the
+// device sends an uplink. Port 0 is reserved for MAC commands and ports above
+// 223 are reserved by the specification.
+func (m *MQTTIntegration) scheduleDownlink(appEUI, deviceEUI protocol.EUI, cmd downlinkCommand) error {
+	if cmd.FPort < 1 || cmd.FPort > 223 {
+		return fmt.Errorf("port must be 1-223")
+	}
+	if m.context.Storage == nil {
+		return fmt.Errorf("no storage for downlinks")
+	}
+	device, err := m.context.Storage.GetDeviceByEUI(deviceEUI)
+	if err != nil {
+		return fmt.Errorf("unable to look up device: %v", err)
+	}
+	if device.AppEUI != appEUI {
+		return fmt.Errorf("device doesn't belong to application %s", appEUI)
+	}
+	return m.context.Storage.CreateDownstreamMessage(deviceEUI, model.DownstreamMessage{
+		DeviceEUI:   deviceEUI,
+		Data:        hex.EncodeToString(cmd.Data),
+		Port:        uint8(cmd.FPort),
+		Ack:         cmd.Confirmed,
+		CreatedTime: time.Now().UnixMilli(),
+	})
+}
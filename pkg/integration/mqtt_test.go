@@ -0,0 +1,124 @@
+package integration
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+	"github.com/lab5e/lospan/pkg/storage"
+	"github.com/lab5e/lospan/pkg/utils"
+	"github.com/lab5e/lospan/pkg/utils/mqtttest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMQTTIntegration(t *testing.T) {
+	assert := require.New(t)
+
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+
+	app := model.NewApplication()
+	app.AppEUI = protocol.EUIFromInt64(0x0100000000000001)
+	assert.NoError(store.CreateApplication(app))
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x0200000000000001)
+	device.AppEUI = app.AppEUI
+	device.DevAddr = protocol.DevAddrFromUint32(0x01020304)
+	assert.NoError(store.CreateDevice(device, app.AppEUI))
+
+	port, err := utils.FreePort()
+	assert.NoError(err)
+	address := fmt.Sprintf("127.0.0.1:%d", port)
+
+	router := server.NewEventRouter[protocol.EUI, *server.PayloadMessage](5)
+	context := &server.Context{Storage: store, AppRouter: &router, Config: &server.Parameters{
+		AppMQTTBroker:      "tcp://" + address,
+		AppMQTTTopicPrefix: "application",
+		AppMQTTBufferSize:  2,
+	}}
+	integration := NewMQTTIntegration(context)
+	integration.retryInterval = 20 * time.Millisecond
+	assert.NoError(integration.Start())
+
+	// The events are buffered until the broker is available. The oldest
+	// event is dropped when the buffer is full.
+	for fCnt := uint32(1); fCnt <= 3; fCnt++ {
+		router.Publish(app.AppEUI, &server.PayloadMessage{
+			Type:        server.UplinkEvent,
+			Payload:     []byte{1, 2, 3},
+			FPort:       1,
+			FCnt:        fCnt,
+			Device:      device,
+			Application: app,
+			FrameContext: server.FrameContext{GatewayContext: server.GatewayPacket{
+				Gateway: server.GatewayContext{GatewayEUI: protocol.EUIFromInt64(3)},
+				Radio:   server.RadioContext{Frequency: 868.1, DataRate: "SF7BW125", RSSI: -80, SNR: 5},
+			}},
+		})
+	}
+	assert.Eventually(func() bool {
+		integration.mutex.Lock()
+		defer integration.mutex.Unlock()
+		return integration.nextID == 3
+	}, time.Second, 10*time.Millisecond)
+
+	broker, err := mqtttest.NewBroker(address)
+	assert.NoError(err)
+	defer broker.Close()
+
+	uplinkTopic := fmt.Sprintf("application/%s/device/%s/event/up", topicEUI(app.AppEUI), topicEUI(device.DeviceEUI))
+	msgs := broker.WaitForMessages(uplinkTopic, 2, 2*time.Second)
+	assert.Len(msgs, 2)
+	for i, msg := range msgs {
+		ev := appEvent{}
+		assert.NoError(json.Unmarshal(msg.Payload, &ev))
+		assert.Equal(uint32(i+2), ev.FCnt)
+		assert.Equal(server.UplinkEvent, ev.Type)
+		assert.Equal(device.DeviceEUI.String(), ev.DeviceEUI)
+		assert.Equal([]byte{1, 2, 3}, ev.Data)
+		assert.Len(ev.RxInfo, 1)
+		assert.Equal("SF7BW125", ev.TxInfo.DataRate)
+	}
+
+	// Downlinks are stored for the device
+	client := mqtt.NewClient(mqtt.NewClientOptions().AddBroker(broker.URL()).SetClientID("app"))
+	token := client.Connect()
+	assert.True(token.WaitTimeout(time.Second))
+	assert.NoError(token.Error())
+	defer client.Disconnect(0)
+
+	commandTopic := fmt.Sprintf("application/%s/device/%s/command/down", topicEUI(app.AppEUI), topicEUI(device.DeviceEUI))
+	assert.True(broker.WaitForSubscriber(commandTopic, time.Second))
+
+	publish := func(topic string, cmd downlinkCommand) {
+		buf, err := json.Marshal(cmd)
+		assert.NoError(err)
+		token := client.Publish(topic, 1, false, buf)
+		assert.True(token.WaitTimeout(time.Second))
+		assert.NoError(token.Error())
+	}
+	// Devices in other applications and the reserved ports are ignored
+	publish(fmt.Sprintf("application/%s/device/%s/command/down", topicEUI(protocol.EUIFromInt64(9)), topicEUI(device.DeviceEUI)),
+		downlinkCommand{FPort: 2, Data: []byte{9}})
+	publish(commandTopic, downlinkCommand{FPort: 0, Data: []byte{8}})
+	publish(commandTopic, downlinkCommand{FPort: 224, Data: []byte{7}})
+	publish(commandTopic, downlinkCommand{FPort: 2, Data: []byte{0xAA, 0xBB}, Confirmed: true})
+
+	var downlinks []model.DownstreamMessage
+	assert.Eventually(func() bool {
+		downlinks, err = store.ListDownstreamMessages(device.DeviceEUI)
+		return err == nil && len(downlinks) > 0
+	}, time.Second, 10*time.Millisecond)
+	assert.Len(downlinks, 1)
+	assert.Equal(hex.EncodeToString([]byte{0xAA, 0xBB}), downlinks[0].Data)
+	assert.Equal(uint8(2), downlinks[0].Port)
+	assert.True(downlinks[0].Ack)
+
+	integration.Stop()
+}
@@ -0,0 +1,296 @@
+package integration
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+)
+
+const (
+	// webhookTimeout is the timeout for each request to a webhook
+	webhookTimeout = 10 * time.Second
+	// defaultInitialBackoff is the wait time before the first retry. The
+	// wait time is doubled for each retry.
+	defaultInitialBackoff = time.Second
+	// defaultMaxBackoff is the maximum wait time between retries
+	defaultMaxBackoff = time.Minute
+	// webhookEventHeader is the header with the event type
+	webhookEventHeader = "X-Lospan-Event"
+	// webhookSignatureHeader is the header with the HMAC-SHA256 signature
+	// of the body. It is only set when the webhook has a secret.
+	webhookSignatureHeader = "X-Lospan-Signature"
+)
+
+// webhookEvent is an event waiting to be delivered to a webhook
+type webhookEvent struct {
+	eventType server.AppEventType
+	body      []byte
+}
+
+// webhookWorker delivers the events for a single webhook
+type webhookWorker struct {
+	hook  model.Webhook
+	queue chan webhookEvent
+}
+
+// WebhookDispatcher delivers the application events to the webhooks for
+// the application. The events are POSTed as JSON. Each webhook has its own
+// queue and the events are retried with exponential backoff if the
+// webhook is unavailable. All delivery attempts are written to the delivery
+// log for the webhook.
+type WebhookDispatcher struct {
+	context        *server.Context
+	queueSize      int
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	client         *http.Client
+	events         <-chan *server.PayloadMessage
+	mutex          *sync.Mutex // Mutex for the workers
+	workers        map[int64]*webhookWorker
+	wg             *sync.WaitGroup // Wait group for the workers
+	stopping       chan bool       // Closed when the dispatcher is stopping
+	done           chan bool       // Closed when the router subscription ends
+}
+
+// NewWebhookDispatcher creates a new webhook dispatcher. The queue size and
+// the number of attempts are read from the configuration.
+func NewWebhookDispatcher(context *server.Context) *WebhookDispatcher {
+	ret := &WebhookDispatcher{
+		context:        context,
+		queueSize:      server.DefaultWebhookQueueSize,
+		maxAttempts:    server.DefaultWebhookMaxAttempts,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		client:         &http.Client{Timeout: webhookTimeout},
+		mutex:          &sync.Mutex{},
+		workers:        make(map[int64]*webhookWorker),
+		wg:             &sync.WaitGroup{},
+		stopping:       make(chan bool),
+		done:           make(chan bool),
+	}
+	if cfg := context.Config; cfg != nil {
+		if cfg.WebhookQueueSize > 0 {
+			ret.queueSize = cfg.WebhookQueueSize
+		}
+		if cfg.WebhookMaxAttempts > 0 {
+			ret.maxAttempts = cfg.WebhookMaxAttempts
+		}
+	}
+	return ret
+}
+
+// Start starts delivering the events for all of the applications
+func (d *WebhookDispatcher) Start() error {
+	if d.context.AppRouter == nil {
+		return errors.New("no application router for the webhooks")
+	}
+	if d.context.Storage == nil {
+		return errors.New("no storage for the webhooks")
+	}
+	d.events = d.context.AppRouter.SubscribeAll()
+	go d.receive()
+	return nil
+}
+
+// Stop stops the dispatcher. Events that haven't been delivered are
+// dropped.
+func (d *WebhookDispatcher) Stop() {
+	if d.events == nil {
+		// Not started
+		return
+	}
+	close(d.stopping)
+	d.context.AppRouter.Unsubscribe(d.events)
+	<-d.done
+	d.wg.Wait()
+}
+
+// receive reads the events from the application router and queues them
+// for the webhooks until the subscription ends.
+func (d *WebhookDispatcher) receive() {
+	for msg := range d.events {
+		hooks, err := d.context.Storage.ListWebhooks(msg.Application.AppEUI)
+		if err != nil {
+			lg.Warning("Unable to list webhooks for application %s: %v", msg.Application.AppEUI, err)
+			continue
+		}
+		d.removeWorkers(msg.Application.AppEUI, hooks)
+		if len(hooks) == 0 {
+			continue
+		}
+		buf, err := json.Marshal(newAppEvent(msg))
+		if err != nil {
+			lg.Warning("Unable to marshal %s event for device %s: %v", msg.Type, msg.Device.DeviceEUI, err)
+			continue
+		}
+		for _, hook := range hooks {
+			d.enqueue(hook, webhookEvent{eventType: msg.Type, body: buf})
+		}
+	}
+
+	d.mutex.Lock()
+	for id, w := range d.workers {
+		close(w.queue)
+		delete(d.workers, id)
+	}
+	d.mutex.Unlock()
+	close(d.done)
+}
+
+// removeWorkers stops the workers for webhooks that have been removed from
+// the application
+func (d *WebhookDispatcher) removeWorkers(appEUI protocol.EUI, hooks []model.Webhook) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for id, w := range d.workers {
+		if w.hook.AppEUI != appEUI {
+			continue
+		}
+		found := false
+		for _, hook := range hooks {
+			if hook.ID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			close(w.queue)
+			delete(d.workers, id)
+		}
+	}
+}
+
+// enqueue adds the event to the queue for the webhook. The event is
+// dropped if the queue is full.
+func (d *WebhookDispatcher) enqueue(hook model.Webhook, ev webhookEvent) {
+	d.mutex.Lock()
+	w, ok := d.workers[hook.ID]
+	if !ok {
+		w = &webhookWorker{hook: hook, queue: make(chan webhookEvent, d.queueSize)}
+		d.workers[hook.ID] = w
+		d.wg.Add(1)
+		go d.run(w)
+	}
+	d.mutex.Unlock()
+
+	select {
+	case w.queue <- ev:
+	default:
+		lg.Warning("Queue for webhook %d is full. Dropping %s event", hook.ID, ev.eventType)
+		d.logDelivery(model.WebhookDelivery{
+			WebhookID: hook.ID,
+			EventType: string(ev.eventType),
+			Error:     "queue is full",
+		})
+	}
+}
+
+// run delivers the queued events for a webhook until the queue is closed
+func (d *WebhookDispatcher) run(w *webhookWorker) {
+	defer d.wg.Done()
+	for ev := range w.queue {
+		select {
+		case <-d.stopping:
+			return
+		default:
+		}
+		d.deliver(w.hook, ev)
+	}
+}
+
+// deliver sends the event to the webhook. Network errors, server errors
+// and rate limiting are retried with exponential backoff. Other errors
+// are permanent.
+func (d *WebhookDispatcher) deliver(hook model.Webhook, ev webhookEvent) {
+	backoff := d.initialBackoff
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		statusCode, err := d.post(hook, ev)
+		delivery := model.WebhookDelivery{
+			WebhookID:  hook.ID,
+			EventType:  string(ev.eventType),
+			Attempt:    attempt,
+			StatusCode: statusCode,
+			Delivered:  err == nil,
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+		d.logDelivery(delivery)
+		if err == nil {
+			return
+		}
+		if statusCode != 0 && statusCode != http.StatusTooManyRequests && statusCode < http.StatusInternalServerError {
+			lg.Info("Webhook %d rejected %s event: %v", hook.ID, ev.eventType, err)
+			return
+		}
+		if attempt == d.maxAttempts {
+			break
+		}
+		select {
+		case <-time.After(backoff):
+		case <-d.stopping:
+			return
+		}
+		backoff *= 2
+		if backoff > d.maxBackoff {
+			backoff = d.maxBackoff
+		}
+	}
+	lg.Warning("Giving up delivering %s event to webhook %d after %d attempts", ev.eventType, hook.ID, d.maxAttempts)
+}
+
+// post sends a single request to the webhook. The status code is 0 if
+// there's no response.
+func (d *WebhookDispatcher) post(hook model.Webhook, ev webhookEvent) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(ev.body))
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range hook.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, string(ev.eventType))
+	if hook.Secret != "" {
+		req.Header.Set(webhookSignatureHeader, webhookSignature(hook.Secret, ev.body))
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *WebhookDispatcher) logDelivery(delivery model.WebhookDelivery) {
+	delivery.Time = time.Now().UnixNano()
+	if err := d.context.Storage.AddWebhookDelivery(delivery); err != nil {
+		lg.Warning("Unable to log delivery for webhook %d: %v", delivery.WebhookID, err)
+	}
+}
+
+// webhookSignature returns the signature header for the body, ie
+// "sha256=<hex encoded HMAC-SHA256>"
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
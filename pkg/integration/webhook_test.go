@@ -0,0 +1,106 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+	"github.com/lab5e/lospan/pkg/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDispatcher(t *testing.T) {
+	assert := require.New(t)
+
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+
+	app := model.NewApplication()
+	app.AppEUI = protocol.EUIFromInt64(0x0100000000000002)
+	assert.NoError(store.CreateApplication(app))
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x0200000000000002)
+	device.AppEUI = app.AppEUI
+
+	// The first request fails and is retried
+	var requests int32
+	bodies := make(chan []byte, 5)
+	hookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer 1234" || r.Header.Get(webhookEventHeader) != "up" ||
+			r.Header.Get(webhookSignatureHeader) != webhookSignature("secret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		bodies <- body
+	}))
+	defer hookServer.Close()
+
+	// Client errors aren't retried
+	rejectServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejectServer.Close()
+
+	hook, err := store.CreateWebhook(model.Webhook{AppEUI: app.AppEUI, URL: hookServer.URL,
+		Headers: map[string]string{"Authorization": "Bearer 1234"}, Secret: "secret"})
+	assert.NoError(err)
+	reject, err := store.CreateWebhook(model.Webhook{AppEUI: app.AppEUI, URL: rejectServer.URL})
+	assert.NoError(err)
+
+	router := server.NewEventRouter[protocol.EUI, *server.PayloadMessage](5)
+	dispatcher := NewWebhookDispatcher(&server.Context{Storage: store, AppRouter: &router, Config: server.NewDefaultConfig()})
+	dispatcher.initialBackoff = 10 * time.Millisecond
+	assert.NoError(dispatcher.Start())
+
+	router.Publish(app.AppEUI, &server.PayloadMessage{
+		Type:        server.UplinkEvent,
+		Payload:     []byte{1, 2, 3},
+		FPort:       1,
+		FCnt:        42,
+		Device:      device,
+		Application: app,
+	})
+
+	select {
+	case body := <-bodies:
+		ev := appEvent{}
+		assert.NoError(json.Unmarshal(body, &ev))
+		assert.Equal(server.UplinkEvent, ev.Type)
+		assert.Equal(uint32(42), ev.FCnt)
+		assert.Equal([]byte{1, 2, 3}, ev.Data)
+	case <-time.After(2 * time.Second):
+		assert.Fail("No event delivered to webhook")
+	}
+
+	var deliveries []model.WebhookDelivery
+	assert.Eventually(func() bool {
+		deliveries, err = store.ListWebhookDeliveries(hook.ID, 10)
+		return err == nil && len(deliveries) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.True(deliveries[0].Delivered)
+	assert.Equal(2, deliveries[0].Attempt)
+	assert.False(deliveries[1].Delivered)
+	assert.Equal(http.StatusServiceUnavailable, deliveries[1].StatusCode)
+	assert.NotEmpty(deliveries[1].Error)
+
+	assert.Eventually(func() bool {
+		deliveries, err = store.ListWebhookDeliveries(reject.ID, 10)
+		return err == nil && len(deliveries) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(http.StatusBadRequest, deliveries[0].StatusCode)
+	assert.False(deliveries[0].Delivered)
+
+	dispatcher.Stop()
+}
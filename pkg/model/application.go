@@ -8,8 +8,10 @@ import (
 
 // Application represents a LoRa application instance.
 type Application struct {
-	AppEUI protocol.EUI // Application EUI
-	Tag    string       // Tag data (for external ref)
+	AppEUI  protocol.EUI // Application EUI
+	Tag     string       // Tag data (for external ref)
+	Decoder string       // Payload decoder (CEL expression). Blank if there's no decoder
+	Encoder string       // Payload encoder (CEL expression). Blank if there's no encoder
 }
 
 // Equals returns true if the other application has identical fields. Just like
@@ -0,0 +1,199 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/lab5e/lospan/pkg/band"
+)
+
+// MaxMultiSFChannels is the number of multi-SF LoRa channels in a concentrator
+const MaxMultiSFChannels = 8
+
+// Concentrator channel numbers for the LoRa std and FSK channels. The multi-SF
+// channels use channel 0-7.
+const (
+	LoRaStdChannel = MaxMultiSFChannels
+	FSKChannel     = MaxMultiSFChannels + 1
+)
+
+// ConcentratorChannel is a single IF channel in the concentrator. The channel
+// frequency is the IF offset relative to the centre frequency of the radio.
+// The fields match the channel settings in the packet forwarder's
+// configuration (chan_multiSF_N, chan_Lora_std and chan_FSK).
+type ConcentratorChannel struct {
+	Enabled      bool   `json:"enable"`
+	Radio        uint8  `json:"radio"`                   // RF chain the channel is connected to
+	IF           int32  `json:"if"`                      // IF offset in Hz
+	Bandwidth    uint32 `json:"bandwidth,omitempty"`     // Bandwidth in Hz (LoRa std and FSK only)
+	SpreadFactor uint8  `json:"spread_factor,omitempty"` // Spread factor (LoRa std only)
+	DataRate     uint32 `json:"datarate,omitempty"`      // Bit rate (FSK only)
+}
+
+// ConcentratorConfig is the channel configuration for a gateway's
+// concentrator, ie the RF chain centre frequencies and the IF channels. The
+// index in MultiSF is the concentrator channel reported by the gateway.
+type ConcentratorConfig struct {
+	Radios  []float32             `json:"radios"`   // Centre frequency for each RF chain, in MHz
+	MultiSF []ConcentratorChannel `json:"multi_sf"` // Multi-SF LoRa channels (channel 0-7)
+	LoRaStd ConcentratorChannel   `json:"lora_std"` // Single-SF LoRa channel (channel 8)
+	FSK     ConcentratorChannel   `json:"fsk"`      // FSK channel (channel 9)
+}
+
+// Empty returns true if there's no radios in the configuration
+func (c ConcentratorConfig) Empty() bool {
+	return len(c.Radios) == 0
+}
+
+// Validate checks that the channels refer to existing radios
+func (c ConcentratorConfig) Validate() error {
+	if c.Empty() {
+		return nil
+	}
+	if len(c.MultiSF) > MaxMultiSFChannels {
+		return fmt.Errorf("too many multi-SF channels (%d). Max is %d", len(c.MultiSF), MaxMultiSFChannels)
+	}
+	channels := append([]ConcentratorChannel{c.LoRaStd, c.FSK}, c.MultiSF...)
+	for _, ch := range channels {
+		if ch.Enabled && int(ch.Radio) >= len(c.Radios) {
+			return fmt.Errorf("channel uses radio %d but there's only %d radios", ch.Radio, len(c.Radios))
+		}
+	}
+	if c.LoRaStd.Enabled && (c.LoRaStd.SpreadFactor < 7 || c.LoRaStd.SpreadFactor > 12 || c.LoRaStd.Bandwidth == 0) {
+		return errors.New("LoRa std channel needs a spread factor (7-12) and bandwidth")
+	}
+	if c.FSK.Enabled && c.FSK.DataRate == 0 {
+		return errors.New("FSK channel needs a data rate")
+	}
+	return nil
+}
+
+// channel returns the concentrator channel
+func (c ConcentratorConfig) channel(channel uint8) (ConcentratorChannel, bool) {
+	var ch ConcentratorChannel
+	switch {
+	case int(channel) < len(c.MultiSF):
+		ch = c.MultiSF[channel]
+	case channel == LoRaStdChannel:
+		ch = c.LoRaStd
+	case channel == FSKChannel:
+		ch = c.FSK
+	}
+	if !ch.Enabled || int(ch.Radio) >= len(c.Radios) {
+		return ch, false
+	}
+	return ch, true
+}
+
+// frequency returns the frequency for the channel in MHz. The frequency is
+// rounded to 100Hz.
+func (c ConcentratorConfig) frequency(ch ConcentratorChannel) float32 {
+	hz := float64(c.Radios[ch.Radio])*1e6 + float64(ch.IF)
+	return float32(math.Round(hz/100) / 1e4)
+}
+
+// Frequency returns the frequency (in MHz) of a concentrator channel
+func (c ConcentratorConfig) Frequency(channel uint8) (float32, error) {
+	ch, ok := c.channel(channel)
+	if !ok {
+		return 0, fmt.Errorf("channel %d isn't configured", channel)
+	}
+	return c.frequency(ch), nil
+}
+
+// Channel returns the concentrator channel for a frequency (in MHz)
+func (c ConcentratorConfig) Channel(frequency float32) (uint8, bool) {
+	for i := uint8(0); i <= FSKChannel; i++ {
+		if f, err := c.Frequency(i); err == nil && math.Abs(float64(f-frequency)) < 0.0001 {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Frequencies returns the frequencies of the enabled multi-SF channels,
+// ordered by concentrator channel.
+func (c ConcentratorConfig) Frequencies() []float32 {
+	var ret []float32
+	for i := range c.MultiSF {
+		if f, err := c.Frequency(uint8(i)); err == nil {
+			ret = append(ret, f)
+		}
+	}
+	return ret
+}
+
+// UplinkFrequencies returns the frequencies of the enabled LoRa channels, ie
+// the multi-SF channels and the LoRa std channel.
+func (c ConcentratorConfig) UplinkFrequencies() []float32 {
+	ret := c.Frequencies()
+	if f, err := c.Frequency(LoRaStdChannel); err == nil {
+		ret = append(ret, f)
+	}
+	return ret
+}
+
+// Equals checks configurations for equality
+func (c ConcentratorConfig) Equals(other ConcentratorConfig) bool {
+	if len(c.Radios) != len(other.Radios) || len(c.MultiSF) != len(other.MultiSF) {
+		return false
+	}
+	for i := range c.Radios {
+		if c.Radios[i] != other.Radios[i] {
+			return false
+		}
+	}
+	for i := range c.MultiSF {
+		if c.MultiSF[i] != other.MultiSF[i] {
+			return false
+		}
+	}
+	return c.LoRaStd == other.LoRaStd && c.FSK == other.FSK
+}
+
+// multiSF is a shorthand for enabled multi-SF channels
+func multiSF(radio uint8, ifOffset int32) ConcentratorChannel {
+	return ConcentratorChannel{Enabled: true, Radio: radio, IF: ifOffset}
+}
+
+// DefaultConcentratorConfig returns the default channel configuration for a
+// frequency plan. The configurations are the ones that ship with the Semtech
+// packet forwarder; US915 and AU915 use the second sub-band (channel 8-15 and
+// 65). Other
+// plans have no default configuration and the forwarders use the frequency
+// reported by the gateway.
+func DefaultConcentratorConfig(plan band.FrequencyBandType) ConcentratorConfig {
+	switch plan {
+	case band.EU868Band:
+		return ConcentratorConfig{
+			Radios: []float32{867.5, 868.5},
+			MultiSF: []ConcentratorChannel{
+				multiSF(1, -400000), multiSF(1, -200000), multiSF(1, 0),
+				multiSF(0, -400000), multiSF(0, -200000), multiSF(0, 0), multiSF(0, 200000), multiSF(0, 400000),
+			},
+			LoRaStd: ConcentratorChannel{Enabled: true, Radio: 1, IF: -200000, Bandwidth: 250000, SpreadFactor: 7},
+			FSK:     ConcentratorChannel{Enabled: true, Radio: 1, IF: 300000, Bandwidth: 125000, DataRate: 50000},
+		}
+	case band.US915Band:
+		return ConcentratorConfig{
+			Radios: []float32{904.3, 905.0},
+			MultiSF: []ConcentratorChannel{
+				multiSF(0, -400000), multiSF(0, -200000), multiSF(0, 0), multiSF(0, 200000),
+				multiSF(1, -300000), multiSF(1, -100000), multiSF(1, 100000), multiSF(1, 300000),
+			},
+			LoRaStd: ConcentratorChannel{Enabled: true, Radio: 0, IF: 300000, Bandwidth: 500000, SpreadFactor: 8},
+		}
+	case band.AU915Band:
+		return ConcentratorConfig{
+			Radios: []float32{917.2, 917.9},
+			MultiSF: []ConcentratorChannel{
+				multiSF(0, -400000), multiSF(0, -200000), multiSF(0, 0), multiSF(0, 200000),
+				multiSF(1, -300000), multiSF(1, -100000), multiSF(1, 100000), multiSF(1, 300000),
+			},
+			LoRaStd: ConcentratorChannel{Enabled: true, Radio: 0, IF: 300000, Bandwidth: 500000, SpreadFactor: 8},
+		}
+	default:
+		return ConcentratorConfig{}
+	}
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/lab5e/lospan/pkg/band"
+)
+
+func TestConcentratorFrequencies(t *testing.T) {
+	eu := DefaultConcentratorConfig(band.EU868Band)
+	expected := []float32{868.1, 868.3, 868.5, 867.1, 867.3, 867.5, 867.7, 867.9}
+	for i, f := range expected {
+		freq, err := eu.Frequency(uint8(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if freq != f {
+			t.Errorf("Expected %f for channel %d but got %f", f, i, freq)
+		}
+		ch, ok := eu.Channel(f)
+		if !ok || ch != uint8(i) {
+			t.Errorf("Expected channel %d for %f but got %d", i, f, ch)
+		}
+	}
+	if f, err := eu.Frequency(LoRaStdChannel); err != nil || f != 868.3 {
+		t.Errorf("Unexpected LoRa std frequency: %f (%v)", f, err)
+	}
+	if f, err := eu.Frequency(FSKChannel); err != nil || f != 868.8 {
+		t.Errorf("Unexpected FSK frequency: %f (%v)", f, err)
+	}
+	if _, err := eu.Frequency(10); err == nil {
+		t.Error("Expected error for unknown channel")
+	}
+	if len(eu.Frequencies()) != len(expected) {
+		t.Errorf("Expected %d frequencies but got %d", len(expected), len(eu.Frequencies()))
+	}
+
+	us := DefaultConcentratorConfig(band.US915Band)
+	expected = []float32{903.9, 904.1, 904.3, 904.5, 904.7, 904.9, 905.1, 905.3}
+	for i, f := range expected {
+		if freq, _ := us.Frequency(uint8(i)); freq != f {
+			t.Errorf("Expected %f for channel %d but got %f", f, i, freq)
+		}
+	}
+	if f, err := us.Frequency(LoRaStdChannel); err != nil || f != 904.6 {
+		t.Errorf("Unexpected LoRa std frequency: %f (%v)", f, err)
+	}
+	if _, err := us.Frequency(FSKChannel); err == nil {
+		t.Error("FSK channel should be disabled")
+	}
+
+	au := DefaultConcentratorConfig(band.AU915Band)
+	expected = []float32{916.8, 917, 917.2, 917.4, 917.6, 917.8, 918, 918.2, 917.5}
+	uplink := au.UplinkFrequencies()
+	if len(uplink) != len(expected) {
+		t.Fatalf("Expected %d uplink frequencies but got %d", len(expected), len(uplink))
+	}
+	for i, f := range expected {
+		if uplink[i] != f {
+			t.Errorf("Expected %f for uplink frequency %d but got %f", f, i, uplink[i])
+		}
+	}
+
+	if !DefaultConcentratorConfig(band.KR920Band).Empty() {
+		t.Error("Expected no default configuration for KR920")
+	}
+}
+
+func TestConcentratorValidate(t *testing.T) {
+	if err := DefaultConcentratorConfig(band.EU868Band).Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := (ConcentratorConfig{}).Validate(); err != nil {
+		t.Fatal("Empty configuration should be valid")
+	}
+	c := DefaultConcentratorConfig(band.EU868Band)
+	c.MultiSF[0].Radio = 2
+	if err := c.Validate(); err == nil {
+		t.Fatal("Expected error for unknown radio")
+	}
+	c = DefaultConcentratorConfig(band.EU868Band)
+	c.MultiSF = append(c.MultiSF, multiSF(0, 0))
+	if err := c.Validate(); err == nil {
+		t.Fatal("Expected error for too many channels")
+	}
+	c = DefaultConcentratorConfig(band.EU868Band)
+	c.LoRaStd.SpreadFactor = 0
+	if err := c.Validate(); err == nil {
+		t.Fatal("Expected error for missing spread factor")
+	}
+	c = DefaultConcentratorConfig(band.EU868Band)
+	c.FSK.DataRate = 0
+	if err := c.Validate(); err == nil {
+		t.Fatal("Expected error for missing FSK data rate")
+	}
+}
+
+func TestGatewayChannelConfig(t *testing.T) {
+	gw := NewGateway()
+	if !gw.ChannelConfig().Equals(DefaultConcentratorConfig(band.EU868Band)) {
+		t.Fatal("Expected default EU868 configuration")
+	}
+	gw.Plan = band.US915Band
+	if !gw.ChannelConfig().Equals(DefaultConcentratorConfig(band.US915Band)) {
+		t.Fatal("Expected default US915 configuration")
+	}
+	gw.Channels = ConcentratorConfig{Radios: []float32{902.7}, MultiSF: []ConcentratorChannel{multiSF(0, -400000)}}
+	if f, _ := gw.ChannelConfig().Frequency(0); f != 902.3 {
+		t.Fatalf("Expected custom configuration but got %f for channel 0", f)
+	}
+	other := gw
+	other.Channels = DefaultConcentratorConfig(band.US915Band)
+	if gw.Equals(other) {
+		t.Fatal("Gateways with different channels should not be equal")
+	}
+}
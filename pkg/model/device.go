@@ -2,9 +2,11 @@ package model
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
+	"github.com/lab5e/lospan/pkg/band"
 	"github.com/lab5e/lospan/pkg/lg"
 	"github.com/lab5e/lospan/pkg/protocol"
 )
@@ -50,38 +52,177 @@ func DeviceStateFromString(str string) (DeviceState, error) {
 	}
 }
 
+// DeviceClass is the LoRaWAN device class. The class decides when the device
+// is able to receive downlink frames.
+type DeviceClass uint8
+
+// Device classes. Class A devices only listen in the two receive windows
+// following an uplink, class B devices listen in scheduled ping slots and
+// class C devices listen continuously.
+const (
+	ClassA DeviceClass = 0
+	ClassB DeviceClass = 1
+	ClassC DeviceClass = 2
+)
+
+// String converts the device class into a human-readable string representation.
+func (c DeviceClass) String() string {
+	switch c {
+	case ClassA:
+		return "A"
+	case ClassB:
+		return "B"
+	case ClassC:
+		return "C"
+	default:
+		lg.Warning("Unknown device class: %d", c)
+		return "A"
+	}
+}
+
+// DeviceClassFromString converts a string representation of DeviceClass into
+// a DeviceClass value. Unknown strings returns ClassA. Conversion is not case
+// sensitive. White space is trimmed.
+func DeviceClassFromString(str string) (DeviceClass, error) {
+	switch strings.TrimSpace(strings.ToUpper(str)) {
+	case "A":
+		return ClassA, nil
+	case "B":
+		return ClassB, nil
+	case "C":
+		return ClassC, nil
+	default:
+		return ClassA, fmt.Errorf("unknown device class: %s", str)
+	}
+}
+
+// MACVersion is the LoRaWAN MAC version implemented by the device
+type MACVersion uint8
+
+// LoRaWAN MAC versions. LoRaWAN 1.1 devices use separate root keys for the
+// network and application and a different set of session keys.
+const (
+	LoRaWAN10 MACVersion = 0
+	LoRaWAN11 MACVersion = 1
+)
+
+// String converts the MAC version into a human-readable string representation.
+func (v MACVersion) String() string {
+	switch v {
+	case LoRaWAN10:
+		return "1.0"
+	case LoRaWAN11:
+		return "1.1"
+	default:
+		lg.Warning("Unknown MAC version: %d", v)
+		return "1.0"
+	}
+}
+
+// MACVersionFromString converts a string representation of MACVersion into
+// a MACVersion value. Unknown strings returns LoRaWAN10. White space is
+// trimmed.
+func MACVersionFromString(str string) (MACVersion, error) {
+	switch strings.TrimSpace(str) {
+	case "1.0":
+		return LoRaWAN10, nil
+	case "1.1":
+		return LoRaWAN11, nil
+	default:
+		return LoRaWAN10, fmt.Errorf("unknown MAC version: %s", str)
+	}
+}
+
 // Device represents a device. Devices are associated with one and only one Application
 type Device struct {
 	DeviceEUI       protocol.EUI     // EUI for device
 	DevAddr         protocol.DevAddr // Device address
 	AppKey          protocol.AESKey  // AES key for application
 	AppSKey         protocol.AESKey  // Application session key
-	NwkSKey         protocol.AESKey  // Network session key
+	NwkSKey         protocol.AESKey  // Network session key. This is the FNwkSIntKey for LoRaWAN 1.1 devices
 	AppEUI          protocol.EUI     // The application associated with the device. Set by storage backend
 	State           DeviceState      // Current state of the device
-	FCntUp          uint16           // Frame counter up (from device)
-	FCntDn          uint16           // Frame counter down (to device)
+	FCntUp          uint32           // Frame counter up (from device)
+	FCntDn          uint32           // Frame counter down (to device). LoRaWAN 1.1 devices use NFCntDown and AFCntDown
 	RelaxedCounter  bool             // Relaxed frame count checks
 	DevNonceHistory []uint16         // Log of DevNonces sent from the device
 	KeyWarning      bool             // Duplicate key warning flag
 	Tag             string           // Tag data (for external refs)
+	DataRate        uint8            // Data rate accepted by the device (via LinkADRReq)
+	TXPower         uint8            // TX power index accepted by the device (via LinkADRReq)
+	ChannelMask     uint16           // Channel mask accepted by the device (via LinkADRReq)
+	RX1DROffset     uint8            // Data rate offset for the first receive window
+	RX2DataRate     uint8            // Data rate for the second receive window. Set by the DLSettings when the device joins or by the device profile
+	RX2Frequency    float32          // Frequency (in MHz) for the second receive window. 0 = band default
+	RX1Delay        uint8            // Delay (in seconds) before the first receive window. 0 = band default
+	MaxDutyCycle    uint8            // Max duty cycle (as 1/2^MaxDutyCycle). 0 = no limit
+	Class           DeviceClass      // Device class (A, B or C)
+	PingPeriodicity uint8            // Class B ping slot periodicity. The device opens a slot every 2^PingPeriodicity seconds
+	PingDataRate    uint8            // Data rate for class B ping slots. Only used if PingFrequency is set
+	PingFrequency   float32          // Frequency (in MHz) for class B ping slots. 0 = band default
+	BeaconFrequency float32          // Frequency (in MHz) for class B beacons (via BeaconFreqReq). 0 = band default
+	MACVersion      MACVersion       // LoRaWAN MAC version implemented by the device
+	NwkKey          protocol.AESKey  // Network root key (LoRaWAN 1.1 only)
+	SNwkSIntKey     protocol.AESKey  // Serving network session integrity key (LoRaWAN 1.1 only)
+	NwkSEncKey      protocol.AESKey  // Network session encryption key (LoRaWAN 1.1 only)
+	JoinNonce       uint32           // Last JoinNonce sent to the device (LoRaWAN 1.1 only)
+	ProfileID       int64            // The device profile. 0 = no profile
+	Channels        []band.Channel   // Extra channels set up on the device (via the CFList or NewChannelReq). The first is the channel after the band's default channels
+	NFCntDown       uint32           // Network frame counter down for frames without FPort or FPort 0 (LoRaWAN 1.1 only)
+	AFCntDown       uint32           // Application frame counter down for frames with FPort > 0 (LoRaWAN 1.1 only)
 }
 
+// DefaultPingPeriodicity is the ping slot periodicity class B devices use
+// until they send a PingSlotInfoReq, ie one ping slot every 128 seconds.
+const DefaultPingPeriodicity = 7
+
 // NewDevice creates a new device
 func NewDevice() Device {
-	return Device{}
+	return Device{PingPeriodicity: DefaultPingPeriodicity}
 }
 
-// GetRX1Window returns the 1st receive window for the device
-// BUG(stlaehd): Returns constant. Should be set based on device settings and frequency plan.
-func (d *Device) GetRX1Window() time.Duration {
-	return time.Second * 1
+// GetRX1Window returns the delay from the end of the uplink to the 1st receive
+// window for the device. Devices that haven't negotiated an RX1 delay use the
+// band's ReceiveDelay1.
+func (d *Device) GetRX1Window(config *band.Configuration) time.Duration {
+	delay := config.ReceiveDelay1
+	if d.RX1Delay != 0 {
+		delay = d.RX1Delay
+	}
+	return time.Duration(delay) * time.Second
 }
 
-// GetRX2Window returns the 2nd receive window for the device
-// BUG(stalehd): Returns a constant. Should be set based on frequency plan (EU, US, CN)
-func (d *Device) GetRX2Window() time.Duration {
-	return time.Second * 2
+// GetRX2Window returns the delay from the end of the uplink to the 2nd receive
+// window for the device. The 2nd window opens one second after the 1st [3.3.2].
+func (d *Device) GetRX2Window(config *band.Configuration) time.Duration {
+	return d.GetRX1Window(config) + time.Duration(config.ReceiveDelay2-config.ReceiveDelay1)*time.Second
+}
+
+// RX2Parameters returns the data rate and frequency for the second receive
+// window. ABP devices without a device profile haven't got their RX2 data
+// rate from the network and use the band default. Devices without an RX2
+// frequency use the band default.
+func (d *Device) RX2Parameters(plan band.FrequencyPlan) band.DownlinkParameters {
+	ret := plan.GetRX2Parameters()
+	if d.State != PersonalizedDevice || d.ProfileID != 0 {
+		ret.DataRate = d.RX2DataRate
+	}
+	if d.RX2Frequency != 0 {
+		ret.Frequency = d.RX2Frequency
+	}
+	return ret
+}
+
+// RX1Frequency returns the RX1 frequency for an uplink. Extra channels with a
+// downlink frequency (set through DlChannelReq) use that frequency, other
+// uplinks use the band's RX1 frequency.
+func (d *Device) RX1Frequency(uplink float32, rx1 float32) float32 {
+	for _, ch := range d.Channels {
+		if ch.DownlinkFrequency != 0 && math.Abs(float64(ch.Frequency-uplink)) < 0.0001 {
+			return ch.DownlinkFrequency
+		}
+	}
+	return rx1
 }
 
 // HasDevNonce returns true if the specified nonce exists in the nonce history
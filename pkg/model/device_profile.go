@@ -0,0 +1,68 @@
+package model
+
+import (
+	"errors"
+)
+
+// DeviceProfile holds the radio settings shared by a group of devices. The
+// settings are applied to the devices when they are created or updated and
+// when they join the network. ABP devices are updated when the profile
+// changes. Service profiles (the network settings for a group of devices)
+// are not supported.
+type DeviceProfile struct {
+	ID                int64       // Identifier. Assigned by the storage layer
+	Name              string      // Name of profile
+	MACVersion        MACVersion  // LoRaWAN MAC version
+	RegParamsRevision string      // Regional parameters revision, ie "RP002-1.0.3". Informational only; each band implements a single revision
+	Class             DeviceClass // Device class (A, B or C)
+	SupportsJoin      bool        // The devices can join via OTAA
+	RX1Delay          uint8       // Delay (in seconds) before the first receive window
+	RX1DROffset       uint8       // Data rate offset for the first receive window
+	RX2DataRate       uint8       // Data rate for the second receive window
+	RX2Frequency      float32     // Frequency (in MHz) for the second receive window. 0 = band default
+	MaxEIRP           uint8       // Max EIRP (in dBm) for the devices. Limits the TX power set by ADR. 0 = band default
+	ADREnabled        bool        // Adaptive data rate is enabled for the devices
+}
+
+// NewDeviceProfile creates a new device profile with the default settings,
+// ie class A devices that support OTAA and ADR with a one second RX1 delay.
+func NewDeviceProfile() DeviceProfile {
+	return DeviceProfile{
+		MACVersion:   LoRaWAN10,
+		Class:        ClassA,
+		SupportsJoin: true,
+		RX1Delay:     1,
+		ADREnabled:   true,
+	}
+}
+
+// Validate checks the profile settings
+func (p *DeviceProfile) Validate() error {
+	if p.Name == "" {
+		return errors.New("profile must have a name")
+	}
+	if p.RX1Delay < 1 || p.RX1Delay > 15 {
+		return errors.New("RX1 delay must be 1-15 seconds")
+	}
+	if p.RX1DROffset > 7 {
+		return errors.New("RX1 data rate offset must be 0-7")
+	}
+	if p.RX2DataRate > 15 {
+		return errors.New("RX2 data rate must be 0-15")
+	}
+	return nil
+}
+
+// Apply copies the profile settings to the device. The device keeps its
+// own RX2 frequency if the profile uses the band default.
+func (p *DeviceProfile) Apply(device *Device) {
+	device.ProfileID = p.ID
+	device.MACVersion = p.MACVersion
+	device.Class = p.Class
+	device.RX1Delay = p.RX1Delay
+	device.RX1DROffset = p.RX1DROffset
+	device.RX2DataRate = p.RX2DataRate
+	if p.RX2Frequency != 0 {
+		device.RX2Frequency = p.RX2Frequency
+	}
+}
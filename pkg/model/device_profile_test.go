@@ -0,0 +1,32 @@
+package model
+
+import "testing"
+
+func TestDeviceProfile(t *testing.T) {
+	p := NewDeviceProfile()
+	if err := p.Validate(); err == nil {
+		t.Fatal("Profiles without names should be invalid")
+	}
+	p.Name = "Sensor"
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	p.RX1Delay = 0
+	if err := p.Validate(); err == nil {
+		t.Fatal("RX1 delay must be set")
+	}
+
+	p = DeviceProfile{ID: 2, Name: "Class C", MACVersion: LoRaWAN11, Class: ClassC, RX1Delay: 3, RX1DROffset: 1, RX2DataRate: 3}
+	d := NewDevice()
+	d.RX2Frequency = 869.525
+	p.Apply(&d)
+	if d.ProfileID != 2 || d.MACVersion != LoRaWAN11 || d.Class != ClassC || d.RX1Delay != 3 || d.RX1DROffset != 1 ||
+		d.RX2DataRate != 3 || d.RX2Frequency != 869.525 {
+		t.Fatalf("Profile not applied correctly: %+v", d)
+	}
+	p.RX2Frequency = 869.1
+	p.Apply(&d)
+	if d.RX2Frequency != 869.1 {
+		t.Fatal("RX2 frequency should be set by profile")
+	}
+}
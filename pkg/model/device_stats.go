@@ -0,0 +1,31 @@
+package model
+
+import (
+	"github.com/lab5e/lospan/pkg/protocol"
+)
+
+// DeviceStats holds the frame statistics for a device. The counters are
+// updated by the decrypter when uplinks are received.
+type DeviceStats struct {
+	DeviceEUI   protocol.EUI // The device
+	Received    uint32       // Number of frames accepted from the device
+	Lost        uint32       // Number of frames lost, ie gaps in the frame counter
+	Replayed    uint32       // Number of frames rejected because the frame counter was too low
+	MICFailed   uint32       // Number of frames with an invalid MIC
+	Rejected    uint32       // Number of frames rejected because the frame counter gap was too big
+	LastUpdated int64        // Time of last update (in nanoseconds)
+}
+
+// NewDeviceStats creates a new, empty DeviceStats instance
+func NewDeviceStats(deviceEUI protocol.EUI) DeviceStats {
+	return DeviceStats{DeviceEUI: deviceEUI}
+}
+
+// FrameLoss returns the fraction of frames lost, ie 0.1 when one out of ten
+// frames is lost.
+func (d *DeviceStats) FrameLoss() float32 {
+	if d.Received+d.Lost == 0 {
+		return 0
+	}
+	return float32(d.Lost) / float32(d.Received+d.Lost)
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/lab5e/lospan/pkg/protocol"
+)
+
+func TestDeviceStatsFrameLoss(t *testing.T) {
+	stats := NewDeviceStats(protocol.EUIFromInt64(1))
+	if stats.FrameLoss() != 0 {
+		t.Fatal("Empty stats should have no frame loss")
+	}
+	stats.Received = 9
+	stats.Lost = 1
+	if stats.FrameLoss() != 0.1 {
+		t.Fatalf("Expected 10%% frame loss but got %f", stats.FrameLoss())
+	}
+}
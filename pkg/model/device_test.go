@@ -3,6 +3,8 @@ package model
 import (
 	"testing"
 	"time"
+
+	"github.com/lab5e/lospan/pkg/band"
 )
 
 func TestDeviceStateConversion(t *testing.T) {
@@ -22,14 +24,49 @@ func TestDeviceStateConversion(t *testing.T) {
 	}
 }
 
+func TestDeviceClassConversion(t *testing.T) {
+	for _, v := range []DeviceClass{ClassA, ClassB, ClassC} {
+		if val, err := DeviceClassFromString(v.String()); val != v || err != nil {
+			t.Errorf("Couldn't convert %v to and from string (error is %v)", v, err)
+		}
+	}
+
+	if _, err := DeviceClassFromString("D"); err == nil {
+		t.Error("Expected error when using unknown class")
+	}
+}
+
+func TestMACVersionConversion(t *testing.T) {
+	for _, v := range []MACVersion{LoRaWAN10, LoRaWAN11} {
+		if val, err := MACVersionFromString(v.String()); val != v || err != nil {
+			t.Errorf("Couldn't convert %v to and from string (error is %v)", v, err)
+		}
+	}
+
+	if _, err := MACVersionFromString("1.2"); err == nil {
+		t.Error("Expected error when using unknown version")
+	}
+}
+
 func TestRXWindows(t *testing.T) {
-	// These values are hard coded. The *real* test will use the device's settings
+	plan, err := band.NewBand(band.EU868Band)
+	if err != nil {
+		t.Fatal(err)
+	}
 	device := Device{}
-	if device.GetRX1Window() != (time.Second * 1) {
-		t.Error("Someone must have fixed the GetRX1Window func but not the test")
+	if device.GetRX1Window(plan.Configuration()) != (time.Second * 1) {
+		t.Errorf("Expected band RX1 delay but got %v", device.GetRX1Window(plan.Configuration()))
+	}
+	if device.GetRX2Window(plan.Configuration()) != (time.Second * 2) {
+		t.Errorf("Expected band RX2 delay but got %v", device.GetRX2Window(plan.Configuration()))
 	}
-	if device.GetRX2Window() != (time.Second * 2) {
-		t.Error("Someone must have fixed the GetRX2Window func but not the test")
+
+	device.RX1Delay = 5
+	if device.GetRX1Window(plan.Configuration()) != (time.Second * 5) {
+		t.Errorf("Expected device RX1 delay but got %v", device.GetRX1Window(plan.Configuration()))
+	}
+	if device.GetRX2Window(plan.Configuration()) != (time.Second * 6) {
+		t.Errorf("Expected device RX2 delay but got %v", device.GetRX2Window(plan.Configuration()))
 	}
 }
 
@@ -44,3 +81,40 @@ func TestDevNonce(t *testing.T) {
 		t.Fatal("Didn't expect 0 or 10 to be in nonce history")
 	}
 }
+
+func TestDeviceRX1Frequency(t *testing.T) {
+	d := NewDevice()
+	if f := d.RX1Frequency(867.1, 867.1); f != 867.1 {
+		t.Fatalf("Expected band RX1 frequency but got %f", f)
+	}
+	d.Channels = []band.Channel{{Frequency: 867.1}, {Frequency: 867.3, DownlinkFrequency: 869.525}}
+	if f := d.RX1Frequency(867.1, 867.1); f != 867.1 {
+		t.Fatalf("Expected uplink frequency for channel without downlink frequency but got %f", f)
+	}
+	if f := d.RX1Frequency(867.3, 867.3); f != 869.525 {
+		t.Fatalf("Expected downlink frequency but got %f", f)
+	}
+}
+
+func TestDeviceRX2Parameters(t *testing.T) {
+	plan, err := band.NewBand(band.US915Band)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewDevice()
+	d.State = PersonalizedDevice
+	if rx2 := d.RX2Parameters(plan); rx2 != plan.GetRX2Parameters() {
+		t.Fatalf("Expected band RX2 parameters for ABP device without profile but got %+v", rx2)
+	}
+
+	d.State = OverTheAirDevice
+	d.RX2DataRate = 10
+	if rx2 := d.RX2Parameters(plan); rx2.DataRate != 10 || rx2.Frequency != plan.GetRX2Parameters().Frequency {
+		t.Fatalf("Expected device data rate and band frequency but got %+v", rx2)
+	}
+
+	d.RX2Frequency = 923.9
+	if rx2 := d.RX2Parameters(plan); rx2.DataRate != 10 || rx2.Frequency != 923.9 {
+		t.Fatalf("Expected device RX2 parameters but got %+v", rx2)
+	}
+}
@@ -30,7 +30,7 @@ type DownstreamMessage struct {
 	CreatedTime int64
 	SentTime    int64
 	AckTime     int64
-	FCntUp      uint16
+	FCntUp      uint32
 }
 
 // NewDownstreamMessage creates a new DownstreamMessage
@@ -2,18 +2,39 @@ package model
 
 import (
 	"net"
+	"time"
 
+	"github.com/lab5e/lospan/pkg/band"
 	"github.com/lab5e/lospan/pkg/protocol"
 )
 
 // Gateway represents - you guessed it - a gateway.
 type Gateway struct {
-	GatewayEUI protocol.EUI // EUI of gateway.
-	IP         net.IP       // IP address of gateway. This might not be fixed.
-	StrictIP   bool         // Strict IP address check
-	Latitude   float32      // Latitude, in decimal degrees, positive N <-90-90>
-	Longitude  float32      // Longitude, in decimal degrees, positive E [-180-180>
-	Altitude   float32      // Altitude, meters
+	GatewayEUI protocol.EUI           // EUI of gateway.
+	IP         net.IP                 // IP address of gateway. This might not be fixed.
+	StrictIP   bool                   // Strict IP address check
+	Latitude   float32                // Latitude, in decimal degrees, positive N <-90-90>
+	Longitude  float32                // Longitude, in decimal degrees, positive E [-180-180>
+	Altitude   float32                // Altitude, meters
+	LastSeen   int64                  // Time of last PULL_DATA or PUSH_DATA from the gateway (in nanoseconds)
+	Stats      GatewayStats           // Latest status report from the gateway
+	Plan       band.FrequencyBandType // Frequency plan used by the gateway
+	Channels   ConcentratorConfig     // Concentrator channel configuration. Empty for the plan's default
+}
+
+// GatewayStats is the status report (the stat object) sent by the gateway
+type GatewayStats struct {
+	Time         string  // Gateway system time when the report was made
+	Latitude     float32 // GPS latitude, in decimal degrees
+	Longitude    float32 // GPS longitude, in decimal degrees
+	Altitude     float32 // GPS altitude, meters
+	RxReceived   uint32  // Number of radio packets received (rxnb)
+	RxOK         uint32  // Number of radio packets received with a valid CRC (rxok)
+	RxForwarded  uint32  // Number of radio packets forwarded (rxfw)
+	AckRatio     float32 // Percentage of upstream datagrams that were acknowledged (ackr)
+	DownReceived uint32  // Number of downlink datagrams received (dwnb)
+	TxEmitted    uint32  // Number of packets emitted (txnb)
+	Updated      int64   // Time the report was received (in nanoseconds)
 }
 
 // NewGateway creates a new gateway
@@ -21,6 +42,24 @@ func NewGateway() Gateway {
 	return Gateway{}
 }
 
+// Online returns true if the gateway has been seen within the timeout
+func (g *Gateway) Online(timeout time.Duration) bool {
+	if g.LastSeen == 0 {
+		return false
+	}
+	return time.Since(time.Unix(0, g.LastSeen)) < timeout
+}
+
+// ChannelConfig returns the concentrator channel configuration for the
+// gateway. Gateways without a channel configuration use the default
+// configuration for the frequency plan.
+func (g *Gateway) ChannelConfig() ConcentratorConfig {
+	if g.Channels.Empty() {
+		return DefaultConcentratorConfig(g.Plan)
+	}
+	return g.Channels
+}
+
 // Equals checks gateways for equality
 func (g *Gateway) Equals(other Gateway) bool {
 	return g.Altitude == other.Altitude &&
@@ -28,5 +67,7 @@ func (g *Gateway) Equals(other Gateway) bool {
 		g.IP.Equal(other.IP) &&
 		g.Latitude == other.Latitude &&
 		g.Longitude == other.Longitude &&
-		g.StrictIP == other.StrictIP
+		g.StrictIP == other.StrictIP &&
+		g.Plan == other.Plan &&
+		g.Channels.Equals(other.Channels)
 }
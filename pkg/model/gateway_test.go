@@ -3,6 +3,7 @@ package model
 import (
 	"net"
 	"testing"
+	"time"
 
 	"github.com/lab5e/lospan/pkg/protocol"
 )
@@ -28,3 +29,17 @@ func TestGatewayCompare(t *testing.T) {
 	}
 
 }
+
+func TestGatewayOnline(t *testing.T) {
+	gw := NewGateway()
+	if gw.Online(time.Minute) {
+		t.Fatal("Gateway that hasn't been seen should be offline")
+	}
+	gw.LastSeen = time.Now().Add(-10 * time.Second).UnixNano()
+	if !gw.Online(time.Minute) {
+		t.Fatal("Gateway should be online")
+	}
+	if gw.Online(5 * time.Second) {
+		t.Fatal("Gateway should be offline")
+	}
+}
@@ -2,21 +2,35 @@ package model
 
 import (
 	"bytes"
+	"reflect"
 
 	"github.com/lab5e/lospan/pkg/protocol"
 )
 
+// GatewayReception is the reception of an uplink frame by a single gateway
+type GatewayReception struct {
+	GatewayEUI    protocol.EUI // The gateway that received the frame
+	RSSI          int32        // Radio stats; RSSI
+	SNR           float32      // Radio; SNR
+	Channel       uint8        // Concentrator channel
+	RFChain       uint8        // Concentrator RF chain
+	Timestamp     uint32       // Concentrator timestamp (in microseconds)
+	FineTimestamp int64        // Fine timestamp (in nanoseconds). 0 if the gateway doesn't support it
+}
+
 // UpstreamMessage contains a single transmission from an end-device.
 type UpstreamMessage struct {
-	DeviceEUI  protocol.EUI     // Device address used
-	Timestamp  int64            // Timestamp for message. Data type might change.
-	Data       []byte           // The data the end-device sent
-	GatewayEUI protocol.EUI     // The gateway the message was received from.
-	RSSI       int32            // Radio stats; RSSI
-	SNR        float32          // Radio; SNR
-	Frequency  float32          // Radio; Frequency
-	DataRate   string           // Data rate (ie "SF7BW125" or similar)
-	DevAddr    protocol.DevAddr // The reported DevAddr (at the time)
+	DeviceEUI  protocol.EUI       // Device address used
+	Timestamp  int64              // Timestamp for message. Data type might change.
+	Data       []byte             // The data the end-device sent
+	GatewayEUI protocol.EUI       // The gateway the message was received from.
+	RSSI       int32              // Radio stats; RSSI
+	SNR        float32            // Radio; SNR
+	Frequency  float32            // Radio; Frequency
+	DataRate   string             // Data rate (ie "SF7BW125" or similar)
+	DevAddr    protocol.DevAddr   // The reported DevAddr (at the time)
+	FPort      uint8              // The port the message was sent to
+	Receptions []GatewayReception // All of the gateways that received the message
 }
 
 // Equals compares two DeviceData instances
@@ -29,6 +43,7 @@ func (d *UpstreamMessage) Equals(other UpstreamMessage) bool {
 		d.SNR == other.SNR &&
 		d.Frequency == other.Frequency &&
 		d.DataRate == other.DataRate &&
-		d.DevAddr == other.DevAddr
-
+		d.DevAddr == other.DevAddr &&
+		d.FPort == other.FPort &&
+		reflect.DeepEqual(d.Receptions, other.Receptions)
 }
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/lab5e/lospan/pkg/protocol"
+)
+
+// Webhook is a HTTP endpoint that receives the events for an application.
+// The events are POSTed as JSON.
+type Webhook struct {
+	ID      int64             // Identifier. Assigned by the storage layer
+	AppEUI  protocol.EUI      // The application
+	URL     string            // The URL the events are sent to
+	Headers map[string]string // Custom headers for the requests
+	Secret  string            // Secret for the HMAC-SHA256 signature. Requests aren't signed if blank
+}
+
+// Validate checks the webhook URL. Only absolute http and https URLs are
+// accepted.
+func (w *Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("webhook URL must be http or https")
+	}
+	if u.Host == "" {
+		return errors.New("webhook URL must have a host")
+	}
+	return nil
+}
+
+// WebhookDelivery is a single attempt to deliver an event to a webhook
+type WebhookDelivery struct {
+	WebhookID  int64  // The webhook
+	EventType  string // The event type (up, join, ack, txack)
+	Time       int64  // Time of attempt (in nanoseconds)
+	Attempt    int    // Attempt number, starting at 1
+	StatusCode int    // HTTP status code. 0 if there's no response
+	Error      string // Error message for failed attempts
+	Delivered  bool   // The event was delivered
+}
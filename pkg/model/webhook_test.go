@@ -0,0 +1,18 @@
+package model
+
+import "testing"
+
+func TestWebhookValidate(t *testing.T) {
+	for _, valid := range []string{"http://example.com/hook", "https://example.com:8443/"} {
+		w := Webhook{URL: valid}
+		if err := w.Validate(); err != nil {
+			t.Fatalf("%s should be valid: %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"", "example.com/hook", "ftp://example.com", "http:///hook", "http://%zz"} {
+		w := Webhook{URL: invalid}
+		if err := w.Validate(); err == nil {
+			t.Fatalf("%s should be invalid", invalid)
+		}
+	}
+}
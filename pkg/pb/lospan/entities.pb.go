@@ -9,6 +9,7 @@ package lospan
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -73,6 +74,159 @@ func (DeviceState) EnumDescriptor() ([]byte, []int) {
 	return file_lospan_entities_proto_rawDescGZIP(), []int{0}
 }
 
+// LoRaWAN device class
+type DeviceClass int32
+
+const (
+	DeviceClass_CLASS_A DeviceClass = 0
+	DeviceClass_CLASS_B DeviceClass = 1
+	DeviceClass_CLASS_C DeviceClass = 2
+)
+
+// Enum value maps for DeviceClass.
+var (
+	DeviceClass_name = map[int32]string{
+		0: "CLASS_A",
+		1: "CLASS_B",
+		2: "CLASS_C",
+	}
+	DeviceClass_value = map[string]int32{
+		"CLASS_A": 0,
+		"CLASS_B": 1,
+		"CLASS_C": 2,
+	}
+)
+
+func (x DeviceClass) Enum() *DeviceClass {
+	p := new(DeviceClass)
+	*p = x
+	return p
+}
+
+func (x DeviceClass) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeviceClass) Descriptor() protoreflect.EnumDescriptor {
+	return file_lospan_entities_proto_enumTypes[1].Descriptor()
+}
+
+func (DeviceClass) Type() protoreflect.EnumType {
+	return &file_lospan_entities_proto_enumTypes[1]
+}
+
+func (x DeviceClass) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeviceClass.Descriptor instead.
+func (DeviceClass) EnumDescriptor() ([]byte, []int) {
+	return file_lospan_entities_proto_rawDescGZIP(), []int{1}
+}
+
+// LoRaWAN MAC version implemented by the device
+type MACVersion int32
+
+const (
+	MACVersion_LORAWAN_1_0 MACVersion = 0
+	MACVersion_LORAWAN_1_1 MACVersion = 1
+)
+
+// Enum value maps for MACVersion.
+var (
+	MACVersion_name = map[int32]string{
+		0: "LORAWAN_1_0",
+		1: "LORAWAN_1_1",
+	}
+	MACVersion_value = map[string]int32{
+		"LORAWAN_1_0": 0,
+		"LORAWAN_1_1": 1,
+	}
+)
+
+func (x MACVersion) Enum() *MACVersion {
+	p := new(MACVersion)
+	*p = x
+	return p
+}
+
+func (x MACVersion) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MACVersion) Descriptor() protoreflect.EnumDescriptor {
+	return file_lospan_entities_proto_enumTypes[2].Descriptor()
+}
+
+func (MACVersion) Type() protoreflect.EnumType {
+	return &file_lospan_entities_proto_enumTypes[2]
+}
+
+func (x MACVersion) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MACVersion.Descriptor instead.
+func (MACVersion) EnumDescriptor() ([]byte, []int) {
+	return file_lospan_entities_proto_rawDescGZIP(), []int{2}
+}
+
+// Type of gateway event
+type GatewayEventType int32
+
+const (
+	GatewayEventType_UNKNOWN_EVENT GatewayEventType = 0
+	GatewayEventType_RX            GatewayEventType = 1 // Packets received by the gateway
+	GatewayEventType_TX            GatewayEventType = 2 // Packet sent to the gateway for transmission
+	GatewayEventType_KEEP_ALIVE    GatewayEventType = 3 // Keepalive from the gateway
+	GatewayEventType_INACTIVE      GatewayEventType = 4 // The gateway has gone inactive
+)
+
+// Enum value maps for GatewayEventType.
+var (
+	GatewayEventType_name = map[int32]string{
+		0: "UNKNOWN_EVENT",
+		1: "RX",
+		2: "TX",
+		3: "KEEP_ALIVE",
+		4: "INACTIVE",
+	}
+	GatewayEventType_value = map[string]int32{
+		"UNKNOWN_EVENT": 0,
+		"RX":            1,
+		"TX":            2,
+		"KEEP_ALIVE":    3,
+		"INACTIVE":      4,
+	}
+)
+
+func (x GatewayEventType) Enum() *GatewayEventType {
+	p := new(GatewayEventType)
+	*p = x
+	return p
+}
+
+func (x GatewayEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GatewayEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_lospan_entities_proto_enumTypes[3].Descriptor()
+}
+
+func (GatewayEventType) Type() protoreflect.EnumType {
+	return &file_lospan_entities_proto_enumTypes[3]
+}
+
+func (x GatewayEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GatewayEventType.Descriptor instead.
+func (GatewayEventType) EnumDescriptor() ([]byte, []int) {
+	return file_lospan_entities_proto_rawDescGZIP(), []int{3}
+}
+
 // Application is a logical construct on top of devices. Devices in the same application share the same
 // application key
 type Application struct {
@@ -80,8 +234,10 @@ type Application struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Eui string  `protobuf:"bytes,1,opt,name=eui,proto3" json:"eui,omitempty"`
-	Tag *string `protobuf:"bytes,2,opt,name=tag,proto3,oneof" json:"tag,omitempty"`
+	Eui     string  `protobuf:"bytes,1,opt,name=eui,proto3" json:"eui,omitempty"`
+	Tag     *string `protobuf:"bytes,2,opt,name=tag,proto3,oneof" json:"tag,omitempty"`
+	Decoder *string `protobuf:"bytes,3,opt,name=decoder,proto3,oneof" json:"decoder,omitempty"` // Payload decoder. CEL expression with fPort and payload that returns a map
+	Encoder *string `protobuf:"bytes,4,opt,name=encoder,proto3,oneof" json:"encoder,omitempty"` // Payload encoder. CEL expression with fPort and object that returns bytes
 }
 
 func (x *Application) Reset() {
@@ -130,6 +286,20 @@ func (x *Application) GetTag() string {
 	return ""
 }
 
+func (x *Application) GetDecoder() string {
+	if x != nil && x.Decoder != nil {
+		return *x.Decoder
+	}
+	return ""
+}
+
+func (x *Application) GetEncoder() string {
+	if x != nil && x.Encoder != nil {
+		return *x.Encoder
+	}
+	return ""
+}
+
 // Device is the ... device that connects to the gateway. "Node" might be a better name since it's
 // part of the LoRaWAN implementation nomenclature.
 type Device struct {
@@ -144,12 +314,30 @@ type Device struct {
 	AppKey            []byte       `protobuf:"bytes,5,opt,name=app_key,json=appKey,proto3,oneof" json:"app_key,omitempty"`                                    // 16 bytes/256 bits
 	AppSessionKey     []byte       `protobuf:"bytes,6,opt,name=app_session_key,json=appSessionKey,proto3,oneof" json:"app_session_key,omitempty"`             // 16 bytes/256 bits
 	NetworkSessionKey []byte       `protobuf:"bytes,7,opt,name=network_session_key,json=networkSessionKey,proto3,oneof" json:"network_session_key,omitempty"` // 16 bytes/256 bits
-	FrameCountUp      *int32       `protobuf:"varint,8,opt,name=frame_count_up,json=frameCountUp,proto3,oneof" json:"frame_count_up,omitempty"`               // in reality uint16
-	FrameCountDown    *int32       `protobuf:"varint,9,opt,name=frame_count_down,json=frameCountDown,proto3,oneof" json:"frame_count_down,omitempty"`         // in reality uint16
+	FrameCountUp      *uint32      `protobuf:"varint,8,opt,name=frame_count_up,json=frameCountUp,proto3,oneof" json:"frame_count_up,omitempty"`               // 32-bit frame counter. Only the lower 16 bits are sent over the air
+	FrameCountDown    *uint32      `protobuf:"varint,9,opt,name=frame_count_down,json=frameCountDown,proto3,oneof" json:"frame_count_down,omitempty"`         // 32-bit frame counter. Only the lower 16 bits are sent over the air
 	RelaxedCounter    *bool        `protobuf:"varint,10,opt,name=relaxed_counter,json=relaxedCounter,proto3,oneof" json:"relaxed_counter,omitempty"`
 	KeyWarning        *bool        `protobuf:"varint,11,opt,name=key_warning,json=keyWarning,proto3,oneof" json:"key_warning,omitempty"` // Ignored on updates; set by service
 	Tag               *string      `protobuf:"bytes,12,opt,name=tag,proto3,oneof" json:"tag,omitempty"`
 	DevNonces         []int32      `protobuf:"varint,13,rep,packed,name=dev_nonces,json=devNonces,proto3" json:"dev_nonces,omitempty"` // in reality uint16
+	// MAC state negotiated with the device. These fields are ignored on updates; set by service
+	DataRate     *int32       `protobuf:"varint,14,opt,name=data_rate,json=dataRate,proto3,oneof" json:"data_rate,omitempty"`                                  // Current data rate
+	TxPower      *int32       `protobuf:"varint,15,opt,name=tx_power,json=txPower,proto3,oneof" json:"tx_power,omitempty"`                                     // Current TX power index
+	ChannelMask  *uint32      `protobuf:"varint,16,opt,name=channel_mask,json=channelMask,proto3,oneof" json:"channel_mask,omitempty"`                         // Enabled channels, in reality uint16
+	Rx1DrOffset  *int32       `protobuf:"varint,17,opt,name=rx1_dr_offset,json=rx1DrOffset,proto3,oneof" json:"rx1_dr_offset,omitempty"`                       // Data rate offset for RX1
+	Rx2DataRate  *int32       `protobuf:"varint,18,opt,name=rx2_data_rate,json=rx2DataRate,proto3,oneof" json:"rx2_data_rate,omitempty"`                       // Data rate for RX2
+	Rx2Frequency *float32     `protobuf:"fixed32,19,opt,name=rx2_frequency,json=rx2Frequency,proto3,oneof" json:"rx2_frequency,omitempty"`                     // Frequency for RX2 (in MHz). 0 = band default
+	Rx1Delay     *int32       `protobuf:"varint,20,opt,name=rx1_delay,json=rx1Delay,proto3,oneof" json:"rx1_delay,omitempty"`                                  // Delay before RX1 (in seconds). 0 = band default
+	MaxDutyCycle *int32       `protobuf:"varint,21,opt,name=max_duty_cycle,json=maxDutyCycle,proto3,oneof" json:"max_duty_cycle,omitempty"`                    // Max duty cycle as 1/2^max_duty_cycle. 0 = no limit
+	DeviceClass  *DeviceClass `protobuf:"varint,22,opt,name=device_class,json=deviceClass,proto3,enum=lospan.DeviceClass,oneof" json:"device_class,omitempty"` // Device class. Class A is the default
+	// Class B ping slot settings. These fields are ignored on updates; set by service
+	PingPeriodicity *int32   `protobuf:"varint,23,opt,name=ping_periodicity,json=pingPeriodicity,proto3,oneof" json:"ping_periodicity,omitempty"` // Ping slot every 2^(5+ping_periodicity) slots
+	PingDataRate    *int32   `protobuf:"varint,24,opt,name=ping_data_rate,json=pingDataRate,proto3,oneof" json:"ping_data_rate,omitempty"`        // Data rate for ping slots. Only used if ping_frequency is set
+	PingFrequency   *float32 `protobuf:"fixed32,25,opt,name=ping_frequency,json=pingFrequency,proto3,oneof" json:"ping_frequency,omitempty"`      // Frequency for ping slots (in MHz). 0 = band default
+	// LoRaWAN 1.1 settings. The network session keys are derived from the network key when the device joins
+	MacVersion *MACVersion `protobuf:"varint,26,opt,name=mac_version,json=macVersion,proto3,enum=lospan.MACVersion,oneof" json:"mac_version,omitempty"` // MAC version. LoRaWAN 1.0 is the default
+	NetworkKey []byte      `protobuf:"bytes,27,opt,name=network_key,json=networkKey,proto3,oneof" json:"network_key,omitempty"`                         // 16 bytes/256 bits
+	ProfileId  *int64      `protobuf:"varint,28,opt,name=profile_id,json=profileId,proto3,oneof" json:"profile_id,omitempty"`                           // Device profile. The profile settings are applied to the device unless device_class or mac_version are set in the request. 0 = no profile
 }
 
 func (x *Device) Reset() {
@@ -233,14 +421,14 @@ func (x *Device) GetNetworkSessionKey() []byte {
 	return nil
 }
 
-func (x *Device) GetFrameCountUp() int32 {
+func (x *Device) GetFrameCountUp() uint32 {
 	if x != nil && x.FrameCountUp != nil {
 		return *x.FrameCountUp
 	}
 	return 0
 }
 
-func (x *Device) GetFrameCountDown() int32 {
+func (x *Device) GetFrameCountDown() uint32 {
 	if x != nil && x.FrameCountDown != nil {
 		return *x.FrameCountDown
 	}
@@ -275,25 +463,133 @@ func (x *Device) GetDevNonces() []int32 {
 	return nil
 }
 
-// UpstreamMessage is a message from one of the devices
-type UpstreamMessage struct {
+func (x *Device) GetDataRate() int32 {
+	if x != nil && x.DataRate != nil {
+		return *x.DataRate
+	}
+	return 0
+}
+
+func (x *Device) GetTxPower() int32 {
+	if x != nil && x.TxPower != nil {
+		return *x.TxPower
+	}
+	return 0
+}
+
+func (x *Device) GetChannelMask() uint32 {
+	if x != nil && x.ChannelMask != nil {
+		return *x.ChannelMask
+	}
+	return 0
+}
+
+func (x *Device) GetRx1DrOffset() int32 {
+	if x != nil && x.Rx1DrOffset != nil {
+		return *x.Rx1DrOffset
+	}
+	return 0
+}
+
+func (x *Device) GetRx2DataRate() int32 {
+	if x != nil && x.Rx2DataRate != nil {
+		return *x.Rx2DataRate
+	}
+	return 0
+}
+
+func (x *Device) GetRx2Frequency() float32 {
+	if x != nil && x.Rx2Frequency != nil {
+		return *x.Rx2Frequency
+	}
+	return 0
+}
+
+func (x *Device) GetRx1Delay() int32 {
+	if x != nil && x.Rx1Delay != nil {
+		return *x.Rx1Delay
+	}
+	return 0
+}
+
+func (x *Device) GetMaxDutyCycle() int32 {
+	if x != nil && x.MaxDutyCycle != nil {
+		return *x.MaxDutyCycle
+	}
+	return 0
+}
+
+func (x *Device) GetDeviceClass() DeviceClass {
+	if x != nil && x.DeviceClass != nil {
+		return *x.DeviceClass
+	}
+	return DeviceClass_CLASS_A
+}
+
+func (x *Device) GetPingPeriodicity() int32 {
+	if x != nil && x.PingPeriodicity != nil {
+		return *x.PingPeriodicity
+	}
+	return 0
+}
+
+func (x *Device) GetPingDataRate() int32 {
+	if x != nil && x.PingDataRate != nil {
+		return *x.PingDataRate
+	}
+	return 0
+}
+
+func (x *Device) GetPingFrequency() float32 {
+	if x != nil && x.PingFrequency != nil {
+		return *x.PingFrequency
+	}
+	return 0
+}
+
+func (x *Device) GetMacVersion() MACVersion {
+	if x != nil && x.MacVersion != nil {
+		return *x.MacVersion
+	}
+	return MACVersion_LORAWAN_1_0
+}
+
+func (x *Device) GetNetworkKey() []byte {
+	if x != nil {
+		return x.NetworkKey
+	}
+	return nil
+}
+
+func (x *Device) GetProfileId() int64 {
+	if x != nil && x.ProfileId != nil {
+		return *x.ProfileId
+	}
+	return 0
+}
+
+// DeviceProfile holds the radio settings shared by a group of devices
+type DeviceProfile struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Eui        string  `protobuf:"bytes,1,opt,name=eui,proto3" json:"eui,omitempty"`
-	Timestamp  int64   `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Payload    []byte  `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
-	GatewayEui string  `protobuf:"bytes,4,opt,name=gateway_eui,json=gatewayEui,proto3" json:"gateway_eui,omitempty"`
-	Rssi       int32   `protobuf:"varint,5,opt,name=rssi,proto3" json:"rssi,omitempty"`
-	Snr        float32 `protobuf:"fixed32,6,opt,name=snr,proto3" json:"snr,omitempty"`
-	Frequency  float32 `protobuf:"fixed32,7,opt,name=frequency,proto3" json:"frequency,omitempty"`
-	DataRate   string  `protobuf:"bytes,8,opt,name=data_rate,json=dataRate,proto3" json:"data_rate,omitempty"`
-	DevAddr    uint32  `protobuf:"varint,9,opt,name=dev_addr,json=devAddr,proto3" json:"dev_addr,omitempty"`
-}
-
-func (x *UpstreamMessage) Reset() {
-	*x = UpstreamMessage{}
+	Id                *int64       `protobuf:"varint,1,opt,name=id,proto3,oneof" json:"id,omitempty"` // Ignored when creating profiles; set by service
+	Name              *string      `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	MacVersion        *MACVersion  `protobuf:"varint,3,opt,name=mac_version,json=macVersion,proto3,enum=lospan.MACVersion,oneof" json:"mac_version,omitempty"`     // MAC version. LoRaWAN 1.0 is the default
+	RegParamsRevision *string      `protobuf:"bytes,4,opt,name=reg_params_revision,json=regParamsRevision,proto3,oneof" json:"reg_params_revision,omitempty"`      // Regional parameters revision, ie "RP002-1.0.3". Informational only
+	DeviceClass       *DeviceClass `protobuf:"varint,5,opt,name=device_class,json=deviceClass,proto3,enum=lospan.DeviceClass,oneof" json:"device_class,omitempty"` // Device class. Class A is the default
+	SupportsJoin      *bool        `protobuf:"varint,6,opt,name=supports_join,json=supportsJoin,proto3,oneof" json:"supports_join,omitempty"`                      // The devices can join via OTAA. Default is true
+	Rx1Delay          *int32       `protobuf:"varint,7,opt,name=rx1_delay,json=rx1Delay,proto3,oneof" json:"rx1_delay,omitempty"`                                  // Delay before RX1 (in seconds). Default is 1
+	Rx1DrOffset       *int32       `protobuf:"varint,8,opt,name=rx1_dr_offset,json=rx1DrOffset,proto3,oneof" json:"rx1_dr_offset,omitempty"`                       // Data rate offset for RX1
+	Rx2DataRate       *int32       `protobuf:"varint,9,opt,name=rx2_data_rate,json=rx2DataRate,proto3,oneof" json:"rx2_data_rate,omitempty"`                       // Data rate for RX2
+	Rx2Frequency      *float32     `protobuf:"fixed32,10,opt,name=rx2_frequency,json=rx2Frequency,proto3,oneof" json:"rx2_frequency,omitempty"`                    // Frequency for RX2 (in MHz). 0 = band default
+	MaxEirp           *int32       `protobuf:"varint,11,opt,name=max_eirp,json=maxEirp,proto3,oneof" json:"max_eirp,omitempty"`                                    // Max EIRP (in dBm). Limits the TX power set by ADR. 0 = band default
+	AdrEnabled        *bool        `protobuf:"varint,12,opt,name=adr_enabled,json=adrEnabled,proto3,oneof" json:"adr_enabled,omitempty"`                           // Adaptive data rate. Default is true
+}
+
+func (x *DeviceProfile) Reset() {
+	*x = DeviceProfile{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_lospan_entities_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -301,13 +597,13 @@ func (x *UpstreamMessage) Reset() {
 	}
 }
 
-func (x *UpstreamMessage) String() string {
+func (x *DeviceProfile) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpstreamMessage) ProtoMessage() {}
+func (*DeviceProfile) ProtoMessage() {}
 
-func (x *UpstreamMessage) ProtoReflect() protoreflect.Message {
+func (x *DeviceProfile) ProtoReflect() protoreflect.Message {
 	mi := &file_lospan_entities_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -319,91 +615,113 @@ func (x *UpstreamMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpstreamMessage.ProtoReflect.Descriptor instead.
-func (*UpstreamMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeviceProfile.ProtoReflect.Descriptor instead.
+func (*DeviceProfile) Descriptor() ([]byte, []int) {
 	return file_lospan_entities_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *UpstreamMessage) GetEui() string {
-	if x != nil {
-		return x.Eui
+func (x *DeviceProfile) GetId() int64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
 	}
-	return ""
+	return 0
 }
 
-func (x *UpstreamMessage) GetTimestamp() int64 {
-	if x != nil {
-		return x.Timestamp
+func (x *DeviceProfile) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *UpstreamMessage) GetPayload() []byte {
-	if x != nil {
-		return x.Payload
+func (x *DeviceProfile) GetMacVersion() MACVersion {
+	if x != nil && x.MacVersion != nil {
+		return *x.MacVersion
 	}
-	return nil
+	return MACVersion_LORAWAN_1_0
 }
 
-func (x *UpstreamMessage) GetGatewayEui() string {
-	if x != nil {
-		return x.GatewayEui
+func (x *DeviceProfile) GetRegParamsRevision() string {
+	if x != nil && x.RegParamsRevision != nil {
+		return *x.RegParamsRevision
 	}
 	return ""
 }
 
-func (x *UpstreamMessage) GetRssi() int32 {
-	if x != nil {
-		return x.Rssi
+func (x *DeviceProfile) GetDeviceClass() DeviceClass {
+	if x != nil && x.DeviceClass != nil {
+		return *x.DeviceClass
+	}
+	return DeviceClass_CLASS_A
+}
+
+func (x *DeviceProfile) GetSupportsJoin() bool {
+	if x != nil && x.SupportsJoin != nil {
+		return *x.SupportsJoin
+	}
+	return false
+}
+
+func (x *DeviceProfile) GetRx1Delay() int32 {
+	if x != nil && x.Rx1Delay != nil {
+		return *x.Rx1Delay
 	}
 	return 0
 }
 
-func (x *UpstreamMessage) GetSnr() float32 {
-	if x != nil {
-		return x.Snr
+func (x *DeviceProfile) GetRx1DrOffset() int32 {
+	if x != nil && x.Rx1DrOffset != nil {
+		return *x.Rx1DrOffset
 	}
 	return 0
 }
 
-func (x *UpstreamMessage) GetFrequency() float32 {
-	if x != nil {
-		return x.Frequency
+func (x *DeviceProfile) GetRx2DataRate() int32 {
+	if x != nil && x.Rx2DataRate != nil {
+		return *x.Rx2DataRate
 	}
 	return 0
 }
 
-func (x *UpstreamMessage) GetDataRate() string {
-	if x != nil {
-		return x.DataRate
+func (x *DeviceProfile) GetRx2Frequency() float32 {
+	if x != nil && x.Rx2Frequency != nil {
+		return *x.Rx2Frequency
 	}
-	return ""
+	return 0
 }
 
-func (x *UpstreamMessage) GetDevAddr() uint32 {
-	if x != nil {
-		return x.DevAddr
+func (x *DeviceProfile) GetMaxEirp() int32 {
+	if x != nil && x.MaxEirp != nil {
+		return *x.MaxEirp
 	}
 	return 0
 }
 
-// DownstreamMessage is a message that should be or is sent to one of the devices
-type DownstreamMessage struct {
+func (x *DeviceProfile) GetAdrEnabled() bool {
+	if x != nil && x.AdrEnabled != nil {
+		return *x.AdrEnabled
+	}
+	return false
+}
+
+// DeviceStats is the frame statistics for a device
+type DeviceStats struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Eui     string `protobuf:"bytes,1,opt,name=eui,proto3" json:"eui,omitempty"`
-	Payload []byte `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
-	Port    int32  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
-	Ack     bool   `protobuf:"varint,4,opt,name=ack,proto3" json:"ack,omitempty"`
-	Created *int64 `protobuf:"varint,5,opt,name=created,proto3,oneof" json:"created,omitempty"`
-	Sent    *int64 `protobuf:"varint,6,opt,name=sent,proto3,oneof" json:"sent,omitempty"`
-	AckTime *int64 `protobuf:"varint,7,opt,name=ack_time,json=ackTime,proto3,oneof" json:"ack_time,omitempty"`
+	Eui         string  `protobuf:"bytes,1,opt,name=eui,proto3" json:"eui,omitempty"`
+	Received    uint32  `protobuf:"varint,2,opt,name=received,proto3" json:"received,omitempty"`                          // Frames accepted from the device
+	Lost        uint32  `protobuf:"varint,3,opt,name=lost,proto3" json:"lost,omitempty"`                                  // Frames lost, ie gaps in the frame counter
+	Replayed    uint32  `protobuf:"varint,4,opt,name=replayed,proto3" json:"replayed,omitempty"`                          // Frames rejected because the frame counter was too low
+	MicFailed   uint32  `protobuf:"varint,5,opt,name=mic_failed,json=micFailed,proto3" json:"mic_failed,omitempty"`       // Frames with an invalid MIC
+	LastUpdated int64   `protobuf:"varint,6,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"` // Time of last update (in nanoseconds)
+	FrameLoss   float32 `protobuf:"fixed32,7,opt,name=frame_loss,json=frameLoss,proto3" json:"frame_loss,omitempty"`      // Fraction of frames lost
+	Rejected    uint32  `protobuf:"varint,8,opt,name=rejected,proto3" json:"rejected,omitempty"`                          // Frames rejected because the frame counter gap was too big
 }
 
-func (x *DownstreamMessage) Reset() {
-	*x = DownstreamMessage{}
+func (x *DeviceStats) Reset() {
+	*x = DeviceStats{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_lospan_entities_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -411,13 +729,13 @@ func (x *DownstreamMessage) Reset() {
 	}
 }
 
-func (x *DownstreamMessage) String() string {
+func (x *DeviceStats) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DownstreamMessage) ProtoMessage() {}
+func (*DeviceStats) ProtoMessage() {}
 
-func (x *DownstreamMessage) ProtoReflect() protoreflect.Message {
+func (x *DeviceStats) ProtoReflect() protoreflect.Message {
 	mi := &file_lospan_entities_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -429,76 +747,84 @@ func (x *DownstreamMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DownstreamMessage.ProtoReflect.Descriptor instead.
-func (*DownstreamMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeviceStats.ProtoReflect.Descriptor instead.
+func (*DeviceStats) Descriptor() ([]byte, []int) {
 	return file_lospan_entities_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *DownstreamMessage) GetEui() string {
+func (x *DeviceStats) GetEui() string {
 	if x != nil {
 		return x.Eui
 	}
 	return ""
 }
 
-func (x *DownstreamMessage) GetPayload() []byte {
+func (x *DeviceStats) GetReceived() uint32 {
 	if x != nil {
-		return x.Payload
+		return x.Received
 	}
-	return nil
+	return 0
 }
 
-func (x *DownstreamMessage) GetPort() int32 {
+func (x *DeviceStats) GetLost() uint32 {
 	if x != nil {
-		return x.Port
+		return x.Lost
 	}
 	return 0
 }
 
-func (x *DownstreamMessage) GetAck() bool {
+func (x *DeviceStats) GetReplayed() uint32 {
 	if x != nil {
-		return x.Ack
+		return x.Replayed
 	}
-	return false
+	return 0
 }
 
-func (x *DownstreamMessage) GetCreated() int64 {
-	if x != nil && x.Created != nil {
-		return *x.Created
+func (x *DeviceStats) GetMicFailed() uint32 {
+	if x != nil {
+		return x.MicFailed
 	}
 	return 0
 }
 
-func (x *DownstreamMessage) GetSent() int64 {
-	if x != nil && x.Sent != nil {
-		return *x.Sent
+func (x *DeviceStats) GetLastUpdated() int64 {
+	if x != nil {
+		return x.LastUpdated
 	}
 	return 0
 }
 
-func (x *DownstreamMessage) GetAckTime() int64 {
-	if x != nil && x.AckTime != nil {
-		return *x.AckTime
+func (x *DeviceStats) GetFrameLoss() float32 {
+	if x != nil {
+		return x.FrameLoss
 	}
 	return 0
 }
 
-// Gateway is a LoRaWAN gateway/concentrator.
-type Gateway struct {
+func (x *DeviceStats) GetRejected() uint32 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+// GatewayReception is the reception of an upstream message by a single gateway
+type GatewayReception struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Eui       string   `protobuf:"bytes,1,opt,name=eui,proto3" json:"eui,omitempty"`
-	Ip        *string  `protobuf:"bytes,2,opt,name=ip,proto3,oneof" json:"ip,omitempty"` // Strictly not optional but used when updating
-	StrictIp  *bool    `protobuf:"varint,3,opt,name=strict_ip,json=strictIp,proto3,oneof" json:"strict_ip,omitempty"`
-	Latitude  *float32 `protobuf:"fixed32,4,opt,name=latitude,proto3,oneof" json:"latitude,omitempty"`
-	Longitude *float32 `protobuf:"fixed32,5,opt,name=longitude,proto3,oneof" json:"longitude,omitempty"`
-	Altitude  *float32 `protobuf:"fixed32,6,opt,name=altitude,proto3,oneof" json:"altitude,omitempty"`
+	GatewayEui    string  `protobuf:"bytes,1,opt,name=gateway_eui,json=gatewayEui,proto3" json:"gateway_eui,omitempty"`
+	Rssi          int32   `protobuf:"varint,2,opt,name=rssi,proto3" json:"rssi,omitempty"`
+	Snr           float32 `protobuf:"fixed32,3,opt,name=snr,proto3" json:"snr,omitempty"`
+	Channel       int32   `protobuf:"varint,4,opt,name=channel,proto3" json:"channel,omitempty"`
+	RfChain       int32   `protobuf:"varint,5,opt,name=rf_chain,json=rfChain,proto3" json:"rf_chain,omitempty"`
+	Timestamp     uint32  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                    // Concentrator timestamp (in microseconds)
+	FineTimestamp *int64  `protobuf:"varint,7,opt,name=fine_timestamp,json=fineTimestamp,proto3,oneof" json:"fine_timestamp,omitempty"` // Fine timestamp (in nanoseconds) if the gateway supports it
 }
 
-func (x *Gateway) Reset() {
-	*x = Gateway{}
+func (x *GatewayReception) Reset() {
+	*x = GatewayReception{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_lospan_entities_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -506,13 +832,13 @@ func (x *Gateway) Reset() {
 	}
 }
 
-func (x *Gateway) String() string {
+func (x *GatewayReception) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Gateway) ProtoMessage() {}
+func (*GatewayReception) ProtoMessage() {}
 
-func (x *Gateway) ProtoReflect() protoreflect.Message {
+func (x *GatewayReception) ProtoReflect() protoreflect.Message {
 	mi := &file_lospan_entities_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -524,78 +850,1297 @@ func (x *Gateway) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Gateway.ProtoReflect.Descriptor instead.
-func (*Gateway) Descriptor() ([]byte, []int) {
+// Deprecated: Use GatewayReception.ProtoReflect.Descriptor instead.
+func (*GatewayReception) Descriptor() ([]byte, []int) {
 	return file_lospan_entities_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *Gateway) GetEui() string {
+func (x *GatewayReception) GetGatewayEui() string {
 	if x != nil {
-		return x.Eui
+		return x.GatewayEui
 	}
 	return ""
 }
 
-func (x *Gateway) GetIp() string {
-	if x != nil && x.Ip != nil {
-		return *x.Ip
+func (x *GatewayReception) GetRssi() int32 {
+	if x != nil {
+		return x.Rssi
 	}
-	return ""
+	return 0
+}
+
+func (x *GatewayReception) GetSnr() float32 {
+	if x != nil {
+		return x.Snr
+	}
+	return 0
+}
+
+func (x *GatewayReception) GetChannel() int32 {
+	if x != nil {
+		return x.Channel
+	}
+	return 0
+}
+
+func (x *GatewayReception) GetRfChain() int32 {
+	if x != nil {
+		return x.RfChain
+	}
+	return 0
+}
+
+func (x *GatewayReception) GetTimestamp() uint32 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *GatewayReception) GetFineTimestamp() int64 {
+	if x != nil && x.FineTimestamp != nil {
+		return *x.FineTimestamp
+	}
+	return 0
+}
+
+// UpstreamMessage is a message from one of the devices
+type UpstreamMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Eui            string              `protobuf:"bytes,1,opt,name=eui,proto3" json:"eui,omitempty"`
+	Timestamp      int64               `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Payload        []byte              `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	GatewayEui     string              `protobuf:"bytes,4,opt,name=gateway_eui,json=gatewayEui,proto3" json:"gateway_eui,omitempty"`
+	Rssi           int32               `protobuf:"varint,5,opt,name=rssi,proto3" json:"rssi,omitempty"`
+	Snr            float32             `protobuf:"fixed32,6,opt,name=snr,proto3" json:"snr,omitempty"`
+	Frequency      float32             `protobuf:"fixed32,7,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	DataRate       string              `protobuf:"bytes,8,opt,name=data_rate,json=dataRate,proto3" json:"data_rate,omitempty"`
+	DevAddr        uint32              `protobuf:"varint,9,opt,name=dev_addr,json=devAddr,proto3" json:"dev_addr,omitempty"`
+	Receptions     []*GatewayReception `protobuf:"bytes,10,rep,name=receptions,proto3" json:"receptions,omitempty"` // All of the gateways that received the message
+	Port           int32               `protobuf:"varint,11,opt,name=port,proto3" json:"port,omitempty"`
+	DecodedPayload *structpb.Struct    `protobuf:"bytes,12,opt,name=decoded_payload,json=decodedPayload,proto3" json:"decoded_payload,omitempty"` // The payload decoded by the application decoder
+	CodecError     *string             `protobuf:"bytes,13,opt,name=codec_error,json=codecError,proto3,oneof" json:"codec_error,omitempty"`       // Set if the decoder failed
+}
+
+func (x *UpstreamMessage) Reset() {
+	*x = UpstreamMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lospan_entities_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpstreamMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpstreamMessage) ProtoMessage() {}
+
+func (x *UpstreamMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_lospan_entities_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpstreamMessage.ProtoReflect.Descriptor instead.
+func (*UpstreamMessage) Descriptor() ([]byte, []int) {
+	return file_lospan_entities_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpstreamMessage) GetEui() string {
+	if x != nil {
+		return x.Eui
+	}
+	return ""
+}
+
+func (x *UpstreamMessage) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *UpstreamMessage) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *UpstreamMessage) GetGatewayEui() string {
+	if x != nil {
+		return x.GatewayEui
+	}
+	return ""
+}
+
+func (x *UpstreamMessage) GetRssi() int32 {
+	if x != nil {
+		return x.Rssi
+	}
+	return 0
+}
+
+func (x *UpstreamMessage) GetSnr() float32 {
+	if x != nil {
+		return x.Snr
+	}
+	return 0
+}
+
+func (x *UpstreamMessage) GetFrequency() float32 {
+	if x != nil {
+		return x.Frequency
+	}
+	return 0
+}
+
+func (x *UpstreamMessage) GetDataRate() string {
+	if x != nil {
+		return x.DataRate
+	}
+	return ""
+}
+
+func (x *UpstreamMessage) GetDevAddr() uint32 {
+	if x != nil {
+		return x.DevAddr
+	}
+	return 0
+}
+
+func (x *UpstreamMessage) GetReceptions() []*GatewayReception {
+	if x != nil {
+		return x.Receptions
+	}
+	return nil
+}
+
+func (x *UpstreamMessage) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *UpstreamMessage) GetDecodedPayload() *structpb.Struct {
+	if x != nil {
+		return x.DecodedPayload
+	}
+	return nil
+}
+
+func (x *UpstreamMessage) GetCodecError() string {
+	if x != nil && x.CodecError != nil {
+		return *x.CodecError
+	}
+	return ""
+}
+
+// DownstreamMessage is a message that should be or is sent to one of the devices
+type DownstreamMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Eui     string           `protobuf:"bytes,1,opt,name=eui,proto3" json:"eui,omitempty"`
+	Payload []byte           `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	Port    int32            `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	Ack     bool             `protobuf:"varint,4,opt,name=ack,proto3" json:"ack,omitempty"`
+	Created *int64           `protobuf:"varint,5,opt,name=created,proto3,oneof" json:"created,omitempty"`
+	Sent    *int64           `protobuf:"varint,6,opt,name=sent,proto3,oneof" json:"sent,omitempty"`
+	AckTime *int64           `protobuf:"varint,7,opt,name=ack_time,json=ackTime,proto3,oneof" json:"ack_time,omitempty"`
+	Object  *structpb.Struct `protobuf:"bytes,8,opt,name=object,proto3" json:"object,omitempty"` // Encoded by the application encoder if the payload is empty
+}
+
+func (x *DownstreamMessage) Reset() {
+	*x = DownstreamMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lospan_entities_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownstreamMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownstreamMessage) ProtoMessage() {}
+
+func (x *DownstreamMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_lospan_entities_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownstreamMessage.ProtoReflect.Descriptor instead.
+func (*DownstreamMessage) Descriptor() ([]byte, []int) {
+	return file_lospan_entities_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DownstreamMessage) GetEui() string {
+	if x != nil {
+		return x.Eui
+	}
+	return ""
+}
+
+func (x *DownstreamMessage) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *DownstreamMessage) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *DownstreamMessage) GetAck() bool {
+	if x != nil {
+		return x.Ack
+	}
+	return false
+}
+
+func (x *DownstreamMessage) GetCreated() int64 {
+	if x != nil && x.Created != nil {
+		return *x.Created
+	}
+	return 0
+}
+
+func (x *DownstreamMessage) GetSent() int64 {
+	if x != nil && x.Sent != nil {
+		return *x.Sent
+	}
+	return 0
+}
+
+func (x *DownstreamMessage) GetAckTime() int64 {
+	if x != nil && x.AckTime != nil {
+		return *x.AckTime
+	}
+	return 0
+}
+
+func (x *DownstreamMessage) GetObject() *structpb.Struct {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+// Gateway is a LoRaWAN gateway/concentrator.
+type Gateway struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Eui           string              `protobuf:"bytes,1,opt,name=eui,proto3" json:"eui,omitempty"`
+	Ip            *string             `protobuf:"bytes,2,opt,name=ip,proto3,oneof" json:"ip,omitempty"` // Strictly not optional but used when updating
+	StrictIp      *bool               `protobuf:"varint,3,opt,name=strict_ip,json=strictIp,proto3,oneof" json:"strict_ip,omitempty"`
+	Latitude      *float32            `protobuf:"fixed32,4,opt,name=latitude,proto3,oneof" json:"latitude,omitempty"`
+	Longitude     *float32            `protobuf:"fixed32,5,opt,name=longitude,proto3,oneof" json:"longitude,omitempty"`
+	Altitude      *float32            `protobuf:"fixed32,6,opt,name=altitude,proto3,oneof" json:"altitude,omitempty"`
+	LastSeen      *int64              `protobuf:"varint,7,opt,name=last_seen,json=lastSeen,proto3,oneof" json:"last_seen,omitempty"` // Last keepalive or packet from the gateway, ms since epoch
+	Online        *bool               `protobuf:"varint,8,opt,name=online,proto3,oneof" json:"online,omitempty"`                     // Gateway has been seen within the gateway timeout
+	Stats         *GatewayStats       `protobuf:"bytes,9,opt,name=stats,proto3,oneof" json:"stats,omitempty"`
+	FrequencyPlan *string             `protobuf:"bytes,10,opt,name=frequency_plan,json=frequencyPlan,proto3,oneof" json:"frequency_plan,omitempty"` // Frequency plan, ie EU868, US915 or AS923-1. EU868 is the default
+	Channels      *ConcentratorConfig `protobuf:"bytes,11,opt,name=channels,proto3,oneof" json:"channels,omitempty"`                                // Concentrator channels. Empty for the frequency plan's default
+	DutyCycle     []*SubBandDutyCycle `protobuf:"bytes,12,rep,name=duty_cycle,json=dutyCycle,proto3" json:"duty_cycle,omitempty"`                   // Downlink airtime in the frequency plan's sub-bands. Read only
+	MaxDwellTime  *int64              `protobuf:"varint,13,opt,name=max_dwell_time,json=maxDwellTime,proto3,oneof" json:"max_dwell_time,omitempty"` // Max airtime for a single downlink (in milliseconds). Read only
+}
+
+func (x *Gateway) Reset() {
+	*x = Gateway{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lospan_entities_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Gateway) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Gateway) ProtoMessage() {}
+
+func (x *Gateway) ProtoReflect() protoreflect.Message {
+	mi := &file_lospan_entities_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Gateway.ProtoReflect.Descriptor instead.
+func (*Gateway) Descriptor() ([]byte, []int) {
+	return file_lospan_entities_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Gateway) GetEui() string {
+	if x != nil {
+		return x.Eui
+	}
+	return ""
+}
+
+func (x *Gateway) GetIp() string {
+	if x != nil && x.Ip != nil {
+		return *x.Ip
+	}
+	return ""
+}
+
+func (x *Gateway) GetStrictIp() bool {
+	if x != nil && x.StrictIp != nil {
+		return *x.StrictIp
+	}
+	return false
+}
+
+func (x *Gateway) GetLatitude() float32 {
+	if x != nil && x.Latitude != nil {
+		return *x.Latitude
+	}
+	return 0
+}
+
+func (x *Gateway) GetLongitude() float32 {
+	if x != nil && x.Longitude != nil {
+		return *x.Longitude
+	}
+	return 0
+}
+
+func (x *Gateway) GetAltitude() float32 {
+	if x != nil && x.Altitude != nil {
+		return *x.Altitude
+	}
+	return 0
+}
+
+func (x *Gateway) GetLastSeen() int64 {
+	if x != nil && x.LastSeen != nil {
+		return *x.LastSeen
+	}
+	return 0
+}
+
+func (x *Gateway) GetOnline() bool {
+	if x != nil && x.Online != nil {
+		return *x.Online
+	}
+	return false
+}
+
+func (x *Gateway) GetStats() *GatewayStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *Gateway) GetFrequencyPlan() string {
+	if x != nil && x.FrequencyPlan != nil {
+		return *x.FrequencyPlan
+	}
+	return ""
+}
+
+func (x *Gateway) GetChannels() *ConcentratorConfig {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+func (x *Gateway) GetDutyCycle() []*SubBandDutyCycle {
+	if x != nil {
+		return x.DutyCycle
+	}
+	return nil
+}
+
+func (x *Gateway) GetMaxDwellTime() int64 {
+	if x != nil && x.MaxDwellTime != nil {
+		return *x.MaxDwellTime
+	}
+	return 0
+}
+
+// Downlink airtime for a gateway in one of the regulatory sub-bands. The airtime
+// is counted over a one hour observation window.
+type SubBandDutyCycle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MinFrequency float32 `protobuf:"fixed32,1,opt,name=min_frequency,json=minFrequency,proto3" json:"min_frequency,omitempty"` // Lowest frequency in the sub-band (in MHz)
+	MaxFrequency float32 `protobuf:"fixed32,2,opt,name=max_frequency,json=maxFrequency,proto3" json:"max_frequency,omitempty"` // Highest frequency in the sub-band (in MHz)
+	DutyCycle    float32 `protobuf:"fixed32,3,opt,name=duty_cycle,json=dutyCycle,proto3" json:"duty_cycle,omitempty"`          // Max duty cycle, ie 0.01 for 1%
+	Used         int64   `protobuf:"varint,4,opt,name=used,proto3" json:"used,omitempty"`                                      // Airtime used (in milliseconds)
+	Remaining    int64   `protobuf:"varint,5,opt,name=remaining,proto3" json:"remaining,omitempty"`                            // Airtime left (in milliseconds)
+}
+
+func (x *SubBandDutyCycle) Reset() {
+	*x = SubBandDutyCycle{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lospan_entities_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubBandDutyCycle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubBandDutyCycle) ProtoMessage() {}
+
+func (x *SubBandDutyCycle) ProtoReflect() protoreflect.Message {
+	mi := &file_lospan_entities_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubBandDutyCycle.ProtoReflect.Descriptor instead.
+func (*SubBandDutyCycle) Descriptor() ([]byte, []int) {
+	return file_lospan_entities_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SubBandDutyCycle) GetMinFrequency() float32 {
+	if x != nil {
+		return x.MinFrequency
+	}
+	return 0
+}
+
+func (x *SubBandDutyCycle) GetMaxFrequency() float32 {
+	if x != nil {
+		return x.MaxFrequency
+	}
+	return 0
+}
+
+func (x *SubBandDutyCycle) GetDutyCycle() float32 {
+	if x != nil {
+		return x.DutyCycle
+	}
+	return 0
+}
+
+func (x *SubBandDutyCycle) GetUsed() int64 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *SubBandDutyCycle) GetRemaining() int64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+// Channel in the gateway's concentrator. The frequency is the IF offset
+// relative to the radio's centre frequency.
+type ConcentratorChannel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Enabled      bool   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Radio        uint32 `protobuf:"varint,2,opt,name=radio,proto3" json:"radio,omitempty"`                                   // RF chain
+	IfOffset     int32  `protobuf:"varint,3,opt,name=if_offset,json=ifOffset,proto3" json:"if_offset,omitempty"`             // IF offset (in Hz)
+	Bandwidth    uint32 `protobuf:"varint,4,opt,name=bandwidth,proto3" json:"bandwidth,omitempty"`                           // Bandwidth (in Hz). LoRa std and FSK channels only
+	SpreadFactor uint32 `protobuf:"varint,5,opt,name=spread_factor,json=spreadFactor,proto3" json:"spread_factor,omitempty"` // Spread factor. LoRa std channel only
+	DataRate     uint32 `protobuf:"varint,6,opt,name=data_rate,json=dataRate,proto3" json:"data_rate,omitempty"`             // Bit rate. FSK channel only
+}
+
+func (x *ConcentratorChannel) Reset() {
+	*x = ConcentratorChannel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lospan_entities_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConcentratorChannel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConcentratorChannel) ProtoMessage() {}
+
+func (x *ConcentratorChannel) ProtoReflect() protoreflect.Message {
+	mi := &file_lospan_entities_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConcentratorChannel.ProtoReflect.Descriptor instead.
+func (*ConcentratorChannel) Descriptor() ([]byte, []int) {
+	return file_lospan_entities_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ConcentratorChannel) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ConcentratorChannel) GetRadio() uint32 {
+	if x != nil {
+		return x.Radio
+	}
+	return 0
+}
+
+func (x *ConcentratorChannel) GetIfOffset() int32 {
+	if x != nil {
+		return x.IfOffset
+	}
+	return 0
+}
+
+func (x *ConcentratorChannel) GetBandwidth() uint32 {
+	if x != nil {
+		return x.Bandwidth
+	}
+	return 0
+}
+
+func (x *ConcentratorChannel) GetSpreadFactor() uint32 {
+	if x != nil {
+		return x.SpreadFactor
+	}
+	return 0
+}
+
+func (x *ConcentratorChannel) GetDataRate() uint32 {
+	if x != nil {
+		return x.DataRate
+	}
+	return 0
+}
+
+// Concentrator channel configuration for a gateway. The multi-SF channels are
+// channel 0-7 as reported by the gateway.
+type ConcentratorConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Radios  []float32              `protobuf:"fixed32,1,rep,packed,name=radios,proto3" json:"radios,omitempty"` // Centre frequency for each RF chain (in MHz)
+	MultiSf []*ConcentratorChannel `protobuf:"bytes,2,rep,name=multi_sf,json=multiSf,proto3" json:"multi_sf,omitempty"`
+	LoraStd *ConcentratorChannel   `protobuf:"bytes,3,opt,name=lora_std,json=loraStd,proto3" json:"lora_std,omitempty"`
+	Fsk     *ConcentratorChannel   `protobuf:"bytes,4,opt,name=fsk,proto3" json:"fsk,omitempty"`
+}
+
+func (x *ConcentratorConfig) Reset() {
+	*x = ConcentratorConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lospan_entities_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConcentratorConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConcentratorConfig) ProtoMessage() {}
+
+func (x *ConcentratorConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_lospan_entities_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConcentratorConfig.ProtoReflect.Descriptor instead.
+func (*ConcentratorConfig) Descriptor() ([]byte, []int) {
+	return file_lospan_entities_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ConcentratorConfig) GetRadios() []float32 {
+	if x != nil {
+		return x.Radios
+	}
+	return nil
+}
+
+func (x *ConcentratorConfig) GetMultiSf() []*ConcentratorChannel {
+	if x != nil {
+		return x.MultiSf
+	}
+	return nil
+}
+
+func (x *ConcentratorConfig) GetLoraStd() *ConcentratorChannel {
+	if x != nil {
+		return x.LoraStd
+	}
+	return nil
+}
+
+func (x *ConcentratorConfig) GetFsk() *ConcentratorChannel {
+	if x != nil {
+		return x.Fsk
+	}
+	return nil
+}
+
+// Latest status report from a gateway
+type GatewayStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time         string  `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`                                      // Gateway system time
+	Latitude     float32 `protobuf:"fixed32,2,opt,name=latitude,proto3" json:"latitude,omitempty"`                            // GPS latitude
+	Longitude    float32 `protobuf:"fixed32,3,opt,name=longitude,proto3" json:"longitude,omitempty"`                          // GPS longitude
+	Altitude     float32 `protobuf:"fixed32,4,opt,name=altitude,proto3" json:"altitude,omitempty"`                            // GPS altitude
+	RxReceived   uint32  `protobuf:"varint,5,opt,name=rx_received,json=rxReceived,proto3" json:"rx_received,omitempty"`       // Number of radio packets received
+	RxOk         uint32  `protobuf:"varint,6,opt,name=rx_ok,json=rxOk,proto3" json:"rx_ok,omitempty"`                         // Number of radio packets with a valid CRC
+	RxForwarded  uint32  `protobuf:"varint,7,opt,name=rx_forwarded,json=rxForwarded,proto3" json:"rx_forwarded,omitempty"`    // Number of radio packets forwarded
+	AckRatio     float32 `protobuf:"fixed32,8,opt,name=ack_ratio,json=ackRatio,proto3" json:"ack_ratio,omitempty"`            // Percentage of upstream datagrams acknowledged
+	DownReceived uint32  `protobuf:"varint,9,opt,name=down_received,json=downReceived,proto3" json:"down_received,omitempty"` // Number of downlink datagrams received
+	TxEmitted    uint32  `protobuf:"varint,10,opt,name=tx_emitted,json=txEmitted,proto3" json:"tx_emitted,omitempty"`         // Number of packets emitted
+	Updated      int64   `protobuf:"varint,11,opt,name=updated,proto3" json:"updated,omitempty"`                              // Time of report, ms since epoch
+}
+
+func (x *GatewayStats) Reset() {
+	*x = GatewayStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lospan_entities_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GatewayStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GatewayStats) ProtoMessage() {}
+
+func (x *GatewayStats) ProtoReflect() protoreflect.Message {
+	mi := &file_lospan_entities_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GatewayStats.ProtoReflect.Descriptor instead.
+func (*GatewayStats) Descriptor() ([]byte, []int) {
+	return file_lospan_entities_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GatewayStats) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *GatewayStats) GetLatitude() float32 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *GatewayStats) GetLongitude() float32 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *GatewayStats) GetAltitude() float32 {
+	if x != nil {
+		return x.Altitude
+	}
+	return 0
+}
+
+func (x *GatewayStats) GetRxReceived() uint32 {
+	if x != nil {
+		return x.RxReceived
+	}
+	return 0
+}
+
+func (x *GatewayStats) GetRxOk() uint32 {
+	if x != nil {
+		return x.RxOk
+	}
+	return 0
+}
+
+func (x *GatewayStats) GetRxForwarded() uint32 {
+	if x != nil {
+		return x.RxForwarded
+	}
+	return 0
+}
+
+func (x *GatewayStats) GetAckRatio() float32 {
+	if x != nil {
+		return x.AckRatio
+	}
+	return 0
+}
+
+func (x *GatewayStats) GetDownReceived() uint32 {
+	if x != nil {
+		return x.DownReceived
+	}
+	return 0
+}
+
+func (x *GatewayStats) GetTxEmitted() uint32 {
+	if x != nil {
+		return x.TxEmitted
+	}
+	return 0
+}
+
+func (x *GatewayStats) GetUpdated() int64 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+// GatewayRxPacket is a packet received by the gateway (rxpk in the Semtech protocol)
+type GatewayRxPacket struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time       string  `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`             // Time of reception (if the gateway has GPS)
+	Timestamp  uint32  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`  // Concentrator timestamp (in microseconds)
+	Frequency  float32 `protobuf:"fixed32,3,opt,name=frequency,proto3" json:"frequency,omitempty"` // Frequency (in MHz)
+	Channel    int32   `protobuf:"varint,4,opt,name=channel,proto3" json:"channel,omitempty"`
+	RfChain    int32   `protobuf:"varint,5,opt,name=rf_chain,json=rfChain,proto3" json:"rf_chain,omitempty"`
+	Modulation string  `protobuf:"bytes,6,opt,name=modulation,proto3" json:"modulation,omitempty"`
+	DataRate   string  `protobuf:"bytes,7,opt,name=data_rate,json=dataRate,proto3" json:"data_rate,omitempty"`
+	CodingRate string  `protobuf:"bytes,8,opt,name=coding_rate,json=codingRate,proto3" json:"coding_rate,omitempty"`
+	Rssi       int32   `protobuf:"varint,9,opt,name=rssi,proto3" json:"rssi,omitempty"`
+	Snr        float32 `protobuf:"fixed32,10,opt,name=snr,proto3" json:"snr,omitempty"`
+	Size       uint32  `protobuf:"varint,11,opt,name=size,proto3" json:"size,omitempty"`
+	Payload    []byte  `protobuf:"bytes,12,opt,name=payload,proto3" json:"payload,omitempty"`
+}
+
+func (x *GatewayRxPacket) Reset() {
+	*x = GatewayRxPacket{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lospan_entities_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GatewayRxPacket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GatewayRxPacket) ProtoMessage() {}
+
+func (x *GatewayRxPacket) ProtoReflect() protoreflect.Message {
+	mi := &file_lospan_entities_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GatewayRxPacket.ProtoReflect.Descriptor instead.
+func (*GatewayRxPacket) Descriptor() ([]byte, []int) {
+	return file_lospan_entities_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GatewayRxPacket) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *GatewayRxPacket) GetTimestamp() uint32 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *GatewayRxPacket) GetFrequency() float32 {
+	if x != nil {
+		return x.Frequency
+	}
+	return 0
+}
+
+func (x *GatewayRxPacket) GetChannel() int32 {
+	if x != nil {
+		return x.Channel
+	}
+	return 0
+}
+
+func (x *GatewayRxPacket) GetRfChain() int32 {
+	if x != nil {
+		return x.RfChain
+	}
+	return 0
+}
+
+func (x *GatewayRxPacket) GetModulation() string {
+	if x != nil {
+		return x.Modulation
+	}
+	return ""
+}
+
+func (x *GatewayRxPacket) GetDataRate() string {
+	if x != nil {
+		return x.DataRate
+	}
+	return ""
+}
+
+func (x *GatewayRxPacket) GetCodingRate() string {
+	if x != nil {
+		return x.CodingRate
+	}
+	return ""
+}
+
+func (x *GatewayRxPacket) GetRssi() int32 {
+	if x != nil {
+		return x.Rssi
+	}
+	return 0
+}
+
+func (x *GatewayRxPacket) GetSnr() float32 {
+	if x != nil {
+		return x.Snr
+	}
+	return 0
+}
+
+func (x *GatewayRxPacket) GetSize() uint32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *GatewayRxPacket) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+// GatewayTxPacket is a packet sent to the gateway (txpk in the Semtech protocol)
+type GatewayTxPacket struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Immediate        bool    `protobuf:"varint,1,opt,name=immediate,proto3" json:"immediate,omitempty"`            // Send immediately
+	Timestamp        uint32  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`            // Concentrator timestamp (in microseconds) for the transmission
+	GpsTime          uint64  `protobuf:"varint,3,opt,name=gps_time,json=gpsTime,proto3" json:"gps_time,omitempty"` // GPS time (in milliseconds) for the transmission
+	Frequency        float32 `protobuf:"fixed32,4,opt,name=frequency,proto3" json:"frequency,omitempty"`           // Frequency (in MHz)
+	RfChain          int32   `protobuf:"varint,5,opt,name=rf_chain,json=rfChain,proto3" json:"rf_chain,omitempty"`
+	Power            uint32  `protobuf:"varint,6,opt,name=power,proto3" json:"power,omitempty"` // TX power (in dBm)
+	Modulation       string  `protobuf:"bytes,7,opt,name=modulation,proto3" json:"modulation,omitempty"`
+	DataRate         string  `protobuf:"bytes,8,opt,name=data_rate,json=dataRate,proto3" json:"data_rate,omitempty"`
+	CodingRate       string  `protobuf:"bytes,9,opt,name=coding_rate,json=codingRate,proto3" json:"coding_rate,omitempty"`
+	InvertedPolarity bool    `protobuf:"varint,10,opt,name=inverted_polarity,json=invertedPolarity,proto3" json:"inverted_polarity,omitempty"`
+	Size             int32   `protobuf:"varint,11,opt,name=size,proto3" json:"size,omitempty"`
+	Payload          []byte  `protobuf:"bytes,12,opt,name=payload,proto3" json:"payload,omitempty"`
+}
+
+func (x *GatewayTxPacket) Reset() {
+	*x = GatewayTxPacket{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lospan_entities_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GatewayTxPacket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GatewayTxPacket) ProtoMessage() {}
+
+func (x *GatewayTxPacket) ProtoReflect() protoreflect.Message {
+	mi := &file_lospan_entities_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GatewayTxPacket.ProtoReflect.Descriptor instead.
+func (*GatewayTxPacket) Descriptor() ([]byte, []int) {
+	return file_lospan_entities_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GatewayTxPacket) GetImmediate() bool {
+	if x != nil {
+		return x.Immediate
+	}
+	return false
+}
+
+func (x *GatewayTxPacket) GetTimestamp() uint32 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *GatewayTxPacket) GetGpsTime() uint64 {
+	if x != nil {
+		return x.GpsTime
+	}
+	return 0
+}
+
+func (x *GatewayTxPacket) GetFrequency() float32 {
+	if x != nil {
+		return x.Frequency
+	}
+	return 0
+}
+
+func (x *GatewayTxPacket) GetRfChain() int32 {
+	if x != nil {
+		return x.RfChain
+	}
+	return 0
+}
+
+func (x *GatewayTxPacket) GetPower() uint32 {
+	if x != nil {
+		return x.Power
+	}
+	return 0
+}
+
+func (x *GatewayTxPacket) GetModulation() string {
+	if x != nil {
+		return x.Modulation
+	}
+	return ""
+}
+
+func (x *GatewayTxPacket) GetDataRate() string {
+	if x != nil {
+		return x.DataRate
+	}
+	return ""
+}
+
+func (x *GatewayTxPacket) GetCodingRate() string {
+	if x != nil {
+		return x.CodingRate
+	}
+	return ""
+}
+
+func (x *GatewayTxPacket) GetInvertedPolarity() bool {
+	if x != nil {
+		return x.InvertedPolarity
+	}
+	return false
+}
+
+func (x *GatewayTxPacket) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *GatewayTxPacket) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+// GatewayMessage is a monitoring message to and from the gateway. This reflects the LoRaWAN gateway UDP
+// protocol which again is more or less a 1:1 representation of the radio traffic with acks on top.
+type GatewayMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GatewayEui string             `protobuf:"bytes,1,opt,name=gateway_eui,json=gatewayEui,proto3" json:"gateway_eui,omitempty"`
+	Type       GatewayEventType   `protobuf:"varint,2,opt,name=type,proto3,enum=lospan.GatewayEventType" json:"type,omitempty"`
+	Timestamp  int64              `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Time of event (in milliseconds)
+	Json       string             `protobuf:"bytes,4,opt,name=json,proto3" json:"json,omitempty"`            // The raw JSON sent to or from the gateway (if applicable)
+	Rx         []*GatewayRxPacket `protobuf:"bytes,5,rep,name=rx,proto3" json:"rx,omitempty"`                // Received packets for RX events
+	Tx         *GatewayTxPacket   `protobuf:"bytes,6,opt,name=tx,proto3,oneof" json:"tx,omitempty"`          // Sent packet for TX events
+}
+
+func (x *GatewayMessage) Reset() {
+	*x = GatewayMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lospan_entities_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GatewayMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GatewayMessage) ProtoMessage() {}
+
+func (x *GatewayMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_lospan_entities_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (x *Gateway) GetStrictIp() bool {
-	if x != nil && x.StrictIp != nil {
-		return *x.StrictIp
+// Deprecated: Use GatewayMessage.ProtoReflect.Descriptor instead.
+func (*GatewayMessage) Descriptor() ([]byte, []int) {
+	return file_lospan_entities_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GatewayMessage) GetGatewayEui() string {
+	if x != nil {
+		return x.GatewayEui
 	}
-	return false
+	return ""
 }
 
-func (x *Gateway) GetLatitude() float32 {
-	if x != nil && x.Latitude != nil {
-		return *x.Latitude
+func (x *GatewayMessage) GetType() GatewayEventType {
+	if x != nil {
+		return x.Type
 	}
-	return 0
+	return GatewayEventType_UNKNOWN_EVENT
 }
 
-func (x *Gateway) GetLongitude() float32 {
-	if x != nil && x.Longitude != nil {
-		return *x.Longitude
+func (x *GatewayMessage) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
 	}
 	return 0
 }
 
-func (x *Gateway) GetAltitude() float32 {
-	if x != nil && x.Altitude != nil {
-		return *x.Altitude
+func (x *GatewayMessage) GetJson() string {
+	if x != nil {
+		return x.Json
+	}
+	return ""
+}
+
+func (x *GatewayMessage) GetRx() []*GatewayRxPacket {
+	if x != nil {
+		return x.Rx
+	}
+	return nil
+}
+
+func (x *GatewayMessage) GetTx() *GatewayTxPacket {
+	if x != nil {
+		return x.Tx
+	}
+	return nil
+}
+
+// Webhook is a HTTP endpoint that receives the events for an application. The events are
+// POSTed as JSON. The body is signed with HMAC-SHA256 if the secret is set.
+type Webhook struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id             int64             `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ApplicationEui string            `protobuf:"bytes,2,opt,name=application_eui,json=applicationEui,proto3" json:"application_eui,omitempty"`
+	Url            string            `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Headers        map[string]string `protobuf:"bytes,4,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // Custom headers for the requests
+	Secret         *string           `protobuf:"bytes,5,opt,name=secret,proto3,oneof" json:"secret,omitempty"`                                                                                     // Signing secret. This is never returned by the service
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lospan_entities_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_lospan_entities_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_lospan_entities_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Webhook) GetId() int64 {
+	if x != nil {
+		return x.Id
 	}
 	return 0
 }
 
-// GatewayMessage is a monitoring message to and from the gateway. This reflects the LoRaWAN gateway UDP
-// protocol which again is more or less a 1:1 representation of the radio traffic with acks on top.
-type GatewayMessage struct {
+func (x *Webhook) GetApplicationEui() string {
+	if x != nil {
+		return x.ApplicationEui
+	}
+	return ""
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *Webhook) GetSecret() string {
+	if x != nil && x.Secret != nil {
+		return *x.Secret
+	}
+	return ""
+}
+
+// WebhookDelivery is a single attempt to deliver an event to a webhook
+type WebhookDelivery struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	WebhookId  int64  `protobuf:"varint,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	EventType  string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`     // up, join, ack or txack
+	Time       int64  `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`                               // Time of attempt, ms since epoch
+	Attempt    int32  `protobuf:"varint,4,opt,name=attempt,proto3" json:"attempt,omitempty"`                         // Attempt number, starting at 1
+	StatusCode int32  `protobuf:"varint,5,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"` // HTTP status code. 0 if there's no response
+	Error      string `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`                              // Error message for failed attempts
+	Delivered  bool   `protobuf:"varint,7,opt,name=delivered,proto3" json:"delivered,omitempty"`                     // The event was delivered
 }
 
-func (x *GatewayMessage) Reset() {
-	*x = GatewayMessage{}
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lospan_entities_proto_msgTypes[5]
+		mi := &file_lospan_entities_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GatewayMessage) String() string {
+func (x *WebhookDelivery) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GatewayMessage) ProtoMessage() {}
+func (*WebhookDelivery) ProtoMessage() {}
 
-func (x *GatewayMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_lospan_entities_proto_msgTypes[5]
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_lospan_entities_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -606,53 +2151,149 @@ func (x *GatewayMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GatewayMessage.ProtoReflect.Descriptor instead.
-func (*GatewayMessage) Descriptor() ([]byte, []int) {
-	return file_lospan_entities_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_lospan_entities_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *WebhookDelivery) GetWebhookId() int64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetDelivered() bool {
+	if x != nil {
+		return x.Delivered
+	}
+	return false
 }
 
 var File_lospan_entities_proto protoreflect.FileDescriptor
 
 var file_lospan_entities_proto_rawDesc = []byte{
 	0x0a, 0x15, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2f, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65,
-	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x22,
-	0x3e, 0x0a, 0x0b, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10,
-	0x0a, 0x03, 0x65, 0x75, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x75, 0x69,
-	0x12, 0x15, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
-	0x03, 0x74, 0x61, 0x67, 0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x74, 0x61, 0x67, 0x22,
-	0xc0, 0x05, 0x0a, 0x06, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x15, 0x0a, 0x03, 0x65, 0x75,
-	0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x03, 0x65, 0x75, 0x69, 0x88, 0x01,
-	0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x5f, 0x65, 0x75, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0e, 0x61, 0x70,
-	0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x75, 0x69, 0x88, 0x01, 0x01, 0x12,
-	0x2e, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13,
-	0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x53, 0x74,
-	0x61, 0x74, 0x65, 0x48, 0x02, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12,
-	0x1e, 0x0a, 0x08, 0x64, 0x65, 0x76, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0d, 0x48, 0x03, 0x52, 0x07, 0x64, 0x65, 0x76, 0x41, 0x64, 0x64, 0x72, 0x88, 0x01, 0x01, 0x12,
-	0x1c, 0x0a, 0x07, 0x61, 0x70, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
-	0x48, 0x04, 0x52, 0x06, 0x61, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a,
-	0x0f, 0x61, 0x70, 0x70, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x6b, 0x65, 0x79,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x05, 0x52, 0x0d, 0x61, 0x70, 0x70, 0x53, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x4b, 0x65, 0x79, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a, 0x13, 0x6e, 0x65,
-	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x6b, 0x65,
-	0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x06, 0x52, 0x11, 0x6e, 0x65, 0x74, 0x77, 0x6f,
-	0x72, 0x6b, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4b, 0x65, 0x79, 0x88, 0x01, 0x01, 0x12,
-	0x29, 0x0a, 0x0e, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x75,
-	0x70, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x48, 0x07, 0x52, 0x0c, 0x66, 0x72, 0x61, 0x6d, 0x65,
-	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x55, 0x70, 0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a, 0x10, 0x66, 0x72,
-	0x61, 0x6d, 0x65, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x64, 0x6f, 0x77, 0x6e, 0x18, 0x09,
-	0x20, 0x01, 0x28, 0x05, 0x48, 0x08, 0x52, 0x0e, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x43, 0x6f, 0x75,
-	0x6e, 0x74, 0x44, 0x6f, 0x77, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x72, 0x65, 0x6c,
-	0x61, 0x78, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x0a, 0x20, 0x01,
-	0x28, 0x08, 0x48, 0x09, 0x52, 0x0e, 0x72, 0x65, 0x6c, 0x61, 0x78, 0x65, 0x64, 0x43, 0x6f, 0x75,
-	0x6e, 0x74, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x6b, 0x65, 0x79, 0x5f, 0x77,
-	0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x48, 0x0a, 0x52, 0x0a,
-	0x6b, 0x65, 0x79, 0x57, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a,
-	0x03, 0x74, 0x61, 0x67, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x48, 0x0b, 0x52, 0x03, 0x74, 0x61,
-	0x67, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x76, 0x5f, 0x6e, 0x6f, 0x6e, 0x63,
-	0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x05, 0x52, 0x09, 0x64, 0x65, 0x76, 0x4e, 0x6f, 0x6e,
-	0x63, 0x65, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x65, 0x75, 0x69, 0x42, 0x12, 0x0a, 0x10, 0x5f,
+	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x1a,
+	0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x94, 0x01,
+	0x0a, 0x0b, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a,
+	0x03, 0x65, 0x75, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x75, 0x69, 0x12,
+	0x15, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x03,
+	0x74, 0x61, 0x67, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x64, 0x65, 0x63, 0x6f, 0x64, 0x65,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x07, 0x64, 0x65, 0x63, 0x6f, 0x64,
+	0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x07, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65,
+	0x72, 0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x74, 0x61, 0x67, 0x42, 0x0a, 0x0a, 0x08,
+	0x5f, 0x64, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x65, 0x6e, 0x63,
+	0x6f, 0x64, 0x65, 0x72, 0x22, 0xb9, 0x0c, 0x0a, 0x06, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x15, 0x0a, 0x03, 0x65, 0x75, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x03,
+	0x65, 0x75, 0x69, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x65, 0x75, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x01, 0x52, 0x0e, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x75,
+	0x69, 0x88, 0x01, 0x01, 0x12, 0x2e, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x48, 0x02, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x08, 0x64, 0x65, 0x76, 0x5f, 0x61, 0x64, 0x64, 0x72,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x03, 0x52, 0x07, 0x64, 0x65, 0x76, 0x41, 0x64, 0x64,
+	0x72, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a, 0x07, 0x61, 0x70, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x04, 0x52, 0x06, 0x61, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x88,
+	0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0f, 0x61, 0x70, 0x70, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x05, 0x52, 0x0d, 0x61,
+	0x70, 0x70, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4b, 0x65, 0x79, 0x88, 0x01, 0x01, 0x12,
+	0x33, 0x0a, 0x13, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x06, 0x52, 0x11,
+	0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4b, 0x65,
+	0x79, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0e, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x5f, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x5f, 0x75, 0x70, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x07, 0x52, 0x0c,
+	0x66, 0x72, 0x61, 0x6d, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x55, 0x70, 0x88, 0x01, 0x01, 0x12,
+	0x2d, 0x0a, 0x10, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x64,
+	0x6f, 0x77, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x08, 0x52, 0x0e, 0x66, 0x72, 0x61,
+	0x6d, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x44, 0x6f, 0x77, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2c,
+	0x0a, 0x0f, 0x72, 0x65, 0x6c, 0x61, 0x78, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65,
+	0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x48, 0x09, 0x52, 0x0e, 0x72, 0x65, 0x6c, 0x61, 0x78,
+	0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b,
+	0x6b, 0x65, 0x79, 0x5f, 0x77, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x0a, 0x52, 0x0a, 0x6b, 0x65, 0x79, 0x57, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x88,
+	0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x0b, 0x52, 0x03, 0x74, 0x61, 0x67, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x76,
+	0x5f, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x05, 0x52, 0x09, 0x64,
+	0x65, 0x76, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x09, 0x64, 0x61, 0x74, 0x61,
+	0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x05, 0x48, 0x0c, 0x52, 0x08, 0x64,
+	0x61, 0x74, 0x61, 0x52, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x08, 0x74, 0x78,
+	0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x48, 0x0d, 0x52, 0x07,
+	0x74, 0x78, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x63, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0d,
+	0x48, 0x0e, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4d, 0x61, 0x73, 0x6b, 0x88,
+	0x01, 0x01, 0x12, 0x27, 0x0a, 0x0d, 0x72, 0x78, 0x31, 0x5f, 0x64, 0x72, 0x5f, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x05, 0x48, 0x0f, 0x52, 0x0b, 0x72, 0x78, 0x31,
+	0x44, 0x72, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0d, 0x72,
+	0x78, 0x32, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x12, 0x20, 0x01,
+	0x28, 0x05, 0x48, 0x10, 0x52, 0x0b, 0x72, 0x78, 0x32, 0x44, 0x61, 0x74, 0x61, 0x52, 0x61, 0x74,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x72, 0x78, 0x32, 0x5f, 0x66, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x13, 0x20, 0x01, 0x28, 0x02, 0x48, 0x11, 0x52, 0x0c, 0x72,
+	0x78, 0x32, 0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x88, 0x01, 0x01, 0x12, 0x20,
+	0x0a, 0x09, 0x72, 0x78, 0x31, 0x5f, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x14, 0x20, 0x01, 0x28,
+	0x05, 0x48, 0x12, 0x52, 0x08, 0x72, 0x78, 0x31, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x88, 0x01, 0x01,
+	0x12, 0x29, 0x0a, 0x0e, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x75, 0x74, 0x79, 0x5f, 0x63, 0x79, 0x63,
+	0x6c, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x05, 0x48, 0x13, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x44,
+	0x75, 0x74, 0x79, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x3b, 0x0a, 0x0c, 0x64,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x16, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x13, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x48, 0x14, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x43, 0x6c, 0x61, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x2e, 0x0a, 0x10, 0x70, 0x69, 0x6e, 0x67,
+	0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x69, 0x63, 0x69, 0x74, 0x79, 0x18, 0x17, 0x20, 0x01,
+	0x28, 0x05, 0x48, 0x15, 0x52, 0x0f, 0x70, 0x69, 0x6e, 0x67, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64,
+	0x69, 0x63, 0x69, 0x74, 0x79, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0e, 0x70, 0x69, 0x6e, 0x67,
+	0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28, 0x05,
+	0x48, 0x16, 0x52, 0x0c, 0x70, 0x69, 0x6e, 0x67, 0x44, 0x61, 0x74, 0x61, 0x52, 0x61, 0x74, 0x65,
+	0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x70, 0x69, 0x6e, 0x67, 0x5f, 0x66, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x19, 0x20, 0x01, 0x28, 0x02, 0x48, 0x17, 0x52, 0x0d, 0x70,
+	0x69, 0x6e, 0x67, 0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x88, 0x01, 0x01, 0x12,
+	0x38, 0x0a, 0x0b, 0x6d, 0x61, 0x63, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x1a,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x4d, 0x41,
+	0x43, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x18, 0x52, 0x0a, 0x6d, 0x61, 0x63, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x6e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x19,
+	0x52, 0x0a, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4b, 0x65, 0x79, 0x88, 0x01, 0x01, 0x12,
+	0x22, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x1c, 0x20,
+	0x01, 0x28, 0x03, 0x48, 0x1a, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x49, 0x64,
+	0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x65, 0x75, 0x69, 0x42, 0x12, 0x0a, 0x10, 0x5f,
 	0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x65, 0x75, 0x69, 0x42,
 	0x08, 0x0a, 0x06, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x64, 0x65,
 	0x76, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x61, 0x70, 0x70, 0x5f, 0x6b,
@@ -664,58 +2305,343 @@ var file_lospan_entities_proto_rawDesc = []byte{
 	0x74, 0x5f, 0x64, 0x6f, 0x77, 0x6e, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x72, 0x65, 0x6c, 0x61, 0x78,
 	0x65, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6b,
 	0x65, 0x79, 0x5f, 0x77, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x74,
-	0x61, 0x67, 0x22, 0xf8, 0x01, 0x0a, 0x0f, 0x55, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4d,
+	0x61, 0x67, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x72, 0x61, 0x74, 0x65,
+	0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x74, 0x78, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x42, 0x0f, 0x0a,
+	0x0d, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x42, 0x10,
+	0x0a, 0x0e, 0x5f, 0x72, 0x78, 0x31, 0x5f, 0x64, 0x72, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x72, 0x78, 0x32, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x72, 0x61,
+	0x74, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x72, 0x78, 0x32, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x79, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x72, 0x78, 0x31, 0x5f, 0x64, 0x65, 0x6c,
+	0x61, 0x79, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x75, 0x74, 0x79, 0x5f,
+	0x63, 0x79, 0x63, 0x6c, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x70, 0x69, 0x6e, 0x67, 0x5f,
+	0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x69, 0x63, 0x69, 0x74, 0x79, 0x42, 0x11, 0x0a, 0x0f, 0x5f,
+	0x70, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x42, 0x11,
+	0x0a, 0x0f, 0x5f, 0x70, 0x69, 0x6e, 0x67, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x79, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6d, 0x61, 0x63, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6b, 0x65,
+	0x79, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x69, 0x64,
+	0x22, 0xb3, 0x05, 0x0a, 0x0d, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x12, 0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00,
+	0x52, 0x02, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x38, 0x0a, 0x0b, 0x6d, 0x61, 0x63, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x4d,
+	0x41, 0x43, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x02, 0x52, 0x0a, 0x6d, 0x61, 0x63,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a, 0x13, 0x72, 0x65,
+	0x67, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x5f, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x11, 0x72, 0x65, 0x67, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x73, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12,
+	0x3b, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x48, 0x04, 0x52, 0x0b, 0x64, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d,
+	0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x5f, 0x6a, 0x6f, 0x69, 0x6e, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x08, 0x48, 0x05, 0x52, 0x0c, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x4a,
+	0x6f, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x72, 0x78, 0x31, 0x5f, 0x64, 0x65,
+	0x6c, 0x61, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x48, 0x06, 0x52, 0x08, 0x72, 0x78, 0x31,
+	0x44, 0x65, 0x6c, 0x61, 0x79, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0d, 0x72, 0x78, 0x31, 0x5f,
+	0x64, 0x72, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x07, 0x52, 0x0b, 0x72, 0x78, 0x31, 0x44, 0x72, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x88, 0x01,
+	0x01, 0x12, 0x27, 0x0a, 0x0d, 0x72, 0x78, 0x32, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x72, 0x61,
+	0x74, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x48, 0x08, 0x52, 0x0b, 0x72, 0x78, 0x32, 0x44,
+	0x61, 0x74, 0x61, 0x52, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x72, 0x78,
+	0x32, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x02, 0x48, 0x09, 0x52, 0x0c, 0x72, 0x78, 0x32, 0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x79, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x08, 0x6d, 0x61, 0x78, 0x5f, 0x65, 0x69, 0x72, 0x70,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x48, 0x0a, 0x52, 0x07, 0x6d, 0x61, 0x78, 0x45, 0x69, 0x72,
+	0x70, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x61, 0x64, 0x72, 0x5f, 0x65, 0x6e, 0x61, 0x62,
+	0x6c, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x48, 0x0b, 0x52, 0x0a, 0x61, 0x64, 0x72,
+	0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69,
+	0x64, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6d,
+	0x61, 0x63, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x72,
+	0x65, 0x67, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x5f, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69,
+	0x6f, 0x6e, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c,
+	0x61, 0x73, 0x73, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x73,
+	0x5f, 0x6a, 0x6f, 0x69, 0x6e, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x72, 0x78, 0x31, 0x5f, 0x64, 0x65,
+	0x6c, 0x61, 0x79, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x72, 0x78, 0x31, 0x5f, 0x64, 0x72, 0x5f, 0x6f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x72, 0x78, 0x32, 0x5f, 0x64, 0x61,
+	0x74, 0x61, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x72, 0x78, 0x32, 0x5f,
+	0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6d, 0x61,
+	0x78, 0x5f, 0x65, 0x69, 0x72, 0x70, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x61, 0x64, 0x72, 0x5f, 0x65,
+	0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x22, 0xe8, 0x01, 0x0a, 0x0b, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x75, 0x69, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x75, 0x69, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x72, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x04, 0x6c, 0x6f, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x70, 0x6c,
+	0x61, 0x79, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x72, 0x65, 0x70, 0x6c,
+	0x61, 0x79, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x69, 0x63, 0x5f, 0x66, 0x61, 0x69, 0x6c,
+	0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6d, 0x69, 0x63, 0x46, 0x61, 0x69,
+	0x6c, 0x65, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x5f,
+	0x6c, 0x6f, 0x73, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x66, 0x72, 0x61, 0x6d,
+	0x65, 0x4c, 0x6f, 0x73, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x22, 0xeb, 0x01, 0x0a, 0x10, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x52, 0x65, 0x63,
+	0x65, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
+	0x79, 0x5f, 0x65, 0x75, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x67, 0x61, 0x74,
+	0x65, 0x77, 0x61, 0x79, 0x45, 0x75, 0x69, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x73, 0x73, 0x69, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x72, 0x73, 0x73, 0x69, 0x12, 0x10, 0x0a, 0x03, 0x73,
+	0x6e, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x03, 0x73, 0x6e, 0x72, 0x12, 0x18, 0x0a,
+	0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x66, 0x5f, 0x63, 0x68,
+	0x61, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x72, 0x66, 0x43, 0x68, 0x61,
+	0x69, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x12, 0x2a, 0x0a, 0x0e, 0x66, 0x69, 0x6e, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0d, 0x66, 0x69, 0x6e, 0x65,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x88, 0x01, 0x01, 0x42, 0x11, 0x0a, 0x0f,
+	0x5f, 0x66, 0x69, 0x6e, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22,
+	0xbe, 0x03, 0x0a, 0x0f, 0x55, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x75, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x65, 0x75, 0x69, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x1f, 0x0a,
+	0x0b, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x5f, 0x65, 0x75, 0x69, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x45, 0x75, 0x69, 0x12, 0x12,
+	0x0a, 0x04, 0x72, 0x73, 0x73, 0x69, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x72, 0x73,
+	0x73, 0x69, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x6e, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x02, 0x52,
+	0x03, 0x73, 0x6e, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e,
+	0x63, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x52, 0x61, 0x74, 0x65, 0x12,
+	0x19, 0x0a, 0x08, 0x64, 0x65, 0x76, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x64, 0x65, 0x76, 0x41, 0x64, 0x64, 0x72, 0x12, 0x38, 0x0a, 0x0a, 0x72, 0x65,
+	0x63, 0x65, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x52,
+	0x65, 0x63, 0x65, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x65, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x40, 0x0a, 0x0f, 0x64, 0x65, 0x63, 0x6f,
+	0x64, 0x65, 0x64, 0x5f, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x0e, 0x64, 0x65, 0x63, 0x6f,
+	0x64, 0x65, 0x64, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x24, 0x0a, 0x0b, 0x63, 0x6f,
+	0x64, 0x65, 0x63, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x00, 0x52, 0x0a, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x88, 0x01, 0x01,
+	0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x22, 0x90, 0x02, 0x0a, 0x11, 0x44, 0x6f, 0x77, 0x6e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4d,
 	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x75, 0x69, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x75, 0x69, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65,
-	0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61,
-	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x75, 0x69, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c,
+	0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x63, 0x6b, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x03, 0x61, 0x63, 0x6b, 0x12, 0x1d, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x07, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x73, 0x65, 0x6e, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x04, 0x73, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01,
+	0x12, 0x1e, 0x0a, 0x08, 0x61, 0x63, 0x6b, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x03, 0x48, 0x02, 0x52, 0x07, 0x61, 0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x2f, 0x0a, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x42, 0x07, 0x0a,
+	0x05, 0x5f, 0x73, 0x65, 0x6e, 0x74, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x61, 0x63, 0x6b, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x22, 0x87, 0x05, 0x0a, 0x07, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x65, 0x75, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x75,
+	0x69, 0x12, 0x13, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x02, 0x69, 0x70, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74,
+	0x5f, 0x69, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x08, 0x73, 0x74, 0x72,
+	0x69, 0x63, 0x74, 0x49, 0x70, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x6c, 0x61, 0x74, 0x69,
+	0x74, 0x75, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x02, 0x48, 0x02, 0x52, 0x08, 0x6c, 0x61,
+	0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x6c, 0x6f, 0x6e,
+	0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x02, 0x48, 0x03, 0x52, 0x09,
+	0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08,
+	0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x02, 0x48, 0x04,
+	0x52, 0x08, 0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a,
+	0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03,
+	0x48, 0x05, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x88, 0x01, 0x01, 0x12,
+	0x1b, 0x0a, 0x06, 0x6f, 0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x48,
+	0x06, 0x52, 0x06, 0x6f, 0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6c, 0x6f,
+	0x73, 0x70, 0x61, 0x6e, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x48, 0x07, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a,
+	0x0e, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x09, 0x48, 0x08, 0x52, 0x0d, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e,
+	0x63, 0x79, 0x50, 0x6c, 0x61, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x3b, 0x0a, 0x08, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6c, 0x6f,
+	0x73, 0x70, 0x61, 0x6e, 0x2e, 0x43, 0x6f, 0x6e, 0x63, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x09, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x73, 0x88, 0x01, 0x01, 0x12, 0x37, 0x0a, 0x0a, 0x64, 0x75, 0x74, 0x79, 0x5f, 0x63,
+	0x79, 0x63, 0x6c, 0x65, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6c, 0x6f, 0x73,
+	0x70, 0x61, 0x6e, 0x2e, 0x53, 0x75, 0x62, 0x42, 0x61, 0x6e, 0x64, 0x44, 0x75, 0x74, 0x79, 0x43,
+	0x79, 0x63, 0x6c, 0x65, 0x52, 0x09, 0x64, 0x75, 0x74, 0x79, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x12,
+	0x29, 0x0a, 0x0e, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x77, 0x65, 0x6c, 0x6c, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x48, 0x0a, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x44, 0x77,
+	0x65, 0x6c, 0x6c, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69,
+	0x70, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x5f, 0x69, 0x70, 0x42,
+	0x0b, 0x0a, 0x09, 0x5f, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x42, 0x0c, 0x0a, 0x0a,
+	0x5f, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x61,
+	0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6c, 0x61, 0x73, 0x74,
+	0x5f, 0x73, 0x65, 0x65, 0x6e, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x6f, 0x6e, 0x6c, 0x69, 0x6e, 0x65,
+	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x66,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x42, 0x0b, 0x0a,
+	0x09, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x6d,
+	0x61, 0x78, 0x5f, 0x64, 0x77, 0x65, 0x6c, 0x6c, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x22, 0xad, 0x01,
+	0x0a, 0x10, 0x53, 0x75, 0x62, 0x42, 0x61, 0x6e, 0x64, 0x44, 0x75, 0x74, 0x79, 0x43, 0x79, 0x63,
+	0x6c, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x69, 0x6e, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0c, 0x6d, 0x69, 0x6e, 0x46, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f, 0x66,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0c,
+	0x6d, 0x61, 0x78, 0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1d, 0x0a, 0x0a,
+	0x64, 0x75, 0x74, 0x79, 0x5f, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02,
+	0x52, 0x09, 0x64, 0x75, 0x74, 0x79, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x73, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x73, 0x65, 0x64, 0x12,
+	0x1c, 0x0a, 0x09, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x22, 0xc2, 0x01,
+	0x0a, 0x13, 0x43, 0x6f, 0x6e, 0x63, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x43, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x72, 0x61, 0x64, 0x69, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05,
+	0x72, 0x61, 0x64, 0x69, 0x6f, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x66, 0x5f, 0x6f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x69, 0x66, 0x4f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68,
+	0x12, 0x23, 0x0a, 0x0d, 0x73, 0x70, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x66, 0x61, 0x63, 0x74, 0x6f,
+	0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x73, 0x70, 0x72, 0x65, 0x61, 0x64, 0x46,
+	0x61, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x72, 0x61,
+	0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x52, 0x61,
+	0x74, 0x65, 0x22, 0xcb, 0x01, 0x0a, 0x12, 0x43, 0x6f, 0x6e, 0x63, 0x65, 0x6e, 0x74, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x64,
+	0x69, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x02, 0x52, 0x06, 0x72, 0x61, 0x64, 0x69, 0x6f,
+	0x73, 0x12, 0x36, 0x0a, 0x08, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x5f, 0x73, 0x66, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x43, 0x6f, 0x6e,
+	0x63, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x52, 0x07, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x53, 0x66, 0x12, 0x36, 0x0a, 0x08, 0x6c, 0x6f, 0x72,
+	0x61, 0x5f, 0x73, 0x74, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6c, 0x6f,
+	0x73, 0x70, 0x61, 0x6e, 0x2e, 0x43, 0x6f, 0x6e, 0x63, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x07, 0x6c, 0x6f, 0x72, 0x61, 0x53, 0x74,
+	0x64, 0x12, 0x2d, 0x0a, 0x03, 0x66, 0x73, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b,
+	0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x43, 0x6f, 0x6e, 0x63, 0x65, 0x6e, 0x74, 0x72,
+	0x61, 0x74, 0x6f, 0x72, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x03, 0x66, 0x73, 0x6b,
+	0x22, 0xcc, 0x02, 0x0a, 0x0c, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x02, 0x52, 0x08, 0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x72,
+	0x78, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0a, 0x72, 0x78, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x13, 0x0a, 0x05,
+	0x72, 0x78, 0x5f, 0x6f, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x72, 0x78, 0x4f,
+	0x6b, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x78, 0x5f, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x65,
+	0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x72, 0x78, 0x46, 0x6f, 0x72, 0x77, 0x61,
+	0x72, 0x64, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x63, 0x6b, 0x5f, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x18, 0x08, 0x20, 0x01, 0x28, 0x02, 0x52, 0x08, 0x61, 0x63, 0x6b, 0x52, 0x61, 0x74, 0x69,
+	0x6f, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x6f, 0x77, 0x6e, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76,
+	0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x64, 0x6f, 0x77, 0x6e, 0x52, 0x65,
+	0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x78, 0x5f, 0x65, 0x6d, 0x69,
+	0x74, 0x74, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x78, 0x45, 0x6d,
+	0x69, 0x74, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x22,
+	0xc8, 0x02, 0x0a, 0x0f, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x52, 0x78, 0x50, 0x61, 0x63,
+	0x6b, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e,
+	0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x19, 0x0a,
+	0x08, 0x72, 0x66, 0x5f, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x72, 0x66, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x6f, 0x64, 0x75,
+	0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x6f,
+	0x64, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x61, 0x74, 0x61,
+	0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61, 0x74,
+	0x61, 0x52, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x5f,
+	0x72, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x64, 0x69,
+	0x6e, 0x67, 0x52, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x73, 0x73, 0x69, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x72, 0x73, 0x73, 0x69, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x6e,
+	0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x02, 0x52, 0x03, 0x73, 0x6e, 0x72, 0x12, 0x12, 0x0a, 0x04,
+	0x73, 0x69, 0x7a, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0xf0, 0x02, 0x0a, 0x0f, 0x47,
+	0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x54, 0x78, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x69, 0x6d, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x09, 0x69, 0x6d, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x70,
+	0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x67, 0x70,
+	0x73, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e,
+	0x63, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x66, 0x5f, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x72, 0x66, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x12, 0x14,
+	0x0a, 0x05, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x70,
+	0x6f, 0x77, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x72, 0x61, 0x74,
+	0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x52, 0x61, 0x74,
+	0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x72, 0x61, 0x74, 0x65,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x61,
+	0x74, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x69, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x70,
+	0x6f, 0x6c, 0x61, 0x72, 0x69, 0x74, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x69,
+	0x6e, 0x76, 0x65, 0x72, 0x74, 0x65, 0x64, 0x50, 0x6f, 0x6c, 0x61, 0x72, 0x69, 0x74, 0x79, 0x12,
+	0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x73,
+	0x69, 0x7a, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0xef, 0x01,
+	0x0a, 0x0e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
 	0x12, 0x1f, 0x0a, 0x0b, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x5f, 0x65, 0x75, 0x69, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x45, 0x75,
-	0x69, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x73, 0x73, 0x69, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x04, 0x72, 0x73, 0x73, 0x69, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x6e, 0x72, 0x18, 0x06, 0x20, 0x01,
-	0x28, 0x02, 0x52, 0x03, 0x73, 0x6e, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75,
-	0x65, 0x6e, 0x63, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x66, 0x72, 0x65, 0x71,
-	0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x72, 0x61,
-	0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x52, 0x61,
-	0x74, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x65, 0x76, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x09,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x64, 0x65, 0x76, 0x41, 0x64, 0x64, 0x72, 0x22, 0xdf, 0x01,
-	0x0a, 0x11, 0x44, 0x6f, 0x77, 0x6e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x75, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x03, 0x65, 0x75, 0x69, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12,
-	0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70,
-	0x6f, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x63, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x03, 0x61, 0x63, 0x6b, 0x12, 0x1d, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x64, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x73, 0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01,
-	0x28, 0x03, 0x48, 0x01, 0x52, 0x04, 0x73, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a,
-	0x08, 0x61, 0x63, 0x6b, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x48,
-	0x02, 0x52, 0x07, 0x61, 0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a,
-	0x08, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x73, 0x65,
-	0x6e, 0x74, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x61, 0x63, 0x6b, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x22,
-	0xf4, 0x01, 0x0a, 0x07, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x65,
-	0x75, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x75, 0x69, 0x12, 0x13, 0x0a,
-	0x02, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x02, 0x69, 0x70, 0x88,
-	0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x5f, 0x69, 0x70, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x08, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x49,
-	0x70, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x02, 0x48, 0x02, 0x52, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75,
-	0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75,
-	0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x02, 0x48, 0x03, 0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67,
-	0x69, 0x74, 0x75, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x61, 0x6c, 0x74, 0x69,
-	0x74, 0x75, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x02, 0x48, 0x04, 0x52, 0x08, 0x61, 0x6c,
-	0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69, 0x70,
-	0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x5f, 0x69, 0x70, 0x42, 0x0b,
-	0x0a, 0x09, 0x5f, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f,
-	0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x61, 0x6c,
-	0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x22, 0x10, 0x0a, 0x0e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61,
-	0x79, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2a, 0x3f, 0x0a, 0x0b, 0x44, 0x65, 0x76, 0x69,
-	0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e, 0x53, 0x50, 0x45,
-	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x4f, 0x54, 0x41, 0x41,
-	0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x42, 0x50, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x44,
-	0x49, 0x53, 0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x03, 0x42, 0x0a, 0x5a, 0x08, 0x2e, 0x2f, 0x6c,
-	0x6f, 0x73, 0x70, 0x61, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x45, 0x75,
+	0x69, 0x12, 0x2c, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x18, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x12, 0x0a,
+	0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6a, 0x73, 0x6f,
+	0x6e, 0x12, 0x27, 0x0a, 0x02, 0x72, 0x78, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x52, 0x78,
+	0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x52, 0x02, 0x72, 0x78, 0x12, 0x2c, 0x0a, 0x02, 0x74, 0x78,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e,
+	0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x54, 0x78, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x48,
+	0x00, 0x52, 0x02, 0x74, 0x78, 0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x74, 0x78, 0x22,
+	0xf0, 0x01, 0x0a, 0x07, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x61,
+	0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x65, 0x75, 0x69, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x45, 0x75, 0x69, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x36, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e,
+	0x2e, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x1b,
+	0x0a, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00,
+	0x52, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x88, 0x01, 0x01, 0x1a, 0x3a, 0x0a, 0x0c, 0x48,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x22, 0xd2, 0x01, 0x0a, 0x0f, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x44, 0x65,
+	0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x65, 0x62, 0x68, 0x6f, 0x6f,
+	0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x77, 0x65, 0x62, 0x68,
+	0x6f, 0x6f, 0x6b, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d,
+	0x70, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x63, 0x6f, 0x64,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43,
+	0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x65, 0x6c,
+	0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x64, 0x65,
+	0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x2a, 0x3f, 0x0a, 0x0b, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x4f, 0x54, 0x41, 0x41, 0x10,
+	0x01, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x42, 0x50, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x49,
+	0x53, 0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x03, 0x2a, 0x34, 0x0a, 0x0b, 0x44, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x4c, 0x41, 0x53, 0x53,
+	0x5f, 0x41, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x4c, 0x41, 0x53, 0x53, 0x5f, 0x42, 0x10,
+	0x01, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x4c, 0x41, 0x53, 0x53, 0x5f, 0x43, 0x10, 0x02, 0x2a, 0x2e,
+	0x0a, 0x0a, 0x4d, 0x41, 0x43, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0f, 0x0a, 0x0b,
+	0x4c, 0x4f, 0x52, 0x41, 0x57, 0x41, 0x4e, 0x5f, 0x31, 0x5f, 0x30, 0x10, 0x00, 0x12, 0x0f, 0x0a,
+	0x0b, 0x4c, 0x4f, 0x52, 0x41, 0x57, 0x41, 0x4e, 0x5f, 0x31, 0x5f, 0x31, 0x10, 0x01, 0x2a, 0x53,
+	0x0a, 0x10, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x45, 0x56,
+	0x45, 0x4e, 0x54, 0x10, 0x00, 0x12, 0x06, 0x0a, 0x02, 0x52, 0x58, 0x10, 0x01, 0x12, 0x06, 0x0a,
+	0x02, 0x54, 0x58, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x4b, 0x45, 0x45, 0x50, 0x5f, 0x41, 0x4c,
+	0x49, 0x56, 0x45, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x49, 0x4e, 0x41, 0x43, 0x54, 0x49, 0x56,
+	0x45, 0x10, 0x04, 0x42, 0x0a, 0x5a, 0x08, 0x2e, 0x2f, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -730,24 +2656,57 @@ func file_lospan_entities_proto_rawDescGZIP() []byte {
 	return file_lospan_entities_proto_rawDescData
 }
 
-var file_lospan_entities_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_lospan_entities_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_lospan_entities_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_lospan_entities_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_lospan_entities_proto_goTypes = []interface{}{
-	(DeviceState)(0),          // 0: lospan.DeviceState
-	(*Application)(nil),       // 1: lospan.Application
-	(*Device)(nil),            // 2: lospan.Device
-	(*UpstreamMessage)(nil),   // 3: lospan.UpstreamMessage
-	(*DownstreamMessage)(nil), // 4: lospan.DownstreamMessage
-	(*Gateway)(nil),           // 5: lospan.Gateway
-	(*GatewayMessage)(nil),    // 6: lospan.GatewayMessage
+	(DeviceState)(0),            // 0: lospan.DeviceState
+	(DeviceClass)(0),            // 1: lospan.DeviceClass
+	(MACVersion)(0),             // 2: lospan.MACVersion
+	(GatewayEventType)(0),       // 3: lospan.GatewayEventType
+	(*Application)(nil),         // 4: lospan.Application
+	(*Device)(nil),              // 5: lospan.Device
+	(*DeviceProfile)(nil),       // 6: lospan.DeviceProfile
+	(*DeviceStats)(nil),         // 7: lospan.DeviceStats
+	(*GatewayReception)(nil),    // 8: lospan.GatewayReception
+	(*UpstreamMessage)(nil),     // 9: lospan.UpstreamMessage
+	(*DownstreamMessage)(nil),   // 10: lospan.DownstreamMessage
+	(*Gateway)(nil),             // 11: lospan.Gateway
+	(*SubBandDutyCycle)(nil),    // 12: lospan.SubBandDutyCycle
+	(*ConcentratorChannel)(nil), // 13: lospan.ConcentratorChannel
+	(*ConcentratorConfig)(nil),  // 14: lospan.ConcentratorConfig
+	(*GatewayStats)(nil),        // 15: lospan.GatewayStats
+	(*GatewayRxPacket)(nil),     // 16: lospan.GatewayRxPacket
+	(*GatewayTxPacket)(nil),     // 17: lospan.GatewayTxPacket
+	(*GatewayMessage)(nil),      // 18: lospan.GatewayMessage
+	(*Webhook)(nil),             // 19: lospan.Webhook
+	(*WebhookDelivery)(nil),     // 20: lospan.WebhookDelivery
+	nil,                         // 21: lospan.Webhook.HeadersEntry
+	(*structpb.Struct)(nil),     // 22: google.protobuf.Struct
 }
 var file_lospan_entities_proto_depIdxs = []int32{
-	0, // 0: lospan.Device.state:type_name -> lospan.DeviceState
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: lospan.Device.state:type_name -> lospan.DeviceState
+	1,  // 1: lospan.Device.device_class:type_name -> lospan.DeviceClass
+	2,  // 2: lospan.Device.mac_version:type_name -> lospan.MACVersion
+	2,  // 3: lospan.DeviceProfile.mac_version:type_name -> lospan.MACVersion
+	1,  // 4: lospan.DeviceProfile.device_class:type_name -> lospan.DeviceClass
+	8,  // 5: lospan.UpstreamMessage.receptions:type_name -> lospan.GatewayReception
+	22, // 6: lospan.UpstreamMessage.decoded_payload:type_name -> google.protobuf.Struct
+	22, // 7: lospan.DownstreamMessage.object:type_name -> google.protobuf.Struct
+	15, // 8: lospan.Gateway.stats:type_name -> lospan.GatewayStats
+	14, // 9: lospan.Gateway.channels:type_name -> lospan.ConcentratorConfig
+	12, // 10: lospan.Gateway.duty_cycle:type_name -> lospan.SubBandDutyCycle
+	13, // 11: lospan.ConcentratorConfig.multi_sf:type_name -> lospan.ConcentratorChannel
+	13, // 12: lospan.ConcentratorConfig.lora_std:type_name -> lospan.ConcentratorChannel
+	13, // 13: lospan.ConcentratorConfig.fsk:type_name -> lospan.ConcentratorChannel
+	3,  // 14: lospan.GatewayMessage.type:type_name -> lospan.GatewayEventType
+	16, // 15: lospan.GatewayMessage.rx:type_name -> lospan.GatewayRxPacket
+	17, // 16: lospan.GatewayMessage.tx:type_name -> lospan.GatewayTxPacket
+	21, // 17: lospan.Webhook.headers:type_name -> lospan.Webhook.HeadersEntry
+	18, // [18:18] is the sub-list for method output_type
+	18, // [18:18] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_lospan_entities_proto_init() }
@@ -781,7 +2740,7 @@ func file_lospan_entities_proto_init() {
 			}
 		}
 		file_lospan_entities_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpstreamMessage); i {
+			switch v := v.(*DeviceProfile); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -793,7 +2752,7 @@ func file_lospan_entities_proto_init() {
 			}
 		}
 		file_lospan_entities_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DownstreamMessage); i {
+			switch v := v.(*DeviceStats); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -805,7 +2764,7 @@ func file_lospan_entities_proto_init() {
 			}
 		}
 		file_lospan_entities_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Gateway); i {
+			switch v := v.(*GatewayReception); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -817,6 +2776,114 @@ func file_lospan_entities_proto_init() {
 			}
 		}
 		file_lospan_entities_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpstreamMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lospan_entities_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownstreamMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lospan_entities_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Gateway); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lospan_entities_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubBandDutyCycle); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lospan_entities_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConcentratorChannel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lospan_entities_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConcentratorConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lospan_entities_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GatewayStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lospan_entities_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GatewayRxPacket); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lospan_entities_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GatewayTxPacket); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lospan_entities_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GatewayMessage); i {
 			case 0:
 				return &v.state
@@ -828,18 +2895,47 @@ func file_lospan_entities_proto_init() {
 				return nil
 			}
 		}
+		file_lospan_entities_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Webhook); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lospan_entities_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WebhookDelivery); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_lospan_entities_proto_msgTypes[0].OneofWrappers = []interface{}{}
 	file_lospan_entities_proto_msgTypes[1].OneofWrappers = []interface{}{}
-	file_lospan_entities_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	file_lospan_entities_proto_msgTypes[2].OneofWrappers = []interface{}{}
 	file_lospan_entities_proto_msgTypes[4].OneofWrappers = []interface{}{}
+	file_lospan_entities_proto_msgTypes[5].OneofWrappers = []interface{}{}
+	file_lospan_entities_proto_msgTypes[6].OneofWrappers = []interface{}{}
+	file_lospan_entities_proto_msgTypes[7].OneofWrappers = []interface{}{}
+	file_lospan_entities_proto_msgTypes[14].OneofWrappers = []interface{}{}
+	file_lospan_entities_proto_msgTypes[15].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_lospan_entities_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   6,
+			NumEnums:      4,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
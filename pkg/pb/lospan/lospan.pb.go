@@ -26,7 +26,7 @@ var file_lospan_lospan_proto_rawDesc = []byte{
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x1a, 0x15, 0x6c,
 	0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2f, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x15, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2f, 0x6d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0xcb, 0x09, 0x0a, 0x06,
+	0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0xf9, 0x0f, 0x0a, 0x06,
 	0x4c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x55, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x70,
 	0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1f, 0x2e, 0x6c, 0x6f, 0x73,
 	0x70, 0x61, 0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
@@ -42,139 +42,225 @@ var file_lospan_lospan_proto_rawDesc = []byte{
 	0x6e, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
 	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x6c, 0x6f, 0x73,
 	0x70, 0x61, 0x6e, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
-	0x4a, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e,
-	0x41, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x49, 0x0a, 0x0c, 0x4c,
-	0x69, 0x73, 0x74, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x73, 0x12, 0x1b, 0x2e, 0x6c, 0x6f,
+	0x3d, 0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x13, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x41, 0x70,
+	0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x13, 0x2e, 0x6c, 0x6f, 0x73, 0x70,
+	0x61, 0x6e, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x4a,
+	0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x20, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x41,
+	0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x31, 0x0a, 0x0d, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x12, 0x0f, 0x2e, 0x6c, 0x6f,
+	0x73, 0x70, 0x61, 0x6e, 0x2e, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x1a, 0x0f, 0x2e, 0x6c,
+	0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x12, 0x49, 0x0a,
+	0x0c, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x73, 0x12, 0x1b, 0x2e,
+	0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x65, 0x62, 0x68, 0x6f,
+	0x6f, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6c, 0x6f, 0x73,
+	0x70, 0x61, 0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x12, 0x1c, 0x2e, 0x6c, 0x6f, 0x73, 0x70,
+	0x61, 0x6e, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e,
+	0x2e, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x12, 0x64, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74,
+	0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x69, 0x65,
+	0x73, 0x12, 0x24, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x57,
+	0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x69, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x44, 0x65, 0x6c, 0x69,
+	0x76, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49,
+	0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x73, 0x12, 0x1b,
+	0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x74, 0x65,
+	0x77, 0x61, 0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6c, 0x6f,
 	0x73, 0x70, 0x61, 0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61,
-	0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x73, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x0d, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x0f, 0x2e, 0x6c, 0x6f, 0x73,
+	0x70, 0x61, 0x6e, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x1a, 0x0f, 0x2e, 0x6c, 0x6f,
+	0x73, 0x70, 0x61, 0x6e, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x38, 0x0a, 0x0a,
+	0x47, 0x65, 0x74, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x19, 0x2e, 0x6c, 0x6f, 0x73,
+	0x70, 0x61, 0x6e, 0x2e, 0x47, 0x65, 0x74, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x47,
+	0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x31, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
 	0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x0f, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e,
 	0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x1a, 0x0f, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61,
-	0x6e, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x38, 0x0a, 0x0a, 0x47, 0x65, 0x74,
-	0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x19, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e,
-	0x2e, 0x47, 0x65, 0x74, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x47, 0x61, 0x74, 0x65,
-	0x77, 0x61, 0x79, 0x12, 0x31, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x47, 0x61, 0x74,
-	0x65, 0x77, 0x61, 0x79, 0x12, 0x0f, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x47, 0x61,
-	0x74, 0x65, 0x77, 0x61, 0x79, 0x1a, 0x0f, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x47,
-	0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x3e, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
-	0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x1c, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e,
-	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x47,
-	0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x44, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65,
-	0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x19, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x4c,
-	0x69, 0x73, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1a, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65,
-	0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x0c,
-	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x0e, 0x2e, 0x6c,
-	0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x0e, 0x2e, 0x6c,
-	0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x35, 0x0a, 0x09,
-	0x47, 0x65, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x18, 0x2e, 0x6c, 0x6f, 0x73, 0x70,
-	0x61, 0x6e, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x2e, 0x0a, 0x0c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x0e, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x1a, 0x0e, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x3b, 0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x1b, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x0e, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
-	0x12, 0x34, 0x0a, 0x05, 0x49, 0x6e, 0x62, 0x6f, 0x78, 0x12, 0x14, 0x2e, 0x6c, 0x6f, 0x73, 0x70,
-	0x61, 0x6e, 0x2e, 0x49, 0x6e, 0x62, 0x6f, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x15, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x49, 0x6e, 0x62, 0x6f, 0x78, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x06, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78,
-	0x12, 0x15, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e,
-	0x2e, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x43, 0x0a, 0x0b, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x19,
-	0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x73, 0x74, 0x72, 0x65,
-	0x61, 0x6d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x19, 0x2e, 0x6c, 0x6f, 0x73, 0x70,
-	0x61, 0x6e, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x12, 0x4a, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x1d, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e,
-	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x55,
-	0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x30, 0x01,
-	0x12, 0x47, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61,
-	0x79, 0x12, 0x1c, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61,
-	0x6d, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x16, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x30, 0x01, 0x42, 0x0a, 0x5a, 0x08, 0x2e, 0x2f, 0x6c,
-	0x6f, 0x73, 0x70, 0x61, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6e, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x3e, 0x0a, 0x0d, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x1c, 0x2e, 0x6c, 0x6f, 0x73,
+	0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61,
+	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61,
+	0x6e, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x5b, 0x0a, 0x12, 0x4c, 0x69, 0x73,
+	0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12,
+	0x21, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x22, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x15, 0x2e,
+	0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72, 0x6f,
+	0x66, 0x69, 0x6c, 0x65, 0x1a, 0x15, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x4a, 0x0a, 0x10, 0x47,
+	0x65, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12,
+	0x1f, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x15, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x43, 0x0a, 0x13, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x15,
+	0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72,
+	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x1a, 0x15, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x50, 0x0a, 0x13,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x66,
+	0x69, 0x6c, 0x65, 0x12, 0x22, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e,
+	0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x44,
+	0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x19, 0x2e,
+	0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61,
+	0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x0e, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x1a, 0x0e, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x35, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x18, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x6c, 0x6f,
+	0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2e, 0x0a, 0x0c, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x0e, 0x2e, 0x6c, 0x6f,
+	0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x0e, 0x2e, 0x6c, 0x6f,
+	0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3b, 0x0a, 0x0c, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1b, 0x2e, 0x6c, 0x6f,
+	0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61,
+	0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x44, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x44,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1d, 0x2e, 0x6c, 0x6f, 0x73,
+	0x70, 0x61, 0x6e, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x6c, 0x6f, 0x73, 0x70,
+	0x61, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x34,
+	0x0a, 0x05, 0x49, 0x6e, 0x62, 0x6f, 0x78, 0x12, 0x14, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e,
+	0x2e, 0x49, 0x6e, 0x62, 0x6f, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e,
+	0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x49, 0x6e, 0x62, 0x6f, 0x78, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x06, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x12, 0x15,
+	0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x4f,
+	0x75, 0x74, 0x62, 0x6f, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a,
+	0x0b, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x19, 0x2e, 0x6c,
+	0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x19, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e,
+	0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x4a, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x73, 0x12, 0x1d, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x55, 0x70, 0x73,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x30, 0x01, 0x12, 0x47,
+	0x0a, 0x0d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12,
+	0x1c, 0x2e, 0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x47,
+	0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x6c, 0x6f, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x30, 0x01, 0x42, 0x0a, 0x5a, 0x08, 0x2e, 0x2f, 0x6c, 0x6f, 0x73,
+	0x70, 0x61, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_lospan_lospan_proto_goTypes = []interface{}{
-	(*ListApplicationsRequest)(nil),  // 0: lospan.ListApplicationsRequest
-	(*GetApplicationRequest)(nil),    // 1: lospan.GetApplicationRequest
-	(*CreateApplicationRequest)(nil), // 2: lospan.CreateApplicationRequest
-	(*DeleteApplicationRequest)(nil), // 3: lospan.DeleteApplicationRequest
-	(*ListGatewaysRequest)(nil),      // 4: lospan.ListGatewaysRequest
-	(*Gateway)(nil),                  // 5: lospan.Gateway
-	(*GetGatewayRequest)(nil),        // 6: lospan.GetGatewayRequest
-	(*DeleteGatewayRequest)(nil),     // 7: lospan.DeleteGatewayRequest
-	(*ListDeviceRequest)(nil),        // 8: lospan.ListDeviceRequest
-	(*Device)(nil),                   // 9: lospan.Device
-	(*GetDeviceRequest)(nil),         // 10: lospan.GetDeviceRequest
-	(*DeleteDeviceRequest)(nil),      // 11: lospan.DeleteDeviceRequest
-	(*InboxRequest)(nil),             // 12: lospan.InboxRequest
-	(*OutboxRequest)(nil),            // 13: lospan.OutboxRequest
-	(*DownstreamMessage)(nil),        // 14: lospan.DownstreamMessage
-	(*StreamMessagesRequest)(nil),    // 15: lospan.StreamMessagesRequest
-	(*StreamGatewayRequest)(nil),     // 16: lospan.StreamGatewayRequest
-	(*ListApplicationsResponse)(nil), // 17: lospan.ListApplicationsResponse
-	(*Application)(nil),              // 18: lospan.Application
-	(*ListGatewaysResponse)(nil),     // 19: lospan.ListGatewaysResponse
-	(*ListDeviceResponse)(nil),       // 20: lospan.ListDeviceResponse
-	(*InboxResponse)(nil),            // 21: lospan.InboxResponse
-	(*OutboxResponse)(nil),           // 22: lospan.OutboxResponse
-	(*UpstreamMessage)(nil),          // 23: lospan.UpstreamMessage
-	(*GatewayMessage)(nil),           // 24: lospan.GatewayMessage
+	(*ListApplicationsRequest)(nil),       // 0: lospan.ListApplicationsRequest
+	(*GetApplicationRequest)(nil),         // 1: lospan.GetApplicationRequest
+	(*CreateApplicationRequest)(nil),      // 2: lospan.CreateApplicationRequest
+	(*Application)(nil),                   // 3: lospan.Application
+	(*DeleteApplicationRequest)(nil),      // 4: lospan.DeleteApplicationRequest
+	(*Webhook)(nil),                       // 5: lospan.Webhook
+	(*ListWebhooksRequest)(nil),           // 6: lospan.ListWebhooksRequest
+	(*DeleteWebhookRequest)(nil),          // 7: lospan.DeleteWebhookRequest
+	(*ListWebhookDeliveriesRequest)(nil),  // 8: lospan.ListWebhookDeliveriesRequest
+	(*ListGatewaysRequest)(nil),           // 9: lospan.ListGatewaysRequest
+	(*Gateway)(nil),                       // 10: lospan.Gateway
+	(*GetGatewayRequest)(nil),             // 11: lospan.GetGatewayRequest
+	(*DeleteGatewayRequest)(nil),          // 12: lospan.DeleteGatewayRequest
+	(*ListDeviceProfilesRequest)(nil),     // 13: lospan.ListDeviceProfilesRequest
+	(*DeviceProfile)(nil),                 // 14: lospan.DeviceProfile
+	(*GetDeviceProfileRequest)(nil),       // 15: lospan.GetDeviceProfileRequest
+	(*DeleteDeviceProfileRequest)(nil),    // 16: lospan.DeleteDeviceProfileRequest
+	(*ListDeviceRequest)(nil),             // 17: lospan.ListDeviceRequest
+	(*Device)(nil),                        // 18: lospan.Device
+	(*GetDeviceRequest)(nil),              // 19: lospan.GetDeviceRequest
+	(*DeleteDeviceRequest)(nil),           // 20: lospan.DeleteDeviceRequest
+	(*GetDeviceStatsRequest)(nil),         // 21: lospan.GetDeviceStatsRequest
+	(*InboxRequest)(nil),                  // 22: lospan.InboxRequest
+	(*OutboxRequest)(nil),                 // 23: lospan.OutboxRequest
+	(*DownstreamMessage)(nil),             // 24: lospan.DownstreamMessage
+	(*StreamMessagesRequest)(nil),         // 25: lospan.StreamMessagesRequest
+	(*StreamGatewayRequest)(nil),          // 26: lospan.StreamGatewayRequest
+	(*ListApplicationsResponse)(nil),      // 27: lospan.ListApplicationsResponse
+	(*ListWebhooksResponse)(nil),          // 28: lospan.ListWebhooksResponse
+	(*ListWebhookDeliveriesResponse)(nil), // 29: lospan.ListWebhookDeliveriesResponse
+	(*ListGatewaysResponse)(nil),          // 30: lospan.ListGatewaysResponse
+	(*ListDeviceProfilesResponse)(nil),    // 31: lospan.ListDeviceProfilesResponse
+	(*ListDeviceResponse)(nil),            // 32: lospan.ListDeviceResponse
+	(*DeviceStats)(nil),                   // 33: lospan.DeviceStats
+	(*InboxResponse)(nil),                 // 34: lospan.InboxResponse
+	(*OutboxResponse)(nil),                // 35: lospan.OutboxResponse
+	(*UpstreamMessage)(nil),               // 36: lospan.UpstreamMessage
+	(*GatewayMessage)(nil),                // 37: lospan.GatewayMessage
 }
 var file_lospan_lospan_proto_depIdxs = []int32{
 	0,  // 0: lospan.Lospan.ListApplications:input_type -> lospan.ListApplicationsRequest
 	1,  // 1: lospan.Lospan.GetApplication:input_type -> lospan.GetApplicationRequest
 	2,  // 2: lospan.Lospan.CreateApplication:input_type -> lospan.CreateApplicationRequest
-	3,  // 3: lospan.Lospan.DeleteApplication:input_type -> lospan.DeleteApplicationRequest
-	4,  // 4: lospan.Lospan.ListGateways:input_type -> lospan.ListGatewaysRequest
-	5,  // 5: lospan.Lospan.CreateGateway:input_type -> lospan.Gateway
-	6,  // 6: lospan.Lospan.GetGateway:input_type -> lospan.GetGatewayRequest
-	5,  // 7: lospan.Lospan.UpdateGateway:input_type -> lospan.Gateway
-	7,  // 8: lospan.Lospan.DeleteGateway:input_type -> lospan.DeleteGatewayRequest
-	8,  // 9: lospan.Lospan.ListDevices:input_type -> lospan.ListDeviceRequest
-	9,  // 10: lospan.Lospan.CreateDevice:input_type -> lospan.Device
-	10, // 11: lospan.Lospan.GetDevice:input_type -> lospan.GetDeviceRequest
-	9,  // 12: lospan.Lospan.UpdateDevice:input_type -> lospan.Device
-	11, // 13: lospan.Lospan.DeleteDevice:input_type -> lospan.DeleteDeviceRequest
-	12, // 14: lospan.Lospan.Inbox:input_type -> lospan.InboxRequest
-	13, // 15: lospan.Lospan.Outbox:input_type -> lospan.OutboxRequest
-	14, // 16: lospan.Lospan.SendMessage:input_type -> lospan.DownstreamMessage
-	15, // 17: lospan.Lospan.StreamMessages:input_type -> lospan.StreamMessagesRequest
-	16, // 18: lospan.Lospan.StreamGateway:input_type -> lospan.StreamGatewayRequest
-	17, // 19: lospan.Lospan.ListApplications:output_type -> lospan.ListApplicationsResponse
-	18, // 20: lospan.Lospan.GetApplication:output_type -> lospan.Application
-	18, // 21: lospan.Lospan.CreateApplication:output_type -> lospan.Application
-	18, // 22: lospan.Lospan.DeleteApplication:output_type -> lospan.Application
-	19, // 23: lospan.Lospan.ListGateways:output_type -> lospan.ListGatewaysResponse
-	5,  // 24: lospan.Lospan.CreateGateway:output_type -> lospan.Gateway
-	5,  // 25: lospan.Lospan.GetGateway:output_type -> lospan.Gateway
-	5,  // 26: lospan.Lospan.UpdateGateway:output_type -> lospan.Gateway
-	5,  // 27: lospan.Lospan.DeleteGateway:output_type -> lospan.Gateway
-	20, // 28: lospan.Lospan.ListDevices:output_type -> lospan.ListDeviceResponse
-	9,  // 29: lospan.Lospan.CreateDevice:output_type -> lospan.Device
-	9,  // 30: lospan.Lospan.GetDevice:output_type -> lospan.Device
-	9,  // 31: lospan.Lospan.UpdateDevice:output_type -> lospan.Device
-	9,  // 32: lospan.Lospan.DeleteDevice:output_type -> lospan.Device
-	21, // 33: lospan.Lospan.Inbox:output_type -> lospan.InboxResponse
-	22, // 34: lospan.Lospan.Outbox:output_type -> lospan.OutboxResponse
-	14, // 35: lospan.Lospan.SendMessage:output_type -> lospan.DownstreamMessage
-	23, // 36: lospan.Lospan.StreamMessages:output_type -> lospan.UpstreamMessage
-	24, // 37: lospan.Lospan.StreamGateway:output_type -> lospan.GatewayMessage
-	19, // [19:38] is the sub-list for method output_type
-	0,  // [0:19] is the sub-list for method input_type
+	3,  // 3: lospan.Lospan.UpdateApplication:input_type -> lospan.Application
+	4,  // 4: lospan.Lospan.DeleteApplication:input_type -> lospan.DeleteApplicationRequest
+	5,  // 5: lospan.Lospan.CreateWebhook:input_type -> lospan.Webhook
+	6,  // 6: lospan.Lospan.ListWebhooks:input_type -> lospan.ListWebhooksRequest
+	7,  // 7: lospan.Lospan.DeleteWebhook:input_type -> lospan.DeleteWebhookRequest
+	8,  // 8: lospan.Lospan.ListWebhookDeliveries:input_type -> lospan.ListWebhookDeliveriesRequest
+	9,  // 9: lospan.Lospan.ListGateways:input_type -> lospan.ListGatewaysRequest
+	10, // 10: lospan.Lospan.CreateGateway:input_type -> lospan.Gateway
+	11, // 11: lospan.Lospan.GetGateway:input_type -> lospan.GetGatewayRequest
+	10, // 12: lospan.Lospan.UpdateGateway:input_type -> lospan.Gateway
+	12, // 13: lospan.Lospan.DeleteGateway:input_type -> lospan.DeleteGatewayRequest
+	13, // 14: lospan.Lospan.ListDeviceProfiles:input_type -> lospan.ListDeviceProfilesRequest
+	14, // 15: lospan.Lospan.CreateDeviceProfile:input_type -> lospan.DeviceProfile
+	15, // 16: lospan.Lospan.GetDeviceProfile:input_type -> lospan.GetDeviceProfileRequest
+	14, // 17: lospan.Lospan.UpdateDeviceProfile:input_type -> lospan.DeviceProfile
+	16, // 18: lospan.Lospan.DeleteDeviceProfile:input_type -> lospan.DeleteDeviceProfileRequest
+	17, // 19: lospan.Lospan.ListDevices:input_type -> lospan.ListDeviceRequest
+	18, // 20: lospan.Lospan.CreateDevice:input_type -> lospan.Device
+	19, // 21: lospan.Lospan.GetDevice:input_type -> lospan.GetDeviceRequest
+	18, // 22: lospan.Lospan.UpdateDevice:input_type -> lospan.Device
+	20, // 23: lospan.Lospan.DeleteDevice:input_type -> lospan.DeleteDeviceRequest
+	21, // 24: lospan.Lospan.GetDeviceStats:input_type -> lospan.GetDeviceStatsRequest
+	22, // 25: lospan.Lospan.Inbox:input_type -> lospan.InboxRequest
+	23, // 26: lospan.Lospan.Outbox:input_type -> lospan.OutboxRequest
+	24, // 27: lospan.Lospan.SendMessage:input_type -> lospan.DownstreamMessage
+	25, // 28: lospan.Lospan.StreamMessages:input_type -> lospan.StreamMessagesRequest
+	26, // 29: lospan.Lospan.StreamGateway:input_type -> lospan.StreamGatewayRequest
+	27, // 30: lospan.Lospan.ListApplications:output_type -> lospan.ListApplicationsResponse
+	3,  // 31: lospan.Lospan.GetApplication:output_type -> lospan.Application
+	3,  // 32: lospan.Lospan.CreateApplication:output_type -> lospan.Application
+	3,  // 33: lospan.Lospan.UpdateApplication:output_type -> lospan.Application
+	3,  // 34: lospan.Lospan.DeleteApplication:output_type -> lospan.Application
+	5,  // 35: lospan.Lospan.CreateWebhook:output_type -> lospan.Webhook
+	28, // 36: lospan.Lospan.ListWebhooks:output_type -> lospan.ListWebhooksResponse
+	5,  // 37: lospan.Lospan.DeleteWebhook:output_type -> lospan.Webhook
+	29, // 38: lospan.Lospan.ListWebhookDeliveries:output_type -> lospan.ListWebhookDeliveriesResponse
+	30, // 39: lospan.Lospan.ListGateways:output_type -> lospan.ListGatewaysResponse
+	10, // 40: lospan.Lospan.CreateGateway:output_type -> lospan.Gateway
+	10, // 41: lospan.Lospan.GetGateway:output_type -> lospan.Gateway
+	10, // 42: lospan.Lospan.UpdateGateway:output_type -> lospan.Gateway
+	10, // 43: lospan.Lospan.DeleteGateway:output_type -> lospan.Gateway
+	31, // 44: lospan.Lospan.ListDeviceProfiles:output_type -> lospan.ListDeviceProfilesResponse
+	14, // 45: lospan.Lospan.CreateDeviceProfile:output_type -> lospan.DeviceProfile
+	14, // 46: lospan.Lospan.GetDeviceProfile:output_type -> lospan.DeviceProfile
+	14, // 47: lospan.Lospan.UpdateDeviceProfile:output_type -> lospan.DeviceProfile
+	14, // 48: lospan.Lospan.DeleteDeviceProfile:output_type -> lospan.DeviceProfile
+	32, // 49: lospan.Lospan.ListDevices:output_type -> lospan.ListDeviceResponse
+	18, // 50: lospan.Lospan.CreateDevice:output_type -> lospan.Device
+	18, // 51: lospan.Lospan.GetDevice:output_type -> lospan.Device
+	18, // 52: lospan.Lospan.UpdateDevice:output_type -> lospan.Device
+	18, // 53: lospan.Lospan.DeleteDevice:output_type -> lospan.Device
+	33, // 54: lospan.Lospan.GetDeviceStats:output_type -> lospan.DeviceStats
+	34, // 55: lospan.Lospan.Inbox:output_type -> lospan.InboxResponse
+	35, // 56: lospan.Lospan.Outbox:output_type -> lospan.OutboxResponse
+	24, // 57: lospan.Lospan.SendMessage:output_type -> lospan.DownstreamMessage
+	36, // 58: lospan.Lospan.StreamMessages:output_type -> lospan.UpstreamMessage
+	37, // 59: lospan.Lospan.StreamGateway:output_type -> lospan.GatewayMessage
+	30, // [30:60] is the sub-list for method output_type
+	0,  // [0:30] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
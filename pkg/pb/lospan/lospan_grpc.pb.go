@@ -29,8 +29,18 @@ type LospanClient interface {
 	GetApplication(ctx context.Context, in *GetApplicationRequest, opts ...grpc.CallOption) (*Application, error)
 	// CreateApplication creates a new application
 	CreateApplication(ctx context.Context, in *CreateApplicationRequest, opts ...grpc.CallOption) (*Application, error)
+	// UpdateApplication updates the tag and the payload codec for an application
+	UpdateApplication(ctx context.Context, in *Application, opts ...grpc.CallOption) (*Application, error)
 	// DeleteApplication removes an application.
 	DeleteApplication(ctx context.Context, in *DeleteApplicationRequest, opts ...grpc.CallOption) (*Application, error)
+	// CreateWebhook adds a webhook to an application
+	CreateWebhook(ctx context.Context, in *Webhook, opts ...grpc.CallOption) (*Webhook, error)
+	// ListWebhooks lists the webhooks for an application
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	// DeleteWebhook removes a webhook from an application
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*Webhook, error)
+	// ListWebhookDeliveries lists the delivery attempts for a webhook
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
 	// ListGateways lists the gateways in the network server. Each concentrator needs its own
 	// gateway definition
 	ListGateways(ctx context.Context, in *ListGatewaysRequest, opts ...grpc.CallOption) (*ListGatewaysResponse, error)
@@ -42,6 +52,17 @@ type LospanClient interface {
 	UpdateGateway(ctx context.Context, in *Gateway, opts ...grpc.CallOption) (*Gateway, error)
 	// DeleteGateway removes a gateway. When deleted the service won't accept data from it anymore.
 	DeleteGateway(ctx context.Context, in *DeleteGatewayRequest, opts ...grpc.CallOption) (*Gateway, error)
+	// ListDeviceProfiles lists the device profiles
+	ListDeviceProfiles(ctx context.Context, in *ListDeviceProfilesRequest, opts ...grpc.CallOption) (*ListDeviceProfilesResponse, error)
+	// CreateDeviceProfile creates a new device profile
+	CreateDeviceProfile(ctx context.Context, in *DeviceProfile, opts ...grpc.CallOption) (*DeviceProfile, error)
+	// GetDeviceProfile returns a single device profile
+	GetDeviceProfile(ctx context.Context, in *GetDeviceProfileRequest, opts ...grpc.CallOption) (*DeviceProfile, error)
+	// UpdateDeviceProfile updates a device profile. The new settings are applied to ABP devices
+	// right away and to OTAA devices when they join.
+	UpdateDeviceProfile(ctx context.Context, in *DeviceProfile, opts ...grpc.CallOption) (*DeviceProfile, error)
+	// DeleteDeviceProfile removes a device profile. Profiles that are in use can't be removed.
+	DeleteDeviceProfile(ctx context.Context, in *DeleteDeviceProfileRequest, opts ...grpc.CallOption) (*DeviceProfile, error)
 	// ListDevices retrieves the devices for the application
 	ListDevices(ctx context.Context, in *ListDeviceRequest, opts ...grpc.CallOption) (*ListDeviceResponse, error)
 	// CreateDevice creates a new device
@@ -52,6 +73,8 @@ type LospanClient interface {
 	UpdateDevice(ctx context.Context, in *Device, opts ...grpc.CallOption) (*Device, error)
 	// DeleteDevice removes a device from the application
 	DeleteDevice(ctx context.Context, in *DeleteDeviceRequest, opts ...grpc.CallOption) (*Device, error)
+	// GetDeviceStats returns the frame statistics for a device
+	GetDeviceStats(ctx context.Context, in *GetDeviceStatsRequest, opts ...grpc.CallOption) (*DeviceStats, error)
 	// Inbox lists the downstream messages from a device
 	Inbox(ctx context.Context, in *InboxRequest, opts ...grpc.CallOption) (*InboxResponse, error)
 	// Outbox lists the downstream messages to a device. Sent messages are purged automatically
@@ -97,6 +120,15 @@ func (c *lospanClient) CreateApplication(ctx context.Context, in *CreateApplicat
 	return out, nil
 }
 
+func (c *lospanClient) UpdateApplication(ctx context.Context, in *Application, opts ...grpc.CallOption) (*Application, error) {
+	out := new(Application)
+	err := c.cc.Invoke(ctx, "/lospan.Lospan/UpdateApplication", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lospanClient) DeleteApplication(ctx context.Context, in *DeleteApplicationRequest, opts ...grpc.CallOption) (*Application, error) {
 	out := new(Application)
 	err := c.cc.Invoke(ctx, "/lospan.Lospan/DeleteApplication", in, out, opts...)
@@ -106,6 +138,42 @@ func (c *lospanClient) DeleteApplication(ctx context.Context, in *DeleteApplicat
 	return out, nil
 }
 
+func (c *lospanClient) CreateWebhook(ctx context.Context, in *Webhook, opts ...grpc.CallOption) (*Webhook, error) {
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, "/lospan.Lospan/CreateWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lospanClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, "/lospan.Lospan/ListWebhooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lospanClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, "/lospan.Lospan/DeleteWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lospanClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, "/lospan.Lospan/ListWebhookDeliveries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lospanClient) ListGateways(ctx context.Context, in *ListGatewaysRequest, opts ...grpc.CallOption) (*ListGatewaysResponse, error) {
 	out := new(ListGatewaysResponse)
 	err := c.cc.Invoke(ctx, "/lospan.Lospan/ListGateways", in, out, opts...)
@@ -151,6 +219,51 @@ func (c *lospanClient) DeleteGateway(ctx context.Context, in *DeleteGatewayReque
 	return out, nil
 }
 
+func (c *lospanClient) ListDeviceProfiles(ctx context.Context, in *ListDeviceProfilesRequest, opts ...grpc.CallOption) (*ListDeviceProfilesResponse, error) {
+	out := new(ListDeviceProfilesResponse)
+	err := c.cc.Invoke(ctx, "/lospan.Lospan/ListDeviceProfiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lospanClient) CreateDeviceProfile(ctx context.Context, in *DeviceProfile, opts ...grpc.CallOption) (*DeviceProfile, error) {
+	out := new(DeviceProfile)
+	err := c.cc.Invoke(ctx, "/lospan.Lospan/CreateDeviceProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lospanClient) GetDeviceProfile(ctx context.Context, in *GetDeviceProfileRequest, opts ...grpc.CallOption) (*DeviceProfile, error) {
+	out := new(DeviceProfile)
+	err := c.cc.Invoke(ctx, "/lospan.Lospan/GetDeviceProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lospanClient) UpdateDeviceProfile(ctx context.Context, in *DeviceProfile, opts ...grpc.CallOption) (*DeviceProfile, error) {
+	out := new(DeviceProfile)
+	err := c.cc.Invoke(ctx, "/lospan.Lospan/UpdateDeviceProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lospanClient) DeleteDeviceProfile(ctx context.Context, in *DeleteDeviceProfileRequest, opts ...grpc.CallOption) (*DeviceProfile, error) {
+	out := new(DeviceProfile)
+	err := c.cc.Invoke(ctx, "/lospan.Lospan/DeleteDeviceProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lospanClient) ListDevices(ctx context.Context, in *ListDeviceRequest, opts ...grpc.CallOption) (*ListDeviceResponse, error) {
 	out := new(ListDeviceResponse)
 	err := c.cc.Invoke(ctx, "/lospan.Lospan/ListDevices", in, out, opts...)
@@ -196,6 +309,15 @@ func (c *lospanClient) DeleteDevice(ctx context.Context, in *DeleteDeviceRequest
 	return out, nil
 }
 
+func (c *lospanClient) GetDeviceStats(ctx context.Context, in *GetDeviceStatsRequest, opts ...grpc.CallOption) (*DeviceStats, error) {
+	out := new(DeviceStats)
+	err := c.cc.Invoke(ctx, "/lospan.Lospan/GetDeviceStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lospanClient) Inbox(ctx context.Context, in *InboxRequest, opts ...grpc.CallOption) (*InboxResponse, error) {
 	out := new(InboxResponse)
 	err := c.cc.Invoke(ctx, "/lospan.Lospan/Inbox", in, out, opts...)
@@ -298,8 +420,18 @@ type LospanServer interface {
 	GetApplication(context.Context, *GetApplicationRequest) (*Application, error)
 	// CreateApplication creates a new application
 	CreateApplication(context.Context, *CreateApplicationRequest) (*Application, error)
+	// UpdateApplication updates the tag and the payload codec for an application
+	UpdateApplication(context.Context, *Application) (*Application, error)
 	// DeleteApplication removes an application.
 	DeleteApplication(context.Context, *DeleteApplicationRequest) (*Application, error)
+	// CreateWebhook adds a webhook to an application
+	CreateWebhook(context.Context, *Webhook) (*Webhook, error)
+	// ListWebhooks lists the webhooks for an application
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	// DeleteWebhook removes a webhook from an application
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*Webhook, error)
+	// ListWebhookDeliveries lists the delivery attempts for a webhook
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
 	// ListGateways lists the gateways in the network server. Each concentrator needs its own
 	// gateway definition
 	ListGateways(context.Context, *ListGatewaysRequest) (*ListGatewaysResponse, error)
@@ -311,6 +443,17 @@ type LospanServer interface {
 	UpdateGateway(context.Context, *Gateway) (*Gateway, error)
 	// DeleteGateway removes a gateway. When deleted the service won't accept data from it anymore.
 	DeleteGateway(context.Context, *DeleteGatewayRequest) (*Gateway, error)
+	// ListDeviceProfiles lists the device profiles
+	ListDeviceProfiles(context.Context, *ListDeviceProfilesRequest) (*ListDeviceProfilesResponse, error)
+	// CreateDeviceProfile creates a new device profile
+	CreateDeviceProfile(context.Context, *DeviceProfile) (*DeviceProfile, error)
+	// GetDeviceProfile returns a single device profile
+	GetDeviceProfile(context.Context, *GetDeviceProfileRequest) (*DeviceProfile, error)
+	// UpdateDeviceProfile updates a device profile. The new settings are applied to ABP devices
+	// right away and to OTAA devices when they join.
+	UpdateDeviceProfile(context.Context, *DeviceProfile) (*DeviceProfile, error)
+	// DeleteDeviceProfile removes a device profile. Profiles that are in use can't be removed.
+	DeleteDeviceProfile(context.Context, *DeleteDeviceProfileRequest) (*DeviceProfile, error)
 	// ListDevices retrieves the devices for the application
 	ListDevices(context.Context, *ListDeviceRequest) (*ListDeviceResponse, error)
 	// CreateDevice creates a new device
@@ -321,6 +464,8 @@ type LospanServer interface {
 	UpdateDevice(context.Context, *Device) (*Device, error)
 	// DeleteDevice removes a device from the application
 	DeleteDevice(context.Context, *DeleteDeviceRequest) (*Device, error)
+	// GetDeviceStats returns the frame statistics for a device
+	GetDeviceStats(context.Context, *GetDeviceStatsRequest) (*DeviceStats, error)
 	// Inbox lists the downstream messages from a device
 	Inbox(context.Context, *InboxRequest) (*InboxResponse, error)
 	// Outbox lists the downstream messages to a device. Sent messages are purged automatically
@@ -344,9 +489,24 @@ func (UnimplementedLospanServer) GetApplication(context.Context, *GetApplication
 func (UnimplementedLospanServer) CreateApplication(context.Context, *CreateApplicationRequest) (*Application, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateApplication not implemented")
 }
+func (UnimplementedLospanServer) UpdateApplication(context.Context, *Application) (*Application, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateApplication not implemented")
+}
 func (UnimplementedLospanServer) DeleteApplication(context.Context, *DeleteApplicationRequest) (*Application, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteApplication not implemented")
 }
+func (UnimplementedLospanServer) CreateWebhook(context.Context, *Webhook) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedLospanServer) ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedLospanServer) DeleteWebhook(context.Context, *DeleteWebhookRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedLospanServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
 func (UnimplementedLospanServer) ListGateways(context.Context, *ListGatewaysRequest) (*ListGatewaysResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListGateways not implemented")
 }
@@ -362,6 +522,21 @@ func (UnimplementedLospanServer) UpdateGateway(context.Context, *Gateway) (*Gate
 func (UnimplementedLospanServer) DeleteGateway(context.Context, *DeleteGatewayRequest) (*Gateway, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteGateway not implemented")
 }
+func (UnimplementedLospanServer) ListDeviceProfiles(context.Context, *ListDeviceProfilesRequest) (*ListDeviceProfilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeviceProfiles not implemented")
+}
+func (UnimplementedLospanServer) CreateDeviceProfile(context.Context, *DeviceProfile) (*DeviceProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDeviceProfile not implemented")
+}
+func (UnimplementedLospanServer) GetDeviceProfile(context.Context, *GetDeviceProfileRequest) (*DeviceProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeviceProfile not implemented")
+}
+func (UnimplementedLospanServer) UpdateDeviceProfile(context.Context, *DeviceProfile) (*DeviceProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDeviceProfile not implemented")
+}
+func (UnimplementedLospanServer) DeleteDeviceProfile(context.Context, *DeleteDeviceProfileRequest) (*DeviceProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDeviceProfile not implemented")
+}
 func (UnimplementedLospanServer) ListDevices(context.Context, *ListDeviceRequest) (*ListDeviceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListDevices not implemented")
 }
@@ -377,6 +552,9 @@ func (UnimplementedLospanServer) UpdateDevice(context.Context, *Device) (*Device
 func (UnimplementedLospanServer) DeleteDevice(context.Context, *DeleteDeviceRequest) (*Device, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteDevice not implemented")
 }
+func (UnimplementedLospanServer) GetDeviceStats(context.Context, *GetDeviceStatsRequest) (*DeviceStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeviceStats not implemented")
+}
 func (UnimplementedLospanServer) Inbox(context.Context, *InboxRequest) (*InboxResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Inbox not implemented")
 }
@@ -458,6 +636,24 @@ func _Lospan_CreateApplication_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lospan_UpdateApplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Application)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LospanServer).UpdateApplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lospan.Lospan/UpdateApplication",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LospanServer).UpdateApplication(ctx, req.(*Application))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lospan_DeleteApplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteApplicationRequest)
 	if err := dec(in); err != nil {
@@ -476,6 +672,78 @@ func _Lospan_DeleteApplication_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lospan_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Webhook)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LospanServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lospan.Lospan/CreateWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LospanServer).CreateWebhook(ctx, req.(*Webhook))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lospan_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LospanServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lospan.Lospan/ListWebhooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LospanServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lospan_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LospanServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lospan.Lospan/DeleteWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LospanServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lospan_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LospanServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lospan.Lospan/ListWebhookDeliveries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LospanServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lospan_ListGateways_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListGatewaysRequest)
 	if err := dec(in); err != nil {
@@ -566,6 +834,96 @@ func _Lospan_DeleteGateway_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lospan_ListDeviceProfiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeviceProfilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LospanServer).ListDeviceProfiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lospan.Lospan/ListDeviceProfiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LospanServer).ListDeviceProfiles(ctx, req.(*ListDeviceProfilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lospan_CreateDeviceProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeviceProfile)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LospanServer).CreateDeviceProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lospan.Lospan/CreateDeviceProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LospanServer).CreateDeviceProfile(ctx, req.(*DeviceProfile))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lospan_GetDeviceProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeviceProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LospanServer).GetDeviceProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lospan.Lospan/GetDeviceProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LospanServer).GetDeviceProfile(ctx, req.(*GetDeviceProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lospan_UpdateDeviceProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeviceProfile)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LospanServer).UpdateDeviceProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lospan.Lospan/UpdateDeviceProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LospanServer).UpdateDeviceProfile(ctx, req.(*DeviceProfile))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lospan_DeleteDeviceProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDeviceProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LospanServer).DeleteDeviceProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lospan.Lospan/DeleteDeviceProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LospanServer).DeleteDeviceProfile(ctx, req.(*DeleteDeviceProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lospan_ListDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListDeviceRequest)
 	if err := dec(in); err != nil {
@@ -656,6 +1014,24 @@ func _Lospan_DeleteDevice_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lospan_GetDeviceStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeviceStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LospanServer).GetDeviceStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lospan.Lospan/GetDeviceStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LospanServer).GetDeviceStats(ctx, req.(*GetDeviceStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lospan_Inbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(InboxRequest)
 	if err := dec(in); err != nil {
@@ -771,10 +1147,30 @@ var Lospan_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateApplication",
 			Handler:    _Lospan_CreateApplication_Handler,
 		},
+		{
+			MethodName: "UpdateApplication",
+			Handler:    _Lospan_UpdateApplication_Handler,
+		},
 		{
 			MethodName: "DeleteApplication",
 			Handler:    _Lospan_DeleteApplication_Handler,
 		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _Lospan_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _Lospan_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _Lospan_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _Lospan_ListWebhookDeliveries_Handler,
+		},
 		{
 			MethodName: "ListGateways",
 			Handler:    _Lospan_ListGateways_Handler,
@@ -795,6 +1191,26 @@ var Lospan_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteGateway",
 			Handler:    _Lospan_DeleteGateway_Handler,
 		},
+		{
+			MethodName: "ListDeviceProfiles",
+			Handler:    _Lospan_ListDeviceProfiles_Handler,
+		},
+		{
+			MethodName: "CreateDeviceProfile",
+			Handler:    _Lospan_CreateDeviceProfile_Handler,
+		},
+		{
+			MethodName: "GetDeviceProfile",
+			Handler:    _Lospan_GetDeviceProfile_Handler,
+		},
+		{
+			MethodName: "UpdateDeviceProfile",
+			Handler:    _Lospan_UpdateDeviceProfile_Handler,
+		},
+		{
+			MethodName: "DeleteDeviceProfile",
+			Handler:    _Lospan_DeleteDeviceProfile_Handler,
+		},
 		{
 			MethodName: "ListDevices",
 			Handler:    _Lospan_ListDevices_Handler,
@@ -815,6 +1231,10 @@ var Lospan_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteDevice",
 			Handler:    _Lospan_DeleteDevice_Handler,
 		},
+		{
+			MethodName: "GetDeviceStats",
+			Handler:    _Lospan_GetDeviceStats_Handler,
+		},
 		{
 			MethodName: "Inbox",
 			Handler:    _Lospan_Inbox_Handler,
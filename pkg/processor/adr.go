@@ -0,0 +1,266 @@
+package processor
+
+//
+//Copyright 2018 Telenor Digital AS
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+//
+import (
+	"sync"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+)
+
+const (
+	// adrHistorySize is the number of uplinks used when calculating the link margin
+	adrHistorySize = 20
+	// adrInstallationMargin is the margin (in dB) kept in reserve when adjusting the data rate
+	adrInstallationMargin = 10.0
+	// adrStepSize is the SNR difference (in dB) between each data rate or TX power step
+	adrStepSize = 3.0
+	// adrNbTrans is the number of transmissions for each uplink requested by the network
+	adrNbTrans = 1
+)
+
+// adrSample is the radio metadata for a single uplink
+type adrSample struct {
+	SNR  float32
+	RSSI int32
+}
+
+// adrState is the ADR state for a single device
+type adrState struct {
+	history  []adrSample
+	pending  *protocol.MACLinkADRReqBlock // The last LinkADRReq block sent to the device
+	channels band.ChannelMask             // Channel mask in the pending block (US915 and AU915)
+	accepted band.ChannelMask             // Channel mask accepted by the device (US915 and AU915)
+}
+
+// ADREngine keeps a rolling window of the SNR and RSSI values for each device
+// and calculates new data rate and TX power settings based on the link margin.
+type ADREngine struct {
+	devices map[protocol.EUI]*adrState
+	mutex   *sync.Mutex
+}
+
+// NewADREngine creates a new ADR engine instance
+func NewADREngine() *ADREngine {
+	return &ADREngine{
+		devices: make(map[protocol.EUI]*adrState),
+		mutex:   &sync.Mutex{},
+	}
+}
+
+func (a *ADREngine) state(deviceEUI protocol.EUI) *adrState {
+	s, exists := a.devices[deviceEUI]
+	if !exists {
+		s = &adrState{history: make([]adrSample, 0, adrHistorySize)}
+		a.devices[deviceEUI] = s
+	}
+	return s
+}
+
+// AddSample adds the radio metadata for an uplink to the device's history.
+// The oldest sample is discarded when the window is full.
+func (a *ADREngine) AddSample(deviceEUI protocol.EUI, radio server.RadioContext) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	s := a.state(deviceEUI)
+	if len(s.history) == adrHistorySize {
+		s.history = s.history[1:]
+	}
+	s.history = append(s.history, adrSample{SNR: radio.SNR, RSSI: radio.RSSI})
+}
+
+// Evaluate calculates the data rate and TX power the device should use. A
+// block of LinkADRReq commands is returned if the settings should be changed,
+// nil otherwise. The data rate and TX power are only adjusted when adr is set
+// and the history window is full. Devices in bands with fixed channel plans
+// (US915 and AU915) are also told to use the channels the gateway listens on
+// until they accept the channel mask. The TX power is limited by maxEIRP, the
+// max EIRP for the device in dBm. 0 means the band default.
+func (a *ADREngine) Evaluate(device model.Device, radio server.RadioContext, adr bool, maxEIRP uint8) *protocol.MACLinkADRReqBlock {
+	if radio.Band == nil {
+		return nil
+	}
+	currentDR, err := radio.Band.GetDataRate(radio.DataRate)
+	if err != nil {
+		lg.Warning("Unable to determine data rate for device %s: %v", device.DeviceEUI, err)
+		return nil
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	s := a.state(device.DeviceEUI)
+	config := radio.Band.Configuration()
+	dataRate, txPower := currentDR, device.TXPower
+	if adr && len(s.history) == adrHistorySize {
+		if requiredSNR, err := requiredSNR(radio.Band, currentDR); err == nil {
+			maxSNR := s.history[0].SNR
+			for _, v := range s.history {
+				if v.SNR > maxSNR {
+					maxSNR = v.SNR
+				}
+			}
+			margin := maxSNR - requiredSNR - adrInstallationMargin
+			dataRate, txPower = adjustDataRate(config.MaxADRDataRate, maxTxPower(radio.Band, maxEIRP), currentDR, device.TXPower, int(margin/adrStepSize))
+		}
+	}
+	setChannels := !radio.Channels.Empty() && radio.Channels != s.accepted
+	if dataRate == currentDR && txPower == device.TXPower && !setChannels {
+		return nil
+	}
+
+	var block *protocol.MACLinkADRReqBlock
+	if radio.Channels.Empty() {
+		chMask := device.ChannelMask
+		if chMask == 0 {
+			chMask = config.DefaultChannelMask
+		}
+		block = protocol.NewLinkADRReqBlock(newLinkADRReq(dataRate, txPower, chMask, 0))
+	} else {
+		// The channel mask is sent with every request to keep the
+		// device on the gateway's sub-band.
+		block = protocol.NewLinkADRReqBlock()
+		for _, m := range radio.Channels.LinkADRMasks() {
+			block.Requests = append(block.Requests, newLinkADRReq(dataRate, txPower, m.ChMask, m.ChMaskCntl))
+		}
+	}
+	if s.pending != nil && sameLinkADRReqs(s.pending, block) {
+		// Already requested; wait for the answer.
+		return nil
+	}
+	s.pending = block
+	s.channels = radio.Channels
+	return block
+}
+
+// requiredSNR returns the required SNR for the data rate. Only LoRa modulation
+// is adjusted so other data rates return an error.
+func requiredSNR(plan band.FrequencyPlan, dataRate uint8) (float32, error) {
+	encoding, err := plan.Encoding(dataRate)
+	if err != nil {
+		return 0, err
+	}
+	return encoding.RequiredSNR()
+}
+
+// newLinkADRReq creates a single LinkADRReq command
+func newLinkADRReq(dataRate, txPower uint8, chMask uint16, chMaskCntl uint8) *protocol.MACLinkADRReq {
+	req := protocol.NewDownlinkMACCommand(protocol.LinkADRReq).(*protocol.MACLinkADRReq)
+	req.DataRate = dataRate
+	req.TXPower = txPower
+	req.ChMask = chMask
+	req.Redundancy = protocol.LinkADRRedundancy(chMaskCntl, adrNbTrans)
+	return req
+}
+
+// sameLinkADRReqs checks if two LinkADRReq blocks have the same commands
+func sameLinkADRReqs(a, b *protocol.MACLinkADRReqBlock) bool {
+	if len(a.Requests) != len(b.Requests) {
+		return false
+	}
+	for i := range a.Requests {
+		if *a.Requests[i] != *b.Requests[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// maxTxPower returns the highest TX power index (ie the lowest power) ADR can
+// use for a device with the given max EIRP. The TX power index is an offset
+// from the device's max EIRP so devices with a lower max EIRP than the band
+// reach the band's lowest power at a lower index. 0 means the band default.
+func maxTxPower(plan band.FrequencyPlan, maxEIRP uint8) uint8 {
+	config := plan.Configuration()
+	bandMax, err := plan.TxPower(0)
+	if err != nil || maxEIRP == 0 || int(maxEIRP) >= int(bandMax) {
+		return config.MaxTxPower
+	}
+	bandMin, err := plan.TxPower(config.MaxTxPower)
+	if err != nil {
+		return config.MaxTxPower
+	}
+	for index := config.MaxTxPower; index > 0; index-- {
+		power, err := plan.TxPower(index)
+		if err == nil && int(maxEIRP)-int(bandMax-power) >= int(bandMin) {
+			return index
+		}
+	}
+	return 0
+}
+
+// adjustDataRate steps the data rate and TX power. Positive steps will
+// increase the data rate until it reaches maxDataRate, then lower the TX
+// power until it reaches maxTxPower. Negative steps will increase the TX
+// power. A TX power above maxTxPower is lowered to maxTxPower.
+func adjustDataRate(maxDataRate, maxTxPower, dataRate, txPower uint8, steps int) (uint8, uint8) {
+	if txPower > maxTxPower {
+		txPower = maxTxPower
+	}
+	for steps > 0 {
+		switch {
+		case dataRate < maxDataRate:
+			dataRate++
+		case txPower < maxTxPower:
+			txPower++
+		default:
+			return dataRate, txPower
+		}
+		steps--
+	}
+	for steps < 0 && txPower > 0 {
+		txPower--
+		steps++
+	}
+	return dataRate, txPower
+}
+
+// Answer handles a LinkADRAns from the device. If the device accepted the
+// pending request the device is updated with the new settings and true is
+// returned. The device answers each command in a block but the answers are
+// identical so the first one is used.
+func (a *ADREngine) Answer(device *model.Device, ans *protocol.MACLinkADRAns) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	s := a.state(device.DeviceEUI)
+	block := s.pending
+	s.pending = nil
+	// Start over with a fresh set of samples. This also works as a back-off
+	// if the device rejects the request.
+	s.history = s.history[:0]
+	if block == nil || len(block.Requests) == 0 {
+		lg.Warning("Got LinkADRAns from device %s but no LinkADRReq is pending", device.DeviceEUI)
+		return false
+	}
+	if !ans.PowerACK || !ans.DataRateACK || !ans.ChannelMaskACK {
+		lg.Warning("Device %s rejected LinkADRReq (power: %t, data rate: %t, channel mask: %t)",
+			device.DeviceEUI, ans.PowerACK, ans.DataRateACK, ans.ChannelMaskACK)
+		return false
+	}
+	// The data rate and TX power in the last command applies
+	req := block.Requests[len(block.Requests)-1]
+	device.DataRate = req.DataRate
+	device.TXPower = req.TXPower
+	device.ChannelMask = req.ChMask
+	s.accepted = s.channels
+	return true
+}
@@ -0,0 +1,275 @@
+package processor
+
+//
+//Copyright 2018 Telenor Digital AS
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+//
+import (
+	"testing"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+	"github.com/stretchr/testify/require"
+)
+
+func newADRRadioContext(dataRate string, snr float32) server.RadioContext {
+	eu868, _ := band.NewBand(band.EU868Band)
+	return server.RadioContext{
+		Band:     eu868,
+		DataRate: dataRate,
+		SNR:      snr,
+		RSSI:     -80,
+	}
+}
+
+func TestADREngine(t *testing.T) {
+	assert := require.New(t)
+
+	adr := NewADREngine()
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x0102030405060708)
+
+	radio := newADRRadioContext("SF12BW125", 10)
+	for i := 0; i < adrHistorySize-1; i++ {
+		adr.AddSample(device.DeviceEUI, radio)
+		assert.Nil(adr.Evaluate(device, radio, true, 0), "Should not adjust until the history is full")
+	}
+	adr.AddSample(device.DeviceEUI, radio)
+
+	// Margin is 10 - (-20) - 10 = 20 dB => 6 steps. 5 steps brings the data
+	// rate from DR0 to DR5, the last step lowers the TX power.
+	block := adr.Evaluate(device, radio, true, 0)
+	assert.NotNil(block)
+	assert.Equal(protocol.LinkADRReq, block.ID())
+	assert.Len(block.Requests, 1)
+	req := block.Requests[0]
+	assert.Equal(uint8(5), req.DataRate)
+	assert.Equal(uint8(1), req.TXPower)
+	assert.Equal(uint16(0x0007), req.ChMask)
+	assert.Equal(uint8(0), req.ChMaskCntl())
+	assert.Equal(uint8(adrNbTrans), req.NbTrans())
+
+	assert.Nil(adr.Evaluate(device, radio, true, 0), "Should not repeat a pending request")
+
+	ans := protocol.NewUplinkMACCommand(protocol.LinkADRAns).(*protocol.MACLinkADRAns)
+	ans.PowerACK = true
+	ans.DataRateACK = true
+	ans.ChannelMaskACK = true
+	assert.True(adr.Answer(&device, ans))
+	assert.Equal(uint8(5), device.DataRate)
+	assert.Equal(uint8(1), device.TXPower)
+	assert.Equal(uint16(0x0007), device.ChannelMask)
+
+	assert.False(adr.Answer(&device, ans), "Answer without a pending request should be ignored")
+
+	// A weak signal at the highest data rate should increase the TX power
+	radio = newADRRadioContext("SF7BW125", -10)
+	for i := 0; i < adrHistorySize; i++ {
+		adr.AddSample(device.DeviceEUI, radio)
+	}
+	block = adr.Evaluate(device, radio, true, 0)
+	assert.NotNil(block)
+	req = block.Requests[0]
+	assert.Equal(uint8(5), req.DataRate)
+	assert.Equal(uint8(0), req.TXPower)
+
+	ans.PowerACK = false
+	assert.False(adr.Answer(&device, ans), "Rejected request should not update the device")
+	assert.Equal(uint8(1), device.TXPower)
+}
+
+func TestADREngineChannelMask(t *testing.T) {
+	assert := require.New(t)
+
+	adr := NewADREngine()
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x0102030405060709)
+	device.TXPower = 2
+
+	us915, _ := band.NewBand(band.US915Band)
+	radio := server.RadioContext{
+		Band:     us915,
+		DataRate: "SF10BW125",
+		SNR:      -10,
+		Channels: band.ChannelMask{0xFF00, 0, 0, 0, 0x0002},
+	}
+
+	// The channel mask is sent without the ADR bit and before the history is
+	// full. The data rate and TX power are kept.
+	block := adr.Evaluate(device, radio, false, 0)
+	assert.NotNil(block)
+	assert.Len(block.Requests, 2)
+	assert.Equal(uint8(7), block.Requests[0].ChMaskCntl())
+	assert.Equal(uint16(0x0002), block.Requests[0].ChMask)
+	assert.Equal(uint8(0), block.Requests[1].ChMaskCntl())
+	assert.Equal(uint16(0xFF00), block.Requests[1].ChMask)
+	for _, req := range block.Requests {
+		assert.Equal(uint8(0), req.DataRate)
+		assert.Equal(uint8(2), req.TXPower)
+	}
+	assert.Nil(adr.Evaluate(device, radio, false, 0), "Should not repeat a pending request")
+
+	ans := protocol.NewUplinkMACCommand(protocol.LinkADRAns).(*protocol.MACLinkADRAns)
+	ans.PowerACK = true
+	ans.DataRateACK = true
+	ans.ChannelMaskACK = false
+	assert.False(adr.Answer(&device, ans))
+	assert.NotNil(adr.Evaluate(device, radio, false, 0), "Rejected channel mask should be sent again")
+
+	ans.ChannelMaskACK = true
+	assert.True(adr.Answer(&device, ans))
+	assert.Nil(adr.Evaluate(device, radio, false, 0), "Accepted channel mask should not be sent again")
+
+	// Data rate changes include the channel mask
+	radio.SNR = 10
+	for i := 0; i < adrHistorySize; i++ {
+		adr.AddSample(device.DeviceEUI, radio)
+	}
+	block = adr.Evaluate(device, radio, true, 0)
+	assert.NotNil(block)
+	assert.Len(block.Requests, 2)
+	assert.Equal(us915.Configuration().MaxADRDataRate, block.Requests[1].DataRate)
+
+	// New channels on the gateway are sent to the device
+	ans.ChannelMaskACK = true
+	assert.True(adr.Answer(&device, ans))
+	radio.Channels = band.ChannelMask{0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0x00FF}
+	block = adr.Evaluate(device, radio, false, 0)
+	assert.NotNil(block)
+	assert.Len(block.Requests, 1)
+	assert.Equal(uint8(6), block.Requests[0].ChMaskCntl())
+}
+
+func TestAdjustDataRate(t *testing.T) {
+	assert := require.New(t)
+	eu868, _ := band.NewBand(band.EU868Band)
+	config := eu868.Configuration()
+
+	dr, tx := adjustDataRate(config.MaxADRDataRate, config.MaxTxPower, 0, 0, 0)
+	assert.Equal([]uint8{0, 0}, []uint8{dr, tx})
+
+	dr, tx = adjustDataRate(config.MaxADRDataRate, config.MaxTxPower, 4, 0, 100)
+	assert.Equal([]uint8{config.MaxADRDataRate, config.MaxTxPower}, []uint8{dr, tx})
+
+	dr, tx = adjustDataRate(config.MaxADRDataRate, config.MaxTxPower, 3, 2, -5)
+	assert.Equal([]uint8{3, 0}, []uint8{dr, tx})
+
+	dr, tx = adjustDataRate(config.MaxADRDataRate, 3, 5, 5, 0)
+	assert.Equal([]uint8{5, 3}, []uint8{dr, tx}, "TX power should be limited")
+}
+
+func TestMaxTxPower(t *testing.T) {
+	assert := require.New(t)
+	eu868, _ := band.NewBand(band.EU868Band)
+	us915, _ := band.NewBand(band.US915Band)
+
+	assert.Equal(eu868.Configuration().MaxTxPower, maxTxPower(eu868, 0))
+	assert.Equal(eu868.Configuration().MaxTxPower, maxTxPower(eu868, 20))
+	// 14 dBm - (20 - 8) dB reaches the band's lowest power (2 dBm) at index 3
+	assert.Equal(uint8(3), maxTxPower(eu868, 14))
+	assert.Equal(uint8(0), maxTxPower(eu868, 1))
+	assert.Equal(uint8(5), maxTxPower(us915, 20))
+}
+
+func TestADREngineMaxEIRP(t *testing.T) {
+	assert := require.New(t)
+
+	adr := NewADREngine()
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x010203040506070A)
+
+	// A strong signal at the highest data rate lowers the TX power as far as
+	// the max EIRP allows.
+	radio := newADRRadioContext("SF7BW125", 20)
+	for i := 0; i < adrHistorySize; i++ {
+		adr.AddSample(device.DeviceEUI, radio)
+	}
+	block := adr.Evaluate(device, radio, true, 14)
+	assert.NotNil(block)
+	assert.Equal(uint8(5), block.Requests[0].DataRate)
+	assert.Equal(uint8(3), block.Requests[0].TXPower)
+}
+
+func TestMACProcessorADR(t *testing.T) {
+	assert := require.New(t)
+
+	store := NewStorageTestContext()
+	frameOutput := server.NewFrameOutputBuffer()
+	context := &server.Context{Storage: store, FrameOutput: &frameOutput}
+	deviceEUI, _ := protocol.EUIFromString("75-0A-09-3A-2C-22-69-F3")
+	device, err := store.GetDeviceByEUI(deviceEUI)
+	assert.NoError(err)
+
+	input := make(chan server.LoRaMessage)
+	defer close(input)
+	macprocessor := NewMACProcessor(context, input)
+	go macprocessor.Start()
+
+	send := func(msg server.LoRaMessage) {
+		input <- msg
+		select {
+		case <-macprocessor.CommandNotifier():
+		case <-time.After(100 * time.Millisecond):
+			assert.Fail("No notification from MAC processor")
+		}
+	}
+	uplink := func(adr bool, fopts []protocol.MACCommand) server.LoRaMessage {
+		msg := makeLoRaMessage(true, protocol.UnconfirmedDataUp, fopts, nil)
+		msg.Payload.MACPayload.FHDR.FCtrl.ADR = adr
+		msg.FrameContext.Device = device
+		msg.FrameContext.GatewayContext.Radio = newADRRadioContext("SF12BW125", 10)
+		return msg
+	}
+
+	for i := 0; i < adrHistorySize; i++ {
+		send(uplink(false, nil))
+	}
+	_, err = frameOutput.GetPHYPayloadForDevice(&device, &server.FrameContext{})
+	assert.Error(err, "Should not request ADR changes when the ADR flag is cleared")
+
+	for i := 0; i < adrHistorySize; i++ {
+		send(uplink(true, nil))
+	}
+	payload, err := frameOutput.GetPHYPayloadForDevice(&device, &server.FrameContext{})
+	assert.NoError(err)
+	assert.True(payload.MACPayload.MACCommands.Contains(protocol.LinkADRReq))
+
+	ans := protocol.NewUplinkMACCommand(protocol.LinkADRAns).(*protocol.MACLinkADRAns)
+	ans.PowerACK = true
+	ans.DataRateACK = true
+	ans.ChannelMaskACK = true
+	send(uplink(true, []protocol.MACCommand{ans}))
+
+	updated, err := store.GetDeviceByEUI(deviceEUI)
+	assert.NoError(err)
+	assert.Equal(uint8(5), updated.DataRate)
+	assert.Equal(uint8(1), updated.TXPower)
+	assert.Equal(uint16(0x0007), updated.ChannelMask)
+
+	// Devices with ADR disabled in the profile are left alone
+	profile := model.NewDeviceProfile()
+	profile.Name = "no-adr"
+	profile.ADREnabled = false
+	profile, err = store.CreateDeviceProfile(profile)
+	assert.NoError(err)
+	device.ProfileID = profile.ID
+	for i := 0; i < adrHistorySize; i++ {
+		send(uplink(true, nil))
+	}
+	_, err = frameOutput.GetPHYPayloadForDevice(&device, &server.FrameContext{})
+	assert.Error(err, "Should not request ADR changes when the profile disables ADR")
+}
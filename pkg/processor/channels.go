@@ -0,0 +1,184 @@
+package processor
+
+import (
+	"math"
+	"sync"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+)
+
+// channelAnswerUplinks is the number of uplinks to wait for an answer to a
+// NewChannelReq or DlChannelReq before the request is sent again
+const channelAnswerUplinks = 8
+
+// channelState is the channel setup state for a single device
+type channelState struct {
+	pending  protocol.MACCommand   // The last NewChannelReq or DlChannelReq sent to the device
+	index    int                   // Index of the pending channel in the device's extra channels
+	channel  band.Channel          // The channel in the pending request
+	uplinks  int                   // Uplinks since the pending request was sent
+	rejected map[band.Channel]bool // Channels the device has rejected
+}
+
+// ChannelManager sets up the extra channels on devices in bands with dynamic
+// channel plans. OTAA devices get the channels in the JoinAccept message and
+// the manager sends NewChannelReq and DlChannelReq commands to devices that
+// don't have the channels, one channel at a time.
+type ChannelManager struct {
+	devices map[protocol.EUI]*channelState
+	mutex   *sync.Mutex
+}
+
+// NewChannelManager creates a new channel manager instance
+func NewChannelManager() *ChannelManager {
+	return &ChannelManager{
+		devices: make(map[protocol.EUI]*channelState),
+		mutex:   &sync.Mutex{},
+	}
+}
+
+func (c *ChannelManager) state(deviceEUI protocol.EUI) *channelState {
+	s, exists := c.devices[deviceEUI]
+	if !exists {
+		s = &channelState{rejected: make(map[band.Channel]bool)}
+		c.devices[deviceEUI] = s
+	}
+	return s
+}
+
+// joinChannels returns the channels the device gets through the CFList in the
+// JoinAccept message. The downlink frequencies must be set with DlChannelReq.
+func joinChannels(channels []band.Channel) []band.Channel {
+	var ret []band.Channel
+	for _, ch := range channels {
+		ret = append(ret, band.Channel{Frequency: ch.Frequency})
+	}
+	return ret
+}
+
+// toFreq100 converts a frequency in MHz into the 100 Hz units used by the MAC
+// commands
+func toFreq100(frequency float32) uint32 {
+	return uint32(math.Round(float64(frequency) * 1e4))
+}
+
+// Evaluate compares the device's channels with the extra channels and returns
+// a NewChannelReq or DlChannelReq if the device is missing a channel. Nil is
+// returned if the device is up to date or there's a pending request.
+func (c *ChannelManager) Evaluate(device model.Device, plan band.FrequencyPlan, channels []band.Channel) protocol.MACCommand {
+	if !band.DynamicChannels(plan) {
+		return nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s := c.state(device.DeviceEUI)
+	if s.pending != nil {
+		s.uplinks++
+		if s.uplinks < channelAnswerUplinks {
+			return nil
+		}
+		lg.Info("No answer to %T from device %s. Sending it again", s.pending, device.DeviceEUI)
+		s.pending = nil
+	}
+
+	config := plan.Configuration()
+	first := len(config.MandatoryEndDeviceChannels)
+	count := len(channels)
+	if len(device.Channels) > count {
+		count = len(device.Channels)
+	}
+	for i := 0; i < count; i++ {
+		var want, have band.Channel
+		if i < len(channels) {
+			want = channels[i]
+		}
+		if i < len(device.Channels) {
+			have = device.Channels[i]
+		}
+		if s.rejected[want] {
+			continue
+		}
+		if want.Frequency != have.Frequency {
+			// A frequency of 0 removes the channel
+			req := protocol.NewDownlinkMACCommand(protocol.NewChannelReq).(*protocol.MACNewChannelReq)
+			req.ChIndex = uint8(first + i)
+			req.Freq = toFreq100(want.Frequency)
+			if want.Frequency != 0 {
+				req.MaxDR = config.MaxADRDataRate
+			}
+			s.pending, s.index, s.channel, s.uplinks = req, i, want, 0
+			return req
+		}
+		if want.Frequency != 0 && want.DownlinkFrequency != have.DownlinkFrequency {
+			req := protocol.NewDownlinkMACCommand(protocol.DlChannelReq).(*protocol.MACDlChannelReq)
+			req.ChIndex = uint8(first + i)
+			req.Freq = toFreq100(want.RX1Frequency())
+			s.pending, s.index, s.channel, s.uplinks = req, i, want, 0
+			return req
+		}
+	}
+	return nil
+}
+
+// setChannel sets the channel at the index in the device's extra channels.
+// Removed channels at the end of the list are dropped.
+func setChannel(device *model.Device, index int, channel band.Channel) {
+	for len(device.Channels) <= index {
+		device.Channels = append(device.Channels, band.Channel{})
+	}
+	device.Channels[index] = channel
+	for len(device.Channels) > 0 && device.Channels[len(device.Channels)-1].Frequency == 0 {
+		device.Channels = device.Channels[:len(device.Channels)-1]
+	}
+}
+
+// NewChannelAns handles a NewChannelAns from the device. The device's
+// channels are updated and true is returned if the device accepted the
+// pending NewChannelReq.
+func (c *ChannelManager) NewChannelAns(device *model.Device, ans *protocol.MACNewChannelAns) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s := c.state(device.DeviceEUI)
+	if _, ok := s.pending.(*protocol.MACNewChannelReq); !ok {
+		lg.Warning("Got NewChannelAns from device %s but no NewChannelReq is pending", device.DeviceEUI)
+		return false
+	}
+	s.pending = nil
+	if !ans.ChannelFrequencyOK || !ans.DataRangeOK {
+		lg.Warning("Device %s rejected channel %s (frequency: %t, data rate range: %t)",
+			device.DeviceEUI, s.channel, ans.ChannelFrequencyOK, ans.DataRangeOK)
+		s.rejected[s.channel] = true
+		return false
+	}
+	// NewChannelReq resets the downlink frequency to the uplink frequency
+	setChannel(device, s.index, band.Channel{Frequency: s.channel.Frequency})
+	return true
+}
+
+// DlChannelAns handles a DlChannelAns from the device. The device's channels
+// are updated and true is returned if the device accepted the pending
+// DlChannelReq.
+func (c *ChannelManager) DlChannelAns(device *model.Device, ans *protocol.MACDlChannelAns) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s := c.state(device.DeviceEUI)
+	if _, ok := s.pending.(*protocol.MACDlChannelReq); !ok {
+		lg.Warning("Got DlChannelAns from device %s but no DlChannelReq is pending", device.DeviceEUI)
+		return false
+	}
+	s.pending = nil
+	if !ans.UplinkFrequencyExists || !ans.ChannelFrequencyOK {
+		lg.Warning("Device %s rejected downlink frequency for channel %s (uplink exists: %t, frequency: %t)",
+			device.DeviceEUI, s.channel, ans.UplinkFrequencyExists, ans.ChannelFrequencyOK)
+		s.rejected[s.channel] = true
+		return false
+	}
+	setChannel(device, s.index, s.channel)
+	return true
+}
@@ -0,0 +1,105 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelManager(t *testing.T) {
+	assert := require.New(t)
+
+	eu868, _ := band.NewBand(band.EU868Band)
+	channels := []band.Channel{{Frequency: 867.1}, {Frequency: 867.3, DownlinkFrequency: 869.525}}
+
+	manager := NewChannelManager()
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x0102030405060708)
+
+	// First channel is added after the three default channels
+	cmd := manager.Evaluate(device, eu868, channels)
+	assert.NotNil(cmd)
+	req, ok := cmd.(*protocol.MACNewChannelReq)
+	assert.True(ok)
+	assert.Equal(uint8(3), req.ChIndex)
+	assert.Equal(uint32(8671000), req.Freq)
+	assert.Equal(uint8(5), req.MaxDR)
+
+	assert.Nil(manager.Evaluate(device, eu868, channels), "Should wait for the answer")
+
+	ans := protocol.NewUplinkMACCommand(protocol.NewChannelAns).(*protocol.MACNewChannelAns)
+	ans.ChannelFrequencyOK = true
+	ans.DataRangeOK = true
+	assert.True(manager.NewChannelAns(&device, ans))
+	assert.Equal([]band.Channel{{Frequency: 867.1}}, device.Channels)
+
+	// Second channel needs a NewChannelReq followed by a DlChannelReq
+	req = manager.Evaluate(device, eu868, channels).(*protocol.MACNewChannelReq)
+	assert.Equal(uint8(4), req.ChIndex)
+	assert.Equal(uint32(8673000), req.Freq)
+	assert.True(manager.NewChannelAns(&device, ans))
+
+	dlReq, ok := manager.Evaluate(device, eu868, channels).(*protocol.MACDlChannelReq)
+	assert.True(ok)
+	assert.Equal(uint8(4), dlReq.ChIndex)
+	assert.Equal(uint32(8695250), dlReq.Freq)
+
+	dlAns := protocol.NewUplinkMACCommand(protocol.DlChannelAns).(*protocol.MACDlChannelAns)
+	dlAns.UplinkFrequencyExists = true
+	dlAns.ChannelFrequencyOK = true
+	assert.True(manager.DlChannelAns(&device, dlAns))
+	assert.Equal(channels, device.Channels)
+
+	assert.Nil(manager.Evaluate(device, eu868, channels), "Device should be up to date")
+
+	// Removed channels are set to 0
+	req = manager.Evaluate(device, eu868, channels[:1]).(*protocol.MACNewChannelReq)
+	assert.Equal(uint8(4), req.ChIndex)
+	assert.Equal(uint32(0), req.Freq)
+	assert.True(manager.NewChannelAns(&device, ans))
+	assert.Equal(channels[:1], device.Channels)
+
+	// Fixed channel plans are left alone
+	us915, _ := band.NewBand(band.US915Band)
+	assert.Nil(manager.Evaluate(model.NewDevice(), us915, channels))
+}
+
+func TestChannelManagerRejected(t *testing.T) {
+	assert := require.New(t)
+
+	eu868, _ := band.NewBand(band.EU868Band)
+	channels := []band.Channel{{Frequency: 867.1}}
+
+	manager := NewChannelManager()
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x0102030405060709)
+
+	assert.NotNil(manager.Evaluate(device, eu868, channels))
+	ans := protocol.NewUplinkMACCommand(protocol.NewChannelAns).(*protocol.MACNewChannelAns)
+	assert.False(manager.NewChannelAns(&device, ans))
+	assert.Len(device.Channels, 0)
+	assert.Nil(manager.Evaluate(device, eu868, channels), "Rejected channels should not be sent again")
+
+	// Answers without a pending request are ignored
+	assert.False(manager.NewChannelAns(&device, ans))
+}
+
+func TestChannelManagerResend(t *testing.T) {
+	assert := require.New(t)
+
+	eu868, _ := band.NewBand(band.EU868Band)
+	channels := []band.Channel{{Frequency: 867.1}}
+
+	manager := NewChannelManager()
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x010203040506070A)
+
+	assert.NotNil(manager.Evaluate(device, eu868, channels))
+	for i := 0; i < channelAnswerUplinks-1; i++ {
+		assert.Nil(manager.Evaluate(device, eu868, channels))
+	}
+	assert.NotNil(manager.Evaluate(device, eu868, channels), "Request should be sent again")
+}
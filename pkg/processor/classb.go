@@ -0,0 +1,133 @@
+package processor
+
+import (
+	"sync"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+)
+
+// classBState is the class B channel setup state for a single device
+type classBState struct {
+	pending  protocol.MACCommand              // The last PingSlotChannelReq or BeaconFreqReq sent to the device
+	ping     band.DownlinkParameters          // The ping slot channel in the pending request
+	beacon   float32                          // The beacon frequency in the pending request
+	uplinks  int                              // Uplinks since the pending request was sent
+	rejected map[band.DownlinkParameters]bool // Ping slot channels the device has rejected
+}
+
+// ClassBManager sets up the ping slot channel and the beacon frequency on
+// class B devices. The manager sends PingSlotChannelReq and BeaconFreqReq
+// commands to devices that don't use the configured channels, one command at a
+// time. A frequency of 0 resets the device to the band defaults.
+type ClassBManager struct {
+	devices map[protocol.EUI]*classBState
+	mutex   *sync.Mutex
+}
+
+// NewClassBManager creates a new class B manager instance
+func NewClassBManager() *ClassBManager {
+	return &ClassBManager{
+		devices: make(map[protocol.EUI]*classBState),
+		mutex:   &sync.Mutex{},
+	}
+}
+
+func (c *ClassBManager) state(deviceEUI protocol.EUI) *classBState {
+	s, exists := c.devices[deviceEUI]
+	if !exists {
+		s = &classBState{rejected: make(map[band.DownlinkParameters]bool)}
+		c.devices[deviceEUI] = s
+	}
+	return s
+}
+
+// Evaluate compares the device's ping slot channel and beacon frequency with
+// the configured ones and returns a PingSlotChannelReq or BeaconFreqReq if
+// they differ. Nil is returned for devices that aren't class B devices, if the
+// device is up to date or if there's a pending request.
+func (c *ClassBManager) Evaluate(device model.Device, plan band.FrequencyPlan, ping band.DownlinkParameters, beacon float32) protocol.MACCommand {
+	if device.Class != model.ClassB || plan == nil {
+		return nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s := c.state(device.DeviceEUI)
+	if s.pending != nil {
+		s.uplinks++
+		if s.uplinks < channelAnswerUplinks {
+			return nil
+		}
+		lg.Info("No answer to %T from device %s. Sending it again", s.pending, device.DeviceEUI)
+		s.pending = nil
+	}
+
+	current := band.DownlinkParameters{Frequency: device.PingFrequency}
+	if device.PingFrequency != 0 {
+		current.DataRate = device.PingDataRate
+	}
+	if ping != current && !s.rejected[ping] {
+		req := protocol.NewDownlinkMACCommand(protocol.PingSlotChannelReq).(*protocol.MACPingSlotChannelReq)
+		req.Frequency = toFreq100(ping.Frequency)
+		// The data rate is the lower nibble of the DR field. The band
+		// default is used when the channel is reset.
+		req.MinDR = ping.DataRate
+		if ping.Frequency == 0 {
+			req.MinDR = plan.Configuration().PingSlotDataRate
+		}
+		s.pending, s.ping, s.uplinks = req, ping, 0
+		return req
+	}
+	if beacon != device.BeaconFrequency {
+		req := protocol.NewDownlinkMACCommand(protocol.BeaconFreqReq).(*protocol.MACBeaconFreqReq)
+		req.Frequency = toFreq100(beacon)
+		s.pending, s.beacon, s.uplinks = req, beacon, 0
+		return req
+	}
+	return nil
+}
+
+// PingSlotFreqAns handles a PingSlotFreqAns from the device. The device's ping
+// slot channel is updated and true is returned if the device accepted the
+// pending PingSlotChannelReq.
+func (c *ClassBManager) PingSlotFreqAns(device *model.Device, ans *protocol.MACPingSlotFreqAns) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s := c.state(device.DeviceEUI)
+	if _, ok := s.pending.(*protocol.MACPingSlotChannelReq); !ok {
+		lg.Warning("Got PingSlotFreqAns from device %s but no PingSlotChannelReq is pending", device.DeviceEUI)
+		return false
+	}
+	s.pending = nil
+	if !ans.ChannelFrequencyOK || !ans.DataRangeOK {
+		lg.Warning("Device %s rejected ping slot channel %.3f MHz/DR%d (frequency: %t, data rate: %t)",
+			device.DeviceEUI, s.ping.Frequency, s.ping.DataRate, ans.ChannelFrequencyOK, ans.DataRangeOK)
+		s.rejected[s.ping] = true
+		return false
+	}
+	device.PingFrequency = s.ping.Frequency
+	device.PingDataRate = s.ping.DataRate
+	return true
+}
+
+// BeaconFreqAns handles a BeaconFreqAns from the device. The device's beacon
+// frequency is updated and true is returned if there's a pending
+// BeaconFreqReq. The answer has no status so the device is assumed to accept
+// the frequency.
+func (c *ClassBManager) BeaconFreqAns(device *model.Device, ans *protocol.MACBeaconFreqAns) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s := c.state(device.DeviceEUI)
+	if _, ok := s.pending.(*protocol.MACBeaconFreqReq); !ok {
+		lg.Warning("Got BeaconFreqAns from device %s but no BeaconFreqReq is pending", device.DeviceEUI)
+		return false
+	}
+	s.pending = nil
+	device.BeaconFrequency = s.beacon
+	return true
+}
@@ -0,0 +1,71 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassBManager(t *testing.T) {
+	assert := require.New(t)
+
+	eu868, _ := band.NewBand(band.EU868Band)
+	ping := band.DownlinkParameters{Frequency: 869.3, DataRate: 4}
+
+	manager := NewClassBManager()
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x0102030405060708)
+	assert.Equal(uint8(model.DefaultPingPeriodicity), device.PingPeriodicity)
+
+	assert.Nil(manager.Evaluate(device, eu868, ping, 869.3), "Class A devices don't need class B channels")
+	device.Class = model.ClassB
+	assert.Nil(manager.Evaluate(device, eu868, band.DownlinkParameters{}, 0), "Device uses the band defaults")
+
+	// Ping slot channel is set first
+	req, ok := manager.Evaluate(device, eu868, ping, 869.3).(*protocol.MACPingSlotChannelReq)
+	assert.True(ok)
+	assert.Equal(uint32(8693000), req.Frequency)
+	assert.Equal(uint8(4), req.MinDR)
+	assert.Nil(manager.Evaluate(device, eu868, ping, 869.3), "Should wait for the answer")
+
+	ans := protocol.NewUplinkMACCommand(protocol.PingSlotFreqAns).(*protocol.MACPingSlotFreqAns)
+	ans.ChannelFrequencyOK = true
+	ans.DataRangeOK = true
+	assert.True(manager.PingSlotFreqAns(&device, ans))
+	assert.Equal(float32(869.3), device.PingFrequency)
+	assert.Equal(uint8(4), device.PingDataRate)
+
+	// ...then the beacon frequency
+	beaconReq, ok := manager.Evaluate(device, eu868, ping, 869.3).(*protocol.MACBeaconFreqReq)
+	assert.True(ok)
+	assert.Equal(uint32(8693000), beaconReq.Frequency)
+	assert.False(manager.PingSlotFreqAns(&device, ans), "No PingSlotChannelReq is pending")
+	assert.True(manager.BeaconFreqAns(&device, &protocol.MACBeaconFreqAns{}))
+	assert.Equal(float32(869.3), device.BeaconFrequency)
+	assert.Nil(manager.Evaluate(device, eu868, ping, 869.3), "Device should be up to date")
+
+	// Resetting the channel uses the band's default data rate
+	req = manager.Evaluate(device, eu868, band.DownlinkParameters{}, 869.3).(*protocol.MACPingSlotChannelReq)
+	assert.Equal(uint32(0), req.Frequency)
+	assert.Equal(uint8(3), req.MinDR)
+	assert.True(manager.PingSlotFreqAns(&device, ans))
+	assert.Equal(float32(0), device.PingFrequency)
+	assert.Equal(uint8(0), device.PingDataRate)
+
+	// Rejected channels aren't requested again
+	other := band.DownlinkParameters{Frequency: 869.1, DataRate: 3}
+	assert.NotNil(manager.Evaluate(device, eu868, other, 869.3))
+	ans.ChannelFrequencyOK = false
+	assert.False(manager.PingSlotFreqAns(&device, ans))
+	assert.Nil(manager.Evaluate(device, eu868, other, 869.3))
+
+	// Requests are sent again if there's no answer
+	beaconReq = manager.Evaluate(device, eu868, other, 869.1).(*protocol.MACBeaconFreqReq)
+	for i := 1; i < channelAnswerUplinks; i++ {
+		assert.Nil(manager.Evaluate(device, eu868, other, 869.1))
+	}
+	assert.NotNil(manager.Evaluate(device, eu868, other, 869.1))
+}
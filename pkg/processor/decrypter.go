@@ -1,8 +1,11 @@
 package processor
 
 import (
+	"errors"
 	"time"
 
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/codec"
 	"github.com/lab5e/lospan/pkg/lg"
 	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/protocol"
@@ -17,23 +20,74 @@ type Decrypter struct {
 	input     <-chan server.LoRaMessage
 	macOutput chan server.LoRaMessage
 	context   *server.Context
+	codecs    *codec.Cache
 }
 
-func (d *Decrypter) validFrameCounter(device *model.Device, decoded server.LoRaMessage) bool {
+// defaultMaxFCntGap is the maximum frame counter gap used when the band isn't
+// known.
+const defaultMaxFCntGap = 16384
+
+// fullFrameCounter reconstructs the 32-bit frame counter from the lower 16 bits
+// sent by the device. The upper 16 bits are taken from the next expected frame
+// counter and adjusted if the lower 16 bits have rolled over. Only counters
+// within maxGap of the expected counter are assumed to be rolled over.
+func fullFrameCounter(device model.Device, fCnt uint16, maxGap uint32) uint32 {
+	next := device.FCntUp
+	ret := next&0xFFFF0000 | uint32(fCnt)
+	switch {
+	case ret < next && ret+0x10000-next <= maxGap:
+		ret += 0x10000
+	case ret > next && ret-next > maxGap && ret >= 0x10000:
+		// This is a frame from before the last rollover
+		ret -= 0x10000
+	}
+	if ret < next && device.RelaxedCounter {
+		// The device has reset the frame counter
+		return uint32(fCnt)
+	}
+	return ret
+}
+
+// maxFCntGap returns the maximum frame counter gap for the band the message
+// is received on.
+func maxFCntGap(decoded server.LoRaMessage) uint32 {
+	if band := decoded.FrameContext.GatewayContext.Radio.Band; band != nil && band.Configuration().MaxFCntGap > 0 {
+		return band.Configuration().MaxFCntGap
+	}
+	return defaultMaxFCntGap
+}
+
+// validFrameCounter checks the frame counter for the message. The stats are
+// updated with lost, replayed and rejected frames.
+func (d *Decrypter) validFrameCounter(device *model.Device, decoded server.LoRaMessage, stats *model.DeviceStats) bool {
 	// Ignore frame counter for JoinRequest messages since that will be reset
 	// when the device have joined.
 	if decoded.Payload.MHDR.MType == protocol.JoinRequest {
 		return true
 	}
+	fCnt := decoded.Payload.MACPayload.FHDR.FCnt
 	// Ensure frame counters are valid if it has strict checks
 	if !device.RelaxedCounter {
 		// Ignore frame counters that are less than the stored value. Bigger ones
 		// means that we've lost one or more message from the device.
-		if device.FCntUp > decoded.Payload.MACPayload.FHDR.FCnt {
+		if device.FCntUp > fCnt {
 			lg.Info("Frame counter check failed for device %s. Expected %d but got %d. Ignoring message.",
-				device.DeviceEUI, device.FCntUp, decoded.Payload.MACPayload.FHDR.FCnt)
+				device.DeviceEUI, device.FCntUp, fCnt)
+			stats.Replayed++
+			return false
+		}
+	}
+	// The gap isn't checked for the first frame from the device since ABP
+	// devices might be added with an existing frame counter.
+	if device.FCntUp > 0 && fCnt > device.FCntUp {
+		gap := fCnt - device.FCntUp
+		if gap > maxFCntGap(decoded) {
+			lg.Info("Frame counter gap for device %s is too big. Expected %d but got %d. Ignoring message.",
+				device.DeviceEUI, device.FCntUp, fCnt)
+			stats.Rejected++
 			return false
 		}
+		stats.Lost += gap
 	}
 
 	// Issue debug warning if there's a mismatch between expected and actual frame counter
@@ -45,13 +99,31 @@ func (d *Decrypter) validFrameCounter(device *model.Device, decoded server.LoRaM
 	return true
 }
 
+// retransmission returns true if the message is a retransmission of the last
+// confirmed uplink from the device. The device repeats the frame with the same
+// frame counter until it gets an ACK.
+func retransmission(device *model.Device, decoded server.LoRaMessage) bool {
+	return decoded.Payload.MHDR.MType == protocol.ConfirmedDataUp &&
+		device.FCntUp > 0 && decoded.Payload.MACPayload.FHDR.FCnt == device.FCntUp-1
+}
+
 // processMessage forwards the message to the proper application
 func (d *Decrypter) processMessage(device *model.Device, decoded server.LoRaMessage, matchingDevices int) {
 	// Frame counters are tricky if there's more than one device since two (or more) devices
 	// will send different frame counters. But this will be treated like any other message. With strict checks in place you *will* loose messages.
 
+	retransmitted := retransmission(device, decoded)
+	stats := model.NewDeviceStats(device.DeviceEUI)
+	stats.LastUpdated = time.Now().UnixNano()
+	valid := retransmitted || d.validFrameCounter(device, decoded, &stats)
+	if valid && !retransmitted {
+		stats.Received++
+	}
+	if err := d.context.Storage.AddDeviceStats(stats); err != nil {
+		lg.Warning("Unable to update stats for device with EUI %s: %v", device.DeviceEUI, err)
+	}
 	// Frame counter checks does not apply for JoinRequest messages
-	if !d.validFrameCounter(device, decoded) {
+	if !valid {
 		return
 	}
 
@@ -68,7 +140,24 @@ func (d *Decrypter) processMessage(device *model.Device, decoded server.LoRaMess
 			lg.Warning("Unable to update frame counters for device with EUI %s: %v", device.DeviceEUI, err)
 		}
 	}
-	decoded.Payload.Decrypt(device.NwkSKey, device.AppSKey)
+	if device.MACVersion == model.LoRaWAN11 {
+		// MAC commands are encrypted with the network session encryption key
+		if err := decoded.Payload.DecryptFOpts(device.NwkSEncKey, decoded.FrameContext.GatewayContext.RawMessage); err != nil {
+			lg.Warning("Unable to decrypt FOpts for device with EUI %s: %v", device.DeviceEUI, err)
+		}
+		decoded.Payload.Decrypt(device.NwkSEncKey, device.AppSKey)
+	} else {
+		decoded.Payload.Decrypt(device.NwkSKey, device.AppSKey)
+	}
+	if retransmitted {
+		// The frame has already been forwarded to the application. Just ACK
+		// it again.
+		lg.Info("Retransmitted frame from device %s (FCnt=%d). Sending ACK", device.DeviceEUI, decoded.Payload.MACPayload.FHDR.FCnt)
+		decoded.FrameContext.Device = *device
+		d.context.FrameOutput.SetMessageAckFlag(device.DeviceEUI, true)
+		d.macOutput <- decoded
+		return
+	}
 	deviceData := model.UpstreamMessage{
 		DeviceEUI:  device.DeviceEUI,
 		Timestamp:  decoded.FrameContext.GatewayContext.ReceivedAt.UnixNano(),
@@ -79,6 +168,8 @@ func (d *Decrypter) processMessage(device *model.Device, decoded server.LoRaMess
 		Frequency:  decoded.FrameContext.GatewayContext.Radio.Frequency,
 		DataRate:   decoded.FrameContext.GatewayContext.Radio.DataRate,
 		DevAddr:    device.DevAddr,
+		FPort:      decoded.Payload.MACPayload.FPort,
+		Receptions: decoded.FrameContext.GatewayReceptions(),
 	}
 
 	if err := d.context.Storage.CreateUpstreamMessage(device.DeviceEUI, deviceData); err != nil {
@@ -104,6 +195,13 @@ func (d *Decrypter) processMessage(device *model.Device, decoded server.LoRaMess
 		// Update messages with matching upstream flags.
 		lg.Info("Setting ack time for message from %s (FC=%d)", device.DeviceEUI, decoded.Payload.MACPayload.FHDR.FCnt)
 		d.context.Storage.UpdateMessageAckTime(device.DeviceEUI, decoded.Payload.MACPayload.FHDR.FCnt, time.Now().UnixNano())
+		d.publish(&server.PayloadMessage{
+			Type:         server.AckEvent,
+			FCnt:         decoded.Payload.MACPayload.FHDR.FCnt,
+			Device:       *device,
+			Application:  application,
+			FrameContext: decoded.FrameContext,
+		})
 	} else {
 		// reset sent time for messages that should be acked.
 		if err := d.context.Storage.ResetActiveAcks(device.DeviceEUI); err != nil {
@@ -119,6 +217,17 @@ func (d *Decrypter) processMessage(device *model.Device, decoded server.LoRaMess
 		decoded.FrameContext.PayloadCreate = msg.CreatedTime
 		lg.Info("Set sent time for message from %s. Fcnt=%d", device.DeviceEUI, decoded.Payload.MACPayload.FHDR.FCnt)
 		d.context.Storage.SetMessageSentTime(device.DeviceEUI, msg.CreatedTime, time.Now().UnixNano(), decoded.Payload.MACPayload.FHDR.FCnt)
+		// The downlink event follows the frame and is published when the
+		// gateway has sent it.
+		decoded.FrameContext.Downlink = &server.PayloadMessage{
+			Type:         server.DownlinkEvent,
+			Payload:      msg.Payload(),
+			FPort:        msg.Port,
+			Device:       *device,
+			Application:  application,
+			FrameContext: decoded.FrameContext,
+			Downlink:     msg,
+		}
 	}
 	if err != nil && err != storage.ErrNotFound {
 		lg.Warning("Unable to retrieve downstream message for device %s: %v", device.DeviceEUI, err)
@@ -126,13 +235,73 @@ func (d *Decrypter) processMessage(device *model.Device, decoded server.LoRaMess
 
 	d.macOutput <- decoded
 
-	d.context.AppRouter.Publish(application.AppEUI, &server.PayloadMessage{
+	uplink := &server.PayloadMessage{
+		Type:         server.UplinkEvent,
 		Payload:      decoded.Payload.MACPayload.FRMPayload,
+		FPort:        decoded.Payload.MACPayload.FPort,
+		FCnt:         decoded.Payload.MACPayload.FHDR.FCnt,
 		Device:       *device,
 		Application:  application,
 		FrameContext: decoded.FrameContext,
-	})
+	}
+	d.decodePayload(uplink)
+	d.publish(uplink)
+}
 
+// decodePayload decodes the uplink payload with the application's decoder.
+// Decoder errors are reported on the message.
+func (d *Decrypter) decodePayload(msg *server.PayloadMessage) {
+	if msg.Application.Decoder == "" || len(msg.Payload) == 0 {
+		return
+	}
+	c, err := d.codecs.Get(msg.Application.Decoder, "")
+	if err == nil {
+		msg.Object, err = c.Decode(msg.FPort, msg.Payload)
+	}
+	if err != nil {
+		lg.Info("Unable to decode payload from device %s: %v", msg.Device.DeviceEUI, err)
+		msg.CodecError = err.Error()
+	}
+}
+
+// publish sends an event to the application's subscribers. Events are
+// ignored if there's no application router.
+func (d *Decrypter) publish(msg *server.PayloadMessage) {
+	if d.context.AppRouter == nil {
+		return
+	}
+	d.context.AppRouter.Publish(msg.Application.AppEUI, msg)
+}
+
+// calculateMIC calculates the uplink MIC for the device. The MIC for LoRaWAN
+// 1.1 devices includes the frame counter of the acknowledged downlink and the
+// data rate and channel for the uplink. The channel is looked up from the
+// uplink frequency.
+func calculateMIC(device model.Device, decoded server.LoRaMessage, message []byte) (uint32, error) {
+	if device.MACVersion != model.LoRaWAN11 {
+		return decoded.Payload.CalculateMIC(device.NwkSKey, message)
+	}
+	confFCnt := uint16(0)
+	if decoded.Payload.MACPayload.FHDR.FCtrl.ACK {
+		// The frame counter is increased when a downlink is sent. Confirmed
+		// downlinks carry application data and use the application counter.
+		confFCnt = uint16(device.AFCntDown - 1)
+	}
+	radio := decoded.FrameContext.GatewayContext.Radio
+	if radio.Band == nil {
+		return 0, errors.New("no band for frame")
+	}
+	txDR, err := radio.Band.GetDataRate(radio.DataRate)
+	if err != nil {
+		return 0, err
+	}
+	// The channel is the device's channel number for the uplink frequency,
+	// not the concentrator's IF channel.
+	txCh, err := band.UplinkChannel(radio.Band, device.Channels, radio.Frequency)
+	if err != nil {
+		return 0, err
+	}
+	return decoded.Payload.CalculateUplinkMIC11(device.NwkSKey, device.SNwkSIntKey, confFCnt, txDR, uint8(txCh), message)
 }
 
 func (d *Decrypter) verifyAndDecryptMessage(decoded server.LoRaMessage) {
@@ -151,30 +320,49 @@ func (d *Decrypter) verifyAndDecryptMessage(decoded server.LoRaMessage) {
 		return
 	}
 
+	maxGap := maxFCntGap(decoded)
+
 	// Find all devices with matching devAddr and correct NwkSKey (ie the MIC is
 	// valid) and forward it to the appropriate application. Issue warnings
 	// wrt key for devices if there's more than one device with the same key.
+	// The full frame counter depends on the device so it is kept for each
+	// of the matching devices.
 	var matchingDevices []model.Device
+	var frameCounters []uint32
+	fCnt := uint16(decoded.Payload.MACPayload.FHDR.FCnt)
 	checked := 0
 	for _, dev := range devices {
 		checked++
 		lg.Debug("Testing MIC for device %s", dev.DeviceEUI)
-		mic, err := decoded.Payload.CalculateMIC(dev.NwkSKey, rawMessage[0:len(rawMessage)-4])
+		decoded.Payload.MACPayload.FHDR.FCnt = fullFrameCounter(dev, fCnt, maxGap)
+		mic, err := calculateMIC(dev, decoded, rawMessage[0:len(rawMessage)-4])
 		if err != nil {
 			lg.Info("Unable to calculate MIC for payload: %v (payload=%v) ", err, decoded.Payload)
 			continue
 		}
 		if mic == decoded.Payload.MIC {
 			matchingDevices = append(matchingDevices, dev)
+			frameCounters = append(frameCounters, decoded.Payload.MACPayload.FHDR.FCnt)
 		}
 	}
 	if len(matchingDevices) == 0 && checked > 0 {
 		lg.Info("MIC validation failed for device with DevAddr: %s", decoded.Payload.MACPayload.FHDR.DevAddr)
+		// There's no way of telling which device sent the frame so all of the
+		// devices with the same DevAddr get the failure.
+		for _, dev := range devices {
+			stats := model.NewDeviceStats(dev.DeviceEUI)
+			stats.MICFailed = 1
+			stats.LastUpdated = time.Now().UnixNano()
+			if err := d.context.Storage.AddDeviceStats(stats); err != nil {
+				lg.Warning("Unable to update stats for device with EUI %s: %v", dev.DeviceEUI, err)
+			}
+		}
 		return
 	}
 
 	// We now have a list of devices
-	for _, dev := range matchingDevices {
+	for i, dev := range matchingDevices {
+		decoded.Payload.MACPayload.FHDR.FCnt = frameCounters[i]
 		d.processMessage(&dev, decoded, len(matchingDevices))
 	}
 }
@@ -221,5 +409,6 @@ func NewDecrypter(context *server.Context, input <-chan server.LoRaMessage) *Dec
 		input:     input,
 		macOutput: make(chan server.LoRaMessage),
 		context:   context,
+		codecs:    codec.NewCache(),
 	}
 }
@@ -16,9 +16,13 @@ package processor
 //limitations under the License.
 //
 import (
+	"bytes"
+	"encoding/binary"
 	"testing"
 	"time"
 
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/protocol"
 	"github.com/lab5e/lospan/pkg/server"
 )
@@ -102,3 +106,345 @@ func TestDecrypterProcessing(t *testing.T) {
 	}
 	close(input)
 }
+
+func TestDecrypter11(t *testing.T) {
+	s := NewStorageTestContext()
+	fNwkSIntKey, _ := protocol.AESKeyFromString("01020304 01020304 01020304 01020304")
+	sNwkSIntKey, _ := protocol.AESKeyFromString("05060708 05060708 05060708 05060708")
+	nwkSEncKey, _ := protocol.AESKeyFromString("090A0B0C 090A0B0C 090A0B0C 090A0B0C")
+	appSKey, _ := protocol.AESKeyFromString("0D0E0F10 0D0E0F10 0D0E0F10 0D0E0F10")
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x0101010101010101)
+	device.AppEUI = TestAppEUI
+	device.DevAddr = protocol.DevAddr{NwkID: 1, NwkAddr: 0x11}
+	device.State = model.PersonalizedDevice
+	device.MACVersion = model.LoRaWAN11
+	device.NwkSKey = fNwkSIntKey
+	device.SNwkSIntKey = sNwkSIntKey
+	device.NwkSEncKey = nwkSEncKey
+	device.AppSKey = appSKey
+	if err := s.CreateDevice(device, TestAppEUI); err != nil {
+		t.Fatal(err)
+	}
+
+	router := server.NewEventRouter[protocol.EUI, *server.PayloadMessage](5)
+	context := server.Context{Storage: s, AppRouter: &router}
+	input := make(chan server.LoRaMessage)
+	defer close(input)
+	decrypter := NewDecrypter(&context, input)
+	go decrypter.Start()
+	msgOutput := context.AppRouter.Subscribe(TestAppEUI)
+
+	msg := protocol.NewPHYPayload(protocol.UnconfirmedDataUp)
+	msg.MACPayload.FHDR.DevAddr = device.DevAddr
+	msg.MACPayload.FHDR.FCnt = 1
+	ind := protocol.NewUplinkMACCommand(protocol.RekeyInd).(*protocol.MACRekeyInd)
+	ind.Minor = 1
+	msg.MACPayload.FHDR.FOpts.Add(ind)
+	msg.MACPayload.FPort = 1
+	msg.MACPayload.FRMPayload = []byte{1, 2, 3, 4}
+	buf, err := msg.EncodeMessage11(sNwkSIntKey, nwkSEncKey, appSKey, 1, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Replace the downlink MIC with an uplink MIC for SF7BW125 (DR5) on channel 2
+	// (868.5 MHz)
+	mic, err := msg.CalculateUplinkMIC11(fNwkSIntKey, sNwkSIntKey, 0, 5, 2, buf[:len(buf)-4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary.LittleEndian.PutUint32(buf[len(buf)-4:], mic)
+
+	decoded := protocol.NewPHYPayload(protocol.Proprietary)
+	if err := decoded.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	// The concentrator's IF channel isn't the device's channel number
+	eu868, _ := band.NewBand(band.EU868Band)
+	input <- server.LoRaMessage{Payload: decoded, FrameContext: server.FrameContext{
+		GatewayContext: server.GatewayPacket{
+			RawMessage: buf,
+			Radio:      server.RadioContext{Band: eu868, DataRate: "SF7BW125", Frequency: 868.5, Channel: 6},
+		},
+	}}
+
+	select {
+	case out := <-decrypter.Output():
+		if !out.Payload.MACPayload.FHDR.FOpts.Contains(protocol.RekeyInd) {
+			t.Fatal("Expected RekeyInd in decrypted FOpts")
+		}
+	case <-time.After(300 * time.Millisecond):
+		t.Fatal("No output from decrypter")
+	}
+	select {
+	case p := <-msgOutput:
+		if !bytes.Equal(p.Payload, []byte{1, 2, 3, 4}) {
+			t.Fatalf("Incorrect payload: %v", p.Payload)
+		}
+	case <-time.After(300 * time.Millisecond):
+		t.Fatal("No payload from decrypter")
+	}
+}
+
+func TestFullFrameCounter(t *testing.T) {
+	tests := []struct {
+		next     uint32
+		fCnt     uint16
+		relaxed  bool
+		expected uint32
+	}{
+		{0, 0, false, 0},
+		{10, 12, false, 12},
+		{0x1FFFF, 0xFFFF, false, 0x1FFFF},
+		{0x1FFFF, 0x0003, false, 0x20003},
+		{0x2FFF0, 0x0100, false, 0x30100},
+		{0x10005, 0xFFFF, false, 0xFFFF},
+		{0x12345, 0x0000, false, 0x10000},
+		{0x12345, 0x0000, true, 0x0000},
+		{0x12345, 0x2345, true, 0x12345},
+	}
+	for _, test := range tests {
+		device := model.Device{FCntUp: test.next, RelaxedCounter: test.relaxed}
+		if fc := fullFrameCounter(device, test.fCnt, defaultMaxFCntGap); fc != test.expected {
+			t.Errorf("Expected frame counter 0x%x but got 0x%x (next: 0x%x, fCnt: 0x%x)", test.expected, fc, test.next, test.fCnt)
+		}
+	}
+}
+
+func TestDecrypterFrameCounterRollover(t *testing.T) {
+	s := NewStorageTestContext()
+	nwkSKey, _ := protocol.AESKeyFromString("01020304 01020304 01020304 01020304")
+	appSKey, _ := protocol.AESKeyFromString("05060708 05060708 05060708 05060708")
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x0202020202020202)
+	device.AppEUI = TestAppEUI
+	device.DevAddr = protocol.DevAddr{NwkID: 1, NwkAddr: 0x22}
+	device.State = model.PersonalizedDevice
+	device.NwkSKey = nwkSKey
+	device.AppSKey = appSKey
+	device.FCntUp = 0xFFFE
+	if err := s.CreateDevice(device, TestAppEUI); err != nil {
+		t.Fatal(err)
+	}
+
+	router := server.NewEventRouter[protocol.EUI, *server.PayloadMessage](5)
+	context := server.Context{Storage: s, AppRouter: &router}
+	input := make(chan server.LoRaMessage)
+	defer close(input)
+	decrypter := NewDecrypter(&context, input)
+	go decrypter.Start()
+	msgOutput := context.AppRouter.Subscribe(TestAppEUI)
+
+	for _, fCnt := range []uint32{0xFFFF, 0x10000, 0x10001} {
+		msg := protocol.NewPHYPayload(protocol.UnconfirmedDataUp)
+		msg.MACPayload.FHDR.DevAddr = device.DevAddr
+		msg.MACPayload.FHDR.FCnt = fCnt
+		msg.MACPayload.FPort = 1
+		msg.MACPayload.FRMPayload = []byte{1, 2, 3, 4}
+		buf, err := msg.EncodeMessage(nwkSKey, appSKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded := protocol.NewPHYPayload(protocol.Proprietary)
+		if err := decoded.UnmarshalBinary(buf); err != nil {
+			t.Fatal(err)
+		}
+		input <- server.LoRaMessage{Payload: decoded, FrameContext: server.FrameContext{
+			GatewayContext: server.GatewayPacket{RawMessage: buf, ReceivedAt: time.Now()},
+		}}
+
+		select {
+		case out := <-decrypter.Output():
+			if out.Payload.MACPayload.FHDR.FCnt != fCnt {
+				t.Fatalf("Expected frame counter 0x%x but got 0x%x", fCnt, out.Payload.MACPayload.FHDR.FCnt)
+			}
+		case <-time.After(300 * time.Millisecond):
+			t.Fatalf("No output from decrypter for frame counter 0x%x", fCnt)
+		}
+		select {
+		case p := <-msgOutput:
+			if !bytes.Equal(p.Payload, []byte{1, 2, 3, 4}) {
+				t.Fatalf("Incorrect payload: %v", p.Payload)
+			}
+		case <-time.After(300 * time.Millisecond):
+			t.Fatal("No payload from decrypter")
+		}
+		updated, err := s.GetDeviceByEUI(device.DeviceEUI)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated.FCntUp != fCnt+1 {
+			t.Fatalf("Expected FCntUp to be 0x%x but it is 0x%x", fCnt+1, updated.FCntUp)
+		}
+	}
+}
+
+func TestDecrypterDeviceStats(t *testing.T) {
+	s := NewStorageTestContext()
+	nwkSKey, _ := protocol.AESKeyFromString("01020304 01020304 01020304 01020304")
+	appSKey, _ := protocol.AESKeyFromString("05060708 05060708 05060708 05060708")
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x0303030303030303)
+	device.AppEUI = TestAppEUI
+	device.DevAddr = protocol.DevAddr{NwkID: 1, NwkAddr: 0x33}
+	device.State = model.PersonalizedDevice
+	device.NwkSKey = nwkSKey
+	device.AppSKey = appSKey
+	if err := s.CreateDevice(device, TestAppEUI); err != nil {
+		t.Fatal(err)
+	}
+
+	router := server.NewEventRouter[protocol.EUI, *server.PayloadMessage](5)
+	context := server.Context{Storage: s, AppRouter: &router}
+	input := make(chan server.LoRaMessage)
+	defer close(input)
+	decrypter := NewDecrypter(&context, input)
+	go decrypter.Start()
+	go func() {
+		for range decrypter.Output() {
+		}
+	}()
+
+	send := func(fCnt uint32, key protocol.AESKey) {
+		msg := protocol.NewPHYPayload(protocol.UnconfirmedDataUp)
+		msg.MACPayload.FHDR.DevAddr = device.DevAddr
+		msg.MACPayload.FHDR.FCnt = fCnt
+		msg.MACPayload.FPort = 1
+		msg.MACPayload.FRMPayload = []byte{1, 2, 3, 4}
+		buf, err := msg.EncodeMessage(key, appSKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded := protocol.NewPHYPayload(protocol.Proprietary)
+		if err := decoded.UnmarshalBinary(buf); err != nil {
+			t.Fatal(err)
+		}
+		before, _ := s.GetDeviceStats(device.DeviceEUI)
+		input <- server.LoRaMessage{Payload: decoded, FrameContext: server.FrameContext{
+			GatewayContext: server.GatewayPacket{RawMessage: buf, ReceivedAt: time.Now()},
+		}}
+		for i := 0; i < 30; i++ {
+			after, _ := s.GetDeviceStats(device.DeviceEUI)
+			if after.LastUpdated != before.LastUpdated {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	send(1, nwkSKey)
+	send(5, nwkSKey)
+	send(5, nwkSKey)
+	send(6+defaultMaxFCntGap+1, nwkSKey)
+	send(6, protocol.AESKey{})
+
+	stats, err := s.GetDeviceStats(device.DeviceEUI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Received != 2 || stats.Lost != 3 || stats.Replayed != 1 || stats.MICFailed != 1 || stats.Rejected != 1 {
+		t.Fatalf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestDecrypterDecodePayload(t *testing.T) {
+	decrypter := NewDecrypter(&server.Context{}, nil)
+
+	msg := &server.PayloadMessage{Payload: []byte{0x01, 0x02}, FPort: 2}
+	decrypter.decodePayload(msg)
+	if msg.Object != nil || msg.CodecError != "" {
+		t.Fatal("Messages shouldn't be decoded when there's no decoder")
+	}
+
+	msg.Application.Decoder = `{"port": fPort, "value": payload.uint16(0)}`
+	decrypter.decodePayload(msg)
+	if msg.Object == nil || msg.Object.AsMap()["value"] != 258.0 || msg.Object.AsMap()["port"] != 2.0 || msg.CodecError != "" {
+		t.Fatalf("Incorrect decoded payload: %v (%s)", msg.Object, msg.CodecError)
+	}
+
+	// Errors are reported on the message
+	msg = &server.PayloadMessage{Payload: []byte{0x01}, FPort: 2, Application: msg.Application}
+	decrypter.decodePayload(msg)
+	if msg.Object != nil || msg.CodecError == "" {
+		t.Fatal("Expected codec error on message")
+	}
+}
+
+func TestDecrypterRetransmission(t *testing.T) {
+	s := NewStorageTestContext()
+	nwkSKey, _ := protocol.AESKeyFromString("01020304 01020304 01020304 01020304")
+	appSKey, _ := protocol.AESKeyFromString("05060708 05060708 05060708 05060708")
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x0404040404040404)
+	device.AppEUI = TestAppEUI
+	device.DevAddr = protocol.DevAddr{NwkID: 1, NwkAddr: 0x44}
+	device.State = model.PersonalizedDevice
+	device.NwkSKey = nwkSKey
+	device.AppSKey = appSKey
+	if err := s.CreateDevice(device, TestAppEUI); err != nil {
+		t.Fatal(err)
+	}
+
+	router := server.NewEventRouter[protocol.EUI, *server.PayloadMessage](5)
+	frameOutput := server.NewFrameOutputBuffer()
+	context := server.Context{Storage: s, AppRouter: &router, FrameOutput: &frameOutput}
+	input := make(chan server.LoRaMessage)
+	defer close(input)
+	decrypter := NewDecrypter(&context, input)
+	go decrypter.Start()
+	events := router.Subscribe(TestAppEUI)
+
+	msg := protocol.NewPHYPayload(protocol.ConfirmedDataUp)
+	msg.MACPayload.FHDR.DevAddr = device.DevAddr
+	msg.MACPayload.FHDR.FCnt = 1
+	msg.MACPayload.FPort = 1
+	msg.MACPayload.FRMPayload = []byte{1, 2, 3, 4}
+	buf, err := msg.EncodeMessage(nwkSKey, appSKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	send := func() {
+		decoded := protocol.NewPHYPayload(protocol.Proprietary)
+		if err := decoded.UnmarshalBinary(buf); err != nil {
+			t.Fatal(err)
+		}
+		input <- server.LoRaMessage{Payload: decoded, FrameContext: server.FrameContext{
+			GatewayContext: server.GatewayPacket{RawMessage: buf, ReceivedAt: time.Now()},
+		}}
+		select {
+		case <-decrypter.Output():
+		case <-time.After(time.Second):
+			t.Fatal("No MAC output for confirmed uplink")
+		}
+	}
+
+	uplinks := 0
+	countUplinks := func() {
+		for {
+			select {
+			case ev := <-events:
+				if ev.Type == server.UplinkEvent {
+					uplinks++
+				}
+			case <-time.After(100 * time.Millisecond):
+				return
+			}
+		}
+	}
+	send()
+	countUplinks()
+	// The retransmission is sent to the scheduler (to get the ACK) but not
+	// to the application
+	send()
+	countUplinks()
+	if uplinks != 1 {
+		t.Fatalf("Expected one uplink event but got %d", uplinks)
+	}
+	stats, err := s.GetDeviceStats(device.DeviceEUI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Received != 1 || stats.Replayed != 0 {
+		t.Fatalf("Retransmission should not count as a replay: %+v", stats)
+	}
+}
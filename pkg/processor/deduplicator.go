@@ -0,0 +1,85 @@
+package processor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/server"
+)
+
+// Deduplicator is the process that merges copies of the same frame received
+// by several gateways into a single message. The first copy of a frame opens
+// a collection window. Copies that arrive within the window are merged
+// into the message. Copies that arrive after the window closes but before the
+// frame expires (ie late copies from gateways with slow backhaul) are
+// dropped. Byte-identical frames after that are treated as retransmissions of
+// confirmed uplinks. The gateway with the best reception is used for the
+// downlink.
+type Deduplicator struct {
+	input      <-chan server.LoRaMessage
+	output     chan server.LoRaMessage
+	context    *server.Context
+	receptions server.ReceptionTracker
+	window     time.Duration
+}
+
+// Start launches the deduplicator. It will terminate when the input channel
+// is closed. On exit the output channel will be closed.
+func (d *Deduplicator) Start() {
+	wg := &sync.WaitGroup{}
+	for m := range d.input {
+		if !d.receptions.Add(m.FrameContext.GatewayContext) {
+			lg.Debug("Dropping duplicate frame from gateway %s", m.FrameContext.GatewayContext.Gateway.GatewayEUI)
+			continue
+		}
+		wg.Add(1)
+		go func(msg server.LoRaMessage) {
+			defer wg.Done()
+			time.Sleep(d.window)
+			receptions := d.receptions.Close(msg.FrameContext.GatewayContext.RawMessage)
+			if len(receptions) > 0 {
+				msg.FrameContext.Receptions = receptions
+				msg.FrameContext.GatewayContext = bestReception(receptions)
+			}
+			d.output <- msg
+		}(m)
+	}
+	wg.Wait()
+	lg.Debug("Input channel for Deduplicator closed. Terminating")
+	close(d.output)
+}
+
+// bestReception returns the packet with the best SNR. RSSI is used if the SNR
+// is the same.
+func bestReception(receptions []server.GatewayPacket) server.GatewayPacket {
+	best := receptions[0]
+	for _, v := range receptions[1:] {
+		if v.Radio.SNR > best.Radio.SNR || (v.Radio.SNR == best.Radio.SNR && v.Radio.RSSI > best.Radio.RSSI) {
+			best = v
+		}
+	}
+	return best
+}
+
+// Output returns the output channel from the deduplicator. The channel will
+// receive one message for each frame.
+func (d *Deduplicator) Output() <-chan server.LoRaMessage {
+	return d.output
+}
+
+// NewDeduplicator creates a new deduplicator. The collection window is set by
+// the configuration.
+func NewDeduplicator(context *server.Context, input <-chan server.LoRaMessage) *Deduplicator {
+	window := time.Duration(0)
+	if context.Config != nil {
+		window = context.Config.DedupWindow
+	}
+	return &Deduplicator{
+		input:      input,
+		output:     make(chan server.LoRaMessage),
+		context:    context,
+		receptions: server.NewReceptionTracker(window + server.DefaultReceptionExpiry),
+		window:     window,
+	}
+}
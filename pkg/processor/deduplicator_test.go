@@ -0,0 +1,93 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeduplicatorChannels(t *testing.T) {
+	context := server.Context{}
+	input := make(chan server.LoRaMessage)
+	dedup := NewDeduplicator(&context, input)
+
+	go dedup.Start()
+
+	close(input)
+	select {
+	case _, ok := <-dedup.Output():
+		if ok {
+			t.Fatal("Shouldn't be able to read from the channel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Channel not closed")
+	}
+}
+
+func TestDeduplicatorMerge(t *testing.T) {
+	assert := require.New(t)
+
+	config := server.NewDefaultConfig()
+	config.DedupWindow = 50 * time.Millisecond
+	context := server.Context{Config: config}
+	input := make(chan server.LoRaMessage)
+	dedup := NewDeduplicator(&context, input)
+	dedup.receptions = server.NewReceptionTracker(500 * time.Millisecond)
+
+	go dedup.Start()
+	defer close(input)
+
+	newCopy := func(raw []byte, gw int64, snr float32, rssi int32) server.LoRaMessage {
+		ret := server.LoRaMessage{}
+		ret.FrameContext.GatewayContext = server.GatewayPacket{
+			RawMessage: raw,
+			Gateway:    server.GatewayContext{GatewayEUI: protocol.EUIFromInt64(gw)},
+			Radio:      server.RadioContext{SNR: snr, RSSI: rssi},
+		}
+		return ret
+	}
+
+	frame := []byte{1, 2, 3, 4, 5}
+	input <- newCopy(frame, 1, 2, -100)
+	input <- newCopy(frame, 2, 7, -110)
+	input <- newCopy(frame, 3, 7, -90)
+
+	select {
+	case msg := <-dedup.Output():
+		assert.Len(msg.FrameContext.Receptions, 3)
+		assert.Equal(protocol.EUIFromInt64(3), msg.FrameContext.GatewayContext.Gateway.GatewayEUI, "Expected gateway with best SNR and RSSI")
+	case <-time.After(time.Second):
+		assert.Fail("No message from deduplicator")
+	}
+
+	// Copies arriving after the window are dropped until the frame expires
+	input <- newCopy(frame, 4, 10, -80)
+	select {
+	case <-dedup.Output():
+		assert.Fail("Late copies should be dropped")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// ...and frames arriving after that are retransmissions of confirmed
+	// uplinks
+	time.Sleep(400 * time.Millisecond)
+	input <- newCopy(frame, 4, 10, -80)
+	select {
+	case msg := <-dedup.Output():
+		assert.Len(msg.FrameContext.Receptions, 1)
+		assert.Equal(protocol.EUIFromInt64(4), msg.FrameContext.GatewayContext.Gateway.GatewayEUI)
+	case <-time.After(time.Second):
+		assert.Fail("Retransmitted frame should be forwarded")
+	}
+
+	input <- newCopy([]byte{5, 4, 3, 2, 1}, 1, 2, -100)
+	select {
+	case msg := <-dedup.Output():
+		assert.Len(msg.FrameContext.Receptions, 1)
+	case <-time.After(time.Second):
+		assert.Fail("No message from deduplicator")
+	}
+}
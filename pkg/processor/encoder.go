@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/protocol"
 	"github.com/lab5e/lospan/pkg/server"
 	"github.com/lab5e/lospan/pkg/storage"
@@ -42,13 +43,20 @@ func (e *Encoder) processMessage(packet server.LoRaMessage) {
 	case protocol.JoinAccept:
 		// Reset frame counter for both
 		packet.FrameContext.Device.FCntDn = 0
+		packet.FrameContext.Device.NFCntDown = 0
+		packet.FrameContext.Device.AFCntDown = 0
 		packet.FrameContext.Device.FCntUp = 0
 		if err := e.context.Storage.UpdateDeviceState(packet.FrameContext.Device); err != nil {
 			lg.Warning("Unable to update frame counters for device with EUI %s: %v. Ignoring JoinRequest.", packet.FrameContext.Device.DeviceEUI, err)
 			return
 		}
 
-		buffer, err = packet.Payload.EncodeJoinAccept(packet.FrameContext.Device.AppKey)
+		device := packet.FrameContext.Device
+		if device.MACVersion == model.LoRaWAN11 {
+			buffer, err = packet.Payload.EncodeJoinAccept11(device.NwkKey, device.DeviceEUI, device.AppEUI, packet.FrameContext.DevNonce)
+		} else {
+			buffer, err = packet.Payload.EncodeJoinAccept(device.AppKey)
+		}
 		if err != nil {
 			lg.Warning("Unable to encode JoinAccept message for device with EUI %s (DevAddr=%s): %v",
 				packet.FrameContext.Device.DeviceEUI,
@@ -56,12 +64,20 @@ func (e *Encoder) processMessage(packet server.LoRaMessage) {
 				err)
 			return
 		}
-		packet.FrameContext.GatewayContext.Radio.RX1Delay = 5
-		packet.FrameContext.GatewayContext.Deadline = 5
 
 	default:
 		packet.Payload.MACPayload.FHDR.FCnt = packet.FrameContext.Device.FCntDn
-		buffer, err = packet.Payload.EncodeMessage(packet.FrameContext.Device.NwkSKey, packet.FrameContext.Device.AppSKey)
+		device := packet.FrameContext.Device
+		if device.MACVersion == model.LoRaWAN11 {
+			confFCnt := uint16(0)
+			if packet.Payload.MACPayload.FHDR.FCtrl.ACK {
+				// The frame counter is increased when an uplink is received
+				confFCnt = uint16(device.FCntUp - 1)
+			}
+			buffer, err = packet.Payload.EncodeMessage11(device.SNwkSIntKey, device.NwkSEncKey, device.AppSKey, device.NFCntDown, device.AFCntDown, confFCnt)
+		} else {
+			buffer, err = packet.Payload.EncodeMessage(device.NwkSKey, device.AppSKey)
+		}
 		if err != nil {
 			lg.Error("Unable to encode message for device with EUI %s: %v. (DevAddr=%s)",
 				packet.FrameContext.Device.DeviceEUI,
@@ -82,20 +98,32 @@ func (e *Encoder) processMessage(packet server.LoRaMessage) {
 		}
 
 		// Increase the frame counter after the message is sent. New devices will get 0,1,2...
-		packet.FrameContext.Device.FCntDn++
+		// LoRaWAN 1.1 devices have separate counters for application data and MAC commands.
+		switch {
+		case device.MACVersion != model.LoRaWAN11:
+			packet.FrameContext.Device.FCntDn++
+		case packet.Payload.MACPayload.ApplicationDownlink():
+			packet.FrameContext.Device.AFCntDown++
+		default:
+			packet.FrameContext.Device.NFCntDown++
+		}
 		if err := e.context.Storage.UpdateDeviceState(packet.FrameContext.Device); err != nil {
 			lg.Error("Unable to update frame counter for downstream message to device with EUI %s: %v",
 				packet.FrameContext.Device.DeviceEUI,
 				err)
 		}
-		packet.FrameContext.GatewayContext.Radio.RX1Delay = 1
-		packet.FrameContext.GatewayContext.Deadline = 1
 	}
 
 	if len(buffer) == 0 {
 		return
 	}
 
+	// The downlink event is only published for frames with the payload
+	var downlink *server.PayloadMessage
+	if len(packet.Payload.MACPayload.FRMPayload) > 0 {
+		downlink = packet.FrameContext.Downlink
+	}
+
 	// Copy relevant data to the outgoing packet.
 	e.output <- server.GatewayPacket{
 		RawMessage: buffer,
@@ -103,6 +131,9 @@ func (e *Encoder) processMessage(packet server.LoRaMessage) {
 		Gateway:    packet.FrameContext.GatewayContext.Gateway,
 		ReceivedAt: packet.FrameContext.GatewayContext.ReceivedAt,
 		Deadline:   packet.FrameContext.GatewayContext.Deadline,
+		Immediate:  packet.FrameContext.GatewayContext.Immediate,
+		GPSTime:    packet.FrameContext.GatewayContext.GPSTime,
+		Downlink:   downlink,
 	}
 }
 
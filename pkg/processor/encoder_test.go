@@ -46,6 +46,23 @@ func TestEncoderEncoding(t *testing.T) {
 		// This is OK
 	}
 
+	// The downlink event follows frames with payload
+	downlink := &server.PayloadMessage{Type: server.DownlinkEvent}
+	payload.MACPayload.FPort = 1
+	payload.MACPayload.FRMPayload = []byte{1, 2, 3}
+	input <- server.LoRaMessage{
+		Payload:      payload,
+		FrameContext: server.FrameContext{Device: d, Downlink: downlink},
+	}
+	select {
+	case pkt := <-output:
+		if pkt.Downlink != downlink {
+			t.Fatalf("Expected downlink event in packet: %+v", pkt.Downlink)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Got timeout reading output channel")
+	}
+
 	close(input)
 }
 
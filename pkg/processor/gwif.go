@@ -17,6 +17,7 @@ package processor
 //
 import (
 	"github.com/lab5e/lospan/pkg/gateway"
+	"github.com/lab5e/lospan/pkg/lg"
 	"github.com/lab5e/lospan/pkg/server"
 )
 
@@ -34,8 +35,45 @@ type GwForwarder interface {
 	Output() <-chan server.GatewayPacket
 }
 
-// NewGwForwarder creates a new gateway forwarder instance. The input and
-// output channels
+// NewGwForwarder creates a new gateway forwarder instance. The backend is
+// selected by the configuration. The generic packet forwarder is used if
+// there's no configuration.
 func NewGwForwarder(port int, context *server.Context) GwForwarder {
+	backend := server.SemtechBackend
+	if context.Config != nil {
+		backend = context.Config.GatewayBackend
+	}
+	return newBackendForwarder(backend, port, context)
+}
+
+func newBackendForwarder(backend string, port int, context *server.Context) GwForwarder {
+	switch backend {
+	case server.BasicStationBackend:
+		return gateway.NewBasicStationForwarder(port, context.Storage, context)
+	case server.MQTTBackend:
+		return gateway.NewMQTTForwarder(context.Storage, context)
+	}
 	return gateway.NewGenericPacketForwarder(port, context.Storage, context)
 }
+
+// NewConfiguredForwarder creates the gateway forwarders in the configuration.
+// A MuxForwarder is returned if there's more than one gateway backend.
+func NewConfiguredForwarder(context *server.Context) (GwForwarder, error) {
+	listeners, err := context.Config.GatewayListeners()
+	if err != nil {
+		return nil, err
+	}
+	var forwarders []GwForwarder
+	for _, l := range listeners {
+		if l.Backend == server.MQTTBackend {
+			lg.Info("Launching %s gateway forwarder...", l.Backend)
+		} else {
+			lg.Info("Launching %s gateway forwarder on port %d...", l.Backend, l.Port)
+		}
+		forwarders = append(forwarders, newBackendForwarder(l.Backend, l.Port, context))
+	}
+	if len(forwarders) == 1 {
+		return forwarders[0], nil
+	}
+	return NewMuxForwarder(forwarders...), nil
+}
@@ -18,6 +18,7 @@ package processor
 import (
 	"testing"
 
+	"github.com/lab5e/lospan/pkg/gateway"
 	"github.com/lab5e/lospan/pkg/server"
 	"github.com/lab5e/lospan/pkg/utils"
 )
@@ -53,3 +54,14 @@ func TestInterfaceChannels(t *testing.T) {
 
 	gwif.Stop()
 }
+
+func TestGwForwarderBackend(t *testing.T) {
+	context := server.Context{Storage: NewStorageTestContext(), Config: &server.Parameters{GatewayBackend: server.BasicStationBackend}}
+	if _, ok := NewGwForwarder(0, &context).(*gateway.BasicStationForwarder); !ok {
+		t.Fatal("Expected Basic Station forwarder")
+	}
+	context.Config.GatewayBackend = server.SemtechBackend
+	if _, ok := NewGwForwarder(0, &context).(*gateway.GenericPacketForwarder); !ok {
+		t.Fatal("Expected generic packet forwarder")
+	}
+}
@@ -16,7 +16,11 @@ package processor
 //limitations under the License.
 //
 import (
+	"fmt"
+	"time"
+
 	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/protocol"
 	"github.com/lab5e/lospan/pkg/server"
 )
@@ -26,15 +30,19 @@ type MACProcessor struct {
 	input    <-chan server.LoRaMessage // Input from decoder; receives decoded, deduped and valid frame
 	notifier chan server.LoRaMessage   // Notifier output; notifies scheduler about new RX
 	context  *server.Context           // Server context
+	adr      *ADREngine                // ADR engine
+	channels *ChannelManager           // Extra channels for dynamic channel plans
+	classB   *ClassBManager            // Ping slot channel and beacon frequency for class B devices
+	txParams *TxParamManager           // Dwell time limits for AS923 devices
 }
 
-func processMACCommand(cmd protocol.MACCommand) {
+func (m *MACProcessor) processMACCommand(msg *server.LoRaMessage, cmd protocol.MACCommand) {
 	switch cmd.ID() {
 	case protocol.LinkCheckReq:
 		// Initiated by the end device
-		lg.Warning("LinkCheckReq support not implemented")
+		m.processLinkCheckReq(msg)
 	case protocol.LinkADRAns:
-		lg.Warning("LinkADRAns support not implemented")
+		m.processLinkADRAns(msg, cmd.(*protocol.MACLinkADRAns))
 	case protocol.DutyCycleAns:
 		lg.Warning("DutyCycleAns support not implemented")
 	case protocol.RXParamSetupAns:
@@ -42,34 +50,333 @@ func processMACCommand(cmd protocol.MACCommand) {
 	case protocol.DevStatusAns:
 		lg.Warning("DevStatusAns support not implemented")
 	case protocol.NewChannelAns:
-		lg.Warning("NewChannelAns support not implemented")
+		m.processNewChannelAns(msg, cmd.(*protocol.MACNewChannelAns))
+	case protocol.DlChannelAns:
+		m.processDlChannelAns(msg, cmd.(*protocol.MACDlChannelAns))
+	case protocol.TxParamSetupAns:
+		m.processTxParamSetupAns(msg)
 	case protocol.RXTimingSetupAns:
 		lg.Warning("RXTimingSetupAns support not implemented")
 	case protocol.PingSlotInfoReq:
 		// Initiated by the end device
-		lg.Warning("PingSlotInfoReq support not implemented")
+		m.processPingSlotInfoReq(msg, cmd.(*protocol.MACPingSlotInfoReq))
 	case protocol.BeaconTimingReq:
 		// Initiated by the end device
-		lg.Warning("BeaconTimingReq support not implemented")
+		m.processBeaconTimingReq(msg)
 	case protocol.PingSlotFreqAns:
-		lg.Warning("PingSlotFreqAns support not implemented")
+		m.processPingSlotFreqAns(msg, cmd.(*protocol.MACPingSlotFreqAns))
 	case protocol.BeaconFreqAns:
-		lg.Warning("BeaconFreqAns support not implemented")
+		m.processBeaconFreqAns(msg, cmd.(*protocol.MACBeaconFreqAns))
+	case protocol.RekeyInd:
+		// Initiated by the end device
+		m.processRekeyInd(msg, cmd.(*protocol.MACRekeyInd))
 	default:
 		lg.Warning("Unknown MAC command: %d", cmd.ID())
 	}
 }
 
+// processLinkCheckReq answers the LinkCheckReq with the demodulation margin of
+// the best reception and the number of gateways that received the frame.
+func (m *MACProcessor) processLinkCheckReq(msg *server.LoRaMessage) {
+	receptions := msg.FrameContext.Receptions
+	if len(receptions) == 0 {
+		receptions = []server.GatewayPacket{msg.FrameContext.GatewayContext}
+	}
+	ans, err := newLinkCheckAns(msg.FrameContext.GatewayContext.Radio, receptions)
+	if err != nil {
+		lg.Warning("Unable to answer LinkCheckReq from device %s: %v", msg.FrameContext.Device.DeviceEUI, err)
+		return
+	}
+	if m.context.FrameOutput == nil {
+		return
+	}
+	if err := m.context.FrameOutput.AddMACCommand(msg.FrameContext.Device.DeviceEUI, ans); err != nil {
+		lg.Warning("Unable to queue LinkCheckAns for device %s: %v", msg.FrameContext.Device.DeviceEUI, err)
+	}
+}
+
+// newLinkCheckAns builds a LinkCheckAns command. The margin is the best SNR
+// for the receptions relative to the required SNR for the frame's data rate.
+func newLinkCheckAns(radio server.RadioContext, receptions []server.GatewayPacket) (*protocol.MACLinkCheckAns, error) {
+	if radio.Band == nil {
+		return nil, fmt.Errorf("no band for frame")
+	}
+	dataRate, err := radio.Band.GetDataRate(radio.DataRate)
+	if err != nil {
+		return nil, err
+	}
+	encoding, err := radio.Band.Encoding(dataRate)
+	if err != nil {
+		return nil, err
+	}
+	requiredSNR, err := encoding.RequiredSNR()
+	if err != nil {
+		return nil, err
+	}
+	bestSNR := radio.SNR
+	for _, v := range receptions {
+		if v.Radio.SNR > bestSNR {
+			bestSNR = v.Radio.SNR
+		}
+	}
+	margin := bestSNR - requiredSNR
+	switch {
+	case margin < 0:
+		margin = 0
+	case margin > 254:
+		// 255 is reserved [5.1]
+		margin = 254
+	}
+	gwCount := len(receptions)
+	if gwCount > 255 {
+		gwCount = 255
+	}
+	ans := protocol.NewDownlinkMACCommand(protocol.LinkCheckAns).(*protocol.MACLinkCheckAns)
+	ans.Margin = uint8(margin)
+	ans.GwCnt = uint8(gwCount)
+	return ans, nil
+}
+
+// processLinkADRAns stores the settings the device has accepted
+func (m *MACProcessor) processLinkADRAns(msg *server.LoRaMessage, ans *protocol.MACLinkADRAns) {
+	device := &msg.FrameContext.Device
+	if !m.adr.Answer(device, ans) {
+		return
+	}
+	lg.Info("Device %s accepted data rate %d, TX power %d and channel mask 0x%04x",
+		device.DeviceEUI, device.DataRate, device.TXPower, device.ChannelMask)
+	if m.context.Storage == nil {
+		return
+	}
+	if err := m.context.Storage.UpdateDeviceMACState(*device); err != nil {
+		lg.Warning("Unable to update MAC state for device %s: %v", device.DeviceEUI, err)
+	}
+}
+
+// processNewChannelAns stores the channel if the device accepted it
+func (m *MACProcessor) processNewChannelAns(msg *server.LoRaMessage, ans *protocol.MACNewChannelAns) {
+	device := &msg.FrameContext.Device
+	if !m.channels.NewChannelAns(device, ans) {
+		return
+	}
+	lg.Info("Device %s accepted new channel. Extra channels: %v", device.DeviceEUI, device.Channels)
+	m.updateMACState(device)
+}
+
+// processDlChannelAns stores the downlink frequency if the device accepted it
+func (m *MACProcessor) processDlChannelAns(msg *server.LoRaMessage, ans *protocol.MACDlChannelAns) {
+	device := &msg.FrameContext.Device
+	if !m.channels.DlChannelAns(device, ans) {
+		return
+	}
+	lg.Info("Device %s accepted downlink frequency. Extra channels: %v", device.DeviceEUI, device.Channels)
+	m.updateMACState(device)
+}
+
+// processTxParamSetupAns logs the dwell time setting the device acknowledged
+func (m *MACProcessor) processTxParamSetupAns(msg *server.LoRaMessage) {
+	device := msg.FrameContext.Device
+	if !m.txParams.TxParamSetupAns(device) {
+		return
+	}
+	lg.Info("Device %s acknowledged TxParamSetupReq", device.DeviceEUI)
+}
+
+// processTxParams queues a TxParamSetupReq if the device hasn't acknowledged
+// the dwell time setting for the band.
+func (m *MACProcessor) processTxParams(msg *server.LoRaMessage) {
+	device := msg.FrameContext.Device
+	req := m.txParams.Evaluate(device, msg.FrameContext.GatewayContext.Radio.Band)
+	if req == nil || m.context.FrameOutput == nil {
+		return
+	}
+	if err := m.context.FrameOutput.AddMACCommand(device.DeviceEUI, req); err != nil {
+		lg.Warning("Unable to queue TxParamSetupReq for device %s: %v", device.DeviceEUI, err)
+	}
+}
+
+// updateMACState stores the MAC settings the device has accepted
+func (m *MACProcessor) updateMACState(device *model.Device) {
+	if m.context.Storage == nil {
+		return
+	}
+	if err := m.context.Storage.UpdateDeviceMACState(*device); err != nil {
+		lg.Warning("Unable to update MAC state for device %s: %v", device.DeviceEUI, err)
+	}
+}
+
+// processChannels queues a NewChannelReq or DlChannelReq if the device is
+// missing one of the extra channels.
+func (m *MACProcessor) processChannels(msg *server.LoRaMessage) {
+	device := msg.FrameContext.Device
+	plan := msg.FrameContext.GatewayContext.Radio.Band
+	req := m.channels.Evaluate(device, plan, m.context.Config.BandChannels(plan))
+	if req == nil || m.context.FrameOutput == nil {
+		return
+	}
+	if err := m.context.FrameOutput.AddMACCommand(device.DeviceEUI, req); err != nil {
+		lg.Warning("Unable to queue %T for device %s: %v", req, device.DeviceEUI, err)
+	}
+}
+
+// processPingSlotInfoReq stores the ping slot periodicity requested by the
+// device and acknowledges the request.
+func (m *MACProcessor) processPingSlotInfoReq(msg *server.LoRaMessage, req *protocol.MACPingSlotInfoReq) {
+	device := &msg.FrameContext.Device
+	device.PingPeriodicity = req.Periodicity
+	lg.Info("Device %s uses ping slot periodicity %d", device.DeviceEUI, device.PingPeriodicity)
+	if m.context.Storage != nil {
+		if err := m.context.Storage.UpdateDeviceMACState(*device); err != nil {
+			lg.Warning("Unable to update MAC state for device %s: %v", device.DeviceEUI, err)
+		}
+	}
+	if m.context.FrameOutput == nil {
+		return
+	}
+	ans := protocol.NewDownlinkMACCommand(protocol.PingSlotInfoAns)
+	if err := m.context.FrameOutput.AddMACCommand(device.DeviceEUI, ans); err != nil {
+		lg.Warning("Unable to queue PingSlotInfoAns for device %s: %v", device.DeviceEUI, err)
+	}
+}
+
+// processPingSlotFreqAns stores the ping slot channel if the device accepted it
+func (m *MACProcessor) processPingSlotFreqAns(msg *server.LoRaMessage, ans *protocol.MACPingSlotFreqAns) {
+	device := &msg.FrameContext.Device
+	if !m.classB.PingSlotFreqAns(device, ans) {
+		return
+	}
+	lg.Info("Device %s accepted ping slot frequency %.3f MHz and data rate %d", device.DeviceEUI, device.PingFrequency, device.PingDataRate)
+	m.updateMACState(device)
+}
+
+// processBeaconFreqAns stores the beacon frequency for the device
+func (m *MACProcessor) processBeaconFreqAns(msg *server.LoRaMessage, ans *protocol.MACBeaconFreqAns) {
+	device := &msg.FrameContext.Device
+	if !m.classB.BeaconFreqAns(device, ans) {
+		return
+	}
+	lg.Info("Device %s accepted beacon frequency %.3f MHz", device.DeviceEUI, device.BeaconFrequency)
+	m.updateMACState(device)
+}
+
+// processClassB queues a PingSlotChannelReq or BeaconFreqReq if a class B
+// device doesn't use the configured ping slot channel or beacon frequency.
+func (m *MACProcessor) processClassB(msg *server.LoRaMessage) {
+	device := msg.FrameContext.Device
+	plan := msg.FrameContext.GatewayContext.Radio.Band
+	ping, beacon := m.context.Config.BandClassBChannels(plan)
+	req := m.classB.Evaluate(device, plan, ping, beacon)
+	if req == nil || m.context.FrameOutput == nil {
+		return
+	}
+	if err := m.context.FrameOutput.AddMACCommand(device.DeviceEUI, req); err != nil {
+		lg.Warning("Unable to queue %T for device %s: %v", req, device.DeviceEUI, err)
+	}
+}
+
+// processRekeyInd confirms the security context for LoRaWAN 1.1 devices. The
+// device repeats the RekeyInd command until it receives a RekeyConf.
+func (m *MACProcessor) processRekeyInd(msg *server.LoRaMessage, ind *protocol.MACRekeyInd) {
+	device := msg.FrameContext.Device
+	if device.MACVersion != model.LoRaWAN11 {
+		lg.Warning("Got RekeyInd from device %s but it isn't a LoRaWAN 1.1 device", device.DeviceEUI)
+		return
+	}
+	if m.context.FrameOutput == nil {
+		return
+	}
+	conf := protocol.NewDownlinkMACCommand(protocol.RekeyConf).(*protocol.MACRekeyConf)
+	conf.Minor = 1
+	if ind.Minor < conf.Minor {
+		conf.Minor = ind.Minor
+	}
+	if err := m.context.FrameOutput.AddMACCommand(device.DeviceEUI, conf); err != nil {
+		lg.Warning("Unable to queue RekeyConf for device %s: %v", device.DeviceEUI, err)
+	}
+}
+
+// processBeaconTimingReq answers with the time until the next beacon and the
+// channel the beacon will be sent on.
+func (m *MACProcessor) processBeaconTimingReq(msg *server.LoRaMessage) {
+	if m.context.FrameOutput == nil {
+		return
+	}
+	radio := msg.FrameContext.GatewayContext.Radio
+	ans := newBeaconTimingAns(radio, protocol.TimeToGPS(time.Now()))
+	if _, beacon := m.context.Config.BandClassBChannels(radio.Band); beacon != 0 {
+		// The beacon doesn't hop when the frequency is set
+		ans.Channel = 0
+	}
+	if err := m.context.FrameOutput.AddMACCommand(msg.FrameContext.Device.DeviceEUI, ans); err != nil {
+		lg.Warning("Unable to queue BeaconTimingAns for device %s: %v", msg.FrameContext.Device.DeviceEUI, err)
+	}
+}
+
+// newBeaconTimingAns builds a BeaconTimingAns command. The delay is in 30 ms
+// units [14.5]
+func newBeaconTimingAns(radio server.RadioContext, gpsTime time.Duration) *protocol.MACBeaconTimingAns {
+	next := protocol.BeaconTime(gpsTime) + protocol.BeaconPeriod
+	ans := protocol.NewDownlinkMACCommand(protocol.BeaconTimingAns).(*protocol.MACBeaconTimingAns)
+	ans.Delay = uint16((next - gpsTime) / protocol.PingSlotLength)
+	if radio.Band != nil {
+		frequencies := radio.Band.Configuration().BeaconFrequencies
+		if len(frequencies) > 0 {
+			ans.Channel = uint8(int64(next/protocol.BeaconPeriod) % int64(len(frequencies)))
+		}
+	}
+	return ans
+}
+
+// processADR adds the uplink to the ADR history and queues a LinkADRReq if the
+// device should change its data rate or TX power. Devices that haven't set the
+// ADR bit in the uplink or have a device profile with ADR disabled keep their
+// data rate and TX power but devices in US915 and AU915 are still told which
+// channels to use. The TX power is limited by the max EIRP in the profile.
+func (m *MACProcessor) processADR(msg *server.LoRaMessage) {
+	device := msg.FrameContext.Device
+	adr := msg.Payload.MACPayload.FHDR.FCtrl.ADR
+	var maxEIRP uint8
+	if adr && device.ProfileID != 0 {
+		profile, err := m.context.Storage.GetDeviceProfile(device.ProfileID)
+		if err != nil {
+			lg.Warning("Unable to retrieve profile %d for device %s: %v", device.ProfileID, device.DeviceEUI, err)
+			return
+		}
+		adr = profile.ADREnabled
+		maxEIRP = profile.MaxEIRP
+	}
+	radio := msg.FrameContext.GatewayContext.Radio
+	if adr {
+		m.adr.AddSample(device.DeviceEUI, radio)
+	}
+
+	req := m.adr.Evaluate(device, radio, adr, maxEIRP)
+	if req == nil || m.context.FrameOutput == nil {
+		return
+	}
+	last := req.Requests[len(req.Requests)-1]
+	lg.Info("Requesting data rate %d and TX power %d for device %s (%d LinkADRReq commands)", last.DataRate, last.TXPower, device.DeviceEUI, len(req.Requests))
+	if err := m.context.FrameOutput.AddMACCommand(device.DeviceEUI, req); err != nil {
+		lg.Warning("Unable to queue LinkADRReq for device %s: %v", device.DeviceEUI, err)
+	}
+}
+
 // Start launches the MAC processor. When the input channel is closed the
 // method will stop and the notifier channel will be closed.
 func (m *MACProcessor) Start() {
 	for v := range m.input {
 		go func(val server.LoRaMessage) {
 			for _, cmd := range val.Payload.MACPayload.MACCommands.List() {
-				processMACCommand(cmd)
+				m.processMACCommand(&val, cmd)
 			}
 			for _, cmd := range val.Payload.MACPayload.FHDR.FOpts.List() {
-				processMACCommand(cmd)
+				m.processMACCommand(&val, cmd)
+			}
+			if val.Payload.MHDR.MType.Uplink() && val.Payload.MHDR.MType != protocol.JoinRequest {
+				m.processADR(&val)
+				m.processChannels(&val)
+				m.processClassB(&val)
+				m.processTxParams(&val)
 			}
 			m.notifier <- val
 		}(v)
@@ -91,5 +398,9 @@ func NewMACProcessor(context *server.Context, input <-chan server.LoRaMessage) *
 		context:  context,
 		input:    input,
 		notifier: make(chan server.LoRaMessage),
+		adr:      NewADREngine(),
+		channels: NewChannelManager(),
+		classB:   NewClassBManager(),
+		txParams: NewTxParamManager(),
 	}
 }
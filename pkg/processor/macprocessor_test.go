@@ -20,8 +20,11 @@ import (
 
 	"time"
 
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/protocol"
 	"github.com/lab5e/lospan/pkg/server"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMacprocessorChannels(t *testing.T) {
@@ -93,3 +96,142 @@ func TestMacprocessorForwarding(t *testing.T) {
 		// OK - got message
 	}
 }
+
+func TestMACProcessorLinkCheck(t *testing.T) {
+	assert := require.New(t)
+
+	frameOutput := server.NewFrameOutputBuffer()
+	context := &server.Context{FrameOutput: &frameOutput}
+
+	input := make(chan server.LoRaMessage)
+	defer close(input)
+	macprocessor := NewMACProcessor(context, input)
+	go macprocessor.Start()
+
+	msg := makeLoRaMessage(true, protocol.UnconfirmedDataUp,
+		[]protocol.MACCommand{protocol.NewUplinkMACCommand(protocol.LinkCheckReq)}, nil)
+	msg.FrameContext.Device.DeviceEUI = protocol.EUIFromInt64(0x0102030405060708)
+
+	eu868, _ := band.NewBand(band.EU868Band)
+	pkt := server.GatewayPacket{
+		Radio:   server.RadioContext{Band: eu868, DataRate: "SF9BW125", SNR: -2},
+		Gateway: server.GatewayContext{GatewayEUI: protocol.EUIFromInt64(1)},
+	}
+	msg.FrameContext.GatewayContext = pkt
+	msg.FrameContext.Receptions = append(msg.FrameContext.Receptions, pkt)
+	pkt.Gateway.GatewayEUI = protocol.EUIFromInt64(2)
+	pkt.Radio.SNR = 3.5
+	msg.FrameContext.Receptions = append(msg.FrameContext.Receptions, pkt)
+
+	input <- msg
+	select {
+	case <-macprocessor.CommandNotifier():
+	case <-time.After(time.Second):
+		assert.Fail("No notification from MAC processor")
+	}
+
+	payload, err := frameOutput.GetPHYPayloadForDevice(&msg.FrameContext.Device, &msg.FrameContext)
+	assert.NoError(err)
+	var ans *protocol.MACLinkCheckAns
+	for _, cmd := range payload.MACPayload.MACCommands.List() {
+		if cmd.ID() == protocol.LinkCheckAns {
+			ans = cmd.(*protocol.MACLinkCheckAns)
+		}
+	}
+	assert.NotNil(ans)
+	assert.Equal(uint8(2), ans.GwCnt)
+	// Best SNR is 3.5 dB and SF9 requires -12.5 dB
+	assert.Equal(uint8(16), ans.Margin)
+}
+
+func TestMACProcessorClassB(t *testing.T) {
+	assert := require.New(t)
+
+	store := NewStorageTestContext()
+	frameOutput := server.NewFrameOutputBuffer()
+	context := &server.Context{Storage: store, FrameOutput: &frameOutput}
+	deviceEUI, _ := protocol.EUIFromString("75-0A-09-3A-2C-22-69-F3")
+	device, err := store.GetDeviceByEUI(deviceEUI)
+	assert.NoError(err)
+
+	input := make(chan server.LoRaMessage)
+	defer close(input)
+	macprocessor := NewMACProcessor(context, input)
+	go macprocessor.Start()
+
+	req := protocol.NewUplinkMACCommand(protocol.PingSlotInfoReq).(*protocol.MACPingSlotInfoReq)
+	req.Periodicity = 3
+	msg := makeLoRaMessage(true, protocol.UnconfirmedDataUp,
+		[]protocol.MACCommand{req, protocol.NewUplinkMACCommand(protocol.BeaconTimingReq)}, nil)
+	msg.FrameContext.Device = device
+	msg.FrameContext.GatewayContext.Radio.Band, _ = band.NewBand(band.EU868Band)
+	msg.FrameContext.GatewayContext.Radio.DataRate = "SF9BW125"
+
+	input <- msg
+	select {
+	case <-macprocessor.CommandNotifier():
+	case <-time.After(time.Second):
+		assert.Fail("No notification from MAC processor")
+	}
+
+	updated, err := store.GetDeviceByEUI(deviceEUI)
+	assert.NoError(err)
+	assert.Equal(uint8(3), updated.PingPeriodicity)
+
+	payload, err := frameOutput.GetPHYPayloadForDevice(&device, &msg.FrameContext)
+	assert.NoError(err)
+	assert.True(payload.MACPayload.MACCommands.Contains(protocol.PingSlotInfoAns))
+	assert.True(payload.MACPayload.MACCommands.Contains(protocol.BeaconTimingAns))
+}
+
+func TestBeaconTimingAns(t *testing.T) {
+	assert := require.New(t)
+
+	us915, _ := band.NewBand(band.US915Band)
+	ans := newBeaconTimingAns(server.RadioContext{Band: us915}, 300*time.Second)
+	// Next beacon is at 384 s, ie 84 s or 2800 slots later on channel 3
+	assert.Equal(uint16(2800), ans.Delay)
+	assert.Equal(uint8(3), ans.Channel)
+}
+
+func TestMACProcessorRekeyInd(t *testing.T) {
+	assert := require.New(t)
+
+	frameOutput := server.NewFrameOutputBuffer()
+	context := &server.Context{FrameOutput: &frameOutput}
+
+	input := make(chan server.LoRaMessage)
+	defer close(input)
+	macprocessor := NewMACProcessor(context, input)
+	go macprocessor.Start()
+
+	send := func(device model.Device) {
+		ind := protocol.NewUplinkMACCommand(protocol.RekeyInd).(*protocol.MACRekeyInd)
+		ind.Minor = 1
+		msg := makeLoRaMessage(true, protocol.UnconfirmedDataUp, []protocol.MACCommand{ind}, nil)
+		msg.FrameContext.Device = device
+		input <- msg
+		select {
+		case <-macprocessor.CommandNotifier():
+		case <-time.After(time.Second):
+			assert.Fail("No notification from MAC processor")
+		}
+	}
+
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(1)
+	send(device)
+	_, err := frameOutput.GetPHYPayloadForDevice(&device, &server.FrameContext{})
+	assert.Error(err, "LoRaWAN 1.0 devices should not get a RekeyConf")
+
+	device.MACVersion = model.LoRaWAN11
+	send(device)
+	payload, err := frameOutput.GetPHYPayloadForDevice(&device, &server.FrameContext{})
+	assert.NoError(err)
+	assert.True(payload.MACPayload.MACCommands.Contains(protocol.RekeyConf))
+	for _, v := range payload.MACPayload.MACCommands.List() {
+		if conf, ok := v.(*protocol.MACRekeyConf); ok {
+			assert.Equal(uint8(1), conf.Minor)
+		}
+	}
+}
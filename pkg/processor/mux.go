@@ -0,0 +1,113 @@
+package processor
+
+import (
+	"sync"
+
+	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+)
+
+// GatewayOwner is implemented by forwarders that know which gateways are
+// connected to them.
+type GatewayOwner interface {
+	// HasGateway returns true if the gateway is connected to the forwarder
+	HasGateway(eui protocol.EUI) bool
+}
+
+// MuxForwarder runs several forwarders as a single forwarder. The uplinks
+// from all of the forwarders are merged into one output channel. Downlinks
+// are routed to the forwarder that last received an uplink from the gateway.
+// If no uplink has been received from the gateway the forwarders that
+// implement GatewayOwner are queried.
+type MuxForwarder struct {
+	forwarders []GwForwarder
+	input      chan server.GatewayPacket
+	output     chan server.GatewayPacket
+	mutex      *sync.Mutex
+	routes     map[protocol.EUI]GwForwarder
+}
+
+// NewMuxForwarder creates a new multiplexing forwarder
+func NewMuxForwarder(forwarders ...GwForwarder) *MuxForwarder {
+	return &MuxForwarder{
+		forwarders: forwarders,
+		input:      make(chan server.GatewayPacket),
+		output:     make(chan server.GatewayPacket),
+		mutex:      &sync.Mutex{},
+		routes:     make(map[protocol.EUI]GwForwarder),
+	}
+}
+
+// Start launches the forwarders. It does not return until the input channel
+// is closed and all of the forwarders have terminated.
+func (m *MuxForwarder) Start() {
+	wg := &sync.WaitGroup{}
+	for _, f := range m.forwarders {
+		wg.Add(1)
+		go f.Start()
+		go func(f GwForwarder) {
+			defer wg.Done()
+			for pkt := range f.Output() {
+				m.setRoute(pkt.Gateway.GatewayEUI, f)
+				m.output <- pkt
+			}
+		}(f)
+	}
+
+	for pkt := range m.input {
+		f := m.route(pkt.Gateway.GatewayEUI)
+		if f == nil {
+			lg.Warning("No forwarder for gateway %s. Dropping downlink", pkt.Gateway.GatewayEUI)
+			continue
+		}
+		f.Input() <- pkt
+	}
+
+	lg.Debug("Input channel for multiplexing forwarder closed. Terminating")
+	for _, f := range m.forwarders {
+		f.Stop()
+	}
+	wg.Wait()
+	close(m.output)
+}
+
+// Stop stops all of the forwarders and closes the channels
+func (m *MuxForwarder) Stop() {
+	close(m.input)
+}
+
+// Input returns the input channel for the forwarder. Packets sent on this
+// channel are forwarded to the forwarder that owns the gateway.
+func (m *MuxForwarder) Input() chan<- server.GatewayPacket {
+	return m.input
+}
+
+// Output returns the output channel for the forwarder. The uplinks from all
+// of the forwarders are sent on this channel.
+func (m *MuxForwarder) Output() <-chan server.GatewayPacket {
+	return m.output
+}
+
+func (m *MuxForwarder) setRoute(eui protocol.EUI, f GwForwarder) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.routes[eui] = f
+}
+
+// route returns the forwarder for the gateway or nil if no forwarder owns
+// the gateway.
+func (m *MuxForwarder) route(eui protocol.EUI) GwForwarder {
+	m.mutex.Lock()
+	f, ok := m.routes[eui]
+	m.mutex.Unlock()
+	if ok {
+		return f
+	}
+	for _, f := range m.forwarders {
+		if owner, ok := f.(GatewayOwner); ok && owner.HasGateway(eui) {
+			return f
+		}
+	}
+	return nil
+}
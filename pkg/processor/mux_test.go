@@ -0,0 +1,86 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/lab5e/lospan/pkg/server"
+	"github.com/stretchr/testify/require"
+)
+
+// ownerForwarder is a test forwarder that owns a single gateway
+type ownerForwarder struct {
+	*testForwarder
+	eui protocol.EUI
+}
+
+func (o *ownerForwarder) HasGateway(eui protocol.EUI) bool {
+	return eui == o.eui
+}
+
+func TestMuxForwarder(t *testing.T) {
+	assert := require.New(t)
+
+	gw1 := protocol.EUIFromInt64(1)
+	gw2 := protocol.EUIFromInt64(2)
+	gw3 := protocol.EUIFromInt64(3)
+	f1 := newTestForwarder()
+	f2 := newTestForwarder()
+	f3 := &ownerForwarder{testForwarder: newTestForwarder(), eui: gw3}
+
+	mux := NewMuxForwarder(f1, f2, f3)
+	go mux.Start()
+
+	packet := func(eui protocol.EUI) server.GatewayPacket {
+		return server.GatewayPacket{RawMessage: eui.Octets[:], Gateway: server.GatewayContext{GatewayEUI: eui}}
+	}
+	receive := func() server.GatewayPacket {
+		select {
+		case pkt := <-mux.Output():
+			return pkt
+		case <-time.After(time.Second):
+			assert.Fail("No packet from multiplexing forwarder")
+		}
+		return server.GatewayPacket{}
+	}
+
+	go f1.injectMessage(packet(gw1))
+	assert.Equal(gw1, receive().Gateway.GatewayEUI)
+	go f2.injectMessage(packet(gw2))
+	assert.Equal(gw2, receive().Gateway.GatewayEUI)
+
+	// Downlinks are routed to the forwarder that received the uplink
+	mux.Input() <- packet(gw2)
+	assert.Nil(f1.grabMessage(100 * time.Millisecond))
+	pkt := f2.grabMessage(100 * time.Millisecond)
+	assert.NotNil(pkt)
+	assert.Equal(gw2, pkt.Gateway.GatewayEUI)
+
+	mux.Input() <- packet(gw1)
+	assert.NotNil(f1.grabMessage(100 * time.Millisecond))
+
+	// Gateways that haven't sent an uplink are looked up in the forwarders
+	mux.Input() <- packet(gw3)
+	assert.NotNil(f3.grabMessage(100 * time.Millisecond))
+
+	// Unknown gateways are dropped
+	mux.Input() <- packet(protocol.EUIFromInt64(4))
+	assert.Nil(f1.grabMessage(50 * time.Millisecond))
+	assert.Nil(f2.grabMessage(50 * time.Millisecond))
+	assert.Nil(f3.grabMessage(50 * time.Millisecond))
+
+	// A gateway that moves to another forwarder is routed to the new one
+	go f1.injectMessage(packet(gw2))
+	receive()
+	mux.Input() <- packet(gw2)
+	assert.NotNil(f1.grabMessage(100 * time.Millisecond))
+
+	mux.Stop()
+	select {
+	case _, ok := <-mux.Output():
+		assert.False(ok, "Output channel should be closed")
+	case <-time.After(time.Second):
+		assert.Fail("Output channel not closed after Stop")
+	}
+}
@@ -16,8 +16,11 @@ package processor
 //limitations under the License.
 //
 import (
+	"fmt"
+
 	"github.com/lab5e/lospan/pkg/frequency"
 	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/protocol"
 	"github.com/lab5e/lospan/pkg/server"
 )
@@ -48,6 +51,28 @@ func (d *Decrypter) processJoinRequest(decoded server.LoRaMessage) bool {
 		lg.Warning("Device %s is re-using a nonce (0x%04x) but nonce checks are disabled", joinRequest.DevEUI, joinRequest.DevNonce)
 	}
 
+	// Devices with a profile use the profile settings rather than the defaults
+	radio := decoded.FrameContext.GatewayContext.Radio
+	dlSettings := frequency.GetDLSettingsOTAA(radio.Band)
+	rxDelay := frequency.GetRxDelayOTAA()
+	if device.ProfileID != 0 {
+		profile, err := d.context.Storage.GetDeviceProfile(device.ProfileID)
+		if err != nil {
+			lg.Warning("Unable to retrieve profile %d for device %s: %v. Ignoring JoinRequest", device.ProfileID, device.DeviceEUI, err)
+			return false
+		}
+		if !profile.SupportsJoin {
+			lg.Info("Device %s uses profile %d which doesn't support joins. Ignoring JoinRequest", device.DeviceEUI, profile.ID)
+			return false
+		}
+		profile.Apply(&device)
+		dlSettings = protocol.DLSettings{
+			RX1DRoffset: profile.RX1DROffset,
+			RX2DataRate: profile.RX2DataRate,
+		}
+		rxDelay = profile.RX1Delay
+	}
+
 	// Retrieve the application
 	app, err := d.context.Storage.GetApplicationByEUI(joinRequest.AppEUI)
 	if err != nil {
@@ -71,26 +96,41 @@ func (d *Decrypter) processJoinRequest(decoded server.LoRaMessage) bool {
 	}
 
 	// Generate app nonce, generate keys, store keys
-	appNonce, err := app.GenerateAppNonce()
-	if err != nil {
-		lg.Warning("Unable to generate app nonce: %v (devEUI: %s, appEUI: %s). Ignoring JoinRequest",
-			err, joinRequest.DevEUI, joinRequest.AppEUI)
-		return false
-	}
-	nwkSKey, err := protocol.NwkSKeyFromNonces(device.AppKey, appNonce, uint32(d.context.Config.NetworkID), joinRequest.DevNonce)
-	if err != nil {
-		lg.Error("Unable to generate NwkSKey for device with EUI %s: %v", device.DeviceEUI, err)
-		return false
+	var appNonce [3]byte
+	if device.MACVersion == model.LoRaWAN11 {
+		// LoRaWAN 1.1 devices only accept JoinNonce values that are bigger
+		// than the previous one.
+		device.JoinNonce++
+		appNonce = [3]byte{byte(device.JoinNonce), byte(device.JoinNonce >> 8), byte(device.JoinNonce >> 16)}
+	} else {
+		appNonce, err = app.GenerateAppNonce()
+		if err != nil {
+			lg.Warning("Unable to generate app nonce: %v (devEUI: %s, appEUI: %s). Ignoring JoinRequest",
+				err, joinRequest.DevEUI, joinRequest.AppEUI)
+			return false
+		}
 	}
-	appSKey, err := protocol.AppSKeyFromNonces(device.AppKey, appNonce, uint32(d.context.Config.NetworkID), joinRequest.DevNonce)
-	if err != nil {
-		lg.Error("Unable to generate AppSKey for device with EUI %s: %v", device.DeviceEUI, err)
+	if err := d.generateSessionKeys(&device, appNonce, joinRequest); err != nil {
+		lg.Error("Unable to generate session keys for device with EUI %s: %v", device.DeviceEUI, err)
 		return false
 	}
-	device.NwkSKey = nwkSKey
-	device.AppSKey = appSKey
 	device.FCntDn = 0
+	device.NFCntDown = 0
+	device.AFCntDown = 0
 	device.FCntUp = 0
+	// The device uses the settings in the JoinAccept
+	device.RX1DROffset = dlSettings.RX1DRoffset
+	device.RX2DataRate = dlSettings.RX2DataRate
+	device.RX1Delay = rxDelay
+	// The device starts out with the default channels and the channels in
+	// the CFList
+	channels := d.context.Config.BandChannels(radio.Band)
+	device.Channels = joinChannels(channels)
+	// The join resets the class B settings to the defaults
+	device.PingPeriodicity = model.DefaultPingPeriodicity
+	device.PingFrequency = 0
+	device.PingDataRate = 0
+	device.BeaconFrequency = 0
 	if device.DevAddr.ToUint32() == 0 {
 		// Set device address if it isn't set
 		device.DevAddr = protocol.NewDevAddr()
@@ -106,14 +146,22 @@ func (d *Decrypter) processJoinRequest(decoded server.LoRaMessage) bool {
 		AppNonce:   appNonce,
 		NetID:      uint32(d.context.Config.NetworkID),
 		DevAddr:    device.DevAddr,
-		DLSettings: frequency.GetDLSettingsOTAA(),
-		RxDelay:    frequency.GetRxDelayOTAA(),
-		CFList:     frequency.GetCFListOTAA(),
+		DLSettings: dlSettings,
+		RxDelay:    rxDelay,
+		CFList:     frequency.GetCFListOTAA(radio.Band, radio.Channels, channels),
 	}
+	joinAccept.DLSettings.OptNeg = device.MACVersion == model.LoRaWAN11
+	decoded.FrameContext.DevNonce = joinRequest.DevNonce
 
 	d.context.FrameOutput.SetJoinAcceptPayload(device.DeviceEUI, joinAccept)
 
 	lg.Debug("JoinAccept sent to %s. DevAddr=%s", device.DeviceEUI, joinAccept.DevAddr)
+	d.publish(&server.PayloadMessage{
+		Type:         server.JoinEvent,
+		Device:       device,
+		Application:  app,
+		FrameContext: decoded.FrameContext,
+	})
 
 	// The incoming message doesn't have a DevAddr set but schedule an empty
 	// message for it. TODO (stalehd): this is butt ugly. Needs redesign.
@@ -122,3 +170,35 @@ func (d *Decrypter) processJoinRequest(decoded server.LoRaMessage) bool {
 	d.macOutput <- decoded
 	return true
 }
+
+// generateSessionKeys derives the session keys for a device. LoRaWAN 1.0
+// devices derive both session keys from the AppKey. LoRaWAN 1.1 devices derive
+// the network session keys from the NwkKey and the JoinEUI is used instead of
+// the NetID.
+func (d *Decrypter) generateSessionKeys(device *model.Device, appNonce [3]byte, joinRequest *protocol.JoinRequestPayload) error {
+	var err error
+	if device.MACVersion != model.LoRaWAN11 {
+		netID := uint32(d.context.Config.NetworkID)
+		if device.NwkSKey, err = protocol.NwkSKeyFromNonces(device.AppKey, appNonce, netID, joinRequest.DevNonce); err != nil {
+			return fmt.Errorf("unable to generate NwkSKey: %v", err)
+		}
+		if device.AppSKey, err = protocol.AppSKeyFromNonces(device.AppKey, appNonce, netID, joinRequest.DevNonce); err != nil {
+			return fmt.Errorf("unable to generate AppSKey: %v", err)
+		}
+		return nil
+	}
+	joinEUI := joinRequest.AppEUI
+	if device.NwkSKey, err = protocol.FNwkSIntKeyFromNonces(device.NwkKey, appNonce, joinEUI, joinRequest.DevNonce); err != nil {
+		return fmt.Errorf("unable to generate FNwkSIntKey: %v", err)
+	}
+	if device.SNwkSIntKey, err = protocol.SNwkSIntKeyFromNonces(device.NwkKey, appNonce, joinEUI, joinRequest.DevNonce); err != nil {
+		return fmt.Errorf("unable to generate SNwkSIntKey: %v", err)
+	}
+	if device.NwkSEncKey, err = protocol.NwkSEncKeyFromNonces(device.NwkKey, appNonce, joinEUI, joinRequest.DevNonce); err != nil {
+		return fmt.Errorf("unable to generate NwkSEncKey: %v", err)
+	}
+	if device.AppSKey, err = protocol.AppSKeyFromJoinNonce(device.AppKey, appNonce, joinEUI, joinRequest.DevNonce); err != nil {
+		return fmt.Errorf("unable to generate AppSKey: %v", err)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/lab5e/lospan/pkg/band"
 	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/protocol"
 	"github.com/lab5e/lospan/pkg/server"
@@ -68,3 +69,260 @@ func TestOTAAJoinRequestProcessing(t *testing.T) {
 		t.Fatal("Did not get output on output channel!")
 	}
 }
+
+func TestOTAAJoinRequestProcessing11(t *testing.T) {
+	deviceEUI, _ := protocol.EUIFromString("00-01-02-03-04-05-06-09")
+	appEUI, _ := protocol.EUIFromString("00-01-02-03-04-05-06-0A")
+	nwkKey, _ := protocol.AESKeyFromString("01020304 05060708 090A0B0C 0D0E0F10")
+
+	store := storage.NewMemoryStorage()
+	store.CreateApplication(model.Application{AppEUI: appEUI})
+	store.CreateDevice(model.Device{
+		DeviceEUI:       deviceEUI,
+		AppEUI:          appEUI,
+		State:           model.OverTheAirDevice,
+		MACVersion:      model.LoRaWAN11,
+		NwkKey:          nwkKey,
+		DevNonceHistory: make([]uint16, 0),
+	}, appEUI)
+
+	foBuffer := server.NewFrameOutputBuffer()
+	decrypter := NewDecrypter(&server.Context{
+		Storage:     store,
+		FrameOutput: &foBuffer,
+		Config:      &server.Parameters{},
+	}, make(chan server.LoRaMessage))
+
+	join := func(devNonce uint16) server.LoRaMessage {
+		payload := protocol.NewPHYPayload(protocol.JoinRequest)
+		payload.JoinRequestPayload = protocol.JoinRequestPayload{
+			DevEUI:   deviceEUI,
+			AppEUI:   appEUI,
+			DevNonce: devNonce,
+		}
+		go decrypter.processJoinRequest(server.LoRaMessage{Payload: payload})
+		select {
+		case msg := <-decrypter.Output():
+			return msg
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("Did not get output on output channel!")
+		}
+		return server.LoRaMessage{}
+	}
+
+	for i := uint32(1); i <= 2; i++ {
+		msg := join(uint16(i))
+		if msg.FrameContext.DevNonce != uint16(i) {
+			t.Fatalf("DevNonce not set in frame context: %d", msg.FrameContext.DevNonce)
+		}
+		device, err := store.GetDeviceByEUI(deviceEUI)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if device.JoinNonce != i {
+			t.Fatalf("Expected JoinNonce %d but got %d", i, device.JoinNonce)
+		}
+		if device.NwkSKey == device.SNwkSIntKey || device.SNwkSIntKey == device.NwkSEncKey || device.NwkSKey == device.AppSKey {
+			t.Fatal("Session keys should be different")
+		}
+		joinAccept, err := foBuffer.GetPHYPayloadForDevice(&device, &msg.FrameContext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !joinAccept.JoinAcceptPayload.DLSettings.OptNeg {
+			t.Fatal("OptNeg should be set for 1.1 devices")
+		}
+	}
+}
+
+func TestOTAAJoinRequestProfile(t *testing.T) {
+	deviceEUI, _ := protocol.EUIFromString("00-01-02-03-04-05-06-0B")
+	appEUI, _ := protocol.EUIFromString("00-01-02-03-04-05-06-0C")
+
+	store := storage.NewMemoryStorage()
+	profile := model.NewDeviceProfile()
+	profile.Name = "slow"
+	profile.RX1Delay = 3
+	profile.RX1DROffset = 2
+	profile.RX2DataRate = 3
+	profile.RX2Frequency = 869.525
+	profile.SupportsJoin = false
+	profile, err := store.CreateDeviceProfile(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.CreateApplication(model.Application{AppEUI: appEUI})
+	store.CreateDevice(model.Device{
+		DeviceEUI:       deviceEUI,
+		AppEUI:          appEUI,
+		State:           model.OverTheAirDevice,
+		ProfileID:       profile.ID,
+		DevNonceHistory: make([]uint16, 0),
+	}, appEUI)
+
+	foBuffer := server.NewFrameOutputBuffer()
+	decrypter := NewDecrypter(&server.Context{
+		Storage:     store,
+		FrameOutput: &foBuffer,
+		Config:      &server.Parameters{},
+	}, make(chan server.LoRaMessage))
+
+	payload := protocol.NewPHYPayload(protocol.JoinRequest)
+	payload.JoinRequestPayload = protocol.JoinRequestPayload{
+		DevEUI:   deviceEUI,
+		AppEUI:   appEUI,
+		DevNonce: 1,
+	}
+	if decrypter.processJoinRequest(server.LoRaMessage{Payload: payload}) {
+		t.Fatal("Join should be rejected when the profile doesn't support joins")
+	}
+
+	profile.SupportsJoin = true
+	if err := store.UpdateDeviceProfile(profile); err != nil {
+		t.Fatal(err)
+	}
+	go decrypter.processJoinRequest(server.LoRaMessage{Payload: payload})
+	var msg server.LoRaMessage
+	select {
+	case msg = <-decrypter.Output():
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Did not get output on output channel!")
+	}
+
+	device, err := store.GetDeviceByEUI(deviceEUI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if device.RX1Delay != 3 || device.RX1DROffset != 2 || device.RX2DataRate != 3 || device.RX2Frequency != 869.525 {
+		t.Fatalf("Profile settings not applied to device: %+v", device)
+	}
+	joinAccept, err := foBuffer.GetPHYPayloadForDevice(&device, &msg.FrameContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if joinAccept.JoinAcceptPayload.RxDelay != 3 {
+		t.Fatalf("Expected RxDelay 3 but got %d", joinAccept.JoinAcceptPayload.RxDelay)
+	}
+	if joinAccept.JoinAcceptPayload.DLSettings.RX1DRoffset != 2 || joinAccept.JoinAcceptPayload.DLSettings.RX2DataRate != 3 {
+		t.Fatalf("DLSettings doesn't match profile: %+v", joinAccept.JoinAcceptPayload.DLSettings)
+	}
+}
+
+func TestOTAAJoinRequestChannelMask(t *testing.T) {
+	deviceEUI, _ := protocol.EUIFromString("00-01-02-03-04-05-06-0D")
+	appEUI, _ := protocol.EUIFromString("00-01-02-03-04-05-06-0E")
+
+	store := storage.NewMemoryStorage()
+	store.CreateApplication(model.Application{AppEUI: appEUI})
+	store.CreateDevice(model.Device{
+		DeviceEUI:       deviceEUI,
+		AppEUI:          appEUI,
+		State:           model.OverTheAirDevice,
+		DevNonceHistory: make([]uint16, 0),
+	}, appEUI)
+
+	foBuffer := server.NewFrameOutputBuffer()
+	decrypter := NewDecrypter(&server.Context{
+		Storage:     store,
+		FrameOutput: &foBuffer,
+		Config:      &server.Parameters{},
+	}, make(chan server.LoRaMessage))
+
+	payload := protocol.NewPHYPayload(protocol.JoinRequest)
+	payload.JoinRequestPayload = protocol.JoinRequestPayload{
+		DevEUI:   deviceEUI,
+		AppEUI:   appEUI,
+		DevNonce: 1,
+	}
+	us, _ := band.NewBand(band.US915Band)
+	mask := band.ChannelMask{0xFF00, 0, 0, 0, 0x0002}
+	input := server.LoRaMessage{Payload: payload}
+	input.FrameContext.GatewayContext.Radio = server.RadioContext{Band: us, Channels: mask}
+
+	go decrypter.processJoinRequest(input)
+	var msg server.LoRaMessage
+	select {
+	case msg = <-decrypter.Output():
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Did not get output on output channel!")
+	}
+
+	device, err := store.GetDeviceByEUI(deviceEUI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	joinAccept, err := foBuffer.GetPHYPayloadForDevice(&device, &msg.FrameContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfList := joinAccept.JoinAcceptPayload.CFList
+	if cfList.Type != protocol.CFListChannelMask || cfList.ChMask != mask {
+		t.Fatalf("Expected channel mask in CFList but got %+v", cfList)
+	}
+	// The JoinAccept uses the band's RX2 data rate and the device keeps the
+	// settings
+	dlSettings := joinAccept.JoinAcceptPayload.DLSettings
+	if dlSettings.RX1DRoffset != 0 || dlSettings.RX2DataRate != us.Configuration().RX2DataRate {
+		t.Fatalf("Expected band default DLSettings but got %+v", dlSettings)
+	}
+	if device.RX1DROffset != 0 || device.RX2DataRate != us.Configuration().RX2DataRate || device.RX1Delay != 1 {
+		t.Fatalf("Expected DLSettings to be stored on the device but got %+v", device)
+	}
+}
+
+func TestOTAAJoinRequestExtraChannels(t *testing.T) {
+	deviceEUI, _ := protocol.EUIFromString("00-01-02-03-04-05-06-0F")
+	appEUI, _ := protocol.EUIFromString("00-01-02-03-04-05-06-10")
+
+	store := storage.NewMemoryStorage()
+	store.CreateApplication(model.Application{AppEUI: appEUI})
+	store.CreateDevice(model.Device{
+		DeviceEUI:       deviceEUI,
+		AppEUI:          appEUI,
+		State:           model.OverTheAirDevice,
+		DevNonceHistory: make([]uint16, 0),
+	}, appEUI)
+
+	foBuffer := server.NewFrameOutputBuffer()
+	decrypter := NewDecrypter(&server.Context{
+		Storage:     store,
+		FrameOutput: &foBuffer,
+		Config:      &server.Parameters{ExtraChannels: []string{"EU868:867.1", "EU868:867.3:869.525", "AS923:922.0"}},
+	}, make(chan server.LoRaMessage))
+
+	payload := protocol.NewPHYPayload(protocol.JoinRequest)
+	payload.JoinRequestPayload = protocol.JoinRequestPayload{
+		DevEUI:   deviceEUI,
+		AppEUI:   appEUI,
+		DevNonce: 1,
+	}
+	eu, _ := band.NewBand(band.EU868Band)
+	input := server.LoRaMessage{Payload: payload}
+	input.FrameContext.GatewayContext.Radio = server.RadioContext{Band: eu}
+
+	go decrypter.processJoinRequest(input)
+	var msg server.LoRaMessage
+	select {
+	case msg = <-decrypter.Output():
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Did not get output on output channel!")
+	}
+
+	device, err := store.GetDeviceByEUI(deviceEUI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The downlink frequency is set later with DlChannelReq
+	if len(device.Channels) != 2 || device.Channels[0] != (band.Channel{Frequency: 867.1}) ||
+		device.Channels[1] != (band.Channel{Frequency: 867.3}) {
+		t.Fatalf("Device channels not set from the CFList: %+v", device.Channels)
+	}
+	joinAccept, err := foBuffer.GetPHYPayloadForDevice(&device, &msg.FrameContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfList := joinAccept.JoinAcceptPayload.CFList
+	if cfList.Type != protocol.CFListFrequencies || cfList.Frequencies != [5]uint32{867100000, 867300000} {
+		t.Fatalf("Expected extra channels in CFList but got %+v", cfList)
+	}
+}
@@ -14,10 +14,11 @@ import (
 //
 // The pipeline is roughly built like this:
 //
-//	GW Forwarder -> Decoder -> Decrypter -> MAC Processor
+//	GW Forwarder -> Decoder -> Deduplicator -> Decrypter -> MAC Processor
 //	      => Scheduler => Encoder -> GW Forwarder
 type Pipeline struct {
 	Decoder      *Decoder
+	Deduplicator *Deduplicator
 	Decrypter    *Decrypter
 	MACProcessor *MACProcessor
 	Scheduler    *Scheduler
@@ -27,6 +28,7 @@ type Pipeline struct {
 // Start launches the pipeline
 func (p *Pipeline) Start() {
 	go p.Decoder.Start()
+	go p.Deduplicator.Start()
 	go p.Decrypter.Start()
 	go p.MACProcessor.Start()
 	go p.Scheduler.Start()
@@ -41,8 +43,11 @@ func NewPipeline(context *server.Context, forwarder GwForwarder) *Pipeline {
 	lg.Debug("Creating decoder...")
 	ret.Decoder = NewDecoder(context, forwarder.Output())
 
+	lg.Debug("Creating deduplicator...")
+	ret.Deduplicator = NewDeduplicator(context, ret.Decoder.Output())
+
 	lg.Debug("Creating decrypter...")
-	ret.Decrypter = NewDecrypter(context, ret.Decoder.Output())
+	ret.Decrypter = NewDecrypter(context, ret.Deduplicator.Output())
 
 	lg.Debug("Creating MAC processor...")
 	ret.MACProcessor = NewMACProcessor(context, ret.Decrypter.Output())
@@ -109,7 +109,7 @@ func newTestForwarder() *testForwarder {
 }
 
 // Helper method to build PHYPayload message
-func newPHYPayloadMessage(messageType protocol.MType, devAddr protocol.DevAddr, fc uint16) *protocol.PHYPayload {
+func newPHYPayloadMessage(messageType protocol.MType, devAddr protocol.DevAddr, fc uint32) *protocol.PHYPayload {
 	// Bypass the forwarder and emulate a new message into the pipeline
 	ret := protocol.NewPHYPayload(messageType)
 	ret.MACPayload.FHDR.DevAddr = devAddr
@@ -165,6 +165,8 @@ func (c *testContext) findDownstreamMessage(eui protocol.EUI, created int64) (mo
 func newTestContext(t *testing.T) testContext {
 	ret := testContext{t: t}
 	ret.config = server.NewDefaultConfig()
+	// Each frame is only sent once so there's no need to wait for duplicates
+	ret.config.DedupWindow = 0
 	ret.datastore = storage.NewMemoryStorage()
 	frameOutput := server.NewFrameOutputBuffer()
 	keyGenerator, _ := keys.NewEUIKeyGenerator(ret.config.RootMA(), uint32(ret.config.NetworkID), ret.datastore)
@@ -1,33 +1,60 @@
 package processor
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/lab5e/lospan/pkg/band"
 	"github.com/lab5e/lospan/pkg/lg"
 	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/protocol"
 	"github.com/lab5e/lospan/pkg/server"
+	"github.com/lab5e/lospan/pkg/storage"
 )
 
 // Scheduler is the process that schedules downlink frames. The sceduler reads
 // from a command notifier channel and will schedule a frame to be sent to the
 // device when it receives notification of an uplink. If the frame to be sent
 // is empty it won't generate any output.
+//
+// Class B and C devices can receive frames without sending an uplink first so
+// the scheduler polls the storage for queued downstream messages. Class B
+// devices get the message in the next ping slot and class C devices get the
+// message right away on the RX2 frequency.
 type Scheduler struct {
-	notifier     <-chan server.LoRaMessage // Input channel; messages on this channel is received
-	output       chan server.LoRaMessage   // Output channel; message will be sent when put on this channel
-	scheduled    map[protocol.EUI]bool     // Map with devaddr for scheduled devices
-	completed    chan protocol.EUI         // Channel for completed schedules
-	context      *server.Context           // Server context
-	fixedRxDelay time.Duration
+	notifier      <-chan server.LoRaMessage            // Input channel; messages on this channel is received
+	output        chan server.LoRaMessage              // Output channel; message will be sent when put on this channel
+	scheduled     map[protocol.EUI]bool                // Map with devaddr for scheduled devices
+	lastSeen      map[protocol.EUI]server.FrameContext // Context for the last uplink from each device
+	completed     chan protocol.EUI                    // Channel for completed schedules
+	context       *server.Context                      // Server context
+	fixedRxDelay  time.Duration
+	queueInterval time.Duration
 }
 
 // DefaultRXDelay is the default delay
 const DefaultRXDelay time.Duration = 200 * time.Millisecond
 
-// calculate the rx delay/wait time for the device with the specified DevAddr. Returned
-// value is in us. (note that 1 second is incorrect)
-// TODO(stalehd): Use device method for this.
+// DefaultQueueInterval is the default polling interval for class B and C
+// downlinks
+const DefaultQueueInterval time.Duration = time.Second
+
+// classBLead is the minimum time before a class B downlink is sent. The
+// frame must reach the gateway before the ping slot starts.
+const classBLead = time.Second
+
+// phyOverhead is the number of bytes the MHDR, FHDR, FPort and MIC fields
+// add to the payload.
+const phyOverhead = 13
+
+// gatewayLead is the time needed to encode a downlink and get it to the
+// gateway before the receive window opens.
+const gatewayLead = 200 * time.Millisecond
+
+// calculateRxDelay returns the time to wait before the downlink for an uplink is
+// built. The wait lets the application and the MAC processor queue data for the
+// device. The receive window is selected when the downlink is built.
 func (s *Scheduler) calculateRxDelay(receivedMessage server.LoRaMessage) time.Duration {
 	spentTime := time.Since(receivedMessage.FrameContext.GatewayContext.ReceivedAt)
 	delay := s.fixedRxDelay - spentTime
@@ -42,20 +69,16 @@ func (s *Scheduler) SetRXDelay(newDelay time.Duration) {
 	s.fixedRxDelay = newDelay
 }
 
-// Get the message to be sent from the device aggregator
-func (s *Scheduler) buildMessageToSend(device model.Device, frameContext server.FrameContext) (server.LoRaMessage, error) {
-
-	payload, err := s.context.FrameOutput.GetPHYPayloadForDevice(&device, &frameContext)
-	if err != nil {
-		lg.Debug("No data for device %s to send: %v", device.DeviceEUI, err)
-	}
-	return server.LoRaMessage{
-		Payload:      payload,
-		FrameContext: frameContext,
-	}, err
+// SetQueueInterval adjusts the polling interval for class B and C downlinks.
+// This is only for testing and must be set before the scheduler is started.
+func (s *Scheduler) SetQueueInterval(newInterval time.Duration) {
+	s.queueInterval = newInterval
 }
 
-// sendAt sends a message at a specified time
+// sendAt sends a message at a specified time. The receive window and data
+// rate for class A downlinks are selected before the payload is built so the
+// payload is sized for the downlink data rate. Class B and C downlinks use the
+// frame context as is.
 func (s *Scheduler) sendAt(delay time.Duration,
 	device model.Device,
 	output chan<- server.LoRaMessage,
@@ -63,7 +86,13 @@ func (s *Scheduler) sendAt(delay time.Duration,
 	doneChannel chan protocol.EUI) {
 
 	time.Sleep(delay)
-	payload, err := s.buildMessageToSend(device, frameContext)
+	var payload server.LoRaMessage
+	var err error
+	if frameContext.GatewayContext.Immediate || frameContext.GatewayContext.GPSTime != 0 {
+		payload, err = s.buildScheduledMessage(device, frameContext)
+	} else {
+		payload, err = s.buildClassAMessage(device, frameContext)
+	}
 	// If there's an error there's no data to send.
 	if err == nil {
 		output <- payload
@@ -71,9 +100,302 @@ func (s *Scheduler) sendAt(delay time.Duration,
 	doneChannel <- device.DeviceEUI
 }
 
+// buildClassAMessage builds the class A downlink for an uplink. The payload is
+// built for the receive window the gateway can reach in time and the airtime
+// is reserved before the data is taken from the frame output buffer. The
+// downlink is moved to RX2 if RX1 is out of airtime or exceeds the dwell time.
+// If neither window can be used the frame is kept for the next uplink.
+func (s *Scheduler) buildClassAMessage(device model.Device, uplink server.FrameContext) (server.LoRaMessage, error) {
+	joinAccept := s.context.FrameOutput.JoinAcceptPending(device.DeviceEUI)
+	elapsed := time.Since(uplink.GatewayContext.ReceivedAt)
+
+	var ret server.LoRaMessage
+	var rejected protocol.PHYPayload
+	var reserveErr error
+	reserve := func(payload protocol.PHYPayload) error {
+		rejected = payload
+		reserveErr = s.reserveAirtime(ret.FrameContext, phyPayloadSize(payload))
+		return reserveErr
+	}
+	var err error
+	for _, rx1 := range []bool{true, false} {
+		ret.FrameContext, err = classAFrameContext(device, uplink, joinAccept, elapsed, rx1)
+		if err != nil {
+			lg.Warning("Unable to schedule downlink to device %s: %v", device.DeviceEUI, err)
+			return ret, err
+		}
+		reserveErr = nil
+		ret.Payload, err = s.context.FrameOutput.ReservePHYPayloadForDevice(&device, &ret.FrameContext, reserve)
+		if err == nil {
+			return ret, nil
+		}
+		if reserveErr == nil {
+			lg.Debug("No data for device %s to send: %v", device.DeviceEUI, err)
+			return ret, err
+		}
+		if ret.FrameContext.GatewayContext.Radio.Window != band.RX1 {
+			break
+		}
+		lg.Info("Unable to use RX1 on gateway %s for device %s: %v. Using RX2",
+			uplink.GatewayContext.Gateway.GatewayEUI, device.DeviceEUI, err)
+	}
+	lg.Warning("Unable to send %s to device %s on gateway %s: %v. The frame is kept for the next uplink",
+		frameDescription(rejected), device.DeviceEUI, uplink.GatewayContext.Gateway.GatewayEUI, err)
+	return ret, err
+}
+
+// buildScheduledMessage builds a class B or C downlink. The airtime for the
+// complete frame, including the pending MAC commands, is reserved before the
+// data is taken from the frame output buffer. The frame is kept for later if
+// it doesn't fit within the gateway's duty cycle. The queued message is marked
+// as sent when the frame is built.
+func (s *Scheduler) buildScheduledMessage(device model.Device, frameContext server.FrameContext) (server.LoRaMessage, error) {
+	ret := server.LoRaMessage{FrameContext: frameContext}
+	gatewayEUI := frameContext.GatewayContext.Gateway.GatewayEUI
+	var reserveErr error
+	reserve := func(payload protocol.PHYPayload) error {
+		if reserveErr = s.reserveAirtime(ret.FrameContext, phyPayloadSize(payload)); reserveErr != nil {
+			lg.Info("Unable to send %s on gateway %s: %v. Delaying downlink to device %s",
+				frameDescription(payload), gatewayEUI, reserveErr, device.DeviceEUI)
+		}
+		return reserveErr
+	}
+	var err error
+	ret.Payload, err = s.context.FrameOutput.ReservePHYPayloadForDevice(&device, &ret.FrameContext, reserve)
+	if err != nil {
+		if reserveErr == nil {
+			lg.Debug("No data for device %s to send: %v", device.DeviceEUI, err)
+		}
+		return ret, err
+	}
+	if ret.FrameContext.Downlink != nil && s.context.Storage != nil {
+		if err := s.context.Storage.SetMessageSentTime(device.DeviceEUI, ret.FrameContext.PayloadCreate, time.Now().UnixNano(), device.FCntUp); err != nil {
+			lg.Warning("Unable to set sent time for message to device %s: %v", device.DeviceEUI, err)
+		}
+	}
+	return ret, nil
+}
+
+// frameDescription returns a short description of a downlink frame for the
+// logs
+func frameDescription(payload protocol.PHYPayload) string {
+	if payload.MHDR.MType == protocol.JoinAccept {
+		return fmt.Sprintf("%s (%d bytes)", payload.MHDR.MType, phyPayloadSize(payload))
+	}
+	mac := payload.MACPayload
+	return fmt.Sprintf("%s (port %d, %d bytes payload, %d MAC commands, %d bytes)",
+		payload.MHDR.MType, mac.FPort, len(mac.FRMPayload), mac.FHDR.FOpts.Size()+mac.MACCommands.Size(), phyPayloadSize(payload))
+}
+
+// classAFrameContext returns the frame context for a class A downlink. The
+// downlink is sent in RX1 if it can reach the gateway before the window opens
+// and rx1 is set, otherwise in RX2. The delays come from the band and the
+// device settings. JoinAccept messages use the join accept delays and the
+// default RX1 data rate offset and RX2 settings since the device hasn't got
+// its settings yet [6.2.5].
+func classAFrameContext(device model.Device, frameContext server.FrameContext, joinAccept bool, elapsed time.Duration, rx1 bool) (server.FrameContext, error) {
+	ret := frameContext
+	radio := &ret.GatewayContext.Radio
+	plan := radio.Band
+	if plan == nil {
+		return ret, errors.New("no frequency plan for uplink")
+	}
+	config := plan.Configuration()
+	rx1Delay := device.GetRX1Window(config)
+	rx2Delay := device.GetRX2Window(config)
+	rx1DROffset := device.RX1DROffset
+	rx2 := device.RX2Parameters(plan)
+	if joinAccept {
+		rx1Delay = time.Duration(config.JoinAccepDelay1) * time.Second
+		rx2Delay = time.Duration(config.JoinAccepDelay2) * time.Second
+		rx1DROffset = 0
+		rx2 = plan.GetRX2Parameters()
+	}
+
+	downlink := rx2
+	radio.Window = band.RX2
+	switch {
+	case rx1 && elapsed+gatewayLead < rx1Delay:
+		uplinkDataRate, err := plan.GetDataRate(radio.DataRate)
+		if err != nil {
+			return ret, err
+		}
+		downlink, err = plan.GetRX1Parameters(radio.Channel, radio.Frequency, uplinkDataRate, rx1DROffset)
+		if err != nil {
+			return ret, err
+		}
+		if !joinAccept {
+			// The device might use a different RX1 frequency (via DlChannelReq)
+			downlink.Frequency = device.RX1Frequency(radio.Frequency, downlink.Frequency)
+		}
+		radio.Window = band.RX1
+	case elapsed+gatewayLead >= rx2Delay:
+		return ret, fmt.Errorf("missed both receive windows (%v since uplink)", elapsed)
+	case rx1:
+		lg.Info("Missed RX1 for device %s (%v since uplink). Using RX2", device.DeviceEUI, elapsed)
+	}
+	encoding, err := plan.Encoding(downlink.DataRate)
+	if err != nil {
+		return ret, err
+	}
+	radio.Frequency = downlink.Frequency
+	radio.DataRate = encoding.GatewayDataRate()
+	radio.RX1Delay = uint8(rx1Delay / time.Second)
+	radio.RX2Delay = uint8(rx2Delay / time.Second)
+	ret.GatewayContext.Deadline = float64(radio.TxDelay())
+	return ret, nil
+}
+
+// classCFrameContext returns the frame context for a class C downlink. The
+// downlink uses the gateway that received the last uplink from the device and
+// is sent immediately with the RX2 frequency and data rate.
+func classCFrameContext(device model.Device, lastSeen server.FrameContext) (server.FrameContext, error) {
+	ret := lastSeen
+	ret.Device = device
+	ret.Receptions = nil
+	plan := ret.GatewayContext.Radio.Band
+	if plan == nil {
+		return ret, errors.New("no frequency plan for last uplink")
+	}
+	rx2 := device.RX2Parameters(plan)
+	encoding, err := plan.Encoding(rx2.DataRate)
+	if err != nil {
+		return ret, err
+	}
+	ret.GatewayContext.RawMessage = nil
+	ret.GatewayContext.Radio.Frequency = rx2.Frequency
+	ret.GatewayContext.Radio.DataRate = encoding.GatewayDataRate()
+	ret.GatewayContext.Immediate = true
+	return ret, nil
+}
+
+// phyPayloadSize returns the size of the PHY payload when it is encoded
+func phyPayloadSize(payload protocol.PHYPayload) int {
+	if payload.MHDR.MType == protocol.JoinAccept {
+		// MHDR, JoinNonce, NetID, DevAddr, DLSettings, RxDelay and MIC
+		if payload.JoinAcceptPayload.CFList.Empty() {
+			return 17
+		}
+		return 33
+	}
+	mac := payload.MACPayload
+	return phyOverhead + mac.FHDR.FOpts.EncodedLength() + mac.MACCommands.EncodedLength() + len(mac.FRMPayload)
+}
+
+// reserveAirtime reserves the airtime for a PHY payload of the given size on
+// the gateway. An error is returned if the downlink doesn't fit within the
+// gateway's duty cycle for the sub-band or the band's dwell time.
+func (s *Scheduler) reserveAirtime(frameContext server.FrameContext, size int) error {
+	if s.context.DutyCycle == nil {
+		return nil
+	}
+	radio := frameContext.GatewayContext.Radio
+	dataRate, err := radio.Band.GetDataRate(radio.DataRate)
+	if err != nil {
+		return fmt.Errorf("unable to determine data rate: %v", err)
+	}
+	encoding, err := radio.Band.Encoding(dataRate)
+	if err != nil {
+		return fmt.Errorf("unable to look up encoding: %v", err)
+	}
+	return s.context.DutyCycle.Reserve(
+		frameContext.GatewayContext.Gateway.GatewayEUI,
+		radio.Band.Configuration(),
+		radio.Frequency,
+		encoding.TimeOnAir(size))
+}
+
+// classBFrameContext returns the frame context for a class B downlink. The
+// downlink uses the gateway that received the last uplink from the device and
+// is sent in the first ping slot after classBLead.
+func classBFrameContext(device model.Device, lastSeen server.FrameContext) (server.FrameContext, error) {
+	ret := lastSeen
+	ret.Device = device
+	ret.Receptions = nil
+	plan := ret.GatewayContext.Radio.Band
+	if plan == nil {
+		return ret, errors.New("no frequency plan for last uplink")
+	}
+	slot, err := protocol.NextPingSlot(protocol.TimeToGPS(time.Now().Add(classBLead)), device.DevAddr, device.PingPeriodicity)
+	if err != nil {
+		return ret, err
+	}
+	config := plan.Configuration()
+	ping := band.DownlinkParameters{
+		DataRate:  config.PingSlotDataRate,
+		Frequency: config.PingSlotFrequency(protocol.BeaconTime(slot), device.DevAddr.ToUint32()),
+	}
+	if device.PingFrequency != 0 {
+		ping = band.DownlinkParameters{DataRate: device.PingDataRate, Frequency: device.PingFrequency}
+	}
+	encoding, err := plan.Encoding(ping.DataRate)
+	if err != nil {
+		return ret, err
+	}
+	ret.GatewayContext.RawMessage = nil
+	ret.GatewayContext.Radio.Frequency = ping.Frequency
+	ret.GatewayContext.Radio.DataRate = encoding.GatewayDataRate()
+	ret.GatewayContext.GPSTime = slot
+	return ret, nil
+}
+
+// sendQueuedMessages sends the next queued downstream message to each of the
+// class B and class C devices. Devices that haven't sent an uplink yet are
+// skipped since there's no gateway to send the message through. Messages that
+// doesn't fit within the gateway's duty cycle are kept in the queue.
+func (s *Scheduler) sendQueuedMessages() {
+	if s.context.Storage == nil || s.context.FrameOutput == nil {
+		return
+	}
+	s.sendQueuedMessagesForClass(model.ClassB, classBFrameContext)
+	s.sendQueuedMessagesForClass(model.ClassC, classCFrameContext)
+}
+
+func (s *Scheduler) sendQueuedMessagesForClass(class model.DeviceClass, frameContextFor func(model.Device, server.FrameContext) (server.FrameContext, error)) {
+	devices, err := s.context.Storage.GetDevicesByClass(class)
+	if err != nil {
+		lg.Warning("Unable to retrieve class %s devices: %v", class, err)
+		return
+	}
+	for _, device := range devices {
+		lastSeen, exists := s.lastSeen[device.DeviceEUI]
+		if !exists || s.scheduled[device.DeviceEUI] {
+			continue
+		}
+		msg, err := s.context.Storage.GetNextUnsentMessage(device.DeviceEUI)
+		if err == storage.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			lg.Warning("Unable to retrieve downstream message for device %s: %v", device.DeviceEUI, err)
+			continue
+		}
+		frameContext, err := frameContextFor(device, lastSeen)
+		if err != nil {
+			lg.Warning("Unable to schedule class %s downlink for device %s: %v", class, device.DeviceEUI, err)
+			continue
+		}
+		s.context.FrameOutput.SetPayload(device.DeviceEUI, msg.Payload(), msg.Port, msg.Ack)
+		frameContext.PayloadCreate = msg.CreatedTime
+		frameContext.Downlink = &server.PayloadMessage{
+			Type:         server.DownlinkEvent,
+			Payload:      msg.Payload(),
+			FPort:        msg.Port,
+			Device:       device,
+			Application:  frameContext.Application,
+			FrameContext: frameContext,
+			Downlink:     msg,
+		}
+		s.scheduled[device.DeviceEUI] = true
+		go s.sendAt(0, device, s.output, frameContext, s.completed)
+	}
+}
+
 // Start launches the scheduler. When the notifier channel is closed it will stop
 // and the output channel will be closed.
 func (s *Scheduler) Start() {
+	queueTicker := time.NewTicker(s.queueInterval)
+	defer queueTicker.Stop()
 	for {
 		select {
 		case message, ok := <-s.notifier:
@@ -82,6 +404,7 @@ func (s *Scheduler) Start() {
 				return
 			}
 			device := message.FrameContext.Device
+			s.lastSeen[device.DeviceEUI] = message.FrameContext
 			// Check if this message is already scheduled. If so - mark it as
 			// a duplicate and skip it. Messages sent within the same n milliseconds
 			// are assumed to be the same. This assumption is most likely wrong
@@ -99,6 +422,9 @@ func (s *Scheduler) Start() {
 		case eui := <-s.completed:
 			// Message has been sent. Remove it from the map
 			delete(s.scheduled, eui)
+
+		case <-queueTicker.C:
+			s.sendQueuedMessages()
 		}
 	}
 }
@@ -112,11 +438,13 @@ func (s *Scheduler) Output() <-chan server.LoRaMessage {
 // NewScheduler creates a new scheduler.
 func NewScheduler(context *server.Context, commandNotifier <-chan server.LoRaMessage) *Scheduler {
 	return &Scheduler{
-		notifier:     commandNotifier,
-		output:       make(chan server.LoRaMessage),
-		context:      context,
-		completed:    make(chan protocol.EUI),
-		scheduled:    make(map[protocol.EUI]bool),
-		fixedRxDelay: DefaultRXDelay,
+		notifier:      commandNotifier,
+		output:        make(chan server.LoRaMessage),
+		context:       context,
+		completed:     make(chan protocol.EUI),
+		scheduled:     make(map[protocol.EUI]bool),
+		lastSeen:      make(map[protocol.EUI]server.FrameContext),
+		fixedRxDelay:  DefaultRXDelay,
+		queueInterval: DefaultQueueInterval,
 	}
 }
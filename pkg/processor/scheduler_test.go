@@ -9,6 +9,8 @@ import (
 	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/protocol"
 	"github.com/lab5e/lospan/pkg/server"
+	"github.com/lab5e/lospan/pkg/storage"
+	"github.com/stretchr/testify/require"
 )
 
 var fo = server.NewFrameOutputBuffer()
@@ -17,10 +19,12 @@ var context = server.Context{
 	FrameOutput: &fo,
 }
 
+var eu868Band, _ = band.NewBand(band.EU868Band)
+
 var frameContext = server.FrameContext{
 	GatewayContext: server.GatewayPacket{
 		Radio: server.RadioContext{
-			Band:      band.EU868{},
+			Band:      eu868Band,
 			DataRate:  "SF10BW125",
 			Frequency: 868.1,
 		},
@@ -92,7 +96,7 @@ func newFrameContext(counter uint32) server.FrameContext {
 	return server.FrameContext{
 		GatewayContext: server.GatewayPacket{
 			Radio: server.RadioContext{
-				Band:      band.EU868{},
+				Band:      eu868Band,
 				DataRate:  "SF10BW125",
 				Frequency: 868.1,
 			},
@@ -183,3 +187,341 @@ func TestSchedulerDuplicate(t *testing.T) {
 		// OK
 	}
 }
+
+func TestSchedulerClassC(t *testing.T) {
+	assert := require.New(t)
+
+	store := NewStorageTestContext()
+	frameOutput := server.NewFrameOutputBuffer()
+	dutyCycle := server.NewDutyCycleLedger()
+	context := &server.Context{Storage: store, FrameOutput: &frameOutput, DutyCycle: &dutyCycle}
+
+	deviceEUI, _ := protocol.EUIFromString("75-0A-09-3A-2C-22-69-F3")
+	device, err := store.GetDeviceByEUI(deviceEUI)
+	assert.NoError(err)
+	device.Class = model.ClassC
+	assert.NoError(store.UpdateDevice(device))
+
+	input := make(chan server.LoRaMessage)
+	defer close(input)
+	scheduler := NewScheduler(context, input)
+	scheduler.SetQueueInterval(10 * time.Millisecond)
+	go scheduler.Start()
+
+	msg := model.NewDownstreamMessage(deviceEUI, 42)
+	msg.Data = "010203"
+	assert.NoError(store.CreateDownstreamMessage(deviceEUI, msg))
+
+	// Nothing is sent until the device has been seen through a gateway
+	select {
+	case <-scheduler.Output():
+		assert.Fail("Should not send downlink before an uplink is received")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	uplink := makeRandomMessage()
+	uplink.FrameContext.Device = device
+	uplink.FrameContext.GatewayContext.Gateway.GatewayEUI = protocol.EUIFromInt64(1)
+	uplink.FrameContext.GatewayContext.Radio.Band, _ = band.NewBand(band.EU868Band)
+	input <- uplink
+
+	select {
+	case out := <-scheduler.Output():
+		assert.True(out.FrameContext.GatewayContext.Immediate)
+		assert.Equal(float32(869.525), out.FrameContext.GatewayContext.Radio.Frequency)
+		assert.Equal("SF12BW125", out.FrameContext.GatewayContext.Radio.DataRate)
+		assert.Equal([]byte{1, 2, 3}, out.Payload.MACPayload.FRMPayload)
+		assert.Equal(uint8(42), out.Payload.MACPayload.FPort)
+	case <-time.After(time.Second):
+		assert.Fail("Did not get class C downlink")
+	}
+
+	_, err = store.GetNextUnsentMessage(deviceEUI)
+	assert.Equal(storage.ErrNotFound, err, "Message should be marked as sent")
+
+	// Use up the airtime for the gateway. The next message should stay in the queue.
+	rx2 := eu868Band.Configuration().SubBand(869.525)
+	remaining := dutyCycle.Remaining(protocol.EUIFromInt64(1), rx2)
+	assert.NoError(dutyCycle.Reserve(protocol.EUIFromInt64(1), eu868Band.Configuration(), 869.525, remaining))
+	msg = model.NewDownstreamMessage(deviceEUI, 42)
+	msg.CreatedTime++
+	msg.Data = "040506"
+	assert.NoError(store.CreateDownstreamMessage(deviceEUI, msg))
+	select {
+	case <-scheduler.Output():
+		assert.Fail("Should not send when the duty cycle is exceeded")
+	case <-time.After(100 * time.Millisecond):
+	}
+	_, err = store.GetNextUnsentMessage(deviceEUI)
+	assert.NoError(err, "Message should still be queued")
+}
+
+func TestSchedulerClassB(t *testing.T) {
+	assert := require.New(t)
+
+	store := NewStorageTestContext()
+	frameOutput := server.NewFrameOutputBuffer()
+	context := &server.Context{Storage: store, FrameOutput: &frameOutput}
+
+	deviceEUI, _ := protocol.EUIFromString("75-0A-09-3A-2C-22-69-F3")
+	device, err := store.GetDeviceByEUI(deviceEUI)
+	assert.NoError(err)
+	device.Class = model.ClassB
+	device.PingPeriodicity = 2
+	assert.NoError(store.UpdateDevice(device))
+
+	input := make(chan server.LoRaMessage)
+	defer close(input)
+	scheduler := NewScheduler(context, input)
+	scheduler.SetQueueInterval(10 * time.Millisecond)
+	go scheduler.Start()
+
+	msg := model.NewDownstreamMessage(deviceEUI, 42)
+	msg.Data = "010203"
+	assert.NoError(store.CreateDownstreamMessage(deviceEUI, msg))
+
+	uplink := makeRandomMessage()
+	uplink.FrameContext.Device = device
+	uplink.FrameContext.GatewayContext.Gateway.GatewayEUI = protocol.EUIFromInt64(1)
+	uplink.FrameContext.GatewayContext.Radio.Band, _ = band.NewBand(band.EU868Band)
+	start := protocol.TimeToGPS(time.Now())
+	input <- uplink
+
+	select {
+	case out := <-scheduler.Output():
+		gwContext := out.FrameContext.GatewayContext
+		assert.False(gwContext.Immediate)
+		assert.Equal(float32(869.525), gwContext.Radio.Frequency)
+		assert.Equal("SF9BW125", gwContext.Radio.DataRate)
+		assert.Equal([]byte{1, 2, 3}, out.Payload.MACPayload.FRMPayload)
+
+		// The downlink must be sent in one of the device's ping slots
+		assert.True(gwContext.GPSTime >= start+classBLead)
+		beaconTime := protocol.BeaconTime(gwContext.GPSTime)
+		offset, err := protocol.PingOffset(beaconTime, device.DevAddr, device.PingPeriodicity)
+		assert.NoError(err)
+		slot := (gwContext.GPSTime - beaconTime - protocol.BeaconReserved) / protocol.PingSlotLength
+		assert.Equal(time.Duration(0), (gwContext.GPSTime-beaconTime-protocol.BeaconReserved)%protocol.PingSlotLength)
+		assert.Equal(offset, int(slot)%protocol.PingPeriod(device.PingPeriodicity))
+	case <-time.After(time.Second):
+		assert.Fail("Did not get class B downlink")
+	}
+}
+
+func TestSchedulerClassAWindows(t *testing.T) {
+	assert := require.New(t)
+
+	device := model.NewDevice()
+	uplink := frameContext
+	uplink.GatewayContext.Radio.Band = eu868Band
+	uplink.GatewayContext.Radio.DataRate = "SF9BW125"
+
+	// RX1 uses the uplink frequency and the data rate with the RX1 offset
+	device.RX1DROffset = 1
+	fc, err := classAFrameContext(device, uplink, false, 100*time.Millisecond, true)
+	assert.NoError(err)
+	assert.Equal(band.RX1, fc.GatewayContext.Radio.Window)
+	assert.Equal(uint8(1), fc.GatewayContext.Radio.TxDelay())
+	assert.Equal(float32(868.1), fc.GatewayContext.Radio.Frequency)
+	assert.Equal("SF10BW125", fc.GatewayContext.Radio.DataRate)
+	assert.Equal(float64(1), fc.GatewayContext.Deadline)
+
+	// Fall back to RX2 when RX1 can't be reached
+	fc, err = classAFrameContext(device, uplink, false, 900*time.Millisecond, true)
+	assert.NoError(err)
+	assert.Equal(band.RX2, fc.GatewayContext.Radio.Window)
+	assert.Equal(uint8(2), fc.GatewayContext.Radio.TxDelay())
+	assert.Equal(float32(869.525), fc.GatewayContext.Radio.Frequency)
+	assert.Equal("SF12BW125", fc.GatewayContext.Radio.DataRate)
+
+	// ...and give up when both windows are missed
+	_, err = classAFrameContext(device, uplink, false, 1900*time.Millisecond, true)
+	assert.Error(err)
+
+	// The device's RX1 delay and RX2 settings are used
+	device.RX1Delay = 3
+	device.RX2Frequency = 869.1
+	device.RX2DataRate = 3
+	fc, err = classAFrameContext(device, uplink, false, 2900*time.Millisecond, true)
+	assert.NoError(err)
+	assert.Equal(uint8(3), fc.GatewayContext.Radio.RX1Delay)
+	assert.Equal(uint8(4), fc.GatewayContext.Radio.TxDelay())
+	assert.Equal(float32(869.1), fc.GatewayContext.Radio.Frequency)
+	assert.Equal("SF9BW125", fc.GatewayContext.Radio.DataRate)
+
+	// The device's RX2 data rate is used with the band's RX2 frequency
+	device.RX2Frequency = 0
+	fc, err = classAFrameContext(device, uplink, false, 2900*time.Millisecond, true)
+	assert.NoError(err)
+	assert.Equal(float32(869.525), fc.GatewayContext.Radio.Frequency)
+	assert.Equal("SF9BW125", fc.GatewayContext.Radio.DataRate)
+
+	// JoinAccept messages use the join accept delays and the default settings
+	fc, err = classAFrameContext(device, uplink, true, 4900*time.Millisecond, true)
+	assert.NoError(err)
+	assert.Equal(band.RX2, fc.GatewayContext.Radio.Window)
+	assert.Equal(uint8(6), fc.GatewayContext.Radio.TxDelay())
+	assert.Equal(float32(869.525), fc.GatewayContext.Radio.Frequency)
+	fc, err = classAFrameContext(device, uplink, true, 0, true)
+	assert.NoError(err)
+	assert.Equal(uint8(5), fc.GatewayContext.Radio.TxDelay())
+	assert.Equal("SF9BW125", fc.GatewayContext.Radio.DataRate)
+}
+
+func TestSchedulerClassADutyCycle(t *testing.T) {
+	assert := require.New(t)
+
+	dutyCycle := server.NewDutyCycleLedger()
+	frameOutput := server.NewFrameOutputBuffer()
+	scheduler := NewScheduler(&server.Context{DutyCycle: &dutyCycle, FrameOutput: &frameOutput}, make(chan server.LoRaMessage))
+
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x0A0B0C0E)
+	gw := protocol.EUIFromInt64(1)
+	uplink := frameContext
+	uplink.GatewayContext.Gateway.GatewayEUI = gw
+	uplink.GatewayContext.Radio.DataRate = "SF9BW125"
+	uplink.GatewayContext.ReceivedAt = time.Now()
+
+	frameOutput.SetPayload(device.DeviceEUI, make([]byte, 7), 1, false)
+	msg, err := scheduler.buildClassAMessage(device, uplink)
+	assert.NoError(err)
+	assert.Equal(band.RX1, msg.FrameContext.GatewayContext.Radio.Window)
+	_, err = scheduler.buildClassAMessage(device, uplink)
+	assert.Error(err, "The frame should be taken from the buffer")
+
+	// Use RX2 when the RX1 sub-band is out of airtime
+	config := eu868Band.Configuration()
+	rx1 := config.SubBand(868.1)
+	assert.NoError(dutyCycle.Reserve(gw, config, 868.1, dutyCycle.Remaining(gw, rx1)))
+	frameOutput.SetPayload(device.DeviceEUI, make([]byte, 7), 1, false)
+	msg, err = scheduler.buildClassAMessage(device, uplink)
+	assert.NoError(err)
+	assert.Equal(band.RX2, msg.FrameContext.GatewayContext.Radio.Window)
+	assert.Equal(float32(869.525), msg.FrameContext.GatewayContext.Radio.Frequency)
+
+	// ...and keep the frame when both are out of airtime
+	rx2 := config.SubBand(869.525)
+	assert.NoError(dutyCycle.Reserve(gw, config, 869.525, dutyCycle.Remaining(gw, rx2)))
+	frameOutput.SetPayload(device.DeviceEUI, make([]byte, 7), 1, false)
+	_, err = scheduler.buildClassAMessage(device, uplink)
+	assert.Equal(server.ErrDutyCycleExceeded, err)
+	kept, err := frameOutput.GetPHYPayloadForDevice(&device, &uplink)
+	assert.NoError(err)
+	assert.Len(kept.MACPayload.FRMPayload, 7)
+
+	// There's no dwell time limit in AS923 unless it is turned on. SF12
+	// exceeds the dwell time so DR2 (SF10) is the lowest RX1 data rate with
+	// the limit.
+	as923, _ := band.NewBand(band.AS923Band)
+	uplink.GatewayContext.Radio.Band = as923
+	uplink.GatewayContext.Radio.Frequency = 923.2
+	uplink.GatewayContext.Radio.DataRate = "SF12BW125"
+	device.RX2DataRate = as923.Configuration().RX2DataRate
+	frameOutput.SetPayload(device.DeviceEUI, make([]byte, 20), 1, false)
+	msg, err = scheduler.buildClassAMessage(device, uplink)
+	assert.NoError(err)
+	assert.Equal(band.RX1, msg.FrameContext.GatewayContext.Radio.Window)
+	assert.Equal("SF12BW125", msg.FrameContext.GatewayContext.Radio.DataRate)
+
+	uplink.GatewayContext.Radio.Band = as923.(band.DwellTimePlan).WithDwellTime()
+	frameOutput.SetPayload(device.DeviceEUI, make([]byte, 20), 1, false)
+	msg, err = scheduler.buildClassAMessage(device, uplink)
+	assert.NoError(err)
+	assert.Equal(band.RX1, msg.FrameContext.GatewayContext.Radio.Window)
+	assert.Equal("SF10BW125", msg.FrameContext.GatewayContext.Radio.DataRate)
+	assert.Len(msg.Payload.MACPayload.FRMPayload, 11, "Payload should be limited to the dwell time size")
+
+	// A frame that exceeds the dwell time in both windows is kept
+	assert.NoError(frameOutput.AddMACCommand(device.DeviceEUI, protocol.NewDownlinkMACCommand(protocol.LinkADRReq)))
+	assert.NoError(frameOutput.AddMACCommand(device.DeviceEUI, protocol.NewDownlinkMACCommand(protocol.NewChannelReq)))
+	_, err = scheduler.buildClassAMessage(device, uplink)
+	assert.Equal(server.ErrDwellTimeExceeded, err)
+	kept, err = frameOutput.GetPHYPayloadForDevice(&device, &msg.FrameContext)
+	assert.NoError(err)
+	assert.Len(kept.MACPayload.FRMPayload, 9, "The rest of the payload should be kept")
+}
+
+func TestSchedulerClassCDutyCycle(t *testing.T) {
+	assert := require.New(t)
+
+	dutyCycle := server.NewDutyCycleLedger()
+	frameOutput := server.NewFrameOutputBuffer()
+	scheduler := NewScheduler(&server.Context{DutyCycle: &dutyCycle, FrameOutput: &frameOutput}, make(chan server.LoRaMessage))
+
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x0A0B0C0F)
+	device.RX2DataRate = eu868Band.Configuration().RX2DataRate
+	gw := protocol.EUIFromInt64(2)
+	lastSeen := frameContext
+	lastSeen.GatewayContext.Gateway.GatewayEUI = gw
+	downlink, err := classCFrameContext(device, lastSeen)
+	assert.NoError(err)
+
+	// Leave just enough airtime for the payload without the MAC commands
+	config := eu868Band.Configuration()
+	rx2 := config.SubBand(869.525)
+	encoding, err := eu868Band.Encoding(device.RX2DataRate)
+	assert.NoError(err)
+	airtime := encoding.TimeOnAir(phyOverhead + 10)
+	assert.NoError(dutyCycle.Reserve(gw, config, 869.525, dutyCycle.Remaining(gw, rx2)-airtime))
+
+	frameOutput.SetPayload(device.DeviceEUI, make([]byte, 10), 1, false)
+	assert.NoError(frameOutput.AddMACCommand(device.DeviceEUI, protocol.NewDownlinkMACCommand(protocol.LinkADRReq)))
+	_, err = scheduler.buildScheduledMessage(device, downlink)
+	assert.Equal(server.ErrDutyCycleExceeded, err, "The MAC commands should be included in the airtime")
+	assert.Equal(airtime, dutyCycle.Remaining(gw, rx2), "Nothing should be reserved")
+
+	// The frame is kept and sent through a gateway with airtime left. The
+	// airtime for the MAC commands is reserved as well.
+	other := protocol.EUIFromInt64(3)
+	downlink.GatewayContext.Gateway.GatewayEUI = other
+	msg, err := scheduler.buildScheduledMessage(device, downlink)
+	assert.NoError(err)
+	assert.Len(msg.Payload.MACPayload.FRMPayload, 10, "The payload should be kept")
+	assert.True(msg.Payload.MACPayload.FHDR.FOpts.Contains(protocol.LinkADRReq), "The MAC commands should be kept")
+	assert.Equal(encoding.TimeOnAir(phyPayloadSize(msg.Payload)), dutyCycle.Used(other, rx2))
+	assert.Greater(dutyCycle.Used(other, rx2), airtime)
+}
+
+func TestSchedulerDownlinkPayloadSize(t *testing.T) {
+	assert := require.New(t)
+
+	frameOutput := server.NewFrameOutputBuffer()
+	scheduler := NewScheduler(&server.Context{FrameOutput: &frameOutput}, make(chan server.LoRaMessage))
+
+	// The uplink uses DR5 and the RX1 offset brings the downlink to DR0. The
+	// payload is limited to the DR0 size.
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x0A0B0C0D)
+	device.RX1DROffset = 5
+	uplink := frameContext
+	uplink.GatewayContext.Radio.DataRate = "SF7BW125"
+	uplink.GatewayContext.ReceivedAt = time.Now()
+	frameOutput.SetPayload(device.DeviceEUI, make([]byte, 100), 1, false)
+
+	output := make(chan server.LoRaMessage, 1)
+	done := make(chan protocol.EUI, 1)
+	scheduler.sendAt(0, device, output, uplink, done)
+	msg := <-output
+	assert.Equal(band.RX1, msg.FrameContext.GatewayContext.Radio.Window)
+	assert.Equal("SF12BW125", msg.FrameContext.GatewayContext.Radio.DataRate)
+	sizes, err := eu868Band.MaximumPayload("SF12BW125")
+	assert.NoError(err)
+	assert.Len(msg.Payload.MACPayload.FRMPayload, int(sizes.WithoutFOpts()))
+	assert.Equal(device.DeviceEUI, <-done)
+}
+
+func TestPHYPayloadSize(t *testing.T) {
+	assert := require.New(t)
+
+	joinAccept := protocol.NewPHYPayload(protocol.JoinAccept)
+	assert.Equal(17, phyPayloadSize(joinAccept))
+	joinAccept.JoinAcceptPayload.CFList.Frequencies[0] = 867100000
+	assert.Equal(33, phyPayloadSize(joinAccept))
+
+	data := protocol.NewPHYPayload(protocol.UnconfirmedDataDown)
+	data.MACPayload.FRMPayload = []byte{1, 2, 3}
+	data.MACPayload.FHDR.FOpts.Add(protocol.NewDownlinkMACCommand(protocol.DevStatusReq))
+	assert.Equal(phyOverhead+3+1, phyPayloadSize(data))
+}
@@ -0,0 +1,90 @@
+package processor
+
+import (
+	"sync"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/lg"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+)
+
+// txParamState is the TxParamSetupReq state for a single device
+type txParamState struct {
+	pending  *protocol.MACTxParamSetupReq // The request sent to the device
+	accepted *protocol.MACTxParamSetupReq // The last request the device acknowledged
+	uplinks  int                          // Uplinks since the pending request was sent
+}
+
+// TxParamManager tells the devices in bands where the network sets the dwell
+// time limit (ie AS923) if the limit applies. The max EIRP in the request is
+// the band's default max EIRP since the TX power in LinkADRReq is relative to
+// it. The acknowledged settings are kept in memory so the devices get the
+// request again after a restart.
+type TxParamManager struct {
+	devices map[protocol.EUI]*txParamState
+	mutex   *sync.Mutex
+}
+
+// NewTxParamManager creates a new TxParamSetupReq manager instance
+func NewTxParamManager() *TxParamManager {
+	return &TxParamManager{
+		devices: make(map[protocol.EUI]*txParamState),
+		mutex:   &sync.Mutex{},
+	}
+}
+
+func (t *TxParamManager) state(deviceEUI protocol.EUI) *txParamState {
+	s, exists := t.devices[deviceEUI]
+	if !exists {
+		s = &txParamState{}
+		t.devices[deviceEUI] = s
+	}
+	return s
+}
+
+// Evaluate returns a TxParamSetupReq if the device hasn't acknowledged the
+// dwell time setting for the band. Nil is returned for bands without a dwell
+// time setting, if the device is up to date or if there's a pending request.
+func (t *TxParamManager) Evaluate(device model.Device, plan band.FrequencyPlan) protocol.MACCommand {
+	dwell, ok := plan.(band.DwellTimePlan)
+	if !ok {
+		return nil
+	}
+	req := protocol.NewDownlinkMACCommand(protocol.TxParamSetupReq).(*protocol.MACTxParamSetupReq)
+	req.DownlinkDwellTime = dwell.DwellTime()
+	req.UplinkDwellTime = dwell.DwellTime()
+	req.MaxEIRP = protocol.MaxEIRPIndex(plan.Configuration().DefaultTxPower)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	s := t.state(device.DeviceEUI)
+	if s.accepted != nil && *s.accepted == *req {
+		return nil
+	}
+	if s.pending != nil {
+		s.uplinks++
+		if s.uplinks < channelAnswerUplinks {
+			return nil
+		}
+		lg.Info("No answer to TxParamSetupReq from device %s. Sending it again", device.DeviceEUI)
+	}
+	s.pending, s.uplinks = req, 0
+	return req
+}
+
+// TxParamSetupAns handles a TxParamSetupAns from the device. True is returned
+// if a TxParamSetupReq was pending.
+func (t *TxParamManager) TxParamSetupAns(device model.Device) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	s := t.state(device.DeviceEUI)
+	if s.pending == nil {
+		lg.Warning("Got TxParamSetupAns from device %s but no TxParamSetupReq is pending", device.DeviceEUI)
+		return false
+	}
+	s.accepted, s.pending = s.pending, nil
+	return true
+}
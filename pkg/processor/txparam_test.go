@@ -0,0 +1,49 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxParamManager(t *testing.T) {
+	assert := require.New(t)
+
+	eu868, _ := band.NewBand(band.EU868Band)
+	as923, _ := band.NewBand(band.AS923Band)
+	dwellTime := as923.(band.DwellTimePlan).WithDwellTime()
+
+	manager := NewTxParamManager()
+	device := model.NewDevice()
+	device.DeviceEUI = protocol.EUIFromInt64(0x0102030405060708)
+
+	assert.Nil(manager.Evaluate(device, eu868), "EU868 has no dwell time setting")
+	assert.False(manager.TxParamSetupAns(device), "No request is pending")
+
+	req, ok := manager.Evaluate(device, as923).(*protocol.MACTxParamSetupReq)
+	assert.True(ok)
+	assert.False(req.DownlinkDwellTime)
+	assert.False(req.UplinkDwellTime)
+	assert.Equal(uint8(5), req.MaxEIRP, "Should use the default max EIRP (16 dBm)")
+	assert.Nil(manager.Evaluate(device, as923), "Should wait for the answer")
+
+	assert.True(manager.TxParamSetupAns(device))
+	assert.Nil(manager.Evaluate(device, as923), "Device should be up to date")
+
+	// Turning on the dwell time limit sends a new request
+	req, ok = manager.Evaluate(device, dwellTime).(*protocol.MACTxParamSetupReq)
+	assert.True(ok)
+	assert.True(req.DownlinkDwellTime)
+	assert.True(req.UplinkDwellTime)
+
+	// The request is sent again if there's no answer
+	for i := 1; i < channelAnswerUplinks; i++ {
+		assert.Nil(manager.Evaluate(device, dwellTime))
+	}
+	assert.NotNil(manager.Evaluate(device, dwellTime))
+	assert.True(manager.TxParamSetupAns(device))
+	assert.Nil(manager.Evaluate(device, dwellTime))
+}
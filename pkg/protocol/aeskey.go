@@ -18,6 +18,7 @@ package protocol
 import (
 	"crypto/aes"
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/hex"
 	"strings"
 )
@@ -87,6 +88,60 @@ func keyFromNonce(appKey AESKey, prefix byte, appNonce [3]byte, netID uint32, de
 	return ret, nil
 }
 
+// FNwkSIntKeyFromNonces generates the forwarding network session integrity
+// key for LoRaWAN 1.1 devices. The key is derived from the network key.
+func FNwkSIntKeyFromNonces(nwkKey AESKey, joinNonce [3]byte, joinEUI EUI, devNonce uint16) (AESKey, error) {
+	return keyFromJoinNonce(nwkKey, 1, joinNonce, joinEUI, devNonce)
+}
+
+// AppSKeyFromJoinNonce generates the application session key for LoRaWAN 1.1
+// devices. The key is derived from the application key.
+func AppSKeyFromJoinNonce(appKey AESKey, joinNonce [3]byte, joinEUI EUI, devNonce uint16) (AESKey, error) {
+	return keyFromJoinNonce(appKey, 2, joinNonce, joinEUI, devNonce)
+}
+
+// SNwkSIntKeyFromNonces generates the serving network session integrity key
+// for LoRaWAN 1.1 devices. The key is derived from the network key.
+func SNwkSIntKeyFromNonces(nwkKey AESKey, joinNonce [3]byte, joinEUI EUI, devNonce uint16) (AESKey, error) {
+	return keyFromJoinNonce(nwkKey, 3, joinNonce, joinEUI, devNonce)
+}
+
+// NwkSEncKeyFromNonces generates the network session encryption key for
+// LoRaWAN 1.1 devices. The key is derived from the network key.
+func NwkSEncKeyFromNonces(nwkKey AESKey, joinNonce [3]byte, joinEUI EUI, devNonce uint16) (AESKey, error) {
+	return keyFromJoinNonce(nwkKey, 4, joinNonce, joinEUI, devNonce)
+}
+
+// The LoRaWAN 1.1 keys use the JoinEUI instead of the NetID. The fields are
+// in the same order as they are sent over the air.
+func keyFromJoinNonce(rootKey AESKey, prefix byte, joinNonce [3]byte, joinEUI EUI, devNonce uint16) (AESKey, error) {
+	buffer := make([]byte, 16)
+	buffer[0] = prefix
+	copy(buffer[1:], joinNonce[:])
+	binary.LittleEndian.PutUint64(buffer[4:], uint64(joinEUI.ToInt64()))
+	binary.BigEndian.PutUint16(buffer[12:], devNonce)
+	return encryptBlock(rootKey, buffer)
+}
+
+// jsIntKeyFromDevEUI generates the key used for the JoinAccept MIC sent to
+// LoRaWAN 1.1 devices.
+func jsIntKeyFromDevEUI(nwkKey AESKey, devEUI EUI) (AESKey, error) {
+	buffer := make([]byte, 16)
+	buffer[0] = 6
+	binary.LittleEndian.PutUint64(buffer[1:], uint64(devEUI.ToInt64()))
+	return encryptBlock(nwkKey, buffer)
+}
+
+func encryptBlock(key AESKey, block []byte) (AESKey, error) {
+	aesCipher, err := aes.NewCipher(key.Key[:])
+	if err != nil {
+		return AESKey{}, err
+	}
+	ret := AESKey{}
+	aesCipher.Encrypt(ret.Key[:], block)
+	return ret, nil
+}
+
 // NewAESKey creates a new AES key from the secure random generator
 func NewAESKey() (AESKey, error) {
 	ret := AESKey{}
@@ -15,7 +15,10 @@ package protocol
 //See the License for the specific language governing permissions and
 //limitations under the License.
 //
-import "testing"
+import (
+	"crypto/aes"
+	"testing"
+)
 
 func TestInvalidStrings(t *testing.T) {
 	// Empty string
@@ -101,3 +104,45 @@ func TestNewAESKey(t *testing.T) {
 		t.Fatal("Got error creating key. Shouldn't get that.")
 	}
 }
+
+func TestSessionKeysFromJoinNonce(t *testing.T) {
+	joinNonce := [3]byte{01, 02, 03}
+	joinEUI := EUIFromInt64(0x0102030405060708)
+	devNonce := uint16(0xabcd)
+	nwkKey, _ := AESKeyFromString("0102 0304 0506 0708 0102 0304 0506 0708")
+	appKey, _ := AESKeyFromString("0807 0605 0403 0201 0807 0605 0403 0201")
+
+	fNwkSIntKey, err := FNwkSIntKeyFromNonces(nwkKey, joinNonce, joinEUI, devNonce)
+	if err != nil {
+		t.Fatal("Got error generating key: ", err)
+	}
+	// The fields are in the same order as in the JoinRequest and JoinAccept messages
+	block := []byte{0x01, 1, 2, 3, 8, 7, 6, 5, 4, 3, 2, 1, 0xab, 0xcd, 0, 0}
+	expected := AESKey{}
+	cipher, _ := aes.NewCipher(nwkKey.Key[:])
+	cipher.Encrypt(expected.Key[:], block)
+	if fNwkSIntKey != expected {
+		t.Fatalf("Unexpected FNwkSIntKey: %s (expected %s)", fNwkSIntKey, expected)
+	}
+
+	sNwkSIntKey, err := SNwkSIntKeyFromNonces(nwkKey, joinNonce, joinEUI, devNonce)
+	if err != nil {
+		t.Fatal("Got error generating key: ", err)
+	}
+	nwkSEncKey, err := NwkSEncKeyFromNonces(nwkKey, joinNonce, joinEUI, devNonce)
+	if err != nil {
+		t.Fatal("Got error generating key: ", err)
+	}
+	appSKey, err := AppSKeyFromJoinNonce(appKey, joinNonce, joinEUI, devNonce)
+	if err != nil {
+		t.Fatal("Got error generating key: ", err)
+	}
+	keys := []AESKey{nwkKey, appKey, fNwkSIntKey, sNwkSIntKey, nwkSEncKey, appSKey}
+	for i := range keys {
+		for j := i + 1; j < len(keys); j++ {
+			if keys[i] == keys[j] {
+				t.Fatalf("Key %d and %d are the same", i, j)
+			}
+		}
+	}
+}
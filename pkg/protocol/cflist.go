@@ -1,7 +1,89 @@
 package protocol
 
-// CFList contains region specific information on frequencies for end-devices.
+import "encoding/binary"
+
+// CFListType is the type of channel list in the CFList. The type is the last
+// byte of the CFList.
+type CFListType uint8
+
+const (
+	// CFListFrequencies is a list of channel frequencies (CFListType 0)
+	CFListFrequencies CFListType = 0
+	// CFListChannelMask is a channel mask (CFListType 1). It is used by the
+	// bands with a fixed channel plan, ie US915 and AU915.
+	CFListChannelMask CFListType = 1
+)
+
+// cfListLength is the length of the CFList when it is included in the
+// JoinAccept message
+const cfListLength = 16
+
+// CFList contains region specific information on frequencies for
+// end-devices. Bands with dynamic channels (like EU868) use a list of up to
+// five additional channel frequencies. Bands with fixed channels (like US915)
+// use a channel mask. See [6.2.5] and the band sub-chapters in the LoRaWAN
+// Regional Parameters.
 type CFList struct {
-	// Left empty until further notice. Region specific settings [6.2.5], see
-	// [2.1.4] in LoRaWAN Regional Parameters for EU868
+	Type        CFListType
+	Frequencies [5]uint32 // Channel frequencies in Hz (CFListFrequencies). 0 means no channel
+	ChMask      [5]uint16 // Channel mask (CFListChannelMask). Bit n in ChMask[i] is channel 16*i+n
+}
+
+// Empty returns true if the CFList has no frequencies or channels. Empty
+// lists are omitted from the JoinAccept message.
+func (c *CFList) Empty() bool {
+	return *c == CFList{}
+}
+
+func (c *CFList) encode(buffer []byte, pos *int) error {
+	if buffer == nil || pos == nil {
+		return ErrNilError
+	}
+	if len(buffer) < (*pos + cfListLength) {
+		return ErrBufferTruncated
+	}
+	p := *pos
+	switch c.Type {
+	case CFListChannelMask:
+		for i, m := range c.ChMask {
+			binary.LittleEndian.PutUint16(buffer[p+2*i:], m)
+		}
+		for i := 10; i < cfListLength-1; i++ {
+			buffer[p+i] = 0
+		}
+	default:
+		for i, f := range c.Frequencies {
+			freq := f / 100
+			buffer[p+3*i+0] = byte(freq)
+			buffer[p+3*i+1] = byte(freq >> 8)
+			buffer[p+3*i+2] = byte(freq >> 16)
+		}
+	}
+	buffer[p+cfListLength-1] = byte(c.Type)
+	*pos += cfListLength
+	return nil
+}
+
+func (c *CFList) decode(buffer []byte, pos *int) error {
+	if buffer == nil || pos == nil {
+		return ErrNilError
+	}
+	if len(buffer) < (*pos + cfListLength) {
+		return ErrBufferTruncated
+	}
+	p := *pos
+	*c = CFList{Type: CFListType(buffer[p+cfListLength-1])}
+	switch c.Type {
+	case CFListChannelMask:
+		for i := range c.ChMask {
+			c.ChMask[i] = binary.LittleEndian.Uint16(buffer[p+2*i:])
+		}
+	default:
+		for i := range c.Frequencies {
+			freq := uint32(buffer[p+3*i]) | uint32(buffer[p+3*i+1])<<8 | uint32(buffer[p+3*i+2])<<16
+			c.Frequencies[i] = freq * 100
+		}
+	}
+	*pos += cfListLength
+	return nil
 }
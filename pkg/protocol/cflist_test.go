@@ -0,0 +1,64 @@
+package protocol
+
+import "testing"
+
+func TestCFListEncodeDecode(t *testing.T) {
+	lists := []CFList{
+		{Type: CFListFrequencies, Frequencies: [5]uint32{867100000, 867300000, 867500000, 867700000, 867900000}},
+		{Type: CFListFrequencies, Frequencies: [5]uint32{867100000}},
+		{Type: CFListChannelMask, ChMask: [5]uint16{0xFF00, 0, 0, 0, 0x0002}},
+	}
+	for _, list := range lists {
+		buffer := make([]byte, cfListLength)
+		pos := 0
+		if err := list.encode(buffer, &pos); err != nil {
+			t.Fatal("Could not encode CFList: ", err)
+		}
+		if pos != cfListLength {
+			t.Fatalf("Position not updated (pos = %d)", pos)
+		}
+		if buffer[cfListLength-1] != byte(list.Type) {
+			t.Fatalf("CFList type isn't the last byte: %v", buffer)
+		}
+		decoded := CFList{}
+		dpos := 0
+		if err := decoded.decode(buffer, &dpos); err != nil {
+			t.Fatal("Could not decode CFList: ", err)
+		}
+		if decoded != list {
+			t.Fatalf("CFList doesn't match. Expected %v but got %v", list, decoded)
+		}
+	}
+
+	mask := CFList{Type: CFListChannelMask, ChMask: [5]uint16{0xFF00, 0, 0, 0, 0x0002}}
+	buffer := make([]byte, cfListLength)
+	pos := 0
+	mask.encode(buffer, &pos)
+	if buffer[0] != 0x00 || buffer[1] != 0xFF || buffer[8] != 0x02 || buffer[9] != 0x00 {
+		t.Fatalf("Channel mask isn't little endian: %v", buffer)
+	}
+}
+
+func TestCFListBuffers(t *testing.T) {
+	list := CFList{}
+	pos := 0
+	if err := list.encode(nil, &pos); err == nil {
+		t.Fatal("Expected error with nil buffer")
+	}
+	if err := list.encode(make([]byte, cfListLength), nil); err == nil {
+		t.Fatal("Expected error with nil pos")
+	}
+	if err := list.encode(make([]byte, cfListLength-1), &pos); err == nil {
+		t.Fatal("Expected error with short buffer")
+	}
+	if err := list.decode(make([]byte, cfListLength-1), &pos); err == nil {
+		t.Fatal("Expected error with short buffer")
+	}
+	if !list.Empty() {
+		t.Fatal("Expected empty list")
+	}
+	list.ChMask[0] = 1
+	if list.Empty() {
+		t.Fatal("Did not expect empty list")
+	}
+}
@@ -0,0 +1,155 @@
+package protocol
+
+//
+//Copyright 2018 Telenor Digital AS
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+//
+import (
+	"crypto/aes"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+const (
+	// BeaconPeriod is the time between class B beacons [13.2]
+	BeaconPeriod = 128 * time.Second
+	// BeaconReserved is the time reserved for the beacon at the start of each
+	// beacon period [12.1]
+	BeaconReserved = 2120 * time.Millisecond
+	// PingSlotLength is the length of a single ping slot [12.1]
+	PingSlotLength = 30 * time.Millisecond
+	// MaxPingPeriodicity is the highest periodicity a device can request.
+	// Periodicity 7 gives one ping slot every beacon period. [14.1]
+	MaxPingPeriodicity = 7
+
+	// gpsLeapSeconds is the number of leap seconds since the GPS epoch
+	gpsLeapSeconds = 18 * time.Second
+)
+
+// gpsEpoch is the start of GPS time
+var gpsEpoch = time.Date(1980, time.January, 6, 0, 0, 0, 0, time.UTC)
+
+// TimeToGPS converts a time stamp to GPS time, ie the time since the GPS epoch
+func TimeToGPS(t time.Time) time.Duration {
+	return t.Sub(gpsEpoch) + gpsLeapSeconds
+}
+
+// GPSToTime converts a GPS time into a time stamp
+func GPSToTime(gps time.Duration) time.Time {
+	return gpsEpoch.Add(gps - gpsLeapSeconds)
+}
+
+// BeaconTime returns the (GPS) start time of the beacon period the GPS time
+// is in.
+func BeaconTime(gps time.Duration) time.Duration {
+	return gps - gps%BeaconPeriod
+}
+
+// PingPeriod returns the number of ping slots between each ping slot the
+// device opens with the given periodicity [12.1]
+func PingPeriod(periodicity uint8) int {
+	return 1 << (5 + periodicity)
+}
+
+// PingOffset calculates the randomized offset (in slots) for the device's
+// first ping slot in the beacon period [12.2]
+func PingOffset(beaconTime time.Duration, devAddr DevAddr, periodicity uint8) (int, error) {
+	block, err := aes.NewCipher(make([]byte, 16))
+	if err != nil {
+		return 0, fmt.Errorf("unable to create cipher: %v", err)
+	}
+	buf := make([]byte, 16)
+	binary.LittleEndian.PutUint32(buf[0:], uint32(beaconTime/time.Second))
+	binary.LittleEndian.PutUint32(buf[4:], devAddr.ToUint32())
+	rand := make([]byte, 16)
+	block.Encrypt(rand, buf)
+	return (int(rand[0]) + int(rand[1])*256) % PingPeriod(periodicity), nil
+}
+
+// NextPingSlot returns the (GPS) start time of the device's first ping slot
+// after the specified GPS time.
+func NextPingSlot(after time.Duration, devAddr DevAddr, periodicity uint8) (time.Duration, error) {
+	if periodicity > MaxPingPeriodicity {
+		return 0, fmt.Errorf("invalid ping periodicity: %d", periodicity)
+	}
+	period := time.Duration(PingPeriod(periodicity)) * PingSlotLength
+	for beaconTime := BeaconTime(after); ; beaconTime += BeaconPeriod {
+		offset, err := PingOffset(beaconTime, devAddr, periodicity)
+		if err != nil {
+			return 0, err
+		}
+		slot := beaconTime + BeaconReserved + time.Duration(offset)*PingSlotLength
+		for ; slot < beaconTime+BeaconPeriod; slot += period {
+			if slot > after {
+				return slot, nil
+			}
+		}
+	}
+}
+
+// Beacon is the class B beacon frame broadcasted by the gateways [13.2]
+type Beacon struct {
+	Time      time.Duration // GPS time for the start of the beacon period
+	InfoDesc  uint8         // Gateway specific info descriptor. 0 = GPS coordinate of the gateway's antenna
+	Latitude  float32       // Latitude of the gateway
+	Longitude float32       // Longitude of the gateway
+}
+
+// Encode encodes the beacon frame. The size of the RFU fields are different
+// for each band.
+func (b *Beacon) Encode(rfu1Size, rfu2Size int) []byte {
+	ret := make([]byte, 0, rfu1Size+rfu2Size+15)
+
+	ret = append(ret, make([]byte, rfu1Size)...)
+	ret = binary.LittleEndian.AppendUint32(ret, uint32(b.Time/time.Second))
+	ret = binary.LittleEndian.AppendUint16(ret, beaconCRC(ret))
+
+	gwSpecific := []byte{b.InfoDesc}
+	gwSpecific = appendUint24(gwSpecific, int32(float64(b.Latitude)/90.0*float64(1<<23)))
+	gwSpecific = appendUint24(gwSpecific, int32(float64(b.Longitude)/180.0*float64(1<<23)))
+	gwSpecific = append(gwSpecific, make([]byte, rfu2Size)...)
+	ret = append(ret, gwSpecific...)
+	ret = binary.LittleEndian.AppendUint16(ret, beaconCRC(gwSpecific))
+	return ret
+}
+
+// appendUint24 appends a 24 bit little endian value to the buffer. Values
+// outside of the 24 bit range are clamped.
+func appendUint24(buf []byte, v int32) []byte {
+	if v > 0x7FFFFF {
+		v = 0x7FFFFF
+	}
+	if v < -0x800000 {
+		v = -0x800000
+	}
+	return append(buf, byte(v), byte(v>>8), byte(v>>16))
+}
+
+// beaconCRC is the CRC-16 (CCITT polynomial, initial value 0) used by the
+// beacon frame.
+func beaconCRC(data []byte) uint16 {
+	crc := uint16(0)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
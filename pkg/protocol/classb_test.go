@@ -0,0 +1,74 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestGPSTime(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	if !GPSToTime(TimeToGPS(now)).Equal(now) {
+		t.Fatal("GPS conversion doesn't round trip")
+	}
+	if TimeToGPS(gpsEpoch) != gpsLeapSeconds {
+		t.Fatalf("GPS epoch should be %v but is %v", gpsLeapSeconds, TimeToGPS(gpsEpoch))
+	}
+	if BeaconTime(300*time.Second) != 256*time.Second {
+		t.Fatalf("Expected beacon time to be 256 s but it is %v", BeaconTime(300*time.Second))
+	}
+}
+
+func TestPingSlots(t *testing.T) {
+	devAddr := DevAddrFromUint32(0x01020304)
+	now := TimeToGPS(time.Now())
+
+	for periodicity := uint8(0); periodicity <= MaxPingPeriodicity; periodicity++ {
+		slot, err := NextPingSlot(now, devAddr, periodicity)
+		if err != nil {
+			t.Fatalf("Got error for periodicity %d: %v", periodicity, err)
+		}
+		if slot <= now {
+			t.Fatalf("Slot (%v) should be after %v", slot, now)
+		}
+		offset, _ := PingOffset(BeaconTime(slot), devAddr, periodicity)
+		if offset >= PingPeriod(periodicity) {
+			t.Fatalf("Offset %d is outside the ping period", offset)
+		}
+		pos := slot - BeaconTime(slot) - BeaconReserved - time.Duration(offset)*PingSlotLength
+		if pos%(time.Duration(PingPeriod(periodicity))*PingSlotLength) != 0 {
+			t.Fatalf("Slot %v isn't aligned with the ping period (periodicity=%d)", slot, periodicity)
+		}
+		next, err := NextPingSlot(slot, devAddr, periodicity)
+		if err != nil || next <= slot {
+			t.Fatalf("Next slot (%v) should be after %v", next, slot)
+		}
+	}
+	if _, err := NextPingSlot(now, devAddr, MaxPingPeriodicity+1); err == nil {
+		t.Fatal("Expected error with invalid periodicity")
+	}
+}
+
+func TestBeaconEncoding(t *testing.T) {
+	if crc := beaconCRC([]byte("123456789")); crc != 0x31C3 {
+		t.Fatalf("CRC is %04x, expected 31c3", crc)
+	}
+
+	b := Beacon{Time: 1280 * time.Second, Latitude: 63.43, Longitude: 10.39}
+	buf := b.Encode(2, 0)
+	if len(buf) != 17 {
+		t.Fatalf("Expected 17 bytes, got %d", len(buf))
+	}
+	if binary.LittleEndian.Uint32(buf[2:]) != 1280 {
+		t.Fatalf("Time field is incorrect: %v", buf)
+	}
+	if binary.LittleEndian.Uint16(buf[6:]) != beaconCRC(buf[0:6]) {
+		t.Fatal("First CRC is incorrect")
+	}
+	if binary.LittleEndian.Uint16(buf[15:]) != beaconCRC(buf[8:15]) {
+		t.Fatal("Second CRC is incorrect")
+	}
+	if len(b.Encode(5, 3)) != 23 {
+		t.Fatal("Expected 23 bytes with 5 and 3 bytes RFU")
+	}
+}
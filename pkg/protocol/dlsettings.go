@@ -5,6 +5,7 @@ package protocol
 type DLSettings struct {
 	RX1DRoffset byte
 	RX2DataRate byte
+	OptNeg      bool // Set when the network server implements LoRaWAN 1.1
 }
 
 // Encode DLSettings type into buffer.
@@ -16,6 +17,9 @@ func (d *DLSettings) encode(buffer []byte, pos *int) error {
 		return ErrBufferTruncated
 	}
 	buffer[*pos] = ((d.RX1DRoffset & 0x07) << 4) | (d.RX2DataRate & 0x0F)
+	if d.OptNeg {
+		buffer[*pos] |= 0x80
+	}
 	*pos++
 	return nil
 }
@@ -27,6 +31,7 @@ func (d *DLSettings) decode(buffer []byte, pos *int) error {
 	if len(buffer) <= *pos {
 		return ErrBufferTruncated
 	}
+	d.OptNeg = (buffer[*pos] & 0x80) != 0
 	d.RX1DRoffset = (buffer[*pos] & 0x70) >> 4
 	d.RX2DataRate = buffer[*pos] & 0x0F
 	*pos++
@@ -68,3 +68,23 @@ func TestDLSettingsBufferRange(t *testing.T) {
 	basicDecoderTests(t, &DLSettings{})
 	basicEncoderTests(t, &DLSettings{})
 }
+
+func TestDLSettingsOptNeg(t *testing.T) {
+	d1 := DLSettings{OptNeg: true, RX1DRoffset: 0x1, RX2DataRate: 0x3}
+	buffer := make([]byte, 1)
+	pos := 0
+	if err := d1.encode(buffer, &pos); err != nil {
+		t.Fatalf("Got error encoding DLSettings: %v", err)
+	}
+	if buffer[0] != 0x93 {
+		t.Fatalf("Expected 10010011 (0x93) from encoding but got %02x", buffer[0])
+	}
+	d2 := DLSettings{}
+	pos = 0
+	if err := d2.decode(buffer, &pos); err != nil {
+		t.Fatalf("Got error decoding DLSettings: %v", err)
+	}
+	if d1 != d2 {
+		t.Fatalf("encoded and decoded are different: %+v != %+v", d1, d2)
+	}
+}
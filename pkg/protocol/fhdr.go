@@ -23,7 +23,7 @@ import (
 type FHDR struct {
 	DevAddr DevAddr       // [6.1.1]
 	FCtrl   FCtrl         // [4.3.1]
-	FCnt    uint16        // [4.3.1.5]
+	FCnt    uint32        // [4.3.1.5] Only the lower 16 bits are sent over the air
 	FOpts   MACCommandSet // MAC Commands in the FOpts structure
 }
 
@@ -40,19 +40,18 @@ func (f *FHDR) decode(octets []byte, pos *int) error {
 	if len(octets) < *pos+2 {
 		return ErrBufferTruncated
 	}
-	f.FCnt = binary.LittleEndian.Uint16(octets[*pos : *pos+2])
+	f.FCnt = uint32(binary.LittleEndian.Uint16(octets[*pos : *pos+2]))
 	*pos += 2
 
 	if f.FCtrl.FOptsLen > 0 {
+		start := *pos
 		f.FOpts = NewMACCommandSet(f.FOpts.Message(), int(f.FCtrl.FOptsLen))
-		if err := f.FOpts.decode(octets, pos); err != nil {
-			if err == errUnknownMAC {
-				// Found an unknown MAC command. Skip forward the number of missing bytes
-				*pos += (int(f.FCtrl.FOptsLen) - f.FOpts.EncodedLength())
-				return nil
-			}
+		if err := f.FOpts.decode(octets, pos); err != nil && err != errUnknownMAC {
 			return err
 		}
+		// Skip to the end of the field. The field might contain unknown MAC
+		// commands or it might be encrypted (LoRaWAN 1.1)
+		*pos = start + int(f.FCtrl.FOptsLen)
 	}
 	return nil
 }
@@ -74,7 +73,7 @@ func (f *FHDR) encode(buffer []byte, count *int) error {
 	if err := f.FCtrl.encode(buffer, count); err != nil {
 		return err
 	}
-	binary.LittleEndian.PutUint16(buffer[*count:*count+2], f.FCnt)
+	binary.LittleEndian.PutUint16(buffer[*count:*count+2], uint16(f.FCnt))
 	*count += 2
 
 	if err := f.FOpts.encode(buffer, count); err != nil {
@@ -62,6 +62,9 @@ func (j *JoinAcceptPayload) encode(buffer []byte, pos *int) error {
 	}
 	buffer[*pos] = j.RxDelay
 	*pos++
+	if !j.CFList.Empty() {
+		return j.CFList.encode(buffer, pos)
+	}
 	return nil
 }
 
@@ -84,5 +87,11 @@ func (j *JoinAcceptPayload) decode(buffer []byte, pos *int) error {
 	}
 	j.RxDelay = buffer[*pos]
 	*pos++
+	// The CFList is optional. It is present if there's room for it and the
+	// MIC in the buffer.
+	j.CFList = CFList{}
+	if len(buffer) >= *pos+cfListLength+4 {
+		return j.CFList.decode(buffer, pos)
+	}
 	return nil
 }
@@ -50,6 +50,14 @@ const (
 	RXTimingSetupReq CID = 0x08
 	// RXTimingSetupAns is sent by the end-device to the network (no payload)
 	RXTimingSetupAns CID = 0x08
+	// TxParamSetupReq is sent by the network to the end-device.
+	TxParamSetupReq CID = 0x09
+	// TxParamSetupAns is sent by the end-device to the network (no payload).
+	TxParamSetupAns CID = 0x09
+	// DlChannelReq is sent by the network to the end-device.
+	DlChannelReq CID = 0x0A
+	// DlChannelAns is sent by the end-device to the network.
+	DlChannelAns CID = 0x0A
 )
 
 // MAC commands for Class B devices
@@ -72,6 +80,14 @@ const (
 	BeaconFreqAns CID = 0x13
 )
 
+// MAC commands for LoRaWAN 1.1 devices
+const (
+	// RekeyInd is sent by the end-device to the network.
+	RekeyInd CID = 0x0B
+	// RekeyConf is sent by the network to the end-device.
+	RekeyConf CID = 0x0B
+)
+
 // MACCommand represents MAC commands
 type MACCommand interface {
 	// ID returns the Command ID (aka CID) for MAC command.
@@ -151,6 +167,10 @@ func NewUplinkMACCommand(id CID) MACCommand {
 		return &MACNewChannelAns{macBase{NewChannelAns, true}, false, false}
 	case RXTimingSetupAns:
 		return &MACRXTimingSetupAns{macBase{RXTimingSetupAns, true}}
+	case TxParamSetupAns:
+		return &MACTxParamSetupAns{macBase{TxParamSetupAns, true}}
+	case DlChannelAns:
+		return &MACDlChannelAns{macBase{DlChannelAns, true}, false, false}
 	case PingSlotInfoReq:
 		return &MACPingSlotInfoReq{macBase{PingSlotInfoReq, true}, 0, 0}
 	case PingSlotFreqAns:
@@ -159,6 +179,8 @@ func NewUplinkMACCommand(id CID) MACCommand {
 		return &MACBeaconTimingReq{macBase{BeaconTimingReq, true}}
 	case BeaconFreqAns:
 		return &MACBeaconFreqAns{macBase{BeaconFreqAns, true}}
+	case RekeyInd:
+		return &MACRekeyInd{macBase{RekeyInd, true}, 0}
 	}
 	return nil
 }
@@ -180,6 +202,10 @@ func NewDownlinkMACCommand(id CID) MACCommand {
 		return &MACNewChannelReq{macBase{NewChannelReq, false}, 0, 0, 0, 0}
 	case RXTimingSetupReq:
 		return &MACRXTimingSetupReq{macBase{RXTimingSetupReq, false}, 0}
+	case TxParamSetupReq:
+		return &MACTxParamSetupReq{macBase{TxParamSetupReq, false}, false, false, 0}
+	case DlChannelReq:
+		return &MACDlChannelReq{macBase{DlChannelReq, false}, 0, 0}
 	case PingSlotInfoAns:
 		return &MACPingSlotInfoAns{macBase{PingSlotInfoAns, false}}
 	case PingSlotChannelReq:
@@ -188,6 +214,8 @@ func NewDownlinkMACCommand(id CID) MACCommand {
 		return &MACBeaconTimingAns{macBase{BeaconTimingAns, false}, 0, 0}
 	case BeaconFreqReq:
 		return &MACBeaconFreqReq{macBase{BeaconFreqReq, false}, 0}
+	case RekeyConf:
+		return &MACRekeyConf{macBase{RekeyConf, false}, 0}
 	}
 	return nil
 }
@@ -0,0 +1,61 @@
+package protocol
+
+// MACRekeyInd is sent by LoRaWAN 1.1 end-devices after a join to confirm the
+// security context. The device keeps sending the command until it receives a
+// RekeyConf from the network server.
+type MACRekeyInd struct {
+	macBase
+	Minor uint8 // Minor version of LoRaWAN implemented by the device (1 = LoRaWAN x.1)
+}
+
+// Length returns the length of the MAC command when encoded into a byte buffer
+func (m *MACRekeyInd) Length() int {
+	return 2
+}
+
+func (m *MACRekeyInd) encode(buffer []byte, pos *int) error {
+	if err := encodeID(m, buffer, pos); err != nil {
+		return err
+	}
+	buffer[*pos] = m.Minor & 0x0F
+	*pos++
+	return nil
+}
+
+func (m *MACRekeyInd) decode(buffer []byte, pos *int) error {
+	if err := decodeID(m, buffer, pos); err != nil {
+		return err
+	}
+	m.Minor = buffer[*pos] & 0x0F
+	*pos++
+	return nil
+}
+
+// MACRekeyConf is sent by the network server as a response to RekeyInd
+type MACRekeyConf struct {
+	macBase
+	Minor uint8 // Minor version of LoRaWAN used by the network server
+}
+
+// Length returns the length of the MAC command when encoded into a byte buffer
+func (m *MACRekeyConf) Length() int {
+	return 2
+}
+
+func (m *MACRekeyConf) encode(buffer []byte, pos *int) error {
+	if err := encodeID(m, buffer, pos); err != nil {
+		return err
+	}
+	buffer[*pos] = m.Minor & 0x0F
+	*pos++
+	return nil
+}
+
+func (m *MACRekeyConf) decode(buffer []byte, pos *int) error {
+	if err := decodeID(m, buffer, pos); err != nil {
+		return err
+	}
+	m.Minor = buffer[*pos] & 0x0F
+	*pos++
+	return nil
+}
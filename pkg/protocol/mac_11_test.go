@@ -0,0 +1,49 @@
+package protocol
+
+import "testing"
+
+func TestRekeyInd(t *testing.T) {
+	m := MACRekeyInd{macBase{RekeyInd, true}, 1}
+	macCommandStandardTests(&m, RekeyInd, t)
+
+	buffer := make([]byte, 3)
+	pos := 0
+	if err := m.encode(buffer, &pos); err != nil {
+		t.Error("Could not encode RekeyInd: ", err)
+	}
+
+	p := MACRekeyInd{macBase{RekeyInd, true}, 0}
+	dpos := 0
+	if err := p.decode(buffer, &dpos); err != nil {
+		t.Error("Could not decode RekeyInd: ", err)
+	}
+	if p != m {
+		t.Errorf("RekeyInd decoded incorrectly: %v != %v", p, m)
+	}
+	if dpos != pos {
+		t.Errorf("RekeyInd decodes different number of bytes (%d != %d)", dpos, pos)
+	}
+}
+
+func TestRekeyConf(t *testing.T) {
+	m := MACRekeyConf{macBase{RekeyConf, false}, 1}
+	macCommandStandardTests(&m, RekeyConf, t)
+
+	buffer := make([]byte, 3)
+	pos := 0
+	if err := m.encode(buffer, &pos); err != nil {
+		t.Error("Could not encode RekeyConf: ", err)
+	}
+
+	p := MACRekeyConf{macBase{RekeyConf, false}, 0}
+	dpos := 0
+	if err := p.decode(buffer, &dpos); err != nil {
+		t.Error("Could not decode RekeyConf: ", err)
+	}
+	if p != m {
+		t.Errorf("RekeyConf decoded incorrectly: %v != %v", p, m)
+	}
+	if dpos != pos {
+		t.Errorf("RekeyConf decodes different number of bytes (%d != %d)", dpos, pos)
+	}
+}
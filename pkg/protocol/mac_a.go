@@ -97,6 +97,80 @@ func (m *MACLinkADRReq) decode(buffer []byte, pos *int) error {
 	return nil
 }
 
+// LinkADRRedundancy returns the Redundancy field for a LinkADRReq. The field
+// holds the channel mask control (bits 6:4) and the number of transmissions
+// for each uplink (bits 3:0) [5.2].
+func LinkADRRedundancy(chMaskCntl uint8, nbTrans uint8) uint8 {
+	return (chMaskCntl&0x07)<<4 | (nbTrans & 0x0F)
+}
+
+// ChMaskCntl returns the channel mask control field. The field controls how
+// the channel mask is interpreted by the device. The meaning is region
+// specific.
+func (m *MACLinkADRReq) ChMaskCntl() uint8 {
+	return (m.Redundancy >> 4) & 0x07
+}
+
+// NbTrans returns the number of transmissions for each uplink
+func (m *MACLinkADRReq) NbTrans() uint8 {
+	return m.Redundancy & 0x0F
+}
+
+// MACLinkADRReqBlock is a contiguous block of LinkADRReq commands. The
+// end-device processes the block as a single request and uses the data rate,
+// TX power and NbTrans from the last command. The block is used to set the
+// channel mask for bands with more than 16 channels, ie US915 and AU915.
+type MACLinkADRReqBlock struct {
+	macBase
+	Requests []*MACLinkADRReq
+}
+
+// NewLinkADRReqBlock creates a new block of LinkADRReq commands
+func NewLinkADRReqBlock(requests ...*MACLinkADRReq) *MACLinkADRReqBlock {
+	return &MACLinkADRReqBlock{macBase{LinkADRReq, false}, requests}
+}
+
+// Length returns the length of the MAC commands when encoded into a byte buffer
+func (m *MACLinkADRReqBlock) Length() int {
+	return 5 * len(m.Requests)
+}
+
+func (m *MACLinkADRReqBlock) encode(buffer []byte, pos *int) error {
+	if pos == nil {
+		return ErrNilError
+	}
+	if !isValidBuffer(buffer, pos, m) {
+		return ErrBufferTruncated
+	}
+	for _, req := range m.Requests {
+		if err := req.encode(buffer, pos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decode decodes LinkADRReq commands as long as the next command in the
+// buffer is a LinkADRReq
+func (m *MACLinkADRReqBlock) decode(buffer []byte, pos *int) error {
+	if pos == nil {
+		return ErrNilError
+	}
+	var requests []*MACLinkADRReq
+	for *pos < len(buffer) && buffer[*pos] == byte(LinkADRReq) {
+		req := &MACLinkADRReq{macBase: macBase{LinkADRReq, false}}
+		if err := req.decode(buffer, pos); err != nil {
+			return err
+		}
+		requests = append(requests, req)
+	}
+	if len(requests) == 0 {
+		return ErrBufferTruncated
+	}
+	m.Requests = requests
+	return nil
+}
+
 // MACLinkADRAns is sent from the end-device to the network server as a response to the LinkAdrReq command
 type MACLinkADRAns struct {
 	macBase
@@ -465,3 +539,158 @@ func (m *MACRXTimingSetupAns) encode(buffer []byte, pos *int) error {
 func (m *MACRXTimingSetupAns) decode(buffer []byte, pos *int) error {
 	return decodeID(m, buffer, pos)
 }
+
+// maxEIRPValues is the max EIRP (in dBm) for each of the MaxEIRP values in
+// TxParamSetupReq [5.8]
+var maxEIRPValues = [16]uint8{8, 10, 12, 13, 14, 16, 18, 20, 21, 24, 26, 27, 29, 30, 33, 36}
+
+// MaxEIRPIndex returns the TxParamSetupReq MaxEIRP value for a max EIRP (in
+// dBm). The value is rounded down to the nearest max EIRP in the table.
+func MaxEIRPIndex(eirp uint8) uint8 {
+	ret := uint8(0)
+	for i, v := range maxEIRPValues {
+		if v <= eirp {
+			ret = uint8(i)
+		}
+	}
+	return ret
+}
+
+// MACTxParamSetupReq is sent by the network server to set the dwell time
+// limits and the max EIRP for the end-device. The MaxEIRP field is an index
+// into the table of max EIRP values [5.8]
+type MACTxParamSetupReq struct {
+	macBase
+	DownlinkDwellTime bool  // The downlinks are limited to 400 ms dwell time
+	UplinkDwellTime   bool  // The uplinks are limited to 400 ms dwell time
+	MaxEIRP           uint8 // Max EIRP index. Use MaxEIRPIndex to convert from dBm
+}
+
+// Length returns the length of the MAC command when encoded into a byte buffer
+func (m *MACTxParamSetupReq) Length() int {
+	return 2
+}
+
+func (m *MACTxParamSetupReq) encode(buffer []byte, pos *int) error {
+	if err := encodeID(m, buffer, pos); err != nil {
+		return err
+	}
+	val := m.MaxEIRP & 0x0F
+	if m.DownlinkDwellTime {
+		val |= (1 << 5)
+	}
+	if m.UplinkDwellTime {
+		val |= (1 << 4)
+	}
+	buffer[*pos] = val
+	*pos++
+	return nil
+}
+
+func (m *MACTxParamSetupReq) decode(buffer []byte, pos *int) error {
+	if err := decodeID(m, buffer, pos); err != nil {
+		return err
+	}
+	m.DownlinkDwellTime = buffer[*pos]&0x20 != 0
+	m.UplinkDwellTime = buffer[*pos]&0x10 != 0
+	m.MaxEIRP = buffer[*pos] & 0x0F
+	*pos++
+	return nil
+}
+
+// MACTxParamSetupAns is sent by the end-device to acknowledge a
+// TxParamSetupReq message
+type MACTxParamSetupAns struct {
+	macBase
+	// no payload
+}
+
+// Length returns the length of the MAC command when encoded into a byte buffer
+func (m *MACTxParamSetupAns) Length() int {
+	return 1
+}
+
+func (m *MACTxParamSetupAns) encode(buffer []byte, pos *int) error {
+	return encodeID(m, buffer, pos)
+}
+
+func (m *MACTxParamSetupAns) decode(buffer []byte, pos *int) error {
+	return decodeID(m, buffer, pos)
+}
+
+// MACDlChannelReq is sent by the network server to set the RX1 downlink
+// frequency for a channel. The frequency is in units of 100 Hz, like the
+// NewChannelReq frequency [5.8]
+type MACDlChannelReq struct {
+	macBase
+	ChIndex uint8
+	Freq    uint32
+}
+
+// Length returns the length of the MAC command when encoded into a byte buffer
+func (m *MACDlChannelReq) Length() int {
+	return 5
+}
+
+func (m *MACDlChannelReq) encode(buffer []byte, pos *int) error {
+	if err := encodeID(m, buffer, pos); err != nil {
+		return err
+	}
+	buffer[*pos] = m.ChIndex
+	*pos++
+	buffer[*pos+0] = byte(m.Freq)
+	buffer[*pos+1] = byte(m.Freq >> 8)
+	buffer[*pos+2] = byte(m.Freq >> 16)
+	*pos += 3
+	return nil
+}
+
+func (m *MACDlChannelReq) decode(buffer []byte, pos *int) error {
+	if err := decodeID(m, buffer, pos); err != nil {
+		return err
+	}
+	m.ChIndex = buffer[*pos]
+	*pos++
+	m.Freq = uint32(buffer[*pos+0]) | uint32(buffer[*pos+1])<<8 | uint32(buffer[*pos+2])<<16
+	*pos += 3
+	return nil
+}
+
+// MACDlChannelAns is sent by the end-device to the network server as a
+// response to the DlChannelReq command
+type MACDlChannelAns struct {
+	macBase
+	UplinkFrequencyExists bool
+	ChannelFrequencyOK    bool
+}
+
+// Length returns the length of the MAC command when encoded into a byte buffer
+func (m *MACDlChannelAns) Length() int {
+	return 2
+}
+
+func (m *MACDlChannelAns) encode(buffer []byte, pos *int) error {
+	if err := encodeID(m, buffer, pos); err != nil {
+		return err
+	}
+	val := byte(0)
+	if m.UplinkFrequencyExists {
+		val |= (1 << 1)
+	}
+	if m.ChannelFrequencyOK {
+		val |= (1 << 0)
+	}
+	buffer[*pos] = val
+	*pos++
+	return nil
+}
+
+func (m *MACDlChannelAns) decode(buffer []byte, pos *int) error {
+	if err := decodeID(m, buffer, pos); err != nil {
+		return err
+	}
+	m.UplinkFrequencyExists = buffer[*pos]&0x02 != 0
+	m.ChannelFrequencyOK = buffer[*pos]&0x01 != 0
+	*pos++
+	return nil
+}
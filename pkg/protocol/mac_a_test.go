@@ -105,6 +105,45 @@ func TestLinkADRReq(t *testing.T) {
 	}
 }
 
+func TestLinkADRReqRedundancy(t *testing.T) {
+	m := MACLinkADRReq{macBase{LinkADRReq, true}, 0, 0, 0xFF00, LinkADRRedundancy(7, 1)}
+	if m.Redundancy != 0x71 || m.ChMaskCntl() != 7 || m.NbTrans() != 1 {
+		t.Fatalf("Incorrect redundancy field: %02x", m.Redundancy)
+	}
+}
+
+func TestLinkADRReqBlock(t *testing.T) {
+	m := NewLinkADRReqBlock(
+		&MACLinkADRReq{macBase{LinkADRReq, false}, 0, 0, 0x0000, LinkADRRedundancy(7, 0)},
+		&MACLinkADRReq{macBase{LinkADRReq, false}, 3, 1, 0xFF00, LinkADRRedundancy(0, 1)},
+	)
+	macCommandStandardTests(m, LinkADRReq, t)
+	if m.Length() != 10 {
+		t.Fatalf("Expected length 10 but got %d", m.Length())
+	}
+	buffer := make([]byte, 11)
+	pos := 0
+	if err := m.encode(buffer, &pos); err != nil {
+		t.Fatal("Could not encode LinkADRReq block: ", err)
+	}
+	if pos != 10 || buffer[0] != byte(LinkADRReq) || buffer[5] != byte(LinkADRReq) {
+		t.Fatalf("Unexpected encoding (pos = %d): %v", pos, buffer)
+	}
+	p := MACLinkADRReqBlock{}
+	dpos := 0
+	if err := p.decode(buffer, &dpos); err != nil {
+		t.Fatal("Could not decode LinkADRReq block: ", err)
+	}
+	if dpos != pos || len(p.Requests) != 2 {
+		t.Fatalf("Decoded %d commands and %d bytes", len(p.Requests), dpos)
+	}
+	for i := range p.Requests {
+		if p.Requests[i].ChMask != m.Requests[i].ChMask || p.Requests[i].Redundancy != m.Requests[i].Redundancy {
+			t.Fatalf("Command %d doesn't match: %v != %v", i, p.Requests[i], m.Requests[i])
+		}
+	}
+}
+
 func TestLinkADRAns(t *testing.T) {
 	m := MACLinkADRAns{macBase{LinkADRAns, true}, true, true, true}
 	macCommandStandardTests(&m, LinkADRAns, t)
@@ -388,3 +427,102 @@ func TestRXTimingSetupAns(t *testing.T) {
 		t.Errorf("RXTimingSetupAns decodes different number of bytes (%d != %d)", dpos, pos)
 	}
 }
+
+func TestTxParamSetupReq(t *testing.T) {
+	m := MACTxParamSetupReq{macBase{TxParamSetupReq, false}, true, false, 5}
+	macCommandStandardTests(&m, TxParamSetupReq, t)
+
+	buffer := make([]byte, 3)
+	pos := 0
+	if err := m.encode(buffer, &pos); err != nil {
+		t.Error("Could not encode TxParamSetupReq: ", err)
+	}
+	if buffer[1] != 0x25 {
+		t.Errorf("Unexpected encoding of TxParamSetupReq: %02x", buffer[1])
+	}
+
+	p := MACTxParamSetupReq{macBase{TxParamSetupReq, false}, false, false, 0}
+	dpos := 0
+	if err := p.decode(buffer, &dpos); err != nil {
+		t.Error("Could not decode TxParamSetupReq: ", err)
+	}
+	if m != p {
+		t.Errorf("Encoded and decoded TxParamSetupReq are different: %v != %v", p, m)
+	}
+	if dpos != pos {
+		t.Errorf("TxParamSetupReq decodes different number of bytes (%d != %d)", dpos, pos)
+	}
+}
+
+func TestTxParamSetupAns(t *testing.T) {
+	m := MACTxParamSetupAns{macBase{TxParamSetupAns, true}}
+	macCommandStandardTests(&m, TxParamSetupAns, t)
+
+	buffer := make([]byte, 2)
+	pos := 0
+	if err := m.encode(buffer, &pos); err != nil {
+		t.Error("Could not encode TxParamSetupAns: ", err)
+	}
+	p := MACTxParamSetupAns{macBase{TxParamSetupAns, true}}
+	dpos := 0
+	if err := p.decode(buffer, &dpos); err != nil {
+		t.Error("Could not decode TxParamSetupAns: ", err)
+	}
+	if dpos != pos {
+		t.Errorf("TxParamSetupAns decodes different number of bytes (%d != %d)", dpos, pos)
+	}
+}
+
+func TestMaxEIRPIndex(t *testing.T) {
+	for eirp, expected := range map[uint8]uint8{0: 0, 8: 0, 9: 0, 16: 5, 17: 5, 36: 15, 40: 15} {
+		if index := MaxEIRPIndex(eirp); index != expected {
+			t.Errorf("Expected index %d for %d dBm but got %d", expected, eirp, index)
+		}
+	}
+}
+
+func TestDlChannelReq(t *testing.T) {
+	m := MACDlChannelReq{macBase{DlChannelReq, false}, 0x03, 8691000}
+	macCommandStandardTests(&m, DlChannelReq, t)
+
+	buffer := make([]byte, 6)
+	pos := 0
+	if err := m.encode(buffer, &pos); err != nil {
+		t.Error("Could not encode DlChannelReq: ", err)
+	}
+
+	p := MACDlChannelReq{macBase{DlChannelReq, false}, 0, 0}
+	dpos := 0
+	if err := p.decode(buffer, &dpos); err != nil {
+		t.Error("Could not decode DlChannelReq: ", err)
+	}
+	if m != p {
+		t.Errorf("Encoded and decoded DlChannelReq are different: %v != %v", p, m)
+	}
+	if dpos != pos {
+		t.Errorf("DlChannelReq decodes different number of bytes (%d != %d)", dpos, pos)
+	}
+}
+
+func TestDlChannelAns(t *testing.T) {
+	m := MACDlChannelAns{macBase{DlChannelAns, true}, true, false}
+	macCommandStandardTests(&m, DlChannelAns, t)
+
+	buffer := make([]byte, 3)
+	pos := 0
+	if err := m.encode(buffer, &pos); err != nil {
+		t.Error("Could not encode DlChannelAns: ", err)
+	}
+
+	p := MACDlChannelAns{macBase{DlChannelAns, true}, false, false}
+	dpos := 0
+	if err := p.decode(buffer, &dpos); err != nil {
+		t.Error("Could not decode DlChannelAns: ", err)
+	}
+	if p != m {
+		t.Errorf("Encoded and decoded DlChannelAns are different: %v != %v", p, m)
+	}
+	if dpos != pos {
+		t.Errorf("DlChannelAns decodes different number of bytes (%d != %d)", dpos, pos)
+	}
+}
@@ -15,6 +15,14 @@ func NewMACPayload(message MType) MACPayload {
 	}
 }
 
+// ApplicationDownlink returns true if the payload carries application data,
+// ie it has a FRMPayload on a FPort > 0. Downlinks to LoRaWAN 1.1 devices use
+// the application frame counter (AFCntDown) for these frames and the network
+// frame counter (NFCntDown) for the rest.
+func (m *MACPayload) ApplicationDownlink() bool {
+	return m.FPort > 0 && len(m.FRMPayload) > 0
+}
+
 // BUG(stalehd): The payload size should be determined via the frequency plan, not as a constant.
 const maxPayloadSize int = 255
 
@@ -23,25 +23,53 @@ import (
 
 // CalculateMIC calculates the Message Integrity Code [4.4]
 func (p *PHYPayload) CalculateMIC(nwkSKey AESKey, message []byte) (uint32, error) {
+	fullMessage := append(p.micBlock(0, 0, 0, len(message)), message[0:]...)
+
+	return p.calculateMICFromBuffer(nwkSKey, fullMessage)
+}
+
+// CalculateUplinkMIC11 calculates the MIC for uplink messages from LoRaWAN 1.1
+// devices. The MIC is made from two halves; one calculated with the forwarding
+// network key and one with the serving network key. The confFCnt parameter is
+// the frame counter of the acknowledged downlink (if the ACK bit is set),
+// txDR is the data rate and txCh is the channel index for the uplink.
+func (p *PHYPayload) CalculateUplinkMIC11(fNwkSIntKey AESKey, sNwkSIntKey AESKey, confFCnt uint16, txDR uint8, txCh uint8, message []byte) (uint32, error) {
+	micF, err := p.calculateMICFromBuffer(fNwkSIntKey, append(p.micBlock(0, 0, 0, len(message)), message...))
+	if err != nil {
+		return 0, err
+	}
+	micS, err := p.calculateMICFromBuffer(sNwkSIntKey, append(p.micBlock(confFCnt, txDR, txCh, len(message)), message...))
+	if err != nil {
+		return 0, err
+	}
+	return (micS & 0xFFFF) | (micF << 16), nil
+}
+
+// CalculateDownlinkMIC11 calculates the MIC for downlink messages to LoRaWAN
+// 1.1 devices. The confFCnt parameter is the frame counter of the acknowledged
+// uplink (if the ACK bit is set).
+func (p *PHYPayload) CalculateDownlinkMIC11(sNwkSIntKey AESKey, confFCnt uint16, message []byte) (uint32, error) {
+	return p.calculateMICFromBuffer(sNwkSIntKey, append(p.micBlock(confFCnt, 0, 0, len(message)), message...))
+}
+
+// micBlock returns the block that is prepended to the message when the MIC is
+// calculated. The confFCnt, txDR and txCh fields are only used by LoRaWAN 1.1.
+func (p *PHYPayload) micBlock(confFCnt uint16, txDR uint8, txCh uint8, messageLength int) []byte {
 	b0 := make([]byte, 16)
 	b0[0] = 0x49
-	b0[1] = 0
-	b0[2] = 0
-	b0[3] = 0
-	b0[4] = 0
+	binary.LittleEndian.PutUint16(b0[1:], confFCnt)
+	b0[3] = txDR
+	b0[4] = txCh
 	if p.MHDR.MType.Uplink() {
 		b0[5] = 0
 	} else {
 		b0[5] = 1
 	}
 	binary.LittleEndian.PutUint32(b0[6:], p.MACPayload.FHDR.DevAddr.ToUint32())
-	binary.LittleEndian.PutUint32(b0[10:], uint32(p.MACPayload.FHDR.FCnt))
+	binary.LittleEndian.PutUint32(b0[10:], p.MACPayload.FHDR.FCnt)
 	b0[14] = 0
-	b0[15] = byte(len(message))
-
-	fullMessage := append(b0, message[0:]...)
-
-	return p.calculateMICFromBuffer(nwkSKey, fullMessage)
+	b0[15] = byte(messageLength)
+	return b0
 }
 
 // calculateMICFromBuffer calculates a MIC using the given key and buffer.
@@ -75,3 +103,16 @@ func (p *PHYPayload) CalculateJoinAcceptMIC(appKey AESKey, payload []byte) (uint
 func (p *PHYPayload) CalculateJoinRequestMIC(appKey AESKey, payload []byte) (uint32, error) {
 	return p.calculateMICFromBuffer(appKey, payload)
 }
+
+// CalculateJoinAcceptMIC11 calculates the JoinAccept MIC for LoRaWAN 1.1
+// devices. The MIC includes the JoinEUI and DevNonce from the JoinRequest:
+//
+//	JoinReqType | JoinEUI | DevNonce | MHDR | JoinNonce | NetID | DevAddr | DLSettings | RxDelay | CFList
+func (p *PHYPayload) CalculateJoinAcceptMIC11(jsIntKey AESKey, joinEUI EUI, devNonce uint16, payload []byte) (uint32, error) {
+	const joinRequestType = 0xFF
+	buffer := make([]byte, 11)
+	buffer[0] = joinRequestType
+	binary.LittleEndian.PutUint64(buffer[1:], uint64(joinEUI.ToInt64()))
+	binary.BigEndian.PutUint16(buffer[9:], devNonce)
+	return p.calculateMICFromBuffer(jsIntKey, append(buffer, payload...))
+}
@@ -108,3 +108,53 @@ func TestJoinAcceptMIC(t *testing.T) {
 		t.Fatalf("Unexpected MIC. Got 0x%08x, expected 0x9d9dddd2", mic)
 	}
 }
+
+func TestUplinkMIC11(t *testing.T) {
+	key, _ := AESKeyFromString("3C5E 5C9F 469E EF3E 02CC D4FF 9531 31BA")
+	otherKey, _ := AESKeyFromString("E001 2A22 25B8 585E DCEC 7042 4798 C510")
+	p := createUnencryptedTestMessage()
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal("Got error marshaling message: ", err)
+	}
+	message := buf[0 : len(buf)-4]
+
+	// With the same key for both halves and no ConfFCnt, data rate or channel
+	// both halves are the lower half of the LoRaWAN 1.0 MIC.
+	mic10, _ := p.CalculateMIC(key, message)
+	mic, err := p.CalculateUplinkMIC11(key, key, 0, 0, 0, message)
+	if err != nil {
+		t.Fatal("Got error calculating MIC: ", err)
+	}
+	if mic != (mic10&0xFFFF)|(mic10<<16) {
+		t.Fatalf("Unexpected MIC: 0x%08x (1.0 MIC is 0x%08x)", mic, mic10)
+	}
+
+	// The serving network key is used for the lower half
+	mic, _ = p.CalculateUplinkMIC11(key, otherKey, 0, 0, 0, message)
+	if mic>>16 != mic10&0xFFFF || mic&0xFFFF == mic10&0xFFFF {
+		t.Fatalf("Unexpected MIC: 0x%08x (1.0 MIC is 0x%08x)", mic, mic10)
+	}
+
+	// ConfFCnt, data rate and channel changes the MIC
+	for _, v := range [][]uint8{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
+		changed, _ := p.CalculateUplinkMIC11(key, key, uint16(v[0]), v[1], v[2], message)
+		if changed>>16 != mic10&0xFFFF || changed == mic {
+			t.Fatalf("Expected lower half of MIC to change: 0x%08x", changed)
+		}
+	}
+}
+
+func TestJoinAcceptMIC11(t *testing.T) {
+	payload := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13}
+	key, _ := AESKeyFromString("0102030405060708 0102030405060708")
+	p := NewPHYPayload(JoinAccept)
+
+	mic1, _ := p.CalculateJoinAcceptMIC11(key, EUIFromInt64(1), 1, payload)
+	mic2, _ := p.CalculateJoinAcceptMIC11(key, EUIFromInt64(2), 1, payload)
+	mic3, _ := p.CalculateJoinAcceptMIC11(key, EUIFromInt64(1), 2, payload)
+	mic10, _ := p.CalculateJoinAcceptMIC(key, payload)
+	if mic1 == mic2 || mic1 == mic3 || mic1 == mic10 {
+		t.Fatalf("JoinEUI and DevNonce should change the MIC (%08x, %08x, %08x, %08x)", mic1, mic2, mic3, mic10)
+	}
+}
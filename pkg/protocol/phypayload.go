@@ -89,7 +89,11 @@ func (p *PHYPayload) DecodeJoinAccept(aesKey AESKey, buffer []byte) error {
 	copy(input, buffer[1:])
 	decrypted := make([]byte, paddedLen)
 
-	cipher.Encrypt(decrypted, input)
+	// The message is encrypted with AES ECB so each block is decrypted
+	// separately [6.2.5]
+	for i := 0; i < paddedLen; i += aes.BlockSize {
+		cipher.Encrypt(decrypted[i:], input[i:])
+	}
 
 	pos := 0
 	p.JoinAcceptPayload.AppNonce[0] = decrypted[pos+0]
@@ -108,9 +112,17 @@ func (p *PHYPayload) DecodeJoinAccept(aesKey AESKey, buffer []byte) error {
 		return err
 	}
 	p.JoinAcceptPayload.RxDelay = decrypted[pos]
+	pos++
+	p.JoinAcceptPayload.CFList = CFList{}
+	if len(buffer)-1-4 >= pos+cfListLength {
+		if err := p.JoinAcceptPayload.CFList.decode(decrypted, &pos); err != nil {
+			return err
+		}
+	}
 
 	// The decrypted buffer shouldn't include the MHDR (1 byte) or the MIC (4 byte)
-	p.MIC, err = p.CalculateJoinAcceptMIC(aesKey, append(buffer[0:1], decrypted[0:len(buffer)-5]...))
+	micBuffer := append([]byte{buffer[0]}, decrypted[0:len(buffer)-5]...)
+	p.MIC, err = p.CalculateJoinAcceptMIC(aesKey, micBuffer)
 	// Check if the MIC in the decrypted buffer matches the MIC we found
 	bufferMIC := binary.LittleEndian.Uint32(decrypted[len(buffer)-5:])
 	if bufferMIC != p.MIC {
@@ -152,13 +164,32 @@ func (p *PHYPayload) MarshalBinary() ([]byte, error) {
 // EncodeJoinAccept encodes a complete JoinAccept message, including
 // MIC and encryption. The appKey parameter is the application key.
 func (p *PHYPayload) EncodeJoinAccept(appKey AESKey) ([]byte, error) {
+	return p.encodeJoinAccept(appKey, func(buffer []byte) (uint32, error) {
+		return p.CalculateJoinAcceptMIC(appKey, buffer)
+	})
+}
+
+// EncodeJoinAccept11 encodes a complete JoinAccept message for a LoRaWAN 1.1
+// device. The message is encrypted with the network key. The JoinEUI and
+// DevNonce are the values from the JoinRequest.
+func (p *PHYPayload) EncodeJoinAccept11(nwkKey AESKey, devEUI EUI, joinEUI EUI, devNonce uint16) ([]byte, error) {
+	jsIntKey, err := jsIntKeyFromDevEUI(nwkKey, devEUI)
+	if err != nil {
+		return nil, err
+	}
+	return p.encodeJoinAccept(nwkKey, func(buffer []byte) (uint32, error) {
+		return p.CalculateJoinAcceptMIC11(jsIntKey, joinEUI, devNonce, buffer)
+	})
+}
+
+func (p *PHYPayload) encodeJoinAccept(key AESKey, calculateMIC func(buffer []byte) (uint32, error)) ([]byte, error) {
 	if p.MHDR.MType != JoinAccept {
 		return nil, ErrInvalidMessageType
 	}
 	var err error
 
-	// Maximum size of JoinAccept is 3+3+4+1+1+16=28 bytes [6.2.5]
-	buffer := make([]byte, 30)
+	// Maximum size of JoinAccept is 1+3+3+4+1+1+16+4=33 bytes [6.2.5]
+	buffer := make([]byte, 33)
 	pos := 0
 	if err = p.MHDR.encode(buffer, &pos); err != nil {
 		return nil, err
@@ -167,7 +198,7 @@ func (p *PHYPayload) EncodeJoinAccept(appKey AESKey) ([]byte, error) {
 		return nil, err
 	}
 
-	if p.MIC, err = p.CalculateJoinAcceptMIC(appKey, buffer[0:pos]); err != nil {
+	if p.MIC, err = calculateMIC(buffer[0:pos]); err != nil {
 		return nil, err
 	}
 
@@ -175,13 +206,17 @@ func (p *PHYPayload) EncodeJoinAccept(appKey AESKey) ([]byte, error) {
 	pos += 4
 
 	// Now do the decryption see [6.2.5]
-	cipher, err := aes.NewCipher(appKey.Key[:])
+	cipher, err := aes.NewCipher(key.Key[:])
 	if err != nil {
 		return nil, err
 	}
 	ret := make([]byte, pos)
-	ret[0] = buffer[0]                     // Use MHDR as is
-	cipher.Decrypt(ret[1:], buffer[1:pos]) // and encrypt payload + mic
+	ret[0] = buffer[0] // Use MHDR as is
+	// ...and encrypt payload + mic. The payload is one or two AES blocks
+	// depending on the CFList.
+	for i := 1; i < pos; i += aes.BlockSize {
+		cipher.Decrypt(ret[i:], buffer[i:])
+	}
 
 	return ret, nil
 }
@@ -221,6 +256,81 @@ func (p *PHYPayload) EncodeMessage(nwkSKey AESKey, appSKey AESKey) ([]byte, erro
 	return p.MarshalBinary()
 }
 
+// EncodeMessage11 encrypts and adds MIC for a message to a LoRaWAN 1.1
+// device. MAC commands (in FOpts and on port 0) are encrypted with the network
+// session encryption key. The frame counter is set to aFCntDown for frames
+// with application data and nFCntDown for the rest. The FOpts field is always
+// encrypted with nFCntDown. The confFCnt parameter is the frame counter of the
+// acknowledged uplink.
+func (p *PHYPayload) EncodeMessage11(sNwkSIntKey AESKey, nwkSEncKey AESKey, appSKey AESKey, nFCntDown uint32, aFCntDown uint32, confFCnt uint16) ([]byte, error) {
+	p.MACPayload.FHDR.FCnt = nFCntDown
+	if p.MACPayload.ApplicationDownlink() {
+		p.MACPayload.FHDR.FCnt = aFCntDown
+	}
+	p.Decrypt(nwkSEncKey, appSKey)
+
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) < fOptsOffset+int(p.MACPayload.FHDR.FCtrl.FOptsLen)+4 {
+		return nil, ErrBufferTruncated
+	}
+	p.encryptFOpts(nwkSEncKey, nFCntDown, buf[fOptsOffset:fOptsOffset+int(p.MACPayload.FHDR.FCtrl.FOptsLen)])
+	if p.MIC, err = p.CalculateDownlinkMIC11(sNwkSIntKey, confFCnt, buf[0:len(buf)-4]); err != nil {
+		return nil, err
+	}
+	binary.LittleEndian.PutUint32(buf[len(buf)-4:], p.MIC)
+	return buf, nil
+}
+
+// fOptsOffset is the position of the FOpts field in a message; after the
+// MHDR, DevAddr, FCtrl and FCnt fields.
+const fOptsOffset = 8
+
+// DecryptFOpts decrypts the FOpts field in a message from a LoRaWAN 1.1
+// device and decodes the MAC commands. The rawMessage parameter is the
+// message as received from the device.
+func (p *PHYPayload) DecryptFOpts(nwkSEncKey AESKey, rawMessage []byte) error {
+	fOptsLen := int(p.MACPayload.FHDR.FCtrl.FOptsLen)
+	if fOptsLen == 0 {
+		return nil
+	}
+	if len(rawMessage) < fOptsOffset+fOptsLen {
+		return ErrBufferTruncated
+	}
+	buf := make([]byte, len(rawMessage))
+	copy(buf, rawMessage)
+	p.encryptFOpts(nwkSEncKey, p.MACPayload.FHDR.FCnt, buf[fOptsOffset:fOptsOffset+fOptsLen])
+
+	p.MACPayload.FHDR.FOpts = NewMACCommandSet(p.MHDR.MType, fOptsLen)
+	pos := fOptsOffset
+	if err := p.MACPayload.FHDR.FOpts.decode(buf, &pos); err != nil && err != errUnknownMAC {
+		return err
+	}
+	return nil
+}
+
+// encryptFOpts encrypts (or decrypts) the FOpts field for LoRaWAN 1.1 devices.
+// The fCnt parameter is FCntUp for uplinks and NFCntDown for downlinks. The
+// FOpts field is at most 15 bytes long so a single block is sufficient.
+func (p *PHYPayload) encryptFOpts(nwkSEncKey AESKey, fCnt uint32, fOpts []byte) {
+	A := make([]byte, 16)
+	A[0] = 0x01
+	if !p.MHDR.MType.Uplink() {
+		A[5] = 1
+	}
+	binary.LittleEndian.PutUint32(A[6:], p.MACPayload.FHDR.DevAddr.ToUint32())
+	binary.LittleEndian.PutUint32(A[10:], fCnt)
+
+	S := make([]byte, 16)
+	block, _ := aes.NewCipher(nwkSEncKey.Key[0:])
+	block.Encrypt(S, A)
+	for i := range fOpts {
+		fOpts[i] ^= S[i]
+	}
+}
+
 // Encrypt encrypts message according to [4.3.3.1]
 func (p *PHYPayload) encrypt(nwkSKey AESKey, appSKey AESKey) error {
 	p.Decrypt(nwkSKey, appSKey)
@@ -255,7 +365,7 @@ func (p *PHYPayload) Decrypt(nwkSKey AESKey, appSKey AESKey) {
 			A[5] = 1
 		}
 		binary.LittleEndian.PutUint32(A[6:], p.MACPayload.FHDR.DevAddr.ToUint32())
-		binary.LittleEndian.PutUint32(A[10:], uint32(p.MACPayload.FHDR.FCnt))
+		binary.LittleEndian.PutUint32(A[10:], p.MACPayload.FHDR.FCnt)
 
 		A[15] = byte(i + 1)
 
@@ -1,7 +1,10 @@
 package protocol
 
 import (
+	"bytes"
+	"crypto/aes"
 	"encoding/base64"
+	"encoding/binary"
 	"reflect"
 	"testing"
 
@@ -51,6 +54,46 @@ func TestDecodeJoinAccept(t *testing.T) {
 	}
 }
 
+func TestDecodeJoinAcceptCFList(t *testing.T) {
+	aesKey, _ := AESKeyFromString("01020304 05060708 01020304 05060708")
+	for _, cfList := range []CFList{
+		{Type: CFListChannelMask, ChMask: [5]uint16{0xFF00, 0, 0, 0, 0x0002}},
+		{Type: CFListFrequencies, Frequencies: [5]uint32{867100000, 867300000}},
+	} {
+		input := PHYPayload{
+			MHDR: MHDR{MType: JoinAccept, MajorVersion: MaxSupportedVersion},
+			JoinAcceptPayload: JoinAcceptPayload{
+				AppNonce:   [3]byte{0, 1, 2},
+				NetID:      0x00010203,
+				DevAddr:    DevAddr{NwkID: 1, NwkAddr: 2},
+				DLSettings: DLSettings{RX1DRoffset: 1, RX2DataRate: 2},
+				RxDelay:    1,
+				CFList:     cfList,
+			},
+		}
+		buffer, err := input.EncodeJoinAccept(aesKey)
+		if err != nil {
+			t.Fatal("Got error encoding JoinAccept: ", err)
+		}
+		if len(buffer) != 33 {
+			t.Fatalf("Expected 33 bytes for JoinAccept with CFList but got %d", len(buffer))
+		}
+		payload := PHYPayload{}
+		if err := payload.UnmarshalBinary(buffer); err != nil {
+			t.Fatal("Got error decoding JoinAccept payload: ", err)
+		}
+		if err = payload.DecodeJoinAccept(aesKey, buffer); err != nil {
+			t.Fatal("Got error decoding JoinAccept payload: ", err)
+		}
+		if payload.MIC != input.MIC {
+			t.Fatalf("MIC doesn't match. Expected %v but got %v", input.MIC, payload.MIC)
+		}
+		if payload.JoinAcceptPayload != input.JoinAcceptPayload {
+			t.Fatalf("Not the same output as input in: %v out: %v", input.JoinAcceptPayload, payload.JoinAcceptPayload)
+		}
+	}
+}
+
 func TestDecodeShortBuffers(t *testing.T) {
 	p := NewPHYPayload(UnconfirmedDataUp)
 
@@ -207,7 +250,7 @@ func TestDecrypt_PleasedToMeetYouMrStanley(t *testing.T) {
 	}
 	var ExpectedNetworkID uint8
 	var ExpectedNetworkAddress uint32 = 0x1020304
-	var ExpectedSequenceNumber uint16 = 0xB3
+	var ExpectedSequenceNumber uint32 = 0xB3
 	var ExpectedMIC uint32 = 0x5345CB54
 
 	var ExpectedPlaintextPayload = "Pleased to meet you Mr Stanley!!"
@@ -275,7 +318,7 @@ func TestEncryptResponseMessage(t *testing.T) {
 
 func TestDecodingPartialBuffers(t *testing.T) {
 	p := NewPHYPayload(ConfirmedDataUp)
-	p.MACPayload.FHDR.FCnt = uint16(0xFFFF)
+	p.MACPayload.FHDR.FCnt = 0xFFFF
 	p.MACPayload.FPort = 223
 	p.MACPayload.FHDR.FOpts.Add(NewUplinkMACCommand(LinkADRAns))
 	p.MACPayload.FHDR.FOpts.Add(NewUplinkMACCommand(BeaconFreqReq))
@@ -686,3 +729,111 @@ func TestDecodeJoinAcceptInvalidMessageType(t *testing.T) {
 		t.Fatal("Expected error when encoding joinaccept with invalid message type")
 	}
 }
+
+func TestEncodeMessage11(t *testing.T) {
+	sNwkSIntKey, _ := AESKeyFromString("0102030405060708 0102030405060708")
+	nwkSEncKey, _ := AESKeyFromString("0807060504030201 0807060504030201")
+	appSKey, _ := AESKeyFromString("00010203 04050607 00010203 04050607")
+
+	input := NewPHYPayload(UnconfirmedDataDown)
+	input.MACPayload.FHDR.DevAddr = DevAddr{NwkID: 1, NwkAddr: 2}
+	input.MACPayload.FHDR.FCtrl.ACK = true
+	conf := NewDownlinkMACCommand(RekeyConf).(*MACRekeyConf)
+	conf.Minor = 1
+	input.MACPayload.FHDR.FOpts.Add(conf)
+	input.MACPayload.FPort = 12
+	input.MACPayload.FRMPayload = []byte{1, 2, 3}
+
+	// Application payloads use AFCntDown, FOpts are encrypted with NFCntDown
+	buffer, err := input.EncodeMessage11(sNwkSIntKey, nwkSEncKey, appSKey, 3, 12, 42)
+	if err != nil {
+		t.Fatal("Got error encoding message: ", err)
+	}
+	if buffer[fOptsOffset] == byte(RekeyConf) && buffer[fOptsOffset+1] == 1 {
+		t.Fatal("FOpts should be encrypted")
+	}
+
+	output := NewPHYPayload(Proprietary)
+	if err := output.UnmarshalBinary(buffer); err != nil {
+		t.Fatal("Got error unmarshaling message: ", err)
+	}
+	if output.MACPayload.FHDR.FCnt != 12 {
+		t.Fatalf("Expected AFCntDown (12) as the frame counter but got %d", output.MACPayload.FHDR.FCnt)
+	}
+	mic, err := output.CalculateDownlinkMIC11(sNwkSIntKey, 42, buffer[0:len(buffer)-4])
+	if err != nil || mic != output.MIC {
+		t.Fatalf("MIC does not match (0x%08x != 0x%08x, err=%v)", mic, output.MIC, err)
+	}
+	decrypted := make([]byte, len(buffer))
+	copy(decrypted, buffer)
+	output.encryptFOpts(nwkSEncKey, 3, decrypted[fOptsOffset:fOptsOffset+int(output.MACPayload.FHDR.FCtrl.FOptsLen)])
+	if err := output.UnmarshalBinary(decrypted); err != nil {
+		t.Fatal("Got error unmarshaling decrypted message: ", err)
+	}
+	output.Decrypt(nwkSEncKey, appSKey)
+	if !output.MACPayload.FHDR.FOpts.Contains(RekeyConf) {
+		t.Fatal("Missing RekeyConf in decrypted FOpts")
+	}
+	if output.MACPayload.FHDR.FOpts.List()[0].(*MACRekeyConf).Minor != 1 {
+		t.Fatal("Incorrect RekeyConf in decrypted FOpts")
+	}
+	if !bytes.Equal(output.MACPayload.FRMPayload, []byte{1, 2, 3}) {
+		t.Fatalf("Incorrect payload: %v", output.MACPayload.FRMPayload)
+	}
+
+	// MAC commands on port 0 use NFCntDown
+	input = NewPHYPayload(UnconfirmedDataDown)
+	input.MACPayload.FHDR.DevAddr = DevAddr{NwkID: 1, NwkAddr: 2}
+	input.MACPayload.MACCommands.Add(NewDownlinkMACCommand(DevStatusReq))
+	buffer, err = input.EncodeMessage11(sNwkSIntKey, nwkSEncKey, appSKey, 3, 12, 0)
+	if err != nil {
+		t.Fatal("Got error encoding message: ", err)
+	}
+	output = NewPHYPayload(Proprietary)
+	if err := output.UnmarshalBinary(buffer); err != nil {
+		t.Fatal("Got error unmarshaling message: ", err)
+	}
+	if output.MACPayload.FHDR.FCnt != 3 {
+		t.Fatalf("Expected NFCntDown (3) as the frame counter but got %d", output.MACPayload.FHDR.FCnt)
+	}
+}
+
+func TestEncodeJoinAccept11(t *testing.T) {
+	nwkKey, _ := AESKeyFromString("00010203 04050607 00010203 04050607")
+	devEUI := EUIFromInt64(0x0102030405060708)
+	joinEUI := EUIFromInt64(0x0807060504030201)
+
+	p := NewPHYPayload(JoinAccept)
+	p.JoinAcceptPayload = JoinAcceptPayload{
+		AppNonce:   [3]byte{1, 0, 0},
+		NetID:      0x010203,
+		DevAddr:    DevAddr{NwkID: 1, NwkAddr: 2},
+		DLSettings: DLSettings{RX1DRoffset: 3, RX2DataRate: 4, OptNeg: true},
+		RxDelay:    5,
+	}
+	buffer, err := p.EncodeJoinAccept11(nwkKey, devEUI, joinEUI, 0x1234)
+	if err != nil {
+		t.Fatal("Got error encoding JoinAccept: ", err)
+	}
+
+	// The device encrypts the message to decrypt it
+	decrypted := make([]byte, len(buffer))
+	decrypted[0] = buffer[0]
+	cipher, _ := aes.NewCipher(nwkKey.Key[:])
+	cipher.Encrypt(decrypted[1:], buffer[1:])
+
+	decoded := JoinAcceptPayload{}
+	pos := 1
+	if err := decoded.decode(decrypted, &pos); err != nil {
+		t.Fatal("Got error decoding JoinAccept: ", err)
+	}
+	if decoded.AppNonce != p.JoinAcceptPayload.AppNonce || !decoded.DLSettings.OptNeg {
+		t.Fatalf("Decoded JoinAccept is different: %+v", decoded)
+	}
+
+	jsIntKey, _ := jsIntKeyFromDevEUI(nwkKey, devEUI)
+	mic, _ := p.CalculateJoinAcceptMIC11(jsIntKey, joinEUI, 0x1234, decrypted[0:len(decrypted)-4])
+	if mic != binary.LittleEndian.Uint32(decrypted[len(decrypted)-4:]) {
+		t.Fatal("JoinAccept MIC does not match")
+	}
+}
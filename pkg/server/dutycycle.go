@@ -0,0 +1,122 @@
+package server
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/protocol"
+)
+
+// DutyCycleWindow is the observation period used when calculating the duty
+// cycle for a gateway.
+const DutyCycleWindow = time.Hour
+
+var (
+	// ErrDutyCycleExceeded is returned when a transmission doesn't fit within
+	// the gateway's duty cycle for the sub-band
+	ErrDutyCycleExceeded = errors.New("duty cycle exceeded")
+	// ErrDwellTimeExceeded is returned when a transmission is longer than the
+	// band's max dwell time
+	ErrDwellTimeExceeded = errors.New("dwell time exceeded")
+)
+
+// transmission is a single transmission from a gateway
+type transmission struct {
+	start   time.Time
+	airtime time.Duration
+}
+
+// ledgerKey identifies the transmissions from a gateway in a sub-band
+type ledgerKey struct {
+	gatewayEUI protocol.EUI
+	subBand    band.SubBand
+}
+
+// DutyCycleLedger keeps track of the airtime used by each gateway in each of
+// the band's sub-bands. The transmissions are kept for one observation window
+// (DutyCycleWindow).
+type DutyCycleLedger struct {
+	transmissions map[ledgerKey][]transmission
+	mutex         *sync.Mutex
+}
+
+// NewDutyCycleLedger creates a new DutyCycleLedger instance
+func NewDutyCycleLedger() DutyCycleLedger {
+	return DutyCycleLedger{
+		transmissions: make(map[ledgerKey][]transmission),
+		mutex:         &sync.Mutex{},
+	}
+}
+
+// budget returns the airtime a gateway can use in the sub-band in one
+// observation window. The budget is rounded to whole milliseconds since the
+// duty cycle is a float32.
+func budget(subBand band.SubBand) time.Duration {
+	ms := math.Round(float64(subBand.DutyCycle) * float64(DutyCycleWindow/time.Millisecond))
+	return time.Duration(ms) * time.Millisecond
+}
+
+// current removes the transmissions outside the observation window and
+// returns the airtime used. The mutex must be held by the caller.
+func (d *DutyCycleLedger) current(key ledgerKey, now time.Time) time.Duration {
+	var used time.Duration
+	var current []transmission
+	for _, v := range d.transmissions[key] {
+		if now.Sub(v.start) < DutyCycleWindow {
+			current = append(current, v)
+			used += v.airtime
+		}
+	}
+	if len(current) == 0 {
+		delete(d.transmissions, key)
+		return 0
+	}
+	d.transmissions[key] = current
+	return used
+}
+
+// Reserve records a transmission on the frequency from the gateway. The
+// transmission is rejected with ErrDwellTimeExceeded if it is longer than the
+// band's max dwell time and with ErrDutyCycleExceeded if the gateway would
+// exceed the duty cycle for the frequency's sub-band.
+func (d *DutyCycleLedger) Reserve(gatewayEUI protocol.EUI, config *band.Configuration, frequency float32, airtime time.Duration) error {
+	if config.MaxDwellTime > 0 && airtime > config.MaxDwellTime {
+		return ErrDwellTimeExceeded
+	}
+	subBand := config.SubBand(frequency)
+	if subBand.DutyCycle == 0 {
+		return nil
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	now := time.Now()
+	key := ledgerKey{gatewayEUI, subBand}
+	if d.current(key, now)+airtime > budget(subBand) {
+		return ErrDutyCycleExceeded
+	}
+	d.transmissions[key] = append(d.transmissions[key], transmission{start: now, airtime: airtime})
+	return nil
+}
+
+// Used returns the airtime the gateway has used in the sub-band in the
+// current observation window.
+func (d *DutyCycleLedger) Used(gatewayEUI protocol.EUI, subBand band.SubBand) time.Duration {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.current(ledgerKey{gatewayEUI, subBand}, time.Now())
+}
+
+// Remaining returns the airtime the gateway has left in the sub-band in the
+// current observation window. Sub-bands without a duty cycle limit have no
+// budget.
+func (d *DutyCycleLedger) Remaining(gatewayEUI protocol.EUI, subBand band.SubBand) time.Duration {
+	remaining := budget(subBand) - d.Used(gatewayEUI, subBand)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDutyCycleLedger(t *testing.T) {
+	assert := require.New(t)
+
+	eu868, _ := band.NewBand(band.EU868Band)
+	config := eu868.Configuration()
+	rx1 := config.SubBand(868.1)
+	rx2 := config.SubBand(869.525)
+
+	ledger := NewDutyCycleLedger()
+	gw1 := protocol.EUIFromInt64(1)
+	gw2 := protocol.EUIFromInt64(2)
+
+	// 1% of an hour is 36 seconds
+	assert.NoError(ledger.Reserve(gw1, config, 868.1, 30*time.Second))
+	assert.NoError(ledger.Reserve(gw1, config, 868.3, 5*time.Second))
+	assert.Equal(ErrDutyCycleExceeded, ledger.Reserve(gw1, config, 868.5, 2*time.Second), "Gateway 1 should be out of airtime")
+	assert.Equal(35*time.Second, ledger.Used(gw1, rx1))
+	assert.Equal(time.Second, ledger.Remaining(gw1, rx1))
+
+	// The sub-bands have separate limits. 10% of an hour is 360 seconds.
+	assert.NoError(ledger.Reserve(gw1, config, 869.525, 2*time.Second), "RX2 sub-band should have airtime left")
+	assert.Equal(358*time.Second, ledger.Remaining(gw1, rx2))
+
+	assert.NoError(ledger.Reserve(gw2, config, 868.1, time.Second), "Gateways should have separate limits")
+	assert.Equal(time.Second, ledger.Used(gw2, rx1))
+
+	// The gaps in the SRD sub-bands use the 0.1% limit (3.6 seconds)
+	assert.Equal(ErrDutyCycleExceeded, ledger.Reserve(gw1, config, 869.3, 4*time.Second))
+	assert.NoError(ledger.Reserve(gw1, config, 869.3, 3*time.Second))
+
+	// Frequencies outside the sub-bands have no limit
+	assert.NoError(ledger.Reserve(gw1, config, 915.2, time.Minute))
+
+	// The dwell time limits single transmissions when it is turned on
+	as923, _ := band.NewBand(band.AS923Band)
+	assert.NoError(ledger.Reserve(gw1, as923.Configuration(), 923.2, 500*time.Millisecond))
+	dwellTime := as923.(band.DwellTimePlan).WithDwellTime()
+	assert.Equal(ErrDwellTimeExceeded, ledger.Reserve(gw1, dwellTime.Configuration(), 923.2, 500*time.Millisecond))
+	assert.NoError(ledger.Reserve(gw1, dwellTime.Configuration(), 923.2, 300*time.Millisecond))
+}
@@ -7,8 +7,9 @@ import (
 )
 
 type route[I comparable, T any] struct {
-	id I
-	ch chan T
+	id  I
+	all bool // Subscribed to all identifiers
+	ch  chan T
 }
 
 // EventRouter is a channel event router. It will route events (or entities)
@@ -36,7 +37,18 @@ func (e *EventRouter[I, T]) Subscribe(identifier I) <-chan T {
 	defer e.mutex.Unlock()
 
 	events := make(chan T, e.channelLength)
-	e.routes = append(e.routes, route[I, T]{identifier, events})
+	e.routes = append(e.routes, route[I, T]{id: identifier, ch: events})
+
+	return events
+}
+
+// SubscribeAll subscribes to the events for all identifiers
+func (e *EventRouter[I, T]) SubscribeAll() <-chan T {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	events := make(chan T, e.channelLength)
+	e.routes = append(e.routes, route[I, T]{all: true, ch: events})
 
 	return events
 }
@@ -63,7 +75,7 @@ func (e *EventRouter[I, T]) Publish(id I, event T) {
 	defer e.mutex.Unlock()
 
 	for _, route := range e.routes {
-		if route.id == id {
+		if route.all || route.id == id {
 			select {
 			case route.ch <- event:
 				// This is OK
@@ -27,6 +27,33 @@ func TestEventRouter(t *testing.T) {
 	router.Unsubscribe(ch)
 }
 
+// Subscribers to all identifiers get events for every identifier
+func TestEventRouterSubscribeAll(t *testing.T) {
+	router := NewEventRouter[int, string](2)
+
+	all := router.SubscribeAll()
+	one := router.Subscribe(1)
+
+	router.Publish(0, "zero")
+	router.Publish(1, "one")
+
+	for _, expected := range []string{"zero", "one"} {
+		select {
+		case ev := <-all:
+			if ev != expected {
+				t.Fatalf("Expected %s but got %s", expected, ev)
+			}
+		case <-time.After(10 * time.Millisecond):
+			t.Fatal("Didn't get an event on the channel")
+		}
+	}
+	if len(one) != 1 {
+		t.Fatalf("Expected a single event for route 1 but got %d", len(one))
+	}
+	router.Unsubscribe(all)
+	router.Unsubscribe(one)
+}
+
 // Test with multiple routes (and channels)
 func TestEventRouterMultipleRoutes(t *testing.T) {
 	const numEvents = 4
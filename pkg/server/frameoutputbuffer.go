@@ -17,7 +17,7 @@ type frameOutput struct {
 	MType             protocol.MType
 	ADR               bool                   // ADR enabled/disabled
 	ACK               bool                   // Ack packet
-	FCnt              uint16                 // Frame counter downstream
+	FCnt              uint32                 // Frame counter downstream
 	Port              uint8                  // Port for output. The port is set as the same time as the payload
 	Payload           []byte                 // The (application) payload. Note: This does not include any MAC commands
 	MACCommands       protocol.MACCommandSet // The MAC commands in the packet
@@ -116,11 +116,30 @@ func (d *FrameOutputBuffer) SetJoinAcceptPayload(deviceEUI protocol.EUI, payload
 	d.frameData[deviceEUI] = fd
 }
 
+// JoinAcceptPending returns true if the next frame for the device is a
+// JoinAccept message.
+func (d *FrameOutputBuffer) JoinAcceptPending(deviceEUI protocol.EUI) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	fd, exists := d.frameData[deviceEUI]
+	return exists && fd.MType == protocol.JoinAccept
+}
+
 // GetPHYPayloadForDevice retrieves the next PHYPayload item for the device.
 // If there's no data available for the device an error is returned. Note
 // that this might not pull all of the data for the device.
 // BUG(stalehd): Uses fixed max length for payload
 func (d *FrameOutputBuffer) GetPHYPayloadForDevice(device *model.Device, context *FrameContext) (protocol.PHYPayload, error) {
+	return d.ReservePHYPayloadForDevice(device, context, nil)
+}
+
+// ReservePHYPayloadForDevice retrieves the next PHYPayload item for the device
+// like GetPHYPayloadForDevice but the data is only removed from the buffer if
+// the reserve function accepts the payload. The data is kept in the buffer if
+// reserve returns an error and the error is returned. A nil reserve function
+// accepts all payloads.
+func (d *FrameOutputBuffer) ReservePHYPayloadForDevice(device *model.Device, context *FrameContext, reserve func(protocol.PHYPayload) error) (protocol.PHYPayload, error) {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 
@@ -205,6 +224,12 @@ func (d *FrameOutputBuffer) GetPHYPayloadForDevice(device *model.Device, context
 		fd.JoinAcceptPayload = protocol.JoinAcceptPayload{}
 	}
 
+	// fd is a copy so the buffer is unchanged until the payload is accepted
+	if reserve != nil {
+		if err := reserve(ret); err != nil {
+			return protocol.PHYPayload{}, err
+		}
+	}
 	d.frameData[device.DeviceEUI] = fd
 	return ret, nil
 }
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"errors"
 	"math/rand"
 	"testing"
 
@@ -240,3 +241,35 @@ func TestSetMessageType(t *testing.T) {
 	}
 
 }
+
+// Payloads that aren't accepted by the reserve function are kept in the buffer
+func TestReservePHYPayload(t *testing.T) {
+	da := NewFrameOutputBuffer()
+
+	d := &model.Device{DeviceEUI: makeRandomEUI(), DevAddr: makeRandomDevAddr()}
+	da.SetPayload(d.DeviceEUI, []byte{0, 1, 2, 3}, 1, false)
+	da.SetMessageAckFlag(d.DeviceEUI, true)
+
+	reserveErr := errors.New("no airtime")
+	if _, err := da.ReservePHYPayloadForDevice(d, &context, func(protocol.PHYPayload) error { return reserveErr }); err != reserveErr {
+		t.Fatalf("Expected reserve error but got %v", err)
+	}
+
+	var reserved protocol.PHYPayload
+	ret, err := da.ReservePHYPayloadForDevice(d, &context, func(p protocol.PHYPayload) error {
+		reserved = p
+		return nil
+	})
+	if err != nil {
+		t.Fatal("Got error retrieving phy payload: ", err)
+	}
+	if !bytes.Equal(ret.MACPayload.FRMPayload, []byte{0, 1, 2, 3}) || !ret.MACPayload.FHDR.FCtrl.ACK {
+		t.Fatalf("Payload and ACK should be kept after the reserve error: %+v", ret.MACPayload)
+	}
+	if !bytes.Equal(reserved.MACPayload.FRMPayload, ret.MACPayload.FRMPayload) {
+		t.Fatal("The reserve function should get the returned payload")
+	}
+	if _, err := da.GetPHYPayloadForDevice(d, &context); err == nil {
+		t.Fatal("Expected no more data for the device")
+	}
+}
@@ -4,30 +4,365 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/lg"
 	"github.com/lab5e/lospan/pkg/protocol"
 )
 
 // Parameters holds the configuration for the system
 type Parameters struct {
-	GRPCEndpoint         string `kong:"help='gRPC endpoint for API',default=':5150'"`
-	GatewayPort          int    `kong:"help='Port for gateway interface',default='8000'"`
-	NetworkID            uint   `kong:"help='Network ID for server',default='0'"`
-	MA                   string `kong:"help='MA for key generator',default='00-00-00'"`
-	ConnectionString     string `kong:"help='SQLite connection string',default=':memory:'"`
-	DisableGatewayChecks bool   `kong:"help='Disable gateway IP address checking'"`
-	DisableNonceCheck    bool   `kong:"help='Disable nonce check for devices',default='false'"`
+	GRPCEndpoint         string        `kong:"help='gRPC endpoint for API',default=':5150'"`
+	GatewayPort          int           `kong:"help='Port for gateway interface',default='8000'"`
+	GatewayBackend       string        `kong:"help='Gateway backend. semtech is the UDP packet forwarder, basicstation the LoRa Basic Station and mqtt the MQTT gateway bridge',enum='semtech,basicstation,mqtt',default='semtech'"`
+	ExtraGateways        []string      `kong:"help='Additional gateway backends as <backend>:<port>, f.e. semtech:1700,basicstation:3001,mqtt'"`
+	MQTTBroker           string        `kong:"help='MQTT broker for the mqtt gateway backend',default='tcp://localhost:1883'"`
+	MQTTUsername         string        `kong:"help='User name for the MQTT broker'"`
+	MQTTPassword         string        `kong:"help='Password for the MQTT broker'"`
+	MQTTTopicPrefix      string        `kong:"help='Topic prefix for the MQTT gateway bridge',default='gateway'"`
+	AppMQTTBroker        string        `kong:"help='MQTT broker for the application integration. The integration is disabled if blank'"`
+	AppMQTTUsername      string        `kong:"help='User name for the application MQTT broker'"`
+	AppMQTTPassword      string        `kong:"help='Password for the application MQTT broker'"`
+	AppMQTTTopicPrefix   string        `kong:"help='Topic prefix for the application integration',default='application'"`
+	AppMQTTBufferSize    int           `kong:"help='Number of application events to buffer while the broker is unavailable',default='1000'"`
+	WebhookQueueSize     int           `kong:"help='Number of events to queue for each webhook',default='100'"`
+	WebhookMaxAttempts   int           `kong:"help='Maximum number of delivery attempts for webhook events',default='5'"`
+	NetworkID            uint          `kong:"help='Network ID for server',default='0'"`
+	MA                   string        `kong:"help='MA for key generator',default='00-00-00'"`
+	ConnectionString     string        `kong:"help='SQLite connection string',default=':memory:'"`
+	DisableGatewayChecks bool          `kong:"help='Disable gateway IP address checking'"`
+	DisableNonceCheck    bool          `kong:"help='Disable nonce check for devices',default='false'"`
+	DedupWindow          time.Duration `kong:"help='Time to wait for copies of a frame from other gateways',default='100ms'"`
+	ClassBBeacons        bool          `kong:"help='Send class B beacons through the gateways',default='false'"`
+	GatewayTimeout       time.Duration `kong:"help='Time without keepalives before a gateway is considered offline',default='1m'"`
+	ExtraChannels        []string      `kong:"help='Extra uplink channel for a band with a dynamic channel plan as <band>:<frequency>, f.e. EU868:867.1,EU868:867.3. Use <band>:<uplink>:<downlink> to set a different RX1 frequency. Max 5 channels per band'"`
+	PingSlotChannels     []string      `kong:"help='Class B ping slot channel for a band as <band>:<frequency>:<data rate>, f.e. EU868:869.525:3. Bands without a channel use the band default'"`
+	BeaconFrequencies    []string      `kong:"help='Class B beacon frequency for a band as <band>:<frequency>, f.e. EU868:869.525. Bands without a frequency use the band default'"`
+	DwellTimeBands       []string      `kong:"help='Bands with the 400 ms dwell time limit, f.e. AS923-1,AS923-2. The limit is sent to the devices with TxParamSetupReq'"`
+
+	settings atomic.Pointer[bandSettings] // The parsed band settings. Set by Validate or on first use
+}
+
+// Gateway backends
+const (
+	// SemtechBackend is the Semtech UDP packet forwarder
+	SemtechBackend = "semtech"
+	// BasicStationBackend is the LoRa Basic Station LNS protocol
+	BasicStationBackend = "basicstation"
+	// MQTTBackend is the MQTT gateway bridge. It connects to the MQTT broker
+	// and doesn't use the port.
+	MQTTBackend = "mqtt"
+)
+
+// Defaults for the MQTT gateway bridge
+const (
+	DefaultMQTTBroker      = "tcp://localhost:1883"
+	DefaultMQTTTopicPrefix = "gateway"
+)
+
+// Defaults for the MQTT application integration
+const (
+	DefaultAppMQTTTopicPrefix = "application"
+	DefaultAppMQTTBufferSize  = 1000
+)
+
+// Defaults for the application webhooks
+const (
+	DefaultWebhookQueueSize   = 100
+	DefaultWebhookMaxAttempts = 5
+)
+
+// GatewayListener is a gateway backend listening on a port
+type GatewayListener struct {
+	Backend string
+	Port    int
+}
+
+// GatewayListeners returns the gateway backends to launch. The first
+// listener is the gateway backend and port, followed by the extra gateways.
+func (cfg *Parameters) GatewayListeners() ([]GatewayListener, error) {
+	backend := cfg.GatewayBackend
+	if backend == "" {
+		backend = SemtechBackend
+	}
+	ret := []GatewayListener{{Backend: backend, Port: cfg.GatewayPort}}
+	for _, v := range cfg.ExtraGateways {
+		if v == MQTTBackend {
+			ret = append(ret, GatewayListener{Backend: MQTTBackend})
+			continue
+		}
+		fields := strings.Split(v, ":")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid gateway backend %q. Format is <backend>:<port>", v)
+		}
+		port, err := strconv.Atoi(fields[1])
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid port for gateway backend %q", v)
+		}
+		ret = append(ret, GatewayListener{Backend: fields[0], Port: port})
+	}
+	seen := make(map[GatewayListener]bool)
+	for _, l := range ret {
+		switch l.Backend {
+		case SemtechBackend, BasicStationBackend:
+		case MQTTBackend:
+			// There's only one connection to the broker
+			l.Port = 0
+		default:
+			return nil, fmt.Errorf("unknown gateway backend: %s", l.Backend)
+		}
+		if seen[l] {
+			return nil, fmt.Errorf("gateway backend %s is already listening on port %d", l.Backend, l.Port)
+		}
+		seen[l] = true
+	}
+	return ret, nil
+}
+
+// MaxExtraChannels is the maximum number of extra channels for a band. This
+// is the number of frequencies in the CFList.
+const MaxExtraChannels = 5
+
+// Channels returns the extra uplink channels that are set up on devices in
+// bands with dynamic channel plans. The channels are keyed by the band name
+// (as returned by FrequencyPlan.Name). The uplink and downlink frequencies
+// must be inside the band.
+func (cfg *Parameters) Channels() (map[string][]band.Channel, error) {
+	ret := make(map[string][]band.Channel)
+	for _, v := range cfg.ExtraChannels {
+		fields := strings.SplitN(v, ":", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid format for %q. Format is <band>:<uplink>[:<downlink>]", v)
+		}
+		bandType, err := band.ParseBand(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		plan, err := band.NewBand(bandType)
+		if err != nil {
+			return nil, err
+		}
+		if !band.DynamicChannels(plan) {
+			return nil, fmt.Errorf("the %s band doesn't support extra channels", bandType)
+		}
+		ch, err := band.ParseChannel(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		config := plan.Configuration()
+		if !config.Contains(ch.Frequency) || (ch.DownlinkFrequency != 0 && !config.Contains(ch.DownlinkFrequency)) {
+			return nil, fmt.Errorf("channel %s is outside the %s band", ch, bandType)
+		}
+		channels := ret[plan.Name()]
+		for _, existing := range channels {
+			if existing.Frequency == ch.Frequency {
+				return nil, fmt.Errorf("channel %s is already added for %s", ch, bandType)
+			}
+		}
+		if len(channels) == MaxExtraChannels {
+			return nil, fmt.Errorf("too many extra channels for %s. Max is %d", bandType, MaxExtraChannels)
+		}
+		ret[plan.Name()] = append(channels, ch)
+	}
+	return ret, nil
+}
+
+// parseBandChannel parses a <band>:<frequency>[:<data rate>] setting. The
+// frequency must be inside the band and the data rate must be valid for the
+// band.
+func parseBandChannel(setting string, withDataRate bool) (band.FrequencyPlan, band.DownlinkParameters, error) {
+	ret := band.DownlinkParameters{}
+	fields := strings.Split(setting, ":")
+	if (withDataRate && len(fields) != 3) || (!withDataRate && len(fields) != 2) {
+		return nil, ret, fmt.Errorf("invalid format for %q", setting)
+	}
+	bandType, err := band.ParseBand(fields[0])
+	if err != nil {
+		return nil, ret, err
+	}
+	plan, err := band.NewBand(bandType)
+	if err != nil {
+		return nil, ret, err
+	}
+	frequency, err := strconv.ParseFloat(fields[1], 32)
+	if err != nil {
+		return nil, ret, fmt.Errorf("invalid frequency in %q", setting)
+	}
+	ret.Frequency = float32(frequency)
+	if !plan.Configuration().Contains(ret.Frequency) {
+		return nil, ret, fmt.Errorf("frequency %.3f MHz is outside the %s band", ret.Frequency, bandType)
+	}
+	if withDataRate {
+		dataRate, err := strconv.ParseUint(fields[2], 10, 8)
+		if err != nil {
+			return nil, ret, fmt.Errorf("invalid data rate in %q", setting)
+		}
+		ret.DataRate = uint8(dataRate)
+		if _, err := plan.Encoding(ret.DataRate); err != nil {
+			return nil, ret, fmt.Errorf("invalid data rate in %q: %v", setting, err)
+		}
+	}
+	return plan, ret, nil
+}
+
+// ClassBChannels returns the ping slot channels and beacon frequencies for
+// class B devices. The channels are keyed by the band name (as returned by
+// FrequencyPlan.Name). The beacons use the band's beacon data rate.
+func (cfg *Parameters) ClassBChannels() (pingSlots map[string]band.DownlinkParameters, beacons map[string]float32, err error) {
+	pingSlots = make(map[string]band.DownlinkParameters)
+	for _, v := range cfg.PingSlotChannels {
+		plan, ch, err := parseBandChannel(v, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, exists := pingSlots[plan.Name()]; exists {
+			return nil, nil, fmt.Errorf("ping slot channel for %s is already set", plan.Name())
+		}
+		pingSlots[plan.Name()] = ch
+	}
+	beacons = make(map[string]float32)
+	for _, v := range cfg.BeaconFrequencies {
+		plan, ch, err := parseBandChannel(v, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, exists := beacons[plan.Name()]; exists {
+			return nil, nil, fmt.Errorf("beacon frequency for %s is already set", plan.Name())
+		}
+		beacons[plan.Name()] = ch.Frequency
+	}
+	return pingSlots, beacons, nil
 }
 
+// BeaconFrequency returns the class B beacon frequency for the beacon period
+// starting at beaconTime (GPS time). Bands without a configured beacon
+// frequency use the band's beacon frequencies.
+func (cfg *Parameters) BeaconFrequency(plan band.FrequencyPlan, beaconTime time.Duration) float32 {
+	if _, beacon := cfg.BandClassBChannels(plan); beacon != 0 {
+		return beacon
+	}
+	return plan.Configuration().BeaconFrequency(beaconTime)
+}
+
+// bandSettings holds the per-band settings parsed from the configuration so
+// they aren't parsed for every frame.
+type bandSettings struct {
+	channels  map[string][]band.Channel
+	pingSlots map[string]band.DownlinkParameters
+	beacons   map[string]float32
+	dwellTime map[band.FrequencyBandType]bool
+}
+
+func (cfg *Parameters) parseBandSettings() (*bandSettings, error) {
+	var err error
+	ret := &bandSettings{}
+	if ret.channels, err = cfg.Channels(); err != nil {
+		return nil, err
+	}
+	if ret.pingSlots, ret.beacons, err = cfg.ClassBChannels(); err != nil {
+		return nil, err
+	}
+	if ret.dwellTime, err = cfg.dwellTimeBands(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// bandSettings returns the parsed band settings. Validate parses the settings
+// but they are parsed on first use if the configuration isn't validated.
+// Invalid settings are ignored.
+func (cfg *Parameters) bandSettings() *bandSettings {
+	if ret := cfg.settings.Load(); ret != nil {
+		return ret
+	}
+	ret, err := cfg.parseBandSettings()
+	if err != nil {
+		lg.Warning("Invalid band settings: %v", err)
+		ret = &bandSettings{}
+	}
+	cfg.settings.Store(ret)
+	return ret
+}
+
+// BandChannels returns the extra channels for a band with a dynamic channel
+// plan. Bands with fixed channel plans have no extra channels.
+func (cfg *Parameters) BandChannels(plan band.FrequencyPlan) []band.Channel {
+	if cfg == nil || plan == nil || !band.DynamicChannels(plan) {
+		return nil
+	}
+	return cfg.bandSettings().channels[plan.Name()]
+}
+
+// BandClassBChannels returns the configured ping slot channel and beacon
+// frequency for the band. Bands without a configured channel get 0, ie the
+// band default.
+func (cfg *Parameters) BandClassBChannels(plan band.FrequencyPlan) (band.DownlinkParameters, float32) {
+	if cfg == nil || plan == nil {
+		return band.DownlinkParameters{}, 0
+	}
+	settings := cfg.bandSettings()
+	return settings.pingSlots[plan.Name()], settings.beacons[plan.Name()]
+}
+
+// dwellTimeBands returns the bands with the dwell time limit turned on. Only
+// bands where the limit is set by the network can be used.
+func (cfg *Parameters) dwellTimeBands() (map[band.FrequencyBandType]bool, error) {
+	ret := make(map[band.FrequencyBandType]bool)
+	for _, v := range cfg.DwellTimeBands {
+		bandType, err := band.ParseBand(v)
+		if err != nil {
+			return nil, err
+		}
+		plan, err := band.NewBand(bandType)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := plan.(band.DwellTimePlan); !ok {
+			return nil, fmt.Errorf("the %s band doesn't have a dwell time setting", bandType)
+		}
+		ret[bandType] = true
+	}
+	return ret, nil
+}
+
+// NewBand creates the band instance for a frequency band type. The dwell time
+// limit is turned on for the bands in DwellTimeBands.
+func (cfg *Parameters) NewBand(bandType band.FrequencyBandType) (band.FrequencyPlan, error) {
+	plan, err := band.NewBand(bandType)
+	if err != nil || cfg == nil {
+		return plan, err
+	}
+	if dt, ok := plan.(band.DwellTimePlan); ok && cfg.bandSettings().dwellTime[bandType] {
+		return dt.WithDwellTime(), nil
+	}
+	return plan, nil
+}
+
+// DefaultGatewayTimeout is the default time without keepalives before a
+// gateway is considered offline.
+const DefaultGatewayTimeout = time.Minute
+
 // NewDefaultConfig returns the default configuration. Note that this configuration
 // isn't valid right out of the box; a storage backend must be selected.
 func NewDefaultConfig() *Parameters {
 	return &Parameters{
-		MA:               "00-00-00",
-		NetworkID:        0,
-		ConnectionString: ":memory:",
-		GatewayPort:      8000,
+		MA:                 "00-00-00",
+		NetworkID:          0,
+		ConnectionString:   ":memory:",
+		GatewayPort:        8000,
+		GatewayBackend:     SemtechBackend,
+		DedupWindow:        100 * time.Millisecond,
+		GatewayTimeout:     DefaultGatewayTimeout,
+		MQTTBroker:         DefaultMQTTBroker,
+		MQTTTopicPrefix:    DefaultMQTTTopicPrefix,
+		AppMQTTTopicPrefix: DefaultAppMQTTTopicPrefix,
+		AppMQTTBufferSize:  DefaultAppMQTTBufferSize,
+		WebhookQueueSize:   DefaultWebhookQueueSize,
+		WebhookMaxAttempts: DefaultWebhookMaxAttempts,
 	}
 }
 
@@ -62,5 +397,15 @@ func (cfg *Parameters) Validate() error {
 		return errors.New("connection string is blank")
 	}
 
+	if _, err := cfg.GatewayListeners(); err != nil {
+		return err
+	}
+
+	settings, err := cfg.parseBandSettings()
+	if err != nil {
+		return err
+	}
+	cfg.settings.Store(settings)
+
 	return nil
 }
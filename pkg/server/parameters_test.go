@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/lab5e/lospan/pkg/band"
 	"github.com/lab5e/lospan/pkg/protocol"
 )
 
@@ -74,3 +75,166 @@ func TestMAInvalidMA(t *testing.T) {
 	config.RootMA() // should panic
 	t.Fatal("I expected panic here")
 }
+
+func TestGatewayListeners(t *testing.T) {
+	config := NewDefaultConfig()
+	config.ExtraGateways = []string{"semtech:1700", "basicstation:3001", "mqtt"}
+	listeners, err := config.GatewayListeners()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []GatewayListener{{SemtechBackend, 8000}, {SemtechBackend, 1700}, {BasicStationBackend, 3001}, {MQTTBackend, 0}}
+	if len(listeners) != len(expected) {
+		t.Fatalf("Incorrect listeners: %+v", listeners)
+	}
+	for i := range expected {
+		if listeners[i] != expected[i] {
+			t.Fatalf("Incorrect listener %d: %+v", i, listeners[i])
+		}
+	}
+
+	config.ExtraGateways = []string{"mqtt", "mqtt:1883"}
+	if err := config.Validate(); err == nil {
+		t.Fatal("Expected error for more than one mqtt backend")
+	}
+
+	for _, invalid := range []string{"semtech", "semtech:x", "semtech:0", "foo:1700", "semtech:8000"} {
+		config.ExtraGateways = []string{invalid}
+		if err := config.Validate(); err == nil {
+			t.Fatalf("Expected error for %s", invalid)
+		}
+	}
+}
+
+func TestExtraChannels(t *testing.T) {
+	config := NewDefaultConfig()
+	channels, err := config.Channels()
+	if err != nil || len(channels) != 0 {
+		t.Fatalf("Expected no extra channels by default: %v (%v)", channels, err)
+	}
+	eu868, _ := band.NewBand(band.EU868Band)
+	as923, _ := band.NewBand(band.AS923Band)
+	config.ExtraChannels = []string{"EU868:867.1", "eu868:867.3:869.525", "AS923:922.0"}
+	channels, err = config.Channels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	eu := channels[eu868.Name()]
+	if len(eu) != 2 || eu[0].Frequency != 867.1 || eu[1].DownlinkFrequency != 869.525 {
+		t.Fatalf("Unexpected EU868 channels: %v", eu)
+	}
+	if as := channels[as923.Name()]; len(as) != 1 || as[0].Frequency != 922.0 {
+		t.Fatalf("Unexpected AS923 channels: %v", as)
+	}
+
+	// The channels are parsed once when the configuration is validated
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	config.ExtraChannels = nil
+	if eu := config.BandChannels(eu868); len(eu) != 2 || eu[1].Frequency != 867.3 {
+		t.Fatalf("Unexpected EU868 band channels: %v", eu)
+	}
+	us915, _ := band.NewBand(band.US915Band)
+	if us := config.BandChannels(us915); len(us) != 0 {
+		t.Fatalf("Bands with fixed channel plans should have no extra channels: %v", us)
+	}
+
+	for _, invalid := range [][]string{
+		{"EU868:867.1", "EU868:867.1"},
+		{"EU868:867.1", "EU868:867.3", "EU868:867.5", "EU868:867.7", "EU868:867.9", "EU868:868.8"},
+		{"foo"},
+		{"867.1"},
+		{"EU868:915.2"},
+		{"EU868:867.1:923.3"},
+		{"US915:903.0"},
+	} {
+		config.ExtraChannels = invalid
+		if err := config.Validate(); err == nil {
+			t.Fatalf("Expected error with %v", invalid)
+		}
+	}
+}
+
+func TestClassBChannels(t *testing.T) {
+	config := NewDefaultConfig()
+	pingSlots, beacons, err := config.ClassBChannels()
+	if err != nil || len(pingSlots) != 0 || len(beacons) != 0 {
+		t.Fatalf("Expected no class B channels by default: %v %v (%v)", pingSlots, beacons, err)
+	}
+	eu868, _ := band.NewBand(band.EU868Band)
+	us915, _ := band.NewBand(band.US915Band)
+	config.PingSlotChannels = []string{"EU868:869.3:4", "us915:923.3:8"}
+	config.BeaconFrequencies = []string{"EU868:869.3"}
+	pingSlots, beacons, err = config.ClassBChannels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch := pingSlots[eu868.Name()]; ch.Frequency != 869.3 || ch.DataRate != 4 {
+		t.Fatalf("Unexpected EU868 ping slot channel: %+v", ch)
+	}
+	if ch := pingSlots[us915.Name()]; ch.Frequency != 923.3 || ch.DataRate != 8 {
+		t.Fatalf("Unexpected US915 ping slot channel: %+v", ch)
+	}
+	if beacons[eu868.Name()] != 869.3 || beacons[us915.Name()] != 0 {
+		t.Fatalf("Unexpected beacon frequencies: %v", beacons)
+	}
+	if f := config.BeaconFrequency(us915, 0); f != 923.3 {
+		t.Fatalf("US915 should use the band's beacon frequency, not %.3f", f)
+	}
+	if f := config.BeaconFrequency(eu868, 0); f != 869.3 {
+		t.Fatalf("EU868 should use the configured beacon frequency, not %.3f", f)
+	}
+
+	for _, invalid := range []string{"EU868:869.3", "EU868:915.0:3", "EU868:869.3:9", "XX999:869.3:3", "EU868:foo:3"} {
+		config.PingSlotChannels = []string{invalid}
+		if err := config.Validate(); err == nil {
+			t.Fatalf("Expected error with ping slot channel %q", invalid)
+		}
+	}
+	config.PingSlotChannels = []string{"EU868:869.3:3", "EU868:869.525:3"}
+	if err := config.Validate(); err == nil {
+		t.Fatal("Expected error with two ping slot channels for the same band")
+	}
+	config.PingSlotChannels = nil
+	config.BeaconFrequencies = []string{"EU868:869.3:3"}
+	if err := config.Validate(); err == nil {
+		t.Fatal("Expected error with data rate for the beacon frequency")
+	}
+}
+
+func TestDwellTimeBands(t *testing.T) {
+	config := NewDefaultConfig()
+	plan, err := config.NewBand(band.AS923Band)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Configuration().MaxDwellTime != 0 {
+		t.Fatal("Expected no dwell time limit by default")
+	}
+
+	config.DwellTimeBands = []string{"AS923"}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for bandType, dwellTime := range map[band.FrequencyBandType]bool{
+		band.AS923Band:       true,
+		band.AS923Group2Band: false,
+		band.EU868Band:       false,
+	} {
+		plan, err := config.NewBand(bandType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (plan.Configuration().MaxDwellTime > 0) != dwellTime {
+			t.Fatalf("Unexpected dwell time for %s: %v", bandType, plan.Configuration().MaxDwellTime)
+		}
+	}
+
+	for _, invalid := range []string{"EU868", "XX999"} {
+		config.DwellTimeBands = []string{invalid}
+		if err := config.Validate(); err == nil {
+			t.Fatalf("Expected error with dwell time band %q", invalid)
+		}
+	}
+}
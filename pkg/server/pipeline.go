@@ -9,6 +9,7 @@ import (
 	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/protocol"
 	"github.com/lab5e/lospan/pkg/storage"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 // Pipeline data structures used by the server
@@ -22,6 +23,7 @@ type Context struct {
 	KeyGenerator  *keys.KeyGenerator                           // Key generator for server
 	GwEventRouter *EventRouter[protocol.EUI, gwevents.GwEvent] // Router for GW events
 	AppRouter     *EventRouter[protocol.EUI, *PayloadMessage]  // Router for app data
+	DutyCycle     *DutyCycleLedger                             // Airtime used by the gateways
 }
 
 // RadioContext - metadata for radio stats and settings
@@ -31,19 +33,34 @@ type RadioContext struct {
 	Frequency float32            // Frequency - set by GW IF
 	DataRate  string             // DataRate (f.e. "SF7BW125") - set by GW IF
 	Band      band.FrequencyPlan // Band used
-	RX1Delay  uint8              // RX1Delay - set during decoding
-	RX2Delay  uint8              // RX2Delay - set during decoding
+	Channels  band.ChannelMask   // Uplink channels for the gateway (US915 and AU915 only) - set by GW IF
+	RX1Delay  uint8              // Delay (in seconds) before RX1 - set by scheduler
+	RX2Delay  uint8              // Delay (in seconds) before RX2 - set by scheduler
+	Window    band.RXWindowType  // Receive window used for the downlink - set by scheduler
 	RSSI      int32              // RSSI for device - set by GW IF
 	SNR       float32            // SNR for device - set by GW IF
 }
 
+// TxDelay returns the delay (in seconds) from the uplink to the downlink, ie
+// the delay for the receive window used for the downlink.
+func (r RadioContext) TxDelay() uint8 {
+	if r.Window == band.RX2 {
+		return r.RX2Delay
+	}
+	return r.RX1Delay
+}
+
 // GatewayContext - metadata for gateway; used when responding
 type GatewayContext struct {
 	GatewayEUI      protocol.EUI // The reported EUI
 	GatewayHost     string       // The originating host
 	GatewayPort     int          // The originating port
 	GatewayClock    uint32       // Clock ticks reported by gateway
+	FineTimestamp   int64        // Fine timestamp (ns) reported by gateway. 0 if not supported
 	ProtocolVersion uint8        // Protocol version (wrt packet forwarder)
+	XTime           int64        // Extended concentrator time (Basic Station). 0 if not supported
+	RCtx            int64        // Radio context (Basic Station). Returned to the station in downlinks
+	BridgeContext   []byte       // Opaque context (MQTT gateway bridge). Returned to the bridge in downlinks
 }
 
 // FrameContext is the context for each frame received (frequency, encoding, data rate rx1 offset and so on)
@@ -51,7 +68,33 @@ type FrameContext struct {
 	Device         model.Device      // The decoded Device. Nil if it haven't been decoded yet.
 	Application    model.Application // The decoded application. Nil if it haven't been resolved yet.
 	GatewayContext GatewayPacket     // Context for gateway'
+	Receptions     []GatewayPacket   // All of the gateways that received the frame
 	PayloadCreate  int64             // Timestamp for the payload
+	Downlink       *PayloadMessage   // Downlink event for the payload. Nil if there's no payload
+	DevNonce       uint16            // DevNonce from the JoinRequest. Only set for JoinAccept messages
+}
+
+// GatewayReceptions returns the reception records for all of the gateways that
+// received the frame. Frames that haven't been through the deduplicator only
+// have the gateway context.
+func (f *FrameContext) GatewayReceptions() []model.GatewayReception {
+	packets := f.Receptions
+	if len(packets) == 0 {
+		packets = []GatewayPacket{f.GatewayContext}
+	}
+	var ret []model.GatewayReception
+	for _, v := range packets {
+		ret = append(ret, model.GatewayReception{
+			GatewayEUI:    v.Gateway.GatewayEUI,
+			RSSI:          v.Radio.RSSI,
+			SNR:           v.Radio.SNR,
+			Channel:       v.Radio.Channel,
+			RFChain:       v.Radio.RFChain,
+			Timestamp:     v.Gateway.GatewayClock,
+			FineTimestamp: v.Gateway.FineTimestamp,
+		})
+	}
+	return ret
 }
 
 // GatewayPacket contains a byte buffer plus radio statistics.
@@ -60,7 +103,10 @@ type GatewayPacket struct {
 	Radio      RadioContext
 	Gateway    GatewayContext
 	ReceivedAt time.Time
-	Deadline   float64 // Send deadline for packet (in seconds)
+	Deadline   float64         // Send deadline for packet (in seconds)
+	Immediate  bool            // Send packet immediately, ignoring the gateway clock (class C)
+	GPSTime    time.Duration   // Send packet at this GPS time (class B). 0 = not used
+	Downlink   *PayloadMessage // Downlink event for the application payload in the packet. Published when the gateway has sent the packet
 }
 
 // LoRaMessage contains the decoded LoRa message
@@ -69,11 +115,28 @@ type LoRaMessage struct {
 	FrameContext FrameContext        // Frame context; set for each frame that arrives
 }
 
+// AppEventType is the type of event sent to the applications
+type AppEventType string
+
+// Application event types
+const (
+	UplinkEvent   AppEventType = "up"    // Payload received from the device
+	JoinEvent     AppEventType = "join"  // The device has joined the network
+	AckEvent      AppEventType = "ack"   // The device has acknowledged a downlink
+	DownlinkEvent AppEventType = "txack" // The gateway has sent a downlink to the device
+)
+
 // PayloadMessage contains the decrypted and verified payload
 type PayloadMessage struct {
-	Payload      []byte                // Unencrypted from the PHYPayload struct
-	Device       model.Device          // The device that the payload was received from (or will be sent to)
-	Application  model.Application     // The device's application.
-	MACCommands  []protocol.MACCommand // MAC Commands received from/sent to the device
-	FrameContext FrameContext          // The context the packet is received in
+	Type         AppEventType            // The event type
+	Payload      []byte                  // Unencrypted from the PHYPayload struct
+	FPort        uint8                   // Port for the payload
+	FCnt         uint32                  // Frame counter for the uplink
+	Device       model.Device            // The device that the payload was received from (or will be sent to)
+	Application  model.Application       // The device's application.
+	MACCommands  []protocol.MACCommand   // MAC Commands received from/sent to the device
+	FrameContext FrameContext            // The context the packet is received in
+	Downlink     model.DownstreamMessage // The scheduled downlink. Only set for downlink events
+	Object       *structpb.Struct        // Payload decoded by the application decoder. Only set for uplink events
+	CodecError   string                  // Set if the application decoder failed
 }
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultReceptionExpiry is the default time received frames are kept in the
+// ReceptionTracker. Copies of a closed frame that arrive before the frame
+// expires are dropped.
+const DefaultReceptionExpiry = 5 * time.Second
+
+// receptionList is the list of gateway packets received for a single frame
+type receptionList struct {
+	firstSeen time.Time
+	closed    bool
+	packets   []GatewayPacket
+}
+
+// ReceptionTracker keeps track of all of the gateways that have received an
+// uplink frame. Frames are identified by their raw bytes since every copy of
+// a frame is identical regardless of which gateway received it. Entries are
+// discarded when they are older than the expiry time. Closed frames are kept
+// until they expire so copies that arrive late aren't treated as new frames.
+type ReceptionTracker struct {
+	frames map[string]*receptionList
+	expiry time.Duration
+	mutex  *sync.Mutex
+}
+
+// NewReceptionTracker creates a new ReceptionTracker instance
+func NewReceptionTracker(expiry time.Duration) ReceptionTracker {
+	return ReceptionTracker{
+		frames: make(map[string]*receptionList),
+		expiry: expiry,
+		mutex:  &sync.Mutex{},
+	}
+}
+
+// Add records a received packet. Returns true if this is the first copy of
+// the frame.
+func (r *ReceptionTracker) Add(pkt GatewayPacket) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	for k, v := range r.frames {
+		if now.Sub(v.firstSeen) > r.expiry {
+			delete(r.frames, k)
+		}
+	}
+
+	key := string(pkt.RawMessage)
+	list, exists := r.frames[key]
+	if !exists {
+		r.frames[key] = &receptionList{firstSeen: now, packets: []GatewayPacket{pkt}}
+		return true
+	}
+	if !list.closed {
+		list.packets = append(list.packets, pkt)
+	}
+	return false
+}
+
+// Receptions returns all received copies of a frame. Copies from the same
+// gateway are only included once.
+func (r *ReceptionTracker) Receptions(rawMessage []byte) []GatewayPacket {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.receptions(rawMessage)
+}
+
+// Close stops collecting copies of the frame and returns all received copies
+// of it. Copies that arrive before the frame expires are dropped by Add. The
+// first copy after the frame has expired is treated as a new frame.
+func (r *ReceptionTracker) Close(rawMessage []byte) []GatewayPacket {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	ret := r.receptions(rawMessage)
+	if list, exists := r.frames[string(rawMessage)]; exists {
+		list.closed = true
+	}
+	return ret
+}
+
+func (r *ReceptionTracker) receptions(rawMessage []byte) []GatewayPacket {
+	list, exists := r.frames[string(rawMessage)]
+	if !exists {
+		return nil
+	}
+	var ret []GatewayPacket
+	seen := make(map[string]bool)
+	for _, v := range list.packets {
+		eui := v.Gateway.GatewayEUI.String()
+		if seen[eui] {
+			continue
+		}
+		seen[eui] = true
+		ret = append(ret, v)
+	}
+	return ret
+}
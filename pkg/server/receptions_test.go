@@ -0,0 +1,65 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceptionTracker(t *testing.T) {
+	assert := require.New(t)
+
+	tracker := NewReceptionTracker(100 * time.Millisecond)
+
+	frameA := []byte{1, 2, 3, 4}
+	frameB := []byte{5, 6, 7, 8}
+	gw1 := protocol.EUIFromInt64(1)
+	gw2 := protocol.EUIFromInt64(2)
+
+	assert.Nil(tracker.Receptions(frameA))
+
+	assert.True(tracker.Add(GatewayPacket{RawMessage: frameA, Gateway: GatewayContext{GatewayEUI: gw1}}))
+	assert.False(tracker.Add(GatewayPacket{RawMessage: frameA, Gateway: GatewayContext{GatewayEUI: gw2}}))
+	// Duplicates from the same gateway are only counted once
+	assert.False(tracker.Add(GatewayPacket{RawMessage: frameA, Gateway: GatewayContext{GatewayEUI: gw2}}))
+	assert.True(tracker.Add(GatewayPacket{RawMessage: frameB, Gateway: GatewayContext{GatewayEUI: gw1}}))
+
+	assert.Len(tracker.Receptions(frameA), 2)
+	assert.Len(tracker.Receptions(frameB), 1)
+
+	assert.Len(tracker.Close(frameB), 1)
+	assert.False(tracker.Add(GatewayPacket{RawMessage: frameB, Gateway: GatewayContext{GatewayEUI: gw2}}), "Late copies of closed frames should be dropped")
+	assert.Len(tracker.Receptions(frameB), 1, "Late copies should not be added")
+
+	time.Sleep(150 * time.Millisecond)
+	assert.True(tracker.Add(GatewayPacket{RawMessage: frameA, Gateway: GatewayContext{GatewayEUI: gw1}}), "Expired frames should be removed")
+	assert.Len(tracker.Receptions(frameA), 1)
+	assert.Nil(tracker.Receptions(frameB))
+	assert.True(tracker.Add(GatewayPacket{RawMessage: frameB, Gateway: GatewayContext{GatewayEUI: gw2}}), "Copies after the expiry are new frames")
+}
+
+func TestFrameContextReceptions(t *testing.T) {
+	assert := require.New(t)
+
+	fc := FrameContext{
+		GatewayContext: GatewayPacket{
+			Gateway: GatewayContext{GatewayEUI: protocol.EUIFromInt64(1), GatewayClock: 100},
+			Radio:   RadioContext{RSSI: -90, SNR: 5, Channel: 2},
+		},
+	}
+	list := fc.GatewayReceptions()
+	assert.Len(list, 1)
+	assert.Equal(protocol.EUIFromInt64(1), list[0].GatewayEUI)
+	assert.Equal(uint32(100), list[0].Timestamp)
+	assert.Equal(uint8(2), list[0].Channel)
+
+	second := fc.GatewayContext
+	second.Gateway.GatewayEUI = protocol.EUIFromInt64(2)
+	second.Gateway.FineTimestamp = 42
+	fc.Receptions = []GatewayPacket{fc.GatewayContext, second}
+	list = fc.GatewayReceptions()
+	assert.Len(list, 2)
+	assert.Equal(int64(42), list[1].FineTimestamp)
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/lab5e/lospan/pkg/lg"
 	"github.com/lab5e/lospan/pkg/model"
@@ -14,6 +15,7 @@ type applicationStatements struct {
 	getStatement       *sql.Stmt // Prepared statement for GetByEUI
 	listStatement      *sql.Stmt // Prepared statement for GetByNetworkEUI
 	deleteStatement    *sql.Stmt // Prepared statement for Delete
+	updateStatement    *sql.Stmt // Prepared statement for Update
 	systemGetStatement *sql.Stmt // Prepared statement for system get
 }
 
@@ -23,6 +25,7 @@ func (a *applicationStatements) Close() {
 	a.getStatement.Close()
 	a.listStatement.Close()
 	a.deleteStatement.Close()
+	a.updateStatement.Close()
 	a.systemGetStatement.Close()
 }
 
@@ -30,8 +33,8 @@ func (a *applicationStatements) prepare(db *sql.DB) error {
 	var err error
 	sqlInsert := `
 		INSERT INTO
-			lora_applications (eui, tag)
-		VALUES ($1, $2)`
+			lora_applications (eui, tag, decoder, encoder)
+		VALUES ($1, $2, $3, $4)`
 	if a.putStatement, err = db.Prepare(sqlInsert); err != nil {
 		return fmt.Errorf("unable to prepare insert statement: %v", err)
 	}
@@ -39,7 +42,9 @@ func (a *applicationStatements) prepare(db *sql.DB) error {
 	sqlSelect := `
 		SELECT
 			a.eui,
-			a.tag
+			a.tag,
+			a.decoder,
+			a.encoder
 		FROM
 			lora_applications a
 		WHERE
@@ -51,7 +56,9 @@ func (a *applicationStatements) prepare(db *sql.DB) error {
 	sqlList := `
 		SELECT
 			a.eui,
-			a.tag
+			a.tag,
+			a.decoder,
+			a.encoder
 		FROM
 			lora_applications a`
 
@@ -67,10 +74,23 @@ func (a *applicationStatements) prepare(db *sql.DB) error {
 		return fmt.Errorf("app:unable to prepare delete statement: %v", err)
 	}
 
+	sqlUpdate := `
+		UPDATE lora_applications
+		SET
+			tag = $2,
+			decoder = $3,
+			encoder = $4
+		WHERE eui = $1`
+	if a.updateStatement, err = db.Prepare(sqlUpdate); err != nil {
+		return fmt.Errorf("app:unable to prepare update statement: %v", err)
+	}
+
 	sqlSystemGet := `
 		SELECT
 			a.eui,
-			a.tag
+			a.tag,
+			a.decoder,
+			a.encoder
 		FROM
 			lora_applications a
 		WHERE
@@ -86,7 +106,7 @@ func (s *Storage) readApplication(rows *sql.Rows) (model.Application, error) {
 	var appEUI int64
 	var err error
 	ret := model.NewApplication()
-	if err = rows.Scan(&appEUI, &ret.Tag); err != nil {
+	if err = rows.Scan(&appEUI, &ret.Tag, &ret.Decoder, &ret.Encoder); err != nil {
 		return ret, err
 	}
 
@@ -139,13 +159,45 @@ func (s *Storage) ListApplications() ([]model.Application, error) {
 // CreateApplication stores an Application instance in the storage backend
 func (s *Storage) CreateApplication(application model.Application) error {
 	return s.doSQLExec(s.appStmt.putStatement, func(st *sql.Stmt) (sql.Result, error) {
-		return st.Exec(application.AppEUI.ToInt64(), application.Tag)
+		return st.Exec(application.AppEUI.ToInt64(), application.Tag, application.Decoder, application.Encoder)
 	})
 }
 
-// DeleteApplication removes the application from the store
-func (s *Storage) DeleteApplication(eui protocol.EUI) error {
-	return s.doSQLExec(s.appStmt.deleteStatement, func(st *sql.Stmt) (sql.Result, error) {
-		return st.Exec(eui.ToInt64())
+// UpdateApplication updates the tag and the payload codec for an application
+func (s *Storage) UpdateApplication(application model.Application) error {
+	return s.doSQLExec(s.appStmt.updateStatement, func(st *sql.Stmt) (sql.Result, error) {
+		return st.Exec(application.AppEUI.ToInt64(), application.Tag, application.Decoder, application.Encoder)
 	})
 }
+
+// DeleteApplication removes the application, its webhooks and their delivery
+// logs from the store
+func (s *Storage) DeleteApplication(eui protocol.EUI) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("unable to start transaction: %v", err)
+	}
+	if _, err := tx.Stmt(s.webhookStmt.deleteAppDeliveriesStatement).Exec(eui.ToInt64()); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("unable to remove webhook deliveries for application %s: %v", eui, err)
+	}
+	if _, err := tx.Stmt(s.webhookStmt.deleteAppStatement).Exec(eui.ToInt64()); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("unable to remove webhooks for application %s: %v", eui, err)
+	}
+	result, err := tx.Stmt(s.appStmt.deleteStatement).Exec(eui.ToInt64())
+	if err != nil {
+		tx.Rollback()
+		if strings.Contains(err.Error(), "violates foreign key constraint") {
+			return ErrDeleteConstraint
+		}
+		return err
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		tx.Rollback()
+		return ErrNotFound
+	}
+	return tx.Commit()
+}
@@ -45,6 +45,14 @@ func TestApplicationStorage(t *testing.T) {
 	assert.NoError(err)
 	assert.Contains(apps, application, "Returned list contains application")
 
+	application.Decoder = `{"value": payload.uint8(0)}`
+	application.Encoder = `packUint8(int(object.value))`
+	assert.NoError(appStorage.UpdateApplication(application))
+	existingApp, err = appStorage.GetApplicationByEUI(application.AppEUI)
+	assert.NoError(err)
+	assert.Equal(application, existingApp)
+	assert.Equal(ErrNotFound, appStorage.UpdateApplication(model.Application{AppEUI: makeRandomEUI()}))
+
 	assert.NoError(appStorage.DeleteApplication(application.AppEUI))
 
 	assert.Error(appStorage.DeleteApplication(application.AppEUI), "Should get error when applications does not exist")
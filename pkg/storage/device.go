@@ -2,6 +2,7 @@ package storage
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	"github.com/lab5e/lospan/pkg/lg"
@@ -15,8 +16,11 @@ type deviceStatements struct {
 	euiStatement         *sql.Stmt
 	nonceStatement       *sql.Stmt
 	appEUIStatement      *sql.Stmt
+	classStatement       *sql.Stmt
+	profileStatement     *sql.Stmt
 	getNonceStatement    *sql.Stmt
 	updateStateStatement *sql.Stmt
+	updateMACStatement   *sql.Stmt
 	deleteStatement      *sql.Stmt
 	updateStatement      *sql.Stmt
 }
@@ -27,8 +31,11 @@ func (d *deviceStatements) Close() {
 	d.euiStatement.Close()
 	d.nonceStatement.Close()
 	d.appEUIStatement.Close()
+	d.classStatement.Close()
+	d.profileStatement.Close()
 	d.getNonceStatement.Close()
 	d.updateStateStatement.Close()
+	d.updateMACStatement.Close()
 	d.deleteStatement.Close()
 	d.updateStatement.Close()
 }
@@ -50,7 +57,29 @@ func (d *deviceStatements) prepare(db *sql.DB) error {
 				fcnt_dn,
 				relaxed_counter,
 				key_warning,
-				tag)
+				tag,
+				data_rate,
+				tx_power,
+				ch_mask,
+				rx1_dr_offset,
+				rx2_data_rate,
+				rx2_frequency,
+				rx1_delay,
+				max_duty_cycle,
+				device_class,
+				ping_period,
+				ping_data_rate,
+				ping_frequency,
+				mac_version,
+				nwk_key,
+				snwksint_key,
+				nwksenc_key,
+				join_nonce,
+				profile_id,
+				channels,
+				beacon_frequency,
+				nfcnt_dn,
+				afcnt_dn)
 		VALUES (
 			$1,
 			$2,
@@ -63,7 +92,29 @@ func (d *deviceStatements) prepare(db *sql.DB) error {
 			$9,
 			$10,
 			$11,
-			$12)`
+			$12,
+			$13,
+			$14,
+			$15,
+			$16,
+			$17,
+			$18,
+			$19,
+			$20,
+			$21,
+			$22,
+			$23,
+			$24,
+			$25,
+			$26,
+			$27,
+			$28,
+			$29,
+			$30,
+			$31,
+			$32,
+			$33,
+			$34)`
 	if d.putStatement, err = db.Prepare(sqlInsert); err != nil {
 		return fmt.Errorf("unable to prepare insert statement: %v", err)
 	}
@@ -81,7 +132,29 @@ func (d *deviceStatements) prepare(db *sql.DB) error {
 			fcnt_dn,
 			relaxed_counter,
 			key_warning,
-			tag
+			tag,
+			data_rate,
+			tx_power,
+			ch_mask,
+			rx1_dr_offset,
+			rx2_data_rate,
+			rx2_frequency,
+			rx1_delay,
+			max_duty_cycle,
+			device_class,
+			ping_period,
+			ping_data_rate,
+			ping_frequency,
+			mac_version,
+			nwk_key,
+			snwksint_key,
+			nwksenc_key,
+			join_nonce,
+			profile_id,
+			channels,
+			beacon_frequency,
+			nfcnt_dn,
+			afcnt_dn
 		FROM
 			lora_devices
 		WHERE
@@ -103,7 +176,29 @@ func (d *deviceStatements) prepare(db *sql.DB) error {
 			fcnt_dn,
 			relaxed_counter,
 			key_warning,
-			tag
+			tag,
+			data_rate,
+			tx_power,
+			ch_mask,
+			rx1_dr_offset,
+			rx2_data_rate,
+			rx2_frequency,
+			rx1_delay,
+			max_duty_cycle,
+			device_class,
+			ping_period,
+			ping_data_rate,
+			ping_frequency,
+			mac_version,
+			nwk_key,
+			snwksint_key,
+			nwksenc_key,
+			join_nonce,
+			profile_id,
+			channels,
+			beacon_frequency,
+			nfcnt_dn,
+			afcnt_dn
 		FROM
 			lora_devices
 		WHERE
@@ -126,7 +221,29 @@ func (d *deviceStatements) prepare(db *sql.DB) error {
 			fcnt_dn,
 			relaxed_counter,
 			key_warning,
-			tag
+			tag,
+			data_rate,
+			tx_power,
+			ch_mask,
+			rx1_dr_offset,
+			rx2_data_rate,
+			rx2_frequency,
+			rx1_delay,
+			max_duty_cycle,
+			device_class,
+			ping_period,
+			ping_data_rate,
+			ping_frequency,
+			mac_version,
+			nwk_key,
+			snwksint_key,
+			nwksenc_key,
+			join_nonce,
+			profile_id,
+			channels,
+			beacon_frequency,
+			nfcnt_dn,
+			afcnt_dn
 		FROM
 			lora_devices
 		WHERE
@@ -136,6 +253,96 @@ func (d *deviceStatements) prepare(db *sql.DB) error {
 		return fmt.Errorf("unable to prepare eui select statement: %v", err)
 	}
 
+	classSelect := `
+		SELECT
+			eui,
+			dev_addr,
+			app_key,
+			apps_key,
+			nwks_key,
+			application_eui,
+			state,
+			fcnt_up,
+			fcnt_dn,
+			relaxed_counter,
+			key_warning,
+			tag,
+			data_rate,
+			tx_power,
+			ch_mask,
+			rx1_dr_offset,
+			rx2_data_rate,
+			rx2_frequency,
+			rx1_delay,
+			max_duty_cycle,
+			device_class,
+			ping_period,
+			ping_data_rate,
+			ping_frequency,
+			mac_version,
+			nwk_key,
+			snwksint_key,
+			nwksenc_key,
+			join_nonce,
+			profile_id,
+			channels,
+			beacon_frequency,
+			nfcnt_dn,
+			afcnt_dn
+		FROM
+			lora_devices
+		WHERE
+			device_class = $1`
+
+	if d.classStatement, err = db.Prepare(classSelect); err != nil {
+		return fmt.Errorf("unable to prepare class select statement: %v", err)
+	}
+
+	profileSelect := `
+		SELECT
+			eui,
+			dev_addr,
+			app_key,
+			apps_key,
+			nwks_key,
+			application_eui,
+			state,
+			fcnt_up,
+			fcnt_dn,
+			relaxed_counter,
+			key_warning,
+			tag,
+			data_rate,
+			tx_power,
+			ch_mask,
+			rx1_dr_offset,
+			rx2_data_rate,
+			rx2_frequency,
+			rx1_delay,
+			max_duty_cycle,
+			device_class,
+			ping_period,
+			ping_data_rate,
+			ping_frequency,
+			mac_version,
+			nwk_key,
+			snwksint_key,
+			nwksenc_key,
+			join_nonce,
+			profile_id,
+			channels,
+			beacon_frequency,
+			nfcnt_dn,
+			afcnt_dn
+		FROM
+			lora_devices
+		WHERE
+			profile_id = $1`
+
+	if d.profileStatement, err = db.Prepare(profileSelect); err != nil {
+		return fmt.Errorf("unable to prepare profile select statement: %v", err)
+	}
+
 	nonceInsert := `INSERT INTO lora_device_nonces (device_eui, nonce) VALUES ($1, $2)`
 	if d.nonceStatement, err = db.Prepare(nonceInsert); err != nil {
 		return fmt.Errorf("unable to prepare nonce insert statement: %v", err)
@@ -146,11 +353,33 @@ func (d *deviceStatements) prepare(db *sql.DB) error {
 		return fmt.Errorf("unable to prepare nonce select statement: %v", err)
 	}
 
-	updateState := `UPDATE lora_devices SET fcnt_dn = $1, fcnt_up = $2, key_warning = $3 WHERE eui = $4`
+	updateState := `UPDATE lora_devices SET fcnt_dn = $1, fcnt_up = $2, key_warning = $3, nfcnt_dn = $4, afcnt_dn = $5 WHERE eui = $6`
 	if d.updateStateStatement, err = db.Prepare(updateState); err != nil {
 		return fmt.Errorf("unable to prepare update state statement: %v", err)
 	}
 
+	updateMAC := `
+		UPDATE
+			lora_devices
+		SET
+			data_rate = $1,
+			tx_power = $2,
+			ch_mask = $3,
+			rx1_dr_offset = $4,
+			rx2_data_rate = $5,
+			rx2_frequency = $6,
+			rx1_delay = $7,
+			max_duty_cycle = $8,
+			ping_period = $9,
+			ping_data_rate = $10,
+			ping_frequency = $11,
+			channels = $12,
+			beacon_frequency = $13
+		WHERE eui = $14`
+	if d.updateMACStatement, err = db.Prepare(updateMAC); err != nil {
+		return fmt.Errorf("unable to prepare update MAC state statement: %v", err)
+	}
+
 	delete := `DELETE FROM lora_devices WHERE eui = $1`
 	if d.deleteStatement, err = db.Prepare(delete); err != nil {
 		return fmt.Errorf("unable to prepare delete statement: %v", err)
@@ -169,8 +398,30 @@ func (d *deviceStatements) prepare(db *sql.DB) error {
 			fcnt_dn = $7,
 			relaxed_counter = $8,
 			key_warning = $9,
-			tag = $10
-		WHERE eui = $11`
+			tag = $10,
+			data_rate = $11,
+			tx_power = $12,
+			ch_mask = $13,
+			rx1_dr_offset = $14,
+			rx2_data_rate = $15,
+			rx2_frequency = $16,
+			rx1_delay = $17,
+			max_duty_cycle = $18,
+			device_class = $19,
+			ping_period = $20,
+			ping_data_rate = $21,
+			ping_frequency = $22,
+			mac_version = $23,
+			nwk_key = $24,
+			snwksint_key = $25,
+			nwksenc_key = $26,
+			join_nonce = $27,
+			profile_id = $28,
+			channels = $29,
+			beacon_frequency = $30,
+			nfcnt_dn = $31,
+			afcnt_dn = $32
+		WHERE eui = $33`
 	if d.updateStatement, err = db.Prepare(update); err != nil {
 		return fmt.Errorf("unable to prepare device update statement: %v", err)
 	}
@@ -199,7 +450,7 @@ func (s *Storage) retrieveNonces(device *model.Device) error {
 
 func (s *Storage) readDeviceSansNonce(row *sql.Rows) (model.Device, error) {
 	ret := model.Device{}
-	var devAddrStr, appKeyStr, appSkeyStr, nwkSkeyStr string
+	var devAddrStr, appKeyStr, appSkeyStr, nwkSkeyStr, nwkKeyStr, sNwkSIntKeyStr, nwkSEncKeyStr, channels string
 	var devEUI, appEUI int64
 	var err error
 	if err = row.Scan(
@@ -214,7 +465,29 @@ func (s *Storage) readDeviceSansNonce(row *sql.Rows) (model.Device, error) {
 		&ret.FCntDn,
 		&ret.RelaxedCounter,
 		&ret.KeyWarning,
-		&ret.Tag); err != nil {
+		&ret.Tag,
+		&ret.DataRate,
+		&ret.TXPower,
+		&ret.ChannelMask,
+		&ret.RX1DROffset,
+		&ret.RX2DataRate,
+		&ret.RX2Frequency,
+		&ret.RX1Delay,
+		&ret.MaxDutyCycle,
+		&ret.Class,
+		&ret.PingPeriodicity,
+		&ret.PingDataRate,
+		&ret.PingFrequency,
+		&ret.MACVersion,
+		&nwkKeyStr,
+		&sNwkSIntKeyStr,
+		&nwkSEncKeyStr,
+		&ret.JoinNonce,
+		&ret.ProfileID,
+		&channels,
+		&ret.BeaconFrequency,
+		&ret.NFCntDown,
+		&ret.AFCntDown); err != nil {
 		return ret, err
 	}
 
@@ -234,6 +507,20 @@ func (s *Storage) readDeviceSansNonce(row *sql.Rows) (model.Device, error) {
 	if ret.NwkSKey, err = protocol.AESKeyFromString(nwkSkeyStr); err != nil {
 		return ret, fmt.Errorf("invalid NwkSKey: %v (key=%s)", err, nwkSkeyStr)
 	}
+	if ret.NwkKey, err = protocol.AESKeyFromString(nwkKeyStr); err != nil {
+		return ret, fmt.Errorf("invalid NwkKey: %v (key=%s)", err, nwkKeyStr)
+	}
+	if ret.SNwkSIntKey, err = protocol.AESKeyFromString(sNwkSIntKeyStr); err != nil {
+		return ret, fmt.Errorf("invalid SNwkSIntKey: %v (key=%s)", err, sNwkSIntKeyStr)
+	}
+	if ret.NwkSEncKey, err = protocol.AESKeyFromString(nwkSEncKeyStr); err != nil {
+		return ret, fmt.Errorf("invalid NwkSEncKey: %v (key=%s)", err, nwkSEncKeyStr)
+	}
+	if channels != "" {
+		if err := json.Unmarshal([]byte(channels), &ret.Channels); err != nil {
+			return ret, fmt.Errorf("unable to unmarshal channels for device %s: %v", ret.DeviceEUI, err)
+		}
+	}
 
 	return ret, nil
 }
@@ -302,8 +589,39 @@ func (s *Storage) GetDevicesByApplicationEUI(appEUI protocol.EUI) ([]model.Devic
 	return s.getDeviceList(s.devStmt.appEUIStatement.Query(appEUI.ToInt64()))
 }
 
+// GetDevicesByClass returns all devices with the given device class
+func (s *Storage) GetDevicesByClass(class model.DeviceClass) ([]model.Device, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.getDeviceList(s.devStmt.classStatement.Query(uint8(class)))
+}
+
+// GetDevicesByProfile returns all devices using the given device profile
+func (s *Storage) GetDevicesByProfile(profileID int64) ([]model.Device, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.getDeviceList(s.devStmt.profileStatement.Query(profileID))
+}
+
+// deviceChannels returns the extra channels for the device as a JSON string.
+// Devices without extra channels get an empty string.
+func deviceChannels(device model.Device) (string, error) {
+	if len(device.Channels) == 0 {
+		return "", nil
+	}
+	buf, err := json.Marshal(device.Channels)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal channels: %v", err)
+	}
+	return string(buf), nil
+}
+
 // CreateDevice creates a device in the store
 func (s *Storage) CreateDevice(device model.Device, appEUI protocol.EUI) error {
+	channels, err := deviceChannels(device)
+	if err != nil {
+		return err
+	}
 	return s.doSQLExec(s.devStmt.putStatement, func(st *sql.Stmt) (sql.Result, error) {
 		return st.Exec(device.DeviceEUI.ToInt64(),
 			device.DevAddr.String(),
@@ -316,7 +634,29 @@ func (s *Storage) CreateDevice(device model.Device, appEUI protocol.EUI) error {
 			device.FCntDn,
 			device.RelaxedCounter,
 			device.KeyWarning,
-			device.Tag)
+			device.Tag,
+			device.DataRate,
+			device.TXPower,
+			device.ChannelMask,
+			device.RX1DROffset,
+			device.RX2DataRate,
+			device.RX2Frequency,
+			device.RX1Delay,
+			device.MaxDutyCycle,
+			uint8(device.Class),
+			device.PingPeriodicity,
+			device.PingDataRate,
+			device.PingFrequency,
+			uint8(device.MACVersion),
+			device.NwkKey.String(),
+			device.SNwkSIntKey.String(),
+			device.NwkSEncKey.String(),
+			device.JoinNonce,
+			device.ProfileID,
+			channels,
+			device.BeaconFrequency,
+			device.NFCntDown,
+			device.AFCntDown)
 	})
 }
 
@@ -330,19 +670,67 @@ func (s *Storage) AddDevNonce(device model.Device, nonce uint16) error {
 // UpdateDeviceState updates the device state in the store
 func (s *Storage) UpdateDeviceState(device model.Device) error {
 	return s.doSQLExec(s.devStmt.updateStateStatement, func(st *sql.Stmt) (sql.Result, error) {
-		return st.Exec(device.FCntDn, device.FCntUp, device.KeyWarning, device.DeviceEUI.ToInt64())
+		return st.Exec(device.FCntDn, device.FCntUp, device.KeyWarning, device.NFCntDown, device.AFCntDown, device.DeviceEUI.ToInt64())
 	})
 }
 
-// DeleteDevice removes a device from the store
-func (s *Storage) DeleteDevice(eui protocol.EUI) error {
-	return s.doSQLExec(s.devStmt.deleteStatement, func(st *sql.Stmt) (sql.Result, error) {
-		return st.Exec(eui.ToInt64())
+// UpdateDeviceMACState updates the MAC layer settings (data rate, TX power,
+// channel mask, RX, ping slot and beacon settings and extra channels)
+// negotiated with the device.
+func (s *Storage) UpdateDeviceMACState(device model.Device) error {
+	channels, err := deviceChannels(device)
+	if err != nil {
+		return err
+	}
+	return s.doSQLExec(s.devStmt.updateMACStatement, func(st *sql.Stmt) (sql.Result, error) {
+		return st.Exec(
+			device.DataRate,
+			device.TXPower,
+			device.ChannelMask,
+			device.RX1DROffset,
+			device.RX2DataRate,
+			device.RX2Frequency,
+			device.RX1Delay,
+			device.MaxDutyCycle,
+			device.PingPeriodicity,
+			device.PingDataRate,
+			device.PingFrequency,
+			channels,
+			device.BeaconFrequency,
+			device.DeviceEUI.ToInt64())
 	})
 }
 
+// DeleteDevice removes a device and its statistics from the store
+func (s *Storage) DeleteDevice(eui protocol.EUI) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("unable to start transaction: %v", err)
+	}
+	if _, err := tx.Stmt(s.statsStmt.deleteStatement).Exec(eui.ToInt64()); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("unable to remove stats for device %s: %v", eui, err)
+	}
+	result, err := tx.Stmt(s.devStmt.deleteStatement).Exec(eui.ToInt64())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		tx.Rollback()
+		return ErrNotFound
+	}
+	return tx.Commit()
+}
+
 // UpdateDevice updates the device
 func (s *Storage) UpdateDevice(device model.Device) error {
+	channels, err := deviceChannels(device)
+	if err != nil {
+		return err
+	}
 	return s.doSQLExec(s.devStmt.updateStatement, func(st *sql.Stmt) (sql.Result, error) {
 		return st.Exec(
 			device.DevAddr.String(),
@@ -355,6 +743,28 @@ func (s *Storage) UpdateDevice(device model.Device) error {
 			device.RelaxedCounter,
 			device.KeyWarning,
 			device.Tag,
+			device.DataRate,
+			device.TXPower,
+			device.ChannelMask,
+			device.RX1DROffset,
+			device.RX2DataRate,
+			device.RX2Frequency,
+			device.RX1Delay,
+			device.MaxDutyCycle,
+			uint8(device.Class),
+			device.PingPeriodicity,
+			device.PingDataRate,
+			device.PingFrequency,
+			uint8(device.MACVersion),
+			device.NwkKey.String(),
+			device.SNwkSIntKey.String(),
+			device.NwkSEncKey.String(),
+			device.JoinNonce,
+			device.ProfileID,
+			channels,
+			device.BeaconFrequency,
+			device.NFCntDown,
+			device.AFCntDown,
 			device.DeviceEUI.ToInt64())
 	})
 }
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"testing"
 
+	"github.com/lab5e/lospan/pkg/band"
 	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/protocol"
 	"github.com/stretchr/testify/require"
@@ -126,23 +127,68 @@ func TestDeviceStorage(t *testing.T) {
 	assert.Equal(deviceC.AppSKey, device.AppSKey)
 	assert.Equal(deviceC.NwkSKey, device.NwkSKey)
 
-	deviceD.FCntDn = 1001
-	deviceD.FCntUp = 2002
+	deviceD.FCntDn = 0x10001
+	deviceD.FCntUp = 0xFFFF0002
 	deviceD.KeyWarning = true
+	deviceD.NFCntDown = 7
+	deviceD.AFCntDown = 0x10003
 	assert.NoError(storage.UpdateDeviceState(deviceD), "State update for device D should work")
 
 	updatedDevice, err := storage.GetDeviceByEUI(deviceD.DeviceEUI)
 	assert.NoError(err, "Retrieve device D should work")
 	assert.Equal(deviceD.FCntDn, updatedDevice.FCntDn)
 	assert.Equal(deviceD.FCntUp, updatedDevice.FCntUp)
+	assert.Equal(deviceD.NFCntDown, updatedDevice.NFCntDown)
+	assert.Equal(deviceD.AFCntDown, updatedDevice.AFCntDown)
 	assert.True(updatedDevice.KeyWarning)
 
+	deviceD.DataRate = 5
+	deviceD.TXPower = 3
+	deviceD.ChannelMask = 0x0007
+	deviceD.RX1DROffset = 1
+	deviceD.RX2DataRate = 3
+	deviceD.RX2Frequency = 869.525
+	deviceD.RX1Delay = 2
+	deviceD.MaxDutyCycle = 7
+	deviceD.PingPeriodicity = 3
+	deviceD.PingDataRate = 3
+	deviceD.PingFrequency = 869.525
+	deviceD.BeaconFrequency = 869.3
+	deviceD.Channels = []band.Channel{{Frequency: 867.1}, {Frequency: 867.3, DownlinkFrequency: 869.525}}
+	assert.NoError(storage.UpdateDeviceMACState(deviceD), "MAC state update for device D should work")
+
+	updatedDevice, err = storage.GetDeviceByEUI(deviceD.DeviceEUI)
+	assert.NoError(err, "Retrieve device D should work")
+	assert.Equal(deviceD.DataRate, updatedDevice.DataRate)
+	assert.Equal(deviceD.TXPower, updatedDevice.TXPower)
+	assert.Equal(deviceD.ChannelMask, updatedDevice.ChannelMask)
+	assert.Equal(deviceD.RX1DROffset, updatedDevice.RX1DROffset)
+	assert.Equal(deviceD.RX2DataRate, updatedDevice.RX2DataRate)
+	assert.Equal(deviceD.RX2Frequency, updatedDevice.RX2Frequency)
+	assert.Equal(deviceD.RX1Delay, updatedDevice.RX1Delay)
+	assert.Equal(deviceD.MaxDutyCycle, updatedDevice.MaxDutyCycle)
+	assert.Equal(deviceD.PingPeriodicity, updatedDevice.PingPeriodicity)
+	assert.Equal(deviceD.PingDataRate, updatedDevice.PingDataRate)
+	assert.Equal(deviceD.PingFrequency, updatedDevice.PingFrequency)
+	assert.Equal(deviceD.BeaconFrequency, updatedDevice.BeaconFrequency)
+	assert.Equal(deviceD.Channels, updatedDevice.Channels)
+
 	updatedDevice.DevAddr = protocol.DevAddrFromUint32(0x01020304)
 	updatedDevice.RelaxedCounter = true
 	updatedDevice.FCntDn = 99
 	updatedDevice.FCntUp = 100
 	updatedDevice.AppSKey, _ = protocol.AESKeyFromString("aaaa bbbb cccc dddd eeee ffff 0000 1111")
 	updatedDevice.NwkSKey, _ = protocol.AESKeyFromString("1111 bbbb 2222 dddd eeee ffff 0000 1111")
+	updatedDevice.DataRate = 2
+	updatedDevice.RX2Frequency = 868.1
+	updatedDevice.Class = model.ClassC
+	updatedDevice.MACVersion = model.LoRaWAN11
+	updatedDevice.NwkKey = makeRandomKey()
+	updatedDevice.SNwkSIntKey = makeRandomKey()
+	updatedDevice.NwkSEncKey = makeRandomKey()
+	updatedDevice.JoinNonce = 0x123456
+	updatedDevice.ProfileID = 7
+	updatedDevice.Channels = []band.Channel{{Frequency: 867.5}}
 
 	assert.NoError(storage.UpdateDevice(updatedDevice), "Expect no error when updating device with keys and counters")
 
@@ -150,8 +196,25 @@ func TestDeviceStorage(t *testing.T) {
 	assert.NoError(err)
 	assert.Equal(updatedDevice, newDevice)
 
-	// Delete the devices, then delete application and network
+	classC, err := storage.GetDevicesByClass(model.ClassC)
+	assert.NoError(err)
+	assert.Equal([]model.Device{newDevice}, classC)
+
+	classA, err := storage.GetDevicesByClass(model.ClassA)
+	assert.NoError(err)
+	assert.Len(classA, 3)
+
+	profile7, err := storage.GetDevicesByProfile(7)
+	assert.NoError(err)
+	assert.Equal([]model.Device{newDevice}, profile7)
+
+	// Delete the devices, then delete application and network. The stats are
+	// removed with the device.
+	assert.NoError(storage.AddDeviceStats(model.DeviceStats{DeviceEUI: deviceA.DeviceEUI, Received: 1}))
 	assert.NoError(storage.DeleteDevice(deviceA.DeviceEUI))
+	var count int
+	assert.NoError(storage.db.QueryRow("SELECT COUNT(*) FROM lora_device_stats WHERE device_eui = $1", deviceA.DeviceEUI.ToInt64()).Scan(&count))
+	assert.Equal(0, count)
 	assert.NoError(storage.DeleteDevice(deviceB.DeviceEUI))
 	assert.NoError(storage.DeleteDevice(deviceC.DeviceEUI))
 	assert.NoError(storage.DeleteDevice(deviceD.DeviceEUI))
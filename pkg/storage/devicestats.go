@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+)
+
+type deviceStatsStatements struct {
+	addStatement    *sql.Stmt // Prepared statement for adding to the counters
+	getStatement    *sql.Stmt // Prepared statement for select
+	deleteStatement *sql.Stmt // Prepared statement for removing the stats for a device
+}
+
+func (d *deviceStatsStatements) Close() {
+	d.addStatement.Close()
+	d.getStatement.Close()
+	d.deleteStatement.Close()
+}
+
+func (d *deviceStatsStatements) prepare(db *sql.DB) error {
+	var err error
+	sqlAdd := `
+		INSERT INTO lora_device_stats (
+			device_eui,
+			received,
+			lost,
+			replayed,
+			mic_failed,
+			rejected,
+			last_updated)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (device_eui) DO UPDATE SET
+			received = received + excluded.received,
+			lost = lost + excluded.lost,
+			replayed = replayed + excluded.replayed,
+			mic_failed = mic_failed + excluded.mic_failed,
+			rejected = rejected + excluded.rejected,
+			last_updated = excluded.last_updated`
+	if d.addStatement, err = db.Prepare(sqlAdd); err != nil {
+		return fmt.Errorf("unable to prepare add statement: %v", err)
+	}
+
+	sqlSelect := `
+		SELECT
+			received,
+			lost,
+			replayed,
+			mic_failed,
+			rejected,
+			last_updated
+		FROM
+			lora_device_stats
+		WHERE
+			device_eui = $1`
+	if d.getStatement, err = db.Prepare(sqlSelect); err != nil {
+		return fmt.Errorf("unable to prepare select statement: %v", err)
+	}
+
+	sqlDelete := `DELETE FROM lora_device_stats WHERE device_eui = $1`
+	if d.deleteStatement, err = db.Prepare(sqlDelete); err != nil {
+		return fmt.Errorf("unable to prepare delete statement: %v", err)
+	}
+	return nil
+}
+
+// AddDeviceStats adds the counters in stats to the stored statistics for the
+// device.
+func (s *Storage) AddDeviceStats(stats model.DeviceStats) error {
+	return s.doSQLExec(s.statsStmt.addStatement, func(st *sql.Stmt) (sql.Result, error) {
+		return st.Exec(
+			stats.DeviceEUI.ToInt64(),
+			stats.Received,
+			stats.Lost,
+			stats.Replayed,
+			stats.MICFailed,
+			stats.Rejected,
+			stats.LastUpdated)
+	})
+}
+
+// GetDeviceStats returns the statistics for a device. Devices without any
+// recorded statistics will get empty statistics.
+func (s *Storage) GetDeviceStats(deviceEUI protocol.EUI) (model.DeviceStats, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ret := model.NewDeviceStats(deviceEUI)
+	err := s.statsStmt.getStatement.QueryRow(deviceEUI.ToInt64()).Scan(
+		&ret.Received,
+		&ret.Lost,
+		&ret.Replayed,
+		&ret.MICFailed,
+		&ret.Rejected,
+		&ret.LastUpdated)
+	if err != nil && err != sql.ErrNoRows {
+		return ret, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceStatsStorage(t *testing.T) {
+	assert := require.New(t)
+
+	s := NewMemoryStorage()
+	defer s.Close()
+
+	eui := protocol.EUIFromInt64(0x0102030405060708)
+	stats, err := s.GetDeviceStats(eui)
+	assert.NoError(err, "Devices without stats should get empty stats")
+	assert.Equal(model.NewDeviceStats(eui), stats)
+
+	assert.NoError(s.AddDeviceStats(model.DeviceStats{DeviceEUI: eui, Received: 1, Lost: 2, LastUpdated: 1}))
+	assert.NoError(s.AddDeviceStats(model.DeviceStats{DeviceEUI: eui, Received: 1, Replayed: 3, MICFailed: 4, Rejected: 5, LastUpdated: 2}))
+
+	stats, err = s.GetDeviceStats(eui)
+	assert.NoError(err)
+	assert.Equal(model.DeviceStats{DeviceEUI: eui, Received: 2, Lost: 2, Replayed: 3, MICFailed: 4, Rejected: 5, LastUpdated: 2}, stats)
+}
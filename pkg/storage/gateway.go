@@ -1,12 +1,14 @@
 package storage
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"database/sql"
 
 	"net"
 
+	"github.com/lab5e/lospan/pkg/band"
 	"github.com/lab5e/lospan/pkg/lg"
 	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/protocol"
@@ -19,6 +21,8 @@ type gatewayStatements struct {
 	getStatement    *sql.Stmt // Prepare statement for select
 	getSysStatement *sql.Stmt // Prepare statement for system get (ie all gateways)
 	updateStatement *sql.Stmt // Prepare statement for gatway update
+	seenStatement   *sql.Stmt // Prepare statement for last seen update
+	statsStatement  *sql.Stmt // Prepare statement for stats update
 }
 
 func (g *gatewayStatements) Close() {
@@ -28,6 +32,8 @@ func (g *gatewayStatements) Close() {
 	g.getStatement.Close()
 	g.getSysStatement.Close()
 	g.updateStatement.Close()
+	g.seenStatement.Close()
+	g.statsStatement.Close()
 }
 
 func (g *gatewayStatements) prepare(db *sql.DB) error {
@@ -39,7 +45,21 @@ func (g *gatewayStatements) prepare(db *sql.DB) error {
 			longitude,
 			altitude,
 			ip,
-			strict_ip
+			strict_ip,
+			last_seen,
+			stat_time,
+			stat_lat,
+			stat_lon,
+			stat_alt,
+			rxnb,
+			rxok,
+			rxfw,
+			ackr,
+			dwnb,
+			txnb,
+			stat_updated,
+			frequency_plan,
+			channel_config
 		FROM
 			lora_gateways`
 
@@ -54,8 +74,10 @@ func (g *gatewayStatements) prepare(db *sql.DB) error {
 			longitude,
 			altitude,
 			ip,
-			strict_ip)
-		VALUES ($1, $2, $3, $4, $5, $6)`
+			strict_ip,
+			frequency_plan,
+			channel_config)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 	if g.putStatement, err = db.Prepare(sqlInsert); err != nil {
 		return fmt.Errorf("unable to prepare insert statement: %v", err)
 	}
@@ -76,7 +98,21 @@ func (g *gatewayStatements) prepare(db *sql.DB) error {
 			gw.longitude,
 			gw.altitude,
 			gw.ip,
-			gw.strict_ip
+			gw.strict_ip,
+			gw.last_seen,
+			gw.stat_time,
+			gw.stat_lat,
+			gw.stat_lon,
+			gw.stat_alt,
+			gw.rxnb,
+			gw.rxok,
+			gw.rxfw,
+			gw.ackr,
+			gw.dwnb,
+			gw.txnb,
+			gw.stat_updated,
+			gw.frequency_plan,
+			gw.channel_config
 		FROM
 			lora_gateways gw
 		WHERE
@@ -92,7 +128,21 @@ func (g *gatewayStatements) prepare(db *sql.DB) error {
 			gw.longitude,
 			gw.altitude,
 			gw.ip,
-			gw.strict_ip
+			gw.strict_ip,
+			gw.last_seen,
+			gw.stat_time,
+			gw.stat_lat,
+			gw.stat_lon,
+			gw.stat_alt,
+			gw.rxnb,
+			gw.rxok,
+			gw.rxfw,
+			gw.ackr,
+			gw.dwnb,
+			gw.txnb,
+			gw.stat_updated,
+			gw.frequency_plan,
+			gw.channel_config
 		FROM
 			lora_gateways gw
 		WHERE
@@ -105,29 +155,80 @@ func (g *gatewayStatements) prepare(db *sql.DB) error {
 		UPDATE
 			lora_gateways 
 		SET
-			latitude = $1, longitude = $2, altitude = $3, ip = $4, strict_ip = $5
+			latitude = $1, longitude = $2, altitude = $3, ip = $4, strict_ip = $5,
+			frequency_plan = $6, channel_config = $7
 		WHERE
-			gateway_eui = $6
+			gateway_eui = $8
 	`
 	if g.updateStatement, err = db.Prepare(updateStatement); err != nil {
 		return fmt.Errorf("unable to prepare update statement: %v", err)
 	}
 
+	seenStatement := `
+		UPDATE
+			lora_gateways
+		SET
+			last_seen = $1
+		WHERE
+			gateway_eui = $2
+	`
+	if g.seenStatement, err = db.Prepare(seenStatement); err != nil {
+		return fmt.Errorf("unable to prepare last seen statement: %v", err)
+	}
+
+	statsStatement := `
+		UPDATE
+			lora_gateways
+		SET
+			stat_time = $1, stat_lat = $2, stat_lon = $3, stat_alt = $4, rxnb = $5, rxok = $6,
+			rxfw = $7, ackr = $8, dwnb = $9, txnb = $10, stat_updated = $11
+		WHERE
+			gateway_eui = $12
+	`
+	if g.statsStatement, err = db.Prepare(statsStatement); err != nil {
+		return fmt.Errorf("unable to prepare stats statement: %v", err)
+	}
+
 	return nil
 }
 
 func (s *Storage) readGateway(rows *sql.Rows) (model.Gateway, error) {
 	var eui int64
-	var ipStr string
+	var ipStr, plan, channels string
 	gw := model.NewGateway()
-	if err := rows.Scan(&eui, &gw.Latitude, &gw.Longitude, &gw.Altitude, &ipStr, &gw.StrictIP); err != nil {
+	if err := rows.Scan(&eui, &gw.Latitude, &gw.Longitude, &gw.Altitude, &ipStr, &gw.StrictIP,
+		&gw.LastSeen, &gw.Stats.Time, &gw.Stats.Latitude, &gw.Stats.Longitude, &gw.Stats.Altitude,
+		&gw.Stats.RxReceived, &gw.Stats.RxOK, &gw.Stats.RxForwarded, &gw.Stats.AckRatio,
+		&gw.Stats.DownReceived, &gw.Stats.TxEmitted, &gw.Stats.Updated, &plan, &channels); err != nil {
 		return gw, err
 	}
 	gw.GatewayEUI = protocol.EUIFromInt64(eui)
 	gw.IP = net.ParseIP(ipStr)
+	var err error
+	if gw.Plan, err = band.ParseBand(plan); err != nil {
+		return gw, err
+	}
+	if channels != "" {
+		if err := json.Unmarshal([]byte(channels), &gw.Channels); err != nil {
+			return gw, fmt.Errorf("unable to unmarshal channel config for gateway %s: %v", gw.GatewayEUI, err)
+		}
+	}
 	return gw, nil
 }
 
+// channelConfig returns the channel configuration for the gateway as a JSON
+// string. Gateways with the default configuration get an empty string.
+func channelConfig(gateway model.Gateway) (string, error) {
+	if gateway.Channels.Empty() {
+		return "", nil
+	}
+	buf, err := json.Marshal(gateway.Channels)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal channel config: %v", err)
+	}
+	return string(buf), nil
+}
+
 func (s *Storage) getGwList(rows *sql.Rows, err error) ([]model.Gateway, error) {
 	if err != nil {
 		return nil, err
@@ -183,6 +284,10 @@ func (s *Storage) GetGateway(eui protocol.EUI) (model.Gateway, error) {
 
 // CreateGateway creates a new gateway in the store
 func (s *Storage) CreateGateway(gateway model.Gateway) error {
+	channels, err := channelConfig(gateway)
+	if err != nil {
+		return err
+	}
 	return s.doSQLExec(s.gwStmt.putStatement, func(st *sql.Stmt) (sql.Result, error) {
 		return st.Exec(
 			gateway.GatewayEUI.ToInt64(),
@@ -190,7 +295,9 @@ func (s *Storage) CreateGateway(gateway model.Gateway) error {
 			gateway.Longitude,
 			gateway.Altitude,
 			gateway.IP.String(),
-			gateway.StrictIP)
+			gateway.StrictIP,
+			gateway.Plan.String(),
+			channels)
 	})
 }
 
@@ -203,8 +310,48 @@ func (s *Storage) DeleteGateway(eui protocol.EUI) error {
 
 // UpdateGateway updates a gateway in the store
 func (s *Storage) UpdateGateway(gateway model.Gateway) error {
+	channels, err := channelConfig(gateway)
+	if err != nil {
+		return err
+	}
 	return s.doSQLExec(s.gwStmt.updateStatement, func(st *sql.Stmt) (sql.Result, error) {
 		return st.Exec(gateway.Latitude, gateway.Longitude, gateway.Altitude,
-			gateway.IP.String(), gateway.StrictIP, gateway.GatewayEUI.ToInt64())
+			gateway.IP.String(), gateway.StrictIP, gateway.Plan.String(), channels,
+			gateway.GatewayEUI.ToInt64())
+	})
+}
+
+// UpdateGatewayLastSeen sets the time the gateway was last seen
+func (s *Storage) UpdateGatewayLastSeen(eui protocol.EUI, lastSeen int64) error {
+	return s.doSQLExec(s.gwStmt.seenStatement, func(st *sql.Stmt) (sql.Result, error) {
+		return st.Exec(lastSeen, eui.ToInt64())
+	})
+}
+
+// UpdateGatewaysLastSeen sets the time the gateways were last seen in a
+// single transaction. Unknown gateways are ignored.
+func (s *Storage) UpdateGatewaysLastSeen(lastSeen map[protocol.EUI]int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("unable to start transaction: %v", err)
+	}
+	stmt := tx.Stmt(s.gwStmt.seenStatement)
+	for eui, seen := range lastSeen {
+		if _, err := stmt.Exec(seen, eui.ToInt64()); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("unable to update last seen time for gateway %s: %v", eui, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// UpdateGatewayStats stores the latest status report from the gateway
+func (s *Storage) UpdateGatewayStats(eui protocol.EUI, stats model.GatewayStats) error {
+	return s.doSQLExec(s.gwStmt.statsStatement, func(st *sql.Stmt) (sql.Result, error) {
+		return st.Exec(stats.Time, stats.Latitude, stats.Longitude, stats.Altitude,
+			stats.RxReceived, stats.RxOK, stats.RxForwarded, stats.AckRatio,
+			stats.DownReceived, stats.TxEmitted, stats.Updated, eui.ToInt64())
 	})
 }
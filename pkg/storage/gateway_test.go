@@ -5,6 +5,7 @@ import (
 	"net"
 	"testing"
 
+	"github.com/lab5e/lospan/pkg/band"
 	"github.com/lab5e/lospan/pkg/model"
 	"github.com/lab5e/lospan/pkg/protocol"
 	"github.com/stretchr/testify/require"
@@ -51,6 +52,8 @@ func TestGatewayStorage(t *testing.T) {
 		Latitude:   -63.0,
 		Longitude:  -10.0,
 		Altitude:   0.0,
+		Plan:       band.US915Band,
+		Channels:   model.DefaultConcentratorConfig(band.US915Band),
 	}
 
 	assert.NoError(gwStorage.CreateGateway(gateway2), "Gateway 1 should be stored")
@@ -83,12 +86,45 @@ func TestGatewayStorage(t *testing.T) {
 	gateway1.Longitude = 333
 	gateway1.IP = net.ParseIP("10.10.10.10")
 	gateway1.StrictIP = true
+	gateway1.Plan = band.AS923Group2Band
+	gateway1.Channels = model.ConcentratorConfig{
+		Radios:  []float32{921.9},
+		MultiSF: []model.ConcentratorChannel{{Enabled: true, Radio: 0, IF: -500000}, {Enabled: true, Radio: 0, IF: -300000}},
+	}
 	assert.NoError(gwStorage.UpdateGateway(gateway1), "Should update gateway")
 
 	updatedGW, err := gwStorage.GetGateway(gateway1.GatewayEUI)
 	assert.NoError(err)
 	assert.Equal(gateway1, updatedGW)
 
+	// Liveness and stats are updated separately from the gateway configuration
+	gateway1.LastSeen = 1234
+	gateway1.Stats = model.GatewayStats{
+		Time:         "2014-01-12 08:59:28 GMT",
+		Latitude:     46.24,
+		Longitude:    3.25,
+		Altitude:     145,
+		RxReceived:   2,
+		RxOK:         2,
+		RxForwarded:  2,
+		AckRatio:     100,
+		DownReceived: 2,
+		TxEmitted:    2,
+		Updated:      5678,
+	}
+	assert.NoError(gwStorage.UpdateGatewayLastSeen(gateway1.GatewayEUI, gateway1.LastSeen))
+	assert.NoError(gwStorage.UpdateGatewayStats(gateway1.GatewayEUI, gateway1.Stats))
+	assert.Equal(ErrNotFound, gwStorage.UpdateGatewayLastSeen(nonEUI, 1))
+	assert.NoError(gwStorage.UpdateGatewaysLastSeen(map[protocol.EUI]int64{nonEUI: 1, gateway2.GatewayEUI: 4321}))
+	gateway2.LastSeen = 4321
+
+	updatedGW, err = gwStorage.GetGateway(gateway1.GatewayEUI)
+	assert.NoError(err)
+	assert.Equal(gateway1, updatedGW)
+	updatedGW, err = gwStorage.GetGateway(gateway2.GatewayEUI)
+	assert.NoError(err)
+	assert.Equal(gateway2.LastSeen, updatedGW.LastSeen)
+
 	// Remove both
 	assert.NoError(gwStorage.DeleteGateway(gateway1.GatewayEUI))
 	assert.NoError(gwStorage.DeleteGateway(gateway2.GatewayEUI))
@@ -14,6 +14,8 @@ import (
 type dataStatements struct {
 	createUpstream       *sql.Stmt
 	listUpstream         *sql.Stmt
+	createReception      *sql.Stmt
+	listReceptions       *sql.Stmt
 	createDownstream     *sql.Stmt
 	deleteDownstream     *sql.Stmt
 	listDownstream       *sql.Stmt
@@ -24,6 +26,8 @@ type dataStatements struct {
 func (d *dataStatements) Close() {
 	d.createUpstream.Close()
 	d.listUpstream.Close()
+	d.createReception.Close()
+	d.listReceptions.Close()
 	d.createDownstream.Close()
 	d.deleteDownstream.Close()
 	d.listDownstream.Close()
@@ -44,8 +48,9 @@ func (d *dataStatements) prepare(db *sql.DB) error {
 				snr,
 				frequency,
 				data_rate,
-				dev_addr)
-		VALUES ($1,	$2,	$3, $4, $5, $6, $7, $8, $9)`); err != nil {
+				dev_addr,
+				port)
+		VALUES ($1,	$2,	$3, $4, $5, $6, $7, $8, $9, $10)`); err != nil {
 		return fmt.Errorf("unable to prepare insert statement: %v", err)
 	}
 
@@ -59,7 +64,8 @@ func (d *dataStatements) prepare(db *sql.DB) error {
 			snr,
 			frequency,
 			data_rate,
-			dev_addr
+			dev_addr,
+			port
 		FROM
 			lora_upstream_messages
 		WHERE
@@ -70,6 +76,40 @@ func (d *dataStatements) prepare(db *sql.DB) error {
 		return fmt.Errorf("unable to prepare list statement: %v", err)
 	}
 
+	if d.createReception, err = db.Prepare(`
+		INSERT INTO
+			lora_upstream_receptions (
+				device_eui,
+				time_stamp,
+				gateway_eui,
+				rssi,
+				snr,
+				channel,
+				rf_chain,
+				gw_timestamp,
+				fine_timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`); err != nil {
+		return fmt.Errorf("unable to prepare reception insert statement: %v", err)
+	}
+
+	if d.listReceptions, err = db.Prepare(`
+		SELECT
+			gateway_eui,
+			rssi,
+			snr,
+			channel,
+			rf_chain,
+			gw_timestamp,
+			fine_timestamp
+		FROM
+			lora_upstream_receptions
+		WHERE
+			device_eui = $1 AND time_stamp = $2
+		ORDER BY
+			snr DESC`); err != nil {
+		return fmt.Errorf("unable to prepare reception list statement: %v", err)
+	}
+
 	if d.createDownstream, err = db.Prepare(`
 		INSERT INTO lora_downstream_messages (
 			device_eui,
@@ -142,20 +182,66 @@ func (d *dataStatements) prepare(db *sql.DB) error {
 	return nil
 }
 
-// CreateUpstreamMessage stores a new data element in the backend. The element is associated with the specified DevAddr
+// CreateUpstreamMessage stores a new data element in the backend. The element is associated with the specified DevAddr.
+// The message and the gateway receptions are written in a single transaction.
 func (s *Storage) CreateUpstreamMessage(deviceEUI protocol.EUI, data model.UpstreamMessage) error {
-	return s.doSQLExec(s.dataStmt.createUpstream, func(st *sql.Stmt) (sql.Result, error) {
-		b64str := base64.StdEncoding.EncodeToString(data.Data)
-		return st.Exec(deviceEUI.ToInt64(),
-			b64str,
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("unable to start transaction: %v", err)
+	}
+	b64str := base64.StdEncoding.EncodeToString(data.Data)
+	if _, err := tx.Stmt(s.dataStmt.createUpstream).Exec(deviceEUI.ToInt64(),
+		b64str,
+		data.Timestamp,
+		data.GatewayEUI.String(),
+		data.RSSI,
+		data.SNR,
+		data.Frequency,
+		data.DataRate,
+		data.DevAddr.String(),
+		data.FPort); err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, r := range data.Receptions {
+		if _, err := tx.Stmt(s.dataStmt.createReception).Exec(deviceEUI.ToInt64(),
 			data.Timestamp,
-			data.GatewayEUI.String(),
-			data.RSSI,
-			data.SNR,
-			data.Frequency,
-			data.DataRate,
-			data.DevAddr.String())
-	})
+			r.GatewayEUI.ToInt64(),
+			r.RSSI,
+			r.SNR,
+			r.Channel,
+			r.RFChain,
+			r.Timestamp,
+			r.FineTimestamp); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("unable to store reception from gateway %s: %v", r.GatewayEUI, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Read the gateway receptions for a message. The storage mutex must be held
+// by the caller.
+func (s *Storage) retrieveReceptions(msg *model.UpstreamMessage) error {
+	rows, err := s.dataStmt.listReceptions.Query(msg.DeviceEUI.ToInt64(), msg.Timestamp)
+	if err != nil {
+		return fmt.Errorf("unable to query receptions: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r := model.GatewayReception{}
+		var gwEUI int64
+		if err := rows.Scan(&gwEUI, &r.RSSI, &r.SNR, &r.Channel, &r.RFChain, &r.Timestamp, &r.FineTimestamp); err != nil {
+			lg.Warning("Unable to read reception for message from device with EUI %s: %v", msg.DeviceEUI, err)
+			continue
+		}
+		r.GatewayEUI = protocol.EUIFromInt64(gwEUI)
+		msg.Receptions = append(msg.Receptions, r)
+	}
+	return nil
 }
 
 // Decode a single row into a DeviceData instance.
@@ -164,7 +250,7 @@ func (s *Storage) readData(rows *sql.Rows) (model.UpstreamMessage, error) {
 	var err error
 	var dataStr, gwEUI, devAddr string
 	var devEUI int64
-	if err = rows.Scan(&devEUI, &dataStr, &ret.Timestamp, &gwEUI, &ret.RSSI, &ret.SNR, &ret.Frequency, &ret.DataRate, &devAddr); err != nil {
+	if err = rows.Scan(&devEUI, &dataStr, &ret.Timestamp, &gwEUI, &ret.RSSI, &ret.SNR, &ret.Frequency, &ret.DataRate, &devAddr, &ret.FPort); err != nil {
 		return ret, err
 	}
 	ret.DeviceEUI = protocol.EUIFromInt64(devEUI)
@@ -188,16 +274,21 @@ func (s *Storage) doQuery(stmt *sql.Stmt, eui protocol.EUI, limit int) ([]model.
 		return nil, fmt.Errorf("unable to query device data for device with EUI %s: %v", eui, err)
 	}
 	var ret []model.UpstreamMessage
-	defer rows.Close()
 	for rows.Next() {
 		data, err := s.readData(rows)
 		if err != nil {
 			lg.Warning("Unable to decode data for device with EUI %s: %v", eui, err)
+			rows.Close()
 			return ret, err
 		}
 		ret = append(ret, data)
 	}
-
+	rows.Close()
+	for i := range ret {
+		if err := s.retrieveReceptions(&ret[i]); err != nil {
+			return ret, err
+		}
+	}
 	return ret, nil
 }
 
@@ -275,7 +366,7 @@ func (s *Storage) GetNextUnsentMessage(deviceEUI protocol.EUI) (model.Downstream
 }
 
 // SetMessageSentTime sets the sent time and frame counter fields for a message in the store.
-func (s *Storage) SetMessageSentTime(deviceEUI protocol.EUI, createdTime int64, sentTime int64, frameCounterUp uint16) error {
+func (s *Storage) SetMessageSentTime(deviceEUI protocol.EUI, createdTime int64, sentTime int64, frameCounterUp uint32) error {
 	res, err := s.db.Exec(`
 		UPDATE 
 			lora_downstream_messages
@@ -299,7 +390,7 @@ func (s *Storage) SetMessageSentTime(deviceEUI protocol.EUI, createdTime int64,
 }
 
 // UpdateMessageAckTime sets the ack time field in the store.
-func (s *Storage) UpdateMessageAckTime(deviceEUI protocol.EUI, frameCounterUp uint16, ackTime int64) error {
+func (s *Storage) UpdateMessageAckTime(deviceEUI protocol.EUI, frameCounterUp uint32, ackTime int64) error {
 	res, err := s.db.Exec(`
 		UPDATE 
 			lora_downstream_messages
@@ -68,6 +68,30 @@ func TestUpstreamStorage(t *testing.T) {
 	assert.NoError(err, "No device => no error (and no data)")
 	assert.Len(data, 0)
 
+	// Messages received by several gateways keep all of the receptions
+	deviceData3 := model.UpstreamMessage{Timestamp: 3, Data: data1, DeviceEUI: device.DeviceEUI, Frequency: 3.0, FPort: 42,
+		Receptions: []model.GatewayReception{
+			{GatewayEUI: makeRandomEUI(), RSSI: -80, SNR: 7.5, Channel: 1, RFChain: 0, Timestamp: 1000, FineTimestamp: 123456},
+			{GatewayEUI: makeRandomEUI(), RSSI: -110, SNR: -3.25, Channel: 2, RFChain: 1, Timestamp: 2000},
+		}}
+	assert.NoError(storage.CreateUpstreamMessage(device.DeviceEUI, deviceData3), "Message 3 stored successfully")
+	data, err = storage.ListUpstreamMessages(device.DeviceEUI, 1)
+	assert.NoError(err)
+	assert.Len(data, 1)
+	assert.Equal(deviceData3, data[0])
+
+	var eui int64
+	assert.NoError(storage.db.QueryRow("SELECT gateway_eui FROM lora_upstream_receptions WHERE fine_timestamp = 123456").Scan(&eui))
+	assert.Equal(deviceData3.Receptions[0].GatewayEUI.ToInt64(), eui, "Gateway EUI should be stored as an integer")
+
+	// The message isn't stored if one of the receptions fails
+	deviceData4 := deviceData3
+	deviceData4.Timestamp = 4
+	deviceData4.Receptions = []model.GatewayReception{deviceData3.Receptions[0], deviceData3.Receptions[0]}
+	assert.Error(storage.CreateUpstreamMessage(device.DeviceEUI, deviceData4))
+	data, err = storage.ListUpstreamMessages(device.DeviceEUI, 1)
+	assert.NoError(err)
+	assert.Equal(int64(3), data[0].Timestamp, "Message 4 should be rolled back")
 }
 
 func TestDownstreamStorage(t *testing.T) {
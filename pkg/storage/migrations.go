@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// emptyKey is the default for the key columns added to existing devices
+const emptyKey = "'00000000000000000000000000000000'"
+
+// addedColumn is a column that has been added to a table after the table was
+// first created. CREATE TABLE IF NOT EXISTS leaves existing tables as they are
+// so the column is added with ALTER TABLE if it is missing. The definition
+// must have a default value if the column is NOT NULL.
+type addedColumn struct {
+	table      string
+	column     string
+	definition string
+}
+
+// addedColumns is the list of columns added to the tables. New columns are
+// appended to the list. The definitions must match the schema.
+var addedColumns = []addedColumn{
+	{"lora_applications", "decoder", "TEXT NOT NULL DEFAULT ''"},
+	{"lora_applications", "encoder", "TEXT NOT NULL DEFAULT ''"},
+
+	{"lora_devices", "data_rate", "SMALLINT NOT NULL DEFAULT 0"},
+	{"lora_devices", "tx_power", "SMALLINT NOT NULL DEFAULT 0"},
+	{"lora_devices", "ch_mask", "INTEGER NOT NULL DEFAULT 0"},
+	{"lora_devices", "rx1_dr_offset", "SMALLINT NOT NULL DEFAULT 0"},
+	{"lora_devices", "rx2_data_rate", "SMALLINT NOT NULL DEFAULT 0"},
+	{"lora_devices", "rx2_frequency", "NUMERIC(6,3) NOT NULL DEFAULT 0"},
+	{"lora_devices", "rx1_delay", "SMALLINT NOT NULL DEFAULT 0"},
+	{"lora_devices", "max_duty_cycle", "SMALLINT NOT NULL DEFAULT 0"},
+	{"lora_devices", "device_class", "SMALLINT NOT NULL DEFAULT 0"},
+	{"lora_devices", "ping_period", "SMALLINT NOT NULL DEFAULT 7"},
+	{"lora_devices", "ping_data_rate", "SMALLINT NOT NULL DEFAULT 0"},
+	{"lora_devices", "ping_frequency", "NUMERIC(6,3) NOT NULL DEFAULT 0"},
+	{"lora_devices", "mac_version", "SMALLINT NOT NULL DEFAULT 0"},
+	{"lora_devices", "nwk_key", "CHAR(32) NOT NULL DEFAULT " + emptyKey},
+	{"lora_devices", "snwksint_key", "CHAR(32) NOT NULL DEFAULT " + emptyKey},
+	{"lora_devices", "nwksenc_key", "CHAR(32) NOT NULL DEFAULT " + emptyKey},
+	{"lora_devices", "join_nonce", "INTEGER NOT NULL DEFAULT 0"},
+	{"lora_devices", "profile_id", "INTEGER NOT NULL DEFAULT 0"},
+	{"lora_devices", "channels", "TEXT NOT NULL DEFAULT ''"},
+	{"lora_devices", "beacon_frequency", "NUMERIC(6,3) NOT NULL DEFAULT 0"},
+	{"lora_devices", "nfcnt_dn", "INTEGER NOT NULL DEFAULT 0"},
+	{"lora_devices", "afcnt_dn", "INTEGER NOT NULL DEFAULT 0"},
+
+	{"lora_upstream_messages", "port", "SMALLINT NOT NULL DEFAULT 0"},
+
+	{"lora_gateways", "last_seen", "BIGINT NOT NULL DEFAULT 0"},
+	{"lora_gateways", "stat_time", "VARCHAR(64) NOT NULL DEFAULT ''"},
+	{"lora_gateways", "stat_lat", "NUMERIC(12,8) NOT NULL DEFAULT 0"},
+	{"lora_gateways", "stat_lon", "NUMERIC(12,8) NOT NULL DEFAULT 0"},
+	{"lora_gateways", "stat_alt", "NUMERIC(8,3) NOT NULL DEFAULT 0"},
+	{"lora_gateways", "rxnb", "INTEGER NOT NULL DEFAULT 0"},
+	{"lora_gateways", "rxok", "INTEGER NOT NULL DEFAULT 0"},
+	{"lora_gateways", "rxfw", "INTEGER NOT NULL DEFAULT 0"},
+	{"lora_gateways", "ackr", "NUMERIC(6,2) NOT NULL DEFAULT 0"},
+	{"lora_gateways", "dwnb", "INTEGER NOT NULL DEFAULT 0"},
+	{"lora_gateways", "txnb", "INTEGER NOT NULL DEFAULT 0"},
+	{"lora_gateways", "stat_updated", "BIGINT NOT NULL DEFAULT 0"},
+	{"lora_gateways", "frequency_plan", "VARCHAR(16) NOT NULL DEFAULT 'EU868'"},
+	{"lora_gateways", "channel_config", "TEXT NOT NULL DEFAULT ''"},
+
+	{"lora_device_stats", "rejected", "INTEGER NOT NULL DEFAULT 0"},
+}
+
+// tableColumns returns the names of the columns in a table
+func tableColumns(db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ret := make(map[string]bool)
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, columnType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &pk); err != nil {
+			return nil, err
+		}
+		ret[name] = true
+	}
+	return ret, rows.Err()
+}
+
+// migrateSchema adds the missing columns to tables created by an earlier
+// version of the schema.
+func migrateSchema(db *sql.DB) error {
+	columns := make(map[string]map[string]bool)
+	for _, c := range addedColumns {
+		if _, ok := columns[c.table]; !ok {
+			existing, err := tableColumns(db, c.table)
+			if err != nil {
+				return fmt.Errorf("unable to read columns for %s: %v", c.table, err)
+			}
+			columns[c.table] = existing
+		}
+		if columns[c.table][c.column] {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)); err != nil {
+			return fmt.Errorf("unable to add column %s to %s: %v", c.column, c.table, err)
+		}
+		columns[c.table][c.column] = true
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/lab5e/lospan/pkg/band"
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+// The tables as they were before columns were added
+const originalSchema = `
+CREATE TABLE lora_applications (
+    eui         BIGINT       NOT NULL,
+    tag         VARCHAR(128) NOT NULL,
+    CONSTRAINT lora_application_pk PRIMARY KEY (eui)
+);
+CREATE TABLE lora_devices (
+    eui             BIGINT       NOT NULL,
+    dev_addr        CHAR(8)      NOT NULL,
+    app_key         CHAR(32)     NOT NULL,
+    apps_key        CHAR(32)     NOT NULL,
+    nwks_key        CHAR(32)     NOT NULL,
+    application_eui BIGINT       NOT NULL REFERENCES lora_application(eui),
+    state           SMALLINT     NOT NULL,
+    fcnt_up         INTEGER      NOT NULL DEFAULT 0,
+    fcnt_dn         INTEGER      NOT NULL DEFAULT 0,
+    relaxed_counter BOOLEAN      NOT NULL DEFAULT false,
+    key_warning     BOOLEAN      NOT NULL DEFAULT false,
+    tag             VARCHAR(128) NOT NULL,
+    CONSTRAINT lora_device_pk PRIMARY KEY (eui)
+);
+CREATE TABLE lora_upstream_messages (
+    device_eui      BIGINT        NOT NULL REFERENCES lora_device (eui) ON DELETE CASCADE,
+    data            VARCHAR(512)  NOT NULL,
+    time_stamp      BIGINT        NOT NULL,
+    gateway_eui     BIGINT        NOT NULL,
+    rssi            INTEGER       NOT NULL,
+    snr             NUMERIC(6,3)  NOT NULL,
+    frequency       NUMERIC(6,3)  NOT NULL,
+    data_rate       VARCHAR(20)   NOT NULL,
+    dev_addr        CHAR(8)       NOT NULL,
+    CONSTRAINT lora_device_data_pk PRIMARY KEY(device_eui, time_stamp)
+);
+CREATE TABLE lora_gateways (
+    gateway_eui BIGINT     NOT NULL,
+    latitude    NUMERIC(12,8) NULL,
+    longitude   NUMERIC(12,8) NULL,
+    altitude    NUMERIC(8,3)  NULL,
+    ip          VARCHAR(64)   NOT NULL,
+    strict_ip   BOOL          NOT NULL,
+    CONSTRAINT lora_gateway_pk PRIMARY KEY (gateway_eui)
+);
+INSERT INTO lora_applications (eui, tag) VALUES (1, 'app');
+INSERT INTO lora_devices (eui, dev_addr, app_key, apps_key, nwks_key, application_eui, state, tag)
+    VALUES (2, '01020304', '00000000000000000000000000000000', '00000000000000000000000000000000',
+        '00000000000000000000000000000000', 1, 2, 'device');
+INSERT INTO lora_gateways (gateway_eui, latitude, longitude, altitude, ip, strict_ip)
+    VALUES (3, 1, 2, 3, '127.0.0.1', false);
+`
+
+func TestSchemaMigration(t *testing.T) {
+	assert := require.New(t)
+
+	dbFile := filepath.Join(t.TempDir(), "lospan.db")
+	db, err := sql.Open(driverName, dbFile)
+	assert.NoError(err)
+	_, err = db.Exec(originalSchema)
+	assert.NoError(err)
+	assert.NoError(db.Close())
+
+	s, err := CreateStorage(dbFile)
+	assert.NoError(err, "Existing tables should be migrated")
+
+	app, err := s.GetApplicationByEUI(protocol.EUIFromInt64(1))
+	assert.NoError(err)
+	assert.Equal("", app.Decoder)
+
+	device, err := s.GetDeviceByEUI(protocol.EUIFromInt64(2))
+	assert.NoError(err)
+	assert.Equal(uint8(model.DefaultPingPeriodicity), device.PingPeriodicity)
+	assert.Len(device.Channels, 0)
+	device.Class = model.ClassC
+	assert.NoError(s.UpdateDevice(device))
+	classC, err := s.GetDevicesByClass(model.ClassC)
+	assert.NoError(err)
+	assert.Len(classC, 1)
+
+	gw, err := s.GetGateway(protocol.EUIFromInt64(3))
+	assert.NoError(err)
+	assert.Equal(band.EU868Band, gw.Plan)
+
+	assert.NoError(s.CreateUpstreamMessage(device.DeviceEUI, model.UpstreamMessage{
+		DeviceEUI: device.DeviceEUI, Timestamp: 1, Data: []byte{1}, FPort: 2}))
+	messages, err := s.ListUpstreamMessages(device.DeviceEUI, 10)
+	assert.NoError(err)
+	assert.Len(messages, 1)
+	assert.Equal(uint8(2), messages[0].FPort)
+	s.Close()
+
+	// Migrations are only applied once
+	s, err = CreateStorage(dbFile)
+	assert.NoError(err)
+	s.Close()
+}
+
+func TestAddedColumnsInSchema(t *testing.T) {
+	assert := require.New(t)
+
+	s := NewMemoryStorage()
+	defer s.Close()
+	for _, c := range addedColumns {
+		columns, err := tableColumns(s.db, c.table)
+		assert.NoError(err)
+		assert.True(columns[c.column], "Column %s.%s should be in the schema", c.table, c.column)
+	}
+}
@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lab5e/lospan/pkg/model"
+)
+
+type profileStatements struct {
+	putStatement    *sql.Stmt // Prepared statement for create
+	getStatement    *sql.Stmt // Prepared statement for get
+	listStatement   *sql.Stmt // Prepared statement for list
+	updateStatement *sql.Stmt // Prepared statement for update
+	deleteStatement *sql.Stmt // Prepared statement for delete
+	inUseStatement  *sql.Stmt // Prepared statement for counting the devices using a profile
+}
+
+func (p *profileStatements) Close() {
+	p.putStatement.Close()
+	p.getStatement.Close()
+	p.listStatement.Close()
+	p.updateStatement.Close()
+	p.deleteStatement.Close()
+	p.inUseStatement.Close()
+}
+
+func (p *profileStatements) prepare(db *sql.DB) error {
+	var err error
+	sqlInsert := `
+		INSERT INTO lora_device_profiles (
+			name,
+			mac_version,
+			reg_params_revision,
+			device_class,
+			supports_join,
+			rx1_delay,
+			rx1_dr_offset,
+			rx2_data_rate,
+			rx2_frequency,
+			max_eirp,
+			adr_enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	if p.putStatement, err = db.Prepare(sqlInsert); err != nil {
+		return fmt.Errorf("unable to prepare insert statement: %v", err)
+	}
+
+	sqlSelect := `
+		SELECT
+			id,
+			name,
+			mac_version,
+			reg_params_revision,
+			device_class,
+			supports_join,
+			rx1_delay,
+			rx1_dr_offset,
+			rx2_data_rate,
+			rx2_frequency,
+			max_eirp,
+			adr_enabled
+		FROM
+			lora_device_profiles`
+	if p.getStatement, err = db.Prepare(sqlSelect + " WHERE id = $1"); err != nil {
+		return fmt.Errorf("unable to prepare select statement: %v", err)
+	}
+	if p.listStatement, err = db.Prepare(sqlSelect + " ORDER BY id"); err != nil {
+		return fmt.Errorf("unable to prepare list statement: %v", err)
+	}
+
+	sqlUpdate := `
+		UPDATE
+			lora_device_profiles
+		SET
+			name = $2,
+			mac_version = $3,
+			reg_params_revision = $4,
+			device_class = $5,
+			supports_join = $6,
+			rx1_delay = $7,
+			rx1_dr_offset = $8,
+			rx2_data_rate = $9,
+			rx2_frequency = $10,
+			max_eirp = $11,
+			adr_enabled = $12
+		WHERE id = $1`
+	if p.updateStatement, err = db.Prepare(sqlUpdate); err != nil {
+		return fmt.Errorf("unable to prepare update statement: %v", err)
+	}
+
+	if p.deleteStatement, err = db.Prepare(`DELETE FROM lora_device_profiles WHERE id = $1`); err != nil {
+		return fmt.Errorf("unable to prepare delete statement: %v", err)
+	}
+	if p.inUseStatement, err = db.Prepare(`SELECT COUNT(*) FROM lora_devices WHERE profile_id = $1`); err != nil {
+		return fmt.Errorf("unable to prepare in use statement: %v", err)
+	}
+	return nil
+}
+
+func readProfile(rows *sql.Rows) (model.DeviceProfile, error) {
+	ret := model.DeviceProfile{}
+	err := rows.Scan(
+		&ret.ID,
+		&ret.Name,
+		&ret.MACVersion,
+		&ret.RegParamsRevision,
+		&ret.Class,
+		&ret.SupportsJoin,
+		&ret.RX1Delay,
+		&ret.RX1DROffset,
+		&ret.RX2DataRate,
+		&ret.RX2Frequency,
+		&ret.MaxEIRP,
+		&ret.ADREnabled)
+	return ret, err
+}
+
+// CreateDeviceProfile creates a new device profile. The returned profile has
+// the assigned identifier.
+func (s *Storage) CreateDeviceProfile(profile model.DeviceProfile) (model.DeviceProfile, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	res, err := s.profileStmt.putStatement.Exec(
+		profile.Name,
+		uint8(profile.MACVersion),
+		profile.RegParamsRevision,
+		uint8(profile.Class),
+		profile.SupportsJoin,
+		profile.RX1Delay,
+		profile.RX1DROffset,
+		profile.RX2DataRate,
+		profile.RX2Frequency,
+		profile.MaxEIRP,
+		profile.ADREnabled)
+	if err != nil {
+		return profile, err
+	}
+	if profile.ID, err = res.LastInsertId(); err != nil {
+		return profile, err
+	}
+	return profile, nil
+}
+
+// GetDeviceProfile retrieves a device profile
+func (s *Storage) GetDeviceProfile(id int64) (model.DeviceProfile, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rows, err := s.profileStmt.getStatement.Query(id)
+	if err != nil {
+		return model.DeviceProfile{}, fmt.Errorf("unable to query for device profile: %v", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return model.DeviceProfile{}, ErrNotFound
+	}
+	return readProfile(rows)
+}
+
+// ListDeviceProfiles lists all of the device profiles
+func (s *Storage) ListDeviceProfiles() ([]model.DeviceProfile, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rows, err := s.profileStmt.listStatement.Query()
+	if err != nil {
+		return nil, fmt.Errorf("unable to query for device profiles: %v", err)
+	}
+	defer rows.Close()
+	var ret []model.DeviceProfile
+	for rows.Next() {
+		p, err := readProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read device profile fields: %v", err)
+		}
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+// UpdateDeviceProfile updates a device profile. The devices using the
+// profile aren't updated.
+func (s *Storage) UpdateDeviceProfile(profile model.DeviceProfile) error {
+	return s.doSQLExec(s.profileStmt.updateStatement, func(st *sql.Stmt) (sql.Result, error) {
+		return st.Exec(
+			profile.ID,
+			profile.Name,
+			uint8(profile.MACVersion),
+			profile.RegParamsRevision,
+			uint8(profile.Class),
+			profile.SupportsJoin,
+			profile.RX1Delay,
+			profile.RX1DROffset,
+			profile.RX2DataRate,
+			profile.RX2Frequency,
+			profile.MaxEIRP,
+			profile.ADREnabled)
+	})
+}
+
+// DeleteDeviceProfile removes a device profile. Profiles that are used by
+// devices can't be removed.
+func (s *Storage) DeleteDeviceProfile(id int64) error {
+	s.mutex.Lock()
+	var count int
+	err := s.profileStmt.inUseStatement.QueryRow(id).Scan(&count)
+	s.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("unable to check if profile is in use: %v", err)
+	}
+	if count > 0 {
+		return ErrDeleteConstraint
+	}
+	return s.doSQLExec(s.profileStmt.deleteStatement, func(st *sql.Stmt) (sql.Result, error) {
+		return st.Exec(id)
+	})
+}
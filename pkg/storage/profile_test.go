@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceProfileStorage(t *testing.T) {
+	assert := require.New(t)
+
+	s := NewMemoryStorage()
+	defer s.Close()
+
+	profiles, err := s.ListDeviceProfiles()
+	assert.NoError(err)
+	assert.Len(profiles, 0)
+
+	profile := model.NewDeviceProfile()
+	profile.Name = "Class C sensor"
+	profile.MACVersion = model.LoRaWAN11
+	profile.RegParamsRevision = "RP002-1.0.3"
+	profile.Class = model.ClassC
+	profile.RX1Delay = 2
+	profile.RX2DataRate = 3
+	profile.RX2Frequency = 869.525
+	profile.MaxEIRP = 16
+	profile.ADREnabled = false
+	profile, err = s.CreateDeviceProfile(profile)
+	assert.NoError(err)
+	assert.NotZero(profile.ID)
+
+	stored, err := s.GetDeviceProfile(profile.ID)
+	assert.NoError(err)
+	assert.Equal(profile, stored)
+
+	_, err = s.GetDeviceProfile(profile.ID + 1)
+	assert.Equal(ErrNotFound, err)
+
+	profile.Name = "Updated"
+	profile.SupportsJoin = false
+	assert.NoError(s.UpdateDeviceProfile(profile))
+	profiles, err = s.ListDeviceProfiles()
+	assert.NoError(err)
+	assert.Equal([]model.DeviceProfile{profile}, profiles)
+	assert.Equal(ErrNotFound, s.UpdateDeviceProfile(model.DeviceProfile{ID: profile.ID + 1}))
+
+	// Profiles in use can't be removed
+	app := model.NewApplication()
+	app.AppEUI = makeRandomEUI()
+	assert.NoError(s.CreateApplication(app))
+	device := model.NewDevice()
+	device.DeviceEUI = makeRandomEUI()
+	device.AppEUI = app.AppEUI
+	device.DevAddr = protocol.DevAddrFromUint32(1)
+	profile.Apply(&device)
+	assert.NoError(s.CreateDevice(device, app.AppEUI))
+	stored, err = s.GetDeviceProfile(device.ProfileID)
+	assert.NoError(err)
+	assert.Equal(profile, stored)
+	assert.Equal(ErrDeleteConstraint, s.DeleteDeviceProfile(profile.ID))
+
+	assert.NoError(s.DeleteDevice(device.DeviceEUI))
+	assert.NoError(s.DeleteDeviceProfile(profile.ID))
+	assert.Equal(ErrNotFound, s.DeleteDeviceProfile(profile.ID))
+}
@@ -1,10 +1,28 @@
 CREATE TABLE IF NOT EXISTS lora_applications (
     eui         BIGINT       NOT NULL,
     tag         VARCHAR(128) NOT NULL,
+    decoder     TEXT         NOT NULL DEFAULT '',
+    encoder     TEXT         NOT NULL DEFAULT '',
     CONSTRAINT lora_application_pk PRIMARY KEY (eui)
 );
 
 
+-- Device profiles. The RX2 frequency and max EIRP are band defaults when set to 0.
+CREATE TABLE IF NOT EXISTS lora_device_profiles (
+    id                  INTEGER      PRIMARY KEY,
+    name                VARCHAR(128) NOT NULL,
+    mac_version         SMALLINT     NOT NULL DEFAULT 0,
+    reg_params_revision VARCHAR(32)  NOT NULL DEFAULT '',
+    device_class        SMALLINT     NOT NULL DEFAULT 0,
+    supports_join       BOOLEAN      NOT NULL DEFAULT true,
+    rx1_delay           SMALLINT     NOT NULL DEFAULT 1,
+    rx1_dr_offset       SMALLINT     NOT NULL DEFAULT 0,
+    rx2_data_rate       SMALLINT     NOT NULL DEFAULT 0,
+    rx2_frequency       NUMERIC(6,3) NOT NULL DEFAULT 0,
+    max_eirp            SMALLINT     NOT NULL DEFAULT 0,
+    adr_enabled         BOOLEAN      NOT NULL DEFAULT true
+);
+
 CREATE TABLE IF NOT EXISTS lora_devices (
     eui             BIGINT       NOT NULL,
     dev_addr        CHAR(8)      NOT NULL,
@@ -18,12 +36,36 @@ CREATE TABLE IF NOT EXISTS lora_devices (
     relaxed_counter BOOLEAN      NOT NULL DEFAULT false,
     key_warning     BOOLEAN      NOT NULL DEFAULT false,
     tag             VARCHAR(128) NOT NULL,
+    data_rate       SMALLINT     NOT NULL DEFAULT 0,
+    tx_power        SMALLINT     NOT NULL DEFAULT 0,
+    ch_mask         INTEGER      NOT NULL DEFAULT 0,
+    rx1_dr_offset   SMALLINT     NOT NULL DEFAULT 0,
+    rx2_data_rate   SMALLINT     NOT NULL DEFAULT 0,
+    rx2_frequency   NUMERIC(6,3) NOT NULL DEFAULT 0,
+    rx1_delay       SMALLINT     NOT NULL DEFAULT 0,
+    max_duty_cycle  SMALLINT     NOT NULL DEFAULT 0,
+    device_class    SMALLINT     NOT NULL DEFAULT 0,
+    ping_period     SMALLINT     NOT NULL DEFAULT 7,
+    ping_data_rate  SMALLINT     NOT NULL DEFAULT 0,
+    ping_frequency  NUMERIC(6,3) NOT NULL DEFAULT 0,
+    mac_version     SMALLINT     NOT NULL DEFAULT 0,
+    nwk_key         CHAR(32)     NOT NULL,
+    snwksint_key    CHAR(32)     NOT NULL,
+    nwksenc_key     CHAR(32)     NOT NULL,
+    join_nonce      INTEGER      NOT NULL DEFAULT 0,
+    profile_id      INTEGER      NOT NULL DEFAULT 0,
+    channels        TEXT         NOT NULL DEFAULT '',
+    beacon_frequency NUMERIC(6,3) NOT NULL DEFAULT 0,
+    nfcnt_dn        INTEGER      NOT NULL DEFAULT 0,
+    afcnt_dn        INTEGER      NOT NULL DEFAULT 0,
     CONSTRAINT lora_device_pk PRIMARY KEY (eui)
 );
 
 CREATE INDEX IF NOT EXISTS lora_device_application_eui ON lora_devices(application_eui);
 CREATE INDEX IF NOT EXISTS lora_device_dev_addr ON lora_devices(dev_addr);
 CREATE INDEX IF NOT EXISTS lora_device_state ON lora_devices(state);
+CREATE INDEX IF NOT EXISTS lora_device_class ON lora_devices(device_class);
+CREATE INDEX IF NOT EXISTS lora_device_profile_id ON lora_devices(profile_id);
 
 
 CREATE TABLE IF NOT EXISTS lora_device_nonces (
@@ -44,6 +86,7 @@ CREATE TABLE IF NOT EXISTS lora_upstream_messages (
     frequency       NUMERIC(6,3)  NOT NULL,
     data_rate       VARCHAR(20)   NOT NULL,
     dev_addr        CHAR(8)       NOT NULL,
+    port            SMALLINT      NOT NULL DEFAULT 0,
 
     CONSTRAINT lora_device_data_pk PRIMARY KEY(device_eui, time_stamp)
 );
@@ -51,6 +94,23 @@ CREATE TABLE IF NOT EXISTS lora_upstream_messages (
 CREATE INDEX IF NOT EXISTS lora_device_data_device_eui ON lora_upstream_messages(device_eui);
 
 
+CREATE TABLE IF NOT EXISTS lora_upstream_receptions (
+    device_eui      BIGINT        NOT NULL,
+    time_stamp      BIGINT        NOT NULL,
+    gateway_eui     BIGINT        NOT NULL,
+    rssi            INTEGER       NOT NULL,
+    snr             NUMERIC(6,3)  NOT NULL,
+    channel         SMALLINT      NOT NULL,
+    rf_chain        SMALLINT      NOT NULL,
+    gw_timestamp    BIGINT        NOT NULL,
+    fine_timestamp  BIGINT        NOT NULL DEFAULT 0,
+
+    CONSTRAINT lora_upstream_reception_pk PRIMARY KEY (device_eui, time_stamp, gateway_eui),
+    CONSTRAINT lora_upstream_reception_fk FOREIGN KEY (device_eui, time_stamp)
+        REFERENCES lora_upstream_messages (device_eui, time_stamp) ON DELETE CASCADE
+);
+
+
 CREATE TABLE IF NOT EXISTS lora_sequences (
     identifier VARCHAR(128) NOT NULL, 
     counter    BIGINT       NOT NULL, 
@@ -68,6 +128,20 @@ CREATE TABLE IF NOT EXISTS lora_gateways (
     altitude    NUMERIC(8,3)  NULL,
     ip          VARCHAR(64)   NOT NULL,
     strict_ip   BOOL          NOT NULL,
+    last_seen   BIGINT        NOT NULL DEFAULT 0,
+    stat_time   VARCHAR(64)   NOT NULL DEFAULT '',
+    stat_lat    NUMERIC(12,8) NOT NULL DEFAULT 0,
+    stat_lon    NUMERIC(12,8) NOT NULL DEFAULT 0,
+    stat_alt    NUMERIC(8,3)  NOT NULL DEFAULT 0,
+    rxnb        INTEGER       NOT NULL DEFAULT 0,
+    rxok        INTEGER       NOT NULL DEFAULT 0,
+    rxfw        INTEGER       NOT NULL DEFAULT 0,
+    ackr        NUMERIC(6,2)  NOT NULL DEFAULT 0,
+    dwnb        INTEGER       NOT NULL DEFAULT 0,
+    txnb        INTEGER       NOT NULL DEFAULT 0,
+    stat_updated BIGINT       NOT NULL DEFAULT 0,
+    frequency_plan VARCHAR(16) NOT NULL DEFAULT 'EU868',
+    channel_config TEXT        NOT NULL DEFAULT '',
 
     CONSTRAINT lora_gateway_pk PRIMARY KEY (gateway_eui)
 );
@@ -93,4 +167,40 @@ CREATE TABLE IF NOT EXISTS lora_downstream_messages (
     CONSTRAINT lora_downstream_message_pk PRIMARY KEY (device_eui, created_time)
 );
 
-CREATE INDEX IF NOT EXISTS lora_downstream_messages_created ON lora_downstream_messages(created_time);  
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS lora_downstream_messages_created ON lora_downstream_messages(created_time);  
+
+CREATE TABLE IF NOT EXISTS lora_device_stats (
+    device_eui   BIGINT  NOT NULL REFERENCES lora_devices(eui) ON DELETE CASCADE,
+    received     INTEGER NOT NULL DEFAULT 0,
+    lost         INTEGER NOT NULL DEFAULT 0,
+    replayed     INTEGER NOT NULL DEFAULT 0,
+    mic_failed   INTEGER NOT NULL DEFAULT 0,
+    rejected     INTEGER NOT NULL DEFAULT 0,
+    last_updated BIGINT  NOT NULL DEFAULT 0,
+
+    CONSTRAINT lora_device_stats_pk PRIMARY KEY (device_eui)
+);
+
+CREATE TABLE IF NOT EXISTS lora_webhooks (
+    id              INTEGER       PRIMARY KEY,
+    application_eui BIGINT        NOT NULL REFERENCES lora_applications(eui) ON DELETE CASCADE,
+    url             VARCHAR(1024) NOT NULL,
+    headers         TEXT          NOT NULL DEFAULT '{}',
+    secret          VARCHAR(256)  NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS lora_webhook_application_eui ON lora_webhooks(application_eui);
+
+-- The delivery log is pruned when new entries are added. Only the most recent entries are kept
+-- for each webhook.
+CREATE TABLE IF NOT EXISTS lora_webhook_deliveries (
+    webhook_id  INTEGER       NOT NULL REFERENCES lora_webhooks(id) ON DELETE CASCADE,
+    event_type  VARCHAR(16)   NOT NULL,
+    time_stamp  BIGINT        NOT NULL,
+    attempt     INTEGER       NOT NULL,
+    status_code INTEGER       NOT NULL DEFAULT 0,
+    error       VARCHAR(512)  NOT NULL DEFAULT '',
+    delivered   BOOLEAN       NOT NULL DEFAULT false
+);
+
+CREATE INDEX IF NOT EXISTS lora_webhook_deliveries_webhook_id ON lora_webhook_deliveries(webhook_id);
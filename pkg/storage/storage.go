@@ -11,13 +11,16 @@ import (
 
 // Storage holds all of the storage objects
 type Storage struct {
-	db       *sql.DB
-	mutex    *sync.Mutex
-	appStmt  applicationStatements
-	devStmt  deviceStatements
-	dataStmt dataStatements
-	gwStmt   gatewayStatements
-	keyStmt  keyStatements
+	db          *sql.DB
+	mutex       *sync.Mutex
+	appStmt     applicationStatements
+	devStmt     deviceStatements
+	dataStmt    dataStatements
+	gwStmt      gatewayStatements
+	keyStmt     keyStatements
+	statsStmt   deviceStatsStatements
+	webhookStmt webhookStatements
+	profileStmt profileStatements
 }
 
 // Close closes all of the storage instances.
@@ -27,6 +30,9 @@ func (s *Storage) Close() {
 	s.dataStmt.Close()
 	s.gwStmt.Close()
 	s.keyStmt.Close()
+	s.statsStmt.Close()
+	s.webhookStmt.Close()
+	s.profileStmt.Close()
 }
 
 // CreateStorage creates a new storage
@@ -65,16 +71,40 @@ func newStorage(driver, connectionString string) (*Storage, error) {
 	if err := ret.keyStmt.prepare(db); err != nil {
 		return nil, err
 	}
+	if err := ret.statsStmt.prepare(db); err != nil {
+		return nil, err
+	}
+	if err := ret.webhookStmt.prepare(db); err != nil {
+		return nil, err
+	}
+	if err := ret.profileStmt.prepare(db); err != nil {
+		return nil, err
+	}
 	return ret, nil
 }
 
 // KeyGeneratorFunc is a function that generates identifiers
 type KeyGeneratorFunc func(string) uint64
 
-// dreateSchema crreates the schema for the database
+// createSchema creates the schema for the database. The tables are created
+// first, then the columns missing from existing tables are added before the
+// indexes are created.
 func createSchema(db *sql.DB) error {
 	commands := schemaCommandList()
+	var indexes []string
 	for _, v := range commands {
+		if !strings.HasPrefix(v, "CREATE TABLE") {
+			indexes = append(indexes, v)
+			continue
+		}
+		if _, err := db.Exec(v); err != nil {
+			return err
+		}
+	}
+	if err := migrateSchema(db); err != nil {
+		return err
+	}
+	for _, v := range indexes {
 		if _, err := db.Exec(v); err != nil {
 			return err
 		}
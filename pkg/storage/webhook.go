@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+)
+
+// MaxWebhookDeliveries is the number of delivery log entries kept for each
+// webhook
+const MaxWebhookDeliveries = 100
+
+type webhookStatements struct {
+	putStatement                 *sql.Stmt // Prepared statement for create
+	listStatement                *sql.Stmt // Prepared statement for list by application
+	deleteStatement              *sql.Stmt // Prepared statement for delete
+	addDeliveryStatement         *sql.Stmt // Prepared statement for adding to the delivery log
+	pruneDeliveryStatement       *sql.Stmt // Prepared statement for pruning the delivery log
+	listDeliveriesStatement      *sql.Stmt // Prepared statement for listing the delivery log
+	deleteAppStatement           *sql.Stmt // Prepared statement for removing the webhooks for an application
+	deleteAppDeliveriesStatement *sql.Stmt // Prepared statement for removing the delivery log for an application
+}
+
+func (w *webhookStatements) Close() {
+	w.putStatement.Close()
+	w.listStatement.Close()
+	w.deleteStatement.Close()
+	w.addDeliveryStatement.Close()
+	w.pruneDeliveryStatement.Close()
+	w.listDeliveriesStatement.Close()
+	w.deleteAppStatement.Close()
+	w.deleteAppDeliveriesStatement.Close()
+}
+
+func (w *webhookStatements) prepare(db *sql.DB) error {
+	var err error
+	sqlInsert := `
+		INSERT INTO lora_webhooks (
+			application_eui,
+			url,
+			headers,
+			secret)
+		VALUES ($1, $2, $3, $4)`
+	if w.putStatement, err = db.Prepare(sqlInsert); err != nil {
+		return fmt.Errorf("unable to prepare insert statement: %v", err)
+	}
+
+	sqlList := `
+		SELECT
+			id,
+			application_eui,
+			url,
+			headers,
+			secret
+		FROM
+			lora_webhooks
+		WHERE
+			application_eui = $1
+		ORDER BY id`
+	if w.listStatement, err = db.Prepare(sqlList); err != nil {
+		return fmt.Errorf("unable to prepare list statement: %v", err)
+	}
+
+	sqlDelete := `
+		DELETE FROM
+			lora_webhooks
+		WHERE
+			application_eui = $1 AND id = $2`
+	if w.deleteStatement, err = db.Prepare(sqlDelete); err != nil {
+		return fmt.Errorf("unable to prepare delete statement: %v", err)
+	}
+
+	sqlAddDelivery := `
+		INSERT INTO lora_webhook_deliveries (
+			webhook_id,
+			event_type,
+			time_stamp,
+			attempt,
+			status_code,
+			error,
+			delivered)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	if w.addDeliveryStatement, err = db.Prepare(sqlAddDelivery); err != nil {
+		return fmt.Errorf("unable to prepare delivery insert statement: %v", err)
+	}
+
+	sqlPrune := `
+		DELETE FROM
+			lora_webhook_deliveries
+		WHERE
+			webhook_id = $1 AND rowid NOT IN (
+				SELECT rowid FROM lora_webhook_deliveries WHERE webhook_id = $1 ORDER BY rowid DESC LIMIT $2)`
+	if w.pruneDeliveryStatement, err = db.Prepare(sqlPrune); err != nil {
+		return fmt.Errorf("unable to prepare delivery prune statement: %v", err)
+	}
+
+	sqlListDeliveries := `
+		SELECT
+			webhook_id,
+			event_type,
+			time_stamp,
+			attempt,
+			status_code,
+			error,
+			delivered
+		FROM
+			lora_webhook_deliveries
+		WHERE
+			webhook_id = $1
+		ORDER BY rowid DESC
+		LIMIT $2`
+	if w.listDeliveriesStatement, err = db.Prepare(sqlListDeliveries); err != nil {
+		return fmt.Errorf("unable to prepare delivery list statement: %v", err)
+	}
+
+	sqlDeleteApp := `
+		DELETE FROM
+			lora_webhooks
+		WHERE
+			application_eui = $1`
+	if w.deleteAppStatement, err = db.Prepare(sqlDeleteApp); err != nil {
+		return fmt.Errorf("unable to prepare application delete statement: %v", err)
+	}
+
+	sqlDeleteAppDeliveries := `
+		DELETE FROM
+			lora_webhook_deliveries
+		WHERE
+			webhook_id IN (SELECT id FROM lora_webhooks WHERE application_eui = $1)`
+	if w.deleteAppDeliveriesStatement, err = db.Prepare(sqlDeleteAppDeliveries); err != nil {
+		return fmt.Errorf("unable to prepare application delivery delete statement: %v", err)
+	}
+	return nil
+}
+
+// CreateWebhook creates a new webhook for an application. The returned
+// webhook has the assigned identifier.
+func (s *Storage) CreateWebhook(webhook model.Webhook) (model.Webhook, error) {
+	headers, err := json.Marshal(webhook.Headers)
+	if err != nil {
+		return webhook, fmt.Errorf("unable to marshal headers: %v", err)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	res, err := s.webhookStmt.putStatement.Exec(webhook.AppEUI.ToInt64(), webhook.URL, string(headers), webhook.Secret)
+	if err != nil {
+		return webhook, err
+	}
+	if webhook.ID, err = res.LastInsertId(); err != nil {
+		return webhook, err
+	}
+	return webhook, nil
+}
+
+// ListWebhooks lists the webhooks for an application
+func (s *Storage) ListWebhooks(appEUI protocol.EUI) ([]model.Webhook, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rows, err := s.webhookStmt.listStatement.Query(appEUI.ToInt64())
+	if err != nil {
+		return nil, fmt.Errorf("unable to query for webhooks: %v", err)
+	}
+	defer rows.Close()
+	var ret []model.Webhook
+	for rows.Next() {
+		var eui int64
+		var headers string
+		w := model.Webhook{}
+		if err := rows.Scan(&w.ID, &eui, &w.URL, &headers, &w.Secret); err != nil {
+			return nil, fmt.Errorf("unable to read webhook fields: %v", err)
+		}
+		w.AppEUI = protocol.EUIFromInt64(eui)
+		if err := json.Unmarshal([]byte(headers), &w.Headers); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal headers for webhook %d: %v", w.ID, err)
+		}
+		ret = append(ret, w)
+	}
+	return ret, nil
+}
+
+// DeleteWebhook removes a webhook from an application. The delivery log for
+// the webhook is removed as well.
+func (s *Storage) DeleteWebhook(appEUI protocol.EUI, id int64) error {
+	if err := s.doSQLExec(s.webhookStmt.deleteStatement, func(st *sql.Stmt) (sql.Result, error) {
+		return st.Exec(appEUI.ToInt64(), id)
+	}); err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, err := s.webhookStmt.pruneDeliveryStatement.Exec(id, 0)
+	return err
+}
+
+// AddWebhookDelivery adds an entry to the delivery log for a webhook. Only
+// the last MaxWebhookDeliveries entries are kept.
+func (s *Storage) AddWebhookDelivery(delivery model.WebhookDelivery) error {
+	if err := s.doSQLExec(s.webhookStmt.addDeliveryStatement, func(st *sql.Stmt) (sql.Result, error) {
+		return st.Exec(
+			delivery.WebhookID,
+			delivery.EventType,
+			delivery.Time,
+			delivery.Attempt,
+			delivery.StatusCode,
+			delivery.Error,
+			delivery.Delivered)
+	}); err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, err := s.webhookStmt.pruneDeliveryStatement.Exec(delivery.WebhookID, MaxWebhookDeliveries)
+	return err
+}
+
+// ListWebhookDeliveries returns the delivery log for a webhook. The newest
+// entries are returned first.
+func (s *Storage) ListWebhookDeliveries(webhookID int64, limit int) ([]model.WebhookDelivery, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rows, err := s.webhookStmt.listDeliveriesStatement.Query(webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query for webhook deliveries: %v", err)
+	}
+	defer rows.Close()
+	var ret []model.WebhookDelivery
+	for rows.Next() {
+		d := model.WebhookDelivery{}
+		if err := rows.Scan(&d.WebhookID, &d.EventType, &d.Time, &d.Attempt, &d.StatusCode, &d.Error, &d.Delivered); err != nil {
+			return nil, fmt.Errorf("unable to read webhook delivery fields: %v", err)
+		}
+		ret = append(ret, d)
+	}
+	return ret, nil
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/lab5e/lospan/pkg/model"
+	"github.com/lab5e/lospan/pkg/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookStorage(t *testing.T) {
+	assert := require.New(t)
+
+	s := NewMemoryStorage()
+	defer s.Close()
+
+	app := model.NewApplication()
+	app.AppEUI = protocol.EUIFromInt64(0x0102030405060708)
+	assert.NoError(s.CreateApplication(app))
+
+	hooks, err := s.ListWebhooks(app.AppEUI)
+	assert.NoError(err)
+	assert.Len(hooks, 0)
+
+	first, err := s.CreateWebhook(model.Webhook{AppEUI: app.AppEUI, URL: "http://example.com/1",
+		Headers: map[string]string{"Authorization": "Bearer 1234"}, Secret: "secret"})
+	assert.NoError(err)
+	assert.NotZero(first.ID)
+	second, err := s.CreateWebhook(model.Webhook{AppEUI: app.AppEUI, URL: "http://example.com/2"})
+	assert.NoError(err)
+	assert.NotEqual(first.ID, second.ID)
+
+	hooks, err = s.ListWebhooks(app.AppEUI)
+	assert.NoError(err)
+	assert.Len(hooks, 2)
+	assert.Equal(first, hooks[0])
+	assert.Equal("http://example.com/2", hooks[1].URL)
+
+	for i := 1; i <= MaxWebhookDeliveries+5; i++ {
+		assert.NoError(s.AddWebhookDelivery(model.WebhookDelivery{WebhookID: first.ID, EventType: "up", Time: int64(i), Attempt: 1, StatusCode: 200, Delivered: true}))
+	}
+	assert.NoError(s.AddWebhookDelivery(model.WebhookDelivery{WebhookID: second.ID, EventType: "join", Time: 1, Attempt: 2, Error: "timeout"}))
+
+	deliveries, err := s.ListWebhookDeliveries(first.ID, 1000)
+	assert.NoError(err)
+	assert.Len(deliveries, MaxWebhookDeliveries, "Delivery log should be pruned")
+	assert.Equal(int64(MaxWebhookDeliveries+5), deliveries[0].Time, "Newest entry should be first")
+
+	deliveries, err = s.ListWebhookDeliveries(second.ID, 10)
+	assert.NoError(err)
+	assert.Equal([]model.WebhookDelivery{{WebhookID: second.ID, EventType: "join", Time: 1, Attempt: 2, Error: "timeout"}}, deliveries)
+
+	assert.Equal(ErrNotFound, s.DeleteWebhook(protocol.EUIFromInt64(1), first.ID), "Webhook belongs to another application")
+	assert.NoError(s.DeleteWebhook(app.AppEUI, first.ID))
+	assert.Equal(ErrNotFound, s.DeleteWebhook(app.AppEUI, first.ID))
+
+	hooks, err = s.ListWebhooks(app.AppEUI)
+	assert.NoError(err)
+	assert.Len(hooks, 1)
+	deliveries, err = s.ListWebhookDeliveries(first.ID, 10)
+	assert.NoError(err)
+	assert.Len(deliveries, 0)
+
+	// Removing the application removes the webhooks and the delivery log
+	assert.NoError(s.AddWebhookDelivery(model.WebhookDelivery{WebhookID: second.ID, EventType: "up", Time: 2, Attempt: 1}))
+	assert.NoError(s.DeleteApplication(app.AppEUI))
+	assert.NoError(s.CreateApplication(app))
+	hooks, err = s.ListWebhooks(app.AppEUI)
+	assert.NoError(err)
+	assert.Len(hooks, 0)
+	deliveries, err = s.ListWebhookDeliveries(second.ID, 10)
+	assert.NoError(err)
+	assert.Len(deliveries, 0)
+}
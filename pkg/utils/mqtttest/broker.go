@@ -0,0 +1,210 @@
+// Package mqtttest contains a minimal in-process MQTT broker for tests. It
+// supports QoS 0 and 1 and doesn't retain messages or keep sessions.
+package mqtttest
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+)
+
+// Message is a message published to the broker
+type Message struct {
+	Topic   string
+	Payload []byte
+}
+
+// Broker is an in-process MQTT broker. All of the published messages are
+// recorded.
+type Broker struct {
+	listener net.Listener
+	mutex    *sync.Mutex
+	clients  map[*client]bool
+	messages []Message
+}
+
+type client struct {
+	conn    net.Conn
+	mutex   *sync.Mutex // Serializes the writes to the connection
+	filters []string
+}
+
+func (c *client) write(p packets.ControlPacket) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	p.Write(c.conn)
+}
+
+// NewBroker creates a new broker listening on the address. Use
+// "127.0.0.1:0" to listen on a random port.
+func NewBroker(address string) (*Broker, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	b := &Broker{listener: listener, mutex: &sync.Mutex{}, clients: make(map[*client]bool)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(&client{conn: conn, mutex: &sync.Mutex{}})
+		}
+	}()
+	return b, nil
+}
+
+// URL returns the URL for the broker, ie tcp://127.0.0.1:1883
+func (b *Broker) URL() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+// Close stops the broker and closes all of the client connections
+func (b *Broker) Close() {
+	b.listener.Close()
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for c := range b.clients {
+		c.conn.Close()
+	}
+}
+
+func (b *Broker) serve(c *client) {
+	b.mutex.Lock()
+	b.clients[c] = true
+	b.mutex.Unlock()
+	defer func() {
+		b.mutex.Lock()
+		delete(b.clients, c)
+		b.mutex.Unlock()
+		c.conn.Close()
+	}()
+	for {
+		p, err := packets.ReadPacket(c.conn)
+		if err != nil {
+			return
+		}
+		switch p := p.(type) {
+		case *packets.ConnectPacket:
+			c.write(packets.NewControlPacket(packets.Connack))
+		case *packets.SubscribePacket:
+			b.mutex.Lock()
+			c.filters = append(c.filters, p.Topics...)
+			b.mutex.Unlock()
+			ack := packets.NewControlPacket(packets.Suback).(*packets.SubackPacket)
+			ack.MessageID = p.MessageID
+			ack.ReturnCodes = make([]byte, len(p.Topics))
+			c.write(ack)
+		case *packets.UnsubscribePacket:
+			ack := packets.NewControlPacket(packets.Unsuback).(*packets.UnsubackPacket)
+			ack.MessageID = p.MessageID
+			c.write(ack)
+		case *packets.PublishPacket:
+			if p.Qos > 0 {
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = p.MessageID
+				c.write(ack)
+			}
+			b.publish(p.TopicName, p.Payload)
+		case *packets.PingreqPacket:
+			c.write(packets.NewControlPacket(packets.Pingresp))
+		case *packets.DisconnectPacket:
+			return
+		}
+	}
+}
+
+// publish forwards the message to the subscribers. Messages are delivered
+// with QoS 0.
+func (b *Broker) publish(topic string, payload []byte) {
+	b.mutex.Lock()
+	b.messages = append(b.messages, Message{Topic: topic, Payload: payload})
+	var receivers []*client
+	for c := range b.clients {
+		for _, f := range c.filters {
+			if topicMatches(f, topic) {
+				receivers = append(receivers, c)
+				break
+			}
+		}
+	}
+	b.mutex.Unlock()
+	for _, c := range receivers {
+		p := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+		p.TopicName = topic
+		p.Payload = payload
+		c.write(p)
+	}
+}
+
+// WaitForSubscriber waits until a client has subscribed to the topic. It
+// returns false if there's no subscriber within the timeout.
+func (b *Broker) WaitForSubscriber(topic string, timeout time.Duration) bool {
+	end := time.Now().Add(timeout)
+	for time.Now().Before(end) {
+		if b.hasSubscriber(topic) {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func (b *Broker) hasSubscriber(topic string) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for c := range b.clients {
+		for _, f := range c.filters {
+			if topicMatches(f, topic) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Messages returns the published messages that match the filter
+func (b *Broker) Messages(filter string) []Message {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	var ret []Message
+	for _, m := range b.messages {
+		if topicMatches(filter, m.Topic) {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
+
+// WaitForMessages waits until at least count messages that match the filter
+// have been published. The matching messages are returned.
+func (b *Broker) WaitForMessages(filter string, count int, timeout time.Duration) []Message {
+	end := time.Now().Add(timeout)
+	for {
+		ret := b.Messages(filter)
+		if len(ret) >= count || time.Now().After(end) {
+			return ret
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// topicMatches returns true if the topic matches the filter. The filter
+// might contain the + and # wildcards.
+func topicMatches(filter, topic string) bool {
+	f := strings.Split(filter, "/")
+	n := strings.Split(topic, "/")
+	for i, v := range f {
+		if v == "#" {
+			return true
+		}
+		if i >= len(n) || (v != "+" && v != n[i]) {
+			return false
+		}
+	}
+	return len(f) == len(n)
+}
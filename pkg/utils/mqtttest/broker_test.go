@@ -0,0 +1,56 @@
+package mqtttest
+
+import (
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+func TestTopicMatches(t *testing.T) {
+	if !topicMatches("gateway/+/event/+", "gateway/0102/event/up") ||
+		!topicMatches("gateway/#", "gateway/0102/event/up") ||
+		topicMatches("gateway/+/event/+", "gateway/0102/state/conn") ||
+		topicMatches("gateway/+", "gateway/0102/event/up") {
+		t.Fatal("Incorrect topic matching")
+	}
+}
+
+func TestBroker(t *testing.T) {
+	broker, err := NewBroker("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+
+	client := mqtt.NewClient(mqtt.NewClientOptions().AddBroker(broker.URL()).SetClientID("test"))
+	if token := client.Connect(); !token.WaitTimeout(time.Second) || token.Error() != nil {
+		t.Fatalf("Unable to connect: %v", token.Error())
+	}
+	defer client.Disconnect(0)
+
+	received := make(chan string, 1)
+	token := client.Subscribe("a/+", 1, func(_ mqtt.Client, msg mqtt.Message) {
+		received <- msg.Topic()
+	})
+	if !token.WaitTimeout(time.Second) || token.Error() != nil {
+		t.Fatalf("Unable to subscribe: %v", token.Error())
+	}
+	if !broker.WaitForSubscriber("a/b", time.Second) {
+		t.Fatal("Subscription not registered")
+	}
+	if token := client.Publish("a/b", 1, false, []byte("hello")); !token.WaitTimeout(time.Second) || token.Error() != nil {
+		t.Fatalf("Unable to publish: %v", token.Error())
+	}
+	select {
+	case topic := <-received:
+		if topic != "a/b" {
+			t.Fatalf("Incorrect topic: %s", topic)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Message not received")
+	}
+	if msgs := broker.WaitForMessages("a/#", 1, time.Second); len(msgs) != 1 || string(msgs[0].Payload) != "hello" {
+		t.Fatalf("Incorrect recorded messages: %+v", msgs)
+	}
+}